@@ -0,0 +1,67 @@
+// Command agent exposes the container runtime of a machine to a go_spin server, which drives it
+// with a RemoteRuntime for the containers declaring this machine as host (runtime.hosts).
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/bassista/go_spin/internal/agent"
+	"github.com/bassista/go_spin/internal/build"
+	"github.com/bassista/go_spin/internal/logger"
+	"github.com/bassista/go_spin/internal/runtime"
+	"github.com/gin-gonic/gin"
+)
+
+// Environment variables configuring the agent and their defaults.
+const (
+	envPort        = "AGENT_PORT"
+	envToken       = "AGENT_TOKEN"
+	envRuntimeType = "AGENT_RUNTIME_TYPE"
+
+	defaultPort        = 8086
+	defaultRuntimeType = runtime.RuntimeTypeDocker
+
+	// readHeaderTimeout bounds how long a client may take to send the request headers.
+	readHeaderTimeout = 10 * time.Second
+)
+
+func main() {
+	token := os.Getenv(envToken)
+	if token == "" {
+		logger.WithComponent("agent").Fatalf("%s must be set", envToken)
+	}
+	port := defaultPort
+	if value := os.Getenv(envPort); value != "" {
+		p, err := strconv.Atoi(value)
+		if err != nil || p < 1 || p > 65535 {
+			logger.WithComponent("agent").Fatalf("%s must be a valid TCP port (1-65535), got %q", envPort, value)
+		}
+		port = p
+	}
+	runtimeType := defaultRuntimeType
+	if value := os.Getenv(envRuntimeType); value != "" {
+		runtimeType = value
+	}
+
+	rt, err := runtime.NewRuntimeFromConfig(runtimeType, nil)
+	if err != nil {
+		logger.WithComponent("agent").Fatalf("cannot init runtime (%s=%q): %v", envRuntimeType, runtimeType, err)
+	}
+
+	gin.SetMode(gin.ReleaseMode)
+	info := build.Current()
+	logger.WithComponent("agent").Infof("go_spin agent version=%s commit=%s runtime=%s port=%d", info.Version, info.Commit, runtimeType, port)
+	srv := &http.Server{
+		Addr:              fmt.Sprintf(":%d", port),
+		Handler:           agent.NewHandler(rt, token),
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.WithComponent("agent").Fatal(err)
+	}
+}
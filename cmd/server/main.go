@@ -10,10 +10,12 @@ import (
 	"net/http"
 	"syscall"
 
+	"github.com/bassista/go_spin/internal/agent"
 	"github.com/bassista/go_spin/internal/api/controller"
 	"github.com/bassista/go_spin/internal/api/middleware"
 	route "github.com/bassista/go_spin/internal/api/route"
 	appctx "github.com/bassista/go_spin/internal/app"
+	"github.com/bassista/go_spin/internal/build"
 	"github.com/bassista/go_spin/internal/cache"
 	"github.com/bassista/go_spin/internal/config"
 	"github.com/bassista/go_spin/internal/logger"
@@ -38,32 +40,96 @@ func main() {
 		logLevel = logrus.InfoLevel
 	}
 	logger.Logger.SetLevel(logLevel)
+	logFile, err := logger.Configure(logger.Options{
+		Format:     cfg.Misc.LogFormat,
+		File:       cfg.Misc.LogFile,
+		MaxSizeMB:  cfg.Misc.LogMaxSizeMB,
+		MaxAge:     cfg.Misc.LogMaxAge,
+		MaxBackups: cfg.Misc.LogMaxBackups,
+	})
+	if err != nil {
+		logger.WithComponent("main").Fatalf("log output error: %v", err)
+	}
+	if logFile != nil {
+		defer logFile.Close()
+	}
 	logger.WithComponent("main").Debugf("log level set to: %s", logLevel.String())
-	logger.WithComponent("main").Infof("Waiting server will run on port: %d", cfg.Server.WaitingServerPort)
-	logger.WithComponent("main").Infof("App will run on port: %d", cfg.Server.Port)
+	info := build.Current()
+	logger.WithComponent("main").Infof("go_spin version=%s commit=%s built=%s runtime=%s scheduling=%v port=%d waiting_port=%d",
+		info.Version, info.Commit, info.BuildDate, cfg.Misc.RuntimeType, cfg.Data.SchedulingEnabled, cfg.Server.Port, cfg.Server.WaitingServerPort)
+	logger.WithComponent("main").Infof("Waiting server will run on: %s", cfg.Server.ListenAddr(cfg.Server.WaitingServerPort))
+	logger.WithComponent("main").Infof("App will run on: %s", cfg.Server.ListenAddr(cfg.Server.Port))
+	if cfg.Auth.Enabled() {
+		logger.WithComponent("main").Infof("management API authentication enabled: %d api keys, %d users", len(cfg.Auth.KeyRoles()), len(cfg.Auth.Users))
+	} else {
+		logger.WithComponent("main").Warnf("management API has no authentication: set auth.api_keys or auth.users before exposing it")
+	}
+	if cfg.Auth.WaitingUserHeader != "" {
+		logger.WithComponent("main").Infof("waiting server requires the forward-auth header %s", cfg.Auth.WaitingUserHeader)
+	}
 
-	repo, err := repository.NewJSONRepository(cfg.Data.FilePath)
+	repo, err := repository.NewJSONRepository(cfg.Data.FilePath,
+		repository.WithSaveMode(cfg.Data.SaveMode),
+		repository.WithCompression(cfg.Data.Compress),
+		repository.WithBackups(cfg.Data.BackupRetention),
+	)
 	if err != nil {
 		logger.WithComponent("main").Fatalf("cannot init repository: %v", err)
 	}
 
-	jsonDoc, err := repo.Load(context.Background())
+	jsonDoc, err := repository.LoadWithRetry(context.Background(), repo, cfg.Data.LoadRetries, cfg.Data.LoadRetryDelay)
 	if err != nil {
 		logger.WithComponent("main").Fatalf("cannot load data file: %v", err)
 	}
 
 	cacheStore := cache.NewStore(*jsonDoc)
+	// The runtime lookups resolve containers in the main document, and in the tenants once loaded
+	containerIndex := cache.NewContainerIndex(cacheStore)
 	rt, err := runtime.NewRuntimeFromConfig(cfg.Misc.RuntimeType, jsonDoc)
 	if err != nil {
-		logger.WithComponent("main").Fatalf("cannot init runtime: %v", err)
+		logger.WithComponent("main").Fatalf("cannot init runtime (misc.runtime_type=%q): %v", cfg.Misc.RuntimeType, err)
+	}
+	if cfg.Runtime.AllowRecreate {
+		if dr, ok := rt.(*runtime.DockerRuntime); ok {
+			logger.WithComponent("main").Warnf("runtime.allow_recreate enabled: containers with a commandOverride are recreated on start")
+			dr.EnableRecreate(commandOverrideLookup(containerIndex))
+		} else {
+			logger.WithComponent("main").Warnf("runtime.allow_recreate is only supported by the docker runtime, ignoring it")
+		}
 	}
 
+	// Entries may select a runtime other than misc.runtime_type with runtimeType
+	entryRuntimes := map[string]runtime.ContainerRuntime{
+		runtime.RuntimeTypeWakeOnLAN: runtime.NewWakeOnLANRuntime(wakeOnLANLookup(containerIndex), runtime.SSHConfig{
+			KeyFile:        cfg.Runtime.WolSSHKeyFile,
+			KnownHostsFile: cfg.Runtime.WolSSHKnownHosts,
+		}),
+	}
+	if cfg.Misc.RuntimeType != runtime.RuntimeTypeSystemd {
+		entryRuntimes[runtime.RuntimeTypeSystemd] = runtime.NewSystemdRuntime()
+	}
+	rt = runtime.NewDispatcher(rt, entryRuntimes, runtimeTypeLookup(containerIndex, cfg.Misc.RuntimeType))
+	if len(cfg.Runtime.Hosts) > 0 {
+		remotes := make(map[string]runtime.ContainerRuntime, len(cfg.Runtime.Hosts))
+		for _, host := range cfg.Runtime.Hosts {
+			remotes[host.Name] = agent.NewRemoteRuntime(host.URL, host.Token, cfg.Runtime.RemoteTimeout)
+			logger.WithComponent("main").Infof("remote host %s served by the agent at %s", host.Name, host.URL)
+		}
+		rt = runtime.NewDispatcher(rt, remotes, hostLookup(containerIndex))
+	}
+	// Pre-stop hooks run below safe mode, which skips them with the stop
+	rt = runtime.NewPreStopRuntime(rt, preStopLookup(containerIndex))
+
 	app, err := appctx.New(cfg, repo, cacheStore, rt)
 	if err != nil {
 		logger.WithComponent("main").Fatalf("cannot init app: %v", err)
 	}
 	defer app.Shutdown()
 
+	if err := app.LoadTenants(context.Background()); err != nil {
+		logger.WithComponent("main").Fatalf("cannot load tenants: %v", err)
+	}
+	containerIndex.SetStores(app.ContainerStores()...)
 	app.StartWatchers()
 
 	gin.SetMode(cfg.Misc.GinMode)
@@ -73,7 +139,7 @@ func main() {
 	// Setup and start the secondary waiting server
 	waitingSrv := createWaitingServer(app, logger.Logger)
 	go func() {
-		if err := waitingSrv.ListenAndServe(fmt.Sprintf(":%d", cfg.Server.WaitingServerPort)); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		if err := waitingSrv.ListenAndServe(cfg.Server.ListenAddr(cfg.Server.WaitingServerPort)); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			logger.WithComponent("main").Errorf("Waiting server error: %v", err)
 		}
 	}()
@@ -82,24 +148,95 @@ func main() {
 	r := route.SetupRoutes(app, logger.Logger)
 	mainSrv := createGraceHttpServer(app.BaseCtx, "main-server", app.Config.Server, r)
 
-	if err := mainSrv.ListenAndServe(fmt.Sprintf(":%d", cfg.Server.Port)); err != nil && !errors.Is(err, http.ErrServerClosed) {
+	if err := mainSrv.ListenAndServe(cfg.Server.ListenAddr(cfg.Server.Port)); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		logger.WithComponent("main").Fatal(err)
 	}
 }
 
+// containerLookup returns a lookup of field of the container by name in index; unknown containers
+// get the zero value.
+func containerLookup[T any](index *cache.ContainerIndex, field func(repository.Container) T) func(string) T {
+	return func(name string) T {
+		c, _ := index.Container(name)
+		return field(c)
+	}
+}
+
+// commandOverrideLookup reads the CommandOverride of a container at start time.
+func commandOverrideLookup(index *cache.ContainerIndex) runtime.CommandLookup {
+	return containerLookup(index, func(c repository.Container) []string { return c.CommandOverride })
+}
+
+// runtimeTypeLookup reads the RuntimeType of a container at call time; the default runtime type
+// routes to the main runtime.
+func runtimeTypeLookup(index *cache.ContainerIndex, defaultType string) runtime.RouteLookup {
+	return containerLookup(index, func(c repository.Container) string {
+		if c.RuntimeType == defaultType {
+			return ""
+		}
+		return c.RuntimeType
+	})
+}
+
+// wakeOnLANLookup reads the WakeOnLAN configuration of a container at call time.
+func wakeOnLANLookup(index *cache.ContainerIndex) runtime.WakeOnLANLookup {
+	return containerLookup(index, func(c repository.Container) *repository.WakeOnLAN { return c.WakeOnLAN })
+}
+
+// hostLookup reads the Host of a container at call time.
+func hostLookup(index *cache.ContainerIndex) runtime.RouteLookup {
+	return containerLookup(index, func(c repository.Container) string { return c.Host })
+}
+
+// preStopLookup reads the PreStop hook of a container at call time.
+func preStopLookup(index *cache.ContainerIndex) runtime.PreStopLookup {
+	return containerLookup(index, func(c repository.Container) *repository.PreStopHook { return c.PreStop })
+}
+
 // createWaitingServer creates a secondary HTTP server dedicated to serving only the waiting page.
 // It exposes a single route GET /:name that triggers RuntimeController.WaitingPage.
 func createWaitingServer(app *appctx.App, logger *logrus.Logger) *httpgrace.Server {
 	r := gin.New()
+	r.Use(middleware.RequestID())
 	r.Use(middleware.HoneybadgerMiddleware(logger))
 	r.Use(gin.Recovery())
+	// Without trusted proxies (empty or nil list) X-Forwarded-For is ignored and the client IP is the peer address
+	if err := r.SetTrustedProxies(app.Config.Misc.WaitingTrustedProxies); err != nil {
+		logger.WithField("component", "main").Fatalf("invalid misc.waiting_trusted_proxies: %v", err)
+	}
+	// Behind a forward-auth proxy only authenticated users may wake containers
+	r.Use(middleware.ForwardAuth(app.Config.Auth.WaitingUserHeader))
+
+	// Per-client limit of the requests that may start containers; the readiness and status polls
+	// of the waiting page are not limited
+	limiter := middleware.NewRateLimiter(app.Config.Misc.WaitingRateLimitPerMinute, app.Config.Misc.WaitingRateLimitBurst)
+	if limiter != nil {
+		app.Janitor.Register("waiting_rate_limit", limiter)
+	}
+	rateLimit := middleware.RateLimit(limiter, app.Config.Misc.WaitingCaptchaURL, app.Config.Misc.WaitingTrustedProxies)
 
 	// Create RuntimeController for the waiting page
 	rc := controller.NewRuntimeController(app)
 	cc := controller.NewContainerController(app.BaseCtx, app.Cache, app.Runtime)
+	cc.SetReadinessCache(app.Config.Misc.ReadyCacheTTL, app.Config.Misc.ReadyCacheNegativeTTL)
+	cc.SetExternalReadyChecker(app.Config.Misc.ExternalReadyURL, app.Config.Misc.ExternalReadyTimeout)
+	cc.SetDefaultURLScheme(app.Config.Misc.DefaultURLScheme)
+	cc.SetOperations(app.Ops)
+	app.Janitor.Register("waiting_readiness_cache", cc.ReadinessCache())
 
 	r.GET("/container/:name/ready", cc.Ready)
-	r.GET("/:name", rc.WaitingPage)
+	r.GET("/container/:name/status", cc.Status)
+	if app.Config.Misc.WaitingProxy {
+		// Reverse-proxy mode: requests reach the container directly once it answers
+		r.Any("/:name", rateLimit, rc.Proxy)
+		r.Any("/:name/*path", rateLimit, rc.Proxy)
+	} else {
+		r.GET("/:name", rateLimit, rc.WaitingPage)
+		// Readiness of a container or, for groups, of all its members
+		r.GET("/:name/ready", cc.Ready)
+		// Starting, failed or ready, so the waiting page can stop on failures
+		r.GET("/:name/status", cc.Status)
+	}
 
 	return createGraceHttpServer(app.BaseCtx, "waiting-server", app.Config.Server, r)
 }
@@ -92,6 +92,8 @@ func (m *mockContainerStore) ClearDirty() {}
 
 func (m *mockContainerStore) SetLastUpdate(ts int64) {}
 
+func (m *mockContainerStore) Version() uint64 { return 0 }
+
 // Verify mockContainerStore implements cache.AppStore
 var _ cache.AppStore = (*mockContainerStore)(nil)
 
@@ -350,3 +352,7 @@ func TestWaitingServerRouting_HandlerIsolation(t *testing.T) {
 		}
 	})
 }
+
+func (m *mockContainerRuntime) Ping(_ context.Context) (runtime.PingResult, error) {
+	return runtime.PingResult{}, nil
+}
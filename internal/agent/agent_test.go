@@ -0,0 +1,93 @@
+package agent
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bassista/go_spin/internal/runtime"
+	"github.com/gin-gonic/gin"
+)
+
+// failingRuntime reports an unreachable daemon from IsRunning and an unknown container from Start.
+type failingRuntime struct {
+	*runtime.MemoryRuntime
+}
+
+func (failingRuntime) IsRunning(context.Context, string) (bool, error) {
+	return false, runtime.ErrUnavailable
+}
+
+func (failingRuntime) Start(_ context.Context, name string) error {
+	return &runtime.NotFoundError{Kind: "container", Name: name}
+}
+
+func TestRemoteRuntime_RoundTrip(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	local := runtime.NewMemoryRuntime()
+	srv := httptest.NewServer(NewHandler(local, "secret"))
+	defer srv.Close()
+	remote := NewRemoteRuntime(srv.URL+"/", "secret", time.Second)
+	ctx := context.Background()
+
+	if err := remote.Start(ctx, "web app"); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if running, _ := local.IsRunning(ctx, "web app"); !running {
+		t.Error("expected the agent to start the container")
+	}
+	if running, err := remote.IsRunning(ctx, "web app"); err != nil || !running {
+		t.Errorf("expected running, got %v err=%v", running, err)
+	}
+	if names, err := remote.ListContainers(ctx); err != nil || len(names) != 1 || names[0] != "web app" {
+		t.Errorf("unexpected containers %v err=%v", names, err)
+	}
+	if _, err := remote.Stats(ctx, "web app"); err != nil {
+		t.Errorf("stats: %v", err)
+	}
+	if res, err := remote.Ping(ctx); err != nil || res.Version != "memory" {
+		t.Errorf("unexpected ping %+v err=%v", res, err)
+	}
+	if err := remote.Stop(ctx, "web app"); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	if running, _ := local.IsRunning(ctx, "web app"); running {
+		t.Error("expected the agent to stop the container")
+	}
+}
+
+func TestRemoteRuntime_Errors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	srv := httptest.NewServer(NewHandler(failingRuntime{runtime.NewMemoryRuntime()}, "secret"))
+	defer srv.Close()
+	ctx := context.Background()
+
+	if _, err := NewRemoteRuntime(srv.URL, "wrong", time.Second).Ping(ctx); err == nil || runtime.IsUnavailable(err) {
+		t.Errorf("expected an authentication error, got %v", err)
+	}
+	remote := NewRemoteRuntime(srv.URL, "secret", time.Second)
+	if _, err := remote.IsRunning(ctx, "web"); !runtime.IsUnavailable(err) {
+		t.Errorf("expected the unreachable daemon to be reported unavailable, got %v", err)
+	}
+	if err := remote.Start(ctx, "web"); !runtime.IsNotFound(err) || runtime.IsUnavailable(err) || !strings.Contains(err.Error(), "container web not found") {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+
+	srv.Close()
+	if _, err := remote.Ping(ctx); !runtime.IsUnavailable(err) {
+		t.Errorf("expected an unreachable agent to be reported unavailable, got %v", err)
+	}
+}
+
+func TestNewHandler_RejectsMissingToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewHandler(runtime.NewMemoryRuntime(), "secret")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, pathContainers+"/web/start", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", w.Code)
+	}
+}
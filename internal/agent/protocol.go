@@ -0,0 +1,33 @@
+// Package agent lets one go_spin instance drive the containers of other machines: the agent
+// (cmd/agent) exposes the local runtime of a machine over HTTP and RemoteRuntime is its client.
+package agent
+
+// Paths of the agent API; :name is a container name.
+const (
+	pathPrefix     = "/agent/v1"
+	pathPing       = pathPrefix + "/ping"
+	pathContainers = pathPrefix + "/containers"
+)
+
+// stateResponse answers GET /containers/:name.
+type stateResponse struct {
+	Running bool `json:"running"`
+}
+
+// statsResponse answers GET /containers/:name/stats.
+type statsResponse struct {
+	CPUPercent float64 `json:"cpuPercent"`
+	MemoryMB   float64 `json:"memoryMb"`
+}
+
+// pingResponse answers GET /ping.
+type pingResponse struct {
+	Version    string `json:"version,omitempty"`
+	APIVersion string `json:"apiVersion,omitempty"`
+}
+
+// errorResponse carries the error of a failed call: 404 for an unknown container, 503 when the
+// runtime of the agent is unreachable.
+type errorResponse struct {
+	Error string `json:"error"`
+}
@@ -0,0 +1,120 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bassista/go_spin/internal/runtime"
+)
+
+// maxErrorBody bounds the error body read from a failed agent call.
+const maxErrorBody = 4096
+
+// RemoteRuntime is a runtime.ContainerRuntime driving the containers of another machine through
+// its agent (cmd/agent). Transport failures and a 503 from the agent wrap runtime.ErrUnavailable,
+// a 404 wraps runtime.ErrContainerNotFound.
+type RemoteRuntime struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+// NewRemoteRuntime creates a RemoteRuntime for the agent listening at baseURL; timeout bounds
+// every call.
+func NewRemoteRuntime(baseURL, token string, timeout time.Duration) *RemoteRuntime {
+	return &RemoteRuntime{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+func containerPath(name string, action string) string {
+	p := pathContainers + "/" + url.PathEscape(name)
+	if action != "" {
+		p += "/" + action
+	}
+	return p
+}
+
+// call sends a request to the agent and decodes the JSON answer into out, when not nil.
+func (r *RemoteRuntime) call(ctx context.Context, method, path string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, method, r.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+r.token)
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %w", runtime.ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		var body errorResponse
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		if json.Unmarshal(raw, &body) != nil || body.Error == "" {
+			body.Error = resp.Status
+		}
+		switch resp.StatusCode {
+		case http.StatusServiceUnavailable:
+			return fmt.Errorf("%w: agent %s: %s", runtime.ErrUnavailable, r.baseURL, body.Error)
+		case http.StatusNotFound:
+			return fmt.Errorf("%w: agent %s: %s", runtime.ErrContainerNotFound, r.baseURL, body.Error)
+		}
+		return fmt.Errorf("agent %s: %s", r.baseURL, body.Error)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("agent %s: invalid response: %w", r.baseURL, err)
+	}
+	return nil
+}
+
+func (r *RemoteRuntime) IsRunning(ctx context.Context, containerName string) (bool, error) {
+	var state stateResponse
+	if err := r.call(ctx, http.MethodGet, containerPath(containerName, ""), &state); err != nil {
+		return false, err
+	}
+	return state.Running, nil
+}
+
+func (r *RemoteRuntime) Start(ctx context.Context, containerName string) error {
+	return r.call(ctx, http.MethodPost, containerPath(containerName, "start"), nil)
+}
+
+func (r *RemoteRuntime) Stop(ctx context.Context, containerName string) error {
+	return r.call(ctx, http.MethodPost, containerPath(containerName, "stop"), nil)
+}
+
+func (r *RemoteRuntime) ListContainers(ctx context.Context) ([]string, error) {
+	var names []string
+	if err := r.call(ctx, http.MethodGet, pathContainers, &names); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+func (r *RemoteRuntime) Stats(ctx context.Context, containerName string) (runtime.ContainerStats, error) {
+	var stats statsResponse
+	if err := r.call(ctx, http.MethodGet, containerPath(containerName, "stats"), &stats); err != nil {
+		return runtime.ContainerStats{}, err
+	}
+	return runtime.ContainerStats{CPUPercent: stats.CPUPercent, MemoryMB: stats.MemoryMB}, nil
+}
+
+func (r *RemoteRuntime) Ping(ctx context.Context) (runtime.PingResult, error) {
+	var res pingResponse
+	if err := r.call(ctx, http.MethodGet, pathPing, &res); err != nil {
+		return runtime.PingResult{}, err
+	}
+	return runtime.PingResult{Version: res.Version, APIVersion: res.APIVersion}, nil
+}
@@ -0,0 +1,110 @@
+package agent
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/bassista/go_spin/internal/logger"
+	"github.com/bassista/go_spin/internal/runtime"
+	"github.com/gin-gonic/gin"
+)
+
+// NewHandler exposes rt under /agent/v1 for the RemoteRuntime of a go_spin server. Every call
+// must carry "Authorization: Bearer <token>".
+func NewHandler(rt runtime.ContainerRuntime, token string) http.Handler {
+	r := gin.New()
+	r.Use(gin.Recovery())
+	s := &server{rt: rt}
+
+	group := r.Group(pathPrefix, requireToken(token))
+	group.GET("/ping", s.ping)
+	group.GET("/containers", s.list)
+	group.GET("/containers/:name", s.state)
+	group.POST("/containers/:name/start", s.start)
+	group.POST("/containers/:name/stop", s.stop)
+	group.GET("/containers/:name/stats", s.stats)
+	return r
+}
+
+// requireToken rejects the requests without the bearer token, comparing it in constant time.
+func requireToken(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		got, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse{Error: "invalid or missing token"})
+			return
+		}
+		c.Next()
+	}
+}
+
+type server struct {
+	rt runtime.ContainerRuntime
+}
+
+// fail answers err with 503 when the runtime is unreachable, 404 for an unknown container and
+// 500 otherwise.
+func fail(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case runtime.IsUnavailable(err):
+		status = http.StatusServiceUnavailable
+	case runtime.IsNotFound(err):
+		status = http.StatusNotFound
+	}
+	logger.WithComponent("agent").Debugf("%s %s failed: %v", c.Request.Method, c.Request.URL.Path, err)
+	c.JSON(status, errorResponse{Error: err.Error()})
+}
+
+func (s *server) ping(c *gin.Context) {
+	res, err := s.rt.Ping(c.Request.Context())
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, pingResponse{Version: res.Version, APIVersion: res.APIVersion})
+}
+
+func (s *server) list(c *gin.Context) {
+	names, err := s.rt.ListContainers(c.Request.Context())
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, names)
+}
+
+func (s *server) state(c *gin.Context) {
+	running, err := s.rt.IsRunning(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, stateResponse{Running: running})
+}
+
+func (s *server) start(c *gin.Context) {
+	if err := s.rt.Start(c.Request.Context(), c.Param("name")); err != nil {
+		fail(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (s *server) stop(c *gin.Context) {
+	if err := s.rt.Stop(c.Request.Context(), c.Param("name")); err != nil {
+		fail(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (s *server) stats(c *gin.Context) {
+	stats, err := s.rt.Stats(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, statsResponse{CPUPercent: stats.CPUPercent, MemoryMB: stats.MemoryMB})
+}
@@ -0,0 +1,198 @@
+// Package apierror writes the error envelope shared by every failed API request, with a stable
+// machine-readable code derived from the HTTP status or from the error that caused the failure.
+package apierror
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/bassista/go_spin/internal/cache"
+	"github.com/bassista/go_spin/internal/logger"
+	"github.com/bassista/go_spin/internal/ops"
+	"github.com/bassista/go_spin/internal/repository"
+	"github.com/bassista/go_spin/internal/runtime"
+	"github.com/bassista/go_spin/internal/waiting"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// Generic codes, one per error status.
+const (
+	CodeInvalidRequest     = "invalid_request"
+	CodeUnauthorized       = "unauthorized"
+	CodeForbidden          = "forbidden"
+	CodeNotFound           = "not_found"
+	CodeMethodNotAllowed   = "method_not_allowed"
+	CodeUnsupportedVersion = "unsupported_version"
+	CodeConflict           = "conflict"
+	CodeTooLarge           = "payload_too_large"
+	CodeUnprocessable      = "unprocessable"
+	CodeRateLimited        = "rate_limited"
+	CodeInternal           = "internal_error"
+	CodeNotImplemented     = "not_implemented"
+	CodeBadGateway         = "bad_gateway"
+	CodeUnavailable        = "unavailable"
+	CodeTimeout            = "timeout"
+)
+
+// Specific codes, for the failures clients are expected to tell apart.
+const (
+	CodeValidationFailed   = "validation_failed"
+	CodeContainerNotFound  = "container_not_found"
+	CodeGroupNotFound      = "group_not_found"
+	CodeScheduleNotFound   = "schedule_not_found"
+	CodeOperationNotFound  = "operation_not_found"
+	CodeBackupNotFound     = "backup_not_found"
+	CodeContainerExists    = "container_exists"
+	CodeDanglingReference  = "dangling_reference"
+	CodeInvalidData        = "invalid_data"
+	CodeInvalidTemplate    = "invalid_template"
+	CodeReadOnly           = "read_only"
+	CodeInactive           = "inactive"
+	CodeRuntimeUnavailable = "runtime_unavailable"
+	CodeSafeMode           = "safe_mode"
+)
+
+// Response is the body of every failed API request.
+type Response struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// Details carries data specific to the failure, e.g. the missing members of a group.
+	Details any `json:"details,omitempty"`
+	// RequestID is the ID assigned to the request by middleware.RequestID, when installed.
+	RequestID string `json:"requestId,omitempty"`
+	// Error repeats Message for the clients of the former {"error": "..."} body.
+	Error string `json:"error"`
+}
+
+// FieldError is a failed validation rule, listed in the details of validation_failed.
+type FieldError struct {
+	Field string `json:"field"`
+	Rule  string `json:"rule"`
+}
+
+// sentinelCodes maps the known sentinel errors to their code, checked in order with errors.Is.
+var sentinelCodes = []struct {
+	err  error
+	code string
+}{
+	{cache.ErrContainerNotFound, CodeContainerNotFound},
+	{cache.ErrGroupNotFound, CodeGroupNotFound},
+	{cache.ErrScheduleNotFound, CodeScheduleNotFound},
+	{cache.ErrContainerExists, CodeContainerExists},
+	{cache.ErrDanglingReference, CodeDanglingReference},
+	{ops.ErrNotFound, CodeOperationNotFound},
+	{repository.ErrBackupNotFound, CodeBackupNotFound},
+	{repository.ErrInvalidData, CodeInvalidData},
+	{waiting.ErrInvalidTemplate, CodeInvalidTemplate},
+	{runtime.ErrContainerNotFound, CodeContainerNotFound},
+	{runtime.ErrUnavailable, CodeRuntimeUnavailable},
+	{runtime.ErrTimeout, CodeTimeout},
+	{runtime.ErrSafeMode, CodeSafeMode},
+	{context.DeadlineExceeded, CodeTimeout},
+}
+
+// CodeForStatus returns the generic code of an error status.
+func CodeForStatus(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return CodeInvalidRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusMethodNotAllowed:
+		return CodeMethodNotAllowed
+	case http.StatusNotAcceptable:
+		return CodeUnsupportedVersion
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusRequestEntityTooLarge:
+		return CodeTooLarge
+	case http.StatusUnprocessableEntity:
+		return CodeUnprocessable
+	case http.StatusTooManyRequests:
+		return CodeRateLimited
+	case http.StatusNotImplemented:
+		return CodeNotImplemented
+	case http.StatusBadGateway:
+		return CodeBadGateway
+	case http.StatusServiceUnavailable:
+		return CodeUnavailable
+	case http.StatusGatewayTimeout:
+		return CodeTimeout
+	}
+	return CodeInternal
+}
+
+// CodeOf returns the code of err: the code of the first known sentinel it wraps,
+// validation_failed for validator errors, otherwise the generic code of status.
+func CodeOf(err error, status int) string {
+	for _, s := range sentinelCodes {
+		if errors.Is(err, s.err) {
+			return s.code
+		}
+	}
+	var invalid validator.ValidationErrors
+	if errors.As(err, &invalid) {
+		return CodeValidationFailed
+	}
+	return CodeForStatus(status)
+}
+
+// New builds the body of a failed request; details may be nil.
+func New(c *gin.Context, code, message string, details any) Response {
+	return Response{
+		Code:      code,
+		Message:   message,
+		Details:   details,
+		RequestID: logger.RequestID(c.Request.Context()),
+		Error:     message,
+	}
+}
+
+// Respond writes message with the generic code of status.
+func Respond(c *gin.Context, status int, message string) {
+	c.JSON(status, New(c, CodeForStatus(status), message, nil))
+}
+
+// RespondCode writes message with code.
+func RespondCode(c *gin.Context, status int, code, message string) {
+	c.JSON(status, New(c, code, message, nil))
+}
+
+// RespondDetails writes message with code and details.
+func RespondDetails(c *gin.Context, status int, code, message string, details any) {
+	c.JSON(status, New(c, code, message, details))
+}
+
+// RespondError writes err as message with its code (see CodeOf). The failed rules of validator
+// errors are listed in the details.
+func RespondError(c *gin.Context, status int, err error) {
+	var details any
+	if fields := fieldErrors(err); fields != nil {
+		details = fields
+	}
+	c.JSON(status, New(c, CodeOf(err, status), err.Error(), details))
+}
+
+// Abort writes message with code and stops the handler chain, for middlewares.
+func Abort(c *gin.Context, status int, code, message string) {
+	c.AbortWithStatusJSON(status, New(c, code, message, nil))
+}
+
+// fieldErrors lists the failed rules of a validator error, nil for other errors.
+func fieldErrors(err error) []FieldError {
+	var invalid validator.ValidationErrors
+	if !errors.As(err, &invalid) {
+		return nil
+	}
+	fields := make([]FieldError, 0, len(invalid))
+	for _, fe := range invalid {
+		fields = append(fields, FieldError{Field: fe.Namespace(), Rule: fe.Tag()})
+	}
+	return fields
+}
@@ -0,0 +1,115 @@
+package apierror
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bassista/go_spin/internal/cache"
+	"github.com/bassista/go_spin/internal/logger"
+	"github.com/bassista/go_spin/internal/runtime"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+func serve(t *testing.T, handler gin.HandlerFunc, requestID string) (int, Response) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/", handler)
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if requestID != "" {
+		req = req.WithContext(logger.WithRequestID(req.Context(), requestID))
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var body Response
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	return w.Code, body
+}
+
+func TestRespond_UsesStatusCodeAndKeepsError(t *testing.T) {
+	status, body := serve(t, func(c *gin.Context) {
+		Respond(c, http.StatusNotFound, "resource not found")
+	}, "req-1")
+
+	if status != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", status)
+	}
+	want := Response{Code: CodeNotFound, Message: "resource not found", RequestID: "req-1", Error: "resource not found"}
+	if body != want {
+		t.Errorf("expected %+v, got %+v", want, body)
+	}
+}
+
+func TestRespondDetails(t *testing.T) {
+	_, body := serve(t, func(c *gin.Context) {
+		RespondDetails(c, http.StatusBadRequest, CodeDanglingReference, "missing members", gin.H{"missing": []string{"ghost"}})
+	}, "")
+
+	details, ok := body.Details.(map[string]any)
+	if body.Code != CodeDanglingReference || !ok || fmt.Sprint(details["missing"]) != "[ghost]" {
+		t.Errorf("unexpected body %+v", body)
+	}
+	if body.RequestID != "" {
+		t.Errorf("expected no request ID, got %q", body.RequestID)
+	}
+}
+
+func TestRespondError_ValidationDetails(t *testing.T) {
+	type payload struct {
+		Name string `validate:"required"`
+	}
+	err := validator.New().Struct(payload{})
+
+	_, body := serve(t, func(c *gin.Context) {
+		RespondError(c, http.StatusBadRequest, err)
+	}, "")
+
+	if body.Code != CodeValidationFailed {
+		t.Errorf("expected code %s, got %s", CodeValidationFailed, body.Code)
+	}
+	fields, ok := body.Details.([]any)
+	if !ok || len(fields) != 1 {
+		t.Fatalf("expected one failed field, got %+v", body.Details)
+	}
+	if field := fields[0].(map[string]any); field["field"] != "payload.Name" || field["rule"] != "required" {
+		t.Errorf("unexpected field error %+v", field)
+	}
+}
+
+func TestRespondError_PlainErrorHasNoDetails(t *testing.T) {
+	_, body := serve(t, func(c *gin.Context) {
+		RespondError(c, http.StatusNotFound, &runtime.NotFoundError{Kind: "container", Name: "web"})
+	}, "")
+
+	if body.Code != CodeContainerNotFound || body.Message != "container web not found" || body.Details != nil {
+		t.Errorf("unexpected body %+v", body)
+	}
+}
+
+func TestCodeOf(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+		want   string
+	}{
+		{fmt.Errorf("get c1: %w", cache.ErrContainerNotFound), http.StatusNotFound, CodeContainerNotFound},
+		{cache.ErrDanglingReference, http.StatusUnprocessableEntity, CodeDanglingReference},
+		{fmt.Errorf("ping: %w", runtime.ErrDaemonUnavailable), http.StatusServiceUnavailable, CodeRuntimeUnavailable},
+		{&runtime.NotFoundError{Kind: "unit", Name: "jellyfin.service"}, http.StatusNotFound, CodeContainerNotFound},
+		{fmt.Errorf("stop: %w", runtime.ErrTimeout), http.StatusInternalServerError, CodeTimeout},
+		{fmt.Errorf("bad input"), http.StatusBadRequest, CodeInvalidRequest},
+		{fmt.Errorf("boom"), http.StatusInternalServerError, CodeInternal},
+	}
+	for _, tt := range tests {
+		if got := CodeOf(tt.err, tt.status); got != tt.want {
+			t.Errorf("CodeOf(%v, %d) = %s, want %s", tt.err, tt.status, got, tt.want)
+		}
+	}
+}
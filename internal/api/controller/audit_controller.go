@@ -0,0 +1,68 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bassista/go_spin/internal/api/apierror"
+	"github.com/bassista/go_spin/internal/audit"
+	"github.com/gin-gonic/gin"
+)
+
+// defaultAuditLimit is the number of entries returned by GET /admin/audit without limit.
+const defaultAuditLimit = 100
+
+// AuditController serves the audit log.
+type AuditController struct {
+	log *audit.Log
+}
+
+// NewAuditController creates a new AuditController.
+func NewAuditController(log *audit.Log) *AuditController {
+	return &AuditController{log: log}
+}
+
+// List handles GET /admin/audit?actor=&action=&kind=&name=&tenant=&since=&until=&limit= - returns
+// the matching entries, most recent first. since/until are RFC3339 times, limit defaults to
+// defaultAuditLimit and 0 returns every match.
+func (ac *AuditController) List(c *gin.Context) {
+	filter := audit.Filter{
+		Actor:  c.Query("actor"),
+		Action: c.Query("action"),
+		Kind:   c.Query("kind"),
+		Name:   c.Query("name"),
+		Tenant: c.Query("tenant"),
+		Limit:  defaultAuditLimit,
+	}
+	var ok bool
+	if filter.Since, ok = timeQuery(c, "since"); !ok {
+		return
+	}
+	if filter.Until, ok = timeQuery(c, "until"); !ok {
+		return
+	}
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 0 {
+			apierror.Respond(c, http.StatusBadRequest, "limit must be a non-negative integer")
+			return
+		}
+		filter.Limit = limit
+	}
+	c.JSON(http.StatusOK, ac.log.Query(filter))
+}
+
+// timeQuery parses the optional RFC3339 query parameter key, answering 400 when it is malformed.
+func timeQuery(c *gin.Context, key string) (time.Time, bool) {
+	v := c.Query(key)
+	if v == "" {
+		return time.Time{}, true
+	}
+	parsed, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, key+" must be an RFC3339 time")
+		return time.Time{}, false
+	}
+	return parsed, true
+}
@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bassista/go_spin/internal/audit"
+	"github.com/gin-gonic/gin"
+)
+
+func TestAuditController_List(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log, err := audit.Open(filepath.Join(t.TempDir(), "audit.jsonl"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer log.Close()
+	base := time.Date(2024, 3, 18, 8, 0, 0, 0, time.UTC)
+	for i, name := range []string{"web", "db", "web"} {
+		_ = log.Record(audit.Entry{Time: base.Add(time.Duration(i) * time.Hour), Actor: "alice", Action: audit.ActionStart, Kind: audit.KindContainer, Name: name})
+	}
+
+	r := gin.New()
+	r.GET("/admin/audit", NewAuditController(log).List)
+
+	tests := []struct {
+		name  string
+		query string
+		code  int
+		want  int
+	}{
+		{"every entry", "", http.StatusOK, 3},
+		{"by name", "?name=web", http.StatusOK, 2},
+		{"since", "?since=2024-03-18T09:00:00Z", http.StatusOK, 2},
+		{"limit", "?limit=1", http.StatusOK, 1},
+		{"unknown actor", "?actor=bob", http.StatusOK, 0},
+		{"invalid since", "?since=yesterday", http.StatusBadRequest, 0},
+		{"invalid limit", "?limit=-1", http.StatusBadRequest, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/audit"+tt.query, nil))
+			if w.Code != tt.code {
+				t.Fatalf("expected %d, got %d: %s", tt.code, w.Code, w.Body.String())
+			}
+			if tt.code != http.StatusOK {
+				return
+			}
+			var entries []audit.Entry
+			if err := json.Unmarshal(w.Body.Bytes(), &entries); err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != tt.want {
+				t.Errorf("expected %d entries, got %+v", tt.want, entries)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/bassista/go_spin/internal/api/apierror"
+	"github.com/bassista/go_spin/internal/cache"
+	"github.com/bassista/go_spin/internal/logger"
+	"github.com/bassista/go_spin/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// RestoreResponse is the result of POST /admin/restore/:id.
+type RestoreResponse struct {
+	Restored   int `json:"restored"`
+	Containers int `json:"containers"`
+	Groups     int `json:"groups"`
+	Schedules  int `json:"schedules"`
+}
+
+// BackupController lists the rotated backups of the data file and rolls the data back to one.
+type BackupController struct {
+	backups repository.BackupStore
+	store   cache.ReadOnlyStore
+}
+
+// NewBackupController creates a new BackupController.
+func NewBackupController(backups repository.BackupStore, store cache.ReadOnlyStore) *BackupController {
+	return &BackupController{backups: backups, store: store}
+}
+
+// List handles GET /admin/backups - returns the backups of the data file, most recent (id 1) first.
+func (bc *BackupController) List(c *gin.Context) {
+	backups, err := bc.backups.ListBackups()
+	if err != nil {
+		logger.WithComponent("backup-controller").Errorf("failed to list backups: %v", err)
+		apierror.Respond(c, http.StatusInternalServerError, "failed to list backups")
+		return
+	}
+	c.JSON(http.StatusOK, backups)
+}
+
+// Restore handles POST /admin/restore/:id - replaces the data with backup id. The current file is
+// itself backed up by the save that follows, so a restore can be rolled back too.
+// Returns 404 if the backup does not exist, 422 if it is not a valid data document.
+func (bc *BackupController) Restore(c *gin.Context) {
+	logger.WithComponent("backup-controller").Debugf("POST /admin/restore/%s handler called", c.Param("id"))
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "invalid backup id")
+		return
+	}
+
+	restored, err := bc.backups.LoadBackup(c.Request.Context(), id)
+	switch {
+	case errors.Is(err, repository.ErrBackupNotFound):
+		apierror.RespondCode(c, http.StatusNotFound, apierror.CodeBackupNotFound, fmt.Sprintf("backup %d not found", id))
+		return
+	case errors.Is(err, repository.ErrInvalidData):
+		logger.WithComponent("backup-controller").Warnf("backup %d is not usable: %v", id, err)
+		apierror.Respond(c, http.StatusUnprocessableEntity, fmt.Sprintf("backup %d is not a valid data document", id))
+		return
+	case err != nil:
+		logger.WithComponent("backup-controller").Errorf("failed to read backup %d: %v", id, err)
+		apierror.Respond(c, http.StatusInternalServerError, "failed to read backup")
+		return
+	}
+
+	store, ok := bc.store.(cache.TransactionalStore)
+	if !ok {
+		logger.WithComponent("backup-controller").Errorf("restore: store does not support transactions")
+		apierror.Respond(c, http.StatusInternalServerError, "restore not supported")
+		return
+	}
+	// The current metadata is kept so the restored document is persisted as the newest version
+	_, err = store.Apply(func(doc *repository.DataDocument) error {
+		metadata := doc.Metadata
+		*doc = *restored
+		doc.Metadata = metadata
+		return nil
+	})
+	if err != nil {
+		logger.WithComponent("backup-controller").Errorf("restore: cache error: %v", err)
+		apierror.Respond(c, http.StatusInternalServerError, "failed to update cache")
+		return
+	}
+
+	logger.WithComponent("backup-controller").Infof("data restored from backup %d", id)
+	c.JSON(http.StatusOK, RestoreResponse{
+		Restored:   id,
+		Containers: len(restored.Containers),
+		Groups:     len(restored.Groups),
+		Schedules:  len(restored.Schedules),
+	})
+}
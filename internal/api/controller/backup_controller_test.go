@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bassista/go_spin/internal/cache"
+	"github.com/bassista/go_spin/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+func TestBackupController_ListAndRestore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
+		t.Fatalf("failed to create data file: %v", err)
+	}
+	repo, err := repository.NewJSONRepository(path, repository.WithBackups(3))
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+
+	// Save an old document, then the current one: backup 1 holds the old document
+	old := repository.DataDocument{
+		Metadata:   repository.Metadata{LastUpdate: 1},
+		Containers: []repository.Container{{Name: "old", FriendlyName: "old", URL: "http://old", Active: boolPtr(true)}},
+	}
+	current := repository.DataDocument{Metadata: repository.Metadata{LastUpdate: 2}}
+	for _, doc := range []repository.DataDocument{old, current} {
+		if err := repo.Save(context.Background(), &doc); err != nil {
+			t.Fatalf("failed to save: %v", err)
+		}
+	}
+
+	store := cache.NewStore(current)
+	store.SetLastUpdate(2)
+	bc := NewBackupController(repo.(repository.BackupStore), store)
+	r := gin.New()
+	r.GET("/admin/backups", bc.List)
+	r.POST("/admin/restore/:id", bc.Restore)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/backups", nil))
+	var backups []repository.Backup
+	if err := json.Unmarshal(w.Body.Bytes(), &backups); err != nil {
+		t.Fatalf("failed to decode backups: %v", err)
+	}
+	if len(backups) != 2 || backups[0].ID != 1 {
+		t.Fatalf("expected 2 backups, got %+v", backups)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/restore/1", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	doc, _ := store.Snapshot()
+	if len(doc.Containers) != 1 || doc.Containers[0].Name != "old" {
+		t.Errorf("expected the old document to be restored, got %+v", doc.Containers)
+	}
+	if doc.Metadata.LastUpdate != 2 {
+		t.Errorf("expected the current lastUpdate to be kept, got %d", doc.Metadata.LastUpdate)
+	}
+	if !store.IsDirty() {
+		t.Error("expected the restored document to be persisted")
+	}
+
+	// Only backups 1 and 2 exist
+	tests := []struct {
+		id         string
+		wantStatus int
+	}{
+		{"3", http.StatusNotFound},
+		{"9", http.StatusNotFound},
+		{"x", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/restore/"+tt.id, nil))
+		if w.Code != tt.wantStatus {
+			t.Errorf("restore %s: expected status %d, got %d", tt.id, tt.wantStatus, w.Code)
+		}
+	}
+}
+
+func TestBackupController_Restore_InvalidBackup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte("not json"), 0644); err != nil {
+		t.Fatalf("failed to create data file: %v", err)
+	}
+	repo, err := repository.NewJSONRepository(path, repository.WithBackups(1))
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	doc := repository.DataDocument{}
+	if err := repo.Save(context.Background(), &doc); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+
+	r := gin.New()
+	r.POST("/admin/restore/:id", NewBackupController(repo.(repository.BackupStore), cache.NewStore(doc)).Restore)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/restore/1", nil))
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected status 422, got %d: %s", w.Code, w.Body.String())
+	}
+}
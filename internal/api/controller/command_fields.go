@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"errors"
+	"maps"
+	"net/http"
+	"slices"
+
+	"github.com/bassista/go_spin/internal/api/apierror"
+	"github.com/bassista/go_spin/internal/api/middleware"
+	"github.com/bassista/go_spin/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// commandField is a container field holding commands run inside the container. Only an
+// authenticated admin may set or change it through the API: with authentication disabled such
+// fields can only come from the data file.
+type commandField struct {
+	name  string
+	equal func(a, b repository.Container) bool
+}
+
+// commandFields lists the container fields holding commands.
+var commandFields = []commandField{
+	{"healthCheck.command", func(a, b repository.Container) bool {
+		return slices.Equal(healthCheckCommand(a), healthCheckCommand(b))
+	}},
+	{"execCommands", func(a, b repository.Container) bool {
+		return maps.EqualFunc(a.ExecCommands, b.ExecCommands, slices.Equal[[]string])
+	}},
+	{"preStop.command", func(a, b repository.Container) bool {
+		return slices.Equal(preStopCommand(a), preStopCommand(b))
+	}},
+}
+
+func healthCheckCommand(c repository.Container) []string {
+	if c.HealthCheck == nil {
+		return nil
+	}
+	return c.HealthCheck.Command
+}
+
+func preStopCommand(c repository.Container) []string {
+	if c.PreStop == nil {
+		return nil
+	}
+	return c.PreStop.Command
+}
+
+// changedCommandFields returns the command fields differing between before (nil for a new
+// container) and after.
+func changedCommandFields(before *repository.Container, after repository.Container) []string {
+	var old repository.Container
+	if before != nil {
+		old = *before
+	}
+	var changed []string
+	for _, field := range commandFields {
+		if !field.equal(old, after) {
+			changed = append(changed, field.name)
+		}
+	}
+	return changed
+}
+
+// changedDocumentCommandFields returns the command fields, prefixed by the container name, differing
+// between the containers before and after a bulk change.
+func changedDocumentCommandFields(before, after []repository.Container) []string {
+	byName := make(map[string]*repository.Container, len(before))
+	for i := range before {
+		byName[before[i].Name] = &before[i]
+	}
+	var changed []string
+	for _, container := range after {
+		for _, field := range changedCommandFields(byName[container.Name], container) {
+			changed = append(changed, container.Name+"."+field)
+		}
+	}
+	return changed
+}
+
+// errCommandFieldsForbidden aborts a transaction changing command fields without the right to.
+var errCommandFieldsForbidden = errors.New("command fields changed without admin rights")
+
+// mayChangeCommandFields reports whether the request may apply the changed command fields: none
+// changed, or it is authenticated as admin.
+func mayChangeCommandFields(c *gin.Context, changed []string) bool {
+	return len(changed) == 0 || middleware.HasAuthenticatedRole(c, middleware.RoleAdmin)
+}
+
+// respondCommandFieldsForbidden answers 403 listing the changed command fields.
+func respondCommandFieldsForbidden(c *gin.Context, changed []string) {
+	apierror.RespondDetails(c, http.StatusForbidden, apierror.CodeForbidden,
+		"only an authenticated admin may set commands run inside containers", gin.H{"fields": changed})
+}
@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bassista/go_spin/internal/api/middleware"
+	"github.com/bassista/go_spin/internal/cache"
+	"github.com/bassista/go_spin/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// newCommandFieldsRouter serves the container writes of store behind API keys "op" (operator) and
+// "adm" (admin), or without authentication when auth is false.
+func newCommandFieldsRouter(store *cache.Store, auth bool) *gin.Engine {
+	keys := map[string]string{}
+	if auth {
+		keys = map[string]string{"op": middleware.RoleOperator, "adm": middleware.RoleAdmin}
+	}
+	cc := NewContainerController(context.Background(), store, &mockRuntime{})
+	dc := NewDocumentController(store)
+	r := gin.New()
+	r.Use(middleware.APIAuth(keys, nil))
+	r.POST("/container", cc.CreateOrUpdateContainer)
+	r.PATCH("/container/:name", cc.PatchContainer)
+	r.POST("/admin/import", dc.Import)
+	return r
+}
+
+func TestContainerCommandFields_RequireAuthenticatedAdmin(t *testing.T) {
+	withCommand := `{"name":"web","friendly_name":"web","url":"http://web","active":true,"healthCheck":{"type":"exec","command":["true"]}}`
+	tests := []struct {
+		name       string
+		auth       bool
+		key        string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+	}{
+		{"operator sets a command", true, "op", http.MethodPost, "/container", withCommand, http.StatusForbidden},
+		{"admin sets a command", true, "adm", http.MethodPost, "/container", withCommand, http.StatusOK},
+		{"no authentication", false, "", http.MethodPost, "/container", withCommand, http.StatusForbidden},
+		{"operator keeps the command", true, "op", http.MethodPost, "/container",
+			`{"name":"db","friendly_name":"db","url":"http://db2","active":true,"healthCheck":{"type":"exec","command":["pg_isready"]}}`, http.StatusOK},
+		{"operator patches other fields", true, "op", http.MethodPatch, "/container/db", `{"url":"http://db2"}`, http.StatusOK},
+		{"operator patches the command", true, "op", http.MethodPatch, "/container/db", `{"healthCheck":{"command":["true"]}}`, http.StatusForbidden},
+		{"operator clears the command", true, "op", http.MethodPatch, "/container/db", `{"healthCheck":null}`, http.StatusForbidden},
+		{"operator sets exec commands", true, "op", http.MethodPatch, "/container/db", `{"execCommands":{"flush":["redis-cli","save"]}}`, http.StatusForbidden},
+		{"admin sets exec commands", true, "adm", http.MethodPatch, "/container/db", `{"execCommands":{"flush":["redis-cli","save"]}}`, http.StatusOK},
+		{"operator sets a pre-stop command", true, "op", http.MethodPatch, "/container/db", `{"preStop":{"command":["pg_ctl","stop"]}}`, http.StatusForbidden},
+		{"operator sets a pre-stop url", true, "op", http.MethodPatch, "/container/db", `{"preStop":{"url":"http://db/shutdown"}}`, http.StatusOK},
+		{"import without authentication", false, "", http.MethodPost, "/admin/import",
+			`{"containers":[` + withCommand + `]}`, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := cache.NewStore(repository.DataDocument{Containers: []repository.Container{
+				{Name: "db", FriendlyName: "db", URL: "http://db", Active: boolPtr(true),
+					HealthCheck: &repository.HealthCheck{Type: repository.HealthCheckExec, Command: []string{"pg_isready"}}},
+			}})
+			r := newCommandFieldsRouter(store, tt.auth)
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.key != "" {
+				req.Header.Set(middleware.HeaderAPIKey, tt.key)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusForbidden {
+				return
+			}
+			doc, _ := store.Snapshot()
+			if len(doc.Containers) != 1 || healthCheckCommand(doc.Containers[0])[0] != "pg_isready" {
+				t.Errorf("expected the rejected write to leave the containers untouched, got %+v", doc.Containers)
+			}
+		})
+	}
+}
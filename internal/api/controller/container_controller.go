@@ -2,13 +2,20 @@ package controller
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/bassista/go_spin/internal/api/apierror"
 	"github.com/bassista/go_spin/internal/cache"
+	"github.com/bassista/go_spin/internal/janitor"
 	"github.com/bassista/go_spin/internal/logger"
+	"github.com/bassista/go_spin/internal/ops"
 	"github.com/bassista/go_spin/internal/repository"
 	"github.com/bassista/go_spin/internal/runtime"
 	"github.com/gin-gonic/gin"
@@ -17,7 +24,13 @@ import (
 
 // ContainerController handles container-related HTTP endpoints using the generic CRUD controller.
 type ContainerController struct {
-	crud *CrudController[repository.Container]
+	crud      *CrudController[repository.Container]
+	readiness *readinessCache       // optional cache of Ready probe results, nil disables caching
+	external  *externalReadyChecker // consulted for containers with ReadyCheckType "external"
+	urlScheme string                // prepended to schemeless group redirect URLs, see absoluteURL
+	ops       *ops.Queue            // start operations consulted by Status, nil without
+	// execChecks runs exec health checks, only enabled on routes behind authentication
+	execChecks bool
 }
 
 // NewContainerController creates a new ContainerController with the given cache store.
@@ -34,10 +47,166 @@ func NewContainerController(ctx context.Context, store cache.ContainerStore, run
 	}
 }
 
-// AllContainers handles GET /containers - returns all containers.
+// SetReadinessCache enables caching of Ready probe results: ready results are reused for
+// positiveTTL, not-ready results for negativeTTL. Both zero leaves caching disabled.
+func (cc *ContainerController) SetReadinessCache(positiveTTL, negativeTTL time.Duration) {
+	if positiveTTL <= 0 && negativeTTL <= 0 {
+		cc.readiness = nil
+		return
+	}
+	cc.readiness = newReadinessCache(positiveTTL, negativeTTL)
+}
+
+// ReadinessCache returns the cache of Ready probe results so the janitor can prune it, nil when
+// caching is disabled.
+func (cc *ContainerController) ReadinessCache() janitor.Prunable {
+	if cc.readiness == nil {
+		return nil
+	}
+	return cc.readiness
+}
+
+// SetExternalReadyChecker configures the external service asked for the readiness of containers
+// with ReadyCheckType "external". An empty url leaves those containers never ready.
+func (cc *ContainerController) SetExternalReadyChecker(url string, timeout time.Duration) {
+	cc.external = newExternalReadyChecker(url, timeout)
+}
+
+// SetExecHealthChecks enables the exec health checks, which run commands inside containers: they
+// are left disabled, and the container URLs probed instead, on routes reached without authentication.
+func (cc *ContainerController) SetExecHealthChecks(enabled bool) {
+	cc.execChecks = enabled
+}
+
+// SetDefaultURLScheme sets the scheme prepended to schemeless member URLs in group readiness
+// redirects (misc.default_url_scheme).
+func (cc *ContainerController) SetDefaultURLScheme(scheme string) {
+	cc.urlScheme = scheme
+}
+
+// SetAnnotationLimits caps the Notes length (characters) and Meta key count accepted on
+// create/update. Zero leaves the corresponding field unlimited.
+func (cc *ContainerController) SetAnnotationLimits(maxNotesLength, maxMetaKeys int) {
+	if v, ok := cc.crud.Validator.(*ContainerCrudValidator); ok {
+		v.maxNotesLength = maxNotesLength
+		v.maxMetaKeys = maxMetaKeys
+	}
+}
+
+// SetReadStore serves the container list from reader (e.g. a read replica) instead of the primary store.
+func (cc *ContainerController) SetReadStore(reader cache.ReadOnlyStore) {
+	if svc, ok := cc.crud.Service.(*ContainerCrudService); ok {
+		svc.Reader = reader
+	}
+}
+
+// AllContainers handles GET /containers - returns all containers, optionally filtered with ?label=key[=value].
+// Hidden containers are left out unless ?includeHidden=true.
 func (cc *ContainerController) AllContainers(c *gin.Context) {
 	logger.WithComponent("container-controller").Debugf("GET /containers handler called")
-	cc.crud.GetAll(c)
+	includeHidden := false
+	if v := c.Query("includeHidden"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			apierror.Respond(c, http.StatusBadRequest, "invalid includeHidden")
+			return
+		}
+		includeHidden = parsed
+	}
+	filter := repository.ContainerFilter{Label: c.Query("label")}
+	if filter.IsEmpty() && includeHidden {
+		cc.crud.GetAll(c)
+		return
+	}
+
+	items, err := cc.crud.Service.All()
+	if err != nil {
+		apierror.Respond(c, http.StatusInternalServerError, "failed to read resource list")
+		return
+	}
+	items = repository.FilterContainers(items, filter)
+	if !includeHidden {
+		items = withoutHidden(items)
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+// withoutHidden returns the containers not flagged hidden, preserving their order.
+func withoutHidden(containers []repository.Container) []repository.Container {
+	out := make([]repository.Container, 0, len(containers))
+	for _, container := range containers {
+		if container.Hidden == nil || !*container.Hidden {
+			out = append(out, container)
+		}
+	}
+	return out
+}
+
+// SetActiveRequest is the payload of POST /containers/active.
+type SetActiveRequest struct {
+	Active *bool                      `json:"active"`
+	Filter repository.ContainerFilter `json:"filter"`
+}
+
+// SetActiveResponse lists the containers whose active flag was changed.
+type SetActiveResponse struct {
+	Affected []string `json:"affected"`
+}
+
+// SetActive handles POST /containers/active - sets Active on every container matching the filter
+// in a single store transaction and returns the names of the containers that changed.
+func (cc *ContainerController) SetActive(c *gin.Context) {
+	logger.WithComponent("container-controller").Debugf("POST /containers/active handler called")
+
+	var req SetActiveRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Active == nil {
+		apierror.Respond(c, http.StatusBadRequest, "invalid payload")
+		return
+	}
+	// An empty filter would flip every container: require an explicit selection.
+	if req.Filter.IsEmpty() {
+		apierror.Respond(c, http.StatusBadRequest, "filter is required")
+		return
+	}
+
+	svc, ok := cc.crud.Service.(*ContainerCrudService)
+	if !ok {
+		logger.WithComponent("container-controller").Errorf("set active: unexpected service type")
+		apierror.Respond(c, http.StatusInternalServerError, "bulk update not supported")
+		return
+	}
+	store, ok := svc.Store.(cache.TransactionalStore)
+	if !ok {
+		logger.WithComponent("container-controller").Errorf("set active: store does not support transactions")
+		apierror.Respond(c, http.StatusInternalServerError, "bulk update not supported")
+		return
+	}
+
+	active := *req.Active
+	affected := []string{}
+	_, err := store.Apply(func(doc *repository.DataDocument) error {
+		for i := range doc.Containers {
+			ct := &doc.Containers[i]
+			if !req.Filter.Matches(*ct) {
+				continue
+			}
+			if ct.Active != nil && *ct.Active == active {
+				continue
+			}
+			v := active
+			ct.Active = &v
+			affected = append(affected, ct.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		logger.WithComponent("container-controller").Errorf("set active: cache error: %v", err)
+		apierror.Respond(c, http.StatusInternalServerError, "failed to update cache")
+		return
+	}
+
+	logger.WithComponent("container-controller").Infof("set active=%v on %d containers matching label %q", active, len(affected), req.Filter.Label)
+	c.JSON(http.StatusOK, SetActiveResponse{Affected: affected})
 }
 
 // CreateOrUpdateContainer handles POST /container - creates or updates a container.
@@ -46,13 +215,282 @@ func (cc *ContainerController) CreateOrUpdateContainer(c *gin.Context) {
 	cc.crud.CreateOrUpdate(c)
 }
 
+// PatchContainer handles PATCH /container/:name - applies a JSON merge patch (RFC 7386) to the
+// stored container, so only the changed fields are sent (e.g. {"active": false}); null removes an
+// optional field. Only RenameContainer changes the name and the runtime state (running,
+// runningSince, lastError) is kept. The patched container is validated as on POST /container.
+func (cc *ContainerController) PatchContainer(c *gin.Context) {
+	name := c.Param("name")
+	logger.WithComponent("container-controller").Debugf("PATCH /container/%s handler called", name)
+
+	body, err := c.GetRawData()
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "invalid payload")
+		return
+	}
+	var patch map[string]any
+	if err := json.Unmarshal(body, &patch); err != nil || patch == nil {
+		apierror.Respond(c, http.StatusBadRequest, "invalid payload: a JSON object is required")
+		return
+	}
+	if v, ok := patch["name"]; ok && v != name {
+		apierror.Respond(c, http.StatusBadRequest, "the container name cannot be changed, use POST /container/:name/rename")
+		return
+	}
+
+	svc, ok := cc.crud.Service.(*ContainerCrudService)
+	if !ok {
+		logger.WithComponent("container-controller").Errorf("patch container: unexpected service type")
+		apierror.Respond(c, http.StatusInternalServerError, "patch not supported")
+		return
+	}
+	store, ok := svc.Store.(cache.TransactionalStore)
+	if !ok {
+		logger.WithComponent("container-controller").Errorf("patch container: store does not support transactions")
+		apierror.Respond(c, http.StatusInternalServerError, "patch not supported")
+		return
+	}
+
+	var patched repository.Container
+	var invalid error
+	var commandsChanged []string
+	_, err = store.Apply(func(doc *repository.DataDocument) error {
+		for i := range doc.Containers {
+			if doc.Containers[i].Name != name {
+				continue
+			}
+			current := doc.Containers[i]
+			patched, invalid = applyContainerPatch(current, patch)
+			if invalid == nil && cc.crud.Validator != nil {
+				invalid = cc.crud.Validator.Validate(patched)
+			}
+			if invalid != nil {
+				return invalid
+			}
+			if commandsChanged = changedCommandFields(&current, patched); !mayChangeCommandFields(c, commandsChanged) {
+				return errCommandFieldsForbidden
+			}
+			doc.Containers[i] = patched
+			return nil
+		}
+		return cache.ErrContainerNotFound
+	})
+	switch {
+	case invalid != nil:
+		apierror.RespondError(c, http.StatusBadRequest, invalid)
+		return
+	case errors.Is(err, errCommandFieldsForbidden):
+		respondCommandFieldsForbidden(c, commandsChanged)
+		return
+	case errors.Is(err, cache.ErrContainerNotFound):
+		apierror.RespondCode(c, http.StatusNotFound, apierror.CodeContainerNotFound, "container not found")
+		return
+	case err != nil:
+		logger.WithComponent("container-controller").Errorf("patch container %s: cache error: %v", name, err)
+		apierror.Respond(c, http.StatusInternalServerError, "failed to update cache")
+		return
+	}
+
+	logger.WithComponent("container-controller").Debugf("container %s patched", name)
+	c.JSON(http.StatusOK, patched)
+}
+
+// RenameRequest is the body of POST /container/:name/rename.
+type RenameRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// RenameResponse is the result of POST /container/:name/rename.
+type RenameResponse struct {
+	PreviousName string `json:"previousName"`
+	Name         string `json:"name"`
+	cache.RenameReport
+}
+
+// RenameContainer handles POST /container/:name/rename - renames the container and, in the same
+// transaction, the references to it in groups, the display order, schedules and dependsOn lists.
+// Returns the updated references, 404 for an unknown container and 409 when the new name exists.
+func (cc *ContainerController) RenameContainer(c *gin.Context) {
+	name := c.Param("name")
+	logger.WithComponent("container-controller").Debugf("POST /container/%s/rename handler called", name)
+
+	var req RenameRequest
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.Name) == "" {
+		apierror.Respond(c, http.StatusBadRequest, "invalid payload: name is required")
+		return
+	}
+	newName := strings.TrimSpace(req.Name)
+
+	svc, ok := cc.crud.Service.(*ContainerCrudService)
+	if !ok {
+		logger.WithComponent("container-controller").Errorf("rename container: unexpected service type")
+		apierror.Respond(c, http.StatusInternalServerError, "rename not supported")
+		return
+	}
+	store, ok := svc.Store.(cache.TransactionalStore)
+	if !ok {
+		logger.WithComponent("container-controller").Errorf("rename container: store does not support transactions")
+		apierror.Respond(c, http.StatusInternalServerError, "rename not supported")
+		return
+	}
+
+	var report cache.RenameReport
+	_, err := store.Apply(func(doc *repository.DataDocument) error {
+		var err error
+		report, err = cache.RenameContainer(doc, name, newName)
+		return err
+	})
+	switch {
+	case errors.Is(err, cache.ErrContainerNotFound):
+		apierror.RespondCode(c, http.StatusNotFound, apierror.CodeContainerNotFound, "container not found")
+		return
+	case errors.Is(err, cache.ErrContainerExists):
+		apierror.RespondCode(c, http.StatusConflict, apierror.CodeContainerExists, "container "+newName+" already exists")
+		return
+	case err != nil:
+		logger.WithComponent("container-controller").Errorf("rename container %s: cache error: %v", name, err)
+		apierror.Respond(c, http.StatusInternalServerError, "failed to update cache")
+		return
+	}
+
+	logger.WithComponent("container-controller").Infof("container %s renamed to %s: %d groups, %d schedules and %d dependents updated",
+		name, newName, len(report.Groups), len(report.Schedules), len(report.Dependents))
+	c.JSON(http.StatusOK, RenameResponse{PreviousName: name, Name: newName, RenameReport: report})
+}
+
+// applyContainerPatch returns current with the merge patch applied, keeping its name and runtime
+// state and lowercasing the friendly name as on every container update.
+func applyContainerPatch(current repository.Container, patch map[string]any) (repository.Container, error) {
+	raw, err := json.Marshal(current)
+	if err != nil {
+		return repository.Container{}, err
+	}
+	var target map[string]any
+	if err := json.Unmarshal(raw, &target); err != nil {
+		return repository.Container{}, err
+	}
+	merged, err := json.Marshal(mergePatch(target, patch))
+	if err != nil {
+		return repository.Container{}, err
+	}
+	var patched repository.Container
+	if err := json.Unmarshal(merged, &patched); err != nil {
+		return repository.Container{}, fmt.Errorf("invalid payload: %w", err)
+	}
+	patched.Name = current.Name
+	patched.KeepRuntimeState(current)
+	patched.FriendlyName = strings.ToLower(patched.FriendlyName)
+	return patched, nil
+}
+
+// mergePatch applies the JSON merge patch (RFC 7386) patch to target: objects are merged
+// recursively, null removes a member and any other value replaces it.
+func mergePatch(target any, patch any) any {
+	patchObj, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	targetObj, ok := target.(map[string]any)
+	if !ok {
+		targetObj = map[string]any{}
+	}
+	for key, value := range patchObj {
+		if value == nil {
+			delete(targetObj, key)
+			continue
+		}
+		targetObj[key] = mergePatch(targetObj[key], value)
+	}
+	return targetObj
+}
+
+// DependencyStatus reports the state of one DependsOn entry in the container detail view.
+type DependencyStatus struct {
+	Name    string `json:"name"`
+	Running bool   `json:"running"`
+	Ready   bool   `json:"ready"`
+	Reason  string `json:"reason,omitempty"` // why the dependency is not ready, as reported by the Ready endpoint
+	Error   string `json:"error,omitempty"`
+}
+
+// ContainerDetailResponse is the container detail payload: the container itself plus the
+// state of its dependencies, to help diagnose why a container is not ready.
+type ContainerDetailResponse struct {
+	repository.Container
+	Dependencies []DependencyStatus `json:"dependencies,omitempty"`
+}
+
+// GetContainer handles GET /container/:name - returns a single container with its live running state
+// and the readiness of its DependsOn dependencies.
+func (cc *ContainerController) GetContainer(c *gin.Context) {
+	name := c.Param("name")
+	logger.WithComponent("container-controller").Debugf("GET /container/%s handler called", name)
+	if name == "" {
+		apierror.Respond(c, http.StatusBadRequest, "missing container name")
+		return
+	}
+
+	items, err := cc.crud.Service.All()
+	if err != nil {
+		logger.WithComponent("container-controller").Errorf("get container %s: cache error: %v", name, err)
+		apierror.Respond(c, http.StatusInternalServerError, "failed to read container list")
+		return
+	}
+
+	for _, item := range items {
+		if item.Name == name {
+			c.JSON(http.StatusOK, ContainerDetailResponse{
+				Container:    item,
+				Dependencies: cc.dependencyStatuses(c.Request.Context(), item, items),
+			})
+			return
+		}
+	}
+	apierror.RespondCode(c, http.StatusNotFound, apierror.CodeContainerNotFound, "container not found")
+}
+
+// dependencyStatuses probes the dependencies of container in parallel. items must carry the
+// live running state, as returned by the CRUD service.
+func (cc *ContainerController) dependencyStatuses(ctx context.Context, container repository.Container, items []repository.Container) []DependencyStatus {
+	if len(container.DependsOn) == 0 {
+		return nil
+	}
+	byName := make(map[string]repository.Container, len(items))
+	for _, item := range items {
+		byName[item.Name] = item
+	}
+
+	statuses := make([]DependencyStatus, len(container.DependsOn))
+	var wg sync.WaitGroup
+	for i, depName := range container.DependsOn {
+		statuses[i].Name = depName
+		dep, ok := byName[depName]
+		if !ok {
+			statuses[i].Error = "container not found"
+			continue
+		}
+		if dep.Running == nil || !*dep.Running {
+			statuses[i].Reason = reasonNotRunning
+			continue
+		}
+		statuses[i].Running = true
+		wg.Add(1)
+		go func(status *DependencyStatus, dep repository.Container) {
+			defer wg.Done()
+			status.Ready, status.Reason, _ = cc.probeReady(ctx, &dep)
+		}(&statuses[i], dep)
+	}
+	wg.Wait()
+	return statuses
+}
+
 // DeleteContainer handles DELETE /container/:name - deletes a container by name.
 func (cc *ContainerController) DeleteContainer(c *gin.Context) {
 	name := c.Param("name")
 	logger.WithComponent("container-controller").Debugf("DELETE /container/%s handler called", name)
 	if name == "" {
 		logger.WithComponent("container-controller").Debugf("delete container: missing name parameter")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "missing container name"})
+		apierror.Respond(c, http.StatusBadRequest, "missing container name")
 		return
 	}
 
@@ -60,11 +498,11 @@ func (cc *ContainerController) DeleteContainer(c *gin.Context) {
 	if err != nil {
 		if errors.Is(err, cache.ErrContainerNotFound) {
 			logger.WithComponent("container-controller").Debugf("delete container %s: not found", name)
-			c.JSON(http.StatusNotFound, gin.H{"error": "container not found"})
+			apierror.RespondCode(c, http.StatusNotFound, apierror.CodeContainerNotFound, "container not found")
 			return
 		}
 		logger.WithComponent("container-controller").Errorf("delete container %s: cache error: %v", name, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update cache"})
+		apierror.Respond(c, http.StatusInternalServerError, "failed to update cache")
 		return
 	}
 
@@ -78,7 +516,7 @@ func (cc *ContainerController) Ready(c *gin.Context) {
 	name := c.Param("name")
 	logger.WithComponent("container-controller").Debugf("GET /container/%s/ready handler called", name)
 	if name == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"ready": false})
+		c.JSON(http.StatusBadRequest, ReadyResponse{})
 		return
 	}
 
@@ -86,7 +524,7 @@ func (cc *ContainerController) Ready(c *gin.Context) {
 	svc, ok := cc.crud.Service.(*ContainerCrudService)
 	if !ok {
 		logger.WithComponent("container-controller").Errorf("ready: unexpected service type")
-		c.JSON(http.StatusInternalServerError, gin.H{"ready": false})
+		c.JSON(http.StatusInternalServerError, ReadyResponse{})
 		return
 	}
 
@@ -94,7 +532,7 @@ func (cc *ContainerController) Ready(c *gin.Context) {
 	doc, err := svc.Store.Snapshot()
 	if err != nil {
 		logger.WithComponent("container-controller").Errorf("ready: failed to snapshot store: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"ready": false})
+		c.JSON(http.StatusInternalServerError, ReadyResponse{})
 		return
 	}
 
@@ -106,8 +544,15 @@ func (cc *ContainerController) Ready(c *gin.Context) {
 		}
 	}
 	if container == nil {
+		// Waiting pages of groups poll with the group name
+		for i := range doc.Groups {
+			if doc.Groups[i].Name == name {
+				cc.respondGroupReady(c, svc, doc, doc.Groups[i])
+				return
+			}
+		}
 		logger.WithComponent("container-controller").Warnf("ready: container not found: %s", name)
-		c.JSON(http.StatusNotFound, gin.H{"ready": false})
+		c.JSON(http.StatusNotFound, ReadyResponse{})
 		return
 	}
 
@@ -115,53 +560,88 @@ func (cc *ContainerController) Ready(c *gin.Context) {
 	running, err := svc.Runtime.IsRunning(svc.Ctx, container.Name)
 	if err != nil {
 		logger.WithComponent("container-controller").Warnf("ready: runtime check failed for %s: %v", container.Name, err)
-		c.JSON(http.StatusOK, gin.H{"ready": false})
+		c.JSON(http.StatusOK, ReadyResponse{Reason: reasonRuntimeError})
 		return
 	}
 	if !running {
-		c.JSON(http.StatusOK, gin.H{"ready": false})
+		c.JSON(http.StatusOK, notReadyResponse(container, reasonNotRunning))
 		return
 	}
 
-	if container.URL == "" {
+	if !hasReadinessCheck(container) {
 		logger.WithComponent("container-controller").Warnf("ready: container URL is empty: %s", name)
-		c.JSON(http.StatusInternalServerError, gin.H{"ready": false})
+		c.JSON(http.StatusInternalServerError, ReadyResponse{Reason: reasonNoURL})
 		return
 	}
 
-	containerURL := container.URL
-	if !strings.HasPrefix(containerURL, "http://") && !strings.HasPrefix(containerURL, "https://") {
-		containerURL = "https://" + containerURL
-	}
-	if !strings.HasSuffix(containerURL, "/") {
-		containerURL = containerURL + "/"
+	isContainerUrlReady, reason, cached := cc.probeReady(c.Request.Context(), container)
+	logger.WithComponent("container-controller").Debugf("GET /container/%s/ready handled with status: %v (cached: %v)", name, isContainerUrlReady, cached)
+	if !isContainerUrlReady {
+		c.JSON(http.StatusOK, notReadyResponse(container, reason))
+		return
 	}
+	c.JSON(http.StatusOK, ReadyResponse{Ready: true})
+}
 
-	// Perform GET with timeout
-	reqCtx, cancel := context.WithTimeout(c.Request.Context(), 1*time.Second)
-	defer cancel()
-
-	req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, containerURL, nil)
-	if err != nil {
-		logger.WithComponent("container-controller").Warnf("ready: failed to create request for %s and url %s: %v", container.Name, containerURL, err)
-		c.JSON(http.StatusOK, gin.H{"ready": false})
+// GroupReady checks whether the active members of the group identified by name pass their
+// readiness checks: all of them, or one with Group.ReadyMode "any".
+// Route: GET /group/:name/ready
+func (cc *ContainerController) GroupReady(c *gin.Context) {
+	name := c.Param("name")
+	logger.WithComponent("container-controller").Debugf("GET /group/%s/ready handler called", name)
+	svc, ok := cc.crud.Service.(*ContainerCrudService)
+	if !ok {
+		logger.WithComponent("container-controller").Errorf("group ready: unexpected service type")
+		c.JSON(http.StatusInternalServerError, ReadyResponse{})
 		return
 	}
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	doc, err := svc.Store.Snapshot()
 	if err != nil {
-		logger.WithComponent("container-controller").Warnf("ready: request failed for %s and url %s: %v", container.Name, containerURL, err)
-		c.JSON(http.StatusOK, gin.H{"ready": false})
+		logger.WithComponent("container-controller").Errorf("group ready: failed to snapshot store: %v", err)
+		c.JSON(http.StatusInternalServerError, ReadyResponse{})
 		return
-	} else {
-		logger.WithComponent("container-controller").Debugf("ready: request succeeded for %s and url %s with status %d", container.Name, containerURL, resp.StatusCode)
 	}
+	for i := range doc.Groups {
+		if doc.Groups[i].Name == name {
+			cc.respondGroupReady(c, svc, doc, doc.Groups[i])
+			return
+		}
+	}
+	c.JSON(http.StatusNotFound, ReadyResponse{})
+}
+
+// respondGroupReady answers a readiness check of group with the readiness of each member.
+func (cc *ContainerController) respondGroupReady(c *gin.Context, svc *ContainerCrudService, doc repository.DataDocument, group repository.Group) {
+	resp := groupReadiness(c.Request.Context(), svc.Runtime, doc, group, func(ctx context.Context, container *repository.Container) (bool, string) {
+		ready, reason, _ := cc.probeReady(ctx, container)
+		return ready, reason
+	})
+	resp.RedirectURL = absoluteURL(resp.RedirectURL, cc.urlScheme)
+	logger.WithComponent("container-controller").Debugf("group %s ready: %v", group.Name, resp.Ready)
+	c.JSON(http.StatusOK, resp)
+}
 
-	defer func() {
-		_ = resp.Body.Close()
-	}()
+// probeReady probes the container readiness, reusing a cached result when available.
+// It returns the result, the not-ready reason and whether the result came from the cache.
+func (cc *ContainerController) probeReady(ctx context.Context, container *repository.Container) (bool, string, bool) {
+	var rt runtime.ContainerRuntime
+	if svc, ok := cc.crud.Service.(*ContainerCrudService); ok {
+		rt = svc.Runtime
+	}
+	if cc.readiness == nil {
+		ready, reason := probeContainerReadiness(ctx, container, cc.external, rt, cc.execChecks)
+		return ready, reason, false
+	}
+	if ready, reason, ok := cc.readiness.get(container.Name); ok {
+		return ready, reason, true
+	}
+	ready, reason := probeContainerReadiness(ctx, container, cc.external, rt, cc.execChecks)
+	cc.readiness.put(container.Name, ready, reason)
+	return ready, reason, false
+}
 
-	isContainerUrlReady := resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusPermanentRedirect || resp.StatusCode == http.StatusTemporaryRedirect
-	logger.WithComponent("container-controller").Debugf("GET /container/%s/ready handled with status: %v", name, isContainerUrlReady)
-	c.JSON(http.StatusOK, gin.H{"ready": isContainerUrlReady})
+// notReadyResponse builds the not-ready payload with the reason, including the last recorded start
+// error (e.g. a startup timeout) so the waiting page can stop polling and show the failure.
+func notReadyResponse(container *repository.Container, reason string) ReadyResponse {
+	return ReadyResponse{Reason: reason, Error: container.LastError}
 }
@@ -7,7 +7,11 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/bassista/go_spin/internal/cache"
 	"github.com/bassista/go_spin/internal/repository"
@@ -74,6 +78,10 @@ func (m *mockContainerRuntimeForContainer) Stats(ctx context.Context, containerN
 	return runtime.ContainerStats{}, nil
 }
 
+func (m *mockContainerRuntimeForContainer) Ping(ctx context.Context) (runtime.PingResult, error) {
+	return runtime.PingResult{}, nil
+}
+
 func TestContainerController_AllContainers(t *testing.T) {
 	active := true
 	running := false
@@ -144,6 +152,80 @@ func TestContainerController_CreateOrUpdateContainer_Valid(t *testing.T) {
 	}
 }
 
+func TestContainerController_CreateOrUpdateContainer_NotesAndMetaRoundTrip(t *testing.T) {
+	store := cache.NewStore(repository.DataDocument{})
+	cc := NewContainerController(context.Background(), store, &mockContainerRuntimeForContainer{})
+	cc.SetAnnotationLimits(100, 4)
+
+	r := gin.New()
+	r.POST("/container", cc.CreateOrUpdateContainer)
+	r.GET("/containers", cc.AllContainers)
+
+	container := repository.Container{
+		Name:         "annotated",
+		FriendlyName: "Annotated",
+		URL:          "http://annotated.local",
+		Active:       boolPtr(true),
+		Notes:        "owned by team-a, see OPS-42",
+		Meta:         map[string]string{"owner": "team-a", "ticket": "OPS-42"},
+	}
+	body, _ := json.Marshal(container)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/container", bytes.NewReader(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/containers", nil))
+	var items []repository.Container
+	if err := json.Unmarshal(w.Body.Bytes(), &items); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(items) != 1 || items[0].Notes != container.Notes || items[0].Meta["owner"] != "team-a" || items[0].Meta["ticket"] != "OPS-42" {
+		t.Fatalf("expected notes and meta returned unchanged, got %+v", items)
+	}
+
+	// The listing holds a copy: mutating it must not leak into the store
+	items[0].Meta["owner"] = "someone-else"
+	doc, _ := store.Snapshot()
+	if doc.Containers[0].Meta["owner"] != "team-a" {
+		t.Errorf("expected stored meta to be isolated from callers, got %v", doc.Containers[0].Meta)
+	}
+}
+
+func TestContainerController_CreateOrUpdateContainer_AnnotationLimits(t *testing.T) {
+	tests := []struct {
+		name  string
+		notes string
+		meta  map[string]string
+		want  int
+	}{
+		{"within limits", "short", map[string]string{"a": "1", "b": "2"}, http.StatusOK},
+		{"notes too long", strings.Repeat("x", 11), nil, http.StatusBadRequest},
+		{"too many meta keys", "", map[string]string{"a": "1", "b": "2", "c": "3"}, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := cache.NewStore(repository.DataDocument{})
+			cc := NewContainerController(context.Background(), store, &mockContainerRuntimeForContainer{})
+			cc.SetAnnotationLimits(10, 2)
+
+			r := gin.New()
+			r.POST("/container", cc.CreateOrUpdateContainer)
+
+			body, _ := json.Marshal(repository.Container{
+				Name: "c", FriendlyName: "c", URL: "http://c.local", Active: boolPtr(true), Notes: tt.notes, Meta: tt.meta,
+			})
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/container", bytes.NewReader(body)))
+			if w.Code != tt.want {
+				t.Errorf("expected status %d, got %d: %s", tt.want, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
 func TestContainerController_CreateOrUpdateContainer_InvalidPayload(t *testing.T) {
 	store := &mockContainerStore{}
 	cc := NewContainerController(context.Background(), store, &mockContainerRuntimeForContainer{})
@@ -187,6 +269,32 @@ func TestContainerController_CreateOrUpdateContainer_ValidationError(t *testing.
 	}
 }
 
+func TestContainerController_CreateOrUpdateContainer_InvalidHealthCheck(t *testing.T) {
+	store := &mockContainerStore{}
+	cc := NewContainerController(context.Background(), store, &mockContainerRuntimeForContainer{})
+
+	r := gin.New()
+	r.POST("/container", cc.CreateOrUpdateContainer)
+
+	checks := []map[string]any{
+		{"type": "exec"},
+		{"type": "udp"},
+		{"expectedStatus": []int{42}},
+	}
+	for _, check := range checks {
+		body, _ := json.Marshal(map[string]any{
+			"name": "test", "friendly_name": "Test", "url": "http://test.local", "active": true, "healthCheck": check,
+		})
+		req := httptest.NewRequest(http.MethodPost, "/container", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("health check %v: expected status 400, got %d", check, w.Code)
+		}
+	}
+}
+
 func TestContainerController_CreateOrUpdateContainer_StoreError(t *testing.T) {
 	store := &mockContainerStore{
 		addErr: errors.New("store error"),
@@ -297,6 +405,9 @@ func (m *mockRuntime) ListContainers(ctx context.Context) ([]string, error)  { r
 func (m *mockRuntime) Stats(ctx context.Context, containerName string) (runtime.ContainerStats, error) {
 	return runtime.ContainerStats{}, nil
 }
+func (m *mockRuntime) Ping(ctx context.Context) (runtime.PingResult, error) {
+	return runtime.PingResult{}, nil
+}
 
 func TestContainerController_Ready_MissingName(t *testing.T) {
 	store := &mockContainerStore{}
@@ -351,7 +462,7 @@ func TestContainerController_Ready_RuntimeErrorAndNotRunning(t *testing.T) {
 	if w.Code != http.StatusOK {
 		t.Errorf("expected status 200 on runtime error, got %d", w.Code)
 	}
-	var resp map[string]bool
+	var resp map[string]any
 	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
 		t.Fatalf("failed to unmarshal response: %v", err)
 	}
@@ -420,7 +531,7 @@ func TestContainerController_Ready_HTTPCheck(t *testing.T) {
 	if w.Code != http.StatusOK {
 		t.Errorf("expected status 200 for http check, got %d", w.Code)
 	}
-	var resp map[string]bool
+	var resp map[string]any
 	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
 		t.Fatalf("failed to unmarshal response: %v", err)
 	}
@@ -451,3 +562,872 @@ func TestContainerController_Ready_HTTPCheck(t *testing.T) {
 		t.Errorf("expected ready=false for http non-200, got %v", resp)
 	}
 }
+
+func TestContainerController_Ready_Reasons(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	// A closed server leaves a local address nobody listens on.
+	closed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	closedURL := closed.URL
+	closed.Close()
+
+	tests := []struct {
+		name       string
+		url        string
+		rt         *mockRuntime
+		wantStatus int
+		wantReason string
+	}{
+		{"runtime error", "http://c.local", &mockRuntime{err: errors.New("rt error")}, http.StatusOK, "runtime_error"},
+		{"not running", "http://c.local", &mockRuntime{running: false}, http.StatusOK, "not_running"},
+		{"no url", "", &mockRuntime{running: true}, http.StatusInternalServerError, "no_url"},
+		{"connection refused", closedURL, &mockRuntime{running: true}, http.StatusOK, "connection_refused"},
+		{"bad status", failing.URL, &mockRuntime{running: true}, http.StatusOK, "bad_status:500"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &mockContainerStore{doc: repository.DataDocument{Containers: []repository.Container{
+				{Name: "c", FriendlyName: "c", URL: tt.url, Active: boolPtr(true)},
+			}}}
+			cc := NewContainerController(context.Background(), store, tt.rt)
+
+			r := gin.New()
+			r.GET("/container/:name/ready", cc.Ready)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/container/c/ready", nil))
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			var resp map[string]any
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if resp["ready"] != false {
+				t.Errorf("expected ready=false, got %v", resp["ready"])
+			}
+			if resp["reason"] != tt.wantReason {
+				t.Errorf("expected reason %q, got %v", tt.wantReason, resp["reason"])
+			}
+		})
+	}
+}
+
+// execRuntime is a running mockRuntime whose exec health checks exit with exitCode.
+type execRuntime struct {
+	mockRuntime
+	exitCode int
+}
+
+func (m *execRuntime) Exec(_ context.Context, _ string, _ []string) (int, error) {
+	return m.exitCode, nil
+}
+
+func TestContainerController_Ready_HealthCheck(t *testing.T) {
+	health := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/app/healthz" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer health.Close()
+	closed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	closedURL := closed.URL
+	closed.Close()
+
+	tests := []struct {
+		name       string
+		url        string
+		check      *repository.HealthCheck
+		rt         runtime.ContainerRuntime
+		wantReady  bool
+		wantReason string
+	}{
+		{"http path and expected status", health.URL + "/app", &repository.HealthCheck{Path: "/healthz", ExpectedStatus: []int{204}}, &mockRuntime{running: true}, true, ""},
+		{"http unexpected status", health.URL + "/app", &repository.HealthCheck{Path: "/missing", ExpectedStatus: []int{204}}, &mockRuntime{running: true}, false, "bad_status:404"},
+		{"tcp open port", health.URL + "/ignored", &repository.HealthCheck{Type: repository.HealthCheckTCP}, &mockRuntime{running: true}, true, ""},
+		{"tcp closed port", closedURL, &repository.HealthCheck{Type: repository.HealthCheckTCP}, &mockRuntime{running: true}, false, "connection_refused"},
+		{"exec exit 0", "", &repository.HealthCheck{Type: repository.HealthCheckExec, Command: []string{"true"}}, &execRuntime{mockRuntime: mockRuntime{running: true}}, true, ""},
+		{"exec exit 1", "", &repository.HealthCheck{Type: repository.HealthCheckExec, Command: []string{"false"}}, &execRuntime{mockRuntime: mockRuntime{running: true}, exitCode: 1}, false, "exit_code:1"},
+		{"exec unsupported", "", &repository.HealthCheck{Type: repository.HealthCheckExec, Command: []string{"true"}}, &mockRuntime{running: true}, false, "exec_unsupported"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &mockContainerStore{doc: repository.DataDocument{Containers: []repository.Container{
+				{Name: "c", FriendlyName: "c", URL: tt.url, Active: boolPtr(true), HealthCheck: tt.check},
+			}}}
+			cc := NewContainerController(context.Background(), store, tt.rt)
+			cc.SetExecHealthChecks(true)
+
+			r := gin.New()
+			r.GET("/container/:name/ready", cc.Ready)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/container/c/ready", nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+			}
+			var resp map[string]any
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if resp["ready"] != tt.wantReady {
+				t.Errorf("expected ready=%v, got %v", tt.wantReady, resp)
+			}
+			if tt.wantReason != "" && resp["reason"] != tt.wantReason {
+				t.Errorf("expected reason %q, got %v", tt.wantReason, resp["reason"])
+			}
+		})
+	}
+}
+
+func TestContainerController_Ready_ExecHealthCheckDisabled(t *testing.T) {
+	health := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer health.Close()
+	check := &repository.HealthCheck{Type: repository.HealthCheckExec, Command: []string{"false"}}
+	store := &mockContainerStore{doc: repository.DataDocument{Containers: []repository.Container{
+		{Name: "c", FriendlyName: "c", URL: health.URL, Active: boolPtr(true), HealthCheck: check},
+	}}}
+	// The command would fail: without exec checks the URL is probed instead
+	cc := NewContainerController(context.Background(), store, &execRuntime{mockRuntime: mockRuntime{running: true}, exitCode: 1})
+
+	r := gin.New()
+	r.GET("/container/:name/ready", cc.Ready)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/container/c/ready", nil))
+	var resp map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp["ready"] != true {
+		t.Errorf("expected the URL probe to make c ready, got %v", resp)
+	}
+}
+
+func TestContainerController_Ready_ExternalChecker(t *testing.T) {
+	checker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("name") {
+		case "up":
+			_, _ = w.Write([]byte(`{"ready":true}`))
+		case "down":
+			_, _ = w.Write([]byte(`{"ready":false}`))
+		case "slow":
+			time.Sleep(200 * time.Millisecond)
+			_, _ = w.Write([]byte(`{"ready":true}`))
+		default:
+			_, _ = w.Write([]byte(`not json`))
+		}
+	}))
+	defer checker.Close()
+
+	tests := []struct {
+		name       string
+		checkerURL string
+		wantReady  bool
+		wantReason string
+	}{
+		{"up", checker.URL, true, ""},
+		{"down", checker.URL, false, "external_not_ready"},
+		{"slow", checker.URL, false, "timeout"},
+		{"garbled", checker.URL, false, "external_invalid_response"},
+		{"up", "", false, "external_not_configured"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+"/"+tt.wantReason, func(t *testing.T) {
+			// No URL: readiness comes from the external checker only
+			store := &mockContainerStore{doc: repository.DataDocument{Containers: []repository.Container{
+				{Name: tt.name, FriendlyName: tt.name, ReadyCheckType: repository.ReadyCheckExternal, Active: boolPtr(true)},
+			}}}
+			cc := NewContainerController(context.Background(), store, &mockRuntime{running: true})
+			cc.SetExternalReadyChecker(tt.checkerURL, 50*time.Millisecond)
+
+			r := gin.New()
+			r.GET("/container/:name/ready", cc.Ready)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/container/"+tt.name+"/ready", nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d", w.Code)
+			}
+			var resp map[string]any
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if resp["ready"] != tt.wantReady {
+				t.Errorf("expected ready=%v, got %v", tt.wantReady, resp["ready"])
+			}
+			if tt.wantReason != "" && resp["reason"] != tt.wantReason {
+				t.Errorf("expected reason %q, got %v", tt.wantReason, resp["reason"])
+			}
+		})
+	}
+}
+
+func TestContainerController_Ready_ReportsLastError(t *testing.T) {
+	rt := newMockRuntime()
+	store := cache.NewStore(repository.DataDocument{
+		Containers: []repository.Container{
+			{Name: "slow", FriendlyName: "slow", URL: "http://slow.local", Active: boolPtr(true), LastError: "startup timeout"},
+		},
+	})
+	cc := NewContainerController(context.Background(), store, rt)
+
+	r := gin.New()
+	r.GET("/container/:name/ready", cc.Ready)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/container/slow/ready", nil))
+
+	var resp map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp["ready"] != false || resp["error"] != "startup timeout" {
+		t.Errorf("expected not ready with startup timeout error, got %v", resp)
+	}
+}
+
+func TestContainerController_Ready_MultipleURLs(t *testing.T) {
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer up.Close()
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+
+	tests := []struct {
+		name      string
+		mode      string
+		wantReady bool
+	}{
+		{"any with one up is ready", repository.ReadyModeAny, true},
+		{"all with one down is not ready", repository.ReadyModeAll, false},
+		{"default mode is all", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			active := true
+			store := &mockContainerStore{doc: repository.DataDocument{Containers: []repository.Container{
+				{Name: "multi", FriendlyName: "multi", URL: up.URL, ReadyURLs: []string{down.URL}, ReadyMode: tt.mode, Active: &active},
+			}}}
+			cc := NewContainerController(context.Background(), store, &mockRuntime{running: true})
+
+			r := gin.New()
+			r.GET("/container/:name/ready", cc.Ready)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/container/multi/ready", nil))
+
+			var resp map[string]any
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if resp["ready"] != tt.wantReady {
+				t.Errorf("expected ready=%v, got %v", tt.wantReady, resp)
+			}
+		})
+	}
+}
+
+func TestContainerController_Ready_OnlyReadyURLs(t *testing.T) {
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer up.Close()
+
+	active := true
+	store := &mockContainerStore{doc: repository.DataDocument{Containers: []repository.Container{
+		{Name: "c5", FriendlyName: "C5", ReadyURLs: []string{up.URL}, Active: &active},
+	}}}
+	cc := NewContainerController(context.Background(), store, &mockRuntime{running: true})
+
+	r := gin.New()
+	r.GET("/container/:name/ready", cc.Ready)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/container/c5/ready", nil))
+
+	var resp map[string]bool
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if !resp["ready"] {
+		t.Errorf("expected ready=true when only readyUrls are set, got %v", resp)
+	}
+}
+
+func TestContainerController_Ready_Group(t *testing.T) {
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer up.Close()
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+
+	tests := []struct {
+		name         string
+		mode         string
+		wantReady    bool
+		wantRedirect string
+	}{
+		{"all with one member down is not ready", "", false, ""},
+		{"any redirects to the first ready member", repository.ReadyModeAny, true, up.URL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &mockContainerStore{doc: repository.DataDocument{
+				Containers: []repository.Container{
+					{Name: "api", FriendlyName: "api", URL: down.URL, Active: boolPtr(true)},
+					{Name: "web", FriendlyName: "web", URL: up.URL, Active: boolPtr(true)},
+					{Name: "off", FriendlyName: "off", URL: down.URL, Active: boolPtr(false)},
+				},
+				Groups: []repository.Group{{Name: "stack", Container: []string{"api", "web", "off"}, Active: boolPtr(true), ReadyMode: tt.mode}},
+			}}
+			cc := NewContainerController(context.Background(), store, &mockRuntime{running: true})
+
+			r := gin.New()
+			r.GET("/:name/ready", cc.Ready)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stack/ready", nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+			}
+			var resp GroupReadyResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if resp.Ready != tt.wantReady || resp.RedirectURL != tt.wantRedirect {
+				t.Errorf("expected ready=%v redirect=%q, got %+v", tt.wantReady, tt.wantRedirect, resp)
+			}
+			if len(resp.Containers) != 2 || resp.Containers[0].Reason != "bad_status:503" || !resp.Containers[1].Ready {
+				t.Errorf("expected api not ready and web ready (inactive member skipped), got %+v", resp.Containers)
+			}
+		})
+	}
+}
+
+func TestContainerController_GetContainer(t *testing.T) {
+	since := int64(1700000000000)
+	store := &mockContainerStore{doc: repository.DataDocument{Containers: []repository.Container{
+		{Name: "web", FriendlyName: "web", URL: "http://web.local", Active: boolPtr(true), RunningSince: &since},
+	}}}
+	cc := NewContainerController(context.Background(), store, &mockRuntime{running: true})
+
+	r := gin.New()
+	r.GET("/container/:name", cc.GetContainer)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/container/web", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	var got repository.Container
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if got.RunningSince == nil || *got.RunningSince != since {
+		t.Errorf("expected runningSince %d, got %v", since, got.RunningSince)
+	}
+	if got.Running == nil || !*got.Running {
+		t.Error("expected live running state true")
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/container/missing", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", w.Code)
+	}
+}
+
+func TestContainerController_GetContainer_DependencyNotReady(t *testing.T) {
+	db := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer db.Close()
+
+	store := &mockContainerStore{doc: repository.DataDocument{Containers: []repository.Container{
+		{Name: "app", FriendlyName: "app", URL: "http://app.local", Active: boolPtr(true), DependsOn: []string{"db"}},
+		{Name: "db", FriendlyName: "db", URL: db.URL, Active: boolPtr(true)},
+	}}}
+	cc := NewContainerController(context.Background(), store, &mockRuntime{running: true})
+
+	r := gin.New()
+	r.GET("/container/:name", cc.GetContainer)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/container/app", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	var got ContainerDetailResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if got.Name != "app" {
+		t.Errorf("expected container app, got %q", got.Name)
+	}
+	if len(got.Dependencies) != 1 {
+		t.Fatalf("expected 1 dependency, got %d", len(got.Dependencies))
+	}
+	dep := got.Dependencies[0]
+	if dep.Name != "db" {
+		t.Errorf("expected dependency db, got %q", dep.Name)
+	}
+	if !dep.Running {
+		t.Error("expected dependency to be reported as running")
+	}
+	if dep.Ready {
+		t.Error("expected dependency to be reported as not ready")
+	}
+	if dep.Reason != "bad_status:503" {
+		t.Errorf("expected reason bad_status:503, got %q", dep.Reason)
+	}
+}
+
+func newLabeledContainerStore() *cache.Store {
+	return cache.NewStore(repository.DataDocument{
+		Containers: []repository.Container{
+			{Name: "api-staging", FriendlyName: "api", URL: "http://api", Active: boolPtr(true), Labels: map[string]string{"env": "staging"}},
+			{Name: "db-staging", FriendlyName: "db", URL: "http://db", Active: boolPtr(true), Labels: map[string]string{"env": "staging", "tier": "data"}},
+			{Name: "api-prod", FriendlyName: "api", URL: "http://api", Active: boolPtr(true), Labels: map[string]string{"env": "prod"}},
+			{Name: "tools", FriendlyName: "tools", URL: "http://tools", Active: boolPtr(true)},
+		},
+	})
+}
+
+func TestContainerController_SetActive_LabeledSubset(t *testing.T) {
+	store := newLabeledContainerStore()
+	cc := NewContainerController(context.Background(), store, &mockContainerRuntimeForContainer{})
+
+	r := gin.New()
+	r.POST("/containers/active", cc.SetActive)
+
+	body := `{"active":false,"filter":{"label":"env=staging"}}`
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/containers/active", bytes.NewBufferString(body)))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp SetActiveResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(resp.Affected) != 2 || resp.Affected[0] != "api-staging" || resp.Affected[1] != "db-staging" {
+		t.Errorf("expected staging containers to be affected, got %v", resp.Affected)
+	}
+
+	doc, _ := store.Snapshot()
+	want := map[string]bool{"api-staging": false, "db-staging": false, "api-prod": true, "tools": true}
+	for _, c := range doc.Containers {
+		if *c.Active != want[c.Name] {
+			t.Errorf("container %s: expected active=%v, got %v", c.Name, want[c.Name], *c.Active)
+		}
+	}
+	if !store.IsDirty() {
+		t.Error("expected store to be dirty after bulk update")
+	}
+
+	// Repeating the request changes nothing.
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/containers/active", bytes.NewBufferString(body)))
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(resp.Affected) != 0 {
+		t.Errorf("expected no affected containers on repeat, got %v", resp.Affected)
+	}
+}
+
+func TestContainerController_SetActive_InvalidRequests(t *testing.T) {
+	tests := []struct {
+		name   string
+		store  cache.ContainerStore
+		body   string
+		status int
+	}{
+		{"missing active", newLabeledContainerStore(), `{"filter":{"label":"env=staging"}}`, http.StatusBadRequest},
+		{"missing filter", newLabeledContainerStore(), `{"active":false}`, http.StatusBadRequest},
+		{"invalid json", newLabeledContainerStore(), `{`, http.StatusBadRequest},
+		{"store without transactions", &mockContainerStore{}, `{"active":false,"filter":{"label":"env"}}`, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cc := NewContainerController(context.Background(), tt.store, &mockContainerRuntimeForContainer{})
+			r := gin.New()
+			r.POST("/containers/active", cc.SetActive)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/containers/active", bytes.NewBufferString(tt.body)))
+			if w.Code != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, w.Code)
+			}
+		})
+	}
+}
+
+func TestContainerController_PatchContainer(t *testing.T) {
+	store := newLabeledContainerStore()
+	cc := NewContainerController(context.Background(), store, &mockContainerRuntimeForContainer{})
+	r := gin.New()
+	r.PATCH("/container/:name", cc.PatchContainer)
+
+	body := `{"active":false,"friendly_name":"DB Staging","labels":{"tier":null,"owner":"ops"}}`
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPatch, "/container/db-staging", bytes.NewBufferString(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var patched repository.Container
+	if err := json.Unmarshal(w.Body.Bytes(), &patched); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if *patched.Active || patched.FriendlyName != "db staging" || patched.URL != "http://db" {
+		t.Errorf("expected only active and friendly_name changed, got %+v", patched)
+	}
+	if want := map[string]string{"env": "staging", "owner": "ops"}; !reflect.DeepEqual(patched.Labels, want) {
+		t.Errorf("expected labels merged to %v, got %v", want, patched.Labels)
+	}
+
+	doc, _ := store.Snapshot()
+	if *doc.Containers[1].Active || !*doc.Containers[0].Active {
+		t.Error("expected only db-staging deactivated in the store")
+	}
+	if !store.IsDirty() {
+		t.Error("expected store to be dirty after patch")
+	}
+}
+
+// TestContainerController_PatchContainer_KeepsFieldsOutsideThePatch patches a container with the
+// fields of the UI editor and checks every other field survives. The fixture sets every field, so
+// a new Container field fails the test until it is added here.
+func TestContainerController_PatchContainer_KeepsFieldsOutsideThePatch(t *testing.T) {
+	since, activatedAt, timeout, cooldown := int64(1000), int64(500), 60, 30
+	stored := repository.Container{
+		Name: "nas", FriendlyName: "nas", URL: "http://nas", Running: boolPtr(true), Active: boolPtr(true),
+		ActivatedAt: &activatedAt, Labels: map[string]string{"env": "home"}, DependsOn: []string{"db"},
+		RunningSince: &since, StartupTimeoutSecs: &timeout, Priority: 5, RestartCooldownSecs: &cooldown,
+		ReadyURLs: []string{"http://nas/health"}, ReadyMode: "any", ReadyCheckType: "http",
+		HealthCheck:    &repository.HealthCheck{Type: repository.HealthCheckExec, Command: []string{"true"}},
+		WaitingMessage: "waking the nas", WaitingPage: &repository.WaitingPage{AccentColor: "#ff6600"},
+		CommandOverride: []string{"serve"}, ExecCommands: map[string][]string{"sync": {"sync"}},
+		PreStop: &repository.PreStopHook{Command: []string{"sync"}}, Notes: "attic", Meta: map[string]string{"owner": "ops"},
+		Hidden: boolPtr(true), AllowedUsers: []string{"bob"}, Discovered: true, Host: "remote", RuntimeType: "wol",
+		WakeOnLAN: &repository.WakeOnLAN{MAC: "00:11:22:33:44:55", ProbeAddress: "nas:22"}, LastError: "startup timeout",
+	}
+	fields := reflect.ValueOf(stored)
+	for i := 0; i < fields.NumField(); i++ {
+		if fields.Field(i).IsZero() {
+			t.Fatalf("set %s in the fixture", fields.Type().Field(i).Name)
+		}
+	}
+	store := cache.NewStore(repository.DataDocument{Containers: []repository.Container{stored}})
+	cc := NewContainerController(context.Background(), store, &mockContainerRuntimeForContainer{})
+	r := gin.New()
+	r.PATCH("/container/:name", cc.PatchContainer)
+
+	body := `{"friendly_name":"NAS","url":"http://nas.local","active":true}`
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPatch, "/container/nas", bytes.NewBufferString(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	want := stored
+	want.URL = "http://nas.local"
+	doc, _ := store.Snapshot()
+	if !reflect.DeepEqual(doc.Containers[0], want) {
+		t.Errorf("expected only the url changed\n got %+v\nwant %+v", doc.Containers[0], want)
+	}
+}
+
+func TestContainerController_PatchContainer_InvalidRequests(t *testing.T) {
+	tests := []struct {
+		name      string
+		container string
+		body      string
+		status    int
+	}{
+		{"unknown container", "missing", `{"active":false}`, http.StatusNotFound},
+		{"not an object", "tools", `[1]`, http.StatusBadRequest},
+		{"invalid json", "tools", `{`, http.StatusBadRequest},
+		{"rename", "tools", `{"name":"toolbox"}`, http.StatusBadRequest},
+		{"wrong type", "tools", `{"active":"yes"}`, http.StatusBadRequest},
+		{"required field removed", "tools", `{"url":null}`, http.StatusBadRequest},
+		{"invalid url", "tools", `{"url":"not a url"}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newLabeledContainerStore()
+			cc := NewContainerController(context.Background(), store, &mockContainerRuntimeForContainer{})
+			r := gin.New()
+			r.PATCH("/container/:name", cc.PatchContainer)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodPatch, "/container/"+tt.container, bytes.NewBufferString(tt.body)))
+			if w.Code != tt.status {
+				t.Errorf("expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			if store.IsDirty() {
+				t.Error("expected a rejected patch to leave the store untouched")
+			}
+		})
+	}
+}
+
+func TestContainerController_RenameContainer(t *testing.T) {
+	store := cache.NewStore(repository.DataDocument{
+		Containers: []repository.Container{
+			{Name: "db", FriendlyName: "db", URL: "http://db", Active: boolPtr(true)},
+			{Name: "api", FriendlyName: "api", URL: "http://api", Active: boolPtr(true), DependsOn: []string{"db"}},
+		},
+		Order:  []string{"api", "db"},
+		Groups: []repository.Group{{Name: "stack", Container: []string{"api", "db"}, Active: boolPtr(true)}},
+		Schedules: []repository.Schedule{
+			{ID: "s1", Target: "db", TargetType: "container"},
+			{ID: "s2", Target: "stack", TargetType: "group"},
+		},
+	})
+	cc := NewContainerController(context.Background(), store, &mockContainerRuntimeForContainer{})
+	r := gin.New()
+	r.POST("/container/:name/rename", cc.RenameContainer)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/container/db/rename", bytes.NewBufferString(`{"name":"postgres"}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp RenameResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	want := RenameResponse{PreviousName: "db", Name: "postgres", RenameReport: cache.RenameReport{Groups: []string{"stack"}, Schedules: []string{"s1"}, Dependents: []string{"api"}}}
+	if !reflect.DeepEqual(resp, want) {
+		t.Errorf("expected %+v, got %+v", want, resp)
+	}
+
+	doc, _ := store.Snapshot()
+	if doc.Containers[0].Name != "postgres" || !reflect.DeepEqual(doc.Containers[1].DependsOn, []string{"postgres"}) {
+		t.Errorf("expected the container and its dependents renamed, got %+v", doc.Containers)
+	}
+	if !reflect.DeepEqual(doc.Order, []string{"api", "postgres"}) || !reflect.DeepEqual(doc.Groups[0].Container, []string{"api", "postgres"}) {
+		t.Errorf("expected order and group members renamed, got %v and %v", doc.Order, doc.Groups[0].Container)
+	}
+	if doc.Schedules[0].Target != "postgres" || doc.Schedules[1].Target != "stack" {
+		t.Errorf("expected only the container schedule retargeted, got %+v", doc.Schedules)
+	}
+}
+
+func TestContainerController_RenameContainer_InvalidRequests(t *testing.T) {
+	tests := []struct {
+		name      string
+		container string
+		body      string
+		status    int
+	}{
+		{"unknown container", "missing", `{"name":"other"}`, http.StatusNotFound},
+		{"existing name", "tools", `{"name":"api-prod"}`, http.StatusConflict},
+		{"missing name", "tools", `{}`, http.StatusBadRequest},
+		{"blank name", "tools", `{"name":" "}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newLabeledContainerStore()
+			cc := NewContainerController(context.Background(), store, &mockContainerRuntimeForContainer{})
+			r := gin.New()
+			r.POST("/container/:name/rename", cc.RenameContainer)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/container/"+tt.container+"/rename", bytes.NewBufferString(tt.body)))
+			if w.Code != tt.status {
+				t.Errorf("expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			if store.IsDirty() {
+				t.Error("expected a rejected rename to leave the store untouched")
+			}
+		})
+	}
+}
+
+func TestContainerController_AllContainers_LabelFilter(t *testing.T) {
+	cc := NewContainerController(context.Background(), newLabeledContainerStore(), &mockContainerRuntimeForContainer{})
+	r := gin.New()
+	r.GET("/containers", cc.AllContainers)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/containers?label=tier", nil))
+
+	var items []repository.Container
+	if err := json.Unmarshal(w.Body.Bytes(), &items); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(items) != 1 || items[0].Name != "db-staging" {
+		t.Errorf("expected only db-staging, got %+v", items)
+	}
+}
+
+func TestContainerController_AllContainers_Hidden(t *testing.T) {
+	store := &mockContainerStore{doc: repository.DataDocument{Containers: []repository.Container{
+		{Name: "app", FriendlyName: "app", URL: "http://app", Active: boolPtr(true)},
+		{Name: "sidecar", FriendlyName: "sidecar", URL: "http://sidecar", Active: boolPtr(true), Hidden: boolPtr(true)},
+	}}}
+	cc := NewContainerController(context.Background(), store, &mockContainerRuntimeForContainer{})
+	r := gin.New()
+	r.GET("/containers", cc.AllContainers)
+
+	list := func(target string) []string {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: expected status 200, got %d", target, w.Code)
+		}
+		var items []repository.Container
+		if err := json.Unmarshal(w.Body.Bytes(), &items); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		names := make([]string, 0, len(items))
+		for _, item := range items {
+			names = append(names, item.Name)
+		}
+		return names
+	}
+
+	if got := list("/containers"); len(got) != 1 || got[0] != "app" {
+		t.Errorf("expected hidden sidecar excluded by default, got %v", got)
+	}
+	if got := list("/containers?includeHidden=true"); len(got) != 2 {
+		t.Errorf("expected hidden sidecar included with includeHidden, got %v", got)
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/containers?includeHidden=maybe", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for invalid includeHidden, got %d", w.Code)
+	}
+}
+
+func TestContainerController_Ready_CachesPositiveResult(t *testing.T) {
+	var hits atomic.Int64
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	store := &mockContainerStore{doc: repository.DataDocument{Containers: []repository.Container{{Name: "c1", FriendlyName: "c1", URL: ts.URL, Active: boolPtr(true)}}}}
+	cc := NewContainerController(context.Background(), store, &mockRuntime{running: true})
+	cc.SetReadinessCache(time.Minute, time.Second)
+	now := time.Now()
+	cc.readiness.now = func() time.Time { return now }
+
+	r := gin.New()
+	r.GET("/container/:name/ready", cc.Ready)
+	ready := func() bool {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/container/c1/ready", nil))
+		var resp map[string]any
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		return resp["ready"] == true
+	}
+
+	if !ready() || !ready() {
+		t.Fatal("expected container to be ready")
+	}
+	if got := hits.Load(); got != 1 {
+		t.Errorf("expected a single probe within the TTL, got %d", got)
+	}
+
+	now = now.Add(time.Minute)
+	if !ready() {
+		t.Fatal("expected container to be ready")
+	}
+	if got := hits.Load(); got != 2 {
+		t.Errorf("expected a new probe after the TTL, got %d probes", got)
+	}
+}
+
+func TestContainerController_Ready_NegativeResultReprobesSooner(t *testing.T) {
+	var hits atomic.Int64
+	var up atomic.Bool
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if up.Load() {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	store := &mockContainerStore{doc: repository.DataDocument{Containers: []repository.Container{{Name: "c1", FriendlyName: "c1", URL: ts.URL, Active: boolPtr(true)}}}}
+	cc := NewContainerController(context.Background(), store, &mockRuntime{running: true})
+	cc.SetReadinessCache(time.Minute, time.Second)
+	now := time.Now()
+	cc.readiness.now = func() time.Time { return now }
+
+	r := gin.New()
+	r.GET("/container/:name/ready", cc.Ready)
+	ready := func() bool {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/container/c1/ready", nil))
+		var resp map[string]any
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		return resp["ready"] == true
+	}
+
+	if ready() || ready() {
+		t.Fatal("expected container not to be ready")
+	}
+	if got := hits.Load(); got != 1 {
+		t.Errorf("expected the negative result to be cached briefly, got %d probes", got)
+	}
+
+	// Well before the positive TTL, the short negative TTL lets recovery be detected.
+	up.Store(true)
+	now = now.Add(2 * time.Second)
+	if !ready() {
+		t.Error("expected recovery to be detected after the negative TTL")
+	}
+	if got := hits.Load(); got != 2 {
+		t.Errorf("expected a re-probe after the negative TTL, got %d probes", got)
+	}
+}
+
+func TestContainerController_ReadinessCache_Prune(t *testing.T) {
+	cc := NewContainerController(context.Background(), &mockContainerStore{}, &mockRuntime{})
+	if cc.ReadinessCache() != nil {
+		t.Fatal("expected no readiness cache when caching is disabled")
+	}
+
+	cc.SetReadinessCache(time.Minute, time.Second)
+	now := time.Now()
+	cc.readiness.now = func() time.Time { return now }
+	cc.readiness.put("ready", true, "")
+	cc.readiness.put("not-ready", false, reasonUnreachable)
+
+	pruner := cc.ReadinessCache()
+	if removed := pruner.Prune(now.Add(2 * time.Second)); removed != 1 {
+		t.Errorf("expected the expired negative result pruned, got %d removed", removed)
+	}
+	if pruner.Len() != 1 {
+		t.Errorf("expected 1 cached result left, got %d", pruner.Len())
+	}
+}
@@ -2,11 +2,18 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"unicode/utf8"
 
+	"github.com/bassista/go_spin/internal/api/apierror"
 	"github.com/bassista/go_spin/internal/cache"
 	"github.com/bassista/go_spin/internal/repository"
 	"github.com/bassista/go_spin/internal/runtime"
+	"github.com/bassista/go_spin/internal/waiting"
 
+	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 )
 
@@ -15,10 +22,16 @@ type ContainerCrudService struct {
 	Store   cache.ContainerStore
 	Runtime runtime.ContainerRuntime
 	Ctx     context.Context
+	// Reader optionally serves All from a read replica; nil reads from Store.
+	Reader cache.ReadOnlyStore
 }
 
 func (s *ContainerCrudService) All() ([]repository.Container, error) {
-	doc, err := s.Store.Snapshot()
+	reader := cache.ReadOnlyStore(s.Store)
+	if s.Reader != nil {
+		reader = s.Reader
+	}
+	doc, err := reader.Snapshot()
 	if err != nil {
 		return nil, err
 	}
@@ -46,6 +59,28 @@ func (s *ContainerCrudService) Add(item repository.Container) ([]repository.Cont
 	return doc.Containers, nil
 }
 
+// Authorize rejects the upserts changing the commands run inside the container without an
+// authenticated admin.
+func (s *ContainerCrudService) Authorize(c *gin.Context, item repository.Container) bool {
+	doc, err := s.Store.Snapshot()
+	if err != nil {
+		apierror.Respond(c, http.StatusInternalServerError, "failed to read container list")
+		return false
+	}
+	var existing *repository.Container
+	for i := range doc.Containers {
+		if doc.Containers[i].Name == item.Name {
+			existing = &doc.Containers[i]
+			break
+		}
+	}
+	if changed := changedCommandFields(existing, item); !mayChangeCommandFields(c, changed) {
+		respondCommandFieldsForbidden(c, changed)
+		return false
+	}
+	return true
+}
+
 func (s *ContainerCrudService) Remove(name string) ([]repository.Container, error) {
 	doc, err := s.Store.RemoveContainer(name)
 	if err != nil {
@@ -56,9 +91,37 @@ func (s *ContainerCrudService) Remove(name string) ([]repository.Container, erro
 
 // ContainerCrudValidator implements CrudValidator for containers.
 type ContainerCrudValidator struct {
-	validator *validator.Validate
+	validator      *validator.Validate
+	maxNotesLength int // maximum Notes length in characters, 0 means unlimited
+	maxMetaKeys    int // maximum number of Meta entries, 0 means unlimited
 }
 
 func (v *ContainerCrudValidator) Validate(item repository.Container) error {
-	return v.validator.Struct(item)
+	if err := v.validator.Struct(item); err != nil {
+		return err
+	}
+	if v.maxNotesLength > 0 && utf8.RuneCountInString(item.Notes) > v.maxNotesLength {
+		return fmt.Errorf("notes exceed %d characters", v.maxNotesLength)
+	}
+	if v.maxMetaKeys > 0 && len(item.Meta) > v.maxMetaKeys {
+		return fmt.Errorf("meta exceeds %d keys", v.maxMetaKeys)
+	}
+	return validateWaitingPage(item.WaitingPage)
+}
+
+// validateWaitingPage checks the inline template of a waiting page customization like the global
+// one, and that its template file stays in the templates directory.
+func validateWaitingPage(page *repository.WaitingPage) error {
+	if page == nil {
+		return nil
+	}
+	if page.Template != "" {
+		if err := waiting.Validate(page.Template); err != nil {
+			return fmt.Errorf("waitingPage.template: %w", err)
+		}
+	}
+	if page.TemplatePath != "" && !filepath.IsLocal(page.TemplatePath) {
+		return fmt.Errorf("waitingPage.templatePath must be a relative path inside the templates directory")
+	}
+	return nil
 }
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http"
 
+	"github.com/bassista/go_spin/internal/api/apierror"
 	"github.com/bassista/go_spin/internal/cache"
 	"github.com/gin-gonic/gin"
 )
@@ -15,6 +16,18 @@ type CrudService[T any] interface {
 	Remove(name string) ([]T, error)
 }
 
+// CrudForceAdder is implemented by services whose upserts can skip the reference checks
+// (?force=true).
+type CrudForceAdder[T any] interface {
+	AddForce(item T) ([]T, error)
+}
+
+// CrudAuthorizer is implemented by services restricting who may upsert an item: Authorize answers
+// the request and returns false when it may not.
+type CrudAuthorizer[T any] interface {
+	Authorize(c *gin.Context, item T) bool
+}
+
 // CrudValidator defines the interface for validating a resource.
 type CrudValidator[T any] interface {
 	Validate(item T) error
@@ -37,7 +50,7 @@ func (cc *CrudController[T]) RegisterCrudRoutes(rg *gin.RouterGroup, resource st
 func (cc *CrudController[T]) GetAll(c *gin.Context) {
 	items, err := cc.Service.All()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read resource list"})
+		apierror.Respond(c, http.StatusInternalServerError, "failed to read resource list")
 		return
 	}
 	c.JSON(http.StatusOK, items)
@@ -47,18 +60,33 @@ func (cc *CrudController[T]) GetAll(c *gin.Context) {
 func (cc *CrudController[T]) CreateOrUpdate(c *gin.Context) {
 	var item T
 	if err := c.ShouldBindJSON(&item); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+		apierror.Respond(c, http.StatusBadRequest, "invalid payload")
 		return
 	}
 	if cc.Validator != nil {
 		if err := cc.Validator.Validate(item); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			apierror.RespondError(c, http.StatusBadRequest, err)
 			return
 		}
 	}
-	items, err := cc.Service.Add(item)
+	if authorizer, isAuthorizer := cc.Service.(CrudAuthorizer[T]); isAuthorizer && !authorizer.Authorize(c, item) {
+		return
+	}
+	force, ok := boolQuery(c, "force")
+	if !ok {
+		return
+	}
+	add := cc.Service.Add
+	if adder, isForceAdder := cc.Service.(CrudForceAdder[T]); force && isForceAdder {
+		add = adder.AddForce
+	}
+	items, err := add(item)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update resource"})
+		if errors.Is(err, cache.ErrDanglingReference) {
+			apierror.RespondError(c, http.StatusUnprocessableEntity, err)
+			return
+		}
+		apierror.Respond(c, http.StatusInternalServerError, "failed to update resource")
 		return
 	}
 	c.JSON(http.StatusOK, items)
@@ -68,19 +96,18 @@ func (cc *CrudController[T]) CreateOrUpdate(c *gin.Context) {
 func (cc *CrudController[T]) Delete(c *gin.Context) {
 	name := c.Param("name")
 	if name == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "missing resource name"})
+		apierror.Respond(c, http.StatusBadRequest, "missing resource name")
 		return
 	}
 	items, err := cc.Service.Remove(name)
 	if err != nil {
-		// Check for specific "not found" errors
 		if errors.Is(err, cache.ErrContainerNotFound) ||
 			errors.Is(err, cache.ErrGroupNotFound) ||
 			errors.Is(err, cache.ErrScheduleNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "resource not found"})
+			apierror.RespondError(c, http.StatusNotFound, err)
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete resource"})
+		apierror.Respond(c, http.StatusInternalServerError, "failed to delete resource")
 		return
 	}
 	c.JSON(http.StatusOK, items)
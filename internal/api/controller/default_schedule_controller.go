@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/bassista/go_spin/internal/api/apierror"
+	"github.com/bassista/go_spin/internal/cache"
+	"github.com/bassista/go_spin/internal/logger"
+	"github.com/bassista/go_spin/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// DefaultScheduleController manages the schedule followed by containers without their own.
+type DefaultScheduleController struct {
+	store     cache.ReadOnlyStore
+	validator *validator.Validate
+}
+
+// NewDefaultScheduleController creates a new DefaultScheduleController.
+func NewDefaultScheduleController(store cache.ReadOnlyStore) *DefaultScheduleController {
+	return &DefaultScheduleController{store: store, validator: validator.New()}
+}
+
+// GetDefaultSchedule handles GET /default-schedule - returns the default schedule, 404 when none is set.
+func (dc *DefaultScheduleController) GetDefaultSchedule(c *gin.Context) {
+	logger.WithComponent("schedule-controller").Debugf("GET /default-schedule handler called")
+	doc, err := dc.store.Snapshot()
+	if err != nil {
+		logger.WithComponent("schedule-controller").Errorf("get default schedule: failed to read snapshot: %v", err)
+		apierror.Respond(c, http.StatusInternalServerError, "failed to read schedules")
+		return
+	}
+	if doc.DefaultSchedule == nil {
+		apierror.Respond(c, http.StatusNotFound, "default schedule not set")
+		return
+	}
+	c.JSON(http.StatusOK, doc.DefaultSchedule)
+}
+
+// PutDefaultSchedule handles PUT /default-schedule - replaces the default schedule ({"timers":[...]}).
+// An empty timer list disables it.
+func (dc *DefaultScheduleController) PutDefaultSchedule(c *gin.Context) {
+	logger.WithComponent("schedule-controller").Debugf("PUT /default-schedule handler called")
+
+	var payload repository.DefaultSchedule
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "invalid payload")
+		return
+	}
+	if err := dc.validator.Struct(payload); err != nil {
+		apierror.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+	if err := validateTimers(payload.Timers); err != nil {
+		apierror.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	store, ok := dc.store.(cache.TransactionalStore)
+	if !ok {
+		logger.WithComponent("schedule-controller").Errorf("put default schedule: store does not support transactions")
+		apierror.Respond(c, http.StatusInternalServerError, "default schedule not supported")
+		return
+	}
+	doc, err := store.Apply(func(doc *repository.DataDocument) error {
+		if len(payload.Timers) == 0 {
+			doc.DefaultSchedule = nil
+			return nil
+		}
+		doc.DefaultSchedule = &payload
+		return nil
+	})
+	if err != nil {
+		logger.WithComponent("schedule-controller").Errorf("put default schedule: cache error: %v", err)
+		apierror.Respond(c, http.StatusInternalServerError, "failed to update cache")
+		return
+	}
+
+	if doc.DefaultSchedule == nil {
+		logger.WithComponent("schedule-controller").Infof("default schedule cleared")
+		c.Status(http.StatusNoContent)
+		return
+	}
+	logger.WithComponent("schedule-controller").Infof("default schedule set with %d timers", len(doc.DefaultSchedule.Timers))
+	c.JSON(http.StatusOK, doc.DefaultSchedule)
+}
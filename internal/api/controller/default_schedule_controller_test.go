@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bassista/go_spin/internal/cache"
+	"github.com/bassista/go_spin/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+func newDefaultScheduleRouter(store *cache.Store) *gin.Engine {
+	dc := NewDefaultScheduleController(store)
+	r := gin.New()
+	r.GET("/default-schedule", dc.GetDefaultSchedule)
+	r.PUT("/default-schedule", dc.PutDefaultSchedule)
+	return r
+}
+
+func TestDefaultScheduleController_PutAndGet(t *testing.T) {
+	store := cache.NewStore(repository.DataDocument{})
+	r := newDefaultScheduleRouter(store)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/default-schedule", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 before a default schedule is set, got %d", w.Code)
+	}
+
+	body := `{"timers":[{"startTime":"08:00","stopTime":"18:00","days":[1,2,3,4,5],"active":true}]}`
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/default-schedule", bytes.NewBufferString(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	doc, _ := store.Snapshot()
+	if doc.DefaultSchedule == nil || len(doc.DefaultSchedule.Timers) != 1 || doc.DefaultSchedule.Timers[0].StartTime != "08:00" {
+		t.Fatalf("expected the default schedule to be stored, got %+v", doc.DefaultSchedule)
+	}
+	if !store.IsDirty() {
+		t.Error("expected store to be dirty after setting the default schedule")
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/default-schedule", nil))
+	var got repository.DefaultSchedule
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(got.Timers) != 1 || got.Timers[0].StopTime != "18:00" {
+		t.Errorf("expected stored timers, got %+v", got)
+	}
+
+	// An empty timer list clears it.
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/default-schedule", bytes.NewBufferString(`{"timers":[]}`)))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204 when clearing, got %d", w.Code)
+	}
+	doc, _ = store.Snapshot()
+	if doc.DefaultSchedule != nil {
+		t.Errorf("expected default schedule to be cleared, got %+v", doc.DefaultSchedule)
+	}
+}
+
+func TestDefaultScheduleController_PutInvalidTimer(t *testing.T) {
+	store := cache.NewStore(repository.DataDocument{})
+	r := newDefaultScheduleRouter(store)
+
+	body := `{"timers":[{"startTime":"08:00","days":[9],"active":true}]}`
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/default-schedule", bytes.NewBufferString(body)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an invalid timer, got %d", w.Code)
+	}
+}
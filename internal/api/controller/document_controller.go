@@ -0,0 +1,222 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/bassista/go_spin/internal/api/apierror"
+	"github.com/bassista/go_spin/internal/cache"
+	"github.com/bassista/go_spin/internal/logger"
+	"github.com/bassista/go_spin/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// Export formats of GET /admin/export.
+const (
+	exportFormatJSON = "json"
+	exportFormatYAML = "yaml"
+)
+
+// exportFileName is the attachment name of an export, without the format extension.
+const exportFileName = "go_spin-data"
+
+// Errors aborting the import transaction: conflicting definitions were not applied, or the result
+// would reference missing containers or groups.
+var (
+	errImportConflicts    = errors.New("import conflicts")
+	errDanglingReferences = errors.New("dangling references")
+)
+
+// ImportDocumentResponse is the result of POST /admin/import.
+type ImportDocumentResponse struct {
+	cache.ImportReport
+	Mode   string `json:"mode"`
+	DryRun bool   `json:"dryRun"`
+}
+
+// DocumentController exports the data document and imports one, to migrate between hosts or keep
+// the definitions in git.
+type DocumentController struct {
+	store     cache.ReadOnlyStore
+	validator *validator.Validate
+}
+
+// NewDocumentController creates a new DocumentController.
+func NewDocumentController(store cache.ReadOnlyStore) *DocumentController {
+	return &DocumentController{store: store, validator: validator.New()}
+}
+
+// Export handles GET /admin/export[?format=json|yaml] - downloads the data document without the
+// runtime observations of the containers (running, runningSince, lastError). The format defaults
+// to yaml when the Accept header asks for it, to json otherwise.
+func (dc *DocumentController) Export(c *gin.Context) {
+	logger.WithComponent("document-controller").Debugf("GET /admin/export handler called")
+
+	format := c.Query("format")
+	if format == "" {
+		format = exportFormatJSON
+		if isYAML(c.GetHeader("Accept")) {
+			format = exportFormatYAML
+		}
+	}
+	if format != exportFormatJSON && format != exportFormatYAML {
+		apierror.Respond(c, http.StatusBadRequest, "format must be 'json' or 'yaml'")
+		return
+	}
+
+	doc, err := dc.store.Snapshot()
+	if err != nil {
+		logger.WithComponent("document-controller").Errorf("export: failed to read snapshot: %v", err)
+		apierror.Respond(c, http.StatusInternalServerError, "failed to read data")
+		return
+	}
+	for i := range doc.Containers {
+		doc.Containers[i] = cache.ContainerDefinition(doc.Containers[i])
+	}
+	if err := dc.validator.Struct(doc); err != nil {
+		logger.WithComponent("document-controller").Errorf("export: data document is not valid: %v", err)
+		apierror.Respond(c, http.StatusInternalServerError, "data document is not valid")
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s.%s", exportFileName, format))
+	if format == exportFormatYAML {
+		c.YAML(http.StatusOK, doc)
+		return
+	}
+	c.JSON(http.StatusOK, doc)
+}
+
+// Import handles POST /admin/import[?mode=merge|replace][&overwrite=true][&dryRun=true] - imports
+// a data document sent as JSON or, with a YAML Content-Type, as YAML. In merge mode (default) the
+// imported entities are added to the current ones and those defined differently are conflicts,
+// applied only with ?overwrite=true; in replace mode the document replaces the current one.
+// Returns the report of the changes, 409 with nothing applied when conflicts are left, 400 when the
+// document is not valid and 422 when the result would reference missing containers or groups.
+func (dc *DocumentController) Import(c *gin.Context) {
+	logger.WithComponent("document-controller").Debugf("POST /admin/import handler called")
+
+	mode := c.DefaultQuery("mode", cache.ImportModeMerge)
+	if mode != cache.ImportModeMerge && mode != cache.ImportModeReplace {
+		apierror.Respond(c, http.StatusBadRequest, "mode must be 'merge' or 'replace'")
+		return
+	}
+	dryRun, ok := boolQuery(c, "dryRun")
+	if !ok {
+		return
+	}
+	overwrite, ok := boolQuery(c, "overwrite")
+	if !ok {
+		return
+	}
+
+	var imported repository.DataDocument
+	var b binding.Binding = binding.JSON
+	if isYAML(c.ContentType()) {
+		b = binding.YAML
+	}
+	if err := c.ShouldBindWith(&imported, b); err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "invalid data document: "+err.Error())
+		return
+	}
+	imported.ApplyDefaults()
+	if err := dc.validator.Struct(imported); err != nil {
+		apierror.RespondError(c, http.StatusBadRequest, fmt.Errorf("invalid data document: %w", err))
+		return
+	}
+
+	store, ok := dc.store.(cache.TransactionalStore)
+	if !ok && !dryRun {
+		logger.WithComponent("document-controller").Errorf("import: store does not support transactions")
+		apierror.Respond(c, http.StatusInternalServerError, "import not supported")
+		return
+	}
+
+	var report cache.ImportReport
+	var orphans cache.PruneReport
+	var commandsChanged []string
+	merge := func(doc *repository.DataDocument) error {
+		before := append([]repository.Container(nil), doc.Containers...)
+		report = cache.MergeDocument(doc, imported, mode, overwrite)
+		if commandsChanged = changedDocumentCommandFields(before, doc.Containers); !mayChangeCommandFields(c, commandsChanged) {
+			return errCommandFieldsForbidden
+		}
+		checked := *doc
+		checked.Groups = append([]repository.Group(nil), doc.Groups...)
+		orphans = cache.PruneOrphans(&checked)
+		switch {
+		case !orphans.IsEmpty():
+			return errDanglingReferences
+		case report.HasConflicts():
+			return errImportConflicts
+		case report.IsEmpty():
+			return errNothingToImport
+		}
+		return nil
+	}
+
+	var err error
+	if dryRun {
+		var doc repository.DataDocument
+		doc, err = dc.store.Snapshot()
+		if err != nil {
+			logger.WithComponent("document-controller").Errorf("import: failed to read snapshot: %v", err)
+			apierror.Respond(c, http.StatusInternalServerError, "failed to read data")
+			return
+		}
+		err = merge(&doc)
+	} else {
+		_, err = store.Apply(merge)
+	}
+
+	response := ImportDocumentResponse{ImportReport: report, Mode: mode, DryRun: dryRun}
+	switch {
+	case errors.Is(err, errCommandFieldsForbidden):
+		respondCommandFieldsForbidden(c, commandsChanged)
+		return
+	case errors.Is(err, errDanglingReferences):
+		apierror.RespondDetails(c, http.StatusUnprocessableEntity, apierror.CodeDanglingReference, "the imported data references missing containers or groups", gin.H{"orphans": orphans})
+		return
+	case errors.Is(err, errImportConflicts):
+		if dryRun {
+			c.JSON(http.StatusOK, response)
+			return
+		}
+		c.JSON(http.StatusConflict, response)
+		return
+	case err != nil && !errors.Is(err, errNothingToImport):
+		logger.WithComponent("document-controller").Errorf("import: cache error: %v", err)
+		apierror.Respond(c, http.StatusInternalServerError, "failed to update cache")
+		return
+	}
+
+	if !dryRun && !report.IsEmpty() {
+		logger.WithComponent("document-controller").Infof("data document imported (%s): %d containers, %d groups, %d schedules added",
+			mode, len(report.Added.Containers), len(report.Added.Groups), len(report.Added.Schedules))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// boolQuery parses the optional boolean query parameter key, answering 400 when it is malformed.
+func boolQuery(c *gin.Context, key string) (bool, bool) {
+	v := c.Query(key)
+	if v == "" {
+		return false, true
+	}
+	parsed, err := strconv.ParseBool(v)
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "invalid "+key)
+		return false, false
+	}
+	return parsed, true
+}
+
+// isYAML reports whether a Content-Type or Accept header value designates YAML.
+func isYAML(mime string) bool {
+	return strings.Contains(mime, binding.MIMEYAML) || strings.Contains(mime, binding.MIMEYAML2)
+}
@@ -0,0 +1,205 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bassista/go_spin/internal/cache"
+	"github.com/bassista/go_spin/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+func newDocumentStore() *cache.Store {
+	return cache.NewStore(repository.DataDocument{
+		Containers: []repository.Container{
+			{Name: "web", FriendlyName: "web", URL: "http://web", Active: boolPtr(true), Running: boolPtr(true), LastError: "startup timeout"},
+		},
+		Order: []string{"web"},
+		Groups: []repository.Group{
+			{Name: "stack", Container: []string{"web"}, Active: boolPtr(true)},
+		},
+		GroupOrder: []string{"stack"},
+		Schedules: []repository.Schedule{
+			{ID: "s1", Target: "web", TargetType: "container", Timers: []repository.Timer{}},
+		},
+	})
+}
+
+func doDocumentRequest(store *cache.Store, method, target, contentType, body string) *httptest.ResponseRecorder {
+	r := gin.New()
+	dc := NewDocumentController(store)
+	r.GET("/admin/export", dc.Export)
+	r.POST("/admin/import", dc.Import)
+
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func decodeImportResponse(t *testing.T, w *httptest.ResponseRecorder) ImportDocumentResponse {
+	t.Helper()
+	var resp ImportDocumentResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	return resp
+}
+
+func TestDocumentController_Export(t *testing.T) {
+	store := newDocumentStore()
+
+	w := doDocumentRequest(store, http.MethodGet, "/admin/export", "", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("Content-Disposition"); got != "attachment; filename=go_spin-data.json" {
+		t.Errorf("unexpected Content-Disposition %q", got)
+	}
+	var doc repository.DataDocument
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("failed to unmarshal export: %v", err)
+	}
+	if len(doc.Containers) != 1 || doc.Containers[0].Running != nil || doc.Containers[0].LastError != "" {
+		t.Errorf("expected the export without runtime observations, got %+v", doc.Containers)
+	}
+
+	w = doDocumentRequest(store, http.MethodGet, "/admin/export?format=yaml", "", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), "friendly_name: web") {
+		t.Errorf("expected a YAML export using the JSON field names, got %s", w.Body.String())
+	}
+
+	w = doDocumentRequest(store, http.MethodGet, "/admin/export?format=xml", "", "")
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for an unknown format, got %d", w.Code)
+	}
+}
+
+func TestDocumentController_Import_YAMLRoundTrip(t *testing.T) {
+	source := newDocumentStore()
+	export := doDocumentRequest(source, http.MethodGet, "/admin/export?format=yaml", "", "")
+
+	target := cache.NewStore(repository.DataDocument{})
+	w := doDocumentRequest(target, http.MethodPost, "/admin/import", "application/yaml", export.Body.String())
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	resp := decodeImportResponse(t, w)
+	if !reflect.DeepEqual(resp.Added.Containers, []string{"web"}) || !reflect.DeepEqual(resp.Added.Schedules, []string{"s1"}) {
+		t.Errorf("unexpected report %+v", resp.ImportReport)
+	}
+
+	doc, _ := target.Snapshot()
+	if len(doc.Containers) != 1 || len(doc.Groups) != 1 || len(doc.Schedules) != 1 {
+		t.Errorf("expected the imported document, got %+v", doc)
+	}
+	if !reflect.DeepEqual(doc.Order, []string{"web"}) || !reflect.DeepEqual(doc.GroupOrder, []string{"stack"}) {
+		t.Errorf("expected the orders to list the imported entities, got %v and %v", doc.Order, doc.GroupOrder)
+	}
+}
+
+func TestDocumentController_Import_MergeConflicts(t *testing.T) {
+	store := newDocumentStore()
+	body := `{"containers":[
+		{"name":"web","friendly_name":"web","url":"http://web:8080","active":true},
+		{"name":"api","friendly_name":"API","url":"http://api","active":true}]}`
+
+	// Dry run: the conflict is reported, nothing changes
+	w := doDocumentRequest(store, http.MethodPost, "/admin/import?dryRun=true", "application/json", body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	resp := decodeImportResponse(t, w)
+	if !resp.DryRun || !reflect.DeepEqual(resp.Conflicts.Containers, []string{"web"}) || !reflect.DeepEqual(resp.Added.Containers, []string{"api"}) {
+		t.Errorf("unexpected dry run report %+v", resp)
+	}
+	if store.IsDirty() {
+		t.Error("expected a dry run to leave the store untouched")
+	}
+
+	// Conflicts without overwrite: 409 and nothing applied
+	w = doDocumentRequest(store, http.MethodPost, "/admin/import", "application/json", body)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected status 409, got %d: %s", w.Code, w.Body.String())
+	}
+	if doc, _ := store.Snapshot(); len(doc.Containers) != 1 {
+		t.Errorf("expected nothing imported on conflict, got %d containers", len(doc.Containers))
+	}
+
+	// Overwrite: the definition is replaced, the runtime state kept
+	w = doDocumentRequest(store, http.MethodPost, "/admin/import?overwrite=true", "application/json", body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	resp = decodeImportResponse(t, w)
+	if !reflect.DeepEqual(resp.Updated.Containers, []string{"web"}) {
+		t.Errorf("expected web updated, got %+v", resp.ImportReport)
+	}
+	doc, _ := store.Snapshot()
+	if len(doc.Containers) != 2 || doc.Containers[0].URL != "http://web:8080" || doc.Containers[0].Running == nil || !*doc.Containers[0].Running {
+		t.Errorf("unexpected containers after overwrite: %+v", doc.Containers)
+	}
+	if doc.Containers[1].FriendlyName != "api" {
+		t.Errorf("expected the friendly name lowercased, got %q", doc.Containers[1].FriendlyName)
+	}
+	if len(doc.Schedules) != 1 || len(doc.Groups) != 1 {
+		t.Error("expected merge to keep the entities missing from the import")
+	}
+}
+
+func TestDocumentController_Import_Replace(t *testing.T) {
+	store := newDocumentStore()
+	body := `{"containers":[{"name":"api","friendly_name":"api","url":"http://api","active":true}]}`
+
+	w := doDocumentRequest(store, http.MethodPost, "/admin/import?mode=replace", "application/json", body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	resp := decodeImportResponse(t, w)
+	want := cache.EntityNames{Containers: []string{"web"}, Groups: []string{"stack"}, Schedules: []string{"s1"}}
+	if !reflect.DeepEqual(resp.Removed, want) {
+		t.Errorf("expected removed %+v, got %+v", want, resp.Removed)
+	}
+	doc, _ := store.Snapshot()
+	if len(doc.Containers) != 1 || doc.Containers[0].Name != "api" || len(doc.Groups) != 0 || len(doc.Schedules) != 0 {
+		t.Errorf("expected the document replaced, got %+v", doc)
+	}
+}
+
+func TestDocumentController_Import_Rejected(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		body   string
+		status int
+	}{
+		{name: "malformed", body: `{"containers":`, status: http.StatusBadRequest},
+		{name: "invalid container", body: `{"containers":[{"name":"api","url":"http://api","active":true}]}`, status: http.StatusBadRequest},
+		{name: "invalid mode", query: "?mode=upsert", body: `{}`, status: http.StatusBadRequest},
+		{name: "invalid dryRun", query: "?dryRun=maybe", body: `{}`, status: http.StatusBadRequest},
+		{name: "dangling schedule", body: `{"schedules":[{"id":"s2","target":"missing","targetType":"container"}]}`, status: http.StatusUnprocessableEntity},
+		{name: "dangling member", body: `{"groups":[{"name":"g","container":["missing"],"active":true}]}`, status: http.StatusUnprocessableEntity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newDocumentStore()
+			w := doDocumentRequest(store, http.MethodPost, "/admin/import"+tt.query, "application/json", tt.body)
+			if w.Code != tt.status {
+				t.Errorf("expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			if store.IsDirty() {
+				t.Error("expected a rejected import to leave the store untouched")
+			}
+		})
+	}
+}
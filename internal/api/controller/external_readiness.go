@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/bassista/go_spin/internal/logger"
+)
+
+// Reasons reported when readiness is delegated to the external checker.
+const (
+	reasonExternalNotConfigured   = "external_not_configured"
+	reasonExternalNotReady        = "external_not_ready"
+	reasonExternalInvalidResponse = "external_invalid_response"
+)
+
+// externalReadyChecker delegates readiness to an external service (e.g. an uptime checker) for
+// containers with ReadyCheckType "external". Any failure to get a clear answer means not ready.
+type externalReadyChecker struct {
+	url     string
+	timeout time.Duration
+	client  *http.Client
+}
+
+// externalReadyResponse is the body expected from the external checker.
+type externalReadyResponse struct {
+	Ready *bool `json:"ready"`
+}
+
+// newExternalReadyChecker returns a checker calling checkURL, or nil when no URL is configured.
+func newExternalReadyChecker(checkURL string, timeout time.Duration) *externalReadyChecker {
+	if checkURL == "" {
+		return nil
+	}
+	return &externalReadyChecker{url: checkURL, timeout: timeout, client: &http.Client{}}
+}
+
+// check calls GET <url>?name=<container> and expects {"ready": bool}. It returns an empty reason
+// when the checker reports the container ready.
+func (e *externalReadyChecker) check(ctx context.Context, name string) string {
+	if e == nil {
+		return reasonExternalNotConfigured
+	}
+	checkURL, err := url.Parse(e.url)
+	if err != nil {
+		logger.WithComponent("readiness").Warnf("invalid external ready checker url %s: %v", e.url, err)
+		return reasonUnreachable
+	}
+	query := checkURL.Query()
+	query.Set("name", name)
+	checkURL.RawQuery = query.Encode()
+
+	reqCtx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, checkURL.String(), nil)
+	if err != nil {
+		logger.WithComponent("readiness").Warnf("failed to create external ready request for %s: %v", name, err)
+		return reasonUnreachable
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		logger.WithComponent("readiness").Warnf("external ready check failed for %s: %v", name, err)
+		return transportFailureReason(err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return reasonBadStatusPrefix + strconv.Itoa(resp.StatusCode)
+	}
+
+	var body externalReadyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil || body.Ready == nil {
+		logger.WithComponent("readiness").Warnf("external ready checker returned an invalid body for %s: %v", name, err)
+		return reasonExternalInvalidResponse
+	}
+	logger.WithComponent("readiness").Debugf("external ready check for %s: ready=%v", name, *body.Ready)
+	if !*body.Ready {
+		return reasonExternalNotReady
+	}
+	return ""
+}
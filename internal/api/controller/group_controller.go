@@ -3,10 +3,16 @@ package controller
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
+	"slices"
+	"time"
 
+	"github.com/bassista/go_spin/internal/api/apierror"
 	"github.com/bassista/go_spin/internal/cache"
+	"github.com/bassista/go_spin/internal/config"
 	"github.com/bassista/go_spin/internal/logger"
+	"github.com/bassista/go_spin/internal/ops"
 	"github.com/bassista/go_spin/internal/repository"
 	"github.com/bassista/go_spin/internal/runtime"
 	"github.com/gin-gonic/gin"
@@ -18,11 +24,18 @@ type GroupController struct {
 	crud    *CrudController[repository.Group]
 	store   cache.GroupStore
 	runtime runtime.ContainerRuntime
+	ops     *ops.Queue
 	baseCtx context.Context
+	config  *config.Config
+	starter groupStarter
 }
 
 // NewGroupController creates a new GroupController with the given cache store and runtime.
-func NewGroupController(baseCtx context.Context, store cache.GroupStore, rt runtime.ContainerRuntime) *GroupController {
+// Containers are started and stopped through queue; a nil queue gets a queue of its own over rt.
+func NewGroupController(baseCtx context.Context, store cache.GroupStore, rt runtime.ContainerRuntime, queue *ops.Queue, cfg *config.Config) *GroupController {
+	if queue == nil {
+		queue = ops.NewQueue(baseCtx, rt, cfg.Data.OperationsRetention)
+	}
 	v := validator.New()
 	service := &GroupCrudService{Store: store}
 	validator := &GroupCrudValidator{validator: v}
@@ -34,10 +47,23 @@ func NewGroupController(baseCtx context.Context, store cache.GroupStore, rt runt
 		},
 		store:   store,
 		runtime: rt,
+		ops:     queue,
 		baseCtx: baseCtx,
+		config:  cfg,
+		starter: groupStarter{
+			runtime:  rt,
+			external: newExternalReadyChecker(cfg.Misc.ExternalReadyURL, cfg.Misc.ExternalReadyTimeout),
+			interval: cfg.Data.StartupProbeInterval,
+		},
 	}
 }
 
+// SetExecHealthChecks enables the exec health checks awaited between start stages, see
+// RuntimeController.SetExecHealthChecks.
+func (gc *GroupController) SetExecHealthChecks(enabled bool) {
+	gc.starter.execChecks = enabled
+}
+
 // AllGroups handles GET /groups - returns all groups.
 func (gc *GroupController) AllGroups(c *gin.Context) {
 	logger.WithComponent("group-controller").Debugf("GET /groups handler called")
@@ -56,7 +82,7 @@ func (gc *GroupController) DeleteGroup(c *gin.Context) {
 	logger.WithComponent("group-controller").Debugf("DELETE /group/%s handler called", name)
 	if name == "" {
 		logger.WithComponent("group-controller").Debugf("delete group: missing name parameter")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "missing group name"})
+		apierror.Respond(c, http.StatusBadRequest, "missing group name")
 		return
 	}
 
@@ -64,11 +90,11 @@ func (gc *GroupController) DeleteGroup(c *gin.Context) {
 	if err != nil {
 		if errors.Is(err, cache.ErrGroupNotFound) {
 			logger.WithComponent("group-controller").Debugf("delete group %s: not found", name)
-			c.JSON(http.StatusNotFound, gin.H{"error": "group not found"})
+			apierror.RespondCode(c, http.StatusNotFound, apierror.CodeGroupNotFound, "group not found")
 			return
 		}
 		logger.WithComponent("group-controller").Errorf("delete group %s: cache error: %v", name, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update cache"})
+		apierror.Respond(c, http.StatusInternalServerError, "failed to update cache")
 		return
 	}
 
@@ -82,14 +108,14 @@ func (gc *GroupController) StartGroup(c *gin.Context) {
 	logger.WithComponent("group-controller").Debugf("POST /group/%s/start handler called", name)
 	if name == "" {
 		logger.WithComponent("group-controller").Debugf("start group: missing name parameter")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "missing group name"})
+		apierror.Respond(c, http.StatusBadRequest, "missing group name")
 		return
 	}
 
 	doc, err := gc.store.Snapshot()
 	if err != nil {
 		logger.WithComponent("group-controller").Errorf("start group %s: failed to read snapshot: %v", name, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read group data"})
+		apierror.Respond(c, http.StatusInternalServerError, "failed to read group data")
 		return
 	}
 
@@ -103,27 +129,52 @@ func (gc *GroupController) StartGroup(c *gin.Context) {
 	}
 	if group == nil {
 		logger.WithComponent("group-controller").Debugf("start group %s: not found", name)
-		c.JSON(http.StatusNotFound, gin.H{"error": "group not found"})
+		apierror.RespondCode(c, http.StatusNotFound, apierror.CodeGroupNotFound, "group not found")
 		return
 	}
 
 	if group.Active == nil || !*group.Active {
 		logger.WithComponent("group-controller").Debugf("start group %s: group is not active", name)
-		c.JSON(http.StatusForbidden, gin.H{"error": "group is not active"})
+		apierror.RespondCode(c, http.StatusForbidden, apierror.CodeInactive, "group is not active")
 		return
 	}
 
-	// Start all containers in the group in background
-	for _, containerName := range group.Container {
-		gc.startContainerInBackground(containerName)
+	missing := missingGroupMembers(doc, *group)
+	if len(missing) > 0 && gc.config.Data.StrictGroups {
+		logger.WithComponent("group-controller").Warnf("start group %s: missing member containers %v", name, missing)
+		apierror.RespondDetails(c, http.StatusBadRequest, apierror.CodeDanglingReference, "group references missing containers",
+			gin.H{"missing": missing})
+		return
 	}
 
-	logger.WithComponent("group-controller").Infof("group %s: started %d containers in background", name, len(group.Container))
-	c.JSON(http.StatusOK, gin.H{
-		"name":       name,
-		"message":    "group containers starting",
-		"containers": group.Container,
-	})
+	// Start all existing containers in the group in background, dependencies first and by start
+	// priority within a stage, skipping missing members
+	warnings := []string{}
+	members := make([]string, 0, len(group.Container))
+	for _, containerName := range groupStartOrder(doc, *group) {
+		if slices.Contains(missing, containerName) {
+			warnings = append(warnings, fmt.Sprintf("container '%s' not found, skipped", containerName))
+			continue
+		}
+		members = append(members, containerName)
+	}
+	stages := groupStartStages(doc, members)
+	started := make([]string, 0, len(members))
+	for _, stage := range stages {
+		started = append(started, stage...)
+	}
+	gc.startStagesInBackground(logger.Detach(gc.baseCtx, c.Request.Context()), doc, *group, stages)
+
+	logger.WithComponent("group-controller").Infof("group %s: started %d containers in background", name, len(started))
+	resp := GroupActionResponse{
+		ActionResponse: ActionResponse{Name: name, Message: "group containers starting"},
+		Containers:     started,
+		Warnings:       warnings,
+	}
+	if len(stages) > 1 {
+		resp.Stages = stages
+	}
+	c.JSON(http.StatusOK, resp)
 }
 
 // StopGroup handles POST /group/:name/stop - stops all containers in a group.
@@ -132,14 +183,14 @@ func (gc *GroupController) StopGroup(c *gin.Context) {
 	logger.WithComponent("group-controller").Debugf("POST /group/%s/stop handler called", name)
 	if name == "" {
 		logger.WithComponent("group-controller").Debugf("stop group: missing name parameter")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "missing group name"})
+		apierror.Respond(c, http.StatusBadRequest, "missing group name")
 		return
 	}
 
 	doc, err := gc.store.Snapshot()
 	if err != nil {
 		logger.WithComponent("group-controller").Errorf("stop group %s: failed to read snapshot: %v", name, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read group data"})
+		apierror.Respond(c, http.StatusInternalServerError, "failed to read group data")
 		return
 	}
 
@@ -153,43 +204,51 @@ func (gc *GroupController) StopGroup(c *gin.Context) {
 	}
 	if group == nil {
 		logger.WithComponent("group-controller").Debugf("stop group %s: not found", name)
-		c.JSON(http.StatusNotFound, gin.H{"error": "group not found"})
+		apierror.RespondCode(c, http.StatusNotFound, apierror.CodeGroupNotFound, "group not found")
 		return
 	}
 
-	// Stop all containers in the group in background
+	// Stop all containers in the group in background, logging with the request ID
+	ctx := logger.Detach(gc.baseCtx, c.Request.Context())
 	for _, containerName := range group.Container {
-		gc.stopContainerInBackground(containerName)
+		gc.stopContainerInBackground(ctx, containerName)
 	}
 
 	logger.WithComponent("group-controller").Infof("group %s: stopped %d containers in background", name, len(group.Container))
-	c.JSON(http.StatusOK, gin.H{
-		"name":       name,
-		"message":    "group containers stopping",
-		"containers": group.Container,
+	containers := group.Container
+	if containers == nil {
+		containers = []string{}
+	}
+	c.JSON(http.StatusOK, GroupActionResponse{
+		ActionResponse: ActionResponse{Name: name, Message: "group containers stopping"},
+		Containers:     containers,
 	})
 }
 
-// startContainerInBackground starts a container in a dedicated goroutine.
-func (gc *GroupController) startContainerInBackground(containerName string) {
-	go func(name string) {
-		logger.WithComponent("group-controller").Infof("starting container %s in background", name)
-		if err := gc.runtime.Start(gc.baseCtx, name); err != nil {
-			logger.WithComponent("group-controller").Errorf("failed to start container %s in background: %v", name, err)
-		} else {
-			logger.WithComponent("group-controller").Infof("container %s started successfully", name)
+// startStagesInBackground starts the group stages in a dedicated goroutine, so the starts are
+// issued in stage then priority order. A failed start does not stop the others of its stage.
+func (gc *GroupController) startStagesInBackground(ctx context.Context, doc repository.DataDocument, group repository.Group, stages [][]string) {
+	go gc.starter.run(ctx, doc, group, stages, func(name string) bool {
+		logger.WithContext(ctx, "group-controller").Infof("starting container %s in background", name)
+		if err := gc.ops.Do(ctx, ops.ActionStart, name); err != nil {
+			logger.WithContext(ctx, "group-controller").Errorf("failed to start container %s in background: %v", name, err)
+			return false
 		}
-	}(containerName)
+		logger.WithContext(ctx, "group-controller").Infof("container %s started successfully", name)
+		cache.RecordRuntimeState(gc.store, name, true, time.Now())
+		return true
+	})
 }
 
 // stopContainerInBackground stops a container in a dedicated goroutine.
-func (gc *GroupController) stopContainerInBackground(containerName string) {
+func (gc *GroupController) stopContainerInBackground(ctx context.Context, containerName string) {
 	go func(name string) {
-		logger.WithComponent("group-controller").Infof("stopping container %s in background", name)
-		if err := gc.runtime.Stop(gc.baseCtx, name); err != nil {
-			logger.WithComponent("group-controller").Errorf("failed to stop container %s in background: %v", name, err)
+		logger.WithContext(ctx, "group-controller").Infof("stopping container %s in background", name)
+		if err := gc.ops.Do(ctx, ops.ActionStop, name); err != nil {
+			logger.WithContext(ctx, "group-controller").Errorf("failed to stop container %s in background: %v", name, err)
 		} else {
-			logger.WithComponent("group-controller").Infof("container %s stopped successfully", name)
+			logger.WithContext(ctx, "group-controller").Infof("container %s stopped successfully", name)
+			cache.RecordRuntimeState(gc.store, name, false, time.Now())
 		}
 	}(containerName)
 }
@@ -7,9 +7,14 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/bassista/go_spin/internal/api/apierror"
 	"github.com/bassista/go_spin/internal/cache"
+	"github.com/bassista/go_spin/internal/config"
 	"github.com/bassista/go_spin/internal/repository"
 	"github.com/bassista/go_spin/internal/runtime"
 	"github.com/gin-gonic/gin"
@@ -51,13 +56,17 @@ func (m *mockGroupStore) RemoveGroup(name string) (repository.DataDocument, erro
 type mockGroupRuntime struct {
 	startErr error
 	stopErr  error
+	started  chan string // receives the started names when set
 }
 
 func (m *mockGroupRuntime) IsRunning(_ context.Context, _ string) (bool, error) {
 	return false, nil
 }
 
-func (m *mockGroupRuntime) Start(_ context.Context, _ string) error {
+func (m *mockGroupRuntime) Start(_ context.Context, name string) error {
+	if m.started != nil {
+		m.started <- name
+	}
 	return m.startErr
 }
 
@@ -88,7 +97,7 @@ func TestGroupController_AllGroups(t *testing.T) {
 	}
 	rt := &mockGroupRuntime{}
 
-	gc := NewGroupController(context.Background(), store, rt)
+	gc := NewGroupController(context.Background(), store, rt, nil, &config.Config{})
 
 	r := gin.New()
 	r.GET("/groups", gc.AllGroups)
@@ -127,7 +136,7 @@ func TestGroupController_CreateOrUpdateGroup_Valid(t *testing.T) {
 	}
 	rt := &mockGroupRuntime{}
 
-	gc := NewGroupController(context.Background(), store, rt)
+	gc := NewGroupController(context.Background(), store, rt, nil, &config.Config{})
 
 	r := gin.New()
 	r.POST("/group", gc.CreateOrUpdateGroup)
@@ -151,10 +160,43 @@ func TestGroupController_CreateOrUpdateGroup_Valid(t *testing.T) {
 	}
 }
 
+func TestGroupController_CreateOrUpdateGroup_DanglingMembers(t *testing.T) {
+	store := cache.NewStore(repository.DataDocument{
+		Containers: []repository.Container{{Name: "c1", FriendlyName: "c1", URL: "http://c1", Active: boolPtr(true)}},
+	})
+	gc := NewGroupController(context.Background(), store, &mockGroupRuntime{}, nil, &config.Config{})
+	r := gin.New()
+	r.POST("/group", gc.CreateOrUpdateGroup)
+
+	body := `{"name":"g","container":["c1","ghost"],"active":true}`
+	post := func(target string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post("/group")
+	if w.Code != http.StatusUnprocessableEntity || !strings.Contains(w.Body.String(), "ghost") {
+		t.Fatalf("expected status 422 naming ghost, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := post("/group?force=maybe"); w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for an invalid force, got %d", w.Code)
+	}
+	if w := post("/group?force=true"); w.Code != http.StatusOK {
+		t.Fatalf("expected status 200 with force, got %d: %s", w.Code, w.Body.String())
+	}
+	doc, _ := store.Snapshot()
+	if len(doc.Groups) != 1 || len(doc.Groups[0].Container) != 2 {
+		t.Errorf("expected the forced group stored as sent, got %+v", doc.Groups)
+	}
+}
+
 func TestGroupController_CreateOrUpdateGroup_InvalidPayload(t *testing.T) {
 	store := &mockGroupStore{}
 	rt := &mockGroupRuntime{}
-	gc := NewGroupController(context.Background(), store, rt)
+	gc := NewGroupController(context.Background(), store, rt, nil, &config.Config{})
 
 	r := gin.New()
 	r.POST("/group", gc.CreateOrUpdateGroup)
@@ -173,7 +215,7 @@ func TestGroupController_CreateOrUpdateGroup_InvalidPayload(t *testing.T) {
 func TestGroupController_CreateOrUpdateGroup_ValidationError(t *testing.T) {
 	store := &mockGroupStore{}
 	rt := &mockGroupRuntime{}
-	gc := NewGroupController(context.Background(), store, rt)
+	gc := NewGroupController(context.Background(), store, rt, nil, &config.Config{})
 
 	r := gin.New()
 	r.POST("/group", gc.CreateOrUpdateGroup)
@@ -200,7 +242,7 @@ func TestGroupController_CreateOrUpdateGroup_StoreError(t *testing.T) {
 		addErr: errors.New("store error"),
 	}
 	rt := &mockGroupRuntime{}
-	gc := NewGroupController(context.Background(), store, rt)
+	gc := NewGroupController(context.Background(), store, rt, nil, &config.Config{})
 
 	r := gin.New()
 	r.POST("/group", gc.CreateOrUpdateGroup)
@@ -234,7 +276,7 @@ func TestGroupController_DeleteGroup_Success(t *testing.T) {
 		},
 	}
 	rt := &mockGroupRuntime{}
-	gc := NewGroupController(context.Background(), store, rt)
+	gc := NewGroupController(context.Background(), store, rt, nil, &config.Config{})
 
 	r := gin.New()
 	r.DELETE("/group/:name", gc.DeleteGroup)
@@ -256,7 +298,7 @@ func TestGroupController_DeleteGroup_NotFound(t *testing.T) {
 		},
 	}
 	rt := &mockGroupRuntime{}
-	gc := NewGroupController(context.Background(), store, rt)
+	gc := NewGroupController(context.Background(), store, rt, nil, &config.Config{})
 
 	r := gin.New()
 	r.DELETE("/group/:name", gc.DeleteGroup)
@@ -274,7 +316,7 @@ func TestGroupController_DeleteGroup_NotFound(t *testing.T) {
 func TestGroupController_DeleteGroup_MissingName(t *testing.T) {
 	store := &mockGroupStore{}
 	rt := &mockGroupRuntime{}
-	gc := NewGroupController(context.Background(), store, rt)
+	gc := NewGroupController(context.Background(), store, rt, nil, &config.Config{})
 
 	r := gin.New()
 	r.DELETE("/group/", gc.DeleteGroup)
@@ -299,7 +341,7 @@ func TestGroupController_StartGroup_Success(t *testing.T) {
 		},
 	}
 	rt := &mockGroupRuntime{}
-	gc := NewGroupController(context.Background(), store, rt)
+	gc := NewGroupController(context.Background(), store, rt, nil, &config.Config{})
 
 	r := gin.New()
 	r.POST("/group/:name/start", gc.StartGroup)
@@ -314,6 +356,146 @@ func TestGroupController_StartGroup_Success(t *testing.T) {
 	}
 }
 
+func TestGroupController_StartGroup_StartsByPriority(t *testing.T) {
+	active := true
+	store := &mockGroupStore{
+		doc: repository.DataDocument{
+			Containers: []repository.Container{
+				{Name: "app"},
+				{Name: "db", Priority: 10},
+				{Name: "cache", Priority: 5},
+				{Name: "worker"},
+			},
+			Groups: []repository.Group{
+				{Name: "test-group", Container: []string{"worker", "app", "cache", "db"}, Active: &active},
+			},
+		},
+	}
+	rt := &mockGroupRuntime{started: make(chan string, 4)}
+	gc := NewGroupController(context.Background(), store, rt, nil, &config.Config{})
+
+	r := gin.New()
+	r.POST("/group/:name/start", gc.StartGroup)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/group/test-group/start", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	expected := []string{"db", "cache", "app", "worker"}
+	for i, want := range expected {
+		select {
+		case got := <-rt.started:
+			if got != want {
+				t.Errorf("start %d: expected %s, got %s", i, want, got)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for start %d (%s)", i, want)
+		}
+	}
+}
+
+// stagedGroupRuntime reports a container running once started, unless it is listed in neverUp.
+type stagedGroupRuntime struct {
+	mockGroupRuntime
+	mu      sync.Mutex
+	running map[string]bool
+	neverUp map[string]bool
+}
+
+func (m *stagedGroupRuntime) IsRunning(_ context.Context, name string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.running[name], nil
+}
+
+func (m *stagedGroupRuntime) Start(ctx context.Context, name string) error {
+	m.mu.Lock()
+	m.running[name] = !m.neverUp[name]
+	m.mu.Unlock()
+	return m.mockGroupRuntime.Start(ctx, name)
+}
+
+func TestGroupController_StartGroup_StartsDependenciesFirst(t *testing.T) {
+	active := true
+	store := &mockGroupStore{
+		doc: repository.DataDocument{
+			Containers: []repository.Container{
+				{Name: "app", Priority: 10, DependsOn: []string{"db"}},
+				{Name: "db"},
+			},
+			Groups: []repository.Group{
+				{Name: "stack", Container: []string{"app", "db"}, Active: &active},
+			},
+		},
+	}
+	rt := &stagedGroupRuntime{mockGroupRuntime: mockGroupRuntime{started: make(chan string, 2)}, running: map[string]bool{}}
+	gc := NewGroupController(context.Background(), store, rt, nil, &config.Config{Data: config.DataConfig{StartupProbeInterval: 10 * time.Millisecond}})
+
+	r := gin.New()
+	r.POST("/group/:name/start", gc.StartGroup)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/group/stack/start", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp GroupActionResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if len(resp.Stages) != 2 || resp.Stages[0][0] != "db" || resp.Stages[1][0] != "app" {
+		t.Errorf("expected stages [[db] [app]], got %v", resp.Stages)
+	}
+
+	for i, want := range []string{"db", "app"} {
+		select {
+		case got := <-rt.started:
+			if got != want {
+				t.Errorf("start %d: expected %s, got %s", i, want, got)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for start %d (%s)", i, want)
+		}
+	}
+}
+
+func TestGroupController_StartGroup_DependencyNotReadyStopsLaterStages(t *testing.T) {
+	active := true
+	timeout := 1
+	store := &mockGroupStore{
+		doc: repository.DataDocument{
+			Containers: []repository.Container{
+				{Name: "app", DependsOn: []string{"db"}},
+				{Name: "db", StartupTimeoutSecs: &timeout},
+			},
+			Groups: []repository.Group{
+				{Name: "stack", Container: []string{"app", "db"}, Active: &active},
+			},
+		},
+	}
+	rt := &stagedGroupRuntime{
+		mockGroupRuntime: mockGroupRuntime{started: make(chan string, 2)},
+		running:          map[string]bool{},
+		neverUp:          map[string]bool{"db": true},
+	}
+	gc := NewGroupController(context.Background(), store, rt, nil, &config.Config{Data: config.DataConfig{StartupProbeInterval: 10 * time.Millisecond}})
+
+	r := gin.New()
+	r.POST("/group/:name/start", gc.StartGroup)
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/group/stack/start", nil))
+
+	if got := <-rt.started; got != "db" {
+		t.Fatalf("expected db started first, got %s", got)
+	}
+	select {
+	case got := <-rt.started:
+		t.Errorf("expected app not started while db is not ready, got %s", got)
+	case <-time.After(1500 * time.Millisecond):
+	}
+}
+
 func TestGroupController_StartGroup_EmptyName(t *testing.T) {
 	active := true
 	store := &mockGroupStore{
@@ -324,7 +506,7 @@ func TestGroupController_StartGroup_EmptyName(t *testing.T) {
 		},
 	}
 	rt := &mockGroupRuntime{}
-	gc := NewGroupController(context.Background(), store, rt)
+	gc := NewGroupController(context.Background(), store, rt, nil, &config.Config{})
 
 	r := gin.New()
 	r.POST("/group/:name/start", gc.StartGroup)
@@ -347,7 +529,7 @@ func TestGroupController_StartGroup_NotFound(t *testing.T) {
 		},
 	}
 	rt := &mockGroupRuntime{}
-	gc := NewGroupController(context.Background(), store, rt)
+	gc := NewGroupController(context.Background(), store, rt, nil, &config.Config{})
 
 	r := gin.New()
 	r.POST("/group/:name/start", gc.StartGroup)
@@ -372,7 +554,7 @@ func TestGroupController_StartGroup_InactiveGroup(t *testing.T) {
 		},
 	}
 	rt := &mockGroupRuntime{}
-	gc := NewGroupController(context.Background(), store, rt)
+	gc := NewGroupController(context.Background(), store, rt, nil, &config.Config{})
 
 	r := gin.New()
 	r.POST("/group/:name/start", gc.StartGroup)
@@ -396,7 +578,7 @@ func TestGroupController_StartGroup_NilActiveGroup(t *testing.T) {
 		},
 	}
 	rt := &mockGroupRuntime{}
-	gc := NewGroupController(context.Background(), store, rt)
+	gc := NewGroupController(context.Background(), store, rt, nil, &config.Config{})
 
 	r := gin.New()
 	r.POST("/group/:name/start", gc.StartGroup)
@@ -421,7 +603,7 @@ func TestGroupController_StopGroup_Success(t *testing.T) {
 		},
 	}
 	rt := &mockGroupRuntime{}
-	gc := NewGroupController(context.Background(), store, rt)
+	gc := NewGroupController(context.Background(), store, rt, nil, &config.Config{})
 
 	r := gin.New()
 	r.POST("/group/:name/stop", gc.StopGroup)
@@ -445,7 +627,7 @@ func TestGroupController_StopGroup_EmptyName(t *testing.T) {
 		},
 	}
 	rt := &mockGroupRuntime{}
-	gc := NewGroupController(context.Background(), store, rt)
+	gc := NewGroupController(context.Background(), store, rt, nil, &config.Config{})
 
 	r := gin.New()
 	r.POST("/group/:name/stop", gc.StopGroup)
@@ -468,7 +650,7 @@ func TestGroupController_StopGroup_NotFound(t *testing.T) {
 		},
 	}
 	rt := &mockGroupRuntime{}
-	gc := NewGroupController(context.Background(), store, rt)
+	gc := NewGroupController(context.Background(), store, rt, nil, &config.Config{})
 
 	r := gin.New()
 	r.POST("/group/:name/stop", gc.StopGroup)
@@ -491,7 +673,7 @@ func TestGroupController_DeleteGroup_StoreError(t *testing.T) {
 		removeErr: errors.New("store error"),
 	}
 	rt := &mockGroupRuntime{}
-	gc := NewGroupController(context.Background(), store, rt)
+	gc := NewGroupController(context.Background(), store, rt, nil, &config.Config{})
 
 	r := gin.New()
 	r.DELETE("/group/:name", gc.DeleteGroup)
@@ -524,7 +706,7 @@ func TestGroupController_StartGroup_SnapshotError(t *testing.T) {
 		snapshotErr: errors.New("snapshot error"),
 	}
 	rt := &mockGroupRuntime{}
-	gc := NewGroupController(context.Background(), store, rt)
+	gc := NewGroupController(context.Background(), store, rt, nil, &config.Config{})
 
 	r := gin.New()
 	r.POST("/group/:name/start", gc.StartGroup)
@@ -544,7 +726,7 @@ func TestGroupController_StopGroup_SnapshotError(t *testing.T) {
 		snapshotErr: errors.New("snapshot error"),
 	}
 	rt := &mockGroupRuntime{}
-	gc := NewGroupController(context.Background(), store, rt)
+	gc := NewGroupController(context.Background(), store, rt, nil, &config.Config{})
 
 	r := gin.New()
 	r.POST("/group/:name/stop", gc.StopGroup)
@@ -558,3 +740,82 @@ func TestGroupController_StopGroup_SnapshotError(t *testing.T) {
 		t.Errorf("expected status 500, got %d", w.Code)
 	}
 }
+
+func newGroupWithMissingMemberStore() *mockGroupStore {
+	active := true
+	return &mockGroupStore{
+		doc: repository.DataDocument{
+			Containers: []repository.Container{{Name: "c1", Active: &active}},
+			Groups: []repository.Group{
+				{Name: "test-group", Container: []string{"c1", "ghost"}, Active: &active},
+			},
+		},
+	}
+}
+
+func TestGroupController_StartGroup_StrictRejectsMissingMembers(t *testing.T) {
+	store := newGroupWithMissingMemberStore()
+	rt := &mockGroupRuntime{}
+	cfg := &config.Config{Data: config.DataConfig{StrictGroups: true}}
+	gc := NewGroupController(context.Background(), store, rt, nil, cfg)
+
+	r := gin.New()
+	r.POST("/group/:name/start", gc.StartGroup)
+
+	req := httptest.NewRequest(http.MethodPost, "/group/test-group/start", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+	var body struct {
+		Code    string `json:"code"`
+		Details struct {
+			Missing []string `json:"missing"`
+		} `json:"details"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body.Code != apierror.CodeDanglingReference {
+		t.Errorf("expected code %s, got %q", apierror.CodeDanglingReference, body.Code)
+	}
+	if len(body.Details.Missing) != 1 || body.Details.Missing[0] != "ghost" {
+		t.Errorf("expected missing [ghost], got %v", body.Details.Missing)
+	}
+}
+
+func TestGroupController_StartGroup_LenientSkipsMissingMembersWithWarnings(t *testing.T) {
+	store := newGroupWithMissingMemberStore()
+	rt := &mockGroupRuntime{}
+	gc := NewGroupController(context.Background(), store, rt, nil, &config.Config{})
+
+	r := gin.New()
+	r.POST("/group/:name/start", gc.StartGroup)
+
+	req := httptest.NewRequest(http.MethodPost, "/group/test-group/start", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var body struct {
+		Containers []string `json:"containers"`
+		Warnings   []string `json:"warnings"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(body.Containers) != 1 || body.Containers[0] != "c1" {
+		t.Errorf("expected only c1 to be started, got %v", body.Containers)
+	}
+	if len(body.Warnings) != 1 {
+		t.Errorf("expected one warning for the missing member, got %v", body.Warnings)
+	}
+}
+
+func (m *mockGroupRuntime) Ping(_ context.Context) (runtime.PingResult, error) {
+	return runtime.PingResult{}, nil
+}
@@ -3,6 +3,7 @@ package controller
 import (
 	"github.com/bassista/go_spin/internal/cache"
 	"github.com/bassista/go_spin/internal/repository"
+	"github.com/bassista/go_spin/internal/scheduler"
 	"github.com/go-playground/validator/v10"
 )
 
@@ -27,6 +28,19 @@ func (s *GroupCrudService) Add(item repository.Group) ([]repository.Group, error
 	return sanitizeGroups(doc), nil
 }
 
+// AddForce upserts the group even when it lists missing containers.
+func (s *GroupCrudService) AddForce(item repository.Group) ([]repository.Group, error) {
+	unchecked, ok := s.Store.(cache.UncheckedStore)
+	if !ok {
+		return s.Add(item)
+	}
+	doc, err := unchecked.AddGroupUnchecked(item)
+	if err != nil {
+		return nil, err
+	}
+	return sanitizeGroups(doc), nil
+}
+
 func (s *GroupCrudService) Remove(name string) ([]repository.Group, error) {
 	doc, err := s.Store.RemoveGroup(name)
 	if err != nil {
@@ -58,11 +72,41 @@ func sanitizeGroups(doc repository.DataDocument) []repository.Group {
 	return sanitized
 }
 
+// missingGroupMembers returns the names listed in the group that do not match
+// any container of the document, preserving the group order.
+func missingGroupMembers(doc repository.DataDocument, group repository.Group) []string {
+	containerSet := make(map[string]struct{}, len(doc.Containers))
+	for _, c := range doc.Containers {
+		containerSet[c.Name] = struct{}{}
+	}
+
+	missing := []string{}
+	for _, cname := range group.Container {
+		if _, ok := containerSet[cname]; !ok {
+			missing = append(missing, cname)
+		}
+	}
+	return missing
+}
+
+// groupStartOrder returns the group members in the order their starts are issued: higher
+// Container.Priority first, then by name.
+func groupStartOrder(doc repository.DataDocument, group repository.Group) []string {
+	containersByName := make(map[string]repository.Container, len(doc.Containers))
+	for _, c := range doc.Containers {
+		containersByName[c.Name] = c
+	}
+	return scheduler.StartOrder(group.Container, containersByName)
+}
+
 // GroupCrudValidator implements CrudValidator for groups.
 type GroupCrudValidator struct {
 	validator *validator.Validate
 }
 
 func (v *GroupCrudValidator) Validate(item repository.Group) error {
-	return v.validator.Struct(item)
+	if err := v.validator.Struct(item); err != nil {
+		return err
+	}
+	return validateWaitingPage(item.WaitingPage)
 }
@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"context"
+	"sync"
+
+	"github.com/bassista/go_spin/internal/repository"
+	"github.com/bassista/go_spin/internal/runtime"
+)
+
+// reasonNoMembers is reported for a group without active configured members.
+const reasonNoMembers = "no_members"
+
+// MemberReadiness is the readiness of one group member.
+type MemberReadiness struct {
+	Name   string `json:"name"`
+	Ready  bool   `json:"ready"`
+	Reason string `json:"reason,omitempty"`
+	Error  string `json:"error,omitempty"` // last recorded start failure, e.g. "startup timeout"
+}
+
+// GroupReadyResponse is the readiness of a group: ready when all its active members are ready
+// (Group.ReadyMode "all", default) or at least one is ("any"). RedirectURL, set once ready, is the
+// URL the waiting page moves to: the first member for "all", the first ready member for "any".
+type GroupReadyResponse struct {
+	Ready       bool              `json:"ready"`
+	Reason      string            `json:"reason,omitempty"`
+	RedirectURL string            `json:"redirectUrl,omitempty"`
+	Containers  []MemberReadiness `json:"containers"`
+}
+
+// readinessProbe probes a running container, returning whether it is ready and why not.
+type readinessProbe func(ctx context.Context, container *repository.Container) (bool, string)
+
+// groupReadiness checks the active configured members of group in parallel, in group order.
+// A running member without readiness URLs counts as ready, as in staged group starts.
+func groupReadiness(ctx context.Context, rt runtime.ContainerRuntime, doc repository.DataDocument, group repository.Group, probe readinessProbe) GroupReadyResponse {
+	members := make([]*repository.Container, 0, len(group.Container))
+	for _, name := range group.Container {
+		container, found := findDocContainer(doc, name)
+		if !found || container.Active == nil || !*container.Active {
+			continue
+		}
+		members = append(members, container)
+	}
+	resp := GroupReadyResponse{Containers: make([]MemberReadiness, len(members))}
+	if len(members) == 0 {
+		resp.Reason = reasonNoMembers
+		return resp
+	}
+
+	var wg sync.WaitGroup
+	for i, container := range members {
+		wg.Add(1)
+		go func(i int, container *repository.Container) {
+			defer wg.Done()
+			member := MemberReadiness{Name: container.Name, Error: container.LastError}
+			running, err := rt.IsRunning(ctx, container.Name)
+			switch {
+			case err != nil:
+				member.Reason = reasonRuntimeError
+			case !running:
+				member.Reason = reasonNotRunning
+			case !hasReadinessCheck(container):
+				member.Ready = true
+			default:
+				member.Ready, member.Reason = probe(ctx, container)
+			}
+			resp.Containers[i] = member
+		}(i, container)
+	}
+	wg.Wait()
+
+	if group.ReadyMode == repository.ReadyModeAny {
+		for i, member := range resp.Containers {
+			if member.Ready {
+				resp.Ready = true
+				resp.RedirectURL = members[i].URL
+				return resp
+			}
+		}
+		resp.Reason = resp.Containers[0].Reason
+		return resp
+	}
+
+	for _, member := range resp.Containers {
+		if !member.Ready {
+			resp.Reason = member.Reason
+			return resp
+		}
+	}
+	resp.Ready = true
+	resp.RedirectURL = members[0].URL
+	return resp
+}
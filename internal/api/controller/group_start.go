@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/bassista/go_spin/internal/logger"
+	"github.com/bassista/go_spin/internal/repository"
+	"github.com/bassista/go_spin/internal/runtime"
+	"github.com/bassista/go_spin/internal/scheduler"
+)
+
+// defaultStageReadyTimeout bounds the wait for a container of a group start stage to become ready
+// when it defines no startupTimeoutSecs.
+const defaultStageReadyTimeout = 2 * time.Minute
+
+// groupStarter starts the members of a group stage by stage (see scheduler.StartStages): the
+// containers of a stage are started in order, then, before the next stage, the starter waits
+// until they are ready and for the group StartDelaySecs.
+type groupStarter struct {
+	runtime    runtime.ContainerRuntime
+	external   *externalReadyChecker
+	execChecks bool          // run exec health checks, see probeContainerReady
+	interval   time.Duration // readiness polling interval
+}
+
+// groupStartStages returns the start stages of names, members of a group of doc.
+func groupStartStages(doc repository.DataDocument, names []string) [][]string {
+	containersByName := make(map[string]repository.Container, len(doc.Containers))
+	for _, c := range doc.Containers {
+		containersByName[c.Name] = c
+	}
+	return scheduler.StartStages(names, containersByName)
+}
+
+// run starts the stages of group with start, which reports whether the start was issued.
+// A stage whose started containers do not become ready in time aborts the later stages, whose
+// containers would fail without their dependencies. It blocks until done or ctx is cancelled.
+func (gs groupStarter) run(ctx context.Context, doc repository.DataDocument, group repository.Group, stages [][]string, start func(name string) bool) {
+	delay := time.Duration(group.StartDelaySecs) * time.Second
+	for i, stage := range stages {
+		started := make([]string, 0, len(stage))
+		for _, name := range stage {
+			if start(name) {
+				started = append(started, name)
+			}
+		}
+		if i == len(stages)-1 {
+			return
+		}
+
+		for _, name := range started {
+			container, found := findDocContainer(doc, name)
+			if !found {
+				continue
+			}
+			if !gs.awaitReady(ctx, container) {
+				if ctx.Err() == nil {
+					logger.WithComponent("group-start").Warnf("group %s: container %s not ready, not starting the containers depending on it", group.Name, name)
+				}
+				return
+			}
+		}
+		if delay > 0 {
+			logger.WithComponent("group-start").Debugf("group %s: waiting %v before the next start stage", group.Name, delay)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+		}
+	}
+}
+
+// awaitReady polls the container until it runs and is ready, for at most its startupTimeoutSecs
+// (defaultStageReadyTimeout when unset). A container without readiness URLs is ready once running.
+func (gs groupStarter) awaitReady(ctx context.Context, container *repository.Container) bool {
+	timeout := defaultStageReadyTimeout
+	if container.StartupTimeoutSecs != nil && *container.StartupTimeoutSecs > 0 {
+		timeout = time.Duration(*container.StartupTimeoutSecs) * time.Second
+	}
+	interval := gs.interval
+	if interval <= 0 {
+		interval = defaultStartupProbeInterval
+	}
+	probeReadiness := hasReadinessCheck(container)
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		running, err := gs.runtime.IsRunning(ctx, container.Name)
+		if err == nil && running && (!probeReadiness || probeContainerReady(ctx, container, gs.external, gs.runtime, gs.execChecks)) {
+			logger.WithComponent("group-start").Debugf("container %s is ready", container.Name)
+			return true
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+	}
+}
+
+// findDocContainer returns the container of doc named name.
+func findDocContainer(doc repository.DataDocument, name string) (*repository.Container, bool) {
+	for i := range doc.Containers {
+		if doc.Containers[i].Name == name {
+			return &doc.Containers[i], true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,304 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/bassista/go_spin/internal/api/apierror"
+	"github.com/bassista/go_spin/internal/cache"
+	"github.com/bassista/go_spin/internal/logger"
+	"github.com/bassista/go_spin/internal/repository"
+	"github.com/bassista/go_spin/internal/runtime"
+	"github.com/gin-gonic/gin"
+)
+
+// errNothingToImport aborts the import transaction so an unchanged document is not marked dirty.
+var errNothingToImport = errors.New("nothing to import")
+
+// autoGroupPrefix groups imported containers by the part of their name before the first "-".
+const autoGroupPrefix = "prefix"
+
+// ImportResponse is the result of POST /import/runtime.
+type ImportResponse struct {
+	Imported []repository.Container `json:"imported"`
+	// Groups are the groups created or extended by ?autoGroup, omitted without it.
+	Groups []repository.Group `json:"groups,omitempty"`
+}
+
+// ImportCandidate is a runtime container missing from the cache, as proposed for import.
+type ImportCandidate struct {
+	Name   string            `json:"name"`
+	URL    string            `json:"url"` // guessed, see importURL
+	Ports  []uint16          `json:"ports,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// DiscoveryResponse is the result of POST /containers/import-from-runtime.
+type DiscoveryResponse struct {
+	Candidates []ImportCandidate `json:"candidates"`
+	Inserted   bool              `json:"inserted"`
+	ImportResponse
+}
+
+// ImportController creates container definitions from what already exists on the runtime host.
+type ImportController struct {
+	store   cache.ReadOnlyStore
+	runtime runtime.ContainerRuntime
+	baseURL string
+}
+
+// NewImportController creates a new ImportController. baseURL is data.base_url, used to derive
+// the URL of imported containers.
+func NewImportController(store cache.ReadOnlyStore, rt runtime.ContainerRuntime, baseURL string) *ImportController {
+	return &ImportController{store: store, runtime: rt, baseURL: baseURL}
+}
+
+// FromRuntime handles POST /import/runtime - adds every runtime container missing from the cache
+// (names compared case-insensitively) as an inactive entry, so nothing starts before the user
+// reviews and activates it. With ?autoGroup=prefix the imported containers are also added to
+// groups named after their name prefix (see groupKey). Returns the created entries.
+func (ic *ImportController) FromRuntime(c *gin.Context) {
+	logger.WithComponent("import-controller").Debugf("POST /import/runtime handler called")
+
+	autoGroup, ok := autoGroupQuery(c)
+	if !ok {
+		return
+	}
+	candidates, ok := ic.discover(c)
+	if !ok {
+		return
+	}
+	resp, ok := ic.insert(c, candidates, autoGroup)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// Discover handles POST /containers/import-from-runtime - proposes an entry (name, guessed URL,
+// published ports and labels) for every runtime container missing from the cache, without
+// changing anything. With ?insert=true the proposed entries are added like POST /import/runtime
+// does, ?autoGroup=prefix included.
+func (ic *ImportController) Discover(c *gin.Context) {
+	logger.WithComponent("import-controller").Debugf("POST /containers/import-from-runtime handler called")
+
+	insert, ok := boolQuery(c, "insert")
+	if !ok {
+		return
+	}
+	autoGroup, ok := autoGroupQuery(c)
+	if !ok {
+		return
+	}
+	candidates, ok := ic.discover(c)
+	if !ok {
+		return
+	}
+
+	if insert {
+		resp, ok := ic.insert(c, candidates, autoGroup)
+		if !ok {
+			return
+		}
+		imported := make(map[string]bool, len(resp.Imported))
+		for _, container := range resp.Imported {
+			imported[container.Name] = true
+		}
+		proposed := []ImportCandidate{}
+		for _, candidate := range candidates {
+			if imported[candidate.Name] {
+				proposed = append(proposed, candidate)
+			}
+		}
+		c.JSON(http.StatusOK, DiscoveryResponse{Candidates: proposed, Inserted: true, ImportResponse: resp})
+		return
+	}
+
+	doc, err := ic.store.Snapshot()
+	if err != nil {
+		logger.WithComponent("import-controller").Errorf("discover: failed to read snapshot: %v", err)
+		apierror.Respond(c, http.StatusInternalServerError, "failed to read data")
+		return
+	}
+	c.JSON(http.StatusOK, DiscoveryResponse{
+		Candidates:     unknownCandidates(doc, candidates),
+		ImportResponse: ImportResponse{Imported: []repository.Container{}},
+	})
+}
+
+// autoGroupQuery parses ?autoGroup, sending 400 and returning false when it is invalid.
+func autoGroupQuery(c *gin.Context) (string, bool) {
+	autoGroup := c.Query("autoGroup")
+	if autoGroup != "" && autoGroup != autoGroupPrefix {
+		apierror.Respond(c, http.StatusBadRequest, "autoGroup must be 'prefix'")
+		return "", false
+	}
+	return autoGroup, true
+}
+
+// discover lists the runtime containers with their published ports and labels, when the runtime
+// reports them, and guesses their URL. It sends 500 and returns false when the list fails.
+func (ic *ImportController) discover(c *gin.Context) ([]ImportCandidate, bool) {
+	ctx := c.Request.Context()
+	names, err := ic.runtime.ListContainers(ctx)
+	if err != nil {
+		logger.WithComponent("import-controller").Errorf("import: failed to list containers: %v", err)
+		apierror.Respond(c, http.StatusInternalServerError, "Unable to list containers")
+		return nil, false
+	}
+
+	var ports map[string][]uint16
+	if runtime.Supports[runtime.PortLister](ic.runtime) {
+		ports, err = ic.runtime.(runtime.PortLister).PublishedPorts(ctx)
+		if err != nil {
+			// Ports only refine the URL, the base_url fallback is still a usable default
+			logger.WithComponent("import-controller").Warnf("import: failed to read published ports, using base_url: %v", err)
+		}
+	}
+	var labels map[string]map[string]string
+	if runtime.Supports[runtime.LabelLister](ic.runtime) {
+		labels, err = ic.runtime.(runtime.LabelLister).ContainerLabels(ctx)
+		if err != nil {
+			logger.WithComponent("import-controller").Warnf("import: failed to read container labels: %v", err)
+		}
+	}
+
+	candidates := make([]ImportCandidate, 0, len(names))
+	for _, name := range names {
+		candidates = append(candidates, ImportCandidate{
+			Name:   name,
+			URL:    ic.importURL(name, ports[name]),
+			Ports:  ports[name],
+			Labels: labels[name],
+		})
+	}
+	return candidates, true
+}
+
+// insert adds the candidates missing from the cache as inactive containers in one transaction,
+// grouping them when autoGroup is set. It sends 500 and returns false when the cache fails.
+func (ic *ImportController) insert(c *gin.Context, candidates []ImportCandidate, autoGroup string) (ImportResponse, bool) {
+	store, ok := ic.store.(cache.TransactionalStore)
+	if !ok {
+		logger.WithComponent("import-controller").Errorf("import: store does not support transactions")
+		apierror.Respond(c, http.StatusInternalServerError, "import not supported")
+		return ImportResponse{}, false
+	}
+
+	imported := []repository.Container{}
+	var groups []repository.Group
+	_, err := store.Apply(func(doc *repository.DataDocument) error {
+		for _, candidate := range unknownCandidates(*doc, candidates) {
+			inactive := false
+			container := repository.Container{
+				Name:         candidate.Name,
+				FriendlyName: strings.ToLower(candidate.Name),
+				URL:          candidate.URL,
+				Active:       &inactive,
+			}
+			doc.Containers = append(doc.Containers, container)
+			doc.Order = append(doc.Order, candidate.Name)
+			imported = append(imported, container)
+		}
+		if len(imported) == 0 {
+			return errNothingToImport
+		}
+		if autoGroup == autoGroupPrefix {
+			groups = groupImported(doc, imported)
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errNothingToImport) {
+		logger.WithComponent("import-controller").Errorf("import: cache error: %v", err)
+		apierror.Respond(c, http.StatusInternalServerError, "failed to update cache")
+		return ImportResponse{}, false
+	}
+
+	if len(imported) > 0 {
+		logger.WithComponent("import-controller").Infof("imported %d containers from the runtime", len(imported))
+	}
+	return ImportResponse{Imported: imported, Groups: groups}, true
+}
+
+// unknownCandidates returns the candidates whose name matches no container of doc, compared
+// case-insensitively, keeping only the first of candidates differing by case.
+func unknownCandidates(doc repository.DataDocument, candidates []ImportCandidate) []ImportCandidate {
+	known := make(map[string]struct{}, len(doc.Containers))
+	for _, container := range doc.Containers {
+		known[strings.ToLower(container.Name)] = struct{}{}
+	}
+	unknown := []ImportCandidate{}
+	for _, candidate := range candidates {
+		key := strings.ToLower(candidate.Name)
+		if _, exists := known[key]; exists {
+			continue
+		}
+		known[key] = struct{}{}
+		unknown = append(unknown, candidate)
+	}
+	return unknown
+}
+
+// groupImported adds the imported containers to the group named after their prefix, creating
+// missing groups inactive like the containers themselves. Containers without a prefix stay
+// ungrouped. It returns the groups it created or extended.
+func groupImported(doc *repository.DataDocument, imported []repository.Container) []repository.Group {
+	touched := map[int]struct{}{} // indexes in doc.Groups, order keeps them as first touched
+	var order []int
+	for _, container := range imported {
+		key := groupKey(container.Name)
+		if key == "" {
+			continue
+		}
+		idx := -1
+		for i := range doc.Groups {
+			if strings.EqualFold(doc.Groups[i].Name, key) {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			inactive := false
+			doc.Groups = append(doc.Groups, repository.Group{Name: key, Active: &inactive})
+			doc.GroupOrder = append(doc.GroupOrder, key)
+			idx = len(doc.Groups) - 1
+		}
+		doc.Groups[idx].Container = append(doc.Groups[idx].Container, container.Name)
+		if _, ok := touched[idx]; !ok {
+			touched[idx] = struct{}{}
+			order = append(order, idx)
+		}
+	}
+
+	groups := make([]repository.Group, 0, len(order))
+	for _, idx := range order {
+		groups = append(groups, doc.Groups[idx])
+	}
+	return groups
+}
+
+// groupKey returns the lowercase part of name before the first "-", or "" when there is none.
+func groupKey(name string) string {
+	prefix, _, found := strings.Cut(name, "-")
+	if !found || prefix == "" {
+		return ""
+	}
+	return strings.ToLower(prefix)
+}
+
+// importURL derives the URL of an imported container: the base_url host on the lowest published
+// port when the runtime reports one, the base_url with $1 replaced by the name otherwise.
+func (ic *ImportController) importURL(name string, ports []uint16) string {
+	fallback := strings.ReplaceAll(ic.baseURL, "$1", name)
+	if len(ports) == 0 {
+		return fallback
+	}
+	u, err := url.Parse(fallback)
+	if err != nil || u.Scheme == "" || u.Hostname() == "" {
+		return fallback
+	}
+	return u.Scheme + "://" + u.Hostname() + ":" + strconv.Itoa(int(ports[0]))
+}
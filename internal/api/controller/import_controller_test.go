@@ -0,0 +1,257 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/bassista/go_spin/internal/cache"
+	"github.com/bassista/go_spin/internal/logger"
+	"github.com/bassista/go_spin/internal/repository"
+	"github.com/bassista/go_spin/internal/runtime"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+)
+
+// portListingRuntime adds published ports to the mock runtime.
+type portListingRuntime struct {
+	*mockContainerRuntime
+	ports map[string][]uint16
+}
+
+func (p *portListingRuntime) PublishedPorts(_ context.Context) (map[string][]uint16, error) {
+	return p.ports, nil
+}
+
+func doImport(t *testing.T, ic *ImportController, target string) ImportResponse {
+	t.Helper()
+	r := gin.New()
+	r.POST("/import/runtime", ic.FromRuntime)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, target, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp ImportResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	return resp
+}
+
+func TestImportController_FromRuntime_ImportsInactive(t *testing.T) {
+	rt := newMockRuntime()
+	rt.runningContainers["web"] = true
+	rt.runningContainers["db"] = false
+	store := cache.NewStore(repository.DataDocument{})
+	ic := NewImportController(store, &portListingRuntime{
+		mockContainerRuntime: rt,
+		ports:                map[string][]uint16{"web": {8080, 8443}},
+	}, "http://$1.local/")
+
+	resp := doImport(t, ic, "/import/runtime")
+	if len(resp.Imported) != 2 {
+		t.Fatalf("expected 2 imported containers, got %d", len(resp.Imported))
+	}
+
+	doc, _ := store.Snapshot()
+	if len(doc.Containers) != 2 || len(doc.Order) != 2 {
+		t.Fatalf("expected 2 containers in cache and order, got %d and %d", len(doc.Containers), len(doc.Order))
+	}
+	urls := map[string]string{}
+	for _, c := range doc.Containers {
+		if c.Active == nil || *c.Active {
+			t.Errorf("expected %s to be imported inactive", c.Name)
+		}
+		urls[c.Name] = c.URL
+	}
+	if urls["web"] != "http://web.local:8080" {
+		t.Errorf("expected web URL from its lowest published port, got %q", urls["web"])
+	}
+	if urls["db"] != "http://db.local/" {
+		t.Errorf("expected db URL from base_url, got %q", urls["db"])
+	}
+	if !store.IsDirty() {
+		t.Error("expected store to be dirty after import")
+	}
+}
+
+func TestImportController_FromRuntime_SkipsKnownContainers(t *testing.T) {
+	rt := newMockRuntime()
+	rt.runningContainers["Web"] = true
+	store := cache.NewStore(repository.DataDocument{Containers: []repository.Container{
+		{Name: "web", FriendlyName: "web", URL: "http://web", Active: boolPtr(true)},
+	}})
+	ic := NewImportController(store, rt, "http://localhost/")
+
+	resp := doImport(t, ic, "/import/runtime")
+	if len(resp.Imported) != 0 {
+		t.Errorf("expected nothing imported, got %v", resp.Imported)
+	}
+	if store.IsDirty() {
+		t.Error("expected store to stay clean when nothing is imported")
+	}
+}
+
+func TestImportController_FromRuntime_AutoGroupPrefix(t *testing.T) {
+	rt := newMockRuntime()
+	rt.runningContainers["web-1"] = true
+	rt.runningContainers["web-2"] = false
+	rt.runningContainers["db-1"] = false
+	rt.runningContainers["proxy"] = true
+	store := cache.NewStore(repository.DataDocument{
+		Groups:     []repository.Group{{Name: "db", Container: []string{"db-0"}, Active: boolPtr(true)}},
+		GroupOrder: []string{"db"},
+	})
+	ic := NewImportController(store, rt, "http://localhost/")
+
+	resp := doImport(t, ic, "/import/runtime?autoGroup=prefix")
+	if len(resp.Imported) != 4 {
+		t.Fatalf("expected 4 imported containers, got %d", len(resp.Imported))
+	}
+	if len(resp.Groups) != 2 {
+		t.Fatalf("expected 2 groups in response, got %v", resp.Groups)
+	}
+
+	doc, _ := store.Snapshot()
+	members := map[string][]string{}
+	active := map[string]bool{}
+	for _, g := range doc.Groups {
+		sort.Strings(g.Container)
+		members[g.Name] = g.Container
+		active[g.Name] = *g.Active
+	}
+	if got := members["web"]; len(got) != 2 || got[0] != "web-1" || got[1] != "web-2" {
+		t.Errorf("expected web group with web-1 and web-2, got %v", got)
+	}
+	if active["web"] {
+		t.Error("expected the new web group to be created inactive")
+	}
+	if got := members["db"]; len(got) != 2 || got[0] != "db-0" || got[1] != "db-1" {
+		t.Errorf("expected existing db group extended with db-1, got %v", got)
+	}
+	if !active["db"] {
+		t.Error("expected the existing db group to keep its active flag")
+	}
+	if len(doc.Groups) != 2 || len(doc.GroupOrder) != 2 {
+		t.Errorf("expected no group for proxy, got groups %v and order %v", doc.Groups, doc.GroupOrder)
+	}
+}
+
+func TestImportController_FromRuntime_InvalidAutoGroup(t *testing.T) {
+	store := cache.NewStore(repository.DataDocument{})
+	ic := NewImportController(store, newMockRuntime(), "http://localhost/")
+
+	r := gin.New()
+	r.POST("/import/runtime", ic.FromRuntime)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/import/runtime?autoGroup=suffix", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Code)
+	}
+}
+
+// labelListingRuntime adds labels to the port listing mock runtime.
+type labelListingRuntime struct {
+	*portListingRuntime
+	labels map[string]map[string]string
+}
+
+func (l *labelListingRuntime) ContainerLabels(_ context.Context) (map[string]map[string]string, error) {
+	return l.labels, nil
+}
+
+func doDiscover(t *testing.T, ic *ImportController, target string) DiscoveryResponse {
+	t.Helper()
+	r := gin.New()
+	r.POST("/containers/import-from-runtime", ic.Discover)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, target, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp DiscoveryResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	return resp
+}
+
+func TestImportController_Discover(t *testing.T) {
+	rt := newMockRuntime()
+	rt.runningContainers["web"] = true
+	rt.runningContainers["known"] = true
+	store := cache.NewStore(repository.DataDocument{Containers: []repository.Container{
+		{Name: "known", FriendlyName: "known", URL: "http://known", Active: boolPtr(true)},
+	}})
+	ic := NewImportController(store, &labelListingRuntime{
+		portListingRuntime: &portListingRuntime{mockContainerRuntime: rt, ports: map[string][]uint16{"web": {8080}}},
+		labels:             map[string]map[string]string{"web": {"com.docker.compose.project": "media"}},
+	}, "http://$1.local/")
+
+	// Proposals only: nothing is stored
+	resp := doDiscover(t, ic, "/containers/import-from-runtime")
+	if resp.Inserted || len(resp.Imported) != 0 || len(resp.Candidates) != 1 {
+		t.Fatalf("expected one proposal and nothing inserted, got %+v", resp)
+	}
+	want := ImportCandidate{Name: "web", URL: "http://web.local:8080", Ports: []uint16{8080}, Labels: map[string]string{"com.docker.compose.project": "media"}}
+	if !reflect.DeepEqual(resp.Candidates[0], want) {
+		t.Errorf("expected candidate %+v, got %+v", want, resp.Candidates[0])
+	}
+	if store.IsDirty() {
+		t.Error("expected proposals to leave the store clean")
+	}
+
+	// Insert: the proposals become inactive containers
+	resp = doDiscover(t, ic, "/containers/import-from-runtime?insert=true")
+	if !resp.Inserted || len(resp.Imported) != 1 || resp.Imported[0].URL != want.URL || len(resp.Candidates) != 1 {
+		t.Fatalf("expected web inserted, got %+v", resp)
+	}
+	if doc, _ := store.Snapshot(); len(doc.Containers) != 2 {
+		t.Errorf("expected 2 containers after insert, got %d", len(doc.Containers))
+	}
+
+	if resp = doDiscover(t, ic, "/containers/import-from-runtime"); len(resp.Candidates) != 0 {
+		t.Errorf("expected no proposal left, got %+v", resp.Candidates)
+	}
+}
+
+func TestImportController_Discover_WrappedRuntimeWithoutPortsOrLabels(t *testing.T) {
+	hook := logtest.NewLocal(logger.Logger)
+	defer hook.Reset()
+
+	// The wrappers of main implement PortLister and LabelLister whatever the memory runtime does
+	rt := runtime.Forwarder{Inner: runtime.NewMemoryRuntimeFromDocument(repository.DataDocument{
+		Containers: []repository.Container{{Name: "web", Running: boolPtr(true)}},
+	})}
+	ic := NewImportController(cache.NewStore(repository.DataDocument{}), rt, "http://$1.local/")
+
+	resp := doDiscover(t, ic, "/containers/import-from-runtime")
+	if len(resp.Candidates) != 1 || resp.Candidates[0].URL != "http://web.local/" {
+		t.Fatalf("expected web with the base_url, got %+v", resp.Candidates)
+	}
+	for _, entry := range hook.AllEntries() {
+		if entry.Level <= logrus.WarnLevel && entry.Data["component"] == "import-controller" {
+			t.Errorf("expected no warning for an unsupported capability, got %q", entry.Message)
+		}
+	}
+}
+
+func TestImportController_Discover_InvalidInsert(t *testing.T) {
+	ic := NewImportController(cache.NewStore(repository.DataDocument{}), newMockRuntime(), "http://localhost/")
+
+	r := gin.New()
+	r.POST("/containers/import-from-runtime", ic.Discover)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/containers/import-from-runtime?insert=maybe", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Code)
+	}
+}
@@ -0,0 +1,104 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/bassista/go_spin/internal/api/apierror"
+	"github.com/bassista/go_spin/internal/cache"
+	"github.com/bassista/go_spin/internal/logger"
+	"github.com/bassista/go_spin/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// errNothingToPrune aborts the prune transaction so an already clean document is not marked dirty.
+var errNothingToPrune = errors.New("nothing to prune")
+
+// PruneResponse is the result of POST /maintenance/prune.
+type PruneResponse struct {
+	cache.PruneReport
+	DryRun bool `json:"dryRun"`
+}
+
+// MaintenanceController exposes data maintenance operations.
+type MaintenanceController struct {
+	store cache.ReadOnlyStore
+}
+
+// NewMaintenanceController creates a new MaintenanceController.
+func NewMaintenanceController(store cache.ReadOnlyStore) *MaintenanceController {
+	return &MaintenanceController{store: store}
+}
+
+// Prune handles POST /maintenance/prune[?dryRun=true] - removes schedules targeting missing
+// containers/groups and scrubs missing containers from groups, returning what was pruned.
+func (mc *MaintenanceController) Prune(c *gin.Context) {
+	logger.WithComponent("maintenance-controller").Debugf("POST /maintenance/prune handler called")
+
+	dryRun := false
+	if v := c.Query("dryRun"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			apierror.Respond(c, http.StatusBadRequest, "invalid dryRun")
+			return
+		}
+		dryRun = parsed
+	}
+
+	if dryRun {
+		doc, err := mc.store.Snapshot()
+		if err != nil {
+			logger.WithComponent("maintenance-controller").Errorf("prune: failed to read snapshot: %v", err)
+			apierror.Respond(c, http.StatusInternalServerError, "failed to read data")
+			return
+		}
+		c.JSON(http.StatusOK, PruneResponse{PruneReport: cache.PruneOrphans(&doc), DryRun: true})
+		return
+	}
+
+	store, ok := mc.store.(cache.TransactionalStore)
+	if !ok {
+		logger.WithComponent("maintenance-controller").Errorf("prune: store does not support transactions")
+		apierror.Respond(c, http.StatusInternalServerError, "prune not supported")
+		return
+	}
+
+	var report cache.PruneReport
+	_, err := store.Apply(func(doc *repository.DataDocument) error {
+		report = cache.PruneOrphans(doc)
+		if report.IsEmpty() {
+			return errNothingToPrune
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errNothingToPrune) {
+		logger.WithComponent("maintenance-controller").Errorf("prune: cache error: %v", err)
+		apierror.Respond(c, http.StatusInternalServerError, "failed to update cache")
+		return
+	}
+
+	if !report.IsEmpty() {
+		logger.WithComponent("maintenance-controller").Infof("pruned %d orphaned schedules and dangling members from %d groups", len(report.Schedules), len(report.GroupMembers))
+	}
+	c.JSON(http.StatusOK, PruneResponse{PruneReport: report})
+}
+
+// IntegrityResponse is the result of GET /admin/integrity.
+type IntegrityResponse struct {
+	OK bool `json:"ok"`
+	cache.IntegrityReport
+}
+
+// Integrity handles GET /admin/integrity - reports the references of schedules, groups, container
+// dependencies and orders to missing containers or groups, without changing anything.
+func (mc *MaintenanceController) Integrity(c *gin.Context) {
+	doc, err := mc.store.Snapshot()
+	if err != nil {
+		logger.WithComponent("maintenance-controller").Errorf("integrity: failed to read snapshot: %v", err)
+		apierror.Respond(c, http.StatusInternalServerError, "failed to read data")
+		return
+	}
+	report := cache.CheckIntegrity(doc)
+	c.JSON(http.StatusOK, IntegrityResponse{OK: report.IsEmpty(), IntegrityReport: report})
+}
@@ -0,0 +1,144 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/bassista/go_spin/internal/cache"
+	"github.com/bassista/go_spin/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+func newOrphanedStore() *cache.Store {
+	return cache.NewStore(repository.DataDocument{
+		Containers: []repository.Container{
+			{Name: "web", FriendlyName: "web", URL: "http://web", Active: boolPtr(true)},
+		},
+		Groups: []repository.Group{
+			{Name: "stack", Container: []string{"web", "gone-db", "gone-cache"}, Active: boolPtr(true)},
+			{Name: "clean", Container: []string{"web"}, Active: boolPtr(true)},
+		},
+		Schedules: []repository.Schedule{
+			{ID: "ok-container", Target: "web", TargetType: "container"},
+			{ID: "ok-group", Target: "stack", TargetType: "group"},
+			{ID: "orphan-container", Target: "deleted", TargetType: "container"},
+			{ID: "orphan-group", Target: "deleted-group", TargetType: "group"},
+		},
+	})
+}
+
+func doPrune(t *testing.T, store *cache.Store, query string) PruneResponse {
+	t.Helper()
+	r := gin.New()
+	r.POST("/maintenance/prune", NewMaintenanceController(store).Prune)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/maintenance/prune"+query, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp PruneResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	return resp
+}
+
+func TestMaintenanceController_Prune(t *testing.T) {
+	store := newOrphanedStore()
+
+	resp := doPrune(t, store, "")
+	if resp.DryRun {
+		t.Error("expected dryRun false")
+	}
+	if want := []string{"orphan-container", "orphan-group"}; !reflect.DeepEqual(resp.Schedules, want) {
+		t.Errorf("expected pruned schedules %v, got %v", want, resp.Schedules)
+	}
+	if want := map[string][]string{"stack": {"gone-db", "gone-cache"}}; !reflect.DeepEqual(resp.GroupMembers, want) {
+		t.Errorf("expected pruned group members %v, got %v", want, resp.GroupMembers)
+	}
+
+	doc, _ := store.Snapshot()
+	if len(doc.Schedules) != 2 {
+		t.Errorf("expected 2 schedules left, got %d", len(doc.Schedules))
+	}
+	if !reflect.DeepEqual(doc.Groups[0].Container, []string{"web"}) {
+		t.Errorf("expected stack to keep only web, got %v", doc.Groups[0].Container)
+	}
+	if !store.IsDirty() {
+		t.Error("expected store to be dirty after prune")
+	}
+
+	// A second prune finds nothing and leaves the store untouched.
+	store.ClearDirty()
+	resp = doPrune(t, store, "")
+	if len(resp.Schedules) != 0 || len(resp.GroupMembers) != 0 {
+		t.Errorf("expected empty report, got %+v", resp.PruneReport)
+	}
+	if store.IsDirty() {
+		t.Error("expected store to stay clean when nothing is pruned")
+	}
+}
+
+func TestMaintenanceController_Prune_DryRun(t *testing.T) {
+	store := newOrphanedStore()
+	before, _ := store.Snapshot()
+
+	resp := doPrune(t, store, "?dryRun=true")
+	if !resp.DryRun {
+		t.Error("expected dryRun true")
+	}
+	if len(resp.Schedules) != 2 || len(resp.GroupMembers["stack"]) != 2 {
+		t.Errorf("expected preview of the orphans, got %+v", resp.PruneReport)
+	}
+
+	after, _ := store.Snapshot()
+	if !reflect.DeepEqual(before, after) {
+		t.Error("expected dry run to leave the document unchanged")
+	}
+	if store.IsDirty() {
+		t.Error("expected dry run not to mark the store dirty")
+	}
+}
+
+func TestMaintenanceController_Prune_InvalidDryRun(t *testing.T) {
+	r := gin.New()
+	r.POST("/maintenance/prune", NewMaintenanceController(newOrphanedStore()).Prune)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/maintenance/prune?dryRun=maybe", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Code)
+	}
+}
+
+func TestMaintenanceController_Integrity(t *testing.T) {
+	store := newOrphanedStore()
+	r := gin.New()
+	r.GET("/admin/integrity", NewMaintenanceController(store).Integrity)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/integrity", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp IntegrityResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.OK {
+		t.Error("expected ok false with dangling references")
+	}
+	if want := map[string]string{"orphan-container": "deleted", "orphan-group": "deleted-group"}; !reflect.DeepEqual(resp.Schedules, want) {
+		t.Errorf("expected dangling schedules %v, got %v", want, resp.Schedules)
+	}
+	if want := map[string][]string{"stack": {"gone-db", "gone-cache"}}; !reflect.DeepEqual(resp.GroupMembers, want) {
+		t.Errorf("expected dangling members %v, got %v", want, resp.GroupMembers)
+	}
+	if store.IsDirty() {
+		t.Error("expected the integrity report not to change the document")
+	}
+}
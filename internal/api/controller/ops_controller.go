@@ -0,0 +1,30 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/bassista/go_spin/internal/api/apierror"
+	"github.com/bassista/go_spin/internal/ops"
+	"github.com/gin-gonic/gin"
+)
+
+// OpsController reports the progress of the queued container starts and stops.
+type OpsController struct {
+	queue *ops.Queue
+}
+
+// NewOpsController creates a new OpsController.
+func NewOpsController(queue *ops.Queue) *OpsController {
+	return &OpsController{queue: queue}
+}
+
+// Get handles GET /ops/:id - returns the operation returned by a start/stop endpoint, while it is
+// queued or running and for a while after it finished.
+func (oc *OpsController) Get(c *gin.Context) {
+	op, ok := oc.queue.Get(c.Param("id"))
+	if !ok {
+		apierror.RespondCode(c, http.StatusNotFound, apierror.CodeOperationNotFound, "operation not found")
+		return
+	}
+	c.JSON(http.StatusOK, op)
+}
@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bassista/go_spin/internal/ops"
+	"github.com/gin-gonic/gin"
+)
+
+func TestOpsController_Get(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	rt := newMockRuntime()
+	queue := ops.NewQueue(context.Background(), rt, time.Minute)
+	appCtx := newTestAppCtx(rt, newMockStoreWithContainer("app"))
+	appCtx.Ops = queue
+	rc := NewRuntimeController(appCtx)
+	oc := NewOpsController(queue)
+
+	r := gin.New()
+	r.POST("/runtime/:name/start", rc.StartContainer)
+	r.GET("/ops/:id", oc.Get)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/runtime/app/start", nil))
+	var started ActionResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &started); err != nil || started.Operation == "" {
+		t.Fatalf("expected an operation ID, got %s err=%v", w.Body.String(), err)
+	}
+	if _, err := queue.Wait(context.Background(), started.Operation); err != nil {
+		t.Fatal(err)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ops/"+started.Operation, nil))
+	var op ops.Operation
+	if err := json.Unmarshal(w.Body.Bytes(), &op); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusOK || op.Container != "app" || op.Action != ops.ActionStart || op.Status != ops.StatusSucceeded {
+		t.Errorf("unexpected operation %d %+v", w.Code, op)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ops/999", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown operation, got %d", w.Code)
+	}
+}
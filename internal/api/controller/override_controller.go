@@ -0,0 +1,167 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bassista/go_spin/internal/api/apierror"
+	"github.com/bassista/go_spin/internal/cache"
+	"github.com/bassista/go_spin/internal/config"
+	"github.com/bassista/go_spin/internal/logger"
+	"github.com/bassista/go_spin/internal/scheduler"
+	"github.com/gin-gonic/gin"
+)
+
+// OverridesResponse lists the manual scheduler overrides in force.
+type OverridesResponse struct {
+	PausedSchedules map[string]time.Time `json:"pausedSchedules"` // schedule id -> paused since
+	Containers      map[string]time.Time `json:"containers"`      // container name -> stops suppressed until
+}
+
+// ContainerOverrideResponse is the override of a container.
+type ContainerOverrideResponse struct {
+	Container string    `json:"container"`
+	Until     time.Time `json:"until"`
+}
+
+// OverrideController manages the manual overrides of the scheduler: paused schedules and
+// containers whose schedule-driven stops are suppressed. Overrides are kept in memory.
+type OverrideController struct {
+	store     cache.ReadOnlyStore
+	overrides *scheduler.Overrides
+	loc       *time.Location
+	now       func() time.Time
+}
+
+// NewOverrideController creates a new OverrideController using the configured scheduling timezone.
+func NewOverrideController(store cache.ReadOnlyStore, overrides *scheduler.Overrides, cfg *config.Config) *OverrideController {
+	loc, err := cfg.Misc.SchedulingLocation()
+	if err != nil {
+		logger.WithComponent("override-controller").Warnf("invalid scheduling timezone, using Local: %v", err)
+		loc = time.Local
+	}
+	return &OverrideController{store: store, overrides: overrides, loc: loc, now: time.Now}
+}
+
+// List handles GET /overrides - returns the paused schedules and the container overrides in force.
+func (oc *OverrideController) List(c *gin.Context) {
+	c.JSON(http.StatusOK, OverridesResponse{
+		PausedSchedules: oc.overrides.Paused(),
+		Containers:      oc.overrides.Containers(oc.now().In(oc.loc)),
+	})
+}
+
+// PauseSchedule handles POST /schedule/:id/pause - the schedule stops starting and stopping its
+// containers until resumed. Containers also targeted by another schedule follow that one.
+// Returns 404 if the schedule does not exist.
+func (oc *OverrideController) PauseSchedule(c *gin.Context) {
+	id := c.Param("id")
+	logger.WithComponent("override-controller").Debugf("POST /schedule/%s/pause handler called", id)
+	if !oc.scheduleExists(c, id) {
+		return
+	}
+	oc.overrides.Pause(id, oc.now().In(oc.loc))
+	logger.WithComponent("override-controller").Infof("schedule %s paused", id)
+	c.JSON(http.StatusOK, PauseResponse{ScheduleID: id, Paused: true})
+}
+
+// ResumeSchedule handles POST /schedule/:id/resume - resumes a paused schedule.
+// Returns 404 if the schedule is not paused.
+func (oc *OverrideController) ResumeSchedule(c *gin.Context) {
+	id := c.Param("id")
+	logger.WithComponent("override-controller").Debugf("POST /schedule/%s/resume handler called", id)
+	if !oc.overrides.Resume(id) {
+		apierror.Respond(c, http.StatusNotFound, "schedule not paused")
+		return
+	}
+	logger.WithComponent("override-controller").Infof("schedule %s resumed", id)
+	c.JSON(http.StatusOK, PauseResponse{ScheduleID: id})
+}
+
+// SetContainerOverride handles POST /container/:name/override?until= - suppresses the
+// schedule-driven stops of the container until the given instant. until is RFC3339 or a duration
+// from now (e.g. "4h"), in the future and at most scheduler.MaxOverrideDuration away.
+// Returns 404 if the container does not exist.
+func (oc *OverrideController) SetContainerOverride(c *gin.Context) {
+	name := c.Param("name")
+	logger.WithComponent("override-controller").Debugf("POST /container/%s/override handler called", name)
+
+	now := oc.now().In(oc.loc)
+	until, err := parseOverrideUntil(c.Query("until"), now)
+	if err != nil {
+		apierror.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	doc, err := oc.store.Snapshot()
+	if err != nil {
+		logger.WithComponent("override-controller").Errorf("override %s: failed to read snapshot: %v", name, err)
+		apierror.Respond(c, http.StatusInternalServerError, "failed to read container list")
+		return
+	}
+	found := false
+	for _, container := range doc.Containers {
+		if container.Name == name {
+			found = true
+			break
+		}
+	}
+	if !found {
+		apierror.RespondCode(c, http.StatusNotFound, apierror.CodeContainerNotFound, fmt.Sprintf("container '%s' not found", name))
+		return
+	}
+
+	oc.overrides.SetContainer(name, until)
+	logger.WithComponent("override-controller").Infof("schedule stops of %s suppressed until %s", name, until.Format(time.RFC3339))
+	c.JSON(http.StatusOK, ContainerOverrideResponse{Container: name, Until: until})
+}
+
+// ClearContainerOverride handles DELETE /container/:name/override - removes the override.
+// Returns 404 if the container has none.
+func (oc *OverrideController) ClearContainerOverride(c *gin.Context) {
+	name := c.Param("name")
+	logger.WithComponent("override-controller").Debugf("DELETE /container/%s/override handler called", name)
+	if !oc.overrides.ClearContainer(name) {
+		apierror.Respond(c, http.StatusNotFound, "container override not found")
+		return
+	}
+	logger.WithComponent("override-controller").Infof("override of %s cleared", name)
+	c.JSON(http.StatusOK, ClearedResponse{Container: name, Cleared: true})
+}
+
+func (oc *OverrideController) scheduleExists(c *gin.Context, id string) bool {
+	doc, err := oc.store.Snapshot()
+	if err != nil {
+		logger.WithComponent("override-controller").Errorf("schedule %s: failed to read snapshot: %v", id, err)
+		apierror.Respond(c, http.StatusInternalServerError, "failed to read schedules")
+		return false
+	}
+	if !scheduler.HasSchedule(doc, id) {
+		apierror.RespondCode(c, http.StatusNotFound, apierror.CodeScheduleNotFound, "schedule not found")
+		return false
+	}
+	return true
+}
+
+// parseOverrideUntil reads the until query parameter: RFC3339 or a positive duration from now.
+func parseOverrideUntil(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, fmt.Errorf("missing until")
+	}
+	until, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		d, derr := time.ParseDuration(value)
+		if derr != nil {
+			return time.Time{}, fmt.Errorf("until must be RFC3339 or a duration such as 4h")
+		}
+		until = now.Add(d)
+	}
+	if !until.After(now) {
+		return time.Time{}, fmt.Errorf("until must be in the future")
+	}
+	if until.Sub(now) > scheduler.MaxOverrideDuration {
+		return time.Time{}, fmt.Errorf("until must be within %v", scheduler.MaxOverrideDuration)
+	}
+	return until.In(now.Location()), nil
+}
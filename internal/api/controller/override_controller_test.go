@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bassista/go_spin/internal/config"
+	"github.com/bassista/go_spin/internal/repository"
+	"github.com/bassista/go_spin/internal/scheduler"
+	"github.com/gin-gonic/gin"
+)
+
+func newOverrideTestRouter(overrides *scheduler.Overrides, now time.Time) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	store := &mockAppStore{doc: repository.DataDocument{
+		Containers: []repository.Container{{Name: "plex", Active: boolPtr(true)}},
+		Schedules:  []repository.Schedule{{ID: "nightly", Target: "plex", TargetType: "container"}},
+	}}
+	oc := NewOverrideController(store, overrides, &config.Config{Misc: config.MiscConfig{SchedulingTZ: "UTC"}})
+	oc.now = func() time.Time { return now }
+
+	r := gin.New()
+	r.GET("/overrides", oc.List)
+	r.POST("/schedule/:id/pause", oc.PauseSchedule)
+	r.POST("/schedule/:id/resume", oc.ResumeSchedule)
+	r.POST("/container/:name/override", oc.SetContainerOverride)
+	r.DELETE("/container/:name/override", oc.ClearContainerOverride)
+	return r
+}
+
+func TestOverrideController_PauseAndResume(t *testing.T) {
+	overrides := scheduler.NewOverrides()
+	r := newOverrideTestRouter(overrides, time.Now())
+	do := func(method, path string) int {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w.Code
+	}
+
+	if code := do(http.MethodPost, "/schedule/nightly/pause"); code != http.StatusOK {
+		t.Fatalf("expected 200 pausing, got %d", code)
+	}
+	if !overrides.IsPaused("nightly") {
+		t.Error("expected schedule paused")
+	}
+	if code := do(http.MethodPost, "/schedule/missing/pause"); code != http.StatusNotFound {
+		t.Errorf("expected 404 pausing an unknown schedule, got %d", code)
+	}
+	if code := do(http.MethodPost, "/schedule/nightly/resume"); code != http.StatusOK {
+		t.Errorf("expected 200 resuming, got %d", code)
+	}
+	if code := do(http.MethodPost, "/schedule/nightly/resume"); code != http.StatusNotFound {
+		t.Errorf("expected 404 resuming a schedule not paused, got %d", code)
+	}
+}
+
+func TestOverrideController_ContainerOverride(t *testing.T) {
+	now := time.Date(2024, 3, 18, 10, 0, 0, 0, time.UTC)
+	overrides := scheduler.NewOverrides()
+	r := newOverrideTestRouter(overrides, now)
+
+	tests := []struct {
+		query  string
+		status int
+	}{
+		{"?until=4h", http.StatusOK},
+		{"?until=2024-03-18T20:00:00Z", http.StatusOK},
+		{"", http.StatusBadRequest},
+		{"?until=yesterday", http.StatusBadRequest},
+		{"?until=-1h", http.StatusBadRequest},
+		{"?until=1000h", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/container/plex/override"+tt.query, nil))
+		if w.Code != tt.status {
+			t.Errorf("until %q: expected %d, got %d: %s", tt.query, tt.status, w.Code, w.Body.String())
+		}
+	}
+	if until, ok := overrides.ContainerUntil("plex", now); !ok || !until.Equal(time.Date(2024, 3, 18, 20, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the last override until 20:00, got %v %v", until, ok)
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/container/unknown/override?until=1h", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown container, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/overrides", nil))
+	var resp OverridesResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || len(resp.Containers) != 1 {
+		t.Errorf("expected the override listed, got %s (%v)", w.Body.String(), err)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/container/plex/override", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200 clearing the override, got %d", w.Code)
+	}
+	if _, ok := overrides.ContainerUntil("plex", now); ok {
+		t.Error("expected the override cleared")
+	}
+}
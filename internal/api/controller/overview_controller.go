@@ -0,0 +1,155 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/bassista/go_spin/internal/api/apierror"
+	"github.com/bassista/go_spin/internal/cache"
+	"github.com/bassista/go_spin/internal/config"
+	"github.com/bassista/go_spin/internal/logger"
+	"github.com/bassista/go_spin/internal/ops"
+	"github.com/bassista/go_spin/internal/runtime"
+	"github.com/bassista/go_spin/internal/scheduler"
+	"github.com/gin-gonic/gin"
+)
+
+// OverviewResponse is the aggregated view of GET /overview.
+type OverviewResponse struct {
+	GeneratedAt time.Time           `json:"generatedAt"`
+	Containers  []OverviewContainer `json:"containers"`
+}
+
+// OverviewContainer is a container as shown by a dashboard.
+type OverviewContainer struct {
+	Name         string `json:"name"`
+	FriendlyName string `json:"friendlyName,omitempty"`
+	Active       bool   `json:"active"`
+	Running      bool   `json:"running"`
+	// Error is set when the runtime could not tell whether the container runs.
+	Error string `json:"error,omitempty"`
+	// Stats is set for running containers when runtime.stats_enabled is on.
+	Stats *OverviewStats `json:"stats,omitempty"`
+	// Groups lists the groups having the container as member.
+	Groups []string `json:"groups"`
+	scheduler.NextEvent
+	// LastOperation is the last start or stop submitted for the container, until it is pruned.
+	LastOperation *ops.Operation `json:"lastOperation,omitempty"`
+}
+
+// OverviewStats is the resource usage of a running container.
+type OverviewStats struct {
+	CPUPercent float64 `json:"cpuPercent"`
+	MemoryMB   float64 `json:"memoryMb"`
+}
+
+// OverviewController serves the data of the dashboard in one request, correlating the document,
+// the runtime, the scheduler and the operation queue.
+type OverviewController struct {
+	store        cache.ReadOnlyStore
+	rt           runtime.ContainerRuntime
+	queue        *ops.Queue
+	loc          *time.Location
+	horizon      time.Duration // lookahead of the next scheduled events
+	statsEnabled bool
+	now          func() time.Time
+}
+
+// NewOverviewController creates a new OverviewController; queue may be nil, then no last
+// operation is reported.
+func NewOverviewController(store cache.ReadOnlyStore, rt runtime.ContainerRuntime, queue *ops.Queue, cfg *config.Config) *OverviewController {
+	loc, err := cfg.Misc.SchedulingLocation()
+	if err != nil {
+		logger.WithComponent("overview-controller").Warnf("invalid scheduling timezone, using Local: %v", err)
+		loc = time.Local
+	}
+	return &OverviewController{
+		store:        store,
+		rt:           rt,
+		queue:        queue,
+		loc:          loc,
+		horizon:      time.Duration(cfg.Data.NextEventsHorizonDays) * 24 * time.Hour,
+		statsEnabled: cfg.Runtime.StatsEnabled,
+		now:          time.Now,
+	}
+}
+
+// Get handles GET /overview - returns every container with its running state, stats, groups,
+// next scheduled start and stop and last operation. The runtime is queried in parallel.
+func (oc *OverviewController) Get(c *gin.Context) {
+	logger.WithComponent("overview-controller").Debugf("GET /overview handler called")
+	doc, err := oc.store.Snapshot()
+	if err != nil {
+		logger.WithComponent("overview-controller").Errorf("failed to read snapshot: %v", err)
+		apierror.Respond(c, http.StatusInternalServerError, "failed to read container list")
+		return
+	}
+	ctx := c.Request.Context()
+	now := oc.now().In(oc.loc)
+
+	next, err := scheduler.NextEvents(ctx, doc, now, oc.horizon, oc.loc)
+	if err != nil {
+		logger.WithComponent("overview-controller").Warnf("next events computation interrupted: %v", err)
+		apierror.Respond(c, http.StatusServiceUnavailable, "next events computation interrupted")
+		return
+	}
+
+	groups := make(map[string][]string, len(doc.Containers))
+	for _, group := range doc.Groups {
+		for _, member := range group.Container {
+			groups[member] = append(groups[member], group.Name)
+		}
+	}
+
+	containers := make([]OverviewContainer, len(doc.Containers))
+	var wg sync.WaitGroup
+	for i, container := range doc.Containers {
+		entry := OverviewContainer{
+			Name:         container.Name,
+			FriendlyName: container.FriendlyName,
+			Active:       container.Active != nil && *container.Active,
+			Groups:       groups[container.Name],
+			NextEvent:    next[container.Name],
+		}
+		if entry.Groups == nil {
+			entry.Groups = []string{}
+		}
+		if oc.queue != nil {
+			if op, ok := oc.queue.Last(container.Name); ok {
+				entry.LastOperation = &op
+			}
+		}
+		containers[i] = entry
+
+		wg.Add(1)
+		go func(entry *OverviewContainer) {
+			defer wg.Done()
+			oc.fillRuntime(ctx, entry)
+		}(&containers[i])
+	}
+	wg.Wait()
+
+	c.JSON(http.StatusOK, OverviewResponse{GeneratedAt: now, Containers: containers})
+}
+
+// fillRuntime sets the running state of entry and, when enabled, its stats.
+func (oc *OverviewController) fillRuntime(ctx context.Context, entry *OverviewContainer) {
+	running, err := oc.rt.IsRunning(ctx, entry.Name)
+	if err != nil {
+		logger.WithComponent("overview-controller").Warnf("failed to check container %s: %v", entry.Name, err)
+		entry.Error = err.Error()
+		return
+	}
+	entry.Running = running
+	if !running || !oc.statsEnabled {
+		return
+	}
+	stats, err := oc.rt.Stats(ctx, entry.Name)
+	if err != nil {
+		logger.WithComponent("overview-controller").Debugf("failed to get stats for container %s: %v", entry.Name, err)
+		return
+	}
+	entry.Stats = &OverviewStats{CPUPercent: stats.CPUPercent, MemoryMB: stats.MemoryMB}
+}
@@ -0,0 +1,96 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bassista/go_spin/internal/config"
+	"github.com/bassista/go_spin/internal/ops"
+	"github.com/bassista/go_spin/internal/repository"
+	"github.com/bassista/go_spin/internal/runtime"
+	"github.com/gin-gonic/gin"
+)
+
+func TestOverviewController_Get(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	rt := newMockRuntime()
+	rt.statsMap["web"] = runtime.ContainerStats{CPUPercent: 12.5, MemoryMB: 64}
+	queue := ops.NewQueue(ctx, rt, time.Minute)
+	if err := queue.Do(ctx, ops.ActionStart, "web"); err != nil {
+		t.Fatal(err)
+	}
+
+	store := &mockContainerStore{doc: repository.DataDocument{
+		Containers: []repository.Container{
+			{Name: "web", FriendlyName: "Web", Active: boolPtr(true)},
+			{Name: "db", FriendlyName: "DB", Active: boolPtr(false)},
+		},
+		Groups: []repository.Group{
+			{Name: "stack", Container: []string{"web", "db"}, Active: boolPtr(true)},
+			{Name: "site", Container: []string{"web"}, Active: boolPtr(true)},
+		},
+	}}
+	cfg := &config.Config{}
+	cfg.Runtime.StatsEnabled = true
+	oc := NewOverviewController(store, rt, queue, cfg)
+	r := gin.New()
+	r.GET("/api/overview", oc.Get)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/overview", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp OverviewResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(resp.Containers) != 2 {
+		t.Fatalf("expected 2 containers, got %d", len(resp.Containers))
+	}
+
+	web, db := resp.Containers[0], resp.Containers[1]
+	if !web.Running || !web.Active || len(web.Groups) != 2 {
+		t.Errorf("unexpected web entry: %+v", web)
+	}
+	if web.Stats == nil || web.Stats.CPUPercent != 12.5 {
+		t.Errorf("expected web stats, got %+v", web.Stats)
+	}
+	if web.LastOperation == nil || web.LastOperation.Status != ops.StatusSucceeded {
+		t.Errorf("expected the succeeded start as last operation, got %+v", web.LastOperation)
+	}
+	if db.Running || db.Active || db.Stats != nil || db.LastOperation != nil || len(db.Groups) != 1 {
+		t.Errorf("unexpected db entry: %+v", db)
+	}
+}
+
+func TestOverviewController_RuntimeError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	rt := newMockRuntime()
+	rt.isRunningErr = errors.New("daemon down")
+	store := &mockContainerStore{doc: repository.DataDocument{
+		Containers: []repository.Container{{Name: "web", Active: boolPtr(true)}},
+	}}
+	oc := NewOverviewController(store, rt, nil, &config.Config{})
+	r := gin.New()
+	r.GET("/api/overview", oc.Get)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/overview", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	var resp OverviewResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if got := resp.Containers[0]; got.Running || got.Error == "" || got.Groups == nil {
+		t.Errorf("expected the runtime error reported, got %+v", got)
+	}
+}
@@ -0,0 +1,327 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/bassista/go_spin/internal/logger"
+	"github.com/bassista/go_spin/internal/repository"
+	"github.com/bassista/go_spin/internal/runtime"
+)
+
+// readyProbeTimeout bounds a single readiness probe when the health check sets no timeout.
+const readyProbeTimeout = 1 * time.Second
+
+// Reasons reported by the Ready endpoint alongside ready=false.
+const (
+	reasonNotRunning        = "not_running"
+	reasonRuntimeError      = "runtime_error"
+	reasonNoURL             = "no_url"
+	reasonConnectionRefused = "connection_refused"
+	reasonTimeout           = "timeout"
+	reasonUnreachable       = "unreachable"
+	reasonBadStatusPrefix   = "bad_status:" // followed by the HTTP status code, e.g. "bad_status:500"
+	reasonExitCodePrefix    = "exit_code:"  // followed by the exit code of an exec health check
+	reasonExecUnsupported   = "exec_unsupported"
+)
+
+// normalizeProbeURL adds a default scheme and a trailing slash to a container URL.
+func normalizeProbeURL(rawURL string) string {
+	probeURL := rawURL
+	if !strings.HasPrefix(probeURL, "http://") && !strings.HasPrefix(probeURL, "https://") {
+		probeURL = "https://" + probeURL
+	}
+	if !strings.HasSuffix(probeURL, "/") {
+		probeURL = probeURL + "/"
+	}
+	return probeURL
+}
+
+// probeContainerURL performs a GET against the container URL, joined with the health check path,
+// and reports why it is not ready, or an empty reason when it answered with a status considered
+// ready (the health check expected statuses, by default 200 or a 307/308 redirect).
+func probeContainerURL(ctx context.Context, container *repository.Container, rawURL string) string {
+	name := container.Name
+	probeURL := normalizeProbeURL(rawURL)
+	var expected []int
+	if hc := container.HealthCheck; hc != nil {
+		if hc.Path != "" {
+			probeURL = strings.TrimSuffix(probeURL, "/") + "/" + strings.TrimPrefix(hc.Path, "/")
+		}
+		expected = hc.ExpectedStatus
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout(container))
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, probeURL, nil)
+	if err != nil {
+		logger.WithComponent("readiness").Warnf("failed to create request for %s and url %s: %v", name, probeURL, err)
+		return reasonUnreachable
+	}
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		logger.WithComponent("readiness").Warnf("request failed for %s and url %s: %v", name, probeURL, err)
+		return transportFailureReason(err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	logger.WithComponent("readiness").Debugf("request succeeded for %s and url %s with status %d", name, probeURL, resp.StatusCode)
+
+	if len(expected) > 0 {
+		if slices.Contains(expected, resp.StatusCode) {
+			return ""
+		}
+	} else if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusPermanentRedirect || resp.StatusCode == http.StatusTemporaryRedirect {
+		return ""
+	}
+	return reasonBadStatusPrefix + strconv.Itoa(resp.StatusCode)
+}
+
+// probeContainerTCP connects to the host and port of the container URL (80 or 443 by scheme
+// when the URL has no port) and reports why it is not ready, or an empty reason on success.
+func probeContainerTCP(ctx context.Context, container *repository.Container, rawURL string) string {
+	parsed, err := url.Parse(normalizeProbeURL(rawURL))
+	if err != nil || parsed.Hostname() == "" {
+		logger.WithComponent("readiness").Warnf("invalid tcp probe url for %s: %s", container.Name, rawURL)
+		return reasonUnreachable
+	}
+	port := parsed.Port()
+	if port == "" {
+		port = "443"
+		if parsed.Scheme == "http" {
+			port = "80"
+		}
+	}
+	address := net.JoinHostPort(parsed.Hostname(), port)
+
+	dialCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout(container))
+	defer cancel()
+	conn, err := (&net.Dialer{}).DialContext(dialCtx, "tcp", address)
+	if err != nil {
+		logger.WithComponent("readiness").Debugf("tcp probe failed for %s and address %s: %v", container.Name, address, err)
+		return transportFailureReason(err)
+	}
+	_ = conn.Close()
+	return ""
+}
+
+// probeContainerExec runs the health check command inside the container through the runtime and
+// reports why it is not ready, or an empty reason when the command exits with 0.
+func probeContainerExec(ctx context.Context, container *repository.Container, rt runtime.ContainerRuntime) string {
+	executor, ok := rt.(runtime.Executor)
+	if !ok {
+		return reasonExecUnsupported
+	}
+	execCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout(container))
+	defer cancel()
+	code, err := executor.Exec(execCtx, container.Name, container.HealthCheck.Command)
+	if err != nil {
+		logger.WithComponent("readiness").Debugf("exec health check failed for %s: %v", container.Name, err)
+		if errors.Is(err, context.DeadlineExceeded) {
+			return reasonTimeout
+		}
+		return reasonRuntimeError
+	}
+	if code != 0 {
+		return reasonExitCodePrefix + strconv.Itoa(code)
+	}
+	return ""
+}
+
+// healthCheckTimeout returns the timeout of a single readiness probe of the container.
+func healthCheckTimeout(container *repository.Container) time.Duration {
+	if container.HealthCheck != nil && container.HealthCheck.TimeoutMillis > 0 {
+		return time.Duration(container.HealthCheck.TimeoutMillis) * time.Millisecond
+	}
+	return readyProbeTimeout
+}
+
+// transportFailureReason classifies a failed probe request.
+func transportFailureReason(err error) string {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return reasonTimeout
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return reasonConnectionRefused
+	}
+	return reasonUnreachable
+}
+
+// readinessURLs returns the URLs to probe for a container: URL followed by ReadyURLs, skipping empty entries.
+func readinessURLs(container *repository.Container) []string {
+	urls := make([]string, 0, len(container.ReadyURLs)+1)
+	if container.URL != "" {
+		urls = append(urls, container.URL)
+	}
+	for _, u := range container.ReadyURLs {
+		if u != "" {
+			urls = append(urls, u)
+		}
+	}
+	return urls
+}
+
+// hasReadinessCheck reports whether the container can be probed: it asks the external checker,
+// runs an exec health check or has readiness URLs.
+func hasReadinessCheck(container *repository.Container) bool {
+	return container.ReadyCheckType == repository.ReadyCheckExternal ||
+		container.HealthCheckType() == repository.HealthCheckExec ||
+		len(readinessURLs(container)) > 0
+}
+
+// probeContainerReady probes all readiness URLs of a container in parallel, over HTTP or TCP as
+// its health check says, and combines the results according to ReadyMode ("all" by default, or
+// "any"). A container without URLs is not ready. Containers with an exec health check run it
+// through rt when execChecks is set, and are probed over HTTP otherwise (callers reached without
+// authentication); containers with ReadyCheckType "external" ask external instead (not ready when
+// it is nil).
+func probeContainerReady(ctx context.Context, container *repository.Container, external *externalReadyChecker, rt runtime.ContainerRuntime, execChecks bool) bool {
+	ready, _ := probeContainerReadiness(ctx, container, external, rt, execChecks)
+	return ready
+}
+
+// probeContainerReadiness is probeContainerReady also returning why the container is not ready:
+// the reason of the first failing URL, in URL then ReadyURLs order.
+func probeContainerReadiness(ctx context.Context, container *repository.Container, external *externalReadyChecker, rt runtime.ContainerRuntime, execChecks bool) (bool, string) {
+	if container.ReadyCheckType == repository.ReadyCheckExternal {
+		reason := external.check(ctx, container.Name)
+		return reason == "", reason
+	}
+	if execChecks && container.HealthCheckType() == repository.HealthCheckExec {
+		reason := probeContainerExec(ctx, container, rt)
+		return reason == "", reason
+	}
+	probe := probeContainerURL
+	if container.HealthCheckType() == repository.HealthCheckTCP {
+		probe = probeContainerTCP
+	}
+	urls := readinessURLs(container)
+	if len(urls) == 0 {
+		return false, reasonNoURL
+	}
+	if len(urls) == 1 {
+		reason := probe(ctx, container, urls[0])
+		return reason == "", reason
+	}
+
+	reasons := make([]string, len(urls))
+	var wg sync.WaitGroup
+	for i, u := range urls {
+		wg.Add(1)
+		go func(i int, rawURL string) {
+			defer wg.Done()
+			reasons[i] = probe(ctx, container, rawURL)
+		}(i, u)
+	}
+	wg.Wait()
+
+	readyCount := 0
+	firstReason := ""
+	for _, reason := range reasons {
+		if reason == "" {
+			readyCount++
+		} else if firstReason == "" {
+			firstReason = reason
+		}
+	}
+	logger.WithComponent("readiness").Debugf("container %s: %d/%d readiness URLs ready (mode: %s)", container.Name, readyCount, len(urls), container.ReadyMode)
+	if container.ReadyMode == repository.ReadyModeAny {
+		if readyCount > 0 {
+			return true, ""
+		}
+		return false, firstReason
+	}
+	if readyCount == len(urls) {
+		return true, ""
+	}
+	return false, firstReason
+}
+
+// readinessCache remembers probe results per container for a short time, so a stampede of
+// waiting pages polling the same container does not hammer its backend. Positive results are
+// kept for positiveTTL, negative ones for the (shorter) negativeTTL so recovery is noticed quickly.
+type readinessCache struct {
+	mu          sync.Mutex
+	entries     map[string]readinessEntry
+	positiveTTL time.Duration
+	negativeTTL time.Duration
+	now         func() time.Time
+}
+
+type readinessEntry struct {
+	ready   bool
+	reason  string
+	expires time.Time
+}
+
+func newReadinessCache(positiveTTL, negativeTTL time.Duration) *readinessCache {
+	return &readinessCache{
+		entries:     map[string]readinessEntry{},
+		positiveTTL: positiveTTL,
+		negativeTTL: negativeTTL,
+		now:         time.Now,
+	}
+}
+
+// get returns the cached result and not-ready reason for name if it has not expired.
+func (rc *readinessCache) get(name string) (bool, string, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	entry, ok := rc.entries[name]
+	if !ok {
+		return false, "", false
+	}
+	if !rc.now().Before(entry.expires) {
+		delete(rc.entries, name)
+		return false, "", false
+	}
+	return entry.ready, entry.reason, true
+}
+
+// put stores a probe result with the TTL matching its outcome; a zero TTL skips caching.
+func (rc *readinessCache) put(name string, ready bool, reason string) {
+	ttl := rc.negativeTTL
+	if ready {
+		ttl = rc.positiveTTL
+	}
+	if ttl <= 0 {
+		return
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.entries[name] = readinessEntry{ready: ready, reason: reason, expires: rc.now().Add(ttl)}
+}
+
+// Prune drops the expired results, returning how many it dropped.
+func (rc *readinessCache) Prune(now time.Time) int {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	removed := 0
+	for name, entry := range rc.entries {
+		if !now.Before(entry.expires) {
+			delete(rc.entries, name)
+			removed++
+		}
+	}
+	return removed
+}
+
+// Len returns the number of cached results, expired ones included until pruned.
+func (rc *readinessCache) Len() int {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return len(rc.entries)
+}
@@ -0,0 +1,48 @@
+package controller
+
+// ActionResponse is the body of the container start, stop and status endpoints.
+// Keys are camelCase; fields that do not apply to an endpoint are omitted.
+type ActionResponse struct {
+	// Name is the container the action refers to.
+	Name string `json:"name"`
+	// Message describes the outcome of start/stop, e.g. "container started".
+	Message string `json:"message,omitempty"`
+	// Operation is the ID of the queued start/stop (GET /ops/:id), empty when nothing was queued.
+	Operation string `json:"operation,omitempty"`
+	// Running is the live state, reported by the status endpoint only.
+	Running *bool `json:"running,omitempty"`
+}
+
+// GroupActionResponse is the body of the group start and stop endpoints.
+type GroupActionResponse struct {
+	ActionResponse
+	// Containers are the members the action was applied to, always present (possibly empty).
+	Containers []string `json:"containers"`
+	// Warnings lists members skipped because they are not configured.
+	Warnings []string `json:"warnings,omitempty"`
+	// Stages lists the start stages when members depend on each other (group start only).
+	Stages [][]string `json:"stages,omitempty"`
+}
+
+// ReadyResponse is the body of the container readiness endpoint.
+type ReadyResponse struct {
+	Ready bool `json:"ready"`
+	// Reason tells why the container is not ready.
+	Reason string `json:"reason,omitempty"`
+	// Error is the last recorded start error, e.g. a startup timeout.
+	Error string `json:"error,omitempty"`
+}
+
+// PauseResponse is the body of the schedule pause and resume endpoints.
+type PauseResponse struct {
+	ScheduleID string `json:"scheduleId"`
+	Paused     bool   `json:"paused"`
+}
+
+// ClearedResponse is the body of the endpoints removing a schedule extension or a container
+// override; only the key of the removed entry is set.
+type ClearedResponse struct {
+	ScheduleID string `json:"scheduleId,omitempty"`
+	Container  string `json:"container,omitempty"`
+	Cleared    bool   `json:"cleared"`
+}
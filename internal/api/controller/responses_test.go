@@ -0,0 +1,104 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/bassista/go_spin/internal/config"
+	"github.com/bassista/go_spin/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// responseKeys returns the sorted top-level JSON keys of body.
+func responseKeys(t *testing.T, body []byte) []string {
+	t.Helper()
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(body, &obj); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestActionResponses_ContainerKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		running  bool
+		wantKeys []string
+	}{
+		{name: "status running", method: http.MethodGet, path: "/runtime/app/status", running: true, wantKeys: []string{"name", "running"}},
+		{name: "status stopped", method: http.MethodGet, path: "/runtime/app/status", wantKeys: []string{"name", "running"}},
+		{name: "start", method: http.MethodPost, path: "/runtime/app/start", wantKeys: []string{"message", "name", "operation"}},
+		{name: "start running", method: http.MethodPost, path: "/runtime/app/start", running: true, wantKeys: []string{"message", "name"}},
+		{name: "stop", method: http.MethodPost, path: "/runtime/app/stop", wantKeys: []string{"message", "name"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := newMockRuntime()
+			rt.runningContainers["app"] = tt.running
+			rc := NewRuntimeController(newTestAppCtx(rt, newMockStoreWithContainer("app")))
+
+			r := gin.New()
+			r.GET("/runtime/:name/status", rc.IsRunning)
+			r.POST("/runtime/:name/start", rc.StartContainer)
+			r.POST("/runtime/:name/stop", rc.StopContainer)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d", w.Code)
+			}
+			if got := responseKeys(t, w.Body.Bytes()); !reflect.DeepEqual(got, tt.wantKeys) {
+				t.Errorf("expected keys %v, got %v", tt.wantKeys, got)
+			}
+		})
+	}
+}
+
+func TestActionResponses_GroupKeys(t *testing.T) {
+	store := &mockGroupStore{doc: repository.DataDocument{
+		Containers: []repository.Container{{Name: "c1"}},
+		Groups: []repository.Group{
+			{Name: "full", Container: []string{"c1"}, Active: boolPtr(true)},
+			{Name: "partial", Container: []string{"c1", "missing"}, Active: boolPtr(true)},
+		},
+	}}
+	gc := NewGroupController(context.Background(), store, &mockGroupRuntime{}, nil, &config.Config{})
+
+	r := gin.New()
+	r.POST("/group/:name/start", gc.StartGroup)
+	r.POST("/group/:name/stop", gc.StopGroup)
+
+	tests := []struct {
+		name     string
+		path     string
+		wantKeys []string
+	}{
+		{name: "start", path: "/group/full/start", wantKeys: []string{"containers", "message", "name"}},
+		{name: "start with missing member", path: "/group/partial/start", wantKeys: []string{"containers", "message", "name", "warnings"}},
+		{name: "stop", path: "/group/full/stop", wantKeys: []string{"containers", "message", "name"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, tt.path, nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d", w.Code)
+			}
+			if got := responseKeys(t, w.Body.Bytes()); !reflect.DeepEqual(got, tt.wantKeys) {
+				t.Errorf("expected keys %v, got %v", tt.wantKeys, got)
+			}
+		})
+	}
+}
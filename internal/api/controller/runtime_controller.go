@@ -2,63 +2,151 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	htmlpkg "html"
 	"net/http"
-	"os"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/bassista/go_spin/internal/api/apierror"
+	"github.com/bassista/go_spin/internal/api/middleware"
 	"github.com/bassista/go_spin/internal/app"
+	"github.com/bassista/go_spin/internal/audit"
 	"github.com/bassista/go_spin/internal/cache"
 	"github.com/bassista/go_spin/internal/config"
 	"github.com/bassista/go_spin/internal/logger"
+	"github.com/bassista/go_spin/internal/metrics"
+	"github.com/bassista/go_spin/internal/ops"
 	"github.com/bassista/go_spin/internal/repository"
 	"github.com/bassista/go_spin/internal/runtime"
+	"github.com/bassista/go_spin/internal/waiting"
 	"github.com/gin-gonic/gin"
 )
 
 // DefaultWaitingTemplatePath is the default path for the waiting page template.
-const DefaultWaitingTemplatePath = "./ui/templates/waiting.html"
+const DefaultWaitingTemplatePath = waiting.DefaultTemplatePath
+
+// defaultStartupProbeInterval is used when data.startup_probe_interval_millis is not set.
+const defaultStartupProbeInterval = time.Second
+
+// defaultURLScheme is used when misc.default_url_scheme is not set.
+const defaultURLScheme = "http://"
+
+// Wait endpoint bounds: the timeout query parameter is in seconds.
+const (
+	defaultWaitTimeout = 60 * time.Second
+	maxWaitTimeout     = 10 * time.Minute
+	waitPollInterval   = 500 * time.Millisecond
+	// waitWriteSlack extends the response write deadline past the wait timeout.
+	waitWriteSlack = 5 * time.Second
+)
+
+// Desired states accepted by the wait endpoint.
+const (
+	waitStateRunning = "running"
+	waitStateStopped = "stopped"
+)
+
+// defaultInactiveStatus answers waiting page hits for inactive entities when misc.inactive_status is not set.
+const defaultInactiveStatus = http.StatusForbidden
+
+// waitingUnavailableWait keeps serving the polling waiting page when the runtime is unreachable
+// (misc.waiting_runtime_unavailable); any other value renders the 503 page.
+const waitingUnavailableWait = "wait"
+
+// unavailableRetryAfterSecs is sent as Retry-After with the runtime unavailable page.
+const unavailableRetryAfterSecs = 30
+
+// unavailablePageHTML is rendered instead of the waiting page when the runtime is unreachable.
+const unavailablePageHTML = `<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Service temporarily unavailable</title></head>
+<body>
+<h1>Service temporarily unavailable</h1>
+<p>%s cannot be started right now because the container runtime is unreachable. Please try again later.</p>
+</body>
+</html>
+`
+
+// startupTimeoutError is recorded as Container.LastError when a start never becomes ready.
+const startupTimeoutError = "startup timeout"
 
 type RuntimeController struct {
 	runtime         runtime.ContainerRuntime
+	ops             *ops.Queue // starts and stops, coalesced with the other components
 	containerStore  cache.ContainerStore
+	readStore       cache.ReadOnlyStore // serves AllStats, a read replica when enabled
+	metrics         *metrics.History    // per-container stats history, nil when disabled
+	waitPoll        time.Duration       // runtime polling interval of the wait endpoint
+	waitingStarts   *startLimiter       // de-duplicates and bounds the starts triggered by the waiting page
+	externalReady   *externalReadyChecker
 	config          *config.Config
 	baseCtx         context.Context
-	waitingTemplate string
+	waitingTemplate *waiting.Template
+	pageTemplates   *waiting.FileCache // per-entry templates, next to the global one
+	// execChecks runs exec health checks, only enabled on routes behind authentication
+	execChecks bool
 }
 
 // NewRuntimeController creates a new RuntimeController with the waiting template loaded from file.
+// The template is the one shared through the App, so updates from the API reach every server.
 func NewRuntimeController(appCtx *app.App) *RuntimeController {
-	templateContent, err := os.ReadFile(DefaultWaitingTemplatePath)
-	if err != nil {
-		logger.WithComponent("runtime_controller").Warnf("failed to load waiting template from %s: %v", DefaultWaitingTemplatePath, err)
-		templateContent = []byte("<!-- template not found -->")
-	} else {
-		logger.WithComponent("runtime_controller").Infof("loaded waiting template from %s", DefaultWaitingTemplatePath)
+	template := appCtx.WaitingTemplate
+	if template == nil {
+		template = waiting.LoadTemplate(DefaultWaitingTemplatePath)
+	}
+
+	var readStore cache.ReadOnlyStore = appCtx.Cache
+	if appCtx.ReadCache != nil {
+		readStore = appCtx.ReadCache
+	}
+
+	queue := appCtx.Ops
+	if queue == nil {
+		queue = ops.NewQueue(appCtx.BaseCtx, appCtx.Runtime, appCtx.Config.Data.OperationsRetention)
 	}
 
 	return &RuntimeController{
 		runtime:         appCtx.Runtime,
+		ops:             queue,
 		containerStore:  appCtx.Cache,
+		readStore:       readStore,
+		metrics:         appCtx.Metrics,
+		waitPoll:        waitPollInterval,
+		waitingStarts:   newStartLimiter(appCtx.Config.Misc.WaitingStartConcurrency),
+		externalReady:   newExternalReadyChecker(appCtx.Config.Misc.ExternalReadyURL, appCtx.Config.Misc.ExternalReadyTimeout),
 		baseCtx:         appCtx.BaseCtx,
 		config:          appCtx.Config,
-		waitingTemplate: string(templateContent),
+		waitingTemplate: template,
+		pageTemplates:   waiting.NewFileCache(filepath.Dir(template.Path())),
 	}
 }
 
+// SetExecHealthChecks enables the exec health checks, which run commands inside containers: they
+// are left disabled, and the container URLs probed instead, on routes reached without authentication.
+func (rc *RuntimeController) SetExecHealthChecks(enabled bool) {
+	rc.execChecks = enabled
+}
+
 // IsRunning checks if a container is currently running.
 func (rc *RuntimeController) IsRunning(c *gin.Context) {
 	name := c.Param("name")
 	if name == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "missing container name"})
+		apierror.Respond(c, http.StatusBadRequest, "missing container name")
 		return
 	}
 
 	// Check if container exists in cache
 	doc, err := rc.containerStore.Snapshot()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read container list"})
+		apierror.Respond(c, http.StatusInternalServerError, "failed to read container list")
 		return
 	}
 
@@ -70,40 +158,36 @@ func (rc *RuntimeController) IsRunning(c *gin.Context) {
 		}
 	}
 	if !containerExists {
-		c.JSON(http.StatusNotFound, gin.H{"error": "container not found"})
+		apierror.RespondCode(c, http.StatusNotFound, apierror.CodeContainerNotFound, "container not found")
 		return
 	}
 
 	running, err := rc.runtime.IsRunning(c.Request.Context(), name)
 	if err != nil {
-		// Check if error is "container not found"
-		if strings.Contains(err.Error(), "not found") {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		if runtime.IsNotFound(err) {
+			apierror.RespondError(c, http.StatusNotFound, err)
 			return
 		}
 		logger.WithComponent("runtime_controller").Errorf("failed to check if container %s is running: %v", name, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Unable to determine container running state"})
+		apierror.Respond(c, http.StatusInternalServerError, "Unable to determine container running state")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"name":    name,
-		"running": running,
-	})
+	c.JSON(http.StatusOK, ActionResponse{Name: name, Running: &running})
 }
 
 // StartContainer starts a container by name.
 func (rc *RuntimeController) StartContainer(c *gin.Context) {
 	name := c.Param("name")
 	if name == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "missing container name"})
+		apierror.Respond(c, http.StatusBadRequest, "missing container name")
 		return
 	}
 
 	// Check if container exists in cache
 	doc, err := rc.containerStore.Snapshot()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read container list"})
+		apierror.Respond(c, http.StatusInternalServerError, "failed to read container list")
 		return
 	}
 
@@ -115,7 +199,7 @@ func (rc *RuntimeController) StartContainer(c *gin.Context) {
 		}
 	}
 	if !containerExists {
-		c.JSON(http.StatusNotFound, gin.H{"error": "container not found"})
+		apierror.RespondCode(c, http.StatusNotFound, apierror.CodeContainerNotFound, "container not found")
 		return
 	}
 
@@ -124,8 +208,8 @@ func (rc *RuntimeController) StartContainer(c *gin.Context) {
 	if err != nil {
 		logger.WithComponent("runtime_controller").Warnf("failed to check if container %s is running: %v", name, err)
 
-		if strings.Contains(err.Error(), "not found") {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Container not found"})
+		if runtime.IsNotFound(err) {
+			apierror.RespondCode(c, http.StatusNotFound, apierror.CodeContainerNotFound, "Container not found")
 			return
 		}
 
@@ -133,28 +217,26 @@ func (rc *RuntimeController) StartContainer(c *gin.Context) {
 		running = false
 	}
 
+	var op ops.Operation
 	if !running {
-		rc.startContainerInBackground(name)
+		op = rc.startContainerInBackground(rc.background(c), name)
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"name":    name,
-		"message": "container started",
-	})
+	c.JSON(http.StatusOK, ActionResponse{Name: name, Message: "container started", Operation: op.ID})
 }
 
 // StopContainer stops a container by name.
 func (rc *RuntimeController) StopContainer(c *gin.Context) {
 	name := c.Param("name")
 	if name == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "missing container name"})
+		apierror.Respond(c, http.StatusBadRequest, "missing container name")
 		return
 	}
 
 	// Check if container exists in cache
 	doc, err := rc.containerStore.Snapshot()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read container list"})
+		apierror.Respond(c, http.StatusInternalServerError, "failed to read container list")
 		return
 	}
 
@@ -166,7 +248,7 @@ func (rc *RuntimeController) StopContainer(c *gin.Context) {
 		}
 	}
 	if !containerExists {
-		c.JSON(http.StatusNotFound, gin.H{"error": "container not found"})
+		apierror.RespondCode(c, http.StatusNotFound, apierror.CodeContainerNotFound, "container not found")
 		return
 	}
 
@@ -175,8 +257,8 @@ func (rc *RuntimeController) StopContainer(c *gin.Context) {
 	if err != nil {
 		logger.WithComponent("runtime_controller").Warnf("failed to check if container %s is running: %v", name, err)
 
-		if strings.Contains(err.Error(), "not found") {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Container not found"})
+		if runtime.IsNotFound(err) {
+			apierror.RespondCode(c, http.StatusNotFound, apierror.CodeContainerNotFound, "Container not found")
 			return
 		}
 
@@ -184,26 +266,135 @@ func (rc *RuntimeController) StopContainer(c *gin.Context) {
 		running = true
 	}
 
+	var op ops.Operation
 	if running {
-		rc.stopContainerInBackground(name)
+		op = rc.stopContainerInBackground(rc.background(c), name)
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"name":    name,
-		"message": "container stopped",
-	})
+	c.JSON(http.StatusOK, ActionResponse{Name: name, Message: "container stopped", Operation: op.ID})
+}
+
+// WaitResponse is returned by the wait endpoint.
+type WaitResponse struct {
+	Name    string `json:"name"`
+	Reached bool   `json:"reached"`
+	State   string `json:"state"` // last observed state: "running" or "stopped"
 }
 
-// stopContainerInBackground stops a container in a dedicated goroutine.
-func (rc *RuntimeController) stopContainerInBackground(containerName string) {
+// Wait handles POST /runtime/:name/wait?state=running|stopped&timeout=60.
+// It starts (or stops) the container when needed, then polls the runtime until the desired state
+// is observed or timeout seconds elapse. A client disconnect aborts the wait.
+func (rc *RuntimeController) Wait(c *gin.Context) {
+	name := c.Param("name")
+	state := c.DefaultQuery("state", waitStateRunning)
+	if state != waitStateRunning && state != waitStateStopped {
+		apierror.Respond(c, http.StatusBadRequest, "state must be 'running' or 'stopped'")
+		return
+	}
+	timeout := defaultWaitTimeout
+	if v := c.Query("timeout"); v != "" {
+		secs, err := strconv.Atoi(v)
+		if err != nil || secs <= 0 || time.Duration(secs)*time.Second > maxWaitTimeout {
+			apierror.Respond(c, http.StatusBadRequest, fmt.Sprintf("timeout must be between 1 and %d seconds", int(maxWaitTimeout/time.Second)))
+			return
+		}
+		timeout = time.Duration(secs) * time.Second
+	}
+
+	doc, err := rc.containerStore.Snapshot()
+	if err != nil {
+		apierror.Respond(c, http.StatusInternalServerError, "failed to read container list")
+		return
+	}
+	containerExists := false
+	for _, container := range doc.Containers {
+		if container.Name == name {
+			containerExists = true
+			break
+		}
+	}
+	if !containerExists {
+		apierror.RespondCode(c, http.StatusNotFound, apierror.CodeContainerNotFound, "container not found")
+		return
+	}
+
+	// The wait may outlast server.write_timeout_secs: extend the deadline of this response only.
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Now().Add(timeout + waitWriteSlack)); err != nil {
+		logger.WithComponent("runtime_controller").Debugf("wait: cannot extend write deadline: %v", err)
+	}
+
+	// The request context is cancelled when the client goes away.
+	ctx := c.Request.Context()
+	wantRunning := state == waitStateRunning
+	running, err := rc.runtime.IsRunning(ctx, name)
+	if err != nil {
+		if runtime.IsNotFound(err) {
+			apierror.RespondError(c, http.StatusNotFound, err)
+			return
+		}
+		logger.WithComponent("runtime_controller").Warnf("wait: failed to check if container %s is running: %v", name, err)
+		running = !wantRunning
+	}
+	if running != wantRunning {
+		if wantRunning {
+			rc.startContainerInBackground(rc.background(c), name)
+			reportAudit(c, audit.ActionStart, audit.KindContainer, name)
+		} else {
+			rc.stopContainerInBackground(rc.background(c), name)
+			reportAudit(c, audit.ActionStop, audit.KindContainer, name)
+		}
+	}
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(rc.waitPoll)
+	defer ticker.Stop()
+	for running != wantRunning {
+		select {
+		case <-ctx.Done():
+			logger.WithComponent("runtime_controller").Debugf("wait for %s aborted: %v", name, ctx.Err())
+			return
+		case <-deadline.C:
+			c.JSON(http.StatusOK, WaitResponse{Name: name, Reached: false, State: runningState(running)})
+			return
+		case <-ticker.C:
+			if observed, err := rc.runtime.IsRunning(ctx, name); err != nil {
+				logger.WithComponent("runtime_controller").Debugf("wait: failed to check if container %s is running: %v", name, err)
+			} else {
+				running = observed
+			}
+		}
+	}
+	c.JSON(http.StatusOK, WaitResponse{Name: name, Reached: true, State: runningState(running)})
+}
+
+func runningState(running bool) string {
+	if running {
+		return waitStateRunning
+	}
+	return waitStateStopped
+}
+
+// background returns the context of the goroutines started by the request of c: they outlive the
+// request, so they run under the application context, and log with its request ID.
+func (rc *RuntimeController) background(c *gin.Context) context.Context {
+	return logger.Detach(rc.baseCtx, c.Request.Context())
+}
+
+// stopContainerInBackground queues the stop of a container and returns its operation; a
+// dedicated goroutine records the outcome.
+func (rc *RuntimeController) stopContainerInBackground(ctx context.Context, containerName string) ops.Operation {
+	logger.WithContext(ctx, "runtime_controller").Infof("stopping container %s in background", containerName)
+	op := rc.ops.Submit(ops.ActionStop, containerName)
 	go func(name string) {
-		logger.WithComponent("runtime_controller").Infof("stopping container %s in background", name)
-		if err := rc.runtime.Stop(rc.baseCtx, name); err != nil {
-			logger.WithComponent("runtime_controller").Errorf("failed to stop container %s in background: %v", name, err)
+		if _, err := rc.ops.Wait(ctx, op.ID); err != nil {
+			logger.WithContext(ctx, "runtime_controller").Errorf("failed to stop container %s in background: %v", name, err)
 		} else {
-			logger.WithComponent("runtime_controller").Infof("container %s stopped successfully", name)
+			logger.WithContext(ctx, "runtime_controller").Infof("container %s stopped successfully", name)
+			cache.RecordRuntimeState(rc.containerStore, name, false, time.Now())
 		}
 	}(containerName)
+	return op
 }
 
 // WaitingPage serves a waiting HTML page for a container or group.
@@ -212,14 +403,14 @@ func (rc *RuntimeController) stopContainerInBackground(containerName string) {
 func (rc *RuntimeController) WaitingPage(c *gin.Context) {
 	name := c.Param("name")
 	if name == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "missing container or group name"})
+		apierror.Respond(c, http.StatusBadRequest, "missing container or group name")
 		return
 	}
 
 	doc, err := rc.containerStore.Snapshot()
 	if err != nil {
 		logger.WithComponent("runtime_controller").Errorf("failed to read container list: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read container list"})
+		apierror.Respond(c, http.StatusInternalServerError, "failed to read container list")
 		return
 	}
 
@@ -256,7 +447,73 @@ func (rc *RuntimeController) WaitingPage(c *gin.Context) {
 	}
 
 	// Not found as container or group
-	c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("container or group '%s' not found", name)})
+	apierror.Respond(c, http.StatusNotFound, fmt.Sprintf("container or group '%s' not found", name))
+}
+
+// GoTo handles GET /go/:name - a stable "open in browser" link. It starts the container in
+// background when it is not running and immediately redirects (302) to its URL, preserving
+// the incoming query string. Unlike the waiting page it never waits for readiness.
+// Returns 404 if the container is not found, 403 if not active.
+func (rc *RuntimeController) GoTo(c *gin.Context) {
+	name := c.Param("name")
+	if name == "" {
+		apierror.Respond(c, http.StatusBadRequest, "missing container name")
+		return
+	}
+
+	doc, err := rc.containerStore.Snapshot()
+	if err != nil {
+		logger.WithComponent("runtime_controller").Errorf("failed to read container list: %v", err)
+		apierror.Respond(c, http.StatusInternalServerError, "failed to read container list")
+		return
+	}
+
+	container, found := rc.findContainer(doc, name)
+	if !found {
+		apierror.RespondCode(c, http.StatusNotFound, apierror.CodeContainerNotFound, fmt.Sprintf("container '%s' not found", name))
+		return
+	}
+	if container.Active == nil || !*container.Active {
+		apierror.RespondCode(c, http.StatusForbidden, apierror.CodeInactive, fmt.Sprintf("container '%s' is not active", container.Name))
+		return
+	}
+
+	running, err := rc.runtime.IsRunning(c.Request.Context(), container.Name)
+	if err != nil {
+		logger.WithComponent("runtime_controller").Warnf("failed to check if container %s is running: %v", container.Name, err)
+		running = false
+	}
+	if !running {
+		rc.startContainerInBackground(rc.background(c), container.Name)
+		reportAudit(c, audit.ActionStart, audit.KindContainer, container.Name)
+	}
+
+	target := withQuery(rc.redirectURL(container.URL), c.Request.URL.RawQuery)
+	logger.WithComponent("runtime_controller").Debugf("go: redirecting %s to %s (running=%v)", container.Name, target, running)
+	c.Redirect(http.StatusFound, target)
+}
+
+// reportAudit reports action on the entity of kind named name to the audit log middleware, for
+// the pages starting containers only when they are not running.
+func reportAudit(c *gin.Context, action, kind, name string) {
+	middleware.ReportAudit(c, audit.Entry{Action: action, Kind: kind, Name: name})
+}
+
+// withQuery appends rawQuery to target, merging with any query target already has.
+func withQuery(target, rawQuery string) string {
+	if rawQuery == "" {
+		return target
+	}
+	base, fragment, hasFragment := strings.Cut(target, "#")
+	sep := "?"
+	if strings.Contains(base, "?") {
+		sep = "&"
+	}
+	out := base + sep + rawQuery
+	if hasFragment {
+		out += "#" + fragment
+	}
+	return out
 }
 
 // findContainer searches for a container by name in the data document.
@@ -289,7 +546,10 @@ func (rc *RuntimeController) findGroup(doc repository.DataDocument, name string)
 func (rc *RuntimeController) handleContainerWaitingPage(c *gin.Context, container *repository.Container) {
 	// Check if container is active
 	if container.Active == nil || !*container.Active {
-		c.JSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf("container '%s' is not active", container.Name)})
+		rc.respondInactive(c, fmt.Sprintf("container '%s' is not active", container.Name))
+		return
+	}
+	if rc.abortIfNotAllowed(c, container.Name, container.AllowedUsers) {
 		return
 	}
 
@@ -297,29 +557,110 @@ func (rc *RuntimeController) handleContainerWaitingPage(c *gin.Context, containe
 	running, err := rc.runtime.IsRunning(c.Request.Context(), container.Name)
 	if err != nil {
 		logger.WithComponent("runtime_controller").Warnf("failed to check if container %s is running: %v", container.Name, err)
+		if rc.abortIfRuntimeUnavailable(c, container.Name, err) {
+			return
+		}
 		// Assume not running and try to start
 		running = false
 	}
 
 	if !running {
-		rc.startContainerInBackground(container.Name)
+		rc.startFromWaitingPage(rc.background(c), container.Name)
+		reportAudit(c, audit.ActionStart, audit.KindContainer, container.Name)
+	} else if rc.redirectIfReady(c, container) {
+		return
+	}
+
+	if rc.config.Misc.WaitingReportDependencies && len(container.DependsOn) > 0 && wantsJSON(c) {
+		rc.serveDependencyWaiting(c, container)
+		return
 	}
 
 	// Serve the waiting page
-	rc.serveWaitingPage(c, container.Name, container.URL)
+	rc.serveWaitingPage(c, container.Name, container.URL, rc.waitingMessage(container, container.WaitingPage), container.WaitingPage)
+}
+
+// serveDependencyWaiting serves the JSON waiting document with the dependencies the container is
+// still waiting on, answering 425 Too Early while at least one of them is not ready.
+func (rc *RuntimeController) serveDependencyWaiting(c *gin.Context, container *repository.Container) {
+	waitingOn := rc.dependenciesNotReady(c.Request.Context(), container.DependsOn)
+	status := http.StatusOK
+	if len(waitingOn) > 0 {
+		status = http.StatusTooEarly
+	}
+	c.JSON(status, WaitingResponse{
+		Name:        container.Name,
+		RedirectURL: rc.redirectURL(container.URL),
+		Message:     rc.waitingMessage(container, container.WaitingPage),
+		MaxWaitSecs: rc.maxWaitSecs(),
+		Starting:    true,
+		WaitingOn:   waitingOn,
+	})
+}
+
+// dependenciesNotReady probes the given dependencies in parallel and returns, in dependsOn order,
+// those that are unknown, not running or not ready.
+func (rc *RuntimeController) dependenciesNotReady(ctx context.Context, dependsOn []string) []string {
+	doc, err := rc.containerStore.Snapshot()
+	if err != nil {
+		logger.WithComponent("runtime_controller").Warnf("dependency check: failed to read container list: %v", err)
+		return dependsOn
+	}
+
+	byName := make(map[string]*repository.Container, len(doc.Containers))
+	for i := range doc.Containers {
+		byName[doc.Containers[i].Name] = &doc.Containers[i]
+	}
+
+	ready := make([]bool, len(dependsOn))
+	var wg sync.WaitGroup
+	for i, depName := range dependsOn {
+		dep, found := byName[depName]
+		if !found {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, dep *repository.Container) {
+			defer wg.Done()
+			running, err := rc.runtime.IsRunning(ctx, dep.Name)
+			ready[i] = err == nil && running && probeContainerReady(ctx, dep, rc.externalReady, rc.runtime, rc.execChecks)
+		}(i, dep)
+	}
+	wg.Wait()
+
+	var waitingOn []string
+	for i, depName := range dependsOn {
+		if !ready[i] {
+			waitingOn = append(waitingOn, depName)
+		}
+	}
+	return waitingOn
 }
 
 // handleGroupWaitingPage handles the waiting page for a group of containers.
 func (rc *RuntimeController) handleGroupWaitingPage(c *gin.Context, doc repository.DataDocument, group *repository.Group) {
 	// Check if group is active
 	if group.Active == nil || !*group.Active {
-		c.JSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf("group '%s' is not active", group.Name)})
+		rc.respondInactive(c, fmt.Sprintf("group '%s' is not active", group.Name))
+		return
+	}
+	if rc.abortIfNotAllowed(c, group.Name, group.AllowedUsers) {
 		return
 	}
 
+	// In strict mode a group referencing unknown containers is rejected instead of partially started
+	if rc.config.Data.StrictGroups {
+		if missing := missingGroupMembers(doc, *group); len(missing) > 0 {
+			logger.WithComponent("runtime_controller").Warnf("group %s references missing containers: %v", group.Name, missing)
+			apierror.RespondDetails(c, http.StatusBadRequest, apierror.CodeDanglingReference,
+				fmt.Sprintf("group '%s' references missing containers", group.Name), gin.H{"missing": missing})
+			return
+		}
+	}
+
 	// Find the first container in the group to get the redirect URL
 	if len(group.Container) == 0 {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("group '%s' has no containers", group.Name)})
+		apierror.Respond(c, http.StatusInternalServerError, fmt.Sprintf("group '%s' has no containers", group.Name))
 		return
 	}
 
@@ -333,12 +674,16 @@ func (rc *RuntimeController) handleGroupWaitingPage(c *gin.Context, doc reposito
 	}
 
 	if firstContainer == nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("no valid containers found in group '%s'", group.Name)})
+		apierror.Respond(c, http.StatusInternalServerError, fmt.Sprintf("no valid containers found in group '%s'", group.Name))
 		return
 	}
 
-	// Start all containers in the group that are not running (in background)
-	for _, containerName := range group.Container {
+	// Start all containers in the group that are not running (in background), dependencies first
+	// and by start priority within a stage
+	allRunning := true
+	members := make([]string, 0, len(group.Container))
+	notRunning := map[string]bool{}
+	for _, containerName := range groupStartOrder(doc, *group) {
 		container, found := rc.findContainer(doc, containerName)
 		if !found {
 			logger.WithComponent("runtime_controller").Warnf("container %s in group %s not found", containerName, group.Name)
@@ -354,51 +699,532 @@ func (rc *RuntimeController) handleGroupWaitingPage(c *gin.Context, doc reposito
 		running, err := rc.runtime.IsRunning(c.Request.Context(), containerName)
 		if err != nil {
 			logger.WithComponent("runtime_controller").Warnf("failed to check if container %s is running: %v", containerName, err)
+			if rc.abortIfRuntimeUnavailable(c, group.Name, err) {
+				return
+			}
 			running = false
 		}
 
+		members = append(members, container.Name)
 		if !running {
-			rc.startContainerInBackground(containerName)
+			allRunning = false
+			notRunning[container.Name] = true
 		}
 	}
 
+	if !allRunning {
+		reportAudit(c, audit.ActionStart, audit.KindGroup, group.Name)
+		if stages := groupStartStages(doc, members); len(stages) > 1 {
+			rc.startStagesFromWaitingPage(rc.background(c), doc, *group, stages, notRunning)
+		} else {
+			for _, name := range members {
+				if notRunning[name] {
+					rc.startFromWaitingPage(rc.background(c), name)
+				}
+			}
+		}
+	}
+
+	if allRunning && rc.redirectIfGroupReady(c, doc, *group) {
+		return
+	}
+
 	// Serve the waiting page with the group name and first container's URL
-	rc.serveWaitingPage(c, group.Name, firstContainer.URL)
+	page := group.WaitingPage
+	if page == nil {
+		page = firstContainer.WaitingPage
+	}
+	rc.serveWaitingPage(c, group.Name, firstContainer.URL, rc.waitingMessage(firstContainer, page), page)
 }
 
-// startContainerInBackground starts a container in a dedicated goroutine.
-func (rc *RuntimeController) startContainerInBackground(containerName string) {
+// startContainerInBackground queues the start of a container and returns its operation; a
+// dedicated goroutine waits for it and, when the container defines a startup timeout, for the
+// container to become ready.
+func (rc *RuntimeController) startContainerInBackground(ctx context.Context, containerName string) ops.Operation {
+	op := rc.queueStart(ctx, containerName)
 	go func(name string) {
-		logger.WithComponent("runtime_controller").Infof("starting container %s in background", name)
-		if err := rc.runtime.Start(rc.baseCtx, name); err != nil {
-			logger.WithComponent("runtime_controller").Errorf("failed to start container %s in background: %v", name, err)
-		} else {
-			logger.WithComponent("runtime_controller").Infof("container %s started successfully", name)
+		if rc.awaitStart(ctx, name, op) {
+			rc.watchStartup(ctx, name)
 		}
 	}(containerName)
+	return op
 }
 
-// serveWaitingPage renders the waiting HTML template with placeholders replaced.
-func (rc *RuntimeController) serveWaitingPage(c *gin.Context, containerName, redirectURL string) {
-	html := rc.waitingTemplate
-	html = strings.ReplaceAll(html, "{{CONTAINER_NAME}}", containerName)
-	html = strings.ReplaceAll(html, "{{REDIRECT_URL}}", redirectURL)
+// startFromWaitingPage is startContainerInBackground for waiting page hits: a container whose start
+// is already queued or running is not started again, and at most misc.waiting_start_concurrency
+// starts run at once (the others wait for a free slot).
+func (rc *RuntimeController) startFromWaitingPage(ctx context.Context, containerName string) {
+	if !rc.waitingStarts.reserve(containerName) {
+		logger.WithContext(ctx, "runtime_controller").Debugf("start of container %s already in flight, not starting it again", containerName)
+		return
+	}
+	go func(name string) {
+		rc.waitingStarts.acquire()
+		started := rc.startContainer(ctx, name)
+		rc.waitingStarts.release(name)
+		if started {
+			rc.watchStartup(ctx, name)
+		}
+	}(containerName)
+}
+
+// startStagesFromWaitingPage starts a group whose members depend on each other stage by stage in
+// a dedicated goroutine (see groupStarter); a staged start of the group already in flight is not
+// started again. Each container start goes through the waiting page start limiter.
+func (rc *RuntimeController) startStagesFromWaitingPage(ctx context.Context, doc repository.DataDocument, group repository.Group, stages [][]string, notRunning map[string]bool) {
+	key := groupStartKey(group.Name)
+	if !rc.waitingStarts.reserve(key) {
+		logger.WithContext(ctx, "runtime_controller").Debugf("staged start of group %s already in flight, not starting it again", group.Name)
+		return
+	}
+	starter := groupStarter{runtime: rc.runtime, external: rc.externalReady, execChecks: rc.execChecks, interval: rc.config.Data.StartupProbeInterval}
+	go func() {
+		defer rc.waitingStarts.cancel(key)
+		starter.run(ctx, doc, group, stages, func(name string) bool {
+			// Running members and members being started elsewhere are only waited for
+			if !notRunning[name] || !rc.waitingStarts.reserve(name) {
+				return true
+			}
+			rc.waitingStarts.acquire()
+			started := rc.startContainer(ctx, name)
+			rc.waitingStarts.release(name)
+			if started {
+				go rc.watchStartup(ctx, name)
+			}
+			return started
+		})
+	}()
+}
+
+// groupStartKey is the start limiter reservation of a staged group start; the slash keeps it
+// apart from container names.
+func groupStartKey(group string) string {
+	return "group/" + group
+}
+
+// startContainer starts a container and records its runtime state; it reports whether the start succeeded.
+func (rc *RuntimeController) startContainer(ctx context.Context, name string) bool {
+	return rc.awaitStart(ctx, name, rc.queueStart(ctx, name))
+}
+
+// queueStart clears the last error of a container and queues its start.
+func (rc *RuntimeController) queueStart(ctx context.Context, name string) ops.Operation {
+	logger.WithContext(ctx, "runtime_controller").Infof("starting container %s in background", name)
+	rc.setContainerLastError(name, "")
+	return rc.ops.Submit(ops.ActionStart, name)
+}
+
+// awaitStart waits for the start operation op of a container and records its runtime state; it
+// reports whether the start succeeded.
+func (rc *RuntimeController) awaitStart(ctx context.Context, name string, op ops.Operation) bool {
+	if _, err := rc.ops.Wait(ctx, op.ID); err != nil {
+		logger.WithContext(ctx, "runtime_controller").Errorf("failed to start container %s in background: %v", name, err)
+		return false
+	}
+	logger.WithContext(ctx, "runtime_controller").Infof("container %s started successfully", name)
+	cache.RecordRuntimeState(rc.containerStore, name, true, time.Now())
+	return true
+}
+
+// watchStartup polls the readiness of a freshly started container until its StartupTimeoutSecs
+// elapses. On timeout it records LastError and, if data.stop_on_startup_timeout is set, stops it.
+func (rc *RuntimeController) watchStartup(ctx context.Context, name string) {
+	doc, err := rc.containerStore.Snapshot()
+	if err != nil {
+		logger.WithContext(ctx, "runtime_controller").Warnf("startup watch: failed to read container list: %v", err)
+		return
+	}
+	var container *repository.Container
+	for i := range doc.Containers {
+		if doc.Containers[i].Name == name {
+			container = &doc.Containers[i]
+			break
+		}
+	}
+	if container == nil || container.StartupTimeoutSecs == nil || *container.StartupTimeoutSecs <= 0 {
+		return
+	}
+
+	timeout := time.Duration(*container.StartupTimeoutSecs) * time.Second
+	interval := rc.config.Data.StartupProbeInterval
+	if interval <= 0 {
+		interval = defaultStartupProbeInterval
+	}
+	logger.WithContext(ctx, "runtime_controller").Debugf("waiting up to %v for container %s to become ready", timeout, name)
+
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		running, err := rc.runtime.IsRunning(waitCtx, name)
+		if err == nil && running && probeContainerReady(waitCtx, container, rc.externalReady, rc.runtime, rc.execChecks) {
+			logger.WithContext(ctx, "runtime_controller").Debugf("container %s is ready", name)
+			return
+		}
+
+		select {
+		case <-waitCtx.Done():
+			if ctx.Err() != nil {
+				return
+			}
+			logger.WithContext(ctx, "runtime_controller").Warnf("container %s not ready after %v, marking start as failed", name, timeout)
+			rc.setContainerLastError(name, startupTimeoutError)
+			if rc.config.Data.StopOnStartupTimeout {
+				if err := rc.ops.Do(ctx, ops.ActionStop, name); err != nil {
+					logger.WithContext(ctx, "runtime_controller").Errorf("failed to stop container %s after startup timeout: %v", name, err)
+				} else {
+					logger.WithContext(ctx, "runtime_controller").Infof("container %s stopped after startup timeout", name)
+					cache.RecordRuntimeState(rc.containerStore, name, false, time.Now())
+				}
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// setContainerLastError updates the LastError of a stored container in place, when the store
+// records runtime state.
+func (rc *RuntimeController) setContainerLastError(name, lastError string) {
+	rs, ok := rc.containerStore.(cache.RuntimeStateStore)
+	if !ok {
+		return
+	}
+	if err := rs.SetLastError(name, lastError); err != nil && !errors.Is(err, cache.ErrContainerNotFound) {
+		logger.WithComponent("runtime_controller").Errorf("failed to record last error for container %s: %v", name, err)
+	}
+}
+
+// redirectIfReady issues an HTTP redirect to the container URL when misc.waiting_probe_before_redirect
+// is enabled and a quick readiness probe succeeds. It returns false when the waiting page must be served.
+func (rc *RuntimeController) redirectIfReady(c *gin.Context, container *repository.Container) bool {
+	if !rc.config.Misc.WaitingProbeBeforeRedirect || container.URL == "" {
+		return false
+	}
+	if !probeContainerReady(c.Request.Context(), container, rc.externalReady, rc.runtime, rc.execChecks) {
+		logger.WithComponent("runtime_controller").Debugf("container %s running but not ready, serving waiting page", container.Name)
+		return false
+	}
+	target := rc.redirectURL(container.URL)
+	logger.WithComponent("runtime_controller").Debugf("container %s ready, redirecting to %s", container.Name, target)
+	c.Redirect(http.StatusFound, target)
+	return true
+}
+
+// redirectIfGroupReady is redirectIfReady for a group: with misc.waiting_probe_before_redirect it
+// redirects once the group is ready according to its ReadyMode (see groupReadiness).
+func (rc *RuntimeController) redirectIfGroupReady(c *gin.Context, doc repository.DataDocument, group repository.Group) bool {
+	if !rc.config.Misc.WaitingProbeBeforeRedirect {
+		return false
+	}
+	resp := groupReadiness(c.Request.Context(), rc.runtime, doc, group, func(ctx context.Context, container *repository.Container) (bool, string) {
+		return probeContainerReadiness(ctx, container, rc.externalReady, rc.runtime, rc.execChecks)
+	})
+	if !resp.Ready || resp.RedirectURL == "" {
+		logger.WithComponent("runtime_controller").Debugf("group %s running but not ready (%s), serving waiting page", group.Name, resp.Reason)
+		return false
+	}
+	target := rc.redirectURL(resp.RedirectURL)
+	logger.WithComponent("runtime_controller").Debugf("group %s ready, redirecting to %s", group.Name, target)
+	c.Redirect(http.StatusFound, target)
+	return true
+}
+
+// redirectURL prepends the configured default scheme to a schemeless container URL ("host:port")
+// so the waiting redirect is absolute. URLs that cannot be made absolute are returned unchanged.
+func (rc *RuntimeController) redirectURL(rawURL string) string {
+	return absoluteURL(rawURL, rc.config.Misc.DefaultURLScheme)
+}
+
+// absoluteURL prepends scheme (defaultURLScheme when empty) to a schemeless URL. URLs that cannot
+// be made absolute are returned unchanged.
+func absoluteURL(rawURL, scheme string) string {
+	if rawURL == "" || strings.Contains(rawURL, "://") {
+		return rawURL
+	}
+	if scheme == "" {
+		scheme = defaultURLScheme
+	}
+	normalized := scheme + rawURL
+	parsed, err := url.Parse(normalized)
+	if err != nil || !parsed.IsAbs() || parsed.Host == "" {
+		logger.WithComponent("runtime_controller").Warnf("container url %q is not a valid absolute url, using it as is", rawURL)
+		return rawURL
+	}
+	return normalized
+}
+
+// WaitingResponse is the JSON form of the waiting page.
+type WaitingResponse struct {
+	Name        string `json:"name"`
+	RedirectURL string `json:"redirectUrl"`
+	Message     string `json:"message"`
+	// MaxWaitSecs is how long the page polls GET /:name/status before giving up, 0 means forever.
+	MaxWaitSecs int `json:"maxWaitSecs"`
+	// Starting and WaitingOn are reported with misc.waiting_report_dependencies: WaitingOn lists
+	// the dependsOn entries not running or not ready yet.
+	Starting  bool     `json:"starting,omitempty"`
+	WaitingOn []string `json:"waitingOn,omitempty"`
+}
+
+// waitingMessage returns the message of the waiting page customization, the container waiting
+// message or the configured default.
+func (rc *RuntimeController) waitingMessage(container *repository.Container, page *repository.WaitingPage) string {
+	if page != nil && page.Message != "" {
+		return page.Message
+	}
+	if container != nil && container.WaitingMessage != "" {
+		return container.WaitingMessage
+	}
+	return rc.config.Misc.WaitingDefaultMessage
+}
+
+// wantsJSON reports whether the client asked for the JSON form of the waiting page,
+// either with ?format=json or an Accept header preferring application/json.
+func wantsJSON(c *gin.Context) bool {
+	if c.Query("format") == "json" {
+		return true
+	}
+	return c.NegotiateFormat(gin.MIMEHTML, gin.MIMEJSON) == gin.MIMEJSON
+}
+
+// serveWaitingPage renders the waiting HTML template with placeholders replaced,
+// or the equivalent JSON document when requested. page, when set, selects the template and the
+// branding of the entry.
+func (rc *RuntimeController) serveWaitingPage(c *gin.Context, containerName, redirectURL, message string, page *repository.WaitingPage) {
+	redirectURL = rc.redirectURL(redirectURL)
+	if wantsJSON(c) {
+		c.JSON(http.StatusOK, WaitingResponse{Name: containerName, RedirectURL: redirectURL, Message: message, MaxWaitSecs: rc.maxWaitSecs()})
+		return
+	}
+
+	logoURL, accentColor := "", waiting.DefaultAccentColor
+	if page != nil {
+		logoURL = page.LogoURL
+		if page.AccentColor != "" {
+			accentColor = page.AccentColor
+		}
+	}
+	html := rc.pageTemplate(containerName, page)
+	html = strings.ReplaceAll(html, waiting.PlaceholderContainerName, containerName)
+	html = strings.ReplaceAll(html, waiting.PlaceholderRedirectURL, redirectURL)
+	html = strings.ReplaceAll(html, waiting.PlaceholderWaitingMessage, htmlpkg.EscapeString(message))
+	html = strings.ReplaceAll(html, waiting.PlaceholderMaxWaitSecs, strconv.Itoa(rc.maxWaitSecs()))
+	html = strings.ReplaceAll(html, waiting.PlaceholderLogoURL, htmlpkg.EscapeString(logoURL))
+	html = strings.ReplaceAll(html, waiting.PlaceholderAccentColor, accentColor)
 
 	c.Header("Content-Type", "text/html; charset=utf-8")
 	c.String(http.StatusOK, html)
 }
 
+// pageTemplate returns the template of the waiting page of name: the inline template of page, its
+// template file, or the global template when page sets neither or its file cannot be used.
+func (rc *RuntimeController) pageTemplate(name string, page *repository.WaitingPage) string {
+	if page != nil && page.Template != "" {
+		return page.Template
+	}
+	if page != nil && page.TemplatePath != "" {
+		content, err := rc.pageTemplates.Load(page.TemplatePath)
+		if err == nil {
+			return content
+		}
+		logger.WithComponent("runtime_controller").Warnf("cannot use the waiting template of %s, serving the global one: %v", name, err)
+	}
+	return rc.waitingTemplate.Content()
+}
+
+// maxWaitSecs returns misc.waiting_max_wait_secs, how long the waiting page polls (0 = forever).
+func (rc *RuntimeController) maxWaitSecs() int {
+	return int(rc.config.Misc.WaitingMaxWait / time.Second)
+}
+
+// respondInactive answers a waiting page hit for an inactive container or group: a redirect to
+// misc.inactive_redirect_url when set, otherwise misc.inactive_status (default 403) with a JSON error.
+func (rc *RuntimeController) respondInactive(c *gin.Context, message string) {
+	status := rc.config.Misc.InactiveStatus
+	if status == 0 {
+		status = defaultInactiveStatus
+	}
+	if target := rc.config.Misc.InactiveRedirectURL; target != "" {
+		if status < http.StatusMultipleChoices || status >= http.StatusBadRequest {
+			status = http.StatusFound
+		}
+		c.Redirect(status, target)
+		return
+	}
+	apierror.RespondCode(c, status, apierror.CodeInactive, message)
+}
+
+// abortIfNotAllowed answers 403 when the forward-auth user of the request
+// (auth.waiting_user_header, see middleware.ForwardAuth) is not in allowed, the AllowedUsers of the
+// container or group being woken. An empty list, or a request without user because forward auth is
+// disabled, lets the request through. It reports whether the response was written.
+func (rc *RuntimeController) abortIfNotAllowed(c *gin.Context, name string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return false
+	}
+	user := c.GetString(middleware.ContextPrincipal)
+	if user == "" || slices.Contains(allowed, user) {
+		return false
+	}
+	logger.WithComponent("runtime_controller").Warnf("user %s is not allowed to wake %s", user, name)
+	apierror.Respond(c, http.StatusForbidden, fmt.Sprintf("user '%s' is not allowed to start '%s'", user, name))
+	return true
+}
+
+// abortIfRuntimeUnavailable renders the 503 "temporarily unavailable" page when err means the
+// runtime is unreachable, since a start would fail too and leave users on an endless waiting page.
+// It reports whether the response was written; misc.waiting_runtime_unavailable "wait" disables it.
+func (rc *RuntimeController) abortIfRuntimeUnavailable(c *gin.Context, name string, err error) bool {
+	if !runtime.IsUnavailable(err) || rc.config.Misc.WaitingRuntimeUnavailable == waitingUnavailableWait {
+		return false
+	}
+	logger.WithComponent("runtime_controller").Errorf("runtime unavailable, not starting %s: %v", name, err)
+	c.Header("Retry-After", strconv.Itoa(unavailableRetryAfterSecs))
+	if wantsJSON(c) {
+		apierror.RespondDetails(c, http.StatusServiceUnavailable, apierror.CodeRuntimeUnavailable, "container runtime unavailable", gin.H{"name": name})
+		return true
+	}
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.String(http.StatusServiceUnavailable, unavailablePageHTML, htmlpkg.EscapeString(name))
+	return true
+}
+
 // ListContainers returns a JSON array with the names of containers present in the runtime.
 func (rc *RuntimeController) ListContainers(c *gin.Context) {
 	names, err := rc.runtime.ListContainers(c.Request.Context())
 	if err != nil {
 		logger.WithComponent("runtime_controller").Errorf("failed to list containers: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Unable to list containers"})
+		apierror.Respond(c, http.StatusInternalServerError, "Unable to list containers")
 		return
 	}
 	c.JSON(http.StatusOK, names)
 }
 
+// DriftResponse lists the container names known to only one side of the runtime/cache pair.
+type DriftResponse struct {
+	OnlyInRuntime []string `json:"onlyInRuntime"`
+	OnlyInCache   []string `json:"onlyInCache"`
+}
+
+// Drift handles GET /runtime/drift - diffs the runtime containers against the configured ones
+// (case-insensitive) to find unmanaged containers and stale configuration.
+func (rc *RuntimeController) Drift(c *gin.Context) {
+	runtimeNames, err := rc.runtime.ListContainers(c.Request.Context())
+	if err != nil {
+		logger.WithComponent("runtime_controller").Errorf("drift: failed to list containers: %v", err)
+		apierror.Respond(c, http.StatusInternalServerError, "Unable to list containers")
+		return
+	}
+	doc, err := rc.containerStore.Snapshot()
+	if err != nil {
+		logger.WithComponent("runtime_controller").Errorf("drift: failed to read container list: %v", err)
+		apierror.Respond(c, http.StatusInternalServerError, "failed to read container list")
+		return
+	}
+	cacheNames := make([]string, 0, len(doc.Containers))
+	for _, container := range doc.Containers {
+		cacheNames = append(cacheNames, container.Name)
+	}
+
+	c.JSON(http.StatusOK, DriftResponse{
+		OnlyInRuntime: namesMissingFrom(runtimeNames, cacheNames),
+		OnlyInCache:   namesMissingFrom(cacheNames, runtimeNames),
+	})
+}
+
+// namesMissingFrom returns the sorted names of a that are not in b, compared case-insensitively.
+func namesMissingFrom(a, b []string) []string {
+	known := make(map[string]struct{}, len(b))
+	for _, name := range b {
+		known[strings.ToLower(name)] = struct{}{}
+	}
+	missing := []string{}
+	for _, name := range a {
+		if _, ok := known[strings.ToLower(name)]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// PingResponse is the result of a runtime connectivity check.
+type PingResponse struct {
+	OK         bool   `json:"ok"`
+	Version    string `json:"version,omitempty"`
+	APIVersion string `json:"apiVersion,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Ping checks connectivity with the configured runtime.
+// It always answers 200: a failing runtime is reported with ok=false and the error message.
+func (rc *RuntimeController) Ping(c *gin.Context) {
+	result, err := rc.runtime.Ping(c.Request.Context())
+	if err != nil {
+		logger.WithComponent("runtime_controller").Warnf("runtime ping failed: %v", err)
+		c.JSON(http.StatusOK, PingResponse{OK: false, Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, PingResponse{OK: true, Version: result.Version, APIVersion: result.APIVersion})
+}
+
+// RuntimeHealthResponse is the result of GET /runtime/health.
+type RuntimeHealthResponse struct {
+	Reachable  bool   `json:"reachable"`
+	Type       string `json:"type"` // misc.runtime_type
+	Version    string `json:"version,omitempty"`
+	APIVersion string `json:"apiVersion,omitempty"`
+	Error      string `json:"error,omitempty"`
+	// EventsSynced reports whether container states come from the Docker event stream, omitted
+	// for other runtimes and when runtime.watch_events is off
+	EventsSynced *bool `json:"eventsSynced,omitempty"`
+	// ConsecutiveFailures counts the runtime calls failed because it was unreachable since the last success
+	ConsecutiveFailures int        `json:"consecutiveFailures"`
+	LastError           string     `json:"lastError,omitempty"`
+	LastSuccess         *time.Time `json:"lastSuccess,omitempty"`
+	LastFailure         *time.Time `json:"lastFailure,omitempty"`
+}
+
+// Health reports whether the runtime answers a ping right now, with its version, together with
+// the connection history of the recent runtime calls. It answers 503 when the runtime is
+// unreachable, so it can back a monitoring check.
+func (rc *RuntimeController) Health(c *gin.Context) {
+	resp := RuntimeHealthResponse{Type: rc.config.Misc.RuntimeType}
+	result, err := rc.runtime.Ping(c.Request.Context())
+	if err != nil {
+		resp.Error = err.Error()
+	} else {
+		resp.Reachable = true
+		resp.Version, resp.APIVersion = result.Version, result.APIVersion
+	}
+
+	if reporter, ok := rc.runtime.(runtime.HealthReporter); ok {
+		health := reporter.Health()
+		resp.ConsecutiveFailures = health.ConsecutiveFailures
+		resp.LastError = health.LastError
+		if !health.LastSuccess.IsZero() {
+			resp.LastSuccess = &health.LastSuccess
+		}
+		if !health.LastFailure.IsZero() {
+			resp.LastFailure = &health.LastFailure
+		}
+	}
+	if watcher, ok := rc.runtime.(runtime.StateWatcher); ok && rc.config.Runtime.WatchEvents && rc.config.Misc.RuntimeType == runtime.RuntimeTypeDocker {
+		_, synced := watcher.RunningStates()
+		resp.EventsSynced = &synced
+	}
+
+	if !resp.Reachable {
+		logger.WithComponent("runtime_controller").Warnf("runtime health check failed: %v", err)
+		c.JSON(http.StatusServiceUnavailable, resp)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
 // ContainerStatsResponse represents the stats for a single container.
 type ContainerStatsResponse struct {
 	Name       string  `json:"name"`
@@ -409,11 +1235,21 @@ type ContainerStatsResponse struct {
 
 // AllStats returns CPU and memory statistics for all containers defined in the store.
 // Stats are fetched in parallel to avoid sequential timeout accumulation.
+// When runtime.stats_enabled is false the runtime is not queried and all values are zero.
 func (rc *RuntimeController) AllStats(c *gin.Context) {
-	doc, err := rc.containerStore.Snapshot()
+	doc, err := rc.readStore.Snapshot()
 	if err != nil {
 		logger.WithComponent("runtime_controller").Errorf("failed to read container list: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read container list"})
+		apierror.Respond(c, http.StatusInternalServerError, "failed to read container list")
+		return
+	}
+
+	if !rc.config.Runtime.StatsEnabled {
+		results := make([]ContainerStatsResponse, len(doc.Containers))
+		for i, container := range doc.Containers {
+			results[i] = ContainerStatsResponse{Name: container.Name}
+		}
+		c.JSON(http.StatusOK, results)
 		return
 	}
 
@@ -467,3 +1303,37 @@ func (rc *RuntimeController) AllStats(c *gin.Context) {
 
 	c.JSON(http.StatusOK, results)
 }
+
+// Metrics handles GET /runtime/:name/metrics?window=60m - returns the recorded CPU/memory samples
+// of a container within window (a Go duration, default the whole retention), oldest first.
+func (rc *RuntimeController) Metrics(c *gin.Context) {
+	name := c.Param("name")
+	if rc.metrics == nil {
+		apierror.Respond(c, http.StatusServiceUnavailable, "metrics history is disabled")
+		return
+	}
+
+	window := rc.metrics.Retention()
+	if v := c.Query("window"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			apierror.Respond(c, http.StatusBadRequest, "window must be a positive duration such as 60m")
+			return
+		}
+		window = d
+	}
+
+	doc, err := rc.readStore.Snapshot()
+	if err != nil {
+		logger.WithComponent("runtime_controller").Errorf("failed to read container list: %v", err)
+		apierror.Respond(c, http.StatusInternalServerError, "failed to read container list")
+		return
+	}
+	container, ok := rc.findContainer(doc, name)
+	if !ok {
+		apierror.RespondCode(c, http.StatusNotFound, apierror.CodeContainerNotFound, "container not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, rc.metrics.Window(container.Name, window, time.Now()))
+}
@@ -4,18 +4,27 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/bassista/go_spin/internal/api/apierror"
+	"github.com/bassista/go_spin/internal/api/middleware"
 	"github.com/bassista/go_spin/internal/app"
 	"github.com/bassista/go_spin/internal/cache"
 	"github.com/bassista/go_spin/internal/config"
+	"github.com/bassista/go_spin/internal/logger"
+	"github.com/bassista/go_spin/internal/metrics"
 	"github.com/bassista/go_spin/internal/repository"
 	"github.com/bassista/go_spin/internal/runtime"
+	"github.com/bassista/go_spin/internal/waiting"
 	"github.com/gin-gonic/gin"
+	logtest "github.com/sirupsen/logrus/hooks/test"
 )
 
 // mockAppStore implements cache.AppStore for testing
@@ -76,11 +85,12 @@ func (m *mockAppStore) RemoveSchedule(id string) (repository.DataDocument, error
 }
 func (m *mockAppStore) ClearDirty()            {}
 func (m *mockAppStore) SetLastUpdate(ts int64) {}
+func (m *mockAppStore) Version() uint64        { return 0 }
 
 // newTestAppCtx creates an *app.App for testing with the given runtime and store
 func newTestAppCtx(rt runtime.ContainerRuntime, store cache.AppStore) *app.App {
 	return &app.App{
-		Config:  &config.Config{},
+		Config:  &config.Config{Runtime: config.RuntimeConfig{StatsEnabled: true}},
 		Cache:   store,
 		Runtime: rt,
 		BaseCtx: context.Background(),
@@ -97,6 +107,9 @@ type mockContainerRuntime struct {
 	listErr           error
 	statsErr          error
 	statsMap          map[string]runtime.ContainerStats
+	statsCalls        atomic.Int64
+	pingResult        runtime.PingResult
+	pingErr           error
 	startCh           chan string // usato per sincronizzazione nei test
 	stopCh            chan string // usato per sincronizzazione stop nei test
 }
@@ -161,6 +174,7 @@ func (m *mockContainerRuntime) ListContainers(ctx context.Context) ([]string, er
 }
 
 func (m *mockContainerRuntime) Stats(ctx context.Context, containerName string) (runtime.ContainerStats, error) {
+	m.statsCalls.Add(1)
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 	if m.statsErr != nil {
@@ -172,6 +186,10 @@ func (m *mockContainerRuntime) Stats(ctx context.Context, containerName string)
 	return runtime.ContainerStats{}, nil
 }
 
+func (m *mockContainerRuntime) Ping(ctx context.Context) (runtime.PingResult, error) {
+	return m.pingResult, m.pingErr
+}
+
 // newMockStoreWithContainer creates a mock store with a container
 func newMockStoreWithContainer(name string) *mockAppStore {
 	return &mockAppStore{
@@ -305,7 +323,7 @@ func TestRuntimeController_IsRunning_RuntimeError(t *testing.T) {
 
 func TestRuntimeController_IsRunning_ContainerNotFound(t *testing.T) {
 	rt := newMockRuntime()
-	rt.isRunningErr = errors.New("container nonexistent not found")
+	rt.isRunningErr = fmt.Errorf("error checking status: %w", &runtime.NotFoundError{Kind: "container", Name: "nonexistent"})
 
 	store := newMockStoreWithContainer("nonexistent")
 	rc := NewRuntimeController(newTestAppCtx(rt, store))
@@ -321,6 +339,13 @@ func TestRuntimeController_IsRunning_ContainerNotFound(t *testing.T) {
 	if w.Code != http.StatusNotFound {
 		t.Errorf("expected status 404, got %d", w.Code)
 	}
+	var body apierror.Response
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body.Code != apierror.CodeContainerNotFound || body.Message != "error checking status: container nonexistent not found" {
+		t.Errorf("unexpected error body %+v", body)
+	}
 }
 
 func TestRuntimeController_StartContainer_Success(t *testing.T) {
@@ -365,6 +390,38 @@ func TestRuntimeController_StartContainer_Success(t *testing.T) {
 	}
 }
 
+func TestRuntimeController_StartContainer_LogsWithRequestID(t *testing.T) {
+	hook := logtest.NewLocal(logger.Logger)
+	defer hook.Reset()
+
+	rt := newMockRuntime()
+	// A container name of its own: the hook also sees the background starts of the other tests
+	store := newMockStoreWithContainer("req-id-container")
+	rc := NewRuntimeController(newTestAppCtx(rt, store))
+
+	r := gin.New()
+	r.Use(middleware.RequestID())
+	r.POST("/runtime/:name/start", rc.StartContainer)
+
+	req := httptest.NewRequest(http.MethodPost, "/runtime/req-id-container/start", nil)
+	req.Header.Set(middleware.HeaderRequestID, "req-42")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		for _, entry := range hook.AllEntries() {
+			if entry.Message == "container req-id-container started successfully" {
+				if entry.Data[logger.FieldRequestID] != "req-42" {
+					t.Errorf("expected the background log line tagged with the request ID, got %v", entry.Data)
+				}
+				return
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("timeout waiting for the background start log line")
+}
+
 func TestRuntimeController_StartContainer_MissingName(t *testing.T) {
 	rt := newMockRuntime()
 	store := newMockStoreEmpty()
@@ -408,7 +465,7 @@ func TestRuntimeController_StartContainer_RuntimeError(t *testing.T) {
 
 func TestRuntimeController_StartContainer_ContainerNotFound(t *testing.T) {
 	rt := newMockRuntime()
-	rt.startErr = errors.New("error starting container nonexistent: container not found")
+	rt.startErr = fmt.Errorf("error starting container nonexistent: %w", runtime.ErrContainerNotFound)
 
 	store := newMockStoreWithContainer("nonexistent")
 	rc := NewRuntimeController(newTestAppCtx(rt, store))
@@ -514,7 +571,7 @@ func TestRuntimeController_StopContainer_RuntimeError(t *testing.T) {
 
 func TestRuntimeController_StopContainer_ContainerNotFound(t *testing.T) {
 	rt := newMockRuntime()
-	rt.stopErr = errors.New("error stopping container nonexistent: container not found")
+	rt.stopErr = fmt.Errorf("error stopping container nonexistent: %w", runtime.ErrContainerNotFound)
 
 	store := newMockStoreWithContainer("nonexistent")
 	rc := NewRuntimeController(newTestAppCtx(rt, store))
@@ -738,6 +795,38 @@ func TestRuntimeController_WaitingPage_ContainerNotActive(t *testing.T) {
 	}
 }
 
+func TestRuntimeController_WaitingPage_AllowedUsers(t *testing.T) {
+	rt := newMockRuntime()
+	rt.runningContainers["my-container"] = true
+	store := newMockStoreWithActiveContainer("my-container", "http://localhost:8080", true)
+	store.doc.Containers[0].AllowedUsers = []string{"alice"}
+	rc := NewRuntimeController(newTestAppCtx(rt, store))
+
+	r := gin.New()
+	r.Use(middleware.ForwardAuth("Remote-User"))
+	r.GET("/start/:name", rc.WaitingPage)
+
+	tests := []struct {
+		user       string
+		wantStatus int
+	}{
+		{"alice", http.StatusOK},
+		{"bob", http.StatusForbidden},
+		{"", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/start/my-container", nil)
+		if tt.user != "" {
+			req.Header.Set("Remote-User", tt.user)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tt.wantStatus {
+			t.Errorf("user %q: expected status %d, got %d", tt.user, tt.wantStatus, w.Code)
+		}
+	}
+}
+
 func TestRuntimeController_WaitingPage_ContainerActiveAndRunning(t *testing.T) {
 	rt := newMockRuntime()
 	rt.runningContainers["my-container"] = true
@@ -1050,6 +1139,53 @@ func TestRuntimeController_ListContainers_Error(t *testing.T) {
 	}
 }
 
+func TestRuntimeController_Drift(t *testing.T) {
+	rt := newMockRuntime()
+	rt.runningContainers["Web"] = true
+	rt.runningContainers["unmanaged"] = true
+
+	store := &mockAppStore{doc: repository.DataDocument{Containers: []repository.Container{
+		{Name: "web", FriendlyName: "web", URL: "http://web"},
+		{Name: "stale", FriendlyName: "stale", URL: "http://stale"},
+	}}}
+	rc := NewRuntimeController(newTestAppCtx(rt, store))
+
+	r := gin.New()
+	r.GET("/runtime/drift", rc.Drift)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/runtime/drift", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+
+	var resp DriftResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(resp.OnlyInRuntime) != 1 || resp.OnlyInRuntime[0] != "unmanaged" {
+		t.Errorf("expected onlyInRuntime [unmanaged], got %v", resp.OnlyInRuntime)
+	}
+	if len(resp.OnlyInCache) != 1 || resp.OnlyInCache[0] != "stale" {
+		t.Errorf("expected onlyInCache [stale], got %v", resp.OnlyInCache)
+	}
+}
+
+func TestRuntimeController_Drift_ListError(t *testing.T) {
+	rt := newMockRuntime()
+	rt.listErr = errors.New("list failed")
+	rc := NewRuntimeController(newTestAppCtx(rt, newMockStoreEmpty()))
+
+	r := gin.New()
+	r.GET("/runtime/drift", rc.Drift)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/runtime/drift", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status 500 on runtime error, got %d", w.Code)
+	}
+}
+
 func TestRuntimeController_AllStats_Success(t *testing.T) {
 	rt := newMockRuntime()
 	rt.statsMap["container1"] = runtime.ContainerStats{CPUPercent: 25.5, MemoryMB: 128.0}
@@ -1135,6 +1271,41 @@ func TestRuntimeController_AllStats_EmptyStore(t *testing.T) {
 	}
 }
 
+func TestRuntimeController_AllStats_DisabledSkipsRuntime(t *testing.T) {
+	rt := newMockRuntime()
+	rt.statsMap["container1"] = runtime.ContainerStats{CPUPercent: 25.5, MemoryMB: 128.0}
+	store := newMockStoreWithActiveContainer("container1", "http://example.com", true)
+	appCtx := newTestAppCtx(rt, store)
+	appCtx.Config.Runtime.StatsEnabled = false
+	rc := NewRuntimeController(appCtx)
+
+	r := gin.New()
+	r.GET("/runtime/stats", rc.AllStats)
+
+	req := httptest.NewRequest(http.MethodGet, "/runtime/stats", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	if calls := rt.statsCalls.Load(); calls != 0 {
+		t.Errorf("expected no Stats calls when disabled, got %d", calls)
+	}
+
+	// Fields must stay present (as zero) so clients keep parsing the same shape.
+	var raw []map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &raw); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(raw) != 1 {
+		t.Fatalf("expected 1 item, got %d", len(raw))
+	}
+	if raw[0]["name"] != "container1" || raw[0]["cpu_percent"] != 0.0 || raw[0]["memory_mb"] != 0.0 {
+		t.Errorf("expected zeroed stats for container1, got %v", raw[0])
+	}
+}
+
 func TestRuntimeController_AllStats_WithError(t *testing.T) {
 	rt := newMockRuntime()
 	rt.statsMap["container1"] = runtime.ContainerStats{CPUPercent: 10.0, MemoryMB: 64.0}
@@ -1209,3 +1380,942 @@ func TestRuntimeController_AllStats_StoreError(t *testing.T) {
 		t.Errorf("expected status 500 on store error, got %d", w.Code)
 	}
 }
+
+func newMockStoreWithPartialGroup() *mockAppStore {
+	return &mockAppStore{
+		doc: repository.DataDocument{
+			Containers: []repository.Container{
+				{Name: "c1", URL: "http://localhost:8001", Active: boolPtr(true)},
+			},
+			Groups: []repository.Group{
+				{Name: "my-group", Container: []string{"c1", "ghost"}, Active: boolPtr(true)},
+			},
+		},
+	}
+}
+
+func TestRuntimeController_WaitingPage_StrictGroupWithMissingMember(t *testing.T) {
+	rt := newMockRuntime()
+	appCtx := newTestAppCtx(rt, newMockStoreWithPartialGroup())
+	appCtx.Config.Data.StrictGroups = true
+	rc := NewRuntimeController(appCtx)
+
+	r := gin.New()
+	r.GET("/start/:name", rc.WaitingPage)
+
+	req := httptest.NewRequest(http.MethodGet, "/start/my-group", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", w.Code)
+	}
+	var body struct {
+		Code    string `json:"code"`
+		Details struct {
+			Missing []string `json:"missing"`
+		} `json:"details"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body.Code != apierror.CodeDanglingReference {
+		t.Errorf("expected code %s, got %q", apierror.CodeDanglingReference, body.Code)
+	}
+	if len(body.Details.Missing) != 1 || body.Details.Missing[0] != "ghost" {
+		t.Errorf("expected missing [ghost], got %v", body.Details.Missing)
+	}
+	select {
+	case name := <-rt.startCh:
+		t.Errorf("expected no start in strict mode, got start of %s", name)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestRuntimeController_WaitingPage_LenientGroupWithMissingMember(t *testing.T) {
+	rt := newMockRuntime()
+	rc := NewRuntimeController(newTestAppCtx(rt, newMockStoreWithPartialGroup()))
+
+	r := gin.New()
+	r.GET("/start/:name", rc.WaitingPage)
+
+	req := httptest.NewRequest(http.MethodGet, "/start/my-group", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	select {
+	case name := <-rt.startCh:
+		if name != "c1" {
+			t.Errorf("expected c1 to be started, got %s", name)
+		}
+	case <-time.After(time.Second):
+		t.Error("expected existing member c1 to be started")
+	}
+}
+
+// newStartupTimeoutTest builds a controller whose only container never becomes ready.
+func newStartupTimeoutTest(t *testing.T, stopOnTimeout bool) (*RuntimeController, *mockContainerRuntime, *cache.Store) {
+	t.Helper()
+	notReady := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(notReady.Close)
+
+	timeoutSecs := 1
+	store := cache.NewStore(repository.DataDocument{
+		Containers: []repository.Container{
+			{Name: "slow", FriendlyName: "slow", URL: notReady.URL, Active: boolPtr(true), StartupTimeoutSecs: &timeoutSecs},
+		},
+	})
+	rt := newMockRuntime()
+	appCtx := newTestAppCtx(rt, store)
+	appCtx.Config.Data.StartupProbeInterval = 20 * time.Millisecond
+	appCtx.Config.Data.StopOnStartupTimeout = stopOnTimeout
+	return NewRuntimeController(appCtx), rt, store
+}
+
+func waitForLastError(t *testing.T, store *cache.Store, name, want string) {
+	t.Helper()
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		doc, _ := store.Snapshot()
+		for _, c := range doc.Containers {
+			if c.Name == name && c.LastError == want {
+				return
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("expected container %s to record last error %q", name, want)
+}
+
+func TestRuntimeController_StartupTimeout_RecordsError(t *testing.T) {
+	rc, rt, store := newStartupTimeoutTest(t, false)
+
+	rc.startContainerInBackground(context.Background(), "slow")
+	waitForLastError(t, store, "slow", startupTimeoutError)
+
+	select {
+	case name := <-rt.stopCh:
+		t.Errorf("expected no stop without stop_on_startup_timeout, got stop of %s", name)
+	default:
+	}
+}
+
+func TestRuntimeController_StartupTimeout_StopsContainer(t *testing.T) {
+	rc, rt, store := newStartupTimeoutTest(t, true)
+
+	rc.startContainerInBackground(context.Background(), "slow")
+	waitForLastError(t, store, "slow", startupTimeoutError)
+
+	select {
+	case name := <-rt.stopCh:
+		if name != "slow" {
+			t.Errorf("expected slow to be stopped, got %s", name)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected container to be stopped after startup timeout")
+	}
+}
+
+func TestRuntimeController_StartupTimeout_ClearedOnNextStart(t *testing.T) {
+	rc, _, store := newStartupTimeoutTest(t, false)
+	doc, _ := store.Snapshot()
+	c := doc.Containers[0]
+	c.LastError = startupTimeoutError
+	c.StartupTimeoutSecs = nil
+	if _, err := store.AddContainer(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rc.startContainerInBackground(context.Background(), "slow")
+	waitForLastError(t, store, "slow", "")
+}
+
+func waitForRunningSince(t *testing.T, store *cache.Store, name string, wantSet bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		doc, _ := store.Snapshot()
+		for _, c := range doc.Containers {
+			if c.Name == name && (c.RunningSince != nil) == wantSet {
+				return
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("expected RunningSince set=%v for container %s", wantSet, name)
+}
+
+func TestRuntimeController_StartStop_TracksRunningSince(t *testing.T) {
+	rt := newMockRuntime()
+	store := cache.NewStore(repository.DataDocument{
+		Containers: []repository.Container{
+			{Name: "web", FriendlyName: "web", URL: "http://web.local", Active: boolPtr(true)},
+		},
+	})
+	rc := NewRuntimeController(newTestAppCtx(rt, store))
+
+	r := gin.New()
+	r.POST("/runtime/:name/start", rc.StartContainer)
+	r.POST("/runtime/:name/stop", rc.StopContainer)
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/runtime/web/start", nil))
+	waitForRunningSince(t, store, "web", true)
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/runtime/web/stop", nil))
+	waitForRunningSince(t, store, "web", false)
+}
+
+func TestRuntimeController_WaitingPage_ProbeBeforeRedirect(t *testing.T) {
+	ready := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ready.Close()
+	notReady := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer notReady.Close()
+
+	tests := []struct {
+		name         string
+		url          string
+		wantCode     int
+		wantLocation string
+	}{
+		{"ready container redirects", ready.URL, http.StatusFound, ready.URL},
+		{"not ready container serves waiting page", notReady.URL, http.StatusOK, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := newMockRuntime()
+			rt.runningContainers["my-container"] = true
+			store := newMockStoreWithActiveContainer("my-container", tt.url, true)
+			appCtx := newTestAppCtx(rt, store)
+			appCtx.Config.Misc.WaitingProbeBeforeRedirect = true
+			rc := NewRuntimeController(appCtx)
+
+			r := gin.New()
+			r.GET("/start/:name", rc.WaitingPage)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/start/my-container", nil))
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("expected status %d, got %d", tt.wantCode, w.Code)
+			}
+			if got := w.Header().Get("Location"); got != tt.wantLocation {
+				t.Errorf("expected Location %q, got %q", tt.wantLocation, got)
+			}
+			if tt.wantCode == http.StatusOK && w.Header().Get("Content-Type") != "text/html; charset=utf-8" {
+				t.Errorf("expected HTML waiting page, got Content-Type %q", w.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestRuntimeController_WaitingPage_ProbeDisabledServesPage(t *testing.T) {
+	ready := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ready.Close()
+
+	rt := newMockRuntime()
+	rt.runningContainers["my-container"] = true
+	store := newMockStoreWithActiveContainer("my-container", ready.URL, true)
+	rc := NewRuntimeController(newTestAppCtx(rt, store))
+
+	r := gin.New()
+	r.GET("/start/:name", rc.WaitingPage)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/start/my-container", nil))
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200 without probe option, got %d", w.Code)
+	}
+}
+
+func TestRuntimeController_Ping_Success(t *testing.T) {
+	rt := newMockRuntime()
+	rt.pingResult = runtime.PingResult{Version: "27.3.1", APIVersion: "1.47"}
+	rc := NewRuntimeController(newTestAppCtx(rt, newMockStoreEmpty()))
+
+	r := gin.New()
+	r.GET("/runtime/ping", rc.Ping)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/runtime/ping", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	var resp PingResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if !resp.OK || resp.Version != "27.3.1" || resp.APIVersion != "1.47" || resp.Error != "" {
+		t.Errorf("unexpected ping response: %+v", resp)
+	}
+}
+
+func TestRuntimeController_Ping_ConnectionError(t *testing.T) {
+	rt := newMockRuntime()
+	rt.pingErr = errors.New("cannot connect to the Docker daemon")
+	rc := NewRuntimeController(newTestAppCtx(rt, newMockStoreEmpty()))
+
+	r := gin.New()
+	r.GET("/runtime/ping", rc.Ping)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/runtime/ping", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	var resp PingResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.OK || resp.Error != "cannot connect to the Docker daemon" {
+		t.Errorf("expected ok=false with error, got %+v", resp)
+	}
+}
+
+// healthReportingRuntime adds a connection history to the mock runtime.
+type healthReportingRuntime struct {
+	*mockContainerRuntime
+	health runtime.Health
+}
+
+func (h *healthReportingRuntime) Health() runtime.Health {
+	return h.health
+}
+
+func TestRuntimeController_Health(t *testing.T) {
+	failedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name    string
+		pingErr error
+		status  int
+	}{
+		{"reachable", nil, http.StatusOK},
+		{"unreachable", errors.New("cannot connect to the Docker daemon"), http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := newMockRuntime()
+			rt.pingResult = runtime.PingResult{Version: "27.3.1", APIVersion: "1.47"}
+			rt.pingErr = tt.pingErr
+			appCtx := newTestAppCtx(&healthReportingRuntime{
+				mockContainerRuntime: rt,
+				health:               runtime.Health{ConsecutiveFailures: 2, LastError: "connection refused", LastFailure: failedAt},
+			}, newMockStoreEmpty())
+			appCtx.Config.Misc.RuntimeType = runtime.RuntimeTypeDocker
+			rc := NewRuntimeController(appCtx)
+
+			r := gin.New()
+			r.GET("/runtime/health", rc.Health)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/runtime/health", nil))
+
+			if w.Code != tt.status {
+				t.Fatalf("expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			var resp RuntimeHealthResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if resp.Reachable != (tt.pingErr == nil) || resp.Type != runtime.RuntimeTypeDocker {
+				t.Errorf("unexpected health %+v", resp)
+			}
+			if resp.ConsecutiveFailures != 2 || resp.LastError != "connection refused" || resp.LastFailure == nil || !resp.LastFailure.Equal(failedAt) || resp.LastSuccess != nil {
+				t.Errorf("expected the connection history reported, got %+v", resp)
+			}
+			if tt.pingErr == nil && resp.Version != "27.3.1" {
+				t.Errorf("expected the runtime version, got %q", resp.Version)
+			}
+		})
+	}
+}
+
+func newWaitingMessageController(message string) *RuntimeController {
+	rt := newMockRuntime()
+	rt.runningContainers["db"] = true
+	store := &mockAppStore{doc: repository.DataDocument{
+		Containers: []repository.Container{
+			{Name: "db", FriendlyName: "db", URL: "http://db.local", Active: boolPtr(true), WaitingMessage: message},
+		},
+	}}
+	appCtx := newTestAppCtx(rt, store)
+	appCtx.Config.Misc.WaitingDefaultMessage = "Starting up, please wait..."
+	rc := NewRuntimeController(appCtx)
+	rc.waitingTemplate = waiting.NewTemplate("", "<p>{{WAITING_MESSAGE}}</p>")
+	return rc
+}
+
+func TestRuntimeController_WaitingPage_CustomMessageHTML(t *testing.T) {
+	rc := newWaitingMessageController("Spinning up your database, ~30s...")
+
+	r := gin.New()
+	r.GET("/start/:name", rc.WaitingPage)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/start/db", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	if got := w.Body.String(); got != "<p>Spinning up your database, ~30s...</p>" {
+		t.Errorf("expected custom message in HTML, got %q", got)
+	}
+}
+
+func TestRuntimeController_WaitingPage_MessageIsEscaped(t *testing.T) {
+	rc := newWaitingMessageController("<script>alert(1)</script>")
+
+	r := gin.New()
+	r.GET("/start/:name", rc.WaitingPage)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/start/db", nil))
+
+	if got := w.Body.String(); got != "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>" {
+		t.Errorf("expected escaped message, got %q", got)
+	}
+}
+
+func TestRuntimeController_WaitingPage_CustomMessageJSON(t *testing.T) {
+	rc := newWaitingMessageController("Spinning up your database, ~30s...")
+
+	r := gin.New()
+	r.GET("/start/:name", rc.WaitingPage)
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/start/db?format=json", nil),
+		func() *http.Request {
+			req := httptest.NewRequest(http.MethodGet, "/start/db", nil)
+			req.Header.Set("Accept", "application/json")
+			return req
+		}(),
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		var resp WaitingResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to unmarshal response %q: %v", w.Body.String(), err)
+		}
+		if resp.Name != "db" || resp.RedirectURL != "http://db.local" || resp.Message != "Spinning up your database, ~30s..." {
+			t.Errorf("unexpected waiting response: %+v", resp)
+		}
+	}
+}
+
+func TestRuntimeController_WaitingPage_ReportsDependenciesNotReady(t *testing.T) {
+	var dbReady atomic.Bool
+	db := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !dbReady.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer db.Close()
+
+	rt := newMockRuntime()
+	rt.runningContainers["db"] = true
+	store := &mockAppStore{doc: repository.DataDocument{Containers: []repository.Container{
+		{Name: "db", FriendlyName: "db", URL: db.URL, Active: boolPtr(true)},
+		{Name: "app", FriendlyName: "app", URL: "http://app.local", Active: boolPtr(true), DependsOn: []string{"db"}},
+	}}}
+	appCtx := newTestAppCtx(rt, store)
+	appCtx.Config.Misc.WaitingReportDependencies = true
+	rc := NewRuntimeController(appCtx)
+
+	r := gin.New()
+	r.GET("/start/:name", rc.WaitingPage)
+	wait := func() (int, WaitingResponse) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/start/app?format=json", nil))
+		var resp WaitingResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to unmarshal response %q: %v", w.Body.String(), err)
+		}
+		return w.Code, resp
+	}
+
+	code, resp := wait()
+	if code != http.StatusTooEarly {
+		t.Errorf("expected status 425 while db is not ready, got %d", code)
+	}
+	if !resp.Starting || len(resp.WaitingOn) != 1 || resp.WaitingOn[0] != "db" {
+		t.Errorf("expected starting with waitingOn [db], got %+v", resp)
+	}
+
+	dbReady.Store(true)
+	code, resp = wait()
+	if code != http.StatusOK {
+		t.Errorf("expected status 200 once db is ready, got %d", code)
+	}
+	if len(resp.WaitingOn) != 0 {
+		t.Errorf("expected empty waitingOn once db is ready, got %v", resp.WaitingOn)
+	}
+}
+
+func TestRuntimeController_WaitingPage_DefaultMessage(t *testing.T) {
+	rc := newWaitingMessageController("")
+
+	r := gin.New()
+	r.GET("/start/:name", rc.WaitingPage)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/start/db?format=json", nil))
+
+	var resp WaitingResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.Message != "Starting up, please wait..." {
+		t.Errorf("expected default message, got %q", resp.Message)
+	}
+}
+
+func TestRuntimeController_WaitingPage_RedirectURLScheme(t *testing.T) {
+	tests := []struct {
+		name   string
+		url    string
+		scheme string
+		want   string
+	}{
+		{"schemeless url gets default scheme", "db.local:8080", "", "http://db.local:8080"},
+		{"schemeless url gets configured scheme", "db.local:8443/app", "https://", "https://db.local:8443/app"},
+		{"absolute url passes through", "https://db.example.com/", "http://", "https://db.example.com/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := newMockRuntime()
+			rt.runningContainers["db"] = true
+			store := &mockAppStore{doc: repository.DataDocument{
+				Containers: []repository.Container{{Name: "db", URL: tt.url, Active: boolPtr(true)}},
+				Groups:     []repository.Group{{Name: "stack", Container: []string{"db"}, Active: boolPtr(true)}},
+			}}
+			appCtx := newTestAppCtx(rt, store)
+			appCtx.Config.Misc.DefaultURLScheme = tt.scheme
+			rc := NewRuntimeController(appCtx)
+			rc.waitingTemplate = waiting.NewTemplate("", "{{REDIRECT_URL}}")
+
+			r := gin.New()
+			r.GET("/start/:name", rc.WaitingPage)
+
+			for _, target := range []string{"db", "stack"} {
+				w := httptest.NewRecorder()
+				r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/start/"+target, nil))
+				if got := w.Body.String(); got != tt.want {
+					t.Errorf("%s: expected redirect url %q, got %q", target, tt.want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestRuntimeController_GoTo_RedirectsRunningContainer(t *testing.T) {
+	rt := newMockRuntime()
+	rt.runningContainers["my-container"] = true
+	store := newMockStoreWithActiveContainer("my-container", "http://app.local/home", true)
+	rc := NewRuntimeController(newTestAppCtx(rt, store))
+
+	r := gin.New()
+	r.GET("/go/:name", rc.GoTo)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/go/my-container?tab=logs&q=a%20b", nil))
+
+	if w.Code != http.StatusFound {
+		t.Fatalf("expected status 302, got %d", w.Code)
+	}
+	if got, want := w.Header().Get("Location"), "http://app.local/home?tab=logs&q=a%20b"; got != want {
+		t.Errorf("expected Location %q, got %q", want, got)
+	}
+	select {
+	case name := <-rt.startCh:
+		t.Errorf("expected no start for a running container, got start of %s", name)
+	default:
+	}
+}
+
+func TestRuntimeController_GoTo_StartsStoppedContainer(t *testing.T) {
+	rt := newMockRuntime()
+	store := newMockStoreWithActiveContainer("my-container", "app.local:8080", true)
+	rc := NewRuntimeController(newTestAppCtx(rt, store))
+
+	r := gin.New()
+	r.GET("/go/:name", rc.GoTo)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/go/my-container", nil))
+
+	if w.Code != http.StatusFound {
+		t.Fatalf("expected status 302, got %d", w.Code)
+	}
+	if got, want := w.Header().Get("Location"), "http://app.local:8080"; got != want {
+		t.Errorf("expected Location %q, got %q", want, got)
+	}
+	select {
+	case name := <-rt.startCh:
+		if name != "my-container" {
+			t.Errorf("expected my-container to be started, got %s", name)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for background start")
+	}
+}
+
+func TestRuntimeController_GoTo_NotFoundAndInactive(t *testing.T) {
+	rt := newMockRuntime()
+	store := newMockStoreWithActiveContainer("my-container", "http://app.local", false)
+	rc := NewRuntimeController(newTestAppCtx(rt, store))
+
+	r := gin.New()
+	r.GET("/go/:name", rc.GoTo)
+
+	for path, want := range map[string]int{
+		"/go/my-container": http.StatusForbidden,
+		"/go/unknown":      http.StatusNotFound,
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != want {
+			t.Errorf("%s: expected status %d, got %d", path, want, w.Code)
+		}
+	}
+}
+
+func TestWithQuery(t *testing.T) {
+	tests := []struct{ target, query, want string }{
+		{"http://a/", "", "http://a/"},
+		{"http://a/", "x=1", "http://a/?x=1"},
+		{"http://a/?y=2", "x=1", "http://a/?y=2&x=1"},
+		{"http://a/#top", "x=1", "http://a/?x=1#top"},
+	}
+	for _, tt := range tests {
+		if got := withQuery(tt.target, tt.query); got != tt.want {
+			t.Errorf("withQuery(%q, %q) = %q, want %q", tt.target, tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestRuntimeController_Metrics(t *testing.T) {
+	store := newMockStoreWithActiveContainer("web", "http://web", true)
+	appCtx := newTestAppCtx(newMockRuntime(), store)
+	appCtx.Metrics = metrics.NewHistory(time.Hour, time.Minute, 0)
+	now := time.Now()
+	appCtx.Metrics.Add("web", metrics.Sample{T: now.Add(-90 * time.Minute), CPU: 1, Mem: 10})
+	appCtx.Metrics.Add("web", metrics.Sample{T: now.Add(-30 * time.Minute), CPU: 2, Mem: 20})
+	appCtx.Metrics.Add("web", metrics.Sample{T: now.Add(-5 * time.Minute), CPU: 3, Mem: 30})
+
+	rc := NewRuntimeController(appCtx)
+	r := gin.New()
+	r.GET("/runtime/:name/metrics", rc.Metrics)
+
+	tests := []struct {
+		url      string
+		wantCode int
+		wantCPU  []float64
+	}{
+		{"/runtime/web/metrics", http.StatusOK, []float64{2, 3}},
+		{"/runtime/web/metrics?window=10m", http.StatusOK, []float64{3}},
+		{"/runtime/web/metrics?window=bogus", http.StatusBadRequest, nil},
+		{"/runtime/web/metrics?window=-5m", http.StatusBadRequest, nil},
+		{"/runtime/missing/metrics", http.StatusNotFound, nil},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.url, nil))
+		if w.Code != tt.wantCode {
+			t.Errorf("%s: expected status %d, got %d: %s", tt.url, tt.wantCode, w.Code, w.Body.String())
+			continue
+		}
+		if tt.wantCode != http.StatusOK {
+			continue
+		}
+		var samples []metrics.Sample
+		if err := json.Unmarshal(w.Body.Bytes(), &samples); err != nil {
+			t.Fatalf("%s: failed to unmarshal response: %v", tt.url, err)
+		}
+		if len(samples) != len(tt.wantCPU) {
+			t.Fatalf("%s: expected %d samples, got %v", tt.url, len(tt.wantCPU), samples)
+		}
+		for i, s := range samples {
+			if s.CPU != tt.wantCPU[i] {
+				t.Errorf("%s: sample %d expected cpu %v, got %v", tt.url, i, tt.wantCPU[i], s.CPU)
+			}
+		}
+	}
+}
+
+func TestRuntimeController_Metrics_Disabled(t *testing.T) {
+	rc := NewRuntimeController(newTestAppCtx(newMockRuntime(), newMockStoreWithActiveContainer("web", "http://web", true)))
+	r := gin.New()
+	r.GET("/runtime/:name/metrics", rc.Metrics)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/runtime/web/metrics", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503 when the history is disabled, got %d", w.Code)
+	}
+}
+
+// delayedStartRuntime reports a started container as running only after delay (never when delay is 0).
+type delayedStartRuntime struct {
+	*mockContainerRuntime
+	delay time.Duration
+}
+
+func (d *delayedStartRuntime) Start(ctx context.Context, name string) error {
+	if d.delay > 0 {
+		time.AfterFunc(d.delay, func() {
+			d.mu.Lock()
+			defer d.mu.Unlock()
+			d.runningContainers[name] = true
+		})
+	}
+	return nil
+}
+
+func newWaitTestRouter(rt runtime.ContainerRuntime) *gin.Engine {
+	rc := NewRuntimeController(newTestAppCtx(rt, newMockStoreWithContainer("web")))
+	rc.waitPoll = 10 * time.Millisecond
+	r := gin.New()
+	r.POST("/runtime/:name/wait", rc.Wait)
+	return r
+}
+
+func TestRuntimeController_Wait_ReachesRunning(t *testing.T) {
+	r := newWaitTestRouter(&delayedStartRuntime{mockContainerRuntime: newMockRuntime(), delay: 50 * time.Millisecond})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/runtime/web/wait?state=running&timeout=5", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp WaitResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if !resp.Reached || resp.State != "running" {
+		t.Errorf("expected reached running state, got %+v", resp)
+	}
+}
+
+func TestRuntimeController_Wait_TimesOut(t *testing.T) {
+	r := newWaitTestRouter(&delayedStartRuntime{mockContainerRuntime: newMockRuntime()})
+
+	start := time.Now()
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/runtime/web/wait?state=running&timeout=1", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp WaitResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.Reached || resp.State != "stopped" {
+		t.Errorf("expected timeout with stopped state, got %+v", resp)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("expected the wait to last the whole timeout, took %v", elapsed)
+	}
+}
+
+func TestRuntimeController_Wait_Stop(t *testing.T) {
+	rt := newMockRuntime()
+	rt.runningContainers["web"] = true
+	r := newWaitTestRouter(rt)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/runtime/web/wait?state=stopped&timeout=5", nil))
+
+	var resp WaitResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if !resp.Reached || resp.State != "stopped" {
+		t.Errorf("expected reached stopped state, got %+v", resp)
+	}
+}
+
+func TestRuntimeController_Wait_ClientDisconnect(t *testing.T) {
+	r := newWaitTestRouter(&delayedStartRuntime{mockContainerRuntime: newMockRuntime()})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	req := httptest.NewRequest(http.MethodPost, "/runtime/web/wait?timeout=5", nil).WithContext(ctx)
+
+	start := time.Now()
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("expected the wait to stop on client disconnect, took %v", elapsed)
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("expected no body after client disconnect, got %s", w.Body.String())
+	}
+}
+
+func TestRuntimeController_Wait_BadRequest(t *testing.T) {
+	r := newWaitTestRouter(newMockRuntime())
+
+	for _, url := range []string{
+		"/runtime/web/wait?state=paused",
+		"/runtime/web/wait?timeout=0",
+		"/runtime/web/wait?timeout=abc",
+		"/runtime/web/wait?timeout=100000",
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, url, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", url, w.Code)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/runtime/missing/wait", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for unknown container, got %d", w.Code)
+	}
+}
+
+func TestRuntimeController_WaitingPage_RuntimeUnavailable(t *testing.T) {
+	rt := newMockRuntime()
+	rt.isRunningErr = fmt.Errorf("error checking status of container my-container: %w: connection refused", runtime.ErrUnavailable)
+
+	store := newMockStoreWithActiveContainer("my-container", "http://localhost:8080", true)
+	appCtx := newTestAppCtx(rt, store)
+	rc := NewRuntimeController(appCtx)
+	rc.waitingTemplate = waiting.NewTemplate("", "<html>waiting {{CONTAINER_NAME}}</html>")
+
+	r := gin.New()
+	r.GET("/start/:name", rc.WaitingPage)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/start/my-container", nil))
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status 503, got %d", w.Code)
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, "temporarily unavailable") || strings.Contains(body, "waiting my-container") {
+		t.Errorf("expected the unavailable page instead of the waiting page, got %s", body)
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Error("expected a Retry-After header")
+	}
+	select {
+	case name := <-rt.startCh:
+		t.Errorf("expected no start attempt, got one for %s", name)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// JSON clients get a 503 JSON error.
+	req := httptest.NewRequest(http.MethodGet, "/start/my-container", nil)
+	req.Header.Set("Accept", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusServiceUnavailable || !strings.Contains(w.Body.String(), "runtime unavailable") {
+		t.Errorf("expected a 503 JSON error, got %d %s", w.Code, w.Body.String())
+	}
+
+	// The legacy behavior keeps serving the polling page.
+	appCtx.Config.Misc.WaitingRuntimeUnavailable = "wait"
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/start/my-container", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "waiting my-container") {
+		t.Errorf("expected the waiting page in wait mode, got %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestRuntimeController_WaitingPage_GroupRuntimeUnavailable(t *testing.T) {
+	rt := newMockRuntime()
+	rt.isRunningErr = runtime.ErrUnavailable
+
+	store := newMockStoreWithGroup("my-group", []string{"c1", "c2"}, true, true)
+	rc := NewRuntimeController(newTestAppCtx(rt, store))
+
+	r := gin.New()
+	r.GET("/start/:name", rc.WaitingPage)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/start/my-group", nil))
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503, got %d", w.Code)
+	}
+}
+
+func TestRuntimeController_WaitingPage_OtherErrorStillWaits(t *testing.T) {
+	rt := newMockRuntime()
+	rt.isRunningErr = errors.New("inspect failed")
+
+	store := newMockStoreWithActiveContainer("my-container", "http://localhost:8080", true)
+	rc := NewRuntimeController(newTestAppCtx(rt, store))
+
+	r := gin.New()
+	r.GET("/start/:name", rc.WaitingPage)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/start/my-container", nil))
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected the waiting page for a non-connectivity error, got %d", w.Code)
+	}
+}
+
+func TestRuntimeController_WaitingPage_InactiveStatus(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		redirectURL  string
+		path         string
+		wantCode     int
+		wantLocation string
+	}{
+		{"default container", 0, "", "/start/c1", http.StatusForbidden, ""},
+		{"custom container status", http.StatusNotFound, "", "/start/c1", http.StatusNotFound, ""},
+		{"custom group status", http.StatusNotFound, "", "/start/g1", http.StatusNotFound, ""},
+		{"redirect with 4xx status", http.StatusForbidden, "https://example.com/landing", "/start/c1", http.StatusFound, "https://example.com/landing"},
+		{"redirect with 3xx status", http.StatusSeeOther, "/landing", "/start/g1", http.StatusSeeOther, "/landing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &mockAppStore{doc: repository.DataDocument{
+				Containers: []repository.Container{{Name: "c1", URL: "http://localhost", Active: boolPtr(false)}},
+				Groups:     []repository.Group{{Name: "g1", Container: []string{"c1"}, Active: boolPtr(false)}},
+			}}
+			appCtx := newTestAppCtx(newMockRuntime(), store)
+			appCtx.Config.Misc.InactiveStatus = tt.status
+			appCtx.Config.Misc.InactiveRedirectURL = tt.redirectURL
+			rc := NewRuntimeController(appCtx)
+
+			r := gin.New()
+			r.GET("/start/:name", rc.WaitingPage)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if w.Code != tt.wantCode {
+				t.Errorf("expected status %d, got %d", tt.wantCode, w.Code)
+			}
+			if got := w.Header().Get("Location"); got != tt.wantLocation {
+				t.Errorf("expected Location %q, got %q", tt.wantLocation, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/bassista/go_spin/internal/api/apierror"
+	"github.com/bassista/go_spin/internal/api/middleware"
+	"github.com/bassista/go_spin/internal/logger"
+	"github.com/bassista/go_spin/internal/runtime"
+	"github.com/gin-gonic/gin"
+)
+
+// execWriteSlack extends the response write deadline past the exec timeout.
+const execWriteSlack = 5 * time.Second
+
+// ExecRequest names the command of the container execCommands to run.
+type ExecRequest struct {
+	Command string `json:"command" binding:"required"`
+}
+
+// ExecResponse is the outcome of POST /runtime/:name/exec.
+type ExecResponse struct {
+	Name      string `json:"name"`
+	Command   string `json:"command"`
+	ExitCode  int    `json:"exitCode"`
+	Output    string `json:"output"`
+	Truncated bool   `json:"truncated,omitempty"`
+}
+
+// Exec runs one of the commands allowlisted in the execCommands of a running container and
+// returns its exit code and output. Commands not configured in the data document are rejected.
+func (rc *RuntimeController) Exec(c *gin.Context) {
+	name := c.Param("name")
+	var req ExecRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	doc, err := rc.containerStore.Snapshot()
+	if err != nil {
+		apierror.Respond(c, http.StatusInternalServerError, "failed to read container list")
+		return
+	}
+	var cmd []string
+	found := false
+	for _, container := range doc.Containers {
+		if container.Name == name {
+			cmd, found = container.ExecCommands[req.Command], true
+			if cmd == nil {
+				apierror.RespondDetails(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "command not allowed for this container", gin.H{"allowed": execCommandNames(container.ExecCommands)})
+				return
+			}
+			break
+		}
+	}
+	if !found {
+		apierror.RespondCode(c, http.StatusNotFound, apierror.CodeContainerNotFound, "container not found")
+		return
+	}
+	executor, ok := rc.runtime.(runtime.OutputExecutor)
+	if !ok {
+		apierror.Respond(c, http.StatusNotImplemented, "runtime does not support exec")
+		return
+	}
+
+	ctx := c.Request.Context()
+	if timeout := rc.config.Runtime.ExecTimeout; timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+		// The command may outlast server.write_timeout_secs: extend the deadline of this response only.
+		if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Now().Add(timeout + execWriteSlack)); err != nil {
+			logger.WithComponent("runtime_controller").Debugf("cannot extend the write deadline of exec: %v", err)
+		}
+	}
+	if running, err := rc.runtime.IsRunning(ctx, name); err == nil && !running {
+		apierror.Respond(c, http.StatusConflict, "container is not running")
+		return
+	}
+
+	logger.WithComponent("runtime_controller").Infof("exec %q in container %s by %s", req.Command, name, c.GetString(middleware.ContextPrincipal))
+	result, err := executor.ExecOutput(ctx, name, cmd)
+	if err != nil {
+		logger.WithComponent("runtime_controller").Warnf("exec %q in container %s failed: %v", req.Command, name, err)
+		switch {
+		case errors.Is(err, runtime.ErrTimeout) || errors.Is(err, context.DeadlineExceeded):
+			apierror.Respond(c, http.StatusGatewayTimeout, "command timed out")
+		case runtime.IsUnavailable(err):
+			apierror.RespondCode(c, http.StatusServiceUnavailable, apierror.CodeRuntimeUnavailable, "container runtime unavailable")
+		case runtime.IsNotFound(err):
+			apierror.RespondCode(c, http.StatusNotFound, apierror.CodeContainerNotFound, "container not found")
+		default:
+			apierror.RespondError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, ExecResponse{
+		Name:      name,
+		Command:   req.Command,
+		ExitCode:  result.ExitCode,
+		Output:    result.Output,
+		Truncated: result.Truncated,
+	})
+}
+
+// execCommandNames returns the sorted names of the allowlisted commands.
+func execCommandNames(commands map[string][]string) []string {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bassista/go_spin/internal/repository"
+	"github.com/bassista/go_spin/internal/runtime"
+	"github.com/gin-gonic/gin"
+)
+
+// execOutputRuntime is a mock runtime recording the commands run with ExecOutput.
+type execOutputRuntime struct {
+	*mockContainerRuntime
+	ran [][]string
+}
+
+func (r *execOutputRuntime) ExecOutput(_ context.Context, _ string, cmd []string) (runtime.ExecResult, error) {
+	r.ran = append(r.ran, cmd)
+	return runtime.ExecResult{ExitCode: 0, Output: "OK\n"}, nil
+}
+
+func TestRuntimeController_Exec(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	rt := &execOutputRuntime{mockContainerRuntime: newMockRuntime()}
+	rt.runningContainers["cache"] = true
+	store := &mockAppStore{doc: repository.DataDocument{
+		Containers: []repository.Container{
+			{Name: "cache", FriendlyName: "cache", URL: "http://cache.local", Active: boolPtr(true),
+				ExecCommands: map[string][]string{"flush": {"redis-cli", "save"}}},
+			{Name: "db", FriendlyName: "db", URL: "http://db.local", Active: boolPtr(true),
+				ExecCommands: map[string][]string{"vacuum": {"vacuumdb", "--all"}}},
+		},
+	}}
+	rc := NewRuntimeController(newTestAppCtx(rt, store))
+	r := gin.New()
+	r.POST("/runtime/:name/exec", rc.Exec)
+
+	tests := []struct {
+		name   string
+		path   string
+		body   string
+		status int
+	}{
+		{"allowed command", "/runtime/cache/exec", `{"command":"flush"}`, http.StatusOK},
+		{"command not allowlisted", "/runtime/cache/exec", `{"command":"vacuum"}`, http.StatusBadRequest},
+		{"missing command", "/runtime/cache/exec", `{}`, http.StatusBadRequest},
+		{"unknown container", "/runtime/missing/exec", `{"command":"flush"}`, http.StatusNotFound},
+		{"stopped container", "/runtime/db/exec", `{"command":"vacuum"}`, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body)))
+		if w.Code != tt.status {
+			t.Errorf("%s: expected status %d, got %d: %s", tt.name, tt.status, w.Code, w.Body.String())
+		}
+		if tt.status != http.StatusOK {
+			continue
+		}
+		var resp ExecResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		if resp.Name != "cache" || resp.Command != "flush" || resp.Output != "OK\n" || resp.ExitCode != 0 {
+			t.Errorf("%s: unexpected response %+v", tt.name, resp)
+		}
+	}
+	if !reflect.DeepEqual(rt.ran, [][]string{{"redis-cli", "save"}}) {
+		t.Errorf("expected only the allowlisted command run, got %v", rt.ran)
+	}
+}
+
+func TestRuntimeController_Exec_Unsupported(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := &mockAppStore{doc: repository.DataDocument{
+		Containers: []repository.Container{
+			{Name: "cache", FriendlyName: "cache", URL: "http://cache.local", Active: boolPtr(true),
+				ExecCommands: map[string][]string{"flush": {"redis-cli", "save"}}},
+		},
+	}}
+	rc := NewRuntimeController(newTestAppCtx(newMockRuntime(), store))
+	r := gin.New()
+	r.POST("/runtime/:name/exec", rc.Exec)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/runtime/cache/exec", strings.NewReader(`{"command":"flush"}`)))
+	if w.Code != http.StatusNotImplemented {
+		t.Errorf("expected 501, got %d", w.Code)
+	}
+}
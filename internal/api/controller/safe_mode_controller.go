@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/bassista/go_spin/internal/api/apierror"
+	"github.com/bassista/go_spin/internal/logger"
+	"github.com/bassista/go_spin/internal/runtime"
+	"github.com/gin-gonic/gin"
+)
+
+// SafeModeRequest is the payload of POST /safe-mode.
+type SafeModeRequest struct {
+	Enabled *bool `json:"enabled"`
+}
+
+// SafeModeResponse reports the current safe mode state.
+type SafeModeResponse struct {
+	Enabled bool `json:"enabled"`
+}
+
+// SafeModeController toggles safe mode, in which go_spin never starts or stops a container
+// while the configuration API keeps working.
+type SafeModeController struct {
+	safeMode *runtime.SafeModeRuntime
+}
+
+// NewSafeModeController creates a new SafeModeController.
+func NewSafeModeController(safeMode *runtime.SafeModeRuntime) *SafeModeController {
+	return &SafeModeController{safeMode: safeMode}
+}
+
+// Get handles GET /safe-mode - returns whether safe mode is enabled.
+func (sc *SafeModeController) Get(c *gin.Context) {
+	c.JSON(http.StatusOK, SafeModeResponse{Enabled: sc.safeMode.Enabled()})
+}
+
+// Set handles POST /safe-mode - enables or disables safe mode until the next restart
+// (misc.safe_mode sets the state at startup).
+func (sc *SafeModeController) Set(c *gin.Context) {
+	logger.WithComponent("safe-mode-controller").Debugf("POST /safe-mode handler called")
+
+	var req SafeModeRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Enabled == nil {
+		apierror.Respond(c, http.StatusBadRequest, "invalid payload")
+		return
+	}
+	sc.safeMode.SetEnabled(*req.Enabled)
+	c.JSON(http.StatusOK, SafeModeResponse{Enabled: sc.safeMode.Enabled()})
+}
@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bassista/go_spin/internal/cache"
+	"github.com/bassista/go_spin/internal/config"
+	"github.com/bassista/go_spin/internal/repository"
+	"github.com/bassista/go_spin/internal/runtime"
+	"github.com/gin-gonic/gin"
+)
+
+func TestSafeModeController_Toggle(t *testing.T) {
+	safeMode := runtime.NewSafeModeRuntime(newMockRuntime(), false)
+	sc := NewSafeModeController(safeMode)
+
+	r := gin.New()
+	r.GET("/safe-mode", sc.Get)
+	r.POST("/safe-mode", sc.Set)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/safe-mode", bytes.NewBufferString(`{"enabled":true}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	if !safeMode.Enabled() {
+		t.Error("expected safe mode enabled")
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/safe-mode", nil))
+	var resp SafeModeResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || !resp.Enabled {
+		t.Errorf("expected enabled=true, got %s (%v)", w.Body.String(), err)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/safe-mode", bytes.NewBufferString(`{}`)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 without enabled, got %d", w.Code)
+	}
+}
+
+func TestSafeMode_SuppressesActionsButKeepsConfigEditable(t *testing.T) {
+	rt := newMockRuntime()
+	rt.runningContainers["up"] = true
+	safeMode := runtime.NewSafeModeRuntime(rt, true)
+	store := cache.NewStore(repository.DataDocument{
+		Containers: []repository.Container{
+			{Name: "down", FriendlyName: "down", URL: "http://down.local", Active: boolPtr(true)},
+			{Name: "up", FriendlyName: "up", URL: "http://up.local", Active: boolPtr(true)},
+		},
+		Groups: []repository.Group{{Name: "all", Container: []string{"down", "up"}, Active: boolPtr(true)}},
+	})
+	rc := NewRuntimeController(newTestAppCtx(safeMode, store))
+	gc := NewGroupController(context.Background(), store, safeMode, nil, &config.Config{})
+	cc := NewContainerController(context.Background(), store, safeMode)
+
+	r := gin.New()
+	r.POST("/runtime/:name/start", rc.StartContainer)
+	r.POST("/runtime/:name/stop", rc.StopContainer)
+	r.POST("/group/:name/start", gc.StartGroup)
+	r.POST("/group/:name/stop", gc.StopGroup)
+	r.GET("/waiting/:name", rc.WaitingPage)
+	r.POST("/container", cc.CreateOrUpdateContainer)
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodPost, "/runtime/down/start", nil),
+		httptest.NewRequest(http.MethodPost, "/runtime/up/stop", nil),
+		httptest.NewRequest(http.MethodPost, "/group/all/start", nil),
+		httptest.NewRequest(http.MethodPost, "/group/all/stop", nil),
+		httptest.NewRequest(http.MethodGet, "/waiting/down", nil),
+	} {
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	select {
+	case name := <-rt.startCh:
+		t.Fatalf("expected no start in safe mode, %s was started", name)
+	case name := <-rt.stopCh:
+		t.Fatalf("expected no stop in safe mode, %s was stopped", name)
+	case <-time.After(200 * time.Millisecond):
+	}
+	if rt.runningContainers["down"] || !rt.runningContainers["up"] {
+		t.Errorf("expected runtime state untouched, got %v", rt.runningContainers)
+	}
+
+	body, _ := json.Marshal(repository.Container{Name: "new", FriendlyName: "new", URL: "http://new.local", Active: boolPtr(true)})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/container", bytes.NewReader(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected config edit to succeed in safe mode, got %d: %s", w.Code, w.Body.String())
+	}
+	if !store.IsDirty() {
+		t.Error("expected the config edit to be pending persistence")
+	}
+}
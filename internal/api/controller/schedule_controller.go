@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http"
 
+	"github.com/bassista/go_spin/internal/api/apierror"
 	"github.com/bassista/go_spin/internal/cache"
 	"github.com/bassista/go_spin/internal/logger"
 	"github.com/bassista/go_spin/internal/repository"
@@ -48,7 +49,7 @@ func (sc *ScheduleController) DeleteSchedule(c *gin.Context) {
 	logger.WithComponent("schedule-controller").Debugf("DELETE /schedule/%s handler called", id)
 	if id == "" {
 		logger.WithComponent("schedule-controller").Debugf("delete schedule: missing id parameter")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "missing schedule id"})
+		apierror.Respond(c, http.StatusBadRequest, "missing schedule id")
 		return
 	}
 
@@ -56,11 +57,11 @@ func (sc *ScheduleController) DeleteSchedule(c *gin.Context) {
 	if err != nil {
 		if errors.Is(err, cache.ErrScheduleNotFound) {
 			logger.WithComponent("schedule-controller").Debugf("delete schedule %s: not found", id)
-			c.JSON(http.StatusNotFound, gin.H{"error": "schedule not found"})
+			apierror.RespondCode(c, http.StatusNotFound, apierror.CodeScheduleNotFound, "schedule not found")
 			return
 		}
 		logger.WithComponent("schedule-controller").Errorf("delete schedule %s: cache error: %v", id, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update cache"})
+		apierror.Respond(c, http.StatusInternalServerError, "failed to update cache")
 		return
 	}
 
@@ -139,6 +139,34 @@ func TestScheduleController_CreateOrUpdateSchedule_Valid(t *testing.T) {
 	}
 }
 
+func TestScheduleController_CreateOrUpdateSchedule_OneShot(t *testing.T) {
+	store := &mockScheduleStore{doc: repository.DataDocument{Schedules: []repository.Schedule{}}}
+	sc := NewScheduleController(store)
+	r := gin.New()
+	r.POST("/schedule", sc.CreateOrUpdateSchedule)
+
+	tests := []struct {
+		name   string
+		timer  map[string]any
+		status int
+	}{
+		{"valid window", map[string]any{"startDate": "2025-12-24 18:00", "stopDate": "2025-12-26", "active": true}, http.StatusOK},
+		{"stop before start", map[string]any{"startDate": "2025-12-26", "stopDate": "2025-12-24", "active": true}, http.StatusBadRequest},
+		{"malformed date", map[string]any{"startDate": "24/12/2025", "stopDate": "2025-12-26", "active": true}, http.StatusBadRequest},
+		{"missing stop date", map[string]any{"startDate": "2025-12-24", "active": true}, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		body, _ := json.Marshal(map[string]any{"id": "xmas", "target": "plex", "targetType": "container", "timers": []any{tt.timer}})
+		req := httptest.NewRequest(http.MethodPost, "/schedule", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tt.status {
+			t.Errorf("%s: expected status %d, got %d: %s", tt.name, tt.status, w.Code, w.Body.String())
+		}
+	}
+}
+
 func TestScheduleController_CreateOrUpdateSchedule_InvalidPayload(t *testing.T) {
 	store := &mockScheduleStore{}
 	sc := NewScheduleController(store)
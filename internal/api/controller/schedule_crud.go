@@ -1,6 +1,9 @@
 package controller
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/bassista/go_spin/internal/cache"
 	"github.com/bassista/go_spin/internal/repository"
 	"github.com/go-playground/validator/v10"
@@ -27,6 +30,19 @@ func (s *ScheduleCrudService) Add(item repository.Schedule) ([]repository.Schedu
 	return doc.Schedules, nil
 }
 
+// AddForce upserts the schedule even when its target is missing.
+func (s *ScheduleCrudService) AddForce(item repository.Schedule) ([]repository.Schedule, error) {
+	unchecked, ok := s.Store.(cache.UncheckedStore)
+	if !ok {
+		return s.Add(item)
+	}
+	doc, err := unchecked.AddScheduleUnchecked(item)
+	if err != nil {
+		return nil, err
+	}
+	return doc.Schedules, nil
+}
+
 func (s *ScheduleCrudService) Remove(id string) ([]repository.Schedule, error) {
 	doc, err := s.Store.RemoveSchedule(id)
 	if err != nil {
@@ -41,5 +57,26 @@ type ScheduleCrudValidator struct {
 }
 
 func (v *ScheduleCrudValidator) Validate(item repository.Schedule) error {
-	return v.validator.Struct(item)
+	if err := v.validator.Struct(item); err != nil {
+		return err
+	}
+	return validateTimers(item.Timers)
+}
+
+// validateTimers checks the shape of every timer: one-shot timers need a valid date window
+// (their dates are checked in UTC, the order does not depend on the scheduling timezone), weekly
+// ones a start and a stop time.
+func validateTimers(timers []repository.Timer) error {
+	for i, timer := range timers {
+		if timer.IsOneShot() {
+			if _, _, err := timer.OneShotWindow(time.UTC); err != nil {
+				return fmt.Errorf("timer %d: %w", i, err)
+			}
+			continue
+		}
+		if timer.StartTime == "" || timer.StopTime == "" {
+			return fmt.Errorf("timer %d: startTime and stopTime are required", i)
+		}
+	}
+	return nil
 }
@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bassista/go_spin/internal/api/apierror"
+	"github.com/bassista/go_spin/internal/cache"
+	"github.com/bassista/go_spin/internal/config"
+	"github.com/bassista/go_spin/internal/logger"
+	"github.com/bassista/go_spin/internal/scheduler"
+	"github.com/gin-gonic/gin"
+)
+
+// ExtendScheduleRequest is the payload of POST /schedule/:id/extend.
+type ExtendScheduleRequest struct {
+	Minutes int `json:"minutes"`
+}
+
+// ScheduleExtensionController manages transient, today-only offsets of schedule stop times.
+type ScheduleExtensionController struct {
+	store      cache.ReadOnlyStore
+	extensions *scheduler.Extensions
+	loc        *time.Location
+}
+
+// NewScheduleExtensionController creates a new ScheduleExtensionController using the configured scheduling timezone.
+func NewScheduleExtensionController(store cache.ReadOnlyStore, extensions *scheduler.Extensions, cfg *config.Config) *ScheduleExtensionController {
+	loc, err := cfg.Misc.SchedulingLocation()
+	if err != nil {
+		logger.WithComponent("schedule-controller").Warnf("invalid scheduling timezone, using Local: %v", err)
+		loc = time.Local
+	}
+	return &ScheduleExtensionController{
+		store:      store,
+		extensions: extensions,
+		loc:        loc,
+	}
+}
+
+// ExtendSchedule handles POST /schedule/:id/extend - offsets the schedule's stop times by the given
+// minutes (negative values shorten the window) until the end of the current day.
+func (ec *ScheduleExtensionController) ExtendSchedule(c *gin.Context) {
+	id := c.Param("id")
+	logger.WithComponent("schedule-controller").Debugf("POST /schedule/%s/extend handler called", id)
+
+	var req ExtendScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "invalid payload")
+		return
+	}
+	if req.Minutes == 0 || req.Minutes <= -scheduler.MaxExtensionMinutes || req.Minutes >= scheduler.MaxExtensionMinutes {
+		apierror.Respond(c, http.StatusBadRequest, fmt.Sprintf("minutes must be non-zero and within ±%d", scheduler.MaxExtensionMinutes-1))
+		return
+	}
+
+	found, err := ec.scheduleExists(id)
+	if err != nil {
+		logger.WithComponent("schedule-controller").Errorf("extend schedule %s: failed to read snapshot: %v", id, err)
+		apierror.Respond(c, http.StatusInternalServerError, "failed to read schedules")
+		return
+	}
+	if !found {
+		apierror.RespondCode(c, http.StatusNotFound, apierror.CodeScheduleNotFound, "schedule not found")
+		return
+	}
+
+	ext := ec.extensions.Set(id, req.Minutes, time.Now().In(ec.loc))
+	logger.WithComponent("schedule-controller").Infof("schedule %s stop times offset by %d minutes for %s", id, ext.Minutes, ext.Day)
+	c.JSON(http.StatusOK, ext)
+}
+
+// ClearScheduleExtension handles DELETE /schedule/:id/extend - removes the schedule's extension.
+func (ec *ScheduleExtensionController) ClearScheduleExtension(c *gin.Context) {
+	id := c.Param("id")
+	logger.WithComponent("schedule-controller").Debugf("DELETE /schedule/%s/extend handler called", id)
+
+	if !ec.extensions.Clear(id) {
+		apierror.Respond(c, http.StatusNotFound, "schedule extension not found")
+		return
+	}
+	logger.WithComponent("schedule-controller").Infof("schedule %s extension cleared", id)
+	c.JSON(http.StatusOK, ClearedResponse{ScheduleID: id, Cleared: true})
+}
+
+func (ec *ScheduleExtensionController) scheduleExists(id string) (bool, error) {
+	doc, err := ec.store.Snapshot()
+	if err != nil {
+		return false, err
+	}
+	for _, s := range doc.Schedules {
+		if s.ID == id {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bassista/go_spin/internal/config"
+	"github.com/bassista/go_spin/internal/repository"
+	"github.com/bassista/go_spin/internal/scheduler"
+	"github.com/gin-gonic/gin"
+)
+
+func newExtensionTestRouter(ext *scheduler.Extensions) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	store := &mockScheduleStore{
+		doc: repository.DataDocument{
+			Schedules: []repository.Schedule{{ID: "s1", Target: "c1", TargetType: "container"}},
+		},
+	}
+	ec := NewScheduleExtensionController(store, ext, &config.Config{})
+	r := gin.New()
+	r.POST("/schedule/:id/extend", ec.ExtendSchedule)
+	r.DELETE("/schedule/:id/extend", ec.ClearScheduleExtension)
+	return r
+}
+
+func TestScheduleExtensionController_ExtendAndClear(t *testing.T) {
+	ext := scheduler.NewExtensions()
+	r := newExtensionTestRouter(ext)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/schedule/s1/extend", bytes.NewBufferString(`{"minutes":60}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp scheduler.Extension
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.ScheduleID != "s1" || resp.Minutes != 60 {
+		t.Errorf("unexpected extension: %+v", resp)
+	}
+	if got := ext.Offset("s1", time.Now()); got != time.Hour {
+		t.Errorf("expected offset 1h, got %v", got)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/schedule/s1/extend", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	if got := ext.Offset("s1", time.Now()); got != 0 {
+		t.Errorf("expected no offset after clear, got %v", got)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/schedule/s1/extend", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 when no extension is set, got %d", w.Code)
+	}
+}
+
+func TestScheduleExtensionController_Extend_Invalid(t *testing.T) {
+	r := newExtensionTestRouter(scheduler.NewExtensions())
+
+	tests := []struct {
+		name   string
+		path   string
+		body   string
+		status int
+	}{
+		{"invalid json", "/schedule/s1/extend", `{`, http.StatusBadRequest},
+		{"zero minutes", "/schedule/s1/extend", `{"minutes":0}`, http.StatusBadRequest},
+		{"beyond one day", "/schedule/s1/extend", `{"minutes":1440}`, http.StatusBadRequest},
+		{"unknown schedule", "/schedule/missing/extend", `{"minutes":30}`, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, tt.path, bytes.NewBufferString(tt.body)))
+			if w.Code != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package controller
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bassista/go_spin/internal/api/apierror"
+	"github.com/bassista/go_spin/internal/cache"
+	"github.com/bassista/go_spin/internal/config"
+	"github.com/bassista/go_spin/internal/logger"
+	"github.com/bassista/go_spin/internal/scheduler"
+	"github.com/gin-gonic/gin"
+)
+
+// planDateLayout is the short date format accepted by the plan endpoints besides RFC3339.
+const planDateLayout = "2006-01-02"
+
+// SchedulerController exposes read-only views over the schedule evaluation logic.
+type SchedulerController struct {
+	store      cache.ReadOnlyStore
+	loc        *time.Location
+	maxDays    int
+	horizon    time.Duration // lookahead of NextEvents
+	extensions *scheduler.Extensions
+	now        func() time.Time
+}
+
+// NewSchedulerController creates a new SchedulerController using the configured scheduling timezone.
+func NewSchedulerController(store cache.ReadOnlyStore, cfg *config.Config) *SchedulerController {
+	loc, err := cfg.Misc.SchedulingLocation()
+	if err != nil {
+		logger.WithComponent("scheduler-controller").Warnf("invalid scheduling timezone, using Local: %v", err)
+		loc = time.Local
+	}
+	return &SchedulerController{
+		store:   store,
+		loc:     loc,
+		maxDays: cfg.Data.PlanMaxDays,
+		horizon: time.Duration(cfg.Data.NextEventsHorizonDays) * 24 * time.Hour,
+		now:     time.Now,
+	}
+}
+
+// SetExtensions makes the snapshot endpoints honor the transient schedule extensions.
+func (sc *SchedulerController) SetExtensions(ext *scheduler.Extensions) {
+	sc.extensions = ext
+}
+
+// Active handles GET /scheduler/active - returns, for every schedule, the timers active right now
+// and the containers the schedule wants running, as evaluated by the scheduler itself.
+func (sc *SchedulerController) Active(c *gin.Context) {
+	logger.WithComponent("scheduler-controller").Debugf("GET /scheduler/active handler called")
+	doc, err := sc.store.Snapshot()
+	if err != nil {
+		logger.WithComponent("scheduler-controller").Errorf("active: failed to read snapshot: %v", err)
+		apierror.Respond(c, http.StatusInternalServerError, "failed to read schedules")
+		return
+	}
+	c.JSON(http.StatusOK, scheduler.ActiveNow(doc, sc.now().In(sc.loc), sc.extensions))
+}
+
+// NextEvents handles GET /runtime/next-events - returns, for every container, the next planned
+// start and stop (RFC3339 in the scheduling timezone, null when none falls within
+// data.next_events_horizon_days).
+func (sc *SchedulerController) NextEvents(c *gin.Context) {
+	logger.WithComponent("scheduler-controller").Debugf("GET /runtime/next-events handler called")
+	doc, err := sc.store.Snapshot()
+	if err != nil {
+		logger.WithComponent("scheduler-controller").Errorf("next events: failed to read snapshot: %v", err)
+		apierror.Respond(c, http.StatusInternalServerError, "failed to read schedules")
+		return
+	}
+	events, err := scheduler.NextEvents(c.Request.Context(), doc, sc.now().In(sc.loc), sc.horizon, sc.loc)
+	if err != nil {
+		logger.WithComponent("scheduler-controller").Warnf("next events: computation interrupted: %v", err)
+		apierror.Respond(c, http.StatusServiceUnavailable, "next events computation interrupted")
+		return
+	}
+	c.JSON(http.StatusOK, events)
+}
+
+// ScheduleNextResponse is the next planned start and stop of a schedule.
+type ScheduleNextResponse struct {
+	ScheduleID string `json:"scheduleId"`
+	Timezone   string `json:"timezone"`
+	scheduler.NextEvent
+}
+
+// ScheduleNext handles GET /schedule/:id/next - returns the next start and stop planned by the
+// schedule (the first of any of its containers), in the scheduling timezone, null when none falls
+// within data.next_events_horizon_days. Returns 404 if the schedule does not exist.
+func (sc *SchedulerController) ScheduleNext(c *gin.Context) {
+	id := c.Param("id")
+	logger.WithComponent("scheduler-controller").Debugf("GET /schedule/%s/next handler called", id)
+	doc, err := sc.store.Snapshot()
+	if err != nil {
+		logger.WithComponent("scheduler-controller").Errorf("schedule next: failed to read snapshot: %v", err)
+		apierror.Respond(c, http.StatusInternalServerError, "failed to read schedules")
+		return
+	}
+	if !scheduler.HasSchedule(doc, id) {
+		apierror.RespondCode(c, http.StatusNotFound, apierror.CodeScheduleNotFound, "schedule not found")
+		return
+	}
+	event, err := scheduler.NextScheduleEvent(c.Request.Context(), doc, id, sc.now().In(sc.loc), sc.horizon, sc.loc)
+	if err != nil {
+		logger.WithComponent("scheduler-controller").Warnf("schedule next: computation interrupted: %v", err)
+		apierror.Respond(c, http.StatusServiceUnavailable, "next events computation interrupted")
+		return
+	}
+	c.JSON(http.StatusOK, ScheduleNextResponse{ScheduleID: id, Timezone: sc.loc.String(), NextEvent: event})
+}
+
+// PreviewResponse lists the actions planned in a window.
+type PreviewResponse struct {
+	From     time.Time                 `json:"from"`
+	To       time.Time                 `json:"to"`
+	Timezone string                    `json:"timezone"`
+	Actions  []scheduler.PlannedAction `json:"actions"`
+}
+
+// Preview handles GET /schedules/preview?from=&to= - returns every planned start/stop in the
+// window, in chronological order. from/to are parsed like PlanCSV (same defaults and limits).
+func (sc *SchedulerController) Preview(c *gin.Context) {
+	logger.WithComponent("scheduler-controller").Debugf("GET /schedules/preview handler called")
+	from, to, err := sc.parseRange(c)
+	if err != nil {
+		apierror.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	doc, err := sc.store.Snapshot()
+	if err != nil {
+		logger.WithComponent("scheduler-controller").Errorf("preview: failed to read snapshot: %v", err)
+		apierror.Respond(c, http.StatusInternalServerError, "failed to read schedules")
+		return
+	}
+
+	actions := []scheduler.PlannedAction{}
+	err = scheduler.WalkPlan(c.Request.Context(), doc, from, to, sc.loc, func(a scheduler.PlannedAction) error {
+		actions = append(actions, a)
+		return nil
+	})
+	if err != nil {
+		logger.WithComponent("scheduler-controller").Warnf("preview: computation interrupted: %v", err)
+		apierror.Respond(c, http.StatusServiceUnavailable, "preview computation interrupted")
+		return
+	}
+	c.JSON(http.StatusOK, PreviewResponse{From: from.In(sc.loc), To: to.In(sc.loc), Timezone: sc.loc.String(), Actions: actions})
+}
+
+// PlanCSV handles GET /scheduler/plan.csv?from=&to= - streams the planned actions as CSV.
+// from/to accept RFC3339 or YYYY-MM-DD (interpreted in the scheduling timezone);
+// from defaults to the start of today and to defaults to one day after from.
+func (sc *SchedulerController) PlanCSV(c *gin.Context) {
+	logger.WithComponent("scheduler-controller").Debugf("GET /scheduler/plan.csv handler called")
+	from, to, err := sc.parseRange(c)
+	if err != nil {
+		apierror.RespondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	doc, err := sc.store.Snapshot()
+	if err != nil {
+		logger.WithComponent("scheduler-controller").Errorf("plan: failed to read snapshot: %v", err)
+		apierror.Respond(c, http.StatusInternalServerError, "failed to read schedules")
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", "attachment; filename=plan.csv")
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	if err := w.Write([]string{"time", "container", "action", "scheduleId"}); err != nil {
+		logger.WithComponent("scheduler-controller").Errorf("plan: failed to write csv header: %v", err)
+		return
+	}
+
+	err = scheduler.WalkPlan(c.Request.Context(), doc, from, to, sc.loc, func(a scheduler.PlannedAction) error {
+		if err := w.Write([]string{a.Time.Format(time.RFC3339), a.Container, a.Action, a.ScheduleID}); err != nil {
+			return err
+		}
+		// Flush row by row so large ranges are streamed instead of buffered.
+		w.Flush()
+		return w.Error()
+	})
+	w.Flush()
+	if err != nil {
+		logger.WithComponent("scheduler-controller").Warnf("plan: streaming interrupted: %v", err)
+	}
+}
+
+// parseRange reads and validates the from/to query parameters.
+func (sc *SchedulerController) parseRange(c *gin.Context) (time.Time, time.Time, error) {
+	now := sc.now().In(sc.loc)
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, sc.loc)
+	if v := c.Query("from"); v != "" {
+		t, err := sc.parsePlanTime(v)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid from: %w", err)
+		}
+		from = t
+	}
+
+	to := from.AddDate(0, 0, 1)
+	if v := c.Query("to"); v != "" {
+		t, err := sc.parsePlanTime(v)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid to: %w", err)
+		}
+		to = t
+	}
+
+	if !to.After(from) {
+		return time.Time{}, time.Time{}, fmt.Errorf("to must be after from")
+	}
+	if sc.maxDays > 0 && to.Sub(from) > time.Duration(sc.maxDays)*24*time.Hour {
+		return time.Time{}, time.Time{}, fmt.Errorf("range exceeds %d days", sc.maxDays)
+	}
+	return from, to, nil
+}
+
+func (sc *SchedulerController) parsePlanTime(value string) (time.Time, error) {
+	if t, err := time.ParseInLocation(planDateLayout, value, sc.loc); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
@@ -0,0 +1,227 @@
+package controller
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bassista/go_spin/internal/config"
+	"github.com/bassista/go_spin/internal/repository"
+	"github.com/bassista/go_spin/internal/scheduler"
+	"github.com/gin-gonic/gin"
+)
+
+func newSchedulerTestRouter(store *mockAppStore) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.Config{Data: config.DataConfig{PlanMaxDays: 31}, Misc: config.MiscConfig{SchedulingTZ: "UTC"}}
+	sc := NewSchedulerController(store, cfg)
+	r := gin.New()
+	r.GET("/scheduler/plan.csv", sc.PlanCSV)
+	return r
+}
+
+func newPlanStore() *mockAppStore {
+	return &mockAppStore{doc: repository.DataDocument{
+		Containers: []repository.Container{{Name: "web", Active: boolPtr(true)}},
+		Schedules: []repository.Schedule{{
+			ID: "office", Target: "web", TargetType: "container",
+			Timers: []repository.Timer{{StartTime: "08:00", StopTime: "18:00", Days: []int{1, 2, 3, 4, 5}, Active: boolPtr(true)}},
+		}},
+	}}
+}
+
+func TestSchedulerController_PlanCSV(t *testing.T) {
+	r := newSchedulerTestRouter(newPlanStore())
+
+	req := httptest.NewRequest(http.MethodGet, "/scheduler/plan.csv?from=2024-03-18&to=2024-03-19", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/csv") {
+		t.Errorf("expected text/csv content type, got %s", ct)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(w.Body.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("expected valid CSV, got error: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("expected header and 2 rows, got %d records: %v", len(records), records)
+	}
+	if strings.Join(records[0], ",") != "time,container,action,scheduleId" {
+		t.Errorf("unexpected header: %v", records[0])
+	}
+	if strings.Join(records[1], ",") != "2024-03-18T08:00:00Z,web,start,office" {
+		t.Errorf("unexpected first row: %v", records[1])
+	}
+	if strings.Join(records[2], ",") != "2024-03-18T18:00:00Z,web,stop,office" {
+		t.Errorf("unexpected second row: %v", records[2])
+	}
+}
+
+func TestSchedulerController_PlanCSV_InvalidRange(t *testing.T) {
+	r := newSchedulerTestRouter(newPlanStore())
+
+	tests := []string{
+		"/scheduler/plan.csv?from=bogus",
+		"/scheduler/plan.csv?from=2024-03-19&to=2024-03-18",
+		"/scheduler/plan.csv?from=2024-01-01&to=2024-12-31",
+	}
+	for _, url := range tests {
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", url, w.Code)
+		}
+	}
+}
+
+func TestSchedulerController_Active(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := newPlanStore()
+	store.doc.Containers = append(store.doc.Containers, repository.Container{Name: "backup", Active: boolPtr(true)})
+	store.doc.Schedules = append(store.doc.Schedules, repository.Schedule{
+		ID: "nightly", Target: "backup", TargetType: "container",
+		Timers: []repository.Timer{{StartTime: "02:00", StopTime: "04:00", Days: []int{0, 1, 2, 3, 4, 5, 6}, Active: boolPtr(true)}},
+	})
+
+	cfg := &config.Config{Misc: config.MiscConfig{SchedulingTZ: "UTC"}}
+	sc := NewSchedulerController(store, cfg)
+	// Monday 10:00 UTC: inside the office window, outside the nightly one.
+	sc.now = func() time.Time { return time.Date(2024, 3, 18, 10, 0, 0, 0, time.UTC) }
+	r := gin.New()
+	r.GET("/scheduler/active", sc.Active)
+
+	req := httptest.NewRequest(http.MethodGet, "/scheduler/active", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var states []scheduler.ScheduleState
+	if err := json.Unmarshal(w.Body.Bytes(), &states); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(states) != 2 {
+		t.Fatalf("expected 2 schedules, got %d: %s", len(states), w.Body.String())
+	}
+
+	office := states[0]
+	if office.ScheduleID != "office" || len(office.ActiveTimers) != 1 || len(office.Containers) != 1 || office.Containers[0] != "web" {
+		t.Errorf("expected office to be active for web, got %+v", office)
+	}
+	nightly := states[1]
+	if nightly.ScheduleID != "nightly" || len(nightly.ActiveTimers) != 0 || len(nightly.Containers) != 0 {
+		t.Errorf("expected nightly to have no active timers, got %+v", nightly)
+	}
+	if !strings.Contains(w.Body.String(), `"containers":[]`) {
+		t.Errorf("expected empty containers to be encoded as [], got %s", w.Body.String())
+	}
+}
+
+func TestSchedulerController_NextEvents(t *testing.T) {
+	cfg := &config.Config{Data: config.DataConfig{NextEventsHorizonDays: 7}, Misc: config.MiscConfig{SchedulingTZ: "UTC"}}
+	sc := NewSchedulerController(newPlanStore(), cfg)
+	// Monday 2024-03-18 at noon, inside the office window.
+	sc.now = func() time.Time { return time.Date(2024, 3, 18, 12, 0, 0, 0, time.UTC) }
+
+	r := gin.New()
+	r.GET("/runtime/next-events", sc.NextEvents)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/runtime/next-events", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var events map[string]struct {
+		NextStart *string `json:"nextStart"`
+		NextStop  *string `json:"nextStop"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &events); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	web, ok := events["web"]
+	if !ok {
+		t.Fatalf("expected an entry for web, got %s", w.Body.String())
+	}
+	if web.NextStart == nil || *web.NextStart != "2024-03-19T08:00:00Z" {
+		t.Errorf("expected tomorrow's start, got %v", web.NextStart)
+	}
+	if web.NextStop == nil || *web.NextStop != "2024-03-18T18:00:00Z" {
+		t.Errorf("expected today's stop, got %v", web.NextStop)
+	}
+}
+
+func TestSchedulerController_ScheduleNext(t *testing.T) {
+	cfg := &config.Config{Data: config.DataConfig{NextEventsHorizonDays: 7}, Misc: config.MiscConfig{SchedulingTZ: "UTC"}}
+	sc := NewSchedulerController(newPlanStore(), cfg)
+	// Friday 2024-03-22 after the office window: the next start is on Monday.
+	sc.now = func() time.Time { return time.Date(2024, 3, 22, 19, 0, 0, 0, time.UTC) }
+
+	r := gin.New()
+	r.GET("/schedule/:id/next", sc.ScheduleNext)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/schedule/office/next", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		ScheduleID string  `json:"scheduleId"`
+		Timezone   string  `json:"timezone"`
+		NextStart  *string `json:"nextStart"`
+		NextStop   *string `json:"nextStop"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.ScheduleID != "office" || resp.Timezone != "UTC" {
+		t.Errorf("unexpected schedule/timezone: %s", w.Body.String())
+	}
+	if resp.NextStart == nil || *resp.NextStart != "2024-03-25T08:00:00Z" {
+		t.Errorf("expected Monday's start, got %v", resp.NextStart)
+	}
+	if resp.NextStop == nil || *resp.NextStop != "2024-03-25T18:00:00Z" {
+		t.Errorf("expected Monday's stop, got %v", resp.NextStop)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/schedule/missing/next", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown schedule, got %d", w.Code)
+	}
+}
+
+func TestSchedulerController_Preview(t *testing.T) {
+	sc := NewSchedulerController(newPlanStore(), &config.Config{Data: config.DataConfig{PlanMaxDays: 31}, Misc: config.MiscConfig{SchedulingTZ: "UTC"}})
+	r := gin.New()
+	r.GET("/schedules/preview", sc.Preview)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/schedules/preview?from=2024-03-18&to=2024-03-20", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp PreviewResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(resp.Actions) != 4 || resp.Actions[0].Action != scheduler.ActionStart || resp.Actions[0].ScheduleID != "office" {
+		t.Errorf("expected two days of start/stop actions, got %+v", resp.Actions)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/schedules/preview?from=2024-03-20&to=2024-03-18", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an inverted range, got %d", w.Code)
+	}
+}
@@ -0,0 +1,55 @@
+package controller
+
+import "sync"
+
+// startLimiter bounds the background starts triggered by the waiting page: a name already being
+// started is not started again, and at most max runtime starts run at once (the others queue).
+type startLimiter struct {
+	mu       sync.Mutex
+	inFlight map[string]struct{}
+	slots    chan struct{} // nil when the number of concurrent starts is unlimited
+}
+
+// newStartLimiter creates a limiter allowing max concurrent starts, 0 meaning unlimited.
+func newStartLimiter(max int) *startLimiter {
+	l := &startLimiter{inFlight: map[string]struct{}{}}
+	if max > 0 {
+		l.slots = make(chan struct{}, max)
+	}
+	return l
+}
+
+// reserve marks name as being started; it returns false when a start of name is already queued or running.
+func (l *startLimiter) reserve(name string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.inFlight[name]; ok {
+		return false
+	}
+	l.inFlight[name] = struct{}{}
+	return true
+}
+
+// acquire blocks until a start slot is free.
+func (l *startLimiter) acquire() {
+	if l.slots != nil {
+		l.slots <- struct{}{}
+	}
+}
+
+// release frees the slot taken by acquire and the reservation of name.
+func (l *startLimiter) release(name string) {
+	if l.slots != nil {
+		<-l.slots
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.inFlight, name)
+}
+
+// cancel drops the reservation of name without releasing a slot, for reservations made without acquire.
+func (l *startLimiter) cancel(name string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.inFlight, name)
+}
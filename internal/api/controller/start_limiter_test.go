@@ -0,0 +1,112 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bassista/go_spin/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+func TestStartLimiter_ReserveDeduplicatesNames(t *testing.T) {
+	l := newStartLimiter(1)
+	if !l.reserve("web") {
+		t.Fatal("expected first reservation to succeed")
+	}
+	if l.reserve("web") {
+		t.Error("expected a second reservation of the same name to fail")
+	}
+	if !l.reserve("db") {
+		t.Error("expected another name to be reservable")
+	}
+	l.acquire()
+	l.release("web")
+	if !l.reserve("web") {
+		t.Error("expected the name to be reservable again after release")
+	}
+}
+
+// blockingStartRuntime counts concurrent Start calls, which block until release is closed.
+type blockingStartRuntime struct {
+	*mockContainerRuntime
+	release    chan struct{}
+	current    atomic.Int64
+	max        atomic.Int64
+	startCalls atomic.Int64
+}
+
+func (b *blockingStartRuntime) Start(ctx context.Context, name string) error {
+	b.startCalls.Add(1)
+	n := b.current.Add(1)
+	for {
+		m := b.max.Load()
+		if n <= m || b.max.CompareAndSwap(m, n) {
+			break
+		}
+	}
+	<-b.release
+	b.current.Add(-1)
+	return b.mockContainerRuntime.Start(ctx, name)
+}
+
+func TestRuntimeController_WaitingPage_StartConcurrencyCap(t *testing.T) {
+	const containers = 10
+	const limit = 2
+
+	doc := repository.DataDocument{}
+	for i := 0; i < containers; i++ {
+		doc.Containers = append(doc.Containers, repository.Container{Name: fmt.Sprintf("c%d", i), URL: "http://localhost", Active: boolPtr(true)})
+	}
+	rt := &blockingStartRuntime{mockContainerRuntime: newMockRuntime(), release: make(chan struct{})}
+	rt.startCh = make(chan string, containers)
+	appCtx := newTestAppCtx(rt, &mockAppStore{doc: doc})
+	appCtx.Config.Misc.WaitingStartConcurrency = limit
+	rc := NewRuntimeController(appCtx)
+
+	r := gin.New()
+	r.GET("/start/:name", rc.WaitingPage)
+
+	// Every container is hit several times concurrently, as with a viral link.
+	var wg sync.WaitGroup
+	for i := 0; i < containers; i++ {
+		for j := 0; j < 3; j++ {
+			wg.Add(1)
+			go func(name string) {
+				defer wg.Done()
+				w := httptest.NewRecorder()
+				r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/start/"+name, nil))
+				if w.Code != http.StatusOK {
+					t.Errorf("expected status 200 for %s, got %d", name, w.Code)
+				}
+			}(fmt.Sprintf("c%d", i))
+		}
+	}
+	wg.Wait()
+
+	// Let the queued starts pile up against the cap.
+	time.Sleep(100 * time.Millisecond)
+	if got := rt.current.Load(); got != limit {
+		t.Errorf("expected %d starts in flight, got %d", limit, got)
+	}
+	close(rt.release)
+
+	for i := 0; i < containers; i++ {
+		select {
+		case <-rt.startCh:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for starts, got %d of %d", i, containers)
+		}
+	}
+	if got := rt.max.Load(); got > limit {
+		t.Errorf("expected at most %d concurrent starts, got %d", limit, got)
+	}
+	if got := rt.startCalls.Load(); got != containers {
+		t.Errorf("expected one start per container, got %d", got)
+	}
+}
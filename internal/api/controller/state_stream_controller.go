@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/bassista/go_spin/internal/app"
+	"github.com/bassista/go_spin/internal/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// stateStreamHeartbeat is the interval of the keep-alive comments sent on an idle state stream,
+// so proxies do not close the connection.
+const stateStreamHeartbeat = 30 * time.Second
+
+// stateEventName is the SSE event name of the container state updates.
+const stateEventName = "state"
+
+// StateStreamController pushes container state changes to the UI as Server-Sent Events.
+type StateStreamController struct {
+	bus       *app.StateBus
+	heartbeat time.Duration
+}
+
+// NewStateStreamController creates a new StateStreamController streaming the events of bus.
+func NewStateStreamController(bus *app.StateBus) *StateStreamController {
+	return &StateStreamController{bus: bus, heartbeat: stateStreamHeartbeat}
+}
+
+// Stream handles GET /ws/state - a Server-Sent Events stream of "state" events
+// ({container, running, cpu_percent?, memory_mb?}): the last known state of every container
+// first, then each change until the client disconnects.
+func (sc *StateStreamController) Stream(c *gin.Context) {
+	logger.WithComponent("state-stream").Debugf("GET /ws/state handler called")
+
+	events, unsubscribe := sc.bus.Subscribe()
+	defer unsubscribe()
+
+	// The stream outlives server.write_timeout_secs: lift the deadline of this response only.
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		logger.WithComponent("state-stream").Debugf("cannot lift write deadline: %v", err)
+	}
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+
+	for _, ev := range sc.bus.Current() {
+		c.SSEvent(stateEventName, ev)
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(sc.heartbeat)
+	defer heartbeat.Stop()
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case ev := <-events:
+			c.SSEvent(stateEventName, ev)
+		case <-heartbeat.C:
+			_, _ = io.WriteString(w, ": keep-alive\n\n")
+		}
+		return true
+	})
+	logger.WithComponent("state-stream").Debugf("state stream closed")
+}
@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"bufio"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bassista/go_spin/internal/app"
+	"github.com/gin-gonic/gin"
+)
+
+func TestStateStreamController_StreamsCurrentStateAndChanges(t *testing.T) {
+	bus := app.NewStateBus()
+	bus.Publish(app.StateEvent{Container: "web", Running: true})
+	sc := NewStateStreamController(bus)
+
+	r := gin.New()
+	r.GET("/ws/state", sc.Stream)
+	// The stream needs a real connection: gin's Stream relies on close notification.
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	resp, err := srv.Client().Get(srv.URL + "/ws/state")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/event-stream") {
+		t.Fatalf("expected an event stream, got %s", ct)
+	}
+
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			if line := scanner.Text(); strings.HasPrefix(line, "data:") {
+				lines <- line
+			}
+		}
+		close(lines)
+	}()
+	next := func() string {
+		select {
+		case line := <-lines:
+			return line
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for a state event")
+			return ""
+		}
+	}
+
+	if first := next(); !strings.Contains(first, `"container":"web"`) || !strings.Contains(first, `"running":true`) {
+		t.Errorf("expected the current state of web first, got %s", first)
+	}
+
+	// Wait for the subscription before publishing the change.
+	deadline := time.Now().Add(2 * time.Second)
+	for bus.Subscribers() == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	bus.Publish(app.StateEvent{Container: "web", Running: false})
+	if change := next(); !strings.Contains(change, `"running":false`) {
+		t.Errorf("expected the stop of web, got %s", change)
+	}
+}
@@ -0,0 +1,31 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/bassista/go_spin/internal/janitor"
+	"github.com/bassista/go_spin/internal/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// StatusResponse is the result of GET /status.
+type StatusResponse struct {
+	// Sizes is the current number of entries of every in-memory structure pruned by the janitor.
+	Sizes map[string]int `json:"sizes"`
+}
+
+// StatusController exposes internal state useful to observe the process.
+type StatusController struct {
+	janitor *janitor.Janitor
+}
+
+// NewStatusController creates a new StatusController.
+func NewStatusController(j *janitor.Janitor) *StatusController {
+	return &StatusController{janitor: j}
+}
+
+// Get handles GET /status - returns the sizes of the in-memory structures kept by the janitor.
+func (sc *StatusController) Get(c *gin.Context) {
+	logger.WithComponent("status-controller").Debugf("GET /status handler called")
+	c.JSON(http.StatusOK, StatusResponse{Sizes: sc.janitor.Sizes()})
+}
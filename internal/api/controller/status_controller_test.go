@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bassista/go_spin/internal/janitor"
+	"github.com/bassista/go_spin/internal/scheduler"
+	"github.com/gin-gonic/gin"
+)
+
+func TestStatusController_Get_ReportsSizes(t *testing.T) {
+	now := time.Now()
+	ext := scheduler.NewExtensions()
+	ext.Set("s1", 30, now)
+	ext.Set("s2", 30, now.AddDate(0, 0, -1))
+
+	j := janitor.New(func() time.Time { return now })
+	j.Register("schedule_extensions", ext)
+	sc := NewStatusController(j)
+
+	r := gin.New()
+	r.GET("/status", sc.Get)
+	get := func() StatusResponse {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/status", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", w.Code)
+		}
+		var resp StatusResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		return resp
+	}
+
+	if got := get().Sizes["schedule_extensions"]; got != 2 {
+		t.Errorf("expected 2 extensions before the janitor runs, got %d", got)
+	}
+	j.RunOnce()
+	if got := get().Sizes["schedule_extensions"]; got != 1 {
+		t.Errorf("expected 1 extension after the janitor runs, got %d", got)
+	}
+}
@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/bassista/go_spin/internal/api/apierror"
+	"github.com/bassista/go_spin/internal/cache"
+	"github.com/bassista/go_spin/internal/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// TenantSummary describes a tenant data document, served under /api/v1/tenants/<name>.
+type TenantSummary struct {
+	Name       string `json:"name"`
+	Containers int    `json:"containers"`
+	Groups     int    `json:"groups"`
+	Schedules  int    `json:"schedules"`
+}
+
+// TenantController lists the tenant data documents loaded from data.tenants_dir.
+type TenantController struct {
+	stores map[string]cache.ReadOnlyStore
+}
+
+// NewTenantController creates a new TenantController over the stores of the tenants, by name.
+func NewTenantController(stores map[string]cache.ReadOnlyStore) *TenantController {
+	return &TenantController{stores: stores}
+}
+
+// List handles GET /tenants - returns the tenants sorted by name with the size of their document.
+func (tc *TenantController) List(c *gin.Context) {
+	tenants := make([]TenantSummary, 0, len(tc.stores))
+	for name, store := range tc.stores {
+		doc, err := store.Snapshot()
+		if err != nil {
+			logger.WithComponent("tenant-controller").Errorf("failed to read snapshot of tenant %s: %v", name, err)
+			apierror.Respond(c, http.StatusInternalServerError, "failed to read tenants")
+			return
+		}
+		tenants = append(tenants, TenantSummary{
+			Name:       name,
+			Containers: len(doc.Containers),
+			Groups:     len(doc.Groups),
+			Schedules:  len(doc.Schedules),
+		})
+	}
+	sort.Slice(tenants, func(i, j int) bool { return tenants[i].Name < tenants[j].Name })
+	c.JSON(http.StatusOK, tenants)
+}
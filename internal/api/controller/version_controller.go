@@ -0,0 +1,37 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/bassista/go_spin/internal/build"
+	"github.com/bassista/go_spin/internal/config"
+	"github.com/gin-gonic/gin"
+)
+
+// VersionResponse reports the build metadata and the effective runtime settings.
+type VersionResponse struct {
+	build.Info
+	RuntimeType       string `json:"runtimeType"`
+	SchedulingEnabled bool   `json:"schedulingEnabled"`
+}
+
+// VersionController handles the version endpoint.
+type VersionController struct {
+	config *config.Config
+}
+
+// NewVersionController creates a new VersionController.
+func NewVersionController(cfg *config.Config) *VersionController {
+	return &VersionController{
+		config: cfg,
+	}
+}
+
+// GetVersion handles GET /version - returns build info, runtime type and scheduling state.
+func (vc *VersionController) GetVersion(c *gin.Context) {
+	c.JSON(http.StatusOK, VersionResponse{
+		Info:              build.Current(),
+		RuntimeType:       vc.config.Misc.RuntimeType,
+		SchedulingEnabled: vc.config.Data.SchedulingEnabled,
+	})
+}
@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bassista/go_spin/internal/build"
+	"github.com/bassista/go_spin/internal/config"
+	"github.com/gin-gonic/gin"
+)
+
+func TestVersionController_GetVersion_Defaults(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cfg := &config.Config{}
+	cfg.Misc.RuntimeType = "memory"
+	cfg.Data.SchedulingEnabled = true
+	vc := NewVersionController(cfg)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/version", nil)
+	vc.GetVersion(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	var resp VersionResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.Version != "dev" || resp.Commit != "dev" || resp.BuildDate != "dev" {
+		t.Errorf("expected dev build info, got %+v", resp.Info)
+	}
+	if resp.RuntimeType != "memory" {
+		t.Errorf("expected runtimeType memory, got %q", resp.RuntimeType)
+	}
+	if !resp.SchedulingEnabled {
+		t.Error("expected schedulingEnabled true")
+	}
+}
+
+func TestVersionController_GetVersion_Injected(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	origVersion, origCommit, origDate := build.Version, build.Commit, build.BuildDate
+	t.Cleanup(func() {
+		build.Version, build.Commit, build.BuildDate = origVersion, origCommit, origDate
+	})
+	build.Version, build.Commit, build.BuildDate = "1.2.3", "abc1234", "2024-01-02T03:04:05Z"
+
+	cfg := &config.Config{}
+	cfg.Misc.RuntimeType = "docker"
+	vc := NewVersionController(cfg)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/version", nil)
+	vc.GetVersion(c)
+
+	var resp VersionResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	want := build.Info{Version: "1.2.3", Commit: "abc1234", BuildDate: "2024-01-02T03:04:05Z"}
+	if resp.Info != want {
+		t.Errorf("expected %+v, got %+v", want, resp.Info)
+	}
+	if resp.RuntimeType != "docker" {
+		t.Errorf("expected runtimeType docker, got %q", resp.RuntimeType)
+	}
+	if resp.SchedulingEnabled {
+		t.Error("expected schedulingEnabled false")
+	}
+}
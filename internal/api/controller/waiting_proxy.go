@@ -0,0 +1,151 @@
+package controller
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"time"
+
+	"github.com/bassista/go_spin/internal/api/apierror"
+	"github.com/bassista/go_spin/internal/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// Proxy handles any request to /:name and /:name/*path on the waiting server when
+// misc.waiting_proxy is enabled. The container is started when it is not running and the request
+// is forwarded to its URL (with the part of the path after the name), retrying while the container
+// does not accept connections, up to misc.waiting_proxy_timeout_millis.
+// Returns 404 if the container is not found, the inactive status if it is not active and 504 when
+// the container does not answer in time.
+func (rc *RuntimeController) Proxy(c *gin.Context) {
+	name := c.Param("name")
+	if name == "" {
+		apierror.Respond(c, http.StatusBadRequest, "missing container name")
+		return
+	}
+
+	doc, err := rc.containerStore.Snapshot()
+	if err != nil {
+		logger.WithComponent("waiting-proxy").Errorf("failed to read container list: %v", err)
+		apierror.Respond(c, http.StatusInternalServerError, "failed to read container list")
+		return
+	}
+	container, found := rc.findContainer(doc, name)
+	if !found {
+		apierror.RespondCode(c, http.StatusNotFound, apierror.CodeContainerNotFound, fmt.Sprintf("container '%s' not found", name))
+		return
+	}
+	if container.Active == nil || !*container.Active {
+		rc.respondInactive(c, fmt.Sprintf("container '%s' is not active", container.Name))
+		return
+	}
+	if rc.abortIfNotAllowed(c, container.Name, container.AllowedUsers) {
+		return
+	}
+
+	target, err := url.Parse(rc.redirectURL(container.URL))
+	if err != nil || !target.IsAbs() || target.Host == "" {
+		logger.WithComponent("waiting-proxy").Errorf("container %s has no usable url %q: %v", container.Name, container.URL, err)
+		apierror.Respond(c, http.StatusBadGateway, fmt.Sprintf("container '%s' has no valid url", container.Name))
+		return
+	}
+
+	// The body is buffered so it can be replayed on every connection attempt while the container starts
+	maxBody := int64(rc.config.Misc.WaitingProxyMaxBodyMB) << 20
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxBody))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			apierror.Respond(c, http.StatusRequestEntityTooLarge, "request body too large")
+			return
+		}
+		apierror.Respond(c, http.StatusBadRequest, "cannot read request body")
+		return
+	}
+
+	running, err := rc.runtime.IsRunning(c.Request.Context(), container.Name)
+	if err != nil {
+		logger.WithComponent("waiting-proxy").Warnf("failed to check if container %s is running: %v", container.Name, err)
+		if rc.abortIfRuntimeUnavailable(c, container.Name, err) {
+			return
+		}
+		running = false
+	}
+	if !running {
+		rc.startFromWaitingPage(rc.background(c), container.Name)
+	}
+
+	timeout := rc.config.Misc.WaitingProxyTimeout
+	// The retries may outlast server.write_timeout_secs: extend the deadline of this response only.
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Now().Add(timeout + waitWriteSlack)); err != nil {
+		logger.WithComponent("waiting-proxy").Debugf("cannot extend write deadline: %v", err)
+	}
+
+	transport := &retryTransport{
+		base:     http.DefaultTransport,
+		body:     body,
+		deadline: time.Now().Add(timeout),
+		interval: rc.waitPoll,
+	}
+	path := c.Param("path")
+	proxy := &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.Out.URL.Path = path
+			pr.Out.URL.RawPath = ""
+			pr.SetURL(target)
+			pr.SetXForwarded()
+		},
+		Transport: transport,
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			status := http.StatusBadGateway
+			if errors.Is(err, errProxyTimeout) {
+				status = http.StatusGatewayTimeout
+			}
+			logger.WithComponent("waiting-proxy").Warnf("proxying %s to %s failed: %v", r.URL.Path, container.Name, err)
+			apierror.Respond(c, status, fmt.Sprintf("container '%s' did not respond", container.Name))
+		},
+	}
+	logger.WithComponent("waiting-proxy").Debugf("proxying %s %s to %s (running=%v)", c.Request.Method, path, target, running)
+	proxy.ServeHTTP(c.Writer, c.Request)
+}
+
+// errProxyTimeout is returned by retryTransport when the container never accepted the request.
+var errProxyTimeout = errors.New("container did not respond in time")
+
+// retryTransport replays a buffered request until the upstream answers (any HTTP status) or the
+// deadline passes, so a request reaching a container that is still booting is held instead of failing.
+type retryTransport struct {
+	base     http.RoundTripper
+	body     []byte
+	deadline time.Time
+	interval time.Duration
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for {
+		attempt := req.Clone(req.Context())
+		attempt.Body = http.NoBody
+		if len(t.body) > 0 {
+			attempt.Body = io.NopCloser(bytes.NewReader(t.body))
+		}
+		attempt.ContentLength = int64(len(t.body))
+
+		resp, err := t.base.RoundTrip(attempt)
+		if err == nil {
+			return resp, nil
+		}
+		if time.Now().Add(t.interval).After(t.deadline) {
+			return nil, fmt.Errorf("%w: %v", errProxyTimeout, err)
+		}
+		logger.WithComponent("waiting-proxy").Debugf("upstream %s not answering yet, retrying in %v: %v", req.URL.Host, t.interval, err)
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(t.interval):
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package controller
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bassista/go_spin/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// newProxyTestServer serves the proxy routes on a real listener: httputil.ReverseProxy needs a
+// response writer supporting close notification, which the recorder does not provide.
+func newProxyTestServer(t *testing.T, rc *RuntimeController) *httptest.Server {
+	t.Helper()
+	r := gin.New()
+	r.Any("/:name", rc.Proxy)
+	r.Any("/:name/*path", rc.Proxy)
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// doProxyRequest sends a request to the proxy server and returns status and body.
+func doProxyRequest(t *testing.T, srv *httptest.Server, method, path string, body io.Reader) (int, string) {
+	t.Helper()
+	req, err := http.NewRequest(method, srv.URL+path, body)
+	if err != nil {
+		t.Fatalf("failed to build request: %v", err)
+	}
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	respBody, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(respBody)
+}
+
+func TestRuntimeController_Proxy_ForwardsToContainer(t *testing.T) {
+	var gotPath, gotQuery, gotBody string
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotQuery = r.URL.Path, r.URL.RawQuery
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("from backend"))
+	}))
+	defer backend.Close()
+
+	rt := newMockRuntime()
+	rt.runningContainers["app"] = true
+	store := &mockAppStore{doc: repository.DataDocument{Containers: []repository.Container{
+		{Name: "app", FriendlyName: "app", URL: backend.URL, Active: boolPtr(true)},
+	}}}
+	appCtx := newTestAppCtx(rt, store)
+	appCtx.Config.Misc.WaitingProxyTimeout = time.Second
+	appCtx.Config.Misc.WaitingProxyMaxBodyMB = 1
+	rc := NewRuntimeController(appCtx)
+
+	status, body := doProxyRequest(t, newProxyTestServer(t, rc), http.MethodPost, "/app/api/items?page=2", bytes.NewBufferString(`{"a":1}`))
+	if status != http.StatusCreated || body != "from backend" {
+		t.Fatalf("expected the backend response, got %d: %s", status, body)
+	}
+	if gotPath != "/api/items" || gotQuery != "page=2" || gotBody != `{"a":1}` {
+		t.Errorf("unexpected upstream request: path=%q query=%q body=%q", gotPath, gotQuery, gotBody)
+	}
+	select {
+	case name := <-rt.startCh:
+		t.Errorf("expected no start for a running container, got %s", name)
+	default:
+	}
+}
+
+func TestRuntimeController_Proxy_StartsStoppedContainer(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer backend.Close()
+
+	rt := newMockRuntime()
+	store := &mockAppStore{doc: repository.DataDocument{Containers: []repository.Container{
+		{Name: "app", FriendlyName: "app", URL: backend.URL, Active: boolPtr(true)},
+	}}}
+	rc := NewRuntimeController(newTestAppCtx(rt, store))
+
+	if status, body := doProxyRequest(t, newProxyTestServer(t, rc), http.MethodGet, "/app", nil); status != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", status, body)
+	}
+	select {
+	case name := <-rt.startCh:
+		if name != "app" {
+			t.Errorf("expected app to be started, got %s", name)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the stopped container to be started")
+	}
+}
+
+func TestRuntimeController_Proxy_NotFoundAndInactive(t *testing.T) {
+	store := &mockAppStore{doc: repository.DataDocument{Containers: []repository.Container{
+		{Name: "off", FriendlyName: "off", URL: "http://off.local", Active: boolPtr(false)},
+	}}}
+	srv := newProxyTestServer(t, NewRuntimeController(newTestAppCtx(newMockRuntime(), store)))
+
+	if status, _ := doProxyRequest(t, srv, http.MethodGet, "/missing/x", nil); status != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", status)
+	}
+	if status, _ := doProxyRequest(t, srv, http.MethodGet, "/off/x", nil); status != http.StatusForbidden {
+		t.Errorf("expected status 403 for an inactive container, got %d", status)
+	}
+}
+
+// failingReader fails every read, like a client dropping the connection mid-body.
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) { return 0, errors.New("connection reset") }
+
+func TestRuntimeController_Proxy_BodyErrors(t *testing.T) {
+	store := &mockAppStore{doc: repository.DataDocument{Containers: []repository.Container{
+		{Name: "app", FriendlyName: "app", URL: "http://app.local", Active: boolPtr(true)},
+	}}}
+	appCtx := newTestAppCtx(newMockRuntime(), store)
+	appCtx.Config.Misc.WaitingProxyMaxBodyMB = 1
+	r := gin.New()
+	r.Any("/:name/*path", NewRuntimeController(appCtx).Proxy)
+
+	tests := []struct {
+		name   string
+		body   io.Reader
+		status int
+	}{
+		{"over misc.waiting_proxy_max_body_mb", bytes.NewReader(make([]byte, 1<<20+1)), http.StatusRequestEntityTooLarge},
+		{"unreadable", failingReader{}, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/app/upload", tt.body))
+			if w.Code != tt.status {
+				t.Errorf("expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+// flakyRoundTripper fails the first failures attempts, then answers 200 echoing the body.
+type flakyRoundTripper struct {
+	failures int
+	attempts int
+	bodies   []string
+}
+
+func (f *flakyRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.attempts++
+	body, _ := io.ReadAll(req.Body)
+	f.bodies = append(f.bodies, string(body))
+	if f.attempts <= f.failures {
+		return nil, errors.New("connection refused")
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("ok")), Request: req}, nil
+}
+
+func TestRetryTransport_ReplaysBodyUntilUpstreamAnswers(t *testing.T) {
+	base := &flakyRoundTripper{failures: 2}
+	transport := &retryTransport{base: base, body: []byte("payload"), deadline: time.Now().Add(time.Second), interval: time.Millisecond}
+
+	req := httptest.NewRequest(http.MethodPost, "http://upstream/", nil)
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = resp.Body.Close()
+	if base.attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", base.attempts)
+	}
+	for i, body := range base.bodies {
+		if body != "payload" {
+			t.Errorf("attempt %d: expected the buffered body, got %q", i, body)
+		}
+	}
+}
+
+func TestRetryTransport_TimesOut(t *testing.T) {
+	base := &flakyRoundTripper{failures: 1000}
+	transport := &retryTransport{base: base, deadline: time.Now().Add(20 * time.Millisecond), interval: 5 * time.Millisecond}
+
+	_, err := transport.RoundTrip(httptest.NewRequest(http.MethodGet, "http://upstream/", nil))
+	if !errors.Is(err, errProxyTimeout) {
+		t.Fatalf("expected errProxyTimeout, got %v", err)
+	}
+}
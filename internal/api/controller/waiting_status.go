@@ -0,0 +1,140 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/bassista/go_spin/internal/api/apierror"
+	"github.com/bassista/go_spin/internal/logger"
+	"github.com/bassista/go_spin/internal/ops"
+	"github.com/bassista/go_spin/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// States reported by the waiting status endpoint.
+const (
+	WaitingStateStarting = "starting"
+	WaitingStateFailed   = "failed"
+	WaitingStateReady    = "ready"
+)
+
+// reasonExitedAfterStart is reported as failure for a container that stopped after a successful
+// start, e.g. one crashing on boot.
+const reasonExitedAfterStart = "exited after start"
+
+// WaitingStatusResponse is the state of a container or group woken by a waiting page.
+type WaitingStatusResponse struct {
+	Name  string `json:"name"`
+	State string `json:"state"` // starting, failed or ready
+	// Reason tells why a starting entry is not ready yet, or why it failed.
+	Reason string `json:"reason,omitempty"`
+	// RedirectURL is the member to open once a group is ready, as in GroupReadyResponse.
+	RedirectURL string `json:"redirectUrl,omitempty"`
+}
+
+// SetOperations makes the status endpoint consult queue for the failed starts and the starts in
+// flight; without it only the recorded LastError marks a failure.
+func (cc *ContainerController) SetOperations(queue *ops.Queue) {
+	cc.ops = queue
+}
+
+// Status reports whether the container or group identified by name is still starting, failed
+// (LastError recorded, start failed, or stopped again right after starting) or ready, so the
+// waiting page can show the failure instead of polling until it gives up.
+// Routes: GET /:name/status on the waiting server, GET /container/:name/status
+func (cc *ContainerController) Status(c *gin.Context) {
+	name := c.Param("name")
+	svc, ok := cc.crud.Service.(*ContainerCrudService)
+	if !ok {
+		logger.WithComponent("container-controller").Errorf("status: unexpected service type")
+		apierror.Respond(c, http.StatusInternalServerError, "unexpected service type")
+		return
+	}
+	doc, err := svc.Store.Snapshot()
+	if err != nil {
+		logger.WithComponent("container-controller").Errorf("status: failed to snapshot store: %v", err)
+		apierror.Respond(c, http.StatusInternalServerError, "failed to read container list")
+		return
+	}
+
+	if container, found := findDocContainer(doc, name); found {
+		state, reason := cc.containerState(c.Request.Context(), svc, container)
+		c.JSON(http.StatusOK, WaitingStatusResponse{Name: name, State: state, Reason: reason})
+		return
+	}
+	for _, group := range doc.Groups {
+		if group.Name != name {
+			continue
+		}
+		readiness := groupReadiness(c.Request.Context(), svc.Runtime, doc, group, func(ctx context.Context, container *repository.Container) (bool, string) {
+			ready, reason, _ := cc.probeReady(ctx, container)
+			return ready, reason
+		})
+		resp := WaitingStatusResponse{Name: name, State: WaitingStateStarting, Reason: readiness.Reason}
+		if readiness.Ready {
+			resp.State, resp.Reason = WaitingStateReady, ""
+			resp.RedirectURL = absoluteURL(readiness.RedirectURL, cc.urlScheme)
+		} else {
+			// One failed member is enough: the group cannot become ready on its own
+			for _, member := range readiness.Containers {
+				if container, found := findDocContainer(doc, member.Name); found && !member.Ready {
+					if reason := cc.failure(container, member.Reason == reasonNotRunning); reason != "" {
+						resp.State, resp.Reason = WaitingStateFailed, member.Name+": "+reason
+						break
+					}
+				}
+			}
+		}
+		c.JSON(http.StatusOK, resp)
+		return
+	}
+	apierror.Respond(c, http.StatusNotFound, "container or group not found")
+}
+
+// containerState returns the waiting state of container and its reason.
+func (cc *ContainerController) containerState(ctx context.Context, svc *ContainerCrudService, container *repository.Container) (string, string) {
+	running, err := svc.Runtime.IsRunning(ctx, container.Name)
+	if err != nil {
+		logger.WithComponent("container-controller").Warnf("status: runtime check failed for %s: %v", container.Name, err)
+		if reason := cc.failure(container, false); reason != "" {
+			return WaitingStateFailed, reason
+		}
+		return WaitingStateStarting, reasonRuntimeError
+	}
+	if reason := cc.failure(container, !running); reason != "" {
+		return WaitingStateFailed, reason
+	}
+	if !running {
+		return WaitingStateStarting, reasonNotRunning
+	}
+	if !hasReadinessCheck(container) {
+		return WaitingStateReady, ""
+	}
+	if ready, reason, _ := cc.probeReady(ctx, container); !ready {
+		return WaitingStateStarting, reason
+	}
+	return WaitingStateReady, ""
+}
+
+// failure returns why the start of container failed, empty while it may still succeed: the
+// recorded LastError, the error of the last start operation, or, when the container is not
+// running, its exit after the last start succeeded.
+func (cc *ContainerController) failure(container *repository.Container, stopped bool) string {
+	if container.LastError != "" {
+		return container.LastError
+	}
+	if cc.ops == nil {
+		return ""
+	}
+	op, ok := cc.ops.Last(container.Name)
+	if !ok || op.Action != ops.ActionStart {
+		return ""
+	}
+	switch {
+	case op.Status == ops.StatusFailed:
+		return op.Error
+	case op.Status == ops.StatusSucceeded && stopped:
+		return reasonExitedAfterStart
+	}
+	return ""
+}
@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bassista/go_spin/internal/ops"
+	"github.com/bassista/go_spin/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+func TestContainerController_Status(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	rt := newMockRuntime()
+	rt.runningContainers["web"] = true
+	queue := ops.NewQueue(ctx, rt, time.Minute)
+	// crashed started fine, then exited on boot
+	if err := queue.Do(ctx, ops.ActionStart, "crashed"); err != nil {
+		t.Fatal(err)
+	}
+	rt.runningContainers["crashed"] = false
+
+	store := &mockContainerStore{doc: repository.DataDocument{
+		Containers: []repository.Container{
+			{Name: "web", Active: boolPtr(true)},
+			{Name: "booting", Active: boolPtr(true)},
+			{Name: "slow", Active: boolPtr(true), LastError: startupTimeoutError},
+			{Name: "crashed", Active: boolPtr(true)},
+		},
+		Groups: []repository.Group{
+			{Name: "stack", Container: []string{"web", "crashed"}, Active: boolPtr(true)},
+			{Name: "site", Container: []string{"web"}, Active: boolPtr(true)},
+		},
+	}}
+	cc := NewContainerController(ctx, store, rt)
+	cc.SetOperations(queue)
+	r := gin.New()
+	r.GET("/:name/status", cc.Status)
+
+	tests := []struct {
+		name   string
+		status int
+		state  string
+		reason string
+	}{
+		{"web", http.StatusOK, WaitingStateReady, ""},
+		{"booting", http.StatusOK, WaitingStateStarting, reasonNotRunning},
+		{"slow", http.StatusOK, WaitingStateFailed, startupTimeoutError},
+		{"crashed", http.StatusOK, WaitingStateFailed, reasonExitedAfterStart},
+		{"stack", http.StatusOK, WaitingStateFailed, "crashed: " + reasonExitedAfterStart},
+		{"site", http.StatusOK, WaitingStateReady, ""},
+		{"missing", http.StatusNotFound, "", ""},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/"+tt.name+"/status", nil))
+		if w.Code != tt.status {
+			t.Errorf("%s: expected status %d, got %d", tt.name, tt.status, w.Code)
+			continue
+		}
+		if tt.status != http.StatusOK {
+			continue
+		}
+		var resp WaitingStatusResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		if resp.State != tt.state || resp.Reason != tt.reason {
+			t.Errorf("%s: expected %s %q, got %s %q", tt.name, tt.state, tt.reason, resp.State, resp.Reason)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/bassista/go_spin/internal/api/apierror"
+	"github.com/bassista/go_spin/internal/logger"
+	"github.com/bassista/go_spin/internal/waiting"
+	"github.com/gin-gonic/gin"
+)
+
+// maxWaitingTemplateBytes bounds the body accepted by PUT /waiting-template.
+const maxWaitingTemplateBytes = 1 << 20
+
+// WaitingTemplateController exposes the waiting page template for editing.
+type WaitingTemplateController struct {
+	template *waiting.Template
+}
+
+// NewWaitingTemplateController creates a new WaitingTemplateController.
+func NewWaitingTemplateController(template *waiting.Template) *WaitingTemplateController {
+	return &WaitingTemplateController{template: template}
+}
+
+// Get handles GET /waiting-template - returns the raw template text.
+func (wc *WaitingTemplateController) Get(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(wc.template.Content()))
+}
+
+// Put handles PUT /waiting-template - validates the raw body, writes it to the template file and
+// serves it from now on. An invalid template is rejected with 400 and the current one is kept.
+func (wc *WaitingTemplateController) Put(c *gin.Context) {
+	logger.WithComponent("waiting-template-controller").Debugf("PUT /waiting-template handler called")
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWaitingTemplateBytes+1))
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, "invalid payload")
+		return
+	}
+	if len(body) > maxWaitingTemplateBytes {
+		apierror.Respond(c, http.StatusRequestEntityTooLarge, "template too large")
+		return
+	}
+
+	if err := wc.template.Update(string(body)); err != nil {
+		if errors.Is(err, waiting.ErrInvalidTemplate) {
+			apierror.RespondError(c, http.StatusBadRequest, err)
+			return
+		}
+		logger.WithComponent("waiting-template-controller").Errorf("failed to save waiting template: %v", err)
+		apierror.Respond(c, http.StatusInternalServerError, "failed to save template")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,126 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bassista/go_spin/internal/repository"
+	"github.com/bassista/go_spin/internal/waiting"
+	"github.com/gin-gonic/gin"
+)
+
+const testWaitingTemplate = "<html>{{CONTAINER_NAME}} -> {{REDIRECT_URL}}</html>"
+
+func newWaitingTemplateRouter(t *testing.T) (*gin.Engine, *waiting.Template, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "waiting.html")
+	if err := os.WriteFile(path, []byte(testWaitingTemplate), 0644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+	template := waiting.LoadTemplate(path)
+	wc := NewWaitingTemplateController(template)
+
+	r := gin.New()
+	r.GET("/waiting-template", wc.Get)
+	r.PUT("/waiting-template", wc.Put)
+	return r, template, path
+}
+
+func TestWaitingTemplateController_Get(t *testing.T) {
+	r, _, _ := newWaitingTemplateRouter(t)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/waiting-template", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	if w.Body.String() != testWaitingTemplate {
+		t.Errorf("expected the raw template, got %q", w.Body.String())
+	}
+}
+
+func TestWaitingTemplateController_Put_UpdatesFileAndRendering(t *testing.T) {
+	r, template, path := newWaitingTemplateRouter(t)
+	updated := "<p>{{WAITING_MESSAGE}}</p><a href=\"{{REDIRECT_URL}}\">{{CONTAINER_NAME}}</a>"
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/waiting-template", strings.NewReader(updated)))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d: %s", w.Code, w.Body.String())
+	}
+
+	saved, err := os.ReadFile(path)
+	if err != nil || string(saved) != updated {
+		t.Errorf("expected template written to %s, got %q (%v)", path, saved, err)
+	}
+
+	// The RuntimeController sharing the template renders the new one
+	rc := NewRuntimeController(newTestAppCtx(newMockRuntime(), newMockStoreEmpty()))
+	rc.waitingTemplate = template
+	rr := gin.New()
+	rr.GET("/page", func(c *gin.Context) { rc.serveWaitingPage(c, "app", "http://app.local", "hold on", nil) })
+	w = httptest.NewRecorder()
+	rr.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/page", nil))
+	if !strings.HasPrefix(w.Body.String(), "<p>hold on</p>") {
+		t.Errorf("expected the updated template to be rendered, got %q", w.Body.String())
+	}
+}
+
+func TestWaitingTemplateController_Put_RejectsInvalidTemplate(t *testing.T) {
+	r, template, path := newWaitingTemplateRouter(t)
+
+	for _, body := range []string{
+		"",
+		"<html>{{CONTAINER_NAME}}</html>",
+		"<html>{{CONTAINER_NAME}} {{REDIRECT_URL}} {{CONTAINER_NAM}}</html>",
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/waiting-template", strings.NewReader(body)))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("body %q: expected status 400, got %d", body, w.Code)
+		}
+	}
+
+	if template.Content() != testWaitingTemplate {
+		t.Errorf("expected the old template to be kept, got %q", template.Content())
+	}
+	if saved, _ := os.ReadFile(path); string(saved) != testWaitingTemplate {
+		t.Errorf("expected the template file untouched, got %q", saved)
+	}
+}
+
+func TestRuntimeController_PageTemplate(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "nas.html"), []byte("<p>{{CONTAINER_NAME}} {{REDIRECT_URL}} {{ACCENT_COLOR}}</p>"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rc := NewRuntimeController(newTestAppCtx(newMockRuntime(), newMockStoreEmpty()))
+	rc.waitingTemplate = waiting.NewTemplate(filepath.Join(dir, "waiting.html"), "<p>global {{CONTAINER_NAME}} {{REDIRECT_URL}}</p>")
+	rc.pageTemplates = waiting.NewFileCache(dir)
+
+	tests := []struct {
+		name string
+		page *repository.WaitingPage
+		want string
+	}{
+		{"global", nil, "<p>global app http://app.local</p>"},
+		{"inline", &repository.WaitingPage{Template: "<i>{{CONTAINER_NAME}} {{REDIRECT_URL}} <img src=\"{{LOGO_URL}}\"></i>", LogoURL: "https://cdn.local/a.png?x=1&y=2"},
+			"<i>app http://app.local <img src=\"https://cdn.local/a.png?x=1&amp;y=2\"></i>"},
+		{"file", &repository.WaitingPage{TemplatePath: "nas.html", AccentColor: "#ff6600"}, "<p>app http://app.local #ff6600</p>"},
+		{"missing file", &repository.WaitingPage{TemplatePath: "missing.html"}, "<p>global app http://app.local</p>"},
+		{"outside directory", &repository.WaitingPage{TemplatePath: "../waiting.html"}, "<p>global app http://app.local</p>"},
+	}
+	for _, tt := range tests {
+		r := gin.New()
+		r.GET("/page", func(c *gin.Context) { rc.serveWaitingPage(c, "app", "http://app.local", "hold on", tt.page) })
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/page", nil))
+		if w.Body.String() != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.want, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/bassista/go_spin/internal/api/apierror"
+	"github.com/gin-gonic/gin"
+)
+
+// Headers of the API version negotiation: clients ask a version with Accept-Version, every
+// versioned response tells the version that served it in API-Version.
+const (
+	HeaderAcceptVersion = "Accept-Version"
+	HeaderAPIVersion    = "API-Version"
+)
+
+// APIVersion tags the responses of the routes of version with the API-Version header.
+func APIVersion(version string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header(HeaderAPIVersion, version)
+		c.Next()
+	}
+}
+
+// VersionFallback serves the unversioned paths kept for existing clients, installed as NoRoute
+// handler: the request is dispatched again by engine to versioned(path, version), with the
+// version asked in Accept-Version or defaultVersion. versioned returns "" for the paths it does
+// not map (already versioned ones, static files), which go on to the next handler. Unsupported
+// versions are rejected with 406 and the supported list.
+func VersionFallback(engine *gin.Engine, defaultVersion string, supported []string, versioned func(path, version string) string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		version := c.GetHeader(HeaderAcceptVersion)
+		if version == "" {
+			version = defaultVersion
+		}
+		target := versioned(c.Request.URL.Path, version)
+		if target == "" {
+			c.Next()
+			return
+		}
+		if !slices.Contains(supported, version) {
+			c.AbortWithStatusJSON(http.StatusNotAcceptable, apierror.New(c, apierror.CodeUnsupportedVersion,
+				"unsupported API version "+version, gin.H{"supported": supported}))
+			return
+		}
+		if c.Request.URL.RawPath != "" {
+			c.Request.URL.RawPath = versioned(c.Request.URL.RawPath, version)
+		}
+		c.Request.URL.Path = target
+		engine.HandleContext(c)
+		c.Abort()
+	}
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestVersionFallback(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	versioned := func(path, version string) string {
+		if strings.HasPrefix(path, "/api/") {
+			return ""
+		}
+		return "/api/" + version + path
+	}
+	r.GET("/api/v1/containers", APIVersion("v1"), func(c *gin.Context) { c.String(http.StatusOK, "v1") })
+	r.GET("/api/v2/containers", APIVersion("v2"), func(c *gin.Context) { c.String(http.StatusOK, "v2") })
+	r.NoRoute(VersionFallback(r, "v1", []string{"v1", "v2"}, versioned), func(c *gin.Context) {
+		c.String(http.StatusNotFound, "not found")
+	})
+
+	tests := []struct {
+		name    string
+		path    string
+		version string
+		status  int
+		body    string
+	}{
+		{"default version", "/containers", "", http.StatusOK, "v1"},
+		{"negotiated", "/containers", "v2", http.StatusOK, "v2"},
+		{"unsupported", "/containers", "v9", http.StatusNotAcceptable, ""},
+		{"unknown", "/missing", "", http.StatusNotFound, "not found"},
+		{"versioned unknown", "/api/v1/missing", "", http.StatusNotFound, "not found"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		if tt.version != "" {
+			req.Header.Set(HeaderAcceptVersion, tt.version)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tt.status {
+			t.Errorf("%s: expected status %d, got %d", tt.name, tt.status, w.Code)
+			continue
+		}
+		if tt.body != "" && w.Body.String() != tt.body {
+			t.Errorf("%s: expected body %q, got %q", tt.name, tt.body, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/bassista/go_spin/internal/audit"
+	"github.com/bassista/go_spin/internal/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// actorAnonymous is the actor of the audit entries of requests without authentication.
+const actorAnonymous = "anonymous"
+
+// AuditChanges records in log the entities added, removed or changed by a successful request,
+// comparing the snapshots of their kinds taken by snapshot before and after the handler. Changes
+// made by others (scheduler, discovery, another request) while the handler runs are attributed to
+// the request as well. A nil log disables the middleware.
+func AuditChanges(log *audit.Log, tenant string, snapshot func() (audit.Snapshots, error)) gin.HandlerFunc {
+	return auditChanges(log, tenant, snapshot, nil)
+}
+
+// AuditRename is AuditChanges for the rename of the entity of kind named by the :name path
+// parameter: its delete and the create of the new name are recorded as a single rename entry.
+func AuditRename(log *audit.Log, tenant, kind string, snapshot func() (audit.Snapshots, error)) gin.HandlerFunc {
+	return auditChanges(log, tenant, snapshot, func(c *gin.Context, entries []audit.Entry) []audit.Entry {
+		return audit.MergeRename(entries, kind, c.Param("name"))
+	})
+}
+
+// auditChanges implements AuditChanges, passing the entries through merge when it is set.
+func auditChanges(log *audit.Log, tenant string, snapshot func() (audit.Snapshots, error), merge func(*gin.Context, []audit.Entry) []audit.Entry) gin.HandlerFunc {
+	if log == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	return func(c *gin.Context) {
+		before, err := snapshot()
+		if err != nil {
+			logger.WithContext(c.Request.Context(), "audit").Warnf("cannot snapshot the data document, the changes are not audited: %v", err)
+			c.Next()
+			return
+		}
+		c.Next()
+		if c.Writer.Status() >= http.StatusBadRequest {
+			return
+		}
+		after, err := snapshot()
+		if err != nil {
+			logger.WithContext(c.Request.Context(), "audit").Warnf("cannot snapshot the data document, the changes are not audited: %v", err)
+			return
+		}
+		entries := audit.DiffAll(before, after)
+		if merge != nil {
+			entries = merge(c, entries)
+		}
+		for _, entry := range entries {
+			record(c, log, tenant, entry)
+		}
+	}
+}
+
+// AuditAction records in log action on the kind named by the :name path parameter, when the
+// request succeeds. A nil log disables the middleware.
+func AuditAction(log *audit.Log, tenant, kind, action string) gin.HandlerFunc {
+	if log == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	return func(c *gin.Context) {
+		c.Next()
+		if c.Writer.Status() >= http.StatusBadRequest {
+			return
+		}
+		record(c, log, tenant, audit.Entry{Action: action, Kind: kind, Name: c.Param("name")})
+	}
+}
+
+// contextAuditEntries is the gin context key of the entries reported by ReportAudit.
+const contextAuditEntries = "audit_entries"
+
+// ReportAudit reports an action (Action, Kind and Name of entry) taken by the handler, recorded by
+// AuditReported. It serves handlers whose action depends on the state, e.g. a page starting the
+// container only when it is not running.
+func ReportAudit(c *gin.Context, entry audit.Entry) {
+	c.Set(contextAuditEntries, append(reportedAudit(c), entry))
+}
+
+// reportedAudit returns the entries reported by ReportAudit on c.
+func reportedAudit(c *gin.Context) []audit.Entry {
+	entries, _ := c.Get(contextAuditEntries)
+	reported, _ := entries.([]audit.Entry)
+	return reported
+}
+
+// AuditReported records in log the actions reported by the handler with ReportAudit, whatever the
+// response status since they were taken anyway. A nil log disables the middleware.
+func AuditReported(log *audit.Log, tenant string) gin.HandlerFunc {
+	if log == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	return func(c *gin.Context) {
+		c.Next()
+		for _, entry := range reportedAudit(c) {
+			record(c, log, tenant, entry)
+		}
+	}
+}
+
+// record completes entry with the actor, the tenant and the request ID of c and appends it to log.
+func record(c *gin.Context, log *audit.Log, tenant string, entry audit.Entry) {
+	entry.Actor = c.GetString(ContextPrincipal)
+	if entry.Actor == "" {
+		entry.Actor = actorAnonymous
+	}
+	entry.Role = c.GetString(ContextRole)
+	entry.Tenant = tenant
+	entry.RequestID = logger.RequestID(c.Request.Context())
+	if err := log.Record(entry); err != nil {
+		logger.WithContext(c.Request.Context(), "audit").Errorf("cannot record %s of %s %s: %v", entry.Action, entry.Kind, entry.Name, err)
+	}
+}
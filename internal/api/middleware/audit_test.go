@@ -0,0 +1,153 @@
+package middleware
+
+import (
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/bassista/go_spin/internal/audit"
+	"github.com/gin-gonic/gin"
+)
+
+func newTestAuditLog(t *testing.T) *audit.Log {
+	t.Helper()
+	l, err := audit.Open(filepath.Join(t.TempDir(), "audit.jsonl"), 0)
+	if err != nil {
+		t.Fatalf("audit.Open: %v", err)
+	}
+	t.Cleanup(func() { _ = l.Close() })
+	return l
+}
+
+func TestAuditChanges(t *testing.T) {
+	log := newTestAuditLog(t)
+	names := map[string]string{"web": `{"name":"web"}`}
+	snapshot := func() (audit.Snapshots, error) {
+		s := audit.Snapshot{}
+		for name, data := range names {
+			s[name] = []byte(data)
+		}
+		return audit.Snapshots{audit.KindContainer: s}, nil
+	}
+
+	r := gin.New()
+	r.Use(RequestID())
+	r.Use(func(c *gin.Context) {
+		c.Set(ContextPrincipal, "alice")
+		c.Set(ContextRole, RoleOperator)
+	})
+	audited := AuditChanges(log, "team-a", snapshot)
+	r.POST("/container", audited, func(c *gin.Context) {
+		names["web"] = `{"name":"web","url":"http://web"}`
+		names["db"] = `{"name":"db"}`
+		c.Status(http.StatusOK)
+	})
+	r.DELETE("/container/:name", audited, func(c *gin.Context) {
+		delete(names, c.Param("name"))
+		c.Status(http.StatusNotFound)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/container", nil))
+	entries := log.Query(audit.Filter{})
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %+v", entries)
+	}
+	for _, e := range entries {
+		if e.Actor != "alice" || e.Role != RoleOperator || e.Tenant != "team-a" || e.RequestID != w.Header().Get(HeaderRequestID) {
+			t.Errorf("unexpected entry context %+v", e)
+		}
+	}
+	if entries[0].Name != "web" || entries[0].Action != audit.ActionUpdate || entries[1].Name != "db" || entries[1].Action != audit.ActionCreate {
+		t.Errorf("unexpected entries %+v", entries)
+	}
+
+	// Failed requests are not recorded
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodDelete, "/container/db", nil))
+	if got := log.Query(audit.Filter{}); len(got) != 2 {
+		t.Errorf("expected no entry for a failed request, got %+v", got)
+	}
+}
+
+func TestAuditAction(t *testing.T) {
+	log := newTestAuditLog(t)
+	r := gin.New()
+	r.POST("/runtime/:name/start", AuditAction(log, "", audit.KindContainer, audit.ActionStart), func(c *gin.Context) {
+		c.Status(http.StatusAccepted)
+	})
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/runtime/web/start", nil))
+	entries := log.Query(audit.Filter{})
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %+v", entries)
+	}
+	e := entries[0]
+	if e.Action != audit.ActionStart || e.Kind != audit.KindContainer || e.Name != "web" || e.Actor != actorAnonymous || e.Role != "" {
+		t.Errorf("unexpected entry %+v", e)
+	}
+}
+
+func TestAuditRename(t *testing.T) {
+	log := newTestAuditLog(t)
+	containers := audit.Snapshot{"web": []byte(`{"name":"web"}`)}
+	groups := audit.Snapshot{"media": []byte(`{"name":"media","container":["web"]}`)}
+	snapshot := func() (audit.Snapshots, error) {
+		return audit.Snapshots{audit.KindContainer: maps.Clone(containers), audit.KindGroup: maps.Clone(groups)}, nil
+	}
+
+	r := gin.New()
+	r.POST("/container/:name/rename", AuditRename(log, "", audit.KindContainer, snapshot), func(c *gin.Context) {
+		delete(containers, "web")
+		containers["site"] = []byte(`{"name":"site"}`)
+		groups["media"] = []byte(`{"name":"media","container":["site"]}`)
+		c.Status(http.StatusOK)
+	})
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/container/web/rename", nil))
+
+	entries := log.Query(audit.Filter{Name: "web"})
+	if len(entries) != 1 {
+		t.Fatalf("expected the rename entry only, got %+v", entries)
+	}
+	e := entries[0]
+	if e.Action != audit.ActionRename || e.Name != "site" || e.PreviousName != "web" || !slices.Equal(e.Changes, []string{"name"}) {
+		t.Errorf("unexpected rename entry %+v", e)
+	}
+	if got := log.Query(audit.Filter{Kind: audit.KindGroup}); len(got) != 1 || got[0].Action != audit.ActionUpdate {
+		t.Errorf("expected the group update, got %+v", got)
+	}
+}
+
+func TestAuditReported(t *testing.T) {
+	log := newTestAuditLog(t)
+	r := gin.New()
+	r.GET("/go/:name", AuditReported(log, ""), func(c *gin.Context) {
+		if c.Query("running") == "" {
+			ReportAudit(c, audit.Entry{Action: audit.ActionStart, Kind: audit.KindContainer, Name: c.Param("name")})
+		}
+		c.Status(http.StatusGatewayTimeout)
+	})
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/go/web?running=1", nil))
+	if got := log.Query(audit.Filter{}); len(got) != 0 {
+		t.Fatalf("expected no entry without a reported action, got %+v", got)
+	}
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/go/web", nil))
+	entries := log.Query(audit.Filter{})
+	if len(entries) != 1 || entries[0].Action != audit.ActionStart || entries[0].Name != "web" {
+		t.Errorf("expected the reported start even on a failed response, got %+v", entries)
+	}
+}
+
+func TestAudit_NilLogPassesThrough(t *testing.T) {
+	r := gin.New()
+	r.POST("/x/:name", AuditAction(nil, "", audit.KindGroup, audit.ActionStop), AuditChanges(nil, "", nil), AuditReported(nil, ""),
+		func(c *gin.Context) { c.Status(http.StatusOK) })
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/x/media", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got %d", w.Code)
+	}
+}
@@ -0,0 +1,196 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/bassista/go_spin/internal/api/apierror"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// HeaderAPIKey carries an API key; "Authorization: Bearer <key>" is accepted as well.
+const HeaderAPIKey = "X-API-Key"
+
+// ContextPrincipal is the gin context key holding who authenticated the request: the basic-auth
+// user name, or "api-key" for API key requests.
+const ContextPrincipal = "auth.principal"
+
+// ContextRole is the gin context key holding the role of the authenticated request.
+const ContextRole = "auth.role"
+
+// Roles of the management API, each including the rights of the previous one: viewers read,
+// operators also change containers, groups and schedules and start or stop them, admins also
+// run maintenance, imports and restores and manage safe mode and the waiting template.
+const (
+	RoleViewer   = "viewer"
+	RoleOperator = "operator"
+	RoleAdmin    = "admin"
+)
+
+// roleRanks orders the roles; unknown roles rank 0 and are denied everything.
+var roleRanks = map[string]int{RoleViewer: 1, RoleOperator: 2, RoleAdmin: 3}
+
+// principalAPIKey identifies requests authenticated with an API key.
+const principalAPIKey = "api-key"
+
+// authRealm is sent in the basic-auth challenge.
+const authRealm = `Basic realm="go_spin"`
+
+// bcryptPrefix marks a password stored as a bcrypt hash instead of plain text.
+const bcryptPrefix = "$2"
+
+// User is a basic-auth user of APIAuth.
+type User struct {
+	Password string // plain text or bcrypt hash
+	Role     string
+}
+
+// APIAuth returns a middleware accepting requests that carry one of apiKeys (key -> role, sent in
+// the X-API-Key header or as bearer token) or the basic-auth credentials of one of users. The
+// principal and its role are stored in the context for RequireRole. Other requests are rejected
+// with 401. With neither keys nor users every request passes, with no role.
+func APIAuth(apiKeys map[string]string, users map[string]User) gin.HandlerFunc {
+	if len(apiKeys) == 0 && len(users) == 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	return func(c *gin.Context) {
+		if key := requestAPIKey(c.Request); key != "" {
+			if role, ok := matchAPIKey(apiKeys, key); ok {
+				c.Set(ContextPrincipal, principalAPIKey)
+				c.Set(ContextRole, role)
+				c.Next()
+				return
+			}
+		}
+		if name, password, ok := c.Request.BasicAuth(); ok && matchPassword(users, name, password) {
+			c.Set(ContextPrincipal, name)
+			c.Set(ContextRole, users[name].Role)
+			c.Next()
+			return
+		}
+
+		if len(users) > 0 {
+			c.Header("WWW-Authenticate", authRealm)
+		}
+		apierror.Abort(c, http.StatusUnauthorized, apierror.CodeUnauthorized, "authentication required")
+	}
+}
+
+// RequireRole returns a middleware rejecting with 403 the requests whose role (set by APIAuth)
+// ranks below role. Requests without role, when authentication is disabled, pass.
+func RequireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !hasRole(c, role) {
+			apierror.Abort(c, http.StatusForbidden, apierror.CodeForbidden, fmt.Sprintf("%s role required", role))
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireAuthenticatedRole is RequireRole for routes that must stay closed when authentication is
+// disabled: requests without role are rejected with 403 too.
+func RequireAuthenticatedRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !HasAuthenticatedRole(c, role) {
+			apierror.Abort(c, http.StatusForbidden, apierror.CodeForbidden, fmt.Sprintf("authenticated %s role required", role))
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireRoleByMethod returns a middleware requiring RoleViewer for reads (GET, HEAD, OPTIONS)
+// and RoleOperator for every other method.
+func RequireRoleByMethod() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := RoleOperator
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			role = RoleViewer
+		}
+		if !hasRole(c, role) {
+			apierror.Abort(c, http.StatusForbidden, apierror.CodeForbidden, fmt.Sprintf("%s role required", role))
+			return
+		}
+		c.Next()
+	}
+}
+
+// hasRole reports whether the role of the request ranks at least role.
+func hasRole(c *gin.Context, role string) bool {
+	got, ok := c.Get(ContextRole)
+	if !ok {
+		return true
+	}
+	name, _ := got.(string)
+	return roleRanks[name] >= roleRanks[role]
+}
+
+// HasAuthenticatedRole reports whether the request was authenticated by APIAuth with a role
+// ranking at least role. Unlike RequireRole, requests without role, when authentication is
+// disabled, do not have it.
+func HasAuthenticatedRole(c *gin.Context, role string) bool {
+	if _, ok := c.Get(ContextRole); !ok {
+		return false
+	}
+	return hasRole(c, role)
+}
+
+// requestAPIKey returns the API key of the request, empty when it has none.
+func requestAPIKey(r *http.Request) string {
+	if key := r.Header.Get(HeaderAPIKey); key != "" {
+		return key
+	}
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return strings.TrimSpace(token)
+	}
+	return ""
+}
+
+// matchAPIKey compares key with every configured key in constant time, returning the role of the match.
+func matchAPIKey(apiKeys map[string]string, key string) (string, bool) {
+	role, matched := "", false
+	for k, r := range apiKeys {
+		if subtle.ConstantTimeCompare([]byte(k), []byte(key)) == 1 {
+			role, matched = r, true
+		}
+	}
+	return role, matched
+}
+
+// matchPassword checks the password of user name against its plain text or bcrypt password.
+func matchPassword(users map[string]User, name, password string) bool {
+	user, ok := users[name]
+	if !ok {
+		return false
+	}
+	if strings.HasPrefix(user.Password, bcryptPrefix) {
+		return bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) == nil
+	}
+	return subtle.ConstantTimeCompare([]byte(user.Password), []byte(password)) == 1
+}
+
+// ForwardAuth returns a middleware for a server published behind an authenticating reverse proxy
+// (Authelia, authentik, oauth2-proxy): requests without the user header set by the proxy are
+// rejected with 401, the others carry the user as ContextPrincipal. With an empty userHeader every
+// request passes. The header is trusted as is, so the server must only be reachable through the proxy.
+func ForwardAuth(userHeader string) gin.HandlerFunc {
+	if userHeader == "" {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	return func(c *gin.Context) {
+		user := strings.TrimSpace(c.GetHeader(userHeader))
+		if user == "" {
+			apierror.Abort(c, http.StatusUnauthorized, apierror.CodeUnauthorized, "authentication required")
+			return
+		}
+		c.Set(ContextPrincipal, user)
+		c.Next()
+	}
+}
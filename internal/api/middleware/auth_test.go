@@ -0,0 +1,179 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func newAuthRouter(apiKeys map[string]string, users map[string]User) *gin.Engine {
+	r := gin.New()
+	r.Use(APIAuth(apiKeys, users))
+	r.GET("/test", func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString(ContextPrincipal))
+	})
+	return r
+}
+
+func TestAPIAuth_OpenWithoutCredentials(t *testing.T) {
+	r := newAuthRouter(nil, nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200 with auth disabled, got %d", w.Code)
+	}
+}
+
+func TestAPIAuth(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("s3cret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("failed to hash password: %v", err)
+	}
+	r := newAuthRouter(map[string]string{"key-1": RoleAdmin}, map[string]User{
+		"admin": {Password: "plain", Role: RoleAdmin},
+		"ops":   {Password: string(hash), Role: RoleOperator},
+	})
+
+	tests := []struct {
+		name          string
+		setup         func(req *http.Request)
+		wantStatus    int
+		wantPrincipal string
+	}{
+		{"no credentials", func(req *http.Request) {}, http.StatusUnauthorized, ""},
+		{"api key header", func(req *http.Request) { req.Header.Set(HeaderAPIKey, "key-1") }, http.StatusOK, "api-key"},
+		{"bearer token", func(req *http.Request) { req.Header.Set("Authorization", "Bearer key-1") }, http.StatusOK, "api-key"},
+		{"wrong api key", func(req *http.Request) { req.Header.Set(HeaderAPIKey, "key-2") }, http.StatusUnauthorized, ""},
+		{"plain password", func(req *http.Request) { req.SetBasicAuth("admin", "plain") }, http.StatusOK, "admin"},
+		{"bcrypt password", func(req *http.Request) { req.SetBasicAuth("ops", "s3cret") }, http.StatusOK, "ops"},
+		{"wrong password", func(req *http.Request) { req.SetBasicAuth("admin", "nope") }, http.StatusUnauthorized, ""},
+		{"unknown user", func(req *http.Request) { req.SetBasicAuth("guest", "plain") }, http.StatusUnauthorized, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			tt.setup(req)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			if tt.wantStatus == http.StatusOK && w.Body.String() != tt.wantPrincipal {
+				t.Errorf("expected principal %q, got %q", tt.wantPrincipal, w.Body.String())
+			}
+			if tt.wantStatus == http.StatusUnauthorized && w.Header().Get("WWW-Authenticate") == "" {
+				t.Error("expected a basic-auth challenge")
+			}
+		})
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	r := gin.New()
+	r.Use(APIAuth(map[string]string{"view": RoleViewer, "op": RoleOperator, "adm": RoleAdmin}, nil))
+	r.Use(RequireRoleByMethod())
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.GET("/containers", ok)
+	r.POST("/container", ok)
+	r.GET("/start", RequireRole(RoleOperator), ok)
+	r.GET("/configuration", RequireRole(RoleAdmin), ok)
+
+	tests := []struct {
+		key, method, path string
+		wantStatus        int
+	}{
+		{"view", http.MethodGet, "/containers", http.StatusOK},
+		{"view", http.MethodPost, "/container", http.StatusForbidden},
+		{"view", http.MethodGet, "/start", http.StatusForbidden},
+		{"op", http.MethodPost, "/container", http.StatusOK},
+		{"op", http.MethodGet, "/start", http.StatusOK},
+		{"op", http.MethodGet, "/configuration", http.StatusForbidden},
+		{"adm", http.MethodGet, "/configuration", http.StatusOK},
+		{"adm", http.MethodPost, "/container", http.StatusOK},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		req.Header.Set(HeaderAPIKey, tt.key)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tt.wantStatus {
+			t.Errorf("%s %s %s: expected status %d, got %d", tt.key, tt.method, tt.path, tt.wantStatus, w.Code)
+		}
+	}
+}
+
+func TestRequireRole_AuthDisabled(t *testing.T) {
+	r := gin.New()
+	r.Use(APIAuth(nil, nil), RequireRoleByMethod())
+	r.POST("/configuration", RequireRole(RoleAdmin), func(c *gin.Context) { c.Status(http.StatusOK) })
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/configuration", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200 with auth disabled, got %d", w.Code)
+	}
+}
+
+func TestRequireAuthenticatedRole(t *testing.T) {
+	tests := []struct {
+		name       string
+		apiKeys    map[string]string
+		key        string
+		wantStatus int
+	}{
+		{"admin", map[string]string{"adm": RoleAdmin}, "adm", http.StatusOK},
+		{"operator", map[string]string{"op": RoleOperator}, "op", http.StatusForbidden},
+		{"auth disabled", nil, "", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(APIAuth(tt.apiKeys, nil))
+			r.GET("/test", RequireAuthenticatedRole(RoleAdmin), func(c *gin.Context) { c.Status(http.StatusOK) })
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			if tt.key != "" {
+				req.Header.Set(HeaderAPIKey, tt.key)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestForwardAuth(t *testing.T) {
+	newRouter := func(header string) *gin.Engine {
+		r := gin.New()
+		r.Use(ForwardAuth(header))
+		r.GET("/test", func(c *gin.Context) {
+			c.String(http.StatusOK, c.GetString(ContextPrincipal))
+		})
+		return r
+	}
+
+	w := httptest.NewRecorder()
+	newRouter("").ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200 with forward auth disabled, got %d", w.Code)
+	}
+
+	r := newRouter("Remote-User")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status 401 without user header, got %d", w.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Remote-User", "alice")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || w.Body.String() != "alice" {
+		t.Errorf("expected 200 with principal alice, got %d %q", w.Code, w.Body.String())
+	}
+}
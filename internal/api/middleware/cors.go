@@ -2,14 +2,50 @@ package middleware
 
 import (
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// DefaultCORSMaxAge is the preflight cache duration used when no max age is configured.
+const DefaultCORSMaxAge = 24 * time.Hour
+
+type corsOptions struct {
+	maxAge           time.Duration
+	allowCredentials bool
+}
+
+// CORSOption customizes the CORS middleware.
+type CORSOption func(*corsOptions)
+
+// WithCORSMaxAge sets the Access-Control-Max-Age sent with CORS responses; negative values keep the default.
+func WithCORSMaxAge(maxAge time.Duration) CORSOption {
+	return func(o *corsOptions) {
+		if maxAge >= 0 {
+			o.maxAge = maxAge
+		}
+	}
+}
+
+// WithCORSCredentials enables Access-Control-Allow-Credentials for allowed specific origins.
+// Credentials are never sent together with a wildcard origin.
+func WithCORSCredentials(allow bool) CORSOption {
+	return func(o *corsOptions) {
+		o.allowCredentials = allow
+	}
+}
+
 // CORSMiddleware returns a Gin middleware that handles CORS preflight and headers.
 // allowedOrigins is a comma-separated list of allowed origins, or "*" for all.
-func CORSMiddleware(allowedOrigins string) gin.HandlerFunc {
+func CORSMiddleware(allowedOrigins string, opts ...CORSOption) gin.HandlerFunc {
+	options := corsOptions{maxAge: DefaultCORSMaxAge}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	maxAge := strconv.Itoa(int(options.maxAge / time.Second))
+
 	// Pre-parse allowed origins for efficiency
 	var allowAll bool
 	var originSet map[string]struct{}
@@ -48,18 +84,20 @@ func CORSMiddleware(allowedOrigins string) gin.HandlerFunc {
 
 		c.Header("Access-Control-Allow-Origin", allowOrigin)
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
-		c.Header("Access-Control-Max-Age", "86400")
+		c.Header("Access-Control-Max-Age", maxAge)
 
 		// For preflight, echo requested headers if present; otherwise use defaults
 		reqHeaders := c.Request.Header.Get("Access-Control-Request-Headers")
 		if strings.TrimSpace(reqHeaders) != "" {
 			c.Header("Access-Control-Allow-Headers", reqHeaders)
 		} else {
-			c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization")
+			c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization, "+HeaderAPIKey+", "+HeaderRequestID)
 		}
+		// Let browser clients read the request ID of the responses
+		c.Header("Access-Control-Expose-Headers", HeaderRequestID)
 
-		// Only set credentials if not using wildcard origin
-		if allowOrigin != "*" {
+		// Credentials require a reflected specific origin, never the wildcard
+		if options.allowCredentials && allowOrigin != "*" {
 			c.Header("Access-Control-Allow-Credentials", "true")
 		}
 
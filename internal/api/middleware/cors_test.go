@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -49,7 +50,7 @@ func TestCORSMiddleware_AllowAll(t *testing.T) {
 
 func TestCORSMiddleware_SpecificOrigin_Allowed(t *testing.T) {
 	r := gin.New()
-	r.Use(CORSMiddleware("http://allowed.com,http://also-allowed.com"))
+	r.Use(CORSMiddleware("http://allowed.com,http://also-allowed.com", WithCORSCredentials(true)))
 	r.GET("/test", func(c *gin.Context) {
 		c.String(http.StatusOK, "ok")
 	})
@@ -78,7 +79,7 @@ func TestCORSMiddleware_SpecificOrigin_Allowed(t *testing.T) {
 	// 	t.Errorf("expected Vary: Origin, got '%s'", vary)
 	// }
 
-	// Should have credentials for specific origin
+	// Should have credentials for specific origin when enabled
 	creds := w.Header().Get("Access-Control-Allow-Credentials")
 	if creds != "true" {
 		t.Errorf("expected Allow-Credentials: true, got '%s'", creds)
@@ -222,3 +223,78 @@ func TestCORSMiddleware_WhitespaceInOrigins(t *testing.T) {
 		t.Errorf("expected origin to be allowed after trimming whitespace, got '%s'", origin)
 	}
 }
+
+func TestCORSMiddleware_DefaultMaxAge(t *testing.T) {
+	r := gin.New()
+	r.Use(CORSMiddleware("*"))
+	r.GET("/test", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	req := httptest.NewRequest(http.MethodOptions, "/test", nil)
+	req.Header.Set("Origin", "http://example.com")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if got := w.Header().Get("Access-Control-Max-Age"); got != "86400" {
+		t.Errorf("expected default max age 86400, got '%s'", got)
+	}
+}
+
+func TestCORSMiddleware_CustomMaxAge(t *testing.T) {
+	r := gin.New()
+	r.Use(CORSMiddleware("*", WithCORSMaxAge(10*time.Minute)))
+	r.GET("/test", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	req := httptest.NewRequest(http.MethodOptions, "/test", nil)
+	req.Header.Set("Origin", "http://example.com")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if got := w.Header().Get("Access-Control-Max-Age"); got != "600" {
+		t.Errorf("expected max age 600, got '%s'", got)
+	}
+}
+
+func TestCORSMiddleware_CredentialsDisabledByDefault(t *testing.T) {
+	r := gin.New()
+	r.Use(CORSMiddleware("http://allowed.com"))
+	r.GET("/test", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Origin", "http://allowed.com")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "http://allowed.com" {
+		t.Errorf("expected reflected origin, got '%s'", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+		t.Errorf("expected no Allow-Credentials header, got '%s'", got)
+	}
+}
+
+func TestCORSMiddleware_CredentialsNeverWithWildcard(t *testing.T) {
+	r := gin.New()
+	r.Use(CORSMiddleware("*", WithCORSCredentials(true)))
+	r.GET("/test", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("Origin", "http://example.com")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+		t.Errorf("expected no Allow-Credentials with wildcard origin, got '%s'", got)
+	}
+}
@@ -0,0 +1,181 @@
+package middleware
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bassista/go_spin/internal/api/apierror"
+	"github.com/bassista/go_spin/internal/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// captchaReturnParam is the query parameter carrying the original URL to the CAPTCHA page.
+const captchaReturnParam = "return"
+
+// RateLimiter is a per-client token bucket: a client may send burst requests at once, then one
+// more every minute/perMinute.
+type RateLimiter struct {
+	mu       sync.Mutex
+	burst    float64
+	interval time.Duration // time to earn one request back
+	clients  map[string]*bucket
+	now      func() time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter creates a limiter allowing perMinute requests per minute to each client, with
+// bursts of burst requests (perMinute when burst <= 0). perMinute <= 0 disables the limit: it
+// returns nil, which RateLimit accepts.
+func NewRateLimiter(perMinute, burst int) *RateLimiter {
+	if perMinute <= 0 {
+		return nil
+	}
+	if burst <= 0 {
+		burst = perMinute
+	}
+	return &RateLimiter{
+		burst:    float64(burst),
+		interval: time.Minute / time.Duration(perMinute),
+		clients:  map[string]*bucket{},
+		now:      time.Now,
+	}
+}
+
+// Allow takes a request from the bucket of client. When it is empty it returns false and how long
+// the client has to wait for the next request.
+func (l *RateLimiter) Allow(client string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	b, ok := l.clients[client]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.clients[client] = b
+	}
+	b.tokens = math.Min(l.burst, b.tokens+float64(now.Sub(b.last))/float64(l.interval))
+	b.last = now
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) * float64(l.interval))
+	}
+	b.tokens--
+	return true, 0
+}
+
+// Prune removes the clients whose bucket is full again at now: they would start afresh anyway.
+func (l *RateLimiter) Prune(now time.Time) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	removed := 0
+	for client, b := range l.clients {
+		if b.tokens+float64(now.Sub(b.last))/float64(l.interval) >= l.burst {
+			delete(l.clients, client)
+			removed++
+		}
+	}
+	return removed
+}
+
+// Len returns the number of clients tracked.
+func (l *RateLimiter) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.clients)
+}
+
+// RateLimit rejects with 429 and Retry-After the requests of the clients (by gin's ClientIP) over
+// limiter. With captchaURL set, browser page loads over the limit are redirected (302) there
+// instead, with the original URL in the "return" query parameter, so a challenge page can let
+// humans through. X-Forwarded-Proto and X-Forwarded-Host only shape that URL when the peer is one of
+// trustedProxies (IPs or CIDRs, as misc.waiting_trusted_proxies). A nil limiter disables the
+// middleware.
+func RateLimit(limiter *RateLimiter, captchaURL string, trustedProxies []string) gin.HandlerFunc {
+	if limiter == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+	trusted := parseNetworks(trustedProxies)
+
+	return func(c *gin.Context) {
+		ok, retryAfter := limiter.Allow(c.ClientIP())
+		if ok {
+			c.Next()
+			return
+		}
+		logger.WithContext(c.Request.Context(), "rate-limit").Debugf("client %s over the rate limit on %s", c.ClientIP(), c.Request.URL.Path)
+		if captchaURL != "" && c.Request.Method == http.MethodGet && strings.Contains(c.GetHeader("Accept"), "text/html") {
+			c.Redirect(http.StatusFound, withReturnURL(captchaURL, requestURL(c.Request, inNetworks(c.RemoteIP(), trusted))))
+			c.Abort()
+			return
+		}
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		apierror.Abort(c, http.StatusTooManyRequests, apierror.CodeRateLimited, "too many requests, retry later")
+	}
+}
+
+// requestURL rebuilds the absolute URL of r as seen by the client. The X-Forwarded-Proto and
+// X-Forwarded-Host headers are honoured only when fromProxy, i.e. r comes from a trusted proxy.
+func requestURL(r *http.Request, fromProxy bool) string {
+	scheme, host := "http", r.Host
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if fromProxy {
+		if proto := r.Header.Get("X-Forwarded-Proto"); proto == "http" || proto == "https" {
+			scheme = proto
+		}
+		if forwarded := r.Header.Get("X-Forwarded-Host"); forwarded != "" {
+			host = forwarded
+		}
+	}
+	return scheme + "://" + host + r.URL.RequestURI()
+}
+
+// parseNetworks parses IPs and CIDRs, skipping the invalid ones (rejected by the configuration).
+func parseNetworks(entries []string) []*net.IPNet {
+	var networks []*net.IPNet
+	for _, entry := range entries {
+		if _, network, err := net.ParseCIDR(entry); err == nil {
+			networks = append(networks, network)
+		} else if ip := net.ParseIP(entry); ip != nil {
+			// A single address: a full mask of its 16-byte form, which also matches IPv4 peers
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(8*net.IPv6len, 8*net.IPv6len)})
+		}
+	}
+	return networks
+}
+
+// inNetworks reports whether ip belongs to one of networks.
+func inNetworks(ip string, networks []*net.IPNet) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, network := range networks {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// withReturnURL adds the return query parameter to target.
+func withReturnURL(target, returnURL string) string {
+	u, err := url.Parse(target)
+	if err != nil {
+		return target
+	}
+	q := u.Query()
+	q.Set(captchaReturnParam, returnURL)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
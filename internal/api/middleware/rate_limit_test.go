@@ -0,0 +1,143 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRateLimiter_Allow(t *testing.T) {
+	now := time.Date(2024, 3, 18, 8, 0, 0, 0, time.UTC)
+	l := NewRateLimiter(60, 2)
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if ok, _ := l.Allow("10.0.0.1"); !ok {
+			t.Fatalf("request %d: expected the burst allowed", i)
+		}
+	}
+	ok, retryAfter := l.Allow("10.0.0.1")
+	if ok || retryAfter != time.Second {
+		t.Fatalf("expected the third request rejected for 1s, got %v %v", ok, retryAfter)
+	}
+	if ok, _ := l.Allow("10.0.0.2"); !ok {
+		t.Error("expected another client allowed")
+	}
+
+	now = now.Add(time.Second)
+	if ok, _ := l.Allow("10.0.0.1"); !ok {
+		t.Error("expected a request allowed again after the refill interval")
+	}
+}
+
+func TestRateLimiter_Prune(t *testing.T) {
+	now := time.Date(2024, 3, 18, 8, 0, 0, 0, time.UTC)
+	l := NewRateLimiter(60, 2)
+	l.now = func() time.Time { return now }
+	l.Allow("10.0.0.1")
+	l.Allow("10.0.0.1")
+	l.Allow("10.0.0.2")
+
+	if removed := l.Prune(now.Add(time.Second)); removed != 1 || l.Len() != 1 {
+		t.Errorf("expected only the refilled client pruned, removed %d, left %d", removed, l.Len())
+	}
+	if removed := l.Prune(now.Add(2 * time.Second)); removed != 1 || l.Len() != 0 {
+		t.Errorf("expected the last client pruned, removed %d, left %d", removed, l.Len())
+	}
+}
+
+func TestNewRateLimiter_Disabled(t *testing.T) {
+	if l := NewRateLimiter(0, 5); l != nil {
+		t.Errorf("expected no limiter, got %+v", l)
+	}
+	r := gin.New()
+	r.GET("/:name", RateLimit(nil, "", nil), func(c *gin.Context) { c.Status(http.StatusOK) })
+	for i := 0; i < 3; i++ {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/app", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", w.Code)
+		}
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	r := gin.New()
+	r.GET("/:name", RateLimit(NewRateLimiter(1, 1), "", nil), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/app", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected the first request allowed, got %d", w.Code)
+	}
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/app", nil))
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "60" {
+		t.Errorf("expected 429 with Retry-After 60, got %d %q", w.Code, w.Header().Get("Retry-After"))
+	}
+}
+
+func TestRateLimit_CaptchaRedirect(t *testing.T) {
+	r := gin.New()
+	r.GET("/:name", RateLimit(NewRateLimiter(1, 1), "https://auth.lan/challenge?site=go", nil), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	page := func(accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "http://spin.lan/app?x=1", nil)
+		req.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	page("text/html")
+
+	w := page("text/html,application/xhtml+xml")
+	if w.Code != http.StatusFound {
+		t.Fatalf("expected a redirect for a browser, got %d", w.Code)
+	}
+	location, err := url.Parse(w.Header().Get("Location"))
+	if err != nil || location.Host != "auth.lan" || location.Query().Get("site") != "go" || location.Query().Get("return") != "http://spin.lan/app?x=1" {
+		t.Errorf("unexpected redirect %q", w.Header().Get("Location"))
+	}
+
+	if w := page("application/json"); w.Code != http.StatusTooManyRequests {
+		t.Errorf("expected 429 for a non-browser client, got %d", w.Code)
+	}
+}
+
+func TestRateLimit_CaptchaReturnURLForwardedHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		trusted []string
+		want    string
+	}{
+		{"untrusted peer", nil, "http://spin.lan/app"},
+		{"trusted proxy", []string{"10.0.0.0/8"}, "https://spin.example.com/app"},
+		{"trusted proxy IP", []string{"10.0.0.1"}, "https://spin.example.com/app"},
+		{"other proxy", []string{"192.168.1.1"}, "http://spin.lan/app"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.GET("/:name", RateLimit(NewRateLimiter(1, 1), "https://auth.lan/challenge", tt.trusted), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			var w *httptest.ResponseRecorder
+			for i := 0; i < 2; i++ {
+				req := httptest.NewRequest(http.MethodGet, "http://spin.lan/app", nil)
+				req.RemoteAddr = "10.0.0.1:4321"
+				req.Header.Set("Accept", "text/html")
+				req.Header.Set("X-Forwarded-Proto", "https")
+				req.Header.Set("X-Forwarded-Host", "spin.example.com")
+				w = httptest.NewRecorder()
+				r.ServeHTTP(w, req)
+			}
+			location, err := url.Parse(w.Header().Get("Location"))
+			if w.Code != http.StatusFound || err != nil || location.Query().Get("return") != tt.want {
+				t.Errorf("expected a redirect returning to %q, got %d %q", tt.want, w.Code, w.Header().Get("Location"))
+			}
+		})
+	}
+}
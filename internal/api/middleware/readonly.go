@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/bassista/go_spin/internal/api/apierror"
+	"github.com/gin-gonic/gin"
+)
+
+// ReadOnly rejects the request with 405 when readOnly is set: it guards the routes changing the
+// data document while the document is synced from a remote source (sync.url, sync.git_repo).
+func ReadOnly(readOnly bool) gin.HandlerFunc {
+	if !readOnly {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	return func(c *gin.Context) {
+		apierror.Abort(c, http.StatusMethodNotAllowed, apierror.CodeReadOnly,
+			"the data document is read-only: it is synced from a remote source")
+	}
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestReadOnly(t *testing.T) {
+	tests := []struct {
+		name     string
+		readOnly bool
+		want     int
+	}{
+		{"writable", false, http.StatusOK},
+		{"read-only", true, http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		r := gin.New()
+		r.POST("/container", ReadOnly(tt.readOnly), func(c *gin.Context) {
+			c.String(http.StatusOK, "ok")
+		})
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/container", nil))
+		if w.Code != tt.want {
+			t.Errorf("%s: expected status %d, got %d", tt.name, tt.want, w.Code)
+		}
+	}
+}
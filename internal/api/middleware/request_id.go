@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/bassista/go_spin/internal/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// HeaderRequestID carries the request ID: taken from the request when the client or a proxy set
+// it, and returned in every response.
+const HeaderRequestID = "X-Request-ID"
+
+// ContextRequestID is the gin context key of the request ID.
+const ContextRequestID = "requestID"
+
+const (
+	// maxRequestIDLength caps the client request IDs kept; longer ones are replaced.
+	maxRequestIDLength = 128
+	// requestIDBytes is the number of random bytes of a generated request ID.
+	requestIDBytes = 8
+)
+
+// RequestID assigns an ID to every request, echoed in the X-Request-ID response header, stored in
+// the gin context and in the request context, where logger.WithContext adds it to the log lines
+// as request_id. A valid X-Request-ID of the request is kept so the logs of a proxy can be
+// correlated too.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(HeaderRequestID)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		c.Set(ContextRequestID, id)
+		c.Request = c.Request.WithContext(logger.WithRequestID(c.Request.Context(), id))
+		c.Header(HeaderRequestID, id)
+		c.Next()
+	}
+}
+
+// validRequestID accepts non-empty IDs of printable ASCII characters up to maxRequestIDLength,
+// so they cannot break the log lines or the response headers.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, requestIDBytes)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
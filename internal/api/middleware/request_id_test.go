@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bassista/go_spin/internal/logger"
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		wantSame bool
+	}{
+		{"generated", "", false},
+		{"kept from the client", "proxy-42", true},
+		{"control characters replaced", "bad\nid", false},
+		{"too long replaced", strings.Repeat("x", maxRequestIDLength+1), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var inContext, inGin string
+			r := gin.New()
+			r.Use(RequestID())
+			r.GET("/test", func(c *gin.Context) {
+				inContext = logger.RequestID(c.Request.Context())
+				inGin = c.GetString(ContextRequestID)
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			if tt.header != "" {
+				req.Header.Set(HeaderRequestID, tt.header)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			id := w.Header().Get(HeaderRequestID)
+			if id == "" || id != inContext || id != inGin {
+				t.Fatalf("expected the same ID in the response, request context and gin context, got %q, %q, %q", id, inContext, inGin)
+			}
+			if (id == tt.header) != tt.wantSame {
+				t.Errorf("header %q: unexpected request ID %q", tt.header, id)
+			}
+		})
+	}
+}
+
+func TestRequestID_UniquePerRequest(t *testing.T) {
+	r := gin.New()
+	r.Use(RequestID())
+	r.GET("/test", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	seen := map[string]bool{}
+	for i := 0; i < 10; i++ {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+		id := w.Header().Get(HeaderRequestID)
+		if seen[id] {
+			t.Fatalf("request ID %q assigned twice", id)
+		}
+		seen[id] = true
+	}
+}
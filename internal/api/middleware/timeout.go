@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/bassista/go_spin/internal/api/apierror"
 	"github.com/gin-gonic/gin"
 )
 
@@ -25,9 +26,7 @@ func RequestTimeout(d time.Duration) gin.HandlerFunc {
 		// If the context timed out and nothing was written, return 504.
 		// (If something was already written, we can't change the response safely.)
 		if ctx.Err() == context.DeadlineExceeded && !c.Writer.Written() {
-			c.AbortWithStatusJSON(http.StatusGatewayTimeout, gin.H{
-				"error": "request timeout",
-			})
+			apierror.Abort(c, http.StatusGatewayTimeout, apierror.CodeTimeout, "request timeout")
 			return
 		}
 	}
@@ -0,0 +1,235 @@
+// Package openapi builds the OpenAPI 3 description of the management API from the routes
+// registered on the gin engine and the request/response models of the controllers.
+package openapi
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Version is the OpenAPI version of the generated documents.
+const Version = "3.0.3"
+
+// Document is an OpenAPI document, limited to the parts go_spin uses.
+type Document struct {
+	OpenAPI    string                `json:"openapi"`
+	Info       Info                  `json:"info"`
+	Paths      map[string]*PathItem  `json:"paths"`
+	Components Components            `json:"components"`
+	Security   []map[string][]string `json:"security,omitempty"`
+}
+
+// Info describes the API.
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// PathItem holds the operations of a path, by lower-case HTTP method.
+type PathItem map[string]*Operation
+
+// Operation is one method of a path.
+type Operation struct {
+	Tags        []string             `json:"tags,omitempty"`
+	Summary     string               `json:"summary,omitempty"`
+	OperationID string               `json:"operationId"`
+	Parameters  []Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+// Parameter is a path or query parameter.
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody is the JSON body of an operation.
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response is one status of an operation.
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType wraps the schema of a body.
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components holds the named schemas referenced by the operations and the security schemes.
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme is an authentication method of the API.
+type SecurityScheme struct {
+	Type   string `json:"type"`
+	Scheme string `json:"scheme,omitempty"`
+	In     string `json:"in,omitempty"`
+	Name   string `json:"name,omitempty"`
+}
+
+// Endpoint describes the models of a route. Request and Response are zero values of the body
+// types, nil when the route has no JSON body.
+type Endpoint struct {
+	Summary  string
+	Request  any
+	Response any
+	// Status is the success status, http.StatusOK when zero.
+	Status int
+	// Query lists the query parameters the route accepts.
+	Query []string
+}
+
+// Builder collects the operations of a Document.
+type Builder struct {
+	doc     Document
+	schemas *schemaRegistry
+	// errorSchema documents the failures of every operation, when set.
+	errorSchema *Schema
+}
+
+// NewBuilder creates a Builder for an API with the given title and version.
+func NewBuilder(title, version string) *Builder {
+	schemas := newSchemaRegistry()
+	return &Builder{
+		doc: Document{
+			OpenAPI:    Version,
+			Info:       Info{Title: title, Version: version},
+			Paths:      map[string]*PathItem{},
+			Components: Components{Schemas: schemas.named},
+		},
+		schemas: schemas,
+	}
+}
+
+// SetErrorModel documents model as the body of every failed request.
+func (b *Builder) SetErrorModel(model any) {
+	b.errorSchema = b.schemas.of(model)
+}
+
+// AddSecurityScheme declares an authentication method required by every operation.
+func (b *Builder) AddSecurityScheme(name string, scheme SecurityScheme) {
+	if b.doc.Components.SecuritySchemes == nil {
+		b.doc.Components.SecuritySchemes = map[string]*SecurityScheme{}
+	}
+	b.doc.Components.SecuritySchemes[name] = &scheme
+	b.doc.Security = append(b.doc.Security, map[string][]string{name: {}})
+}
+
+// Add documents the gin route method path with the models of endpoint.
+func (b *Builder) Add(method, path string, endpoint Endpoint) {
+	openPath, params := convertPath(path)
+	op := &Operation{
+		Tags:        []string{tagOf(openPath)},
+		Summary:     endpoint.Summary,
+		OperationID: operationID(method, openPath),
+		Responses:   map[string]*Response{},
+	}
+	for _, name := range params {
+		op.Parameters = append(op.Parameters, Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}})
+	}
+	for _, name := range endpoint.Query {
+		op.Parameters = append(op.Parameters, Parameter{Name: name, In: "query", Schema: &Schema{Type: "string"}})
+	}
+	if endpoint.Request != nil {
+		op.RequestBody = &RequestBody{Required: true, Content: jsonContent(b.schemas.of(endpoint.Request))}
+	}
+
+	status := endpoint.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := &Response{Description: http.StatusText(status)}
+	if endpoint.Response != nil {
+		success.Content = jsonContent(b.schemas.of(endpoint.Response))
+	}
+	op.Responses[strconv.Itoa(status)] = success
+	if b.errorSchema != nil {
+		op.Responses["default"] = &Response{Description: "Error", Content: jsonContent(b.errorSchema)}
+	}
+
+	item, ok := b.doc.Paths[openPath]
+	if !ok {
+		item = &PathItem{}
+		b.doc.Paths[openPath] = item
+	}
+	(*item)[strings.ToLower(method)] = op
+}
+
+// Document returns the built document.
+func (b *Builder) Document() *Document {
+	return &b.doc
+}
+
+// AddRoutes documents the routes for which describe returns true, with the models it returns.
+func (b *Builder) AddRoutes(routes gin.RoutesInfo, describe func(gin.RouteInfo) (Endpoint, bool)) {
+	sorted := append(gin.RoutesInfo(nil), routes...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Path != sorted[j].Path {
+			return sorted[i].Path < sorted[j].Path
+		}
+		return sorted[i].Method < sorted[j].Method
+	})
+	for _, route := range sorted {
+		if endpoint, ok := describe(route); ok {
+			b.Add(route.Method, route.Path, endpoint)
+		}
+	}
+}
+
+// convertPath turns the gin path parameters (:name, *path) into OpenAPI ones ({name}) and
+// returns their names.
+func convertPath(path string) (string, []string) {
+	segments := strings.Split(path, "/")
+	var params []string
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			params = append(params, segment[1:])
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// tagOf groups an operation by the first static segment of its path, without the plural, so
+// /container/{name} and /containers share a tag.
+func tagOf(path string) string {
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "" || segment == "api" || strings.HasPrefix(segment, "{") {
+			continue
+		}
+		return strings.TrimSuffix(segment, "s")
+	}
+	return "root"
+}
+
+// operationID derives a unique ID such as get_container_name from method and path.
+func operationID(method, path string) string {
+	id := strings.ToLower(method)
+	for _, segment := range strings.Split(path, "/") {
+		segment = strings.Trim(segment, "{}")
+		if segment == "" {
+			continue
+		}
+		id += "_" + strings.NewReplacer("-", "_", ".", "_").Replace(segment)
+	}
+	return id
+}
+
+func jsonContent(schema *Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: schema}}
+}
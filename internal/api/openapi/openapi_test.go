@@ -0,0 +1,60 @@
+package openapi
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testBase struct {
+	ID string `json:"id"`
+}
+
+type testNode struct {
+	testBase
+	Name     string     `json:"name" validate:"required"`
+	Note     string     `json:"note" validate:"omitempty,max=10"`
+	Count    int        `json:"count,omitempty"`
+	At       *time.Time `json:"at"`
+	Children []testNode `json:"children"`
+	secret   string
+}
+
+func TestSchema_Struct(t *testing.T) {
+	r := newSchemaRegistry()
+	ref := r.of(testNode{})
+	if ref.Ref != componentsRef+"testNode" {
+		t.Fatalf("expected a reference to testNode, got %+v", ref)
+	}
+	s := r.named["testNode"]
+	for _, name := range []string{"id", "name", "note", "count", "at", "children"} {
+		if s.Properties[name] == nil {
+			t.Errorf("expected property %s", name)
+		}
+	}
+	if len(s.Properties) != 6 {
+		t.Errorf("expected unexported fields skipped, got %d properties", len(s.Properties))
+	}
+	if want := []string{"id", "name", "children"}; !reflect.DeepEqual(s.Required, want) {
+		t.Errorf("expected required %v, got %v", want, s.Required)
+	}
+	if at := s.Properties["at"]; at.Format != "date-time" || !at.Nullable {
+		t.Errorf("expected a nullable date-time, got %+v", at)
+	}
+	if items := s.Properties["children"].Items; items == nil || items.Ref != ref.Ref {
+		t.Errorf("expected the recursive field to reference testNode, got %+v", items)
+	}
+}
+
+func TestConvertPath(t *testing.T) {
+	path, params := convertPath("/container/:name/files/*path")
+	if path != "/container/{name}/files/{path}" || !reflect.DeepEqual(params, []string{"name", "path"}) {
+		t.Errorf("unexpected conversion: %s %v", path, params)
+	}
+	if got := tagOf("/api/overview"); got != "overview" {
+		t.Errorf("expected tag overview, got %s", got)
+	}
+	if got := operationID("GET", "/runtime/{name}/next-events"); got != "get_runtime_name_next_events" {
+		t.Errorf("unexpected operation ID %s", got)
+	}
+}
@@ -0,0 +1,178 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// componentsRef prefixes the references to named schemas.
+const componentsRef = "#/components/schemas/"
+
+// Schema is a JSON schema as used by OpenAPI 3.0.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	durationType      = reflect.TypeOf(time.Duration(0))
+	rawMessageType    = reflect.TypeOf(json.RawMessage{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// schemaRegistry derives schemas from Go types, registering every named struct once as a
+// component so recursive and shared models are referenced instead of inlined.
+type schemaRegistry struct {
+	named map[string]*Schema
+	names map[reflect.Type]string
+}
+
+func newSchemaRegistry() *schemaRegistry {
+	return &schemaRegistry{named: map[string]*Schema{}, names: map[reflect.Type]string{}}
+}
+
+// of returns the schema of the type of model.
+func (r *schemaRegistry) of(model any) *Schema {
+	return r.schema(reflect.TypeOf(model))
+}
+
+func (r *schemaRegistry) schema(t reflect.Type) *Schema {
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case durationType:
+		return &Schema{Type: "integer", Format: "int64"}
+	case rawMessageType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		s := r.schema(t.Elem())
+		if s.Ref != "" {
+			return s
+		}
+		s.Nullable = true
+		return s
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Uint, reflect.Uint8, reflect.Uint16:
+		return &Schema{Type: "integer"}
+	case reflect.Int32, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: r.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: r.schema(t.Elem())}
+	case reflect.Struct:
+		return r.structSchema(t)
+	}
+	// Interfaces and anything else accept any value
+	return &Schema{}
+}
+
+// structSchema returns a reference to the component of a named struct, registering it on first
+// use, or the inline schema of an anonymous one. Types with their own JSON encoding accept any
+// value.
+func (r *schemaRegistry) structSchema(t reflect.Type) *Schema {
+	if t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType) {
+		return &Schema{}
+	}
+	if t.Name() == "" {
+		return r.object(t)
+	}
+	if name, ok := r.names[t]; ok {
+		return &Schema{Ref: componentsRef + name}
+	}
+	name := r.componentName(t)
+	r.names[t] = name
+	// Registered before its fields so recursive types resolve to the reference
+	r.named[name] = &Schema{Type: "object"}
+	r.named[name] = r.object(t)
+	return &Schema{Ref: componentsRef + name}
+}
+
+// componentName is the type name, prefixed by its package when another package already uses it.
+func (r *schemaRegistry) componentName(t reflect.Type) string {
+	name := t.Name()
+	if _, taken := r.named[name]; !taken {
+		return name
+	}
+	pkg := t.PkgPath()
+	if i := strings.LastIndex(pkg, "/"); i >= 0 {
+		pkg = pkg[i+1:]
+	}
+	return pkg + "." + name
+}
+
+// object lists the JSON fields of t as properties, flattening embedded structs like
+// encoding/json. Fields with validation rules are required when validated as required, the
+// others when they are neither omitempty nor pointers.
+func (r *schemaRegistry) object(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	r.addFields(s, t)
+	return s
+}
+
+func (r *schemaRegistry) addFields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				r.addFields(s, embedded)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		s.Properties[name] = r.schema(field.Type)
+		if isRequired(field, opts) {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+func isRequired(field reflect.StructField, jsonOpts string) bool {
+	if rules, ok := field.Tag.Lookup("validate"); ok {
+		for _, rule := range strings.Split(rules, ",") {
+			if rule == "required" {
+				return true
+			}
+		}
+		return false
+	}
+	return !strings.Contains(jsonOpts, "omitempty") && field.Type.Kind() != reflect.Pointer
+}
@@ -0,0 +1,84 @@
+package route
+
+import (
+	"github.com/bassista/go_spin/internal/api/controller"
+	"github.com/bassista/go_spin/internal/api/middleware"
+	"github.com/bassista/go_spin/internal/app"
+	"github.com/bassista/go_spin/internal/audit"
+	"github.com/bassista/go_spin/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// NewAuditRouter sets up the audit log route, when the audit log is enabled. Tenants share the
+// audit log of the main document, so the route is not repeated under tenants/<tenant>.
+func NewAuditRouter(appCtx *app.App, group *gin.RouterGroup) {
+	if appCtx.Audit == nil {
+		return
+	}
+	ac := controller.NewAuditController(appCtx.Audit)
+	group.GET("admin/audit", middleware.RequestTimeout(appCtx.Config.Server.RequestTimeout), ac.List)
+}
+
+// defaultScheduleAuditName names the default schedule, the only entity of its kind, in the audit log.
+const defaultScheduleAuditName = "default"
+
+// documentKinds are the kinds of the entities of a data document, audited by the routes changing
+// any part of it (imports, restores, prunes).
+var documentKinds = []string{audit.KindContainer, audit.KindGroup, audit.KindSchedule, audit.KindDefaultSchedule}
+
+// auditChanges returns the middleware auditing the changes of the entities of kinds in the data
+// document of appCtx.
+func auditChanges(appCtx *app.App, kinds ...string) gin.HandlerFunc {
+	return middleware.AuditChanges(appCtx.Audit, appCtx.Tenant, auditSnapshot(appCtx, kinds))
+}
+
+// auditRename returns the middleware auditing the rename of the container named by the :name
+// parameter, along with the groups and schedules referencing it.
+func auditRename(appCtx *app.App) gin.HandlerFunc {
+	return middleware.AuditRename(appCtx.Audit, appCtx.Tenant, audit.KindContainer, auditSnapshot(appCtx, documentKinds))
+}
+
+// auditSnapshot returns the function snapshotting the entities of kinds in the data document of appCtx.
+func auditSnapshot(appCtx *app.App, kinds []string) func() (audit.Snapshots, error) {
+	return func() (audit.Snapshots, error) {
+		doc, err := appCtx.Cache.Snapshot()
+		if err != nil {
+			return nil, err
+		}
+		snapshots := make(audit.Snapshots, len(kinds))
+		for _, kind := range kinds {
+			if snapshots[kind], err = snapshotKind(doc, kind); err != nil {
+				return nil, err
+			}
+		}
+		return snapshots, nil
+	}
+}
+
+// snapshotKind snapshots the entities of kind in doc.
+func snapshotKind(doc repository.DataDocument, kind string) (audit.Snapshot, error) {
+	switch kind {
+	case audit.KindContainer:
+		return audit.NewSnapshot(doc.Containers, func(c repository.Container) string { return c.Name })
+	case audit.KindGroup:
+		return audit.NewSnapshot(doc.Groups, func(g repository.Group) string { return g.Name })
+	case audit.KindDefaultSchedule:
+		var schedules []repository.DefaultSchedule
+		if doc.DefaultSchedule != nil {
+			schedules = append(schedules, *doc.DefaultSchedule)
+		}
+		return audit.NewSnapshot(schedules, func(repository.DefaultSchedule) string { return defaultScheduleAuditName })
+	default:
+		return audit.NewSnapshot(doc.Schedules, func(s repository.Schedule) string { return s.ID })
+	}
+}
+
+// auditAction returns the middleware auditing action on the kind named by the :name parameter.
+func auditAction(appCtx *app.App, kind, action string) gin.HandlerFunc {
+	return middleware.AuditAction(appCtx.Audit, appCtx.Tenant, kind, action)
+}
+
+// auditReported returns the middleware auditing the actions reported by the handler.
+func auditReported(appCtx *app.App) gin.HandlerFunc {
+	return middleware.AuditReported(appCtx.Audit, appCtx.Tenant)
+}
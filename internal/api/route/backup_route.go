@@ -0,0 +1,23 @@
+package route
+
+import (
+	"github.com/bassista/go_spin/internal/api/controller"
+	"github.com/bassista/go_spin/internal/api/middleware"
+	"github.com/bassista/go_spin/internal/app"
+	"github.com/bassista/go_spin/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// NewBackupRouter sets up the data file backup routes, when the repository keeps backups.
+func NewBackupRouter(appCtx *app.App, group *gin.RouterGroup) {
+	backups, ok := appCtx.Repo.(repository.BackupStore)
+	if !ok || appCtx.Config.Data.BackupRetention <= 0 {
+		return
+	}
+	bc := controller.NewBackupController(backups, appCtx.Cache)
+	timeoutMiddleware := middleware.RequestTimeout(appCtx.Config.Server.RequestTimeout)
+	readOnly := middleware.ReadOnly(appCtx.ReadOnly())
+
+	group.GET("admin/backups", timeoutMiddleware, bc.List)
+	group.POST("admin/restore/:id", readOnly, timeoutMiddleware, auditChanges(appCtx, documentKinds...), bc.Restore)
+}
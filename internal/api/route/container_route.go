@@ -4,16 +4,37 @@ import (
 	"github.com/bassista/go_spin/internal/api/controller"
 	"github.com/bassista/go_spin/internal/api/middleware"
 	"github.com/bassista/go_spin/internal/app"
+	"github.com/bassista/go_spin/internal/audit"
 	"github.com/gin-gonic/gin"
 )
 
 func NewContainerRouter(appCtx *app.App, group *gin.RouterGroup) {
 	cc := controller.NewContainerController(appCtx.BaseCtx, appCtx.Cache, appCtx.Runtime)
+	cc.SetExecHealthChecks(appCtx.Config.Auth.Enabled())
+	cc.SetReadinessCache(appCtx.Config.Misc.ReadyCacheTTL, appCtx.Config.Misc.ReadyCacheNegativeTTL)
+	cc.SetExternalReadyChecker(appCtx.Config.Misc.ExternalReadyURL, appCtx.Config.Misc.ExternalReadyTimeout)
+	cc.SetDefaultURLScheme(appCtx.Config.Misc.DefaultURLScheme)
+	cc.SetOperations(appCtx.Ops)
+	cc.SetAnnotationLimits(appCtx.Config.Data.MaxNotesLength, appCtx.Config.Data.MaxMetaKeys)
+	if appCtx.ReadCache != nil {
+		cc.SetReadStore(appCtx.ReadCache)
+	}
+	if appCtx.Janitor != nil {
+		appCtx.Janitor.Register("readiness_cache", cc.ReadinessCache())
+	}
 
 	timeoutMiddleware := middleware.RequestTimeout(appCtx.Config.Server.RequestTimeout)
+	readOnly := middleware.ReadOnly(appCtx.ReadOnly())
+	audited := auditChanges(appCtx, audit.KindContainer)
 
 	group.GET("containers", timeoutMiddleware, cc.AllContainers)
-	group.POST("container", timeoutMiddleware, cc.CreateOrUpdateContainer)
-	group.DELETE("container/:name", timeoutMiddleware, cc.DeleteContainer)
+	group.POST("containers/active", readOnly, timeoutMiddleware, audited, cc.SetActive)
+	group.POST("container", readOnly, timeoutMiddleware, audited, cc.CreateOrUpdateContainer)
+	group.GET("container/:name", timeoutMiddleware, cc.GetContainer)
+	group.PATCH("container/:name", readOnly, timeoutMiddleware, audited, cc.PatchContainer)
+	group.POST("container/:name/rename", readOnly, timeoutMiddleware, auditRename(appCtx), cc.RenameContainer)
+	group.DELETE("container/:name", readOnly, timeoutMiddleware, audited, cc.DeleteContainer)
 	group.GET("container/:name/ready", timeoutMiddleware, cc.Ready)
+	group.GET("container/:name/status", timeoutMiddleware, cc.Status)
+	group.GET("group/:name/ready", timeoutMiddleware, cc.GroupReady)
 }
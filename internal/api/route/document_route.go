@@ -0,0 +1,18 @@
+package route
+
+import (
+	"github.com/bassista/go_spin/internal/api/controller"
+	"github.com/bassista/go_spin/internal/api/middleware"
+	"github.com/bassista/go_spin/internal/app"
+	"github.com/gin-gonic/gin"
+)
+
+// NewDocumentRouter sets up the data document export and import routes.
+func NewDocumentRouter(appCtx *app.App, group *gin.RouterGroup) {
+	dc := controller.NewDocumentController(appCtx.Cache)
+	timeoutMiddleware := middleware.RequestTimeout(appCtx.Config.Server.RequestTimeout)
+	readOnly := middleware.ReadOnly(appCtx.ReadOnly())
+
+	group.GET("admin/export", timeoutMiddleware, dc.Export)
+	group.POST("admin/import", readOnly, timeoutMiddleware, auditChanges(appCtx, documentKinds...), dc.Import)
+}
@@ -4,16 +4,20 @@ import (
 	"github.com/bassista/go_spin/internal/api/controller"
 	"github.com/bassista/go_spin/internal/api/middleware"
 	"github.com/bassista/go_spin/internal/app"
+	"github.com/bassista/go_spin/internal/audit"
 	"github.com/gin-gonic/gin"
 )
 
 func NewGroupRouter(appCtx *app.App, group *gin.RouterGroup) {
-	gc := controller.NewGroupController(appCtx.BaseCtx, appCtx.Cache, appCtx.Runtime)
+	gc := controller.NewGroupController(appCtx.BaseCtx, appCtx.Cache, appCtx.Runtime, appCtx.Ops, appCtx.Config)
+	gc.SetExecHealthChecks(appCtx.Config.Auth.Enabled())
 	timeoutMiddleware := middleware.RequestTimeout(appCtx.Config.Server.RequestTimeout)
+	readOnly := middleware.ReadOnly(appCtx.ReadOnly())
+	audited := auditChanges(appCtx, audit.KindGroup)
 
 	group.GET("groups", timeoutMiddleware, gc.AllGroups)
-	group.POST("group", timeoutMiddleware, gc.CreateOrUpdateGroup)
-	group.DELETE("group/:name", timeoutMiddleware, gc.DeleteGroup)
-	group.POST("group/:name/start", timeoutMiddleware, gc.StartGroup)
-	group.POST("group/:name/stop", timeoutMiddleware, gc.StopGroup)
+	group.POST("group", readOnly, timeoutMiddleware, audited, gc.CreateOrUpdateGroup)
+	group.DELETE("group/:name", readOnly, timeoutMiddleware, audited, gc.DeleteGroup)
+	group.POST("group/:name/start", timeoutMiddleware, auditAction(appCtx, audit.KindGroup, audit.ActionStart), gc.StartGroup)
+	group.POST("group/:name/stop", timeoutMiddleware, auditAction(appCtx, audit.KindGroup, audit.ActionStop), gc.StopGroup)
 }
@@ -0,0 +1,20 @@
+package route
+
+import (
+	"github.com/bassista/go_spin/internal/api/controller"
+	"github.com/bassista/go_spin/internal/api/middleware"
+	"github.com/bassista/go_spin/internal/app"
+	"github.com/gin-gonic/gin"
+)
+
+// NewImportRouter sets up the routes importing definitions from external sources.
+func NewImportRouter(appCtx *app.App, group *gin.RouterGroup) {
+	ic := controller.NewImportController(appCtx.Cache, appCtx.Runtime, appCtx.Config.Data.BaseUrl)
+	timeoutMiddleware := middleware.RequestTimeout(appCtx.Config.Server.RequestTimeout)
+	readOnly := middleware.ReadOnly(appCtx.ReadOnly())
+
+	audited := auditChanges(appCtx, documentKinds...)
+
+	group.POST("import/runtime", readOnly, timeoutMiddleware, audited, ic.FromRuntime)
+	group.POST("containers/import-from-runtime", readOnly, timeoutMiddleware, audited, ic.Discover)
+}
@@ -0,0 +1,18 @@
+package route
+
+import (
+	"github.com/bassista/go_spin/internal/api/controller"
+	"github.com/bassista/go_spin/internal/api/middleware"
+	"github.com/bassista/go_spin/internal/app"
+	"github.com/gin-gonic/gin"
+)
+
+// NewMaintenanceRouter sets up data maintenance routes.
+func NewMaintenanceRouter(appCtx *app.App, group *gin.RouterGroup) {
+	mc := controller.NewMaintenanceController(appCtx.Cache)
+	timeoutMiddleware := middleware.RequestTimeout(appCtx.Config.Server.RequestTimeout)
+	readOnly := middleware.ReadOnly(appCtx.ReadOnly())
+
+	group.POST("maintenance/prune", readOnly, timeoutMiddleware, auditChanges(appCtx, documentKinds...), mc.Prune)
+	group.GET("admin/integrity", timeoutMiddleware, mc.Integrity)
+}
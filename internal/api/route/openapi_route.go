@@ -0,0 +1,162 @@
+package route
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/bassista/go_spin/internal/api/apierror"
+	"github.com/bassista/go_spin/internal/api/controller"
+	"github.com/bassista/go_spin/internal/api/middleware"
+	"github.com/bassista/go_spin/internal/api/openapi"
+	"github.com/bassista/go_spin/internal/app"
+	"github.com/bassista/go_spin/internal/audit"
+	"github.com/bassista/go_spin/internal/build"
+	"github.com/bassista/go_spin/internal/metrics"
+	"github.com/bassista/go_spin/internal/ops"
+	"github.com/bassista/go_spin/internal/repository"
+	"github.com/bassista/go_spin/internal/scheduler"
+	"github.com/gin-gonic/gin"
+)
+
+// Paths of the API description and of its Swagger UI.
+const (
+	openAPIPath     = "/api/openapi.json"
+	swaggerUIPath   = "/api/docs"
+	openAPITitle    = "go_spin management API"
+	swaggerUIAssets = "https://unpkg.com/swagger-ui-dist@5"
+)
+
+// swaggerUIPage renders openAPIPath with the Swagger UI bundle.
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>` + openAPITitle + `</title>
+  <link rel="stylesheet" href="` + swaggerUIAssets + `/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="` + swaggerUIAssets + `/swagger-ui-bundle.js"></script>
+  <script>
+    SwaggerUIBundle({ url: "` + openAPIPath + `", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>
+`
+
+// apiEndpoints holds the request/response models of the routes, keyed by "METHOD /path" with the
+// path relative to /api/<version>. Routes missing here are described without models.
+var apiEndpoints = map[string]openapi.Endpoint{
+	"GET /containers":                  {Summary: "List containers", Response: []repository.Container{}, Query: []string{"label", "includeHidden"}},
+	"POST /containers/active":          {Summary: "Activate or deactivate containers", Request: controller.SetActiveRequest{}, Response: controller.SetActiveResponse{}},
+	"POST /container":                  {Summary: "Create or update a container", Request: repository.Container{}, Response: []repository.Container{}, Query: []string{"force"}},
+	"GET /container/:name":             {Summary: "Get a container", Response: controller.ContainerDetailResponse{}},
+	"PATCH /container/:name":           {Summary: "Apply a JSON merge patch to a container", Request: map[string]any{}, Response: repository.Container{}},
+	"POST /container/:name/rename":     {Summary: "Rename a container", Request: controller.RenameRequest{}, Response: controller.RenameResponse{}},
+	"DELETE /container/:name":          {Summary: "Delete a container", Response: []repository.Container{}},
+	"GET /container/:name/ready":       {Summary: "Readiness of a container", Response: controller.ReadyResponse{}},
+	"GET /container/:name/status":      {Summary: "Waiting state of a container or group", Response: controller.WaitingStatusResponse{}},
+	"POST /container/:name/override":   {Summary: "Suppress the scheduled stops of a container", Response: controller.ContainerOverrideResponse{}, Query: []string{"until"}},
+	"DELETE /container/:name/override": {Summary: "Remove the override of a container", Response: controller.ClearedResponse{}},
+
+	"GET /groups":             {Summary: "List groups", Response: []repository.Group{}},
+	"POST /group":             {Summary: "Create or update a group", Request: repository.Group{}, Response: []repository.Group{}, Query: []string{"force"}},
+	"DELETE /group/:name":     {Summary: "Delete a group", Response: []repository.Group{}},
+	"POST /group/:name/start": {Summary: "Start the members of a group", Response: controller.GroupActionResponse{}},
+	"POST /group/:name/stop":  {Summary: "Stop the members of a group", Response: controller.GroupActionResponse{}},
+	"GET /group/:name/ready":  {Summary: "Readiness of a group", Response: controller.GroupReadyResponse{}},
+
+	"GET /schedules":              {Summary: "List schedules", Response: []repository.Schedule{}},
+	"POST /schedule":              {Summary: "Create or update a schedule", Request: repository.Schedule{}, Response: []repository.Schedule{}},
+	"DELETE /schedule/:id":        {Summary: "Delete a schedule", Response: []repository.Schedule{}},
+	"GET /default-schedule":       {Summary: "Get the default schedule", Response: repository.DefaultSchedule{}},
+	"PUT /default-schedule":       {Summary: "Replace the default schedule", Request: repository.DefaultSchedule{}, Response: repository.DefaultSchedule{}},
+	"POST /schedule/:id/extend":   {Summary: "Offset today's stop times of a schedule", Request: controller.ExtendScheduleRequest{}, Response: scheduler.Extension{}},
+	"DELETE /schedule/:id/extend": {Summary: "Remove the extension of a schedule", Response: controller.ClearedResponse{}},
+	"GET /schedule/:id/next":      {Summary: "Next start and stop of a schedule", Response: controller.ScheduleNextResponse{}},
+	"POST /schedule/:id/pause":    {Summary: "Pause a schedule", Response: controller.PauseResponse{}},
+	"POST /schedule/:id/resume":   {Summary: "Resume a schedule", Response: controller.PauseResponse{}},
+	"GET /scheduler/active":       {Summary: "Schedules active right now", Response: []scheduler.ScheduleState{}},
+	"GET /schedules/preview":      {Summary: "Planned actions in a time range", Response: controller.PreviewResponse{}, Query: []string{"from", "to"}},
+	"GET /overrides":              {Summary: "List the scheduler overrides", Response: controller.OverridesResponse{}},
+
+	"GET /runtime/:name/status":  {Summary: "Whether a container is running", Response: controller.ActionResponse{}},
+	"POST /runtime/:name/start":  {Summary: "Queue the start of a container", Response: controller.ActionResponse{}},
+	"POST /runtime/:name/stop":   {Summary: "Queue the stop of a container", Response: controller.ActionResponse{}},
+	"POST /runtime/:name/wait":   {Summary: "Wait for a container to reach a state", Response: controller.WaitResponse{}, Query: []string{"state", "timeout"}},
+	"POST /runtime/:name/exec":   {Summary: "Run an allowlisted command in a container", Request: controller.ExecRequest{}, Response: controller.ExecResponse{}},
+	"GET /runtime/:name/metrics": {Summary: "Recorded CPU and memory samples", Response: []metrics.Sample{}, Query: []string{"window"}},
+	"GET /runtime/containers":    {Summary: "Containers known to the runtime", Response: []string{}},
+	"GET /runtime/drift":         {Summary: "Differences between the document and the runtime", Response: controller.DriftResponse{}},
+	"GET /runtime/ping":          {Summary: "Check the runtime connection", Response: controller.PingResponse{}},
+	"GET /runtime/health":        {Summary: "Health of the runtime connection", Response: controller.RuntimeHealthResponse{}},
+	"GET /runtime/stats":         {Summary: "CPU and memory of every container", Response: []controller.ContainerStatsResponse{}},
+	"GET /runtime/next-events":   {Summary: "Next scheduled start and stop of every container", Response: map[string]scheduler.NextEvent{}},
+	"GET /start/:name":           {Summary: "Waiting page starting a container or group", Response: controller.WaitingResponse{}, Query: []string{"format"}},
+	"GET /ops/:id":               {Summary: "Progress of a queued start or stop", Response: ops.Operation{}},
+	"GET /overview":              {Summary: "Dashboard view of every container", Response: controller.OverviewResponse{}},
+
+	"GET /health":        {Summary: "Liveness check"},
+	"GET /version":       {Summary: "Build information", Response: controller.VersionResponse{}},
+	"GET /configuration": {Summary: "Effective configuration", Response: controller.ConfigurationResponse{}},
+	"GET /status":        {Summary: "Sizes of the in-memory structures", Response: controller.StatusResponse{}},
+	"GET /safe-mode":     {Summary: "Safe mode state", Response: controller.SafeModeResponse{}},
+	"POST /safe-mode":    {Summary: "Enable or disable safe mode", Request: controller.SafeModeRequest{}, Response: controller.SafeModeResponse{}},
+	"GET /tenants":       {Summary: "List the tenant documents", Response: []controller.TenantSummary{}},
+
+	"POST /maintenance/prune":              {Summary: "Remove orphan references", Response: controller.PruneResponse{}, Query: []string{"dryRun"}},
+	"GET /admin/integrity":                 {Summary: "Check the document integrity", Response: controller.IntegrityResponse{}},
+	"GET /admin/backups":                   {Summary: "List the backups of the data file", Response: []repository.Backup{}},
+	"POST /admin/restore/:id":              {Summary: "Restore a backup", Response: controller.RestoreResponse{}},
+	"GET /admin/export":                    {Summary: "Export the data document", Response: repository.DataDocument{}},
+	"GET /admin/audit":                     {Summary: "List the audit log entries", Response: []audit.Entry{}, Query: []string{"actor", "action", "kind", "name", "tenant", "since", "until", "limit"}},
+	"POST /admin/import":                   {Summary: "Import a data document", Request: repository.DataDocument{}, Response: controller.ImportDocumentResponse{}, Query: []string{"mode", "dryRun"}},
+	"POST /import/runtime":                 {Summary: "Import the runtime containers", Response: controller.ImportResponse{}},
+	"POST /containers/import-from-runtime": {Summary: "Propose containers found in the runtime", Response: controller.DiscoveryResponse{}, Query: []string{"autoGroup"}},
+	"PUT /waiting-template":                {Summary: "Replace the waiting page template", Status: http.StatusNoContent},
+}
+
+// NewOpenAPIRouter describes the routes registered so far on r at /api/openapi.json and serves
+// a Swagger UI for it at /api/docs. It must run after every other router. Both routes are public
+// like /health; the description declares the authentication of the management API.
+func NewOpenAPIRouter(appCtx *app.App, r *gin.Engine) {
+	builder := openapi.NewBuilder(openAPITitle, build.Current().Version)
+	builder.SetErrorModel(apierror.Response{})
+	if len(appCtx.Config.Auth.KeyRoles()) > 0 {
+		builder.AddSecurityScheme("apiKey", openapi.SecurityScheme{Type: "apiKey", In: "header", Name: middleware.HeaderAPIKey})
+	}
+	if len(appCtx.Config.Auth.Users) > 0 {
+		builder.AddSecurityScheme("basicAuth", openapi.SecurityScheme{Type: "http", Scheme: "basic"})
+	}
+	builder.AddRoutes(r.Routes(), func(route gin.RouteInfo) (openapi.Endpoint, bool) {
+		return describeRoute(appCtx, route)
+	})
+	doc := builder.Document()
+
+	r.GET(openAPIPath, func(c *gin.Context) {
+		c.JSON(http.StatusOK, doc)
+	})
+	r.GET(swaggerUIPath, func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(swaggerUIPage))
+	})
+}
+
+// describeRoute returns the models of route. The UI files, the tenant copies of the routes and
+// the unversioned aliases are left out.
+func describeRoute(appCtx *app.App, route gin.RouteInfo) (openapi.Endpoint, bool) {
+	if route.Method == http.MethodHead || route.Path == "/" || route.Path == "/favicon.ico" ||
+		route.Path == "/ui" || strings.HasPrefix(route.Path, "/ui/") {
+		return openapi.Endpoint{}, false
+	}
+	path := route.Path
+	for _, version := range apiVersions {
+		if rest, ok := strings.CutPrefix(path, apiPrefix+version); ok {
+			path = rest
+			break
+		}
+	}
+	if strings.HasPrefix(path, "/"+tenantsPrefix) {
+		return openapi.Endpoint{}, false
+	}
+	return apiEndpoints[route.Method+" "+path], true
+}
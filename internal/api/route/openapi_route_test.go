@@ -0,0 +1,109 @@
+package route
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bassista/go_spin/internal/api/openapi"
+	"github.com/bassista/go_spin/internal/app"
+	"github.com/bassista/go_spin/internal/audit"
+	"github.com/bassista/go_spin/internal/config"
+	"github.com/bassista/go_spin/internal/janitor"
+	"github.com/bassista/go_spin/internal/ops"
+	"github.com/bassista/go_spin/internal/repository"
+	"github.com/bassista/go_spin/internal/runtime"
+	"github.com/bassista/go_spin/internal/scheduler"
+	"github.com/bassista/go_spin/internal/waiting"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+func TestOpenAPIRouter_DescribesRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.Config{Server: config.ServerConfig{RequestTimeout: time.Second}}
+	cfg.Auth.APIKeys = []string{"secret"}
+	cfg.Data.BackupRetention = 1
+	repo, err := repository.NewJSONRepository(filepath.Join(t.TempDir(), "config.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	auditLog, err := audit.Open(filepath.Join(t.TempDir(), "audit.jsonl"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer auditLog.Close()
+	rt := &mockContainerRuntime{}
+	appCtx := &app.App{
+		Config:          cfg,
+		Repo:            repo,
+		Audit:           auditLog,
+		Cache:           &mockAppStore{},
+		Runtime:         rt,
+		SafeMode:        runtime.NewSafeModeRuntime(rt, false),
+		Ops:             ops.NewQueue(context.Background(), rt, time.Minute),
+		Overrides:       scheduler.NewOverrides(),
+		Janitor:         janitor.New(time.Now),
+		WaitingTemplate: waiting.NewTemplate(filepath.Join(t.TempDir(), "waiting.html"), ""),
+		BaseCtx:         context.Background(),
+		Tenants: map[string]*app.App{
+			"team-a": {Config: cfg, Cache: &mockAppStore{}, Runtime: rt, Tenant: "team-a", BaseCtx: context.Background()},
+		},
+	}
+	r := SetupRoutes(appCtx, logrus.New())
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, openAPIPath, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected the description without credentials, got %d", w.Code)
+	}
+	var doc openapi.Document
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("failed to decode the description: %v", err)
+	}
+	if doc.OpenAPI != openapi.Version || doc.Components.SecuritySchemes["apiKey"] == nil {
+		t.Errorf("unexpected document header: %+v", doc)
+	}
+
+	// Every documented endpoint is a registered route
+	for key := range apiEndpoints {
+		method, path, _ := strings.Cut(key, " ")
+		openPath := apiPrefix + apiV1 + path
+		if path == "/health" || path == "/version" {
+			openPath = path
+		}
+		for _, segment := range strings.Split(path, "/") {
+			if strings.HasPrefix(segment, ":") {
+				openPath = strings.Replace(openPath, segment, "{"+segment[1:]+"}", 1)
+			}
+		}
+		item, ok := doc.Paths[openPath]
+		if !ok || (*item)[strings.ToLower(method)] == nil {
+			t.Errorf("%s is documented but not registered", key)
+		}
+	}
+
+	create := (*doc.Paths["/api/v1/container"])["post"]
+	if create == nil || create.RequestBody == nil || create.RequestBody.Content["application/json"].Schema.Ref != "#/components/schemas/Container" {
+		t.Fatalf("expected POST /container to reference the Container model, got %+v", create)
+	}
+	if _, ok := doc.Paths["/ui"]; ok {
+		t.Error("expected the UI routes left out")
+	}
+	for _, path := range []string{"/api/v1/tenants/team-a/containers", "/containers"} {
+		if _, ok := doc.Paths[path]; ok {
+			t.Errorf("expected %s left out", path)
+		}
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, swaggerUIPath, nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), openAPIPath) {
+		t.Errorf("expected the Swagger UI page, got %d", w.Code)
+	}
+}
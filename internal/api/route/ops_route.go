@@ -0,0 +1,19 @@
+package route
+
+import (
+	"github.com/bassista/go_spin/internal/api/controller"
+	"github.com/bassista/go_spin/internal/api/middleware"
+	"github.com/bassista/go_spin/internal/app"
+	"github.com/gin-gonic/gin"
+)
+
+// NewOpsRouter sets up the operation status route, when starts and stops go through the queue.
+func NewOpsRouter(appCtx *app.App, group *gin.RouterGroup) {
+	if appCtx.Ops == nil {
+		return
+	}
+	oc := controller.NewOpsController(appCtx.Ops)
+	timeoutMiddleware := middleware.RequestTimeout(appCtx.Config.Server.RequestTimeout)
+
+	group.GET("ops/:id", timeoutMiddleware, oc.Get)
+}
@@ -0,0 +1,23 @@
+package route
+
+import (
+	"github.com/bassista/go_spin/internal/api/controller"
+	"github.com/bassista/go_spin/internal/api/middleware"
+	"github.com/bassista/go_spin/internal/app"
+	"github.com/gin-gonic/gin"
+)
+
+// NewOverrideRouter sets up the manual scheduler override routes, when the app holds overrides.
+func NewOverrideRouter(appCtx *app.App, group *gin.RouterGroup) {
+	if appCtx.Overrides == nil {
+		return
+	}
+	oc := controller.NewOverrideController(appCtx.Cache, appCtx.Overrides, appCtx.Config)
+	timeoutMiddleware := middleware.RequestTimeout(appCtx.Config.Server.RequestTimeout)
+
+	group.GET("overrides", timeoutMiddleware, oc.List)
+	group.POST("schedule/:id/pause", timeoutMiddleware, oc.PauseSchedule)
+	group.POST("schedule/:id/resume", timeoutMiddleware, oc.ResumeSchedule)
+	group.POST("container/:name/override", timeoutMiddleware, oc.SetContainerOverride)
+	group.DELETE("container/:name/override", timeoutMiddleware, oc.ClearContainerOverride)
+}
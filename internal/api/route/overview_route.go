@@ -0,0 +1,17 @@
+package route
+
+import (
+	"github.com/bassista/go_spin/internal/api/controller"
+	"github.com/bassista/go_spin/internal/api/middleware"
+	"github.com/bassista/go_spin/internal/app"
+	"github.com/gin-gonic/gin"
+)
+
+// NewOverviewRouter sets up the aggregated dashboard route, /api/overview on the unversioned
+// routes.
+func NewOverviewRouter(appCtx *app.App, group *gin.RouterGroup) {
+	oc := controller.NewOverviewController(appCtx.Cache, appCtx.Runtime, appCtx.Ops, appCtx.Config)
+	timeoutMiddleware := middleware.RequestTimeout(appCtx.Config.Server.RequestTimeout)
+
+	group.GET("overview", timeoutMiddleware, oc.Get)
+}
@@ -2,37 +2,115 @@ package route
 
 import (
 	"net/http"
+	"strings"
 
+	"github.com/bassista/go_spin/internal/api/controller"
 	"github.com/bassista/go_spin/internal/api/middleware"
 	"github.com/bassista/go_spin/internal/app"
+	"github.com/bassista/go_spin/internal/config"
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 )
 
+// API versions served under /api/<version>, oldest first. The unversioned paths serve v1.
+const apiV1 = "v1"
+
+var apiVersions = []string{apiV1}
+
+// apiPrefix roots the versioned routes.
+const apiPrefix = "/api/"
+
 func SetupRoutes(appCtx *app.App, logger *logrus.Logger) *gin.Engine {
 	r := gin.New()
+	r.Use(middleware.RequestID())
 	r.Use(middleware.HoneybadgerMiddleware(logger))
 	r.Use(gin.Recovery())
 	r.Use(middleware.HoneybadgerMiddleware(logger))
-	r.Use(middleware.CORSMiddleware(appCtx.Config.Server.CORSAllowedOrigins))
+	r.Use(middleware.CORSMiddleware(appCtx.Config.Server.CORSAllowedOrigins,
+		middleware.WithCORSMaxAge(appCtx.Config.Server.CORSMaxAge),
+		middleware.WithCORSCredentials(appCtx.Config.Server.CORSAllowCredentials),
+	))
 
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
 			"message": "UP",
 		})
 	})
+	r.GET("/version", controller.NewVersionController(appCtx.Config).GetVersion)
+
+	// Management APIs under /api/<version>
+	NewManagementAPI(appCtx, r.Group(apiPrefix+apiV1, middleware.APIVersion(apiV1)))
+
+	// UI static files; the unversioned API paths of existing clients are served by the versioned
+	// routes: v1, or the version asked with Accept-Version
+	NewUIRouter(r, middleware.VersionFallback(r, apiV1, apiVersions, versionedPath))
+
+	// Describes every route above, so it goes last
+	NewOpenAPIRouter(appCtx, r)
 
-	// All Public APIs
-	publicRouter := r.Group("")
+	return r
+}
+
+// NewManagementAPI sets up the management routes on root, behind authentication when API keys
+// or users are configured: viewers may read, operators also change and start/stop, admins also
+// reach the administrative routes.
+func NewManagementAPI(appCtx *app.App, root *gin.RouterGroup) {
+	publicRouter := root.Group("")
+	publicRouter.Use(middleware.APIAuth(appCtx.Config.Auth.KeyRoles(), authUsers(appCtx.Config.Auth)))
+	publicRouter.Use(middleware.RequireRoleByMethod())
+	// Running commands inside containers needs authentication: the routers enable the exec health
+	// checks only when Auth.Enabled(), and the exec endpoint uses RequireAuthenticatedRole, which
+	// rejects every request when authentication is disabled
+	adminRouter := publicRouter.Group("", middleware.RequireRole(middleware.RoleAdmin))
 
 	NewContainerRouter(appCtx, publicRouter)
 	NewGroupRouter(appCtx, publicRouter)
 	NewScheduleRouter(appCtx, publicRouter)
+	NewSchedulerRouter(appCtx, publicRouter)
 	NewRuntimeRouter(appCtx, publicRouter)
+	NewOpsRouter(appCtx, publicRouter)
+	NewOverviewRouter(appCtx, publicRouter)
 	NewConfigurationRouter(appCtx, publicRouter)
+	NewMaintenanceRouter(appCtx, adminRouter)
+	NewImportRouter(appCtx, adminRouter)
+	NewSafeModeRouter(appCtx, adminRouter)
+	NewWaitingTemplateRouter(appCtx, adminRouter)
+	NewBackupRouter(appCtx, adminRouter)
+	NewDocumentRouter(appCtx, adminRouter)
+	NewAuditRouter(appCtx, adminRouter)
+	NewStatusRouter(appCtx, publicRouter)
+	NewStateStreamRouter(appCtx, publicRouter)
+	NewOverrideRouter(appCtx, publicRouter)
+	NewTenantRouter(appCtx, publicRouter, adminRouter)
+}
 
-	// UI static files
-	NewUIRouter(r)
+// versionedPath maps an unversioned API path to the one of version, "" for the paths under
+// /ui and the ones already versioned. /api/overview and the tenant routes /api/<tenant>/...
+// predate the versions: they become /api/<version>/overview and /api/<version>/tenants/<tenant>/...
+func versionedPath(path, version string) string {
+	if path == "/ui" || strings.HasPrefix(path, "/ui/") {
+		return ""
+	}
+	rest, underAPI := strings.CutPrefix(path, apiPrefix)
+	if !underAPI {
+		return apiPrefix + version + path
+	}
+	for _, v := range apiVersions {
+		if rest == v || strings.HasPrefix(rest, v+"/") {
+			return ""
+		}
+	}
+	if rest == "overview" {
+		return apiPrefix + version + "/overview"
+	}
+	return apiPrefix + version + "/" + tenantsPrefix + rest
+}
 
-	return r
+// authUsers returns the basic-auth users of the management API with their role.
+func authUsers(auth config.AuthConfig) map[string]middleware.User {
+	users := make(map[string]middleware.User, len(auth.Users))
+	for _, u := range auth.Users {
+		users[u.Username] = middleware.User{Password: u.Password, Role: u.RoleOrDefault()}
+	}
+	return users
 }
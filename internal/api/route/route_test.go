@@ -0,0 +1,76 @@
+package route
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bassista/go_spin/internal/api/middleware"
+	"github.com/bassista/go_spin/internal/app"
+	"github.com/bassista/go_spin/internal/cache"
+	"github.com/bassista/go_spin/internal/config"
+	"github.com/bassista/go_spin/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+func TestSetupRoutes_VersionedAndLegacyPaths(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.Config{Server: config.ServerConfig{RequestTimeout: time.Second}}
+	active := true
+	tenantStore := cache.NewStore(repository.DataDocument{
+		Containers: []repository.Container{{Name: "team-web", FriendlyName: "team-web", URL: "http://team.local", Active: &active}},
+	})
+	rt := &mockContainerRuntime{}
+	appCtx := &app.App{
+		Config:  cfg,
+		Cache:   &mockAppStore{},
+		Runtime: rt,
+		BaseCtx: context.Background(),
+		Tenants: map[string]*app.App{
+			"team-a": {Config: cfg, Cache: tenantStore, Runtime: rt, Tenant: "team-a", BaseCtx: context.Background()},
+		},
+	}
+	r := SetupRoutes(appCtx, logrus.New())
+
+	tests := []struct {
+		name    string
+		path    string
+		version string
+		status  int
+		want    string
+	}{
+		{"versioned", "/api/v1/containers", "", http.StatusOK, "test-container"},
+		{"legacy", "/containers", "", http.StatusOK, "test-container"},
+		{"legacy negotiated", "/containers", apiV1, http.StatusOK, "test-container"},
+		{"legacy unsupported version", "/containers", "v0", http.StatusNotAcceptable, ""},
+		{"versioned tenant", "/api/v1/tenants/team-a/containers", "", http.StatusOK, "team-web"},
+		{"legacy tenant", "/api/team-a/containers", "", http.StatusOK, "team-web"},
+		{"legacy overview", "/api/overview", "", http.StatusOK, "containers"},
+		{"unknown", "/api/v1/missing", "", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		if tt.version != "" {
+			req.Header.Set(middleware.HeaderAcceptVersion, tt.version)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tt.status {
+			t.Errorf("%s: expected status %d, got %d: %s", tt.name, tt.status, w.Code, w.Body.String())
+			continue
+		}
+		if tt.status != http.StatusOK {
+			continue
+		}
+		if !strings.Contains(w.Body.String(), tt.want) {
+			t.Errorf("%s: expected %q in %s", tt.name, tt.want, w.Body.String())
+		}
+		if got := w.Header().Get(middleware.HeaderAPIVersion); got != apiV1 {
+			t.Errorf("%s: expected API-Version %s, got %q", tt.name, apiV1, got)
+		}
+	}
+}
@@ -4,19 +4,34 @@ import (
 	"github.com/bassista/go_spin/internal/api/controller"
 	"github.com/bassista/go_spin/internal/api/middleware"
 	"github.com/bassista/go_spin/internal/app"
+	"github.com/bassista/go_spin/internal/audit"
 	"github.com/gin-gonic/gin"
 )
 
 func NewRuntimeRouter(appCtx *app.App, group *gin.RouterGroup) {
 	rc := controller.NewRuntimeController(appCtx)
+	rc.SetExecHealthChecks(appCtx.Config.Auth.Enabled())
 
 	// Apply default timeout middleware to most routes
 	defaultTimeout := middleware.RequestTimeout(appCtx.Config.Server.RequestTimeout)
 	group.GET("runtime/:name/status", defaultTimeout, rc.IsRunning)
-	group.POST("runtime/:name/start", defaultTimeout, rc.StartContainer)
-	group.POST("runtime/:name/stop", defaultTimeout, rc.StopContainer)
+	group.POST("runtime/:name/start", defaultTimeout, auditAction(appCtx, audit.KindContainer, audit.ActionStart), rc.StartContainer)
+	group.POST("runtime/:name/stop", defaultTimeout, auditAction(appCtx, audit.KindContainer, audit.ActionStop), rc.StopContainer)
 	group.GET("runtime/containers", defaultTimeout, rc.ListContainers)
-	group.GET("start/:name", defaultTimeout, rc.WaitingPage)
+	group.GET("runtime/drift", defaultTimeout, rc.Drift)
+	group.GET("runtime/ping", defaultTimeout, rc.Ping)
+	group.GET("runtime/health", defaultTimeout, rc.Health)
+	group.GET("runtime/:name/metrics", defaultTimeout, rc.Metrics)
+	// These reads start the container when it is not running
+	operator := middleware.RequireRole(middleware.RoleOperator)
+	reported := auditReported(appCtx)
+	group.GET("start/:name", defaultTimeout, operator, reported, rc.WaitingPage)
+	group.GET("go/:name", defaultTimeout, operator, reported, rc.GoTo)
+
+	// The wait endpoint long-polls up to its own timeout query parameter, so it has no request timeout
+	group.POST("runtime/:name/wait", reported, rc.Wait)
+	// Commands run inside containers are bounded by runtime.exec_timeout_secs instead
+	group.POST("runtime/:name/exec", middleware.RequireAuthenticatedRole(middleware.RoleAdmin), rc.Exec)
 
 	// Stats endpoint needs a longer timeout since it queries all containers
 	statsRequestTimeout := appCtx.Config.Server.ReadTimeout
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -83,6 +84,7 @@ func (m *mockAppStore) RemoveSchedule(id string) (repository.DataDocument, error
 
 func (m *mockAppStore) ClearDirty()            {}
 func (m *mockAppStore) SetLastUpdate(ts int64) {}
+func (m *mockAppStore) Version() uint64        { return 0 }
 
 func TestRuntimeRoute_StatsEndpointHasLongerTimeout(t *testing.T) {
 	gin.SetMode(gin.TestMode)
@@ -93,7 +95,7 @@ func TestRuntimeRoute_StatsEndpointHasLongerTimeout(t *testing.T) {
 	r := gin.New()
 	group := r.Group("/api")
 
-	cfg := &config.Config{Server: config.ServerConfig{ReadTimeout: 30 * time.Second, WriteTimeout: 30 * time.Second, RequestTimeout: 100 * time.Millisecond}}
+	cfg := &config.Config{Server: config.ServerConfig{ReadTimeout: 30 * time.Second, WriteTimeout: 30 * time.Second, RequestTimeout: 100 * time.Millisecond}, Runtime: config.RuntimeConfig{StatsEnabled: true}}
 
 	appCtx := &app.App{Config: cfg, Cache: mockStore, Runtime: mockRT, BaseCtx: context.Background()}
 	NewRuntimeRouter(appCtx, group)
@@ -117,7 +119,7 @@ func TestRuntimeRoute_DefaultTimeoutAppliedToOtherRoutes(t *testing.T) {
 	r := gin.New()
 	group := r.Group("/api")
 
-	cfg := &config.Config{Server: config.ServerConfig{RequestTimeout: 50 * time.Millisecond, ReadTimeout: 30 * time.Second, WriteTimeout: 30 * time.Second}}
+	cfg := &config.Config{Server: config.ServerConfig{RequestTimeout: 50 * time.Millisecond, ReadTimeout: 30 * time.Second, WriteTimeout: 30 * time.Second}, Runtime: config.RuntimeConfig{StatsEnabled: true}}
 	appCtx := &app.App{Config: cfg, Cache: mockStore, Runtime: mockRT, BaseCtx: context.Background()}
 	NewRuntimeRouter(appCtx, group)
 
@@ -140,7 +142,7 @@ func TestRuntimeRoute_StatsTimeoutIsIndependentFromDefaultTimeout(t *testing.T)
 	r := gin.New()
 	group := r.Group("/api")
 
-	cfg := &config.Config{Server: config.ServerConfig{RequestTimeout: 50 * time.Millisecond, ReadTimeout: 30 * time.Second, WriteTimeout: 30 * time.Second}}
+	cfg := &config.Config{Server: config.ServerConfig{RequestTimeout: 50 * time.Millisecond, ReadTimeout: 30 * time.Second, WriteTimeout: 30 * time.Second}, Runtime: config.RuntimeConfig{StatsEnabled: true}}
 	appCtx := &app.App{Config: cfg, Cache: mockStore, Runtime: mockRT, BaseCtx: context.Background()}
 	NewRuntimeRouter(appCtx, group)
 
@@ -166,7 +168,7 @@ func TestRuntimeRoute_StatsContextDeadlineIsApproximately30Seconds(t *testing.T)
 	r := gin.New()
 	group := r.Group("/api")
 
-	cfg := &config.Config{Server: config.ServerConfig{RequestTimeout: 100 * time.Millisecond, ReadTimeout: 30 * time.Second, WriteTimeout: 30 * time.Second}}
+	cfg := &config.Config{Server: config.ServerConfig{RequestTimeout: 100 * time.Millisecond, ReadTimeout: 30 * time.Second, WriteTimeout: 30 * time.Second}, Runtime: config.RuntimeConfig{StatsEnabled: true}}
 	appCtx := &app.App{Config: cfg, Cache: mockStore, Runtime: mockRT, BaseCtx: context.Background()}
 	NewRuntimeRouter(appCtx, group)
 
@@ -206,3 +208,24 @@ func TestRuntimeRoute_StatsContextDeadlineIsApproximately30Seconds(t *testing.T)
 		t.Errorf("stats context deadline should be ~30s, got deadline at %v (expected around %v)", capturedDeadline, expectedDeadline)
 	}
 }
+
+func (m *mockContainerRuntime) Ping(ctx context.Context) (runtime.PingResult, error) {
+	return runtime.PingResult{}, nil
+}
+
+func TestRuntimeRoute_ExecClosedWithoutAuthentication(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	cfg := &config.Config{Server: config.ServerConfig{RequestTimeout: time.Second}}
+	appCtx := &app.App{Config: cfg, Cache: &mockAppStore{}, Runtime: &mockContainerRuntime{}, BaseCtx: context.Background()}
+	NewRuntimeRouter(appCtx, r.Group("/api"))
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/runtime/test-container/exec", strings.NewReader(`{"command":"flush"}`))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected status 403 without authentication, got %d", w.Code)
+	}
+}
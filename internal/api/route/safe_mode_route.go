@@ -0,0 +1,20 @@
+package route
+
+import (
+	"github.com/bassista/go_spin/internal/api/controller"
+	"github.com/bassista/go_spin/internal/api/middleware"
+	"github.com/bassista/go_spin/internal/app"
+	"github.com/gin-gonic/gin"
+)
+
+// NewSafeModeRouter sets up the safe mode toggle routes, when the runtime is wrapped for it.
+func NewSafeModeRouter(appCtx *app.App, group *gin.RouterGroup) {
+	if appCtx.SafeMode == nil {
+		return
+	}
+	sc := controller.NewSafeModeController(appCtx.SafeMode)
+	timeoutMiddleware := middleware.RequestTimeout(appCtx.Config.Server.RequestTimeout)
+
+	group.GET("safe-mode", timeoutMiddleware, sc.Get)
+	group.POST("safe-mode", timeoutMiddleware, sc.Set)
+}
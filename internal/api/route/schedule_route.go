@@ -4,14 +4,25 @@ import (
 	"github.com/bassista/go_spin/internal/api/controller"
 	"github.com/bassista/go_spin/internal/api/middleware"
 	"github.com/bassista/go_spin/internal/app"
+	"github.com/bassista/go_spin/internal/audit"
 	"github.com/gin-gonic/gin"
 )
 
 func NewScheduleRouter(appCtx *app.App, group *gin.RouterGroup) {
 	sc := controller.NewScheduleController(appCtx.Cache)
 	timeoutMiddleware := middleware.RequestTimeout(appCtx.Config.Server.RequestTimeout)
+	readOnly := middleware.ReadOnly(appCtx.ReadOnly())
+	audited := auditChanges(appCtx, audit.KindSchedule)
 
 	group.GET("schedules", timeoutMiddleware, sc.AllSchedules)
-	group.POST("schedule", timeoutMiddleware, sc.CreateOrUpdateSchedule)
-	group.DELETE("schedule/:id", timeoutMiddleware, sc.DeleteSchedule)
+	group.POST("schedule", readOnly, timeoutMiddleware, audited, sc.CreateOrUpdateSchedule)
+	group.DELETE("schedule/:id", readOnly, timeoutMiddleware, audited, sc.DeleteSchedule)
+
+	dc := controller.NewDefaultScheduleController(appCtx.Cache)
+	group.GET("default-schedule", timeoutMiddleware, dc.GetDefaultSchedule)
+	group.PUT("default-schedule", readOnly, timeoutMiddleware, auditChanges(appCtx, audit.KindDefaultSchedule), dc.PutDefaultSchedule)
+
+	ec := controller.NewScheduleExtensionController(appCtx.Cache, appCtx.Extensions, appCtx.Config)
+	group.POST("schedule/:id/extend", timeoutMiddleware, ec.ExtendSchedule)
+	group.DELETE("schedule/:id/extend", timeoutMiddleware, ec.ClearScheduleExtension)
 }
@@ -0,0 +1,23 @@
+package route
+
+import (
+	"github.com/bassista/go_spin/internal/api/controller"
+	"github.com/bassista/go_spin/internal/api/middleware"
+	"github.com/bassista/go_spin/internal/app"
+	"github.com/gin-gonic/gin"
+)
+
+func NewSchedulerRouter(appCtx *app.App, group *gin.RouterGroup) {
+	sc := controller.NewSchedulerController(appCtx.Cache, appCtx.Config)
+	sc.SetExtensions(appCtx.Extensions)
+
+	timeoutMiddleware := middleware.RequestTimeout(appCtx.Config.Server.RequestTimeout)
+	group.GET("scheduler/active", timeoutMiddleware, sc.Active)
+	group.GET("runtime/next-events", timeoutMiddleware, sc.NextEvents)
+
+	// The plan is streamed and can span many days, so it uses the longer read timeout
+	planTimeout := middleware.RequestTimeout(appCtx.Config.Server.ReadTimeout)
+	group.GET("scheduler/plan.csv", planTimeout, sc.PlanCSV)
+	group.GET("schedules/preview", planTimeout, sc.Preview)
+	group.GET("schedule/:id/next", timeoutMiddleware, sc.ScheduleNext)
+}
@@ -0,0 +1,18 @@
+package route
+
+import (
+	"github.com/bassista/go_spin/internal/api/controller"
+	"github.com/bassista/go_spin/internal/app"
+	"github.com/gin-gonic/gin"
+)
+
+// NewStateStreamRouter sets up the live container state stream, when it is enabled.
+func NewStateStreamRouter(appCtx *app.App, group *gin.RouterGroup) {
+	if appCtx.State == nil {
+		return
+	}
+	sc := controller.NewStateStreamController(appCtx.State)
+
+	// The stream is long-lived, so it has no request timeout
+	group.GET("ws/state", sc.Stream)
+}
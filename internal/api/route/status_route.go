@@ -0,0 +1,19 @@
+package route
+
+import (
+	"github.com/bassista/go_spin/internal/api/controller"
+	"github.com/bassista/go_spin/internal/api/middleware"
+	"github.com/bassista/go_spin/internal/app"
+	"github.com/gin-gonic/gin"
+)
+
+// NewStatusRouter sets up the status route, when the app has a janitor.
+func NewStatusRouter(appCtx *app.App, group *gin.RouterGroup) {
+	if appCtx.Janitor == nil {
+		return
+	}
+	sc := controller.NewStatusController(appCtx.Janitor)
+	timeoutMiddleware := middleware.RequestTimeout(appCtx.Config.Server.RequestTimeout)
+
+	group.GET("status", timeoutMiddleware, sc.Get)
+}
@@ -0,0 +1,44 @@
+package route
+
+import (
+	"github.com/bassista/go_spin/internal/api/controller"
+	"github.com/bassista/go_spin/internal/api/middleware"
+	"github.com/bassista/go_spin/internal/app"
+	"github.com/bassista/go_spin/internal/cache"
+	"github.com/gin-gonic/gin"
+)
+
+// tenantsPrefix roots the routes of each tenant, /api/v1/tenants/<tenant>/...
+const tenantsPrefix = "tenants/"
+
+// NewTenantRouter sets up the routes of the tenant data documents, when the app has tenants:
+// GET /tenants and, for each tenant, the container, group, schedule, scheduler, runtime and
+// override routes under tenants/<tenant> on group, and the maintenance, import, backup and
+// export/import ones on adminGroup.
+func NewTenantRouter(appCtx *app.App, group, adminGroup *gin.RouterGroup) {
+	if len(appCtx.Tenants) == 0 {
+		return
+	}
+	stores := make(map[string]cache.ReadOnlyStore, len(appCtx.Tenants))
+	for _, name := range appCtx.TenantNames() {
+		tenant := appCtx.Tenants[name]
+		stores[name] = tenant.Cache
+
+		tenantGroup := group.Group(tenantsPrefix + name)
+		NewContainerRouter(tenant, tenantGroup)
+		NewGroupRouter(tenant, tenantGroup)
+		NewScheduleRouter(tenant, tenantGroup)
+		NewSchedulerRouter(tenant, tenantGroup)
+		NewRuntimeRouter(tenant, tenantGroup)
+		NewOverrideRouter(tenant, tenantGroup)
+
+		tenantAdminGroup := adminGroup.Group(tenantsPrefix + name)
+		NewMaintenanceRouter(tenant, tenantAdminGroup)
+		NewImportRouter(tenant, tenantAdminGroup)
+		NewBackupRouter(tenant, tenantAdminGroup)
+		NewDocumentRouter(tenant, tenantAdminGroup)
+	}
+
+	tc := controller.NewTenantController(stores)
+	group.GET("tenants", middleware.RequestTimeout(appCtx.Config.Server.RequestTimeout), tc.List)
+}
@@ -0,0 +1,71 @@
+package route
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bassista/go_spin/internal/api/controller"
+	"github.com/bassista/go_spin/internal/app"
+	"github.com/bassista/go_spin/internal/cache"
+	"github.com/bassista/go_spin/internal/config"
+	"github.com/bassista/go_spin/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+func TestTenantRouter_ServesTenantDocuments(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cfg := &config.Config{Server: config.ServerConfig{RequestTimeout: time.Second}}
+	active := true
+	tenantStore := cache.NewStore(repository.DataDocument{
+		Containers: []repository.Container{{Name: "team-web", FriendlyName: "team-web", URL: "http://team.local", Active: &active}},
+	})
+	rt := &mockContainerRuntime{}
+	appCtx := &app.App{
+		Config:  cfg,
+		Cache:   &mockAppStore{},
+		Runtime: rt,
+		BaseCtx: context.Background(),
+		Tenants: map[string]*app.App{
+			"team-a": {Config: cfg, Cache: tenantStore, Runtime: rt, Tenant: "team-a", BaseCtx: context.Background()},
+		},
+	}
+
+	r := gin.New()
+	group := r.Group("")
+	NewContainerRouter(appCtx, group)
+	NewTenantRouter(appCtx, group, group)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tenants/team-a/containers", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "team-web") || strings.Contains(w.Body.String(), "test-container") {
+		t.Fatalf("expected the containers of team-a, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/containers", nil))
+	if w.Code != http.StatusOK || strings.Contains(w.Body.String(), "team-web") {
+		t.Fatalf("expected the main containers, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tenants", nil))
+	var tenants []controller.TenantSummary
+	if err := json.Unmarshal(w.Body.Bytes(), &tenants); err != nil {
+		t.Fatalf("failed to decode tenants: %v", err)
+	}
+	if len(tenants) != 1 || tenants[0].Name != "team-a" || tenants[0].Containers != 1 {
+		t.Errorf("expected tenant team-a with 1 container, got %+v", tenants)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tenants/team-b/containers", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown tenant, got %d", w.Code)
+	}
+}
@@ -4,12 +4,14 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/bassista/go_spin/internal/api/apierror"
 	"github.com/gin-gonic/gin"
 )
 
 // NewUIRouter sets up routes to serve the UI static files under /ui.
-// It serves index.html for the root and any sub-paths (SPA routing).
-func NewUIRouter(r *gin.Engine) {
+// It serves index.html for the root and any sub-paths (SPA routing). The fallback handlers run
+// before it for the paths matching no route.
+func NewUIRouter(r *gin.Engine, fallback ...gin.HandlerFunc) {
 	// Serve static assets (JS, CSS, images)
 	r.Static("/ui/assets", "./ui/assets")
 
@@ -30,7 +32,7 @@ func NewUIRouter(r *gin.Engine) {
 	})
 
 	// Serve index.html for any sub-path under /ui (SPA client-side routing)
-	r.NoRoute(func(c *gin.Context) {
+	r.NoRoute(append(fallback, func(c *gin.Context) {
 		p := c.Request.URL.Path
 
 		// Only handle /ui/* paths, return 404 for others
@@ -38,6 +40,6 @@ func NewUIRouter(r *gin.Engine) {
 			c.File("./ui/index.html")
 			return
 		}
-		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
-	})
+		apierror.Respond(c, http.StatusNotFound, "not found")
+	})...)
 }
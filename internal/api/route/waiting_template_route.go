@@ -0,0 +1,20 @@
+package route
+
+import (
+	"github.com/bassista/go_spin/internal/api/controller"
+	"github.com/bassista/go_spin/internal/api/middleware"
+	"github.com/bassista/go_spin/internal/app"
+	"github.com/gin-gonic/gin"
+)
+
+// NewWaitingTemplateRouter sets up the routes editing the waiting page template.
+func NewWaitingTemplateRouter(appCtx *app.App, group *gin.RouterGroup) {
+	if appCtx.WaitingTemplate == nil {
+		return
+	}
+	wc := controller.NewWaitingTemplateController(appCtx.WaitingTemplate)
+	timeoutMiddleware := middleware.RequestTimeout(appCtx.Config.Server.RequestTimeout)
+
+	group.GET("waiting-template", timeoutMiddleware, wc.Get)
+	group.PUT("waiting-template", timeoutMiddleware, wc.Put)
+}
@@ -3,14 +3,25 @@ package app
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strconv"
 	"time"
 
+	"github.com/bassista/go_spin/internal/audit"
 	"github.com/bassista/go_spin/internal/cache"
 	"github.com/bassista/go_spin/internal/config"
+	"github.com/bassista/go_spin/internal/discovery"
+	"github.com/bassista/go_spin/internal/events"
+	"github.com/bassista/go_spin/internal/gitops"
+	"github.com/bassista/go_spin/internal/janitor"
 	"github.com/bassista/go_spin/internal/logger"
+	"github.com/bassista/go_spin/internal/metrics"
+	"github.com/bassista/go_spin/internal/ops"
 	"github.com/bassista/go_spin/internal/repository"
 	"github.com/bassista/go_spin/internal/runtime"
 	"github.com/bassista/go_spin/internal/scheduler"
+	"github.com/bassista/go_spin/internal/waiting"
+	"github.com/bassista/go_spin/internal/webhook"
 )
 
 // App is the application container (immutable dependencies + lifecycle context).
@@ -21,9 +32,57 @@ type App struct {
 	Cache   cache.AppStore
 	Runtime runtime.ContainerRuntime
 
+	// SafeMode wraps Runtime: while enabled every start/stop is skipped (misc.safe_mode, POST /safe-mode).
+	SafeMode *runtime.SafeModeRuntime
+
+	// Ops queues the starts and stops issued through Runtime by the API, the waiting page and the
+	// scheduler, coalescing the concurrent requests for the same container.
+	Ops *ops.Queue
+
+	// ReadCache serves hot read endpoints; it is a read replica of Cache when data.read_replica
+	// is enabled, nil otherwise (readers then use Cache).
+	ReadCache cache.ReadOnlyStore
+
+	// Extensions holds the transient (today only) schedule stop time offsets shared by the API and the scheduler.
+	Extensions *scheduler.Extensions
+
+	// Overrides holds the manual scheduler overrides (paused schedules, container stop overrides)
+	// shared by the API and the scheduler.
+	Overrides *scheduler.Overrides
+
+	// WaitingTemplate is the waiting page template shared by the API and the waiting server.
+	WaitingTemplate *waiting.Template
+
+	// Events carries the state change notifications (container started/stopped, schedule fired,
+	// cache replaced, config persisted) to the internal consumers.
+	Events *events.Bus
+
+	// Janitor periodically prunes the expired entries of the in-memory maps (day flags, schedule
+	// extensions, readiness caches); their sizes are exposed by GET /status.
+	Janitor *janitor.Janitor
+
+	// State fans container running state/stats changes out to the /ws/state stream; nil when
+	// data.state_stream_interval_millis is 0.
+	State *StateBus
+
+	// Metrics holds the recent per-container stats samples; nil unless runtime.metrics_retention_minutes is set.
+	Metrics *metrics.History
+
+	// Audit records the changes of containers, groups and schedules and the manual starts/stops
+	// made through the API; nil when data.audit_file_path is empty.
+	Audit *audit.Log
+
+	// Tenant is the name of the tenant data document served by this App, empty for the main one.
+	Tenant string
+
+	// Tenants are the tenant data documents loaded from data.tenants_dir by LoadTenants, by name.
+	// Only the main App has tenants; each of them is an App sharing its runtime, bus and janitor.
+	Tenants map[string]*App
+
 	BaseCtx     context.Context
 	Cancel      context.CancelFunc
 	persistDone <-chan struct{} // signal for completion of persistence scheduler
+	janitorDone <-chan struct{} // signal for completion of the janitor
 }
 
 func New(cfg *config.Config, repo repository.Repository, store cache.AppStore, rt runtime.ContainerRuntime) (*App, error) {
@@ -48,14 +107,66 @@ func New(cfg *config.Config, repo repository.Repository, store cache.AppStore, r
 
 	logger.WithComponent("app").Debugf("all dependencies validated")
 
+	// Every start/stop goes through the safe mode wrapper, whichever component triggers it; the
+	// events wrapper sits below it so skipped actions publish nothing.
+	bus := events.NewBus()
+	safeMode := runtime.NewSafeModeRuntime(events.NewRuntime(rt, bus), cfg.Misc.SafeMode)
+	if cfg.Misc.SafeMode {
+		logger.WithComponent("app").Warnf("safe mode enabled: start/stop actions are skipped")
+	}
+
+	// Day keys are computed in the scheduling timezone, like the scheduler does; an invalid
+	// timezone is reported when the scheduler starts.
+	loc, err := cfg.Misc.SchedulingLocation()
+	if err != nil {
+		loc = time.Local
+	}
+	extensions := scheduler.NewExtensions()
+	j := janitor.New(func() time.Time { return time.Now().In(loc) })
+	j.Register("schedule_extensions", extensions)
+	overrides := scheduler.NewOverrides()
+	j.Register("scheduler_overrides", overrides)
+
 	ctx, cancel := context.WithCancel(context.Background())
+	queue := ops.NewQueue(ctx, safeMode, cfg.Data.OperationsRetention)
+	j.Register("operations", queue)
+
+	templatePath := cfg.UI.WaitingTemplatePath
+	if templatePath == "" {
+		templatePath = waiting.DefaultTemplatePath
+	}
+
+	var state *StateBus
+	if cfg.Data.StateStreamInterval > 0 {
+		state = NewStateBus()
+	}
+
+	var auditLog *audit.Log
+	if cfg.Data.AuditFilePath != "" {
+		auditLog, err = audit.Open(cfg.Data.AuditFilePath, cfg.Data.AuditMaxEntries)
+		if err != nil {
+			cancel()
+			logger.WithComponent("app").Errorf("cannot open audit log: %v", err)
+			return nil, fmt.Errorf("cannot open audit log: %w", err)
+		}
+	}
+
 	return &App{
-		Config:  cfg,
-		Repo:    repo,
-		Cache:   store,
-		Runtime: rt,
-		BaseCtx: ctx,
-		Cancel:  cancel,
+		Config:          cfg,
+		Repo:            repo,
+		Cache:           store,
+		Runtime:         safeMode,
+		SafeMode:        safeMode,
+		Ops:             queue,
+		Events:          bus,
+		Extensions:      extensions,
+		Overrides:       overrides,
+		Janitor:         j,
+		State:           state,
+		Audit:           auditLog,
+		WaitingTemplate: waiting.LoadTemplate(templatePath),
+		BaseCtx:         ctx,
+		Cancel:          cancel,
 	}, nil
 }
 
@@ -68,11 +179,24 @@ func (a *App) Shutdown() {
 	}
 	a.Cancel()
 
-	// Attende il completamento del persistence scheduler
+	// Attende il completamento del persistence scheduler, anche dei tenant
+	for _, tenant := range a.Tenants {
+		if tenant.persistDone != nil {
+			<-tenant.persistDone
+		}
+	}
 	if a.persistDone != nil {
 		logger.WithComponent("app").Debugf("waiting for persistence scheduler to complete")
 		<-a.persistDone
 	}
+	if a.janitorDone != nil {
+		<-a.janitorDone
+	}
+	if a.Audit != nil {
+		if err := a.Audit.Close(); err != nil {
+			logger.WithComponent("app").Warnf("cannot close audit log: %v", err)
+		}
+	}
 
 	logger.WithComponent("app").Debugf("app shutdown completed")
 }
@@ -80,30 +204,144 @@ func (a *App) Shutdown() {
 func (a *App) StartWatchers() {
 	logger.WithComponent("app").Debugf("starting watchers")
 
-	if err := a.Repo.StartWatcher(a.BaseCtx, a.Cache); err != nil {
-		logger.WithComponent("app").Fatalf("cannot start config file watcher: %v", err)
+	// A synced document follows its remote source, local edits of the data file are not reloaded
+	if a.Config.Sync.Enabled() {
+		source, err := gitops.NewSource(a.Config.Sync)
+		if err != nil {
+			logger.WithComponent("app").Fatalf("cannot init sync source: %v", err)
+		}
+		gitops.NewSyncer(source, a.Cache, a.Repo, a.Config.Sync.Timeout).Start(a.BaseCtx, a.Config.Sync.Interval)
+		logger.WithComponent("app").Infof("data document synced from %s every %v, the API cannot change it", source, a.Config.Sync.Interval)
+	} else {
+		if err := a.Repo.StartWatcher(a.BaseCtx, a.Cache); err != nil {
+			logger.WithComponent("app").Fatalf("cannot start config file watcher: %v", err)
+		}
+		logger.WithComponent("app").Debugf("file watcher started")
 	}
 
-	logger.WithComponent("app").Debugf("file watcher started")
-
-	// Start scheduled persistence goroutine
-	a.persistDone = cache.StartPersistenceScheduler(a.BaseCtx, a.Cache, a.Repo, a.Config.Data.PersistInterval)
-	logger.WithComponent("app").Debugf("persistence scheduler started")
-
-	if a.Config.Data.SchedulingEnabled {
-		loc := time.Local
-		if a.Config.Misc.SchedulingTZ != "" && a.Config.Misc.SchedulingTZ != "Local" {
-			l, err := time.LoadLocation(a.Config.Misc.SchedulingTZ)
+	if a.Config.Data.ReadReplica {
+		primary, ok := a.Cache.(*cache.Store)
+		if !ok {
+			logger.WithComponent("app").Warnf("read replica requires the in-memory cache store, serving reads from the primary")
+		} else {
+			replica, err := cache.NewReplicatedStore(a.BaseCtx, primary, a.Config.Data.ReadReplicaResync)
 			if err != nil {
-				logger.WithComponent("app").Fatalf("invalid scheduling timezone: %v", err)
+				logger.WithComponent("app").Fatalf("cannot start read replica: %v", err)
 			}
-			loc = l
+			a.ReadCache = replica
+			logger.WithComponent("app").Debugf("read replica started, resync interval: %v", a.Config.Data.ReadReplicaResync)
 		}
+	}
 
-		logger.WithComponent("app").Debugf("starting polling scheduler with timezone: %v", loc)
-		s := scheduler.NewPollingScheduler(a.Cache, a.Runtime, a.Config.Data.SchedulingPoll, loc)
-		s.Start(a.BaseCtx)
+	if a.Config.Runtime.MetricsRetention > 0 {
+		if !a.Config.Runtime.StatsEnabled {
+			logger.WithComponent("app").Warnf("metrics history requires runtime.stats_enabled, not sampling")
+		} else {
+			interval := time.Duration(a.Config.Data.StatsRefreshIntervalSecs) * time.Second
+			a.Metrics = metrics.NewHistory(a.Config.Runtime.MetricsRetention, interval, a.Config.Runtime.MetricsMaxSamples)
+			metrics.StartSampler(a.BaseCtx, a.Cache, a.Runtime, a.Metrics, interval)
+			logger.WithComponent("app").Debugf("metrics sampler started, interval: %v", interval)
+		}
 	}
 
+	if watcher, ok := a.Runtime.(runtime.StateWatcher); ok && a.Config.Runtime.WatchEvents {
+		go watcher.WatchState(a.BaseCtx)
+		logger.WithComponent("app").Debugf("runtime state watcher started")
+	}
+
+	if a.Config.Runtime.DiscoveryInterval > 0 {
+		a.startDiscovery()
+	}
+
+	startCacheEvents(a.BaseCtx, a.Cache, a.Events)
+	if len(a.Config.Webhooks.Hooks) > 0 {
+		webhook.Start(a.BaseCtx, a.Events, a.Config.Webhooks)
+		logger.WithComponent("app").Debugf("%d webhooks started", len(a.Config.Webhooks.Hooks))
+	}
+
+	if a.State != nil {
+		StartStateWatcher(a.BaseCtx, a.Cache, a.Runtime, a.State, a.Config.Data.StateStreamInterval, a.Config.Runtime.StatsEnabled)
+		ForwardContainerEvents(a.BaseCtx, a.Events, a.State)
+	}
+
+	if err := a.WaitingTemplate.Watch(a.BaseCtx); err != nil {
+		logger.WithComponent("app").Warnf("waiting template changes will not be reloaded: %v", err)
+	}
+
+	a.startDocumentWatchers()
+	for _, tenant := range a.Tenants {
+		if err := tenant.Repo.StartWatcher(tenant.BaseCtx, tenant.Cache); err != nil {
+			logger.WithComponent("app").Fatalf("cannot start data file watcher of tenant %s: %v", tenant.Tenant, err)
+		}
+		tenant.startDocumentWatchers()
+	}
+
+	a.janitorDone = a.Janitor.Start(a.BaseCtx, a.Config.Data.JanitorInterval)
+
 	logger.WithComponent("app").Debugf("all watchers started successfully")
 }
+
+// startDiscovery registers the main document containers from their runtime labels. Tenants share
+// the runtime, so they do not run their own discovery.
+func (a *App) startDiscovery() {
+	store, ok := a.Cache.(cache.TransactionalStore)
+	if !ok {
+		logger.WithComponent("app").Warnf("discovery requires a transactional cache store, not started")
+		return
+	}
+	worker, err := discovery.NewWorker(a.Runtime, store, a.Config.Runtime.DiscoveryLabelPrefix, a.Config.Data.BaseUrl)
+	if err != nil {
+		logger.WithComponent("app").Warnf("discovery not started: %v", err)
+		return
+	}
+	worker.Start(a.BaseCtx, a.Config.Runtime.DiscoveryInterval)
+	logger.WithComponent("app").Infof("containers labeled %s.enable=true discovered every %v", a.Config.Runtime.DiscoveryLabelPrefix, a.Config.Runtime.DiscoveryInterval)
+}
+
+// ReadOnly reports whether the data document of a is synced from a remote source and so cannot be
+// changed through the API. Tenant documents are never synced.
+func (a *App) ReadOnly() bool {
+	return a.Tenant == "" && a.Config.Sync.Enabled()
+}
+
+// startDocumentWatchers starts the persistence scheduler and the polling scheduler of the data
+// document of a, the main one or a tenant.
+func (a *App) startDocumentWatchers() {
+	a.persistDone = cache.StartPersistenceScheduler(a.BaseCtx, a.Cache, a.Repo, a.Config.Data.PersistInterval,
+		cache.WithWriteThrough(a.Config.Data.WriteThrough),
+		cache.WithOnPersist(func(lastUpdate int64) {
+			data := map[string]string{"last_update": strconv.FormatInt(lastUpdate, 10)}
+			if a.Tenant != "" {
+				data["tenant"] = a.Tenant
+			}
+			a.Events.Publish(events.Event{Type: events.ConfigPersisted, Data: data})
+		}))
+	logger.WithComponent("app").Debugf("persistence scheduler started%s", a.tenantSuffix())
+
+	if !a.Config.Data.SchedulingEnabled {
+		return
+	}
+	loc, err := a.Config.Misc.SchedulingLocation()
+	if err != nil {
+		logger.WithComponent("app").Fatalf("invalid scheduling timezone: %v", err)
+	}
+
+	logger.WithComponent("app").Debugf("starting polling scheduler%s with timezone: %v", a.tenantSuffix(), loc)
+	opts := []scheduler.Option{
+		scheduler.WithChangeTrigger(a.Config.Data.SchedulingChangeDebounce, a.Config.Data.SchedulingMinTrigger),
+		scheduler.WithExtensions(a.Extensions),
+		scheduler.WithOverrides(a.Overrides),
+		scheduler.WithShards(a.Config.Data.SchedulerShards),
+		scheduler.WithEvents(a.Events),
+		scheduler.WithExactTransitions(a.Config.Data.SchedulingExact),
+		scheduler.WithOperations(a.Ops),
+		scheduler.WithErrorLogDedup(a.Config.Data.SchedulingErrorLogDedup),
+	}
+	if a.Config.Data.MaxLoadForStart > 0 {
+		logger.WithComponent("app").Debugf("scheduler load guard enabled, max load for start: %.2f", a.Config.Data.MaxLoadForStart)
+		opts = append(opts, scheduler.WithLoadGuard(scheduler.NewProcLoadProvider(), a.Config.Data.MaxLoadForStart))
+	}
+	s := scheduler.NewPollingScheduler(a.Cache, a.Runtime, a.Config.Data.SchedulingPoll, loc, opts...)
+	a.Janitor.Register(a.janitorName("scheduler_day_flags"), s)
+	s.Start(a.BaseCtx)
+}
@@ -107,6 +107,10 @@ func (m *mockAppStore) SetLastUpdate(ts int64) {
 	m.lastUpdate = ts
 }
 
+func (m *mockAppStore) Version() uint64 {
+	return 0
+}
+
 // mockContainerRuntime implements runtime.ContainerRuntime for testing
 type mockRuntimeForApp struct {
 	runningContainers map[string]bool
@@ -333,3 +337,7 @@ func TestApp_StartWatchers_Success(t *testing.T) {
 	// Shutdown to clean up scheduler goroutine
 	app.Shutdown()
 }
+
+func (m *mockRuntimeForApp) Ping(ctx context.Context) (runtime.PingResult, error) {
+	return runtime.PingResult{}, nil
+}
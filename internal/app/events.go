@@ -0,0 +1,36 @@
+package app
+
+import (
+	"context"
+
+	"github.com/bassista/go_spin/internal/cache"
+	"github.com/bassista/go_spin/internal/events"
+	"github.com/bassista/go_spin/internal/logger"
+)
+
+// startCacheEvents publishes an events.CacheReplaced event every time the whole cache is replaced
+// (the config file was reloaded), when store publishes change events.
+func startCacheEvents(ctx context.Context, store cache.AppStore, bus *events.Bus) {
+	notifier, ok := store.(cache.ChangeNotifier)
+	if !ok {
+		logger.WithComponent("events").Debugf("cache store publishes no change events, cache replaced events disabled")
+		return
+	}
+	changes, unsubscribe := notifier.Subscribe()
+	go func() {
+		defer unsubscribe()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case ev, ok := <-changes:
+				if !ok {
+					return
+				}
+				if ev.Kind == cache.ChangeReplace {
+					bus.Publish(events.Event{Type: events.CacheReplaced})
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,197 @@
+package app
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bassista/go_spin/internal/cache"
+	"github.com/bassista/go_spin/internal/events"
+	"github.com/bassista/go_spin/internal/logger"
+	"github.com/bassista/go_spin/internal/runtime"
+)
+
+// stateSubscriberBuffer is the number of pending events kept per subscriber; a subscriber that
+// falls further behind misses events (the next change of the same container catches it up).
+const stateSubscriberBuffer = 64
+
+// StateEvent reports the running state and, when stats are enabled, the stats of a container.
+type StateEvent struct {
+	Container  string   `json:"container"`
+	Running    bool     `json:"running"`
+	CPUPercent *float64 `json:"cpu_percent,omitempty"`
+	MemoryMB   *float64 `json:"memory_mb,omitempty"`
+}
+
+// StateBus fans container state changes out to live subscribers (the /ws/state stream). Only
+// events that differ from the last one published for the same container reach the subscribers.
+type StateBus struct {
+	mu   sync.Mutex
+	subs map[chan StateEvent]struct{}
+	last map[string]StateEvent
+}
+
+// NewStateBus creates an empty StateBus.
+func NewStateBus() *StateBus {
+	return &StateBus{subs: map[chan StateEvent]struct{}{}, last: map[string]StateEvent{}}
+}
+
+// Subscribe returns a channel receiving the state changes and a function ending the subscription.
+func (b *StateBus) Subscribe() (<-chan StateEvent, func()) {
+	ch := make(chan StateEvent, stateSubscriberBuffer)
+	b.mu.Lock()
+	b.subs[ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subs, ch)
+			b.mu.Unlock()
+		})
+	}
+}
+
+// Subscribers returns the number of live subscriptions.
+func (b *StateBus) Subscribers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subs)
+}
+
+// Publish records ev and delivers it to every subscriber unless it equals the last event of the
+// same container. Delivery never blocks: a full subscriber misses the event.
+func (b *StateBus) Publish(ev StateEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if prev, ok := b.last[ev.Container]; ok && sameState(prev, ev) {
+		return
+	}
+	b.last[ev.Container] = ev
+	for ch := range b.subs {
+		select {
+		case ch <- ev:
+		default:
+			logger.WithComponent("state-bus").Debugf("subscriber behind, dropping state event of %s", ev.Container)
+		}
+	}
+}
+
+// Current returns the last known state of every container, sorted by name, so a new subscriber
+// can render the full picture before the first change arrives.
+func (b *StateBus) Current() []StateEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	events := make([]StateEvent, 0, len(b.last))
+	for _, ev := range b.last {
+		events = append(events, ev)
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].Container < events[j].Container })
+	return events
+}
+
+// Forget drops the containers not in names, so removed containers are no longer reported.
+func (b *StateBus) Forget(names map[string]struct{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for name := range b.last {
+		if _, ok := names[name]; !ok {
+			delete(b.last, name)
+		}
+	}
+}
+
+func sameState(a, b StateEvent) bool {
+	return a.Running == b.Running && equalFloatPtr(a.CPUPercent, b.CPUPercent) && equalFloatPtr(a.MemoryMB, b.MemoryMB)
+}
+
+func equalFloatPtr(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// StartStateWatcher samples the running state (and the stats when withStats is set) of every
+// container each interval and publishes the changes on bus. Sampling is skipped while nobody is
+// subscribed, so the runtime is not queried for an empty audience.
+func StartStateWatcher(ctx context.Context, store cache.ReadOnlyStore, rt runtime.ContainerRuntime, bus *StateBus, interval time.Duration, withStats bool) {
+	logger.WithComponent("state-bus").Debugf("starting state watcher with interval: %v, stats: %v", interval, withStats)
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				logger.WithComponent("state-bus").Debugf("state watcher stopped")
+				return
+			case <-ticker.C:
+				if bus.Subscribers() == 0 {
+					continue
+				}
+				// Bound a sweep by the interval so a slow runtime cannot pile sweeps up.
+				sweepCtx, cancel := context.WithTimeout(ctx, interval)
+				sampleState(sweepCtx, store, rt, bus, withStats)
+				cancel()
+			}
+		}
+	}()
+}
+
+// sampleState publishes the current state of every container of the store.
+func sampleState(ctx context.Context, store cache.ReadOnlyStore, rt runtime.ContainerRuntime, bus *StateBus, withStats bool) {
+	doc, err := store.Snapshot()
+	if err != nil {
+		logger.WithComponent("state-bus").Errorf("snapshot error: %v", err)
+		return
+	}
+	// A runtime watching its events answers the whole sweep from one copy of its state
+	var states map[string]bool
+	if watcher, ok := rt.(runtime.StateWatcher); ok {
+		states, _ = watcher.RunningStates()
+	}
+	names := make(map[string]struct{}, len(doc.Containers))
+	for _, c := range doc.Containers {
+		names[c.Name] = struct{}{}
+		running, known := states[c.Name]
+		if !known {
+			var err error
+			if running, err = rt.IsRunning(ctx, c.Name); err != nil {
+				logger.WithComponent("state-bus").Debugf("IsRunning(%s) error: %v", c.Name, err)
+				continue
+			}
+		}
+		ev := StateEvent{Container: c.Name, Running: running}
+		if withStats && running {
+			if stats, err := rt.Stats(ctx, c.Name); err == nil {
+				ev.CPUPercent, ev.MemoryMB = &stats.CPUPercent, &stats.MemoryMB
+			} else {
+				logger.WithComponent("state-bus").Debugf("Stats(%s) error: %v", c.Name, err)
+			}
+		}
+		bus.Publish(ev)
+	}
+	bus.Forget(names)
+}
+
+// ForwardContainerEvents pushes the container started/stopped events of bus to state as
+// soon as they happen, instead of waiting for the next sampling of the state watcher.
+func ForwardContainerEvents(ctx context.Context, bus *events.Bus, state *StateBus) {
+	ch, unsubscribe := bus.Subscribe(events.ContainerStarted, events.ContainerStopped)
+	go func() {
+		defer unsubscribe()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case ev, ok := <-ch:
+				if !ok {
+					return
+				}
+				state.Publish(StateEvent{Container: ev.Subject, Running: ev.Type == events.ContainerStarted})
+			}
+		}
+	}()
+}
@@ -0,0 +1,86 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bassista/go_spin/internal/events"
+	"github.com/bassista/go_spin/internal/repository"
+)
+
+func TestStateBus_PublishesOnlyChanges(t *testing.T) {
+	bus := NewStateBus()
+	events, unsubscribe := bus.Subscribe()
+
+	bus.Publish(StateEvent{Container: "c1", Running: true})
+	bus.Publish(StateEvent{Container: "c1", Running: true})
+	bus.Publish(StateEvent{Container: "c1", Running: false})
+
+	for _, want := range []bool{true, false} {
+		select {
+		case ev := <-events:
+			if ev.Running != want {
+				t.Errorf("expected running=%v, got %+v", want, ev)
+			}
+		default:
+			t.Fatalf("expected an event with running=%v", want)
+		}
+	}
+	select {
+	case ev := <-events:
+		t.Errorf("expected the repeated state to be skipped, got %+v", ev)
+	default:
+	}
+
+	unsubscribe()
+	if bus.Subscribers() != 0 {
+		t.Errorf("expected no subscribers after unsubscribe, got %d", bus.Subscribers())
+	}
+}
+
+func TestSampleState_PublishesRuntimeStateAndForgetsRemoved(t *testing.T) {
+	rt := newMockRuntimeForApp()
+	rt.runningContainers["web"] = true
+	store := &mockAppStore{doc: repository.DataDocument{Containers: []repository.Container{{Name: "web"}, {Name: "db"}}}}
+	bus := NewStateBus()
+	bus.Publish(StateEvent{Container: "removed", Running: true})
+
+	sampleState(context.Background(), store, rt, bus, true)
+
+	current := bus.Current()
+	if len(current) != 2 || current[0].Container != "db" || current[1].Container != "web" {
+		t.Fatalf("expected db and web only, got %+v", current)
+	}
+	if current[0].Running || current[0].CPUPercent != nil {
+		t.Errorf("expected db stopped without stats, got %+v", current[0])
+	}
+	if !current[1].Running || current[1].CPUPercent == nil || current[1].MemoryMB == nil {
+		t.Errorf("expected web running with stats, got %+v", current[1])
+	}
+}
+
+func TestForwardContainerEvents_PushesStartsAndStops(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	bus := events.NewBus()
+	state := NewStateBus()
+	stateEvents, unsubscribe := state.Subscribe()
+	defer unsubscribe()
+
+	ForwardContainerEvents(ctx, bus, state)
+	bus.Publish(events.Event{Type: events.ContainerStarted, Subject: "c1"})
+	bus.Publish(events.Event{Type: events.ConfigPersisted})
+	bus.Publish(events.Event{Type: events.ContainerStopped, Subject: "c1"})
+
+	for _, want := range []bool{true, false} {
+		select {
+		case ev := <-stateEvents:
+			if ev.Container != "c1" || ev.Running != want {
+				t.Errorf("expected c1 running=%v, got %+v", want, ev)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("expected a state event with running=%v", want)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bassista/go_spin/internal/cache"
+	"github.com/bassista/go_spin/internal/logger"
+	"github.com/bassista/go_spin/internal/repository"
+	"github.com/bassista/go_spin/internal/scheduler"
+)
+
+// tenantFileSuffix is the extension of the tenant data documents in data.tenants_dir.
+const tenantFileSuffix = ".json"
+
+// tenantNamePattern restricts tenant names, taken from the file names, to safe URL path segments.
+var tenantNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// LoadTenants loads every <tenant>.json file of data.tenants_dir as a tenant App. Tenants share the
+// configuration, the runtime, the event bus, the janitor and the waiting template of a, and have
+// their own repository, cache store, overrides and, once StartWatchers runs, data file watcher,
+// persistence and scheduler. Files whose name is not a valid tenant name are skipped with a warning.
+// It must be called before StartWatchers.
+func (a *App) LoadTenants(ctx context.Context) error {
+	dir := a.Config.Data.TenantsDir
+	if dir == "" {
+		return nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("cannot read tenants dir %s: %w", dir, err)
+	}
+
+	tenants := make(map[string]*App)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), tenantFileSuffix) {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), tenantFileSuffix)
+		if !tenantNamePattern.MatchString(name) {
+			logger.WithComponent("app").Warnf("skipping tenant file %s: the name must match %s", entry.Name(), tenantNamePattern)
+			continue
+		}
+
+		repo, err := repository.NewJSONRepository(filepath.Join(dir, entry.Name()),
+			repository.WithSaveMode(a.Config.Data.SaveMode),
+			repository.WithCompression(a.Config.Data.Compress),
+			repository.WithBackups(a.Config.Data.BackupRetention),
+		)
+		if err != nil {
+			return fmt.Errorf("cannot init repository of tenant %s: %w", name, err)
+		}
+		doc, err := repository.LoadWithRetry(ctx, repo, a.Config.Data.LoadRetries, a.Config.Data.LoadRetryDelay)
+		if err != nil {
+			return fmt.Errorf("cannot load data file of tenant %s: %w", name, err)
+		}
+		tenants[name] = a.newTenant(name, repo, cache.NewStore(*doc))
+		logger.WithComponent("app").Infof("tenant %s loaded: %d containers, %d groups, %d schedules",
+			name, len(doc.Containers), len(doc.Groups), len(doc.Schedules))
+	}
+	a.Tenants = tenants
+	return nil
+}
+
+// newTenant creates the App of tenant name on top of a.
+func (a *App) newTenant(name string, repo repository.Repository, store cache.AppStore) *App {
+	ctx, cancel := context.WithCancel(a.BaseCtx)
+	tenant := &App{
+		Config:          a.Config,
+		Repo:            repo,
+		Cache:           store,
+		Runtime:         a.Runtime,
+		SafeMode:        a.SafeMode,
+		Ops:             a.Ops,
+		Extensions:      scheduler.NewExtensions(),
+		Overrides:       scheduler.NewOverrides(),
+		WaitingTemplate: a.WaitingTemplate,
+		Events:          a.Events,
+		Janitor:         a.Janitor,
+		Audit:           a.Audit,
+		Tenant:          name,
+		BaseCtx:         ctx,
+		Cancel:          cancel,
+	}
+	a.Janitor.Register(tenant.janitorName("schedule_extensions"), tenant.Extensions)
+	a.Janitor.Register(tenant.janitorName("scheduler_overrides"), tenant.Overrides)
+	return tenant
+}
+
+// TenantNames returns the names of the tenants, sorted.
+func (a *App) TenantNames() []string {
+	names := make([]string, 0, len(a.Tenants))
+	for name := range a.Tenants {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ContainerStores returns the cache stores of the main document and of the tenants, sorted by
+// name, for lookups resolving a container against the document that holds it.
+func (a *App) ContainerStores() []cache.VersionedStore {
+	stores := []cache.VersionedStore{a.Cache}
+	for _, name := range a.TenantNames() {
+		stores = append(stores, a.Tenants[name].Cache)
+	}
+	return stores
+}
+
+// janitorName prefixes name with the tenant, so the janitor registrations of tenants do not clash.
+func (a *App) janitorName(name string) string {
+	if a.Tenant == "" {
+		return name
+	}
+	return "tenant/" + a.Tenant + "/" + name
+}
+
+// tenantSuffix returns " for tenant <name>" for tenant Apps, for log messages.
+func (a *App) tenantSuffix() string {
+	if a.Tenant == "" {
+		return ""
+	}
+	return " for tenant " + a.Tenant
+}
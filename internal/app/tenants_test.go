@@ -0,0 +1,78 @@
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/bassista/go_spin/internal/config"
+)
+
+func TestApp_LoadTenants(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"team-a.json":   `{"containers":[{"name":"web","friendly_name":"web","url":"http://web.local","active":true}],"groups":[],"schedules":[]}`,
+		"team-b.json":   `{"containers":[],"groups":[],"schedules":[]}`,
+		"Bad Name.json": `{}`,
+		"notes.txt":     "not a tenant",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	cfg := &config.Config{Data: config.DataConfig{PersistInterval: 10, TenantsDir: dir}}
+	app, err := New(cfg, &mockRepository{}, &mockAppStore{}, newMockRuntimeForApp())
+	if err != nil {
+		t.Fatalf("failed to create app: %v", err)
+	}
+	defer app.Shutdown()
+
+	if err := app.LoadTenants(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if names := app.TenantNames(); !reflect.DeepEqual(names, []string{"team-a", "team-b"}) {
+		t.Fatalf("expected tenants team-a and team-b, got %v", names)
+	}
+
+	tenant := app.Tenants["team-a"]
+	doc, err := tenant.Cache.Snapshot()
+	if err != nil || len(doc.Containers) != 1 || doc.Containers[0].Name != "web" {
+		t.Fatalf("expected the team-a document, got %+v (%v)", doc, err)
+	}
+	if tenant.Runtime != app.Runtime || tenant.Janitor != app.Janitor || tenant.Tenant != "team-a" {
+		t.Error("expected the tenant to share the runtime and janitor of the main app")
+	}
+	if stores := app.ContainerStores(); len(stores) != 3 || stores[0] != app.Cache || stores[1] != tenant.Cache {
+		t.Errorf("expected the main store then the tenant ones by name, got %v", stores)
+	}
+	if tenant.Overrides == app.Overrides {
+		t.Error("expected the tenant to have its own overrides")
+	}
+	if _, ok := app.Janitor.Sizes()["tenant/team-a/scheduler_overrides"]; !ok {
+		t.Error("expected the tenant overrides to be registered with the janitor")
+	}
+
+	app.StartWatchers()
+	if tenant.persistDone == nil {
+		t.Error("expected the tenant persistence scheduler to be started")
+	}
+}
+
+func TestApp_LoadTenants_Disabled(t *testing.T) {
+	app, err := New(&config.Config{}, &mockRepository{}, &mockAppStore{}, newMockRuntimeForApp())
+	if err != nil {
+		t.Fatalf("failed to create app: %v", err)
+	}
+	defer app.Shutdown()
+
+	if err := app.LoadTenants(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(app.Tenants) != 0 {
+		t.Errorf("expected no tenants, got %v", app.TenantNames())
+	}
+}
@@ -0,0 +1,365 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Actions recorded in the audit log.
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+	ActionRename = "rename"
+	ActionStart  = "start"
+	ActionStop   = "stop"
+)
+
+// Kinds of the audited entities.
+const (
+	KindContainer       = "container"
+	KindGroup           = "group"
+	KindSchedule        = "schedule"
+	KindDefaultSchedule = "default-schedule"
+)
+
+// fileMode is the permission of the audit log file.
+const fileMode = 0644
+
+// maxLineSize bounds a line of the file, that is an entry with its before and after copies.
+const maxLineSize = 4 << 20
+
+// compactFactor rewrites the file with the last maxEntries entries once it holds this many times
+// maxEntries lines, so it does not grow without bound.
+const compactFactor = 2
+
+// Entry is a recorded action.
+type Entry struct {
+	Time         time.Time `json:"time"`
+	Actor        string    `json:"actor"`          // basic-auth user, "api-key" or "anonymous" without authentication
+	Role         string    `json:"role,omitempty"` // role of the actor, empty without authentication
+	Action       string    `json:"action"`
+	Kind         string    `json:"kind"`
+	Name         string    `json:"name"`                   // container/group name or schedule id
+	PreviousName string    `json:"previousName,omitempty"` // name before a rename
+	Tenant       string    `json:"tenant,omitempty"`       // tenant data document, empty for the main one
+	RequestID    string    `json:"requestId,omitempty"`
+	// Before and After are the entity before and after a create/update/delete, absent respectively
+	// for creates and deletes; Changes lists the top-level fields that differ.
+	Before  json.RawMessage `json:"before,omitempty"`
+	After   json.RawMessage `json:"after,omitempty"`
+	Changes []string        `json:"changes,omitempty"`
+}
+
+// Filter selects entries; empty fields match every entry.
+type Filter struct {
+	Actor  string
+	Action string
+	Kind   string
+	Name   string
+	Tenant string
+	Since  time.Time
+	Until  time.Time
+	Limit  int // at most this many entries, the most recent ones; 0 means no limit
+}
+
+func (f Filter) matches(e Entry) bool {
+	return (f.Actor == "" || e.Actor == f.Actor) &&
+		(f.Action == "" || e.Action == f.Action) &&
+		(f.Kind == "" || e.Kind == f.Kind) &&
+		(f.Name == "" || e.Name == f.Name || e.PreviousName == f.Name) &&
+		(f.Tenant == "" || e.Tenant == f.Tenant) &&
+		(f.Since.IsZero() || !e.Time.Before(f.Since)) &&
+		(f.Until.IsZero() || e.Time.Before(f.Until))
+}
+
+// Log is the audit log: entries are appended to a JSON Lines file, separate from the data
+// document, and the last maxEntries of them are kept in memory for Query.
+type Log struct {
+	mu         sync.Mutex
+	path       string
+	maxEntries int
+	file       *os.File
+	entries    []Entry // oldest first
+	fileLines  int
+	now        func() time.Time
+}
+
+// Open loads the entries of the audit log at path, creating it if needed, and opens it for
+// appending. maxEntries <= 0 keeps every entry.
+func Open(path string, maxEntries int) (*Log, error) {
+	l := &Log{path: path, maxEntries: maxEntries, now: time.Now}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("create audit log dir: %w", err)
+	}
+	if err := l.load(); err != nil {
+		return nil, err
+	}
+	if l.fileLines > len(l.entries) {
+		if err := l.compact(); err != nil {
+			return nil, err
+		}
+	}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Record appends e, setting its time when unset.
+func (l *Log) Record(e Entry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return os.ErrClosed
+	}
+	if e.Time.IsZero() {
+		e.Time = l.now()
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("encode audit entry: %w", err)
+	}
+	if _, err := l.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("write audit entry: %w", err)
+	}
+	l.fileLines++
+	l.append(e)
+	if l.maxEntries > 0 && l.fileLines >= compactFactor*l.maxEntries {
+		return l.reopenCompacted()
+	}
+	return nil
+}
+
+// reopenCompacted closes the file, compacts it and opens it again, even when the compaction
+// fails, so the next entries are still written. The caller must hold l.mu.
+func (l *Log) reopenCompacted() error {
+	if err := l.file.Close(); err != nil {
+		return fmt.Errorf("close audit log: %w", err)
+	}
+	l.file = nil
+	compactErr := l.compact()
+	if err := l.open(); err != nil {
+		return err
+	}
+	return compactErr
+}
+
+// Query returns the entries matching f, most recent first.
+func (l *Log) Query(f Filter) []Entry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	result := []Entry{}
+	for i := len(l.entries) - 1; i >= 0; i-- {
+		if f.Limit > 0 && len(result) == f.Limit {
+			break
+		}
+		if f.matches(l.entries[i]) {
+			result = append(result, l.entries[i])
+		}
+	}
+	return result
+}
+
+// Close closes the file; later Record calls fail.
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
+
+func (l *Log) append(e Entry) {
+	l.entries = append(l.entries, e)
+	if l.maxEntries > 0 && len(l.entries) > l.maxEntries {
+		l.entries = append([]Entry(nil), l.entries[len(l.entries)-l.maxEntries:]...)
+	}
+}
+
+// load reads the existing file; lines that cannot be decoded (a write cut by a crash) are skipped.
+func (l *Log) load() error {
+	file, err := os.Open(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("open audit log: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, maxLineSize)
+	for scanner.Scan() {
+		l.fileLines++
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		l.append(e)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read audit log: %w", err)
+	}
+	return nil
+}
+
+func (l *Log) open() error {
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, fileMode)
+	if err != nil {
+		return fmt.Errorf("open audit log: %w", err)
+	}
+	l.file = file
+	return nil
+}
+
+// compact rewrites the file with the entries kept in memory, through a temporary file renamed over
+// it. The file must be closed.
+func (l *Log) compact() error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, e := range l.entries {
+		if err := enc.Encode(e); err != nil {
+			return fmt.Errorf("encode audit entry: %w", err)
+		}
+	}
+	tmp := l.path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), fileMode); err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
+	if err := os.Rename(tmp, l.path); err != nil {
+		return fmt.Errorf("replace audit log: %w", err)
+	}
+	l.fileLines = len(l.entries)
+	return nil
+}
+
+// Snapshot maps the names of the entities of a kind to their JSON encoding.
+type Snapshot map[string]json.RawMessage
+
+// NewSnapshot encodes items, named by name.
+func NewSnapshot[T any](items []T, name func(T) string) (Snapshot, error) {
+	s := make(Snapshot, len(items))
+	for _, item := range items {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return nil, fmt.Errorf("encode %s: %w", name(item), err)
+		}
+		s[name(item)] = data
+	}
+	return s, nil
+}
+
+// Snapshots maps kinds to the snapshots of their entities.
+type Snapshots map[string]Snapshot
+
+// DiffAll returns the entries of Diff for every kind of before, sorted by kind.
+func DiffAll(before, after Snapshots) []Entry {
+	kinds := make([]string, 0, len(before))
+	for kind := range before {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	var entries []Entry
+	for _, kind := range kinds {
+		entries = append(entries, Diff(kind, before[kind], after[kind])...)
+	}
+	return entries
+}
+
+// Diff returns an entry of kind for every entity added, removed or changed between before and
+// after, sorted by name, without actor and time. A rename shows up as a delete and a create,
+// which MergeRename turns into a rename entry.
+func Diff(kind string, before, after Snapshot) []Entry {
+	var entries []Entry
+	for name, old := range before {
+		current, ok := after[name]
+		switch {
+		case !ok:
+			entries = append(entries, Entry{Action: ActionDelete, Kind: kind, Name: name, Before: old})
+		case !bytes.Equal(old, current):
+			entries = append(entries, Entry{Action: ActionUpdate, Kind: kind, Name: name, Before: old, After: current,
+				Changes: changedFields(old, current)})
+		}
+	}
+	for name, current := range after {
+		if _, ok := before[name]; !ok {
+			entries = append(entries, Entry{Action: ActionCreate, Kind: kind, Name: name, After: current})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries
+}
+
+// MergeRename replaces, in entries, the delete of the entity of kind named from and the create of
+// its new name with a single rename entry. entries are returned unchanged unless exactly one
+// entity of kind was created.
+func MergeRename(entries []Entry, kind, from string) []Entry {
+	removed, created := -1, -1
+	for i, e := range entries {
+		switch {
+		case e.Kind != kind:
+		case e.Action == ActionDelete && e.Name == from:
+			removed = i
+		case e.Action == ActionCreate:
+			if created >= 0 {
+				return entries
+			}
+			created = i
+		}
+	}
+	if removed < 0 || created < 0 {
+		return entries
+	}
+	renamed := entries[created]
+	renamed.Action = ActionRename
+	renamed.PreviousName = from
+	renamed.Before = entries[removed].Before
+	renamed.Changes = changedFields(renamed.Before, renamed.After)
+	merged := make([]Entry, 0, len(entries)-1)
+	for i, e := range entries {
+		switch i {
+		case removed:
+			merged = append(merged, renamed)
+		case created:
+		default:
+			merged = append(merged, e)
+		}
+	}
+	return merged
+}
+
+// changedFields returns the sorted top-level fields whose encoding differs between the JSON
+// objects before and after.
+func changedFields(before, after json.RawMessage) []string {
+	var old, current map[string]json.RawMessage
+	if json.Unmarshal(before, &old) != nil || json.Unmarshal(after, &current) != nil {
+		return nil
+	}
+	var fields []string
+	for field, value := range old {
+		if other, ok := current[field]; !ok || !bytes.Equal(value, other) {
+			fields = append(fields, field)
+		}
+	}
+	for field := range current {
+		if _, ok := old[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
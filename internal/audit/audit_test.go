@@ -0,0 +1,219 @@
+package audit
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type item struct {
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	Active bool   `json:"active"`
+}
+
+func snapshotOf(t *testing.T, items ...item) Snapshot {
+	t.Helper()
+	s, err := NewSnapshot(items, func(i item) string { return i.Name })
+	if err != nil {
+		t.Fatalf("NewSnapshot: %v", err)
+	}
+	return s
+}
+
+func TestDiff(t *testing.T) {
+	before := snapshotOf(t, item{Name: "a", URL: "http://a"}, item{Name: "b"}, item{Name: "c"})
+	after := snapshotOf(t, item{Name: "a", URL: "http://a2", Active: true}, item{Name: "c"}, item{Name: "d"})
+
+	entries := Diff(KindContainer, before, after)
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %+v", entries)
+	}
+	update, remove, create := entries[0], entries[1], entries[2]
+	if update.Action != ActionUpdate || update.Name != "a" || !reflect.DeepEqual(update.Changes, []string{"active", "url"}) {
+		t.Errorf("unexpected update %+v", update)
+	}
+	if string(update.Before) != `{"name":"a","url":"http://a","active":false}` || update.After == nil {
+		t.Errorf("unexpected update copies %s -> %s", update.Before, update.After)
+	}
+	if remove.Action != ActionDelete || remove.Name != "b" || remove.Before == nil || remove.After != nil {
+		t.Errorf("unexpected delete %+v", remove)
+	}
+	if create.Action != ActionCreate || create.Name != "d" || create.Before != nil || create.After == nil {
+		t.Errorf("unexpected create %+v", create)
+	}
+	for _, e := range entries {
+		if e.Kind != KindContainer {
+			t.Errorf("unexpected kind %q", e.Kind)
+		}
+	}
+}
+
+func TestDiff_NoChanges(t *testing.T) {
+	s := snapshotOf(t, item{Name: "a"})
+	if entries := Diff(KindGroup, s, snapshotOf(t, item{Name: "a"})); len(entries) != 0 {
+		t.Errorf("expected no entries, got %+v", entries)
+	}
+}
+
+func TestDiffAll(t *testing.T) {
+	before := Snapshots{KindGroup: snapshotOf(t, item{Name: "g"}), KindContainer: snapshotOf(t, item{Name: "a"})}
+	after := Snapshots{KindGroup: snapshotOf(t, item{Name: "g", Active: true}), KindContainer: snapshotOf(t)}
+
+	entries := DiffAll(before, after)
+	if len(entries) != 2 || entries[0].Kind != KindContainer || entries[0].Action != ActionDelete ||
+		entries[1].Kind != KindGroup || entries[1].Action != ActionUpdate {
+		t.Errorf("unexpected entries %+v", entries)
+	}
+}
+
+func TestMergeRename(t *testing.T) {
+	before := snapshotOf(t, item{Name: "a", URL: "http://a"}, item{Name: "b"})
+	after := snapshotOf(t, item{Name: "c", URL: "http://a"}, item{Name: "b", Active: true})
+
+	entries := MergeRename(Diff(KindContainer, before, after), KindContainer, "a")
+	if len(entries) != 2 {
+		t.Fatalf("expected the rename and the update, got %+v", entries)
+	}
+	renamed := entries[0]
+	if renamed.Action != ActionRename || renamed.Name != "c" || renamed.PreviousName != "a" ||
+		renamed.Before == nil || renamed.After == nil || !reflect.DeepEqual(renamed.Changes, []string{"name"}) {
+		t.Errorf("unexpected rename %+v", renamed)
+	}
+	if entries[1].Action != ActionUpdate || entries[1].Name != "b" {
+		t.Errorf("unexpected update %+v", entries[1])
+	}
+
+	// Without a single create the entries are left alone
+	removed := Diff(KindContainer, before, snapshotOf(t, item{Name: "b"}))
+	if got := MergeRename(removed, KindContainer, "a"); !reflect.DeepEqual(got, removed) {
+		t.Errorf("expected the entries unchanged, got %+v", got)
+	}
+}
+
+func TestLog_RecordAndQuery(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit", "audit.jsonl")
+	l, err := Open(path, 0)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	base := time.Date(2024, 3, 18, 8, 0, 0, 0, time.UTC)
+	records := []Entry{
+		{Time: base, Actor: "alice", Action: ActionCreate, Kind: KindContainer, Name: "web"},
+		{Time: base.Add(time.Minute), Actor: "bob", Action: ActionStart, Kind: KindContainer, Name: "web"},
+		{Time: base.Add(2 * time.Minute), Actor: "alice", Action: ActionDelete, Kind: KindGroup, Name: "media", Tenant: "team-a"},
+	}
+	for _, e := range records {
+		if err := l.Record(e); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []string
+	}{
+		{"all, newest first", Filter{}, []string{"media", "web", "web"}},
+		{"actor", Filter{Actor: "alice"}, []string{"media", "web"}},
+		{"action and kind", Filter{Action: ActionStart, Kind: KindContainer}, []string{"web"}},
+		{"tenant", Filter{Tenant: "team-a"}, []string{"media"}},
+		{"since and until", Filter{Since: base.Add(time.Minute), Until: base.Add(2 * time.Minute)}, []string{"web"}},
+		{"limit", Filter{Limit: 1}, []string{"media"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []string{}
+			for _, e := range l.Query(tt.filter) {
+				got = append(got, e.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Query(%+v) = %v, want %v", tt.filter, got, tt.want)
+			}
+		})
+	}
+
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := l.Record(Entry{Name: "late"}); err == nil {
+		t.Error("expected an error recording on a closed log")
+	}
+
+	// The entries survive a restart
+	reopened, err := Open(path, 0)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer reopened.Close()
+	if got := reopened.Query(Filter{}); len(got) != 3 || got[0].Name != "media" || !got[0].Time.Equal(records[2].Time) {
+		t.Errorf("unexpected entries after reopening: %+v", got)
+	}
+}
+
+func TestLog_RecordSetsTime(t *testing.T) {
+	l, err := Open(filepath.Join(t.TempDir(), "audit.jsonl"), 0)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer l.Close()
+	now := time.Date(2024, 3, 18, 8, 0, 0, 0, time.UTC)
+	l.now = func() time.Time { return now }
+
+	_ = l.Record(Entry{Name: "web"})
+	if got := l.Query(Filter{}); len(got) != 1 || !got[0].Time.Equal(now) {
+		t.Errorf("expected the entry stamped with the current time, got %+v", got)
+	}
+}
+
+func TestLog_CompactsBeyondMaxEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	l, err := Open(path, 2)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		if err := l.Record(Entry{Name: name}); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+	_ = l.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) >= compactFactor*2 {
+		t.Errorf("expected the file compacted, got %d lines", len(lines))
+	}
+
+	reopened, err := Open(path, 2)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer reopened.Close()
+	if got := reopened.Query(Filter{}); len(got) != 2 || got[0].Name != "e" || got[1].Name != "d" {
+		t.Errorf("expected the last 2 entries, got %+v", got)
+	}
+}
+
+func TestOpen_SkipsCorruptLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	valid, _ := json.Marshal(Entry{Name: "web", Action: ActionStop})
+	if err := os.WriteFile(path, append(valid, []byte("\n{\"name\":\"cut")...), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	l, err := Open(path, 0)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer l.Close()
+	if got := l.Query(Filter{}); len(got) != 1 || got[0].Name != "web" {
+		t.Errorf("expected the valid entry only, got %+v", got)
+	}
+}
@@ -0,0 +1,29 @@
+// Package build exposes build-time metadata injected via -ldflags, e.g.
+//
+//	go build -ldflags "-X github.com/bassista/go_spin/internal/build.Version=1.2.3 \
+//	  -X github.com/bassista/go_spin/internal/build.Commit=$(git rev-parse --short HEAD) \
+//	  -X github.com/bassista/go_spin/internal/build.BuildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+package build
+
+// Values overridden at link time; the defaults identify a local development build.
+var (
+	Version   = "dev"
+	Commit    = "dev"
+	BuildDate = "dev"
+)
+
+// Info groups the build metadata.
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildDate string `json:"buildDate"`
+}
+
+// Current returns the build metadata of the running binary.
+func Current() Info {
+	return Info{
+		Version:   Version,
+		Commit:    Commit,
+		BuildDate: BuildDate,
+	}
+}
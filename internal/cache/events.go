@@ -0,0 +1,78 @@
+package cache
+
+import "sync"
+
+// Kinds of change published by the Store.
+const (
+	ChangeContainer = "container"
+	ChangeGroup     = "group"
+	ChangeSchedule  = "schedule"
+	ChangeReplace   = "replace"
+	ChangeBulk      = "bulk"
+)
+
+// changeBufferSize bounds each subscriber queue; events are dropped when a subscriber lags,
+// which is acceptable because subscribers only need a "something changed" signal.
+const changeBufferSize = 16
+
+// ChangeEvent describes a mutation applied to the cache.
+type ChangeEvent struct {
+	Kind string // one of the Change* constants
+	Name string // container/group name or schedule id, empty for ChangeReplace and ChangeBulk
+}
+
+// ChangeNotifier is implemented by stores that publish change events.
+type ChangeNotifier interface {
+	// Subscribe returns a channel receiving change events and a function that cancels the subscription.
+	Subscribe() (<-chan ChangeEvent, func())
+}
+
+// changeBroker is a minimal in-memory fan-out of change events to subscribers.
+type changeBroker struct {
+	mu        sync.Mutex
+	nextID    int
+	subs      map[int]chan ChangeEvent
+	published uint64 // events published so far
+}
+
+func (b *changeBroker) subscribe() (<-chan ChangeEvent, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.subs == nil {
+		b.subs = map[int]chan ChangeEvent{}
+	}
+	id := b.nextID
+	b.nextID++
+	ch := make(chan ChangeEvent, changeBufferSize)
+	b.subs[id] = ch
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			delete(b.subs, id)
+			close(ch)
+		})
+	}
+}
+
+// publish delivers the event to every subscriber without blocking.
+func (b *changeBroker) publish(ev ChangeEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.published++
+	for _, ch := range b.subs {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
+
+// count returns the number of events published so far.
+func (b *changeBroker) count() uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.published
+}
@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"sync"
+
+	"github.com/bassista/go_spin/internal/logger"
+	"github.com/bassista/go_spin/internal/repository"
+)
+
+// ContainerIndex looks containers up by name across several stores, e.g. the main data document
+// and the tenant ones. It keeps a map built from their snapshots and builds it again only when a
+// store version changed, so a lookup does not copy the documents. When a name is in several
+// stores the first one wins. Runtime observations (RunningSince, LastError) may be stale: the
+// index serves the configuration of the containers.
+type ContainerIndex struct {
+	mu       sync.Mutex
+	stores   []VersionedStore
+	versions []uint64
+	byName   map[string]repository.Container
+}
+
+// NewContainerIndex creates an index of the containers of stores.
+func NewContainerIndex(stores ...VersionedStore) *ContainerIndex {
+	x := &ContainerIndex{}
+	x.SetStores(stores...)
+	return x
+}
+
+// SetStores replaces the indexed stores, e.g. once the tenants are loaded.
+func (x *ContainerIndex) SetStores(stores ...VersionedStore) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.stores = stores
+	x.versions = nil
+	x.byName = nil
+}
+
+// Container returns the container named name, and false when no store holds it.
+func (x *ContainerIndex) Container(name string) (repository.Container, bool) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if x.stale() {
+		x.rebuild()
+	}
+	c, ok := x.byName[name]
+	return c, ok
+}
+
+// stale reports whether a store changed since the map was built. The caller must hold x.mu.
+func (x *ContainerIndex) stale() bool {
+	if x.byName == nil {
+		return true
+	}
+	for i, store := range x.stores {
+		if store.Version() != x.versions[i] {
+			return true
+		}
+	}
+	return false
+}
+
+// rebuild builds the map from the snapshots of the stores. When a store cannot be read the previous
+// map is kept and the next lookup tries again. The caller must hold x.mu.
+func (x *ContainerIndex) rebuild() {
+	versions := make([]uint64, len(x.stores))
+	byName := make(map[string]repository.Container)
+	for i, store := range x.stores {
+		// Read the version first: a change racing with the snapshot only causes another rebuild
+		versions[i] = store.Version()
+		doc, err := store.Snapshot()
+		if err != nil {
+			logger.WithComponent("cache").Warnf("cannot index containers: %v", err)
+			return
+		}
+		for _, c := range doc.Containers {
+			if _, ok := byName[c.Name]; !ok {
+				byName[c.Name] = c
+			}
+		}
+	}
+	x.byName = byName
+	x.versions = versions
+}
@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/bassista/go_spin/internal/repository"
+)
+
+// countingStore counts the snapshots taken from the wrapped store.
+type countingStore struct {
+	*Store
+	snapshots int
+}
+
+func (s *countingStore) Snapshot() (repository.DataDocument, error) {
+	s.snapshots++
+	return s.Store.Snapshot()
+}
+
+func TestContainerIndex_Container(t *testing.T) {
+	primary := &countingStore{Store: NewStore(repository.DataDocument{Containers: []repository.Container{
+		{Name: "web", Host: "main"},
+	}})}
+	tenant := NewStore(repository.DataDocument{Containers: []repository.Container{
+		{Name: "web", Host: "tenant"},
+		{Name: "db", Host: "nas"},
+	}})
+	index := NewContainerIndex(primary, tenant)
+
+	if c, ok := index.Container("web"); !ok || c.Host != "main" {
+		t.Errorf("expected the main document to win, got %+v (%v)", c, ok)
+	}
+	if c, ok := index.Container("db"); !ok || c.Host != "nas" {
+		t.Errorf("expected the tenant container, got %+v (%v)", c, ok)
+	}
+	if _, ok := index.Container("missing"); ok {
+		t.Error("expected an unknown container not to be found")
+	}
+	if primary.snapshots != 1 {
+		t.Errorf("expected one snapshot for unchanged stores, got %d", primary.snapshots)
+	}
+
+	if err := primary.MarkRunning("web", 1); err != nil {
+		t.Fatalf("failed to mark running: %v", err)
+	}
+	index.Container("web")
+	if primary.snapshots != 1 {
+		t.Errorf("expected runtime observations not to rebuild the index, got %d snapshots", primary.snapshots)
+	}
+
+	if _, err := tenant.AddContainer(repository.Container{Name: "cache", Host: "nas"}); err != nil {
+		t.Fatalf("failed to add container: %v", err)
+	}
+	if c, ok := index.Container("cache"); !ok || c.Host != "nas" {
+		t.Errorf("expected the added container, got %+v (%v)", c, ok)
+	}
+	if primary.snapshots != 2 {
+		t.Errorf("expected a change to rebuild the index once, got %d snapshots", primary.snapshots)
+	}
+}
+
+func TestContainerIndex_SetStores(t *testing.T) {
+	index := NewContainerIndex(NewStore(repository.DataDocument{}))
+	if _, ok := index.Container("web"); ok {
+		t.Fatal("expected an empty index")
+	}
+	index.SetStores(NewStore(repository.DataDocument{Containers: []repository.Container{{Name: "web"}}}))
+	if _, ok := index.Container("web"); !ok {
+		t.Error("expected the container of the new store")
+	}
+}
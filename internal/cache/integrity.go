@@ -0,0 +1,121 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bassista/go_spin/internal/repository"
+)
+
+// ErrDanglingReference is wrapped by the ReferenceError of a group or schedule referencing a
+// missing container or group.
+var ErrDanglingReference = errors.New("dangling reference")
+
+// ReferenceError lists the missing containers or groups referenced by a group or schedule.
+type ReferenceError struct {
+	Kind    string // "container" or "group"
+	Missing []string
+}
+
+func (e *ReferenceError) Error() string {
+	return fmt.Sprintf("unknown %s: %s", e.Kind, strings.Join(e.Missing, ", "))
+}
+
+func (e *ReferenceError) Unwrap() error {
+	return ErrDanglingReference
+}
+
+// IntegrityReport lists the references of a data document to missing containers or groups.
+type IntegrityReport struct {
+	Schedules    map[string]string   `json:"schedules"`    // schedule id -> missing target
+	GroupMembers map[string][]string `json:"groupMembers"` // group name -> missing containers
+	DependsOn    map[string][]string `json:"dependsOn"`    // container name -> missing dependencies
+	Order        []string            `json:"order"`        // order entries without a container
+	GroupOrder   []string            `json:"groupOrder"`   // groupOrder entries without a group
+}
+
+// IsEmpty reports whether the document has no dangling reference.
+func (r IntegrityReport) IsEmpty() bool {
+	return len(r.Schedules) == 0 && len(r.GroupMembers) == 0 && len(r.DependsOn) == 0 &&
+		len(r.Order) == 0 && len(r.GroupOrder) == 0
+}
+
+// CheckIntegrity reports the dangling references of doc without changing it. PruneOrphans removes
+// those of schedules and groups.
+func CheckIntegrity(doc repository.DataDocument) IntegrityReport {
+	report := IntegrityReport{
+		Schedules:    map[string]string{},
+		GroupMembers: map[string][]string{},
+		DependsOn:    map[string][]string{},
+		Order:        []string{},
+		GroupOrder:   []string{},
+	}
+	containers, groups := referenceSets(doc)
+
+	for _, s := range doc.Schedules {
+		if scheduleReferences(s, containers, groups) != nil {
+			report.Schedules[s.ID] = s.Target
+		}
+	}
+	for _, g := range doc.Groups {
+		if missing := missingNames(g.Container, containers); len(missing) > 0 {
+			report.GroupMembers[g.Name] = missing
+		}
+	}
+	for _, c := range doc.Containers {
+		if missing := missingNames(c.DependsOn, containers); len(missing) > 0 {
+			report.DependsOn[c.Name] = missing
+		}
+	}
+	report.Order = append(report.Order, missingNames(doc.Order, containers)...)
+	report.GroupOrder = append(report.GroupOrder, missingNames(doc.GroupOrder, groups)...)
+	return report
+}
+
+// groupReferences returns a ReferenceError when group lists containers missing from doc.
+func groupReferences(doc repository.DataDocument, group repository.Group) error {
+	containers, _ := referenceSets(doc)
+	if missing := missingNames(group.Container, containers); len(missing) > 0 {
+		return &ReferenceError{Kind: "container", Missing: missing}
+	}
+	return nil
+}
+
+// scheduleReferences returns a ReferenceError when the target of s is missing.
+func scheduleReferences(s repository.Schedule, containers, groups map[string]bool) error {
+	switch s.TargetType {
+	case "container":
+		if !containers[s.Target] {
+			return &ReferenceError{Kind: "container", Missing: []string{s.Target}}
+		}
+	case "group":
+		if !groups[s.Target] {
+			return &ReferenceError{Kind: "group", Missing: []string{s.Target}}
+		}
+	}
+	return nil
+}
+
+func referenceSets(doc repository.DataDocument) (containers, groups map[string]bool) {
+	containers = make(map[string]bool, len(doc.Containers))
+	for _, c := range doc.Containers {
+		containers[c.Name] = true
+	}
+	groups = make(map[string]bool, len(doc.Groups))
+	for _, g := range doc.Groups {
+		groups[g.Name] = true
+	}
+	return containers, groups
+}
+
+// missingNames returns the names not in known, in order.
+func missingNames(names []string, known map[string]bool) []string {
+	var missing []string
+	for _, name := range names {
+		if !known[name] {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
@@ -7,6 +7,12 @@ type ReadOnlyStore interface {
 	Snapshot() (repository.DataDocument, error)
 }
 
+// VersionedStore is a store telling whether its configuration changed (see Store.Version).
+type VersionedStore interface {
+	ReadOnlyStore
+	Version() uint64
+}
+
 // ContainerStore is the cache API needed by container handlers.
 type ContainerStore interface {
 	ReadOnlyStore
@@ -28,6 +34,29 @@ type ScheduleStore interface {
 	RemoveSchedule(id string) (repository.DataDocument, error)
 }
 
+// TransactionalStore applies several mutations atomically.
+// It is optional: bulk handlers type-assert it and fail when the store does not implement it.
+type TransactionalStore interface {
+	// Apply runs fn on a copy of the document and commits it only if fn returns nil.
+	Apply(fn func(doc *repository.DataDocument) error) (repository.DataDocument, error)
+}
+
+// UncheckedStore upserts groups and schedules without checking that the containers and groups
+// they reference exist (?force=true). It is optional: handlers type-assert it and fall back to
+// the checked methods when the store does not implement it.
+type UncheckedStore interface {
+	AddGroupUnchecked(group repository.Group) (repository.DataDocument, error)
+	AddScheduleUnchecked(schedule repository.Schedule) (repository.DataDocument, error)
+}
+
+// RuntimeStateStore records runtime observations on stored containers.
+// It is optional: consumers type-assert it and skip recording when the store does not implement it.
+type RuntimeStateStore interface {
+	MarkRunning(name string, since int64) error
+	MarkStopped(name string) error
+	SetLastError(name, lastError string) error
+}
+
 // PersistableStore is the cache API needed by the persistence scheduler.
 type PersistableStore interface {
 	IsDirty() bool
@@ -44,4 +73,5 @@ type AppStore interface {
 	GroupStore
 	ScheduleStore
 	PersistableStore
+	VersionedStore
 }
@@ -0,0 +1,257 @@
+package cache
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/bassista/go_spin/internal/repository"
+)
+
+// Import modes of MergeDocument.
+const (
+	ImportModeMerge   = "merge"   // imported entities are added to the current ones
+	ImportModeReplace = "replace" // the imported document replaces the current one
+)
+
+// Default schedule outcomes reported by MergeDocument.
+const (
+	defaultScheduleAdded    = "added"
+	defaultScheduleUpdated  = "updated"
+	defaultScheduleConflict = "conflict"
+	defaultScheduleRemoved  = "removed"
+)
+
+// EntityNames lists containers, groups (by name) and schedules (by id).
+type EntityNames struct {
+	Containers []string `json:"containers"`
+	Groups     []string `json:"groups"`
+	Schedules  []string `json:"schedules"`
+}
+
+// IsEmpty reports whether no entity is listed.
+func (n EntityNames) IsEmpty() bool {
+	return len(n.Containers) == 0 && len(n.Groups) == 0 && len(n.Schedules) == 0
+}
+
+func newEntityNames() EntityNames {
+	return EntityNames{Containers: []string{}, Groups: []string{}, Schedules: []string{}}
+}
+
+// ImportReport lists what MergeDocument changed (or would change).
+type ImportReport struct {
+	Added   EntityNames `json:"added"`
+	Updated EntityNames `json:"updated"` // entities whose definition was replaced by the imported one
+	// Conflicts are entities defined differently by the current and the imported document and
+	// left untouched: merge mode without overwrite.
+	Conflicts EntityNames `json:"conflicts"`
+	Removed   EntityNames `json:"removed"` // replace mode: entities missing from the imported document
+	// DefaultSchedule is "added", "updated", "conflict" or "removed", empty when unchanged.
+	DefaultSchedule string `json:"defaultSchedule,omitempty"`
+}
+
+// HasConflicts reports whether some imported definitions were not applied.
+func (r ImportReport) HasConflicts() bool {
+	return !r.Conflicts.IsEmpty() || r.DefaultSchedule == defaultScheduleConflict
+}
+
+// IsEmpty reports whether the import changes nothing.
+func (r ImportReport) IsEmpty() bool {
+	return r.Added.IsEmpty() && r.Updated.IsEmpty() && r.Removed.IsEmpty() &&
+		(r.DefaultSchedule == "" || r.DefaultSchedule == defaultScheduleConflict)
+}
+
+// MergeDocument applies imported to doc in place according to mode (ImportModeMerge or
+// ImportModeReplace). In merge mode an entity defined differently by both documents is a conflict:
+// it is replaced when overwrite is set, kept and reported otherwise. Definitions are compared
+// without the runtime observations (running, runningSince, lastError), which doc keeps.
+// Container friendly names are lowercased as on every container update.
+func MergeDocument(doc *repository.DataDocument, imported repository.DataDocument, mode string, overwrite bool) ImportReport {
+	replace := mode == ImportModeReplace
+	if replace {
+		overwrite = true
+	}
+	report := ImportReport{Added: newEntityNames(), Updated: newEntityNames(), Conflicts: newEntityNames(), Removed: newEntityNames()}
+
+	// Containers
+	current := make(map[string]int, len(doc.Containers))
+	for i, c := range doc.Containers {
+		current[c.Name] = i
+	}
+	containers := make([]repository.Container, 0, len(imported.Containers))
+	seen := make(map[string]bool, len(imported.Containers))
+	for _, c := range imported.Containers {
+		c = ContainerDefinition(c)
+		c.FriendlyName = strings.ToLower(c.FriendlyName)
+		seen[c.Name] = true
+		i, exists := current[c.Name]
+		switch {
+		case !exists:
+			report.Added.Containers = append(report.Added.Containers, c.Name)
+		case reflect.DeepEqual(ContainerDefinition(doc.Containers[i]), c):
+			c = doc.Containers[i]
+		case overwrite:
+			report.Updated.Containers = append(report.Updated.Containers, c.Name)
+			c.Running, c.RunningSince, c.LastError = doc.Containers[i].Running, doc.Containers[i].RunningSince, doc.Containers[i].LastError
+		default:
+			report.Conflicts.Containers = append(report.Conflicts.Containers, c.Name)
+			c = doc.Containers[i]
+		}
+		containers = append(containers, c)
+	}
+	kept := make([]repository.Container, 0, len(doc.Containers))
+	for _, c := range doc.Containers {
+		if seen[c.Name] {
+			continue
+		}
+		if replace {
+			report.Removed.Containers = append(report.Removed.Containers, c.Name)
+			continue
+		}
+		kept = append(kept, c)
+	}
+	doc.Containers = append(kept, containers...)
+
+	// Groups
+	currentGroups := make(map[string]repository.Group, len(doc.Groups))
+	for _, g := range doc.Groups {
+		currentGroups[g.Name] = g
+	}
+	groups := make([]repository.Group, 0, len(imported.Groups))
+	seen = make(map[string]bool, len(imported.Groups))
+	for _, g := range imported.Groups {
+		seen[g.Name] = true
+		existing, exists := currentGroups[g.Name]
+		switch {
+		case !exists:
+			report.Added.Groups = append(report.Added.Groups, g.Name)
+		case reflect.DeepEqual(existing, g):
+		case overwrite:
+			report.Updated.Groups = append(report.Updated.Groups, g.Name)
+		default:
+			report.Conflicts.Groups = append(report.Conflicts.Groups, g.Name)
+			g = existing
+		}
+		groups = append(groups, g)
+	}
+	keptGroups := make([]repository.Group, 0, len(doc.Groups))
+	for _, g := range doc.Groups {
+		if seen[g.Name] {
+			continue
+		}
+		if replace {
+			report.Removed.Groups = append(report.Removed.Groups, g.Name)
+			continue
+		}
+		keptGroups = append(keptGroups, g)
+	}
+	doc.Groups = append(keptGroups, groups...)
+
+	// Schedules
+	currentSchedules := make(map[string]repository.Schedule, len(doc.Schedules))
+	for _, s := range doc.Schedules {
+		currentSchedules[s.ID] = s
+	}
+	schedules := make([]repository.Schedule, 0, len(imported.Schedules))
+	seen = make(map[string]bool, len(imported.Schedules))
+	for _, s := range imported.Schedules {
+		seen[s.ID] = true
+		existing, exists := currentSchedules[s.ID]
+		switch {
+		case !exists:
+			report.Added.Schedules = append(report.Added.Schedules, s.ID)
+		case reflect.DeepEqual(existing, s):
+		case overwrite:
+			report.Updated.Schedules = append(report.Updated.Schedules, s.ID)
+		default:
+			report.Conflicts.Schedules = append(report.Conflicts.Schedules, s.ID)
+			s = existing
+		}
+		schedules = append(schedules, s)
+	}
+	keptSchedules := make([]repository.Schedule, 0, len(doc.Schedules))
+	for _, s := range doc.Schedules {
+		if seen[s.ID] {
+			continue
+		}
+		if replace {
+			report.Removed.Schedules = append(report.Removed.Schedules, s.ID)
+			continue
+		}
+		keptSchedules = append(keptSchedules, s)
+	}
+	doc.Schedules = append(keptSchedules, schedules...)
+
+	// Default schedule
+	switch {
+	case imported.DefaultSchedule == nil && doc.DefaultSchedule != nil && replace:
+		report.DefaultSchedule = defaultScheduleRemoved
+		doc.DefaultSchedule = nil
+	case imported.DefaultSchedule == nil:
+	case doc.DefaultSchedule == nil:
+		report.DefaultSchedule = defaultScheduleAdded
+		doc.DefaultSchedule = imported.DefaultSchedule
+	case reflect.DeepEqual(doc.DefaultSchedule, imported.DefaultSchedule):
+	case overwrite:
+		report.DefaultSchedule = defaultScheduleUpdated
+		doc.DefaultSchedule = imported.DefaultSchedule
+	default:
+		report.DefaultSchedule = defaultScheduleConflict
+	}
+
+	// Display orders: the imported one wins on replace, new entities are appended otherwise
+	containerOrder, groupOrder := doc.Order, doc.GroupOrder
+	if replace {
+		containerOrder, groupOrder = imported.Order, imported.GroupOrder
+	}
+	doc.Order = reconcileOrder(containerOrder, containerNames(doc.Containers))
+	doc.GroupOrder = reconcileOrder(groupOrder, groupNames(doc.Groups))
+
+	return report
+}
+
+// ContainerDefinition returns c without its runtime observations (running, runningSince,
+// lastError), i.e. what a user defines and an export carries.
+func ContainerDefinition(c repository.Container) repository.Container {
+	c.Running = nil
+	c.RunningSince = nil
+	c.LastError = ""
+	return c
+}
+
+// reconcileOrder keeps the names of order that exist in names, then appends the missing ones.
+func reconcileOrder(order, names []string) []string {
+	exists := make(map[string]bool, len(names))
+	for _, name := range names {
+		exists[name] = true
+	}
+	out := make([]string, 0, len(names))
+	listed := make(map[string]bool, len(names))
+	for _, name := range order {
+		if exists[name] && !listed[name] {
+			out = append(out, name)
+			listed[name] = true
+		}
+	}
+	for _, name := range names {
+		if !listed[name] {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+func containerNames(containers []repository.Container) []string {
+	names := make([]string, len(containers))
+	for i, c := range containers {
+		names[i] = c.Name
+	}
+	return names
+}
+
+func groupNames(groups []repository.Group) []string {
+	names := make([]string, len(groups))
+	for i, g := range groups {
+		names[i] = g.Name
+	}
+	return names
+}
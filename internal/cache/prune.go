@@ -0,0 +1,61 @@
+package cache
+
+import "github.com/bassista/go_spin/internal/repository"
+
+// PruneReport lists what PruneOrphans removed (or would remove).
+type PruneReport struct {
+	Schedules    []string            `json:"schedules"`    // ids of schedules whose target no longer exists
+	GroupMembers map[string][]string `json:"groupMembers"` // group name -> missing container names scrubbed from it
+}
+
+// IsEmpty reports whether nothing was pruned.
+func (r PruneReport) IsEmpty() bool {
+	return len(r.Schedules) == 0 && len(r.GroupMembers) == 0
+}
+
+// PruneOrphans removes schedules targeting missing containers or groups and scrubs
+// missing container names from groups, modifying doc in place.
+func PruneOrphans(doc *repository.DataDocument) PruneReport {
+	report := PruneReport{Schedules: []string{}, GroupMembers: map[string][]string{}}
+
+	containers := make(map[string]bool, len(doc.Containers))
+	for _, c := range doc.Containers {
+		containers[c.Name] = true
+	}
+	groups := make(map[string]bool, len(doc.Groups))
+	for _, g := range doc.Groups {
+		groups[g.Name] = true
+	}
+
+	for gi := range doc.Groups {
+		g := &doc.Groups[gi]
+		kept := make([]string, 0, len(g.Container))
+		for _, name := range g.Container {
+			if !containers[name] {
+				report.GroupMembers[g.Name] = append(report.GroupMembers[g.Name], name)
+				continue
+			}
+			kept = append(kept, name)
+		}
+		g.Container = kept
+	}
+
+	kept := make([]repository.Schedule, 0, len(doc.Schedules))
+	for _, sch := range doc.Schedules {
+		var exists bool
+		switch sch.TargetType {
+		case "container":
+			exists = containers[sch.Target]
+		case "group":
+			exists = groups[sch.Target]
+		}
+		if !exists {
+			report.Schedules = append(report.Schedules, sch.ID)
+			continue
+		}
+		kept = append(kept, sch)
+	}
+	doc.Schedules = kept
+
+	return report
+}
@@ -0,0 +1,67 @@
+package cache
+
+import "github.com/bassista/go_spin/internal/repository"
+
+// RenameReport lists the references RenameContainer updated.
+type RenameReport struct {
+	Groups     []string `json:"groups"`     // groups listing the container
+	Schedules  []string `json:"schedules"`  // ids of the schedules targeting the container
+	Dependents []string `json:"dependents"` // containers depending on it
+}
+
+// RenameContainer renames container oldName to newName in doc, in place, together with every
+// reference to it: group members, the display order, schedule targets and the dependsOn lists of
+// other containers. It fails with ErrContainerNotFound when oldName does not exist and with
+// ErrContainerExists when newName already does.
+func RenameContainer(doc *repository.DataDocument, oldName, newName string) (RenameReport, error) {
+	report := RenameReport{Groups: []string{}, Schedules: []string{}, Dependents: []string{}}
+
+	idx := -1
+	for i, c := range doc.Containers {
+		switch c.Name {
+		case oldName:
+			idx = i
+		case newName:
+			return report, ErrContainerExists
+		}
+	}
+	if idx < 0 {
+		return report, ErrContainerNotFound
+	}
+	if oldName == newName {
+		return report, nil
+	}
+	doc.Containers[idx].Name = newName
+
+	for i := range doc.Containers {
+		if renameIn(doc.Containers[i].DependsOn, oldName, newName) {
+			report.Dependents = append(report.Dependents, doc.Containers[i].Name)
+		}
+	}
+	renameIn(doc.Order, oldName, newName)
+	for i := range doc.Groups {
+		if renameIn(doc.Groups[i].Container, oldName, newName) {
+			report.Groups = append(report.Groups, doc.Groups[i].Name)
+		}
+	}
+	for i := range doc.Schedules {
+		s := &doc.Schedules[i]
+		if s.TargetType == "container" && s.Target == oldName {
+			s.Target = newName
+			report.Schedules = append(report.Schedules, s.ID)
+		}
+	}
+	return report, nil
+}
+
+// renameIn replaces oldName with newName in names, reporting whether it was listed.
+func renameIn(names []string, oldName, newName string) bool {
+	found := false
+	for i, name := range names {
+		if name == oldName {
+			names[i] = newName
+			found = true
+		}
+	}
+	return found
+}
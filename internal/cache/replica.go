@@ -0,0 +1,90 @@
+package cache
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/bassista/go_spin/internal/logger"
+	"github.com/bassista/go_spin/internal/repository"
+)
+
+// ReplicatedStore is an eventually-consistent, read-only copy of a primary Store.
+// It is refreshed from the primary's change events (and periodically, to pick up runtime
+// observations that do not publish events), so hot read endpoints can take snapshots
+// without contending with writers on the primary's lock. Writes must go to the primary.
+//
+// Read-modify-write flows must keep reading from the primary: the replica may lag behind.
+type ReplicatedStore struct {
+	primary *Store
+	current atomic.Pointer[repository.DataDocument]
+	resync  time.Duration
+}
+
+// NewReplicatedStore creates a replica of primary and keeps it updated until ctx is done.
+// resync <= 0 disables the periodic refresh.
+func NewReplicatedStore(ctx context.Context, primary *Store, resync time.Duration) (*ReplicatedStore, error) {
+	r := &ReplicatedStore{primary: primary, resync: resync}
+	if err := r.refresh(); err != nil {
+		return nil, err
+	}
+	events, unsubscribe := primary.Subscribe()
+	go r.run(ctx, events, unsubscribe)
+	return r, nil
+}
+
+// Snapshot returns a deep copy of the replicated data without touching the primary's lock.
+func (r *ReplicatedStore) Snapshot() (repository.DataDocument, error) {
+	return cloneData(*r.current.Load())
+}
+
+func (r *ReplicatedStore) run(ctx context.Context, events <-chan ChangeEvent, unsubscribe func()) {
+	defer unsubscribe()
+
+	var resync <-chan time.Time
+	if r.resync > 0 {
+		ticker := time.NewTicker(r.resync)
+		defer ticker.Stop()
+		resync = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.WithComponent("replica").Debugf("read replica stopped")
+			return
+		case _, ok := <-events:
+			if !ok {
+				return
+			}
+			// Collapse queued events: one snapshot covers all of them.
+			drainEvents(events)
+		case <-resync:
+		}
+		if err := r.refresh(); err != nil {
+			logger.WithComponent("replica").Errorf("failed to refresh read replica: %v", err)
+		}
+	}
+}
+
+func (r *ReplicatedStore) refresh() error {
+	doc, err := r.primary.Snapshot()
+	if err != nil {
+		return err
+	}
+	r.current.Store(&doc)
+	return nil
+}
+
+func drainEvents(events <-chan ChangeEvent) {
+	for {
+		select {
+		case _, ok := <-events:
+			if !ok {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bassista/go_spin/internal/repository"
+)
+
+func waitForReplica(t *testing.T, replica *ReplicatedStore, cond func(repository.DataDocument) bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		doc, err := replica.Snapshot()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cond(doc) {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("replica did not converge")
+}
+
+func TestReplicatedStore_ConvergesAfterWrite(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	primary := NewStore(createTestDocument())
+	replica, err := NewReplicatedStore(ctx, primary, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	active := true
+	if _, err := primary.AddContainer(repository.Container{Name: "new", FriendlyName: "new", URL: "http://new", Active: &active}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	waitForReplica(t, replica, func(doc repository.DataDocument) bool {
+		for _, c := range doc.Containers {
+			if c.Name == "new" {
+				return true
+			}
+		}
+		return false
+	})
+
+	if _, err := primary.RemoveContainer("new"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	waitForReplica(t, replica, func(doc repository.DataDocument) bool {
+		for _, c := range doc.Containers {
+			if c.Name == "new" {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+func TestReplicatedStore_ResyncPicksUpUnpublishedChanges(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	primary := NewStore(createTestDocument())
+	replica, err := NewReplicatedStore(ctx, primary, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Runtime observations do not publish change events.
+	if err := primary.MarkRunning("container1", 1234); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	waitForReplica(t, replica, func(doc repository.DataDocument) bool {
+		return doc.Containers[0].RunningSince != nil
+	})
+}
+
+func TestReplicatedStore_ReadsDoNotContendWithWriters(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	primary := NewStore(createTestDocument())
+	replica, err := NewReplicatedStore(ctx, primary, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Simulate a long-running writer holding the primary's lock.
+	primary.mu.Lock()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if _, err := replica.Snapshot(); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("replica read blocked on the primary's write lock")
+	}
+	primary.mu.Unlock()
+
+	// Concurrent replica reads must not delay writes on the primary.
+	stop := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		go func() {
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					_, _ = replica.Snapshot()
+				}
+			}
+		}()
+	}
+	defer close(stop)
+
+	writeDone := make(chan struct{})
+	go func() {
+		defer close(writeDone)
+		for i := 0; i < 50; i++ {
+			primary.MarkDirty()
+		}
+	}()
+	select {
+	case <-writeDone:
+	case <-time.After(time.Second):
+		t.Error("writes blocked by replica reads")
+	}
+}
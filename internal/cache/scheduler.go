@@ -8,6 +8,31 @@ import (
 	"github.com/bassista/go_spin/internal/repository"
 )
 
+// PersistOption configures optional behavior of the persistence scheduler.
+type PersistOption func(*persistOptions)
+
+type persistOptions struct {
+	writeThrough bool
+	onPersist    func(lastUpdate int64)
+}
+
+// WithWriteThrough makes the scheduler flush right after every mutation published by the
+// store, instead of waiting for the next tick. The periodic flush keeps running as a safety net.
+// Runtime observations (MarkRunning/MarkStopped) publish no events and stay buffered.
+func WithWriteThrough(enabled bool) PersistOption {
+	return func(o *persistOptions) {
+		o.writeThrough = enabled
+	}
+}
+
+// WithOnPersist calls fn with the new metadata.lastUpdate after every successful flush to disk,
+// so the caller can notify others (e.g. publish a config persisted event). fn must not block.
+func WithOnPersist(fn func(lastUpdate int64)) PersistOption {
+	return func(o *persistOptions) {
+		o.onPersist = fn
+	}
+}
+
 // StartPersistenceScheduler runs a goroutine that periodically flushes dirty cache to disk.
 // On ctx.Done, it performs a final flush before returning.
 // Returns a channel that is closed when the scheduler has completed shutdown.
@@ -16,25 +41,53 @@ func StartPersistenceScheduler(
 	store PersistableStore,
 	repo repository.Saver,
 	interval time.Duration,
+	opts ...PersistOption,
 ) <-chan struct{} {
+	var options persistOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	done := make(chan struct{})
 	logger.WithComponent("persist").Debugf("starting persistence scheduler with interval: %v", interval)
 	ticker := time.NewTicker(interval)
+
+	// A nil channel never fires, so buffered mode simply ignores this select case.
+	var changes <-chan ChangeEvent
+	unsubscribe := func() {}
+	if options.writeThrough {
+		if notifier, ok := store.(ChangeNotifier); ok {
+			// Subscribe before starting the goroutine so no mutation after this call is missed.
+			changes, unsubscribe = notifier.Subscribe()
+			logger.WithComponent("persist").Debugf("write-through enabled")
+		} else {
+			logger.WithComponent("persist").Warnf("write-through requires a store publishing change events, using periodic flush only")
+		}
+	}
+
 	go func() {
 		defer close(done)
 		defer ticker.Stop()
+		defer unsubscribe()
 		logger.WithComponent("persist").Debugf("persistence scheduler running")
 		for {
 			select {
 			case <-ctx.Done():
 				logger.WithComponent("persist").Debugf("persistence scheduler received context cancellation, performing final flush")
 				// Final flush on shutdown - use background context to ensure it completes
-				flushCache(context.Background(), store, repo)
+				flushCache(context.Background(), store, repo, options.onPersist)
 				logger.WithComponent("persist").Info("persistence scheduler stopped after final flush")
 				return
 			case <-ticker.C:
 				logger.WithComponent("persist").Tracef("persistence scheduler tick, checking if dirty")
-				flushCache(ctx, store, repo)
+				flushCache(ctx, store, repo, options.onPersist)
+			case ev, ok := <-changes:
+				if !ok {
+					changes = nil
+					continue
+				}
+				logger.WithComponent("persist").Tracef("write-through flush after %s change %q", ev.Kind, ev.Name)
+				flushCache(ctx, store, repo, options.onPersist)
 			}
 		}
 	}()
@@ -42,8 +95,9 @@ func StartPersistenceScheduler(
 }
 
 // flushCache persists the cache to disk if dirty, using optimistic locking.
-// It respects context cancellation to allow graceful shutdown.
-func flushCache(ctx context.Context, store PersistableStore, repo repository.Saver) {
+// It respects context cancellation to allow graceful shutdown. onPersist, when not nil, is
+// called after a successful save.
+func flushCache(ctx context.Context, store PersistableStore, repo repository.Saver, onPersist func(lastUpdate int64)) {
 	if !store.IsDirty() {
 		logger.WithComponent("persist").Tracef("cache is clean, skipping flush")
 		return
@@ -73,4 +127,7 @@ func flushCache(ctx context.Context, store PersistableStore, repo repository.Sav
 	store.ClearDirty()
 	store.SetLastUpdate(snapshot.Metadata.LastUpdate)
 	logger.WithComponent("persist").Info("cache persisted to disk")
+	if onPersist != nil {
+		onPersist(snapshot.Metadata.LastUpdate)
+	}
 }
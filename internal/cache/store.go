@@ -5,6 +5,7 @@ import (
 	"errors"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/bassista/go_spin/internal/logger"
 	"github.com/bassista/go_spin/internal/repository"
@@ -13,6 +14,7 @@ import (
 var ErrContainerNotFound = errors.New("container not found")
 var ErrGroupNotFound = errors.New("group not found")
 var ErrScheduleNotFound = errors.New("schedule not found")
+var ErrContainerExists = errors.New("container already exists")
 
 // Store keeps an in-memory copy of the data document.
 type Store struct {
@@ -20,6 +22,7 @@ type Store struct {
 	data       repository.DataDocument
 	dirty      bool  // true if cache changed since last persist
 	lastUpdate int64 // cache's metadata.lastUpdate
+	changes    changeBroker
 }
 
 // NewStore creates an empty cache store.
@@ -62,6 +65,19 @@ func (s *Store) SetLastUpdate(ts int64) {
 	s.lastUpdate = ts
 }
 
+// Subscribe registers a listener for cache mutations.
+// The returned function must be called to release the subscription.
+func (s *Store) Subscribe() (<-chan ChangeEvent, func()) {
+	return s.changes.subscribe()
+}
+
+// Version returns a counter increased by every change event, so readers can tell whether the
+// configuration changed since they last read it. Runtime observations (MarkRunning, MarkStopped,
+// SetLastError) do not change it.
+func (s *Store) Version() uint64 {
+	return s.changes.count()
+}
+
 // Snapshot returns a deep copy of the cached data.
 func (s *Store) Snapshot() (repository.DataDocument, error) {
 	s.mu.RLock()
@@ -80,11 +96,34 @@ func (s *Store) Replace(doc repository.DataDocument) error {
 	s.data = cloned
 	s.lastUpdate = doc.Metadata.LastUpdate
 	s.dirty = false
+	s.changes.publish(ChangeEvent{Kind: ChangeReplace})
 
 	return nil
 }
 
-// AddContainer upserts a container by name, updating order and returning the new snapshot.
+// Apply runs fn on a deep copy of the cached data and swaps it in only when fn succeeds,
+// so multi-entity changes are applied all at once or not at all. The dirty flag and the
+// change notification are set only on success.
+func (s *Store) Apply(fn func(doc *repository.DataDocument) error) (repository.DataDocument, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	working, err := cloneData(s.data)
+	if err != nil {
+		return repository.DataDocument{}, err
+	}
+	if err := fn(&working); err != nil {
+		return repository.DataDocument{}, err
+	}
+	s.data = working
+	s.dirty = true
+	s.changes.publish(ChangeEvent{Kind: ChangeBulk})
+
+	return cloneData(s.data)
+}
+
+// AddContainer upserts a container by name, updating order and returning the new snapshot. An
+// update keeps the runtime observations (Running, RunningSince, LastError) of the stored container.
 func (s *Store) AddContainer(container repository.Container) (repository.DataDocument, error) {
 	logger.WithComponent("cache").Debugf("adding/updating container: %s", container.Name)
 	s.mu.Lock()
@@ -109,6 +148,7 @@ func (s *Store) AddContainer(container repository.Container) (repository.DataDoc
 	replaced := false
 	for i := range s.data.Containers {
 		if s.data.Containers[i].Name == clonedContainer.Name {
+			clonedContainer.KeepRuntimeState(s.data.Containers[i])
 			s.data.Containers[i] = clonedContainer
 			replaced = true
 			break
@@ -126,73 +166,176 @@ func (s *Store) AddContainer(container repository.Container) (repository.DataDoc
 	// Mark cache as dirty after mutation
 	s.dirty = true
 
+	s.changes.publish(ChangeEvent{Kind: ChangeContainer, Name: clonedContainer.Name})
+
 	return cloneData(s.data)
 }
 
+// MarkRunning sets RunningSince (epoch millis) on the named container if it is not already set.
+// Runtime observations do not publish change events since the configuration is unchanged.
+func (s *Store) MarkRunning(name string, since int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.data.Containers {
+		if s.data.Containers[i].Name != name {
+			continue
+		}
+		if s.data.Containers[i].RunningSince == nil {
+			v := since
+			s.data.Containers[i].RunningSince = &v
+			s.dirty = true
+		}
+		return nil
+	}
+	return ErrContainerNotFound
+}
+
+// MarkStopped clears RunningSince on the named container.
+func (s *Store) MarkStopped(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.data.Containers {
+		if s.data.Containers[i].Name != name {
+			continue
+		}
+		if s.data.Containers[i].RunningSince != nil {
+			s.data.Containers[i].RunningSince = nil
+			s.dirty = true
+		}
+		return nil
+	}
+	return ErrContainerNotFound
+}
+
+// SetLastError sets LastError on the named container, leaving the cache clean when it is unchanged.
+func (s *Store) SetLastError(name, lastError string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.data.Containers {
+		if s.data.Containers[i].Name != name {
+			continue
+		}
+		if s.data.Containers[i].LastError != lastError {
+			s.data.Containers[i].LastError = lastError
+			s.dirty = true
+		}
+		return nil
+	}
+	return ErrContainerNotFound
+}
+
+// RecordRuntimeState marks a container as running (since now, when not already running) or stopped
+// when store implements RuntimeStateStore. Unknown containers (e.g. runtime-only ones) are ignored.
+func RecordRuntimeState(store any, name string, running bool, now time.Time) {
+	rs, ok := store.(RuntimeStateStore)
+	if !ok {
+		return
+	}
+	var err error
+	if running {
+		err = rs.MarkRunning(name, now.UnixMilli())
+	} else {
+		err = rs.MarkStopped(name)
+	}
+	if err != nil && !errors.Is(err, ErrContainerNotFound) {
+		logger.WithComponent("cache").Warnf("failed to record runtime state for %s: %v", name, err)
+	}
+}
+
 // RemoveContainer deletes a container by name and removes it from the order list.
 func (s *Store) RemoveContainer(name string) (repository.DataDocument, error) {
 	logger.WithComponent("cache").Debugf("removing container: %s", name)
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if !RemoveContainerFrom(&s.data, name) {
+		return repository.DataDocument{}, ErrContainerNotFound
+	}
+
+	// Mark cache as dirty after mutation
+	s.dirty = true
+
+	s.changes.publish(ChangeEvent{Kind: ChangeContainer, Name: name})
+
+	return cloneData(s.data)
+}
+
+// RemoveContainerFrom removes the named container from doc together with its order entry, the
+// schedules targeting it and its group memberships. It reports whether the container existed.
+func RemoveContainerFrom(doc *repository.DataDocument, name string) bool {
 	idx := -1
-	for i := range s.data.Containers {
-		if s.data.Containers[i].Name == name {
+	for i := range doc.Containers {
+		if doc.Containers[i].Name == name {
 			idx = i
 			break
 		}
 	}
 	if idx == -1 {
-		return repository.DataDocument{}, ErrContainerNotFound
+		return false
 	}
 
 	// Remove from Containers slice
-	s.data.Containers = append(s.data.Containers[:idx], s.data.Containers[idx+1:]...)
+	doc.Containers = append(doc.Containers[:idx], doc.Containers[idx+1:]...)
 
 	// Remove from Order slice
-	for i := 0; i < len(s.data.Order); i++ {
-		if s.data.Order[i] == name {
-			s.data.Order = append(s.data.Order[:i], s.data.Order[i+1:]...)
+	for i := 0; i < len(doc.Order); i++ {
+		if doc.Order[i] == name {
+			doc.Order = append(doc.Order[:i], doc.Order[i+1:]...)
 			i--
 		}
 	}
 
-	// Mark cache as dirty after mutation
-	s.dirty = true
-
 	// Remove schedules that target this container
-	newSchedules := make([]repository.Schedule, 0, len(s.data.Schedules))
-	for _, sch := range s.data.Schedules {
+	newSchedules := make([]repository.Schedule, 0, len(doc.Schedules))
+	for _, sch := range doc.Schedules {
 		if sch.TargetType == "container" && sch.Target == name {
 			logger.WithComponent("cache").Debugf("removing schedule %s because it targets deleted container %s", sch.ID, name)
 			continue
 		}
 		newSchedules = append(newSchedules, sch)
 	}
-	s.data.Schedules = newSchedules
+	doc.Schedules = newSchedules
 
 	// Remove container references from all groups
-	for gi := range s.data.Groups {
-		newContainers := make([]string, 0, len(s.data.Groups[gi].Container))
-		for _, cname := range s.data.Groups[gi].Container {
+	for gi := range doc.Groups {
+		newContainers := make([]string, 0, len(doc.Groups[gi].Container))
+		for _, cname := range doc.Groups[gi].Container {
 			if cname == name {
-				logger.WithComponent("cache").Debugf("removing container %s from group %s", name, s.data.Groups[gi].Name)
+				logger.WithComponent("cache").Debugf("removing container %s from group %s", name, doc.Groups[gi].Name)
 				continue
 			}
 			newContainers = append(newContainers, cname)
 		}
-		s.data.Groups[gi].Container = newContainers
+		doc.Groups[gi].Container = newContainers
 	}
-
-	return cloneData(s.data)
+	return true
 }
 
-// AddGroup upserts a group by name, updating group order and returning the new snapshot.
+// AddGroup upserts a group by name, updating group order and returning the new snapshot. It fails
+// with a ReferenceError (ErrDanglingReference) when a member container does not exist.
 func (s *Store) AddGroup(group repository.Group) (repository.DataDocument, error) {
+	return s.addGroup(group, true)
+}
+
+// AddGroupUnchecked is AddGroup without the check of the member containers.
+func (s *Store) AddGroupUnchecked(group repository.Group) (repository.DataDocument, error) {
+	return s.addGroup(group, false)
+}
+
+func (s *Store) addGroup(group repository.Group, checkReferences bool) (repository.DataDocument, error) {
 	logger.WithComponent("cache").Debugf("adding/updating group: %s with %d containers", group.Name, len(group.Container))
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if checkReferences {
+		if err := groupReferences(s.data, group); err != nil {
+			return repository.DataDocument{}, err
+		}
+	}
+
 	clonedGroup, err := cloneGroup(group)
 	if err != nil {
 		return repository.DataDocument{}, err
@@ -226,6 +369,8 @@ func (s *Store) AddGroup(group repository.Group) (repository.DataDocument, error
 	// Mark cache as dirty after mutation
 	s.dirty = true
 
+	s.changes.publish(ChangeEvent{Kind: ChangeGroup, Name: clonedGroup.Name})
+
 	return cloneData(s.data)
 }
 
@@ -270,15 +415,34 @@ func (s *Store) RemoveGroup(name string) (repository.DataDocument, error) {
 	}
 	s.data.Schedules = newSchedules
 
+	s.changes.publish(ChangeEvent{Kind: ChangeGroup, Name: name})
+
 	return cloneData(s.data)
 }
 
-// AddSchedule upserts a schedule by id and returns the new snapshot.
+// AddSchedule upserts a schedule by id and returns the new snapshot. It fails with a
+// ReferenceError (ErrDanglingReference) when the target container or group does not exist.
 func (s *Store) AddSchedule(schedule repository.Schedule) (repository.DataDocument, error) {
+	return s.addSchedule(schedule, true)
+}
+
+// AddScheduleUnchecked is AddSchedule without the check of the target.
+func (s *Store) AddScheduleUnchecked(schedule repository.Schedule) (repository.DataDocument, error) {
+	return s.addSchedule(schedule, false)
+}
+
+func (s *Store) addSchedule(schedule repository.Schedule, checkReferences bool) (repository.DataDocument, error) {
 	logger.WithComponent("cache").Debugf("adding/updating schedule: %s (target: %s, %d timers)", schedule.ID, schedule.Target, len(schedule.Timers))
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if checkReferences {
+		containers, groups := referenceSets(s.data)
+		if err := scheduleReferences(schedule, containers, groups); err != nil {
+			return repository.DataDocument{}, err
+		}
+	}
+
 	clonedSchedule, err := cloneSchedule(schedule)
 	if err != nil {
 		return repository.DataDocument{}, err
@@ -300,6 +464,8 @@ func (s *Store) AddSchedule(schedule repository.Schedule) (repository.DataDocume
 	// Mark cache as dirty after mutation
 	s.dirty = true
 
+	s.changes.publish(ChangeEvent{Kind: ChangeSchedule, Name: clonedSchedule.ID})
+
 	return cloneData(s.data)
 }
 
@@ -325,6 +491,8 @@ func (s *Store) RemoveSchedule(id string) (repository.DataDocument, error) {
 	// Mark cache as dirty after mutation
 	s.dirty = true
 
+	s.changes.publish(ChangeEvent{Kind: ChangeSchedule, Name: id})
+
 	return cloneData(s.data)
 }
 
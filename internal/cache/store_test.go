@@ -194,6 +194,26 @@ func TestStore_AddContainer_Update(t *testing.T) {
 	}
 }
 
+func TestStore_AddContainer_UpdateKeepsRuntimeState(t *testing.T) {
+	since := int64(1000)
+	doc := createTestDocument()
+	doc.Containers[0].Running = boolPtr(true)
+	doc.Containers[0].RunningSince = &since
+	doc.Containers[0].LastError = "startup timeout"
+	store := NewStore(doc)
+
+	result, err := store.AddContainer(repository.Container{
+		Name: "container1", FriendlyName: "c1", URL: "http://c1.local", Running: boolPtr(false), Active: boolPtr(true),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c := result.Containers[0]
+	if c.Running == nil || !*c.Running || c.RunningSince == nil || *c.RunningSince != since || c.LastError != "startup timeout" {
+		t.Errorf("expected the stored runtime state to be kept, got running=%v since=%v lastError=%q", c.Running, c.RunningSince, c.LastError)
+	}
+}
+
 func TestStore_RemoveContainer_Success(t *testing.T) {
 	doc := createTestDocument()
 	store := NewStore(doc)
@@ -262,6 +282,7 @@ func TestStore_AddGroup_New(t *testing.T) {
 
 func TestStore_AddGroup_Update(t *testing.T) {
 	doc := createTestDocument()
+	doc.Containers = append(doc.Containers, repository.Container{Name: "container2", FriendlyName: "Container 2", URL: "http://c2.local", Active: boolPtr(true)})
 	store := NewStore(doc)
 
 	updatedGroup := repository.Group{
@@ -284,6 +305,39 @@ func TestStore_AddGroup_Update(t *testing.T) {
 	}
 }
 
+func TestStore_AddGroupAndSchedule_CheckReferences(t *testing.T) {
+	store := NewStore(createTestDocument())
+
+	_, err := store.AddGroup(repository.Group{Name: "group2", Container: []string{"container1", "ghost"}, Active: boolPtr(true)})
+	var refErr *ReferenceError
+	if !errors.As(err, &refErr) || !errors.Is(err, ErrDanglingReference) || len(refErr.Missing) != 1 || refErr.Missing[0] != "ghost" {
+		t.Fatalf("expected a dangling reference to ghost, got %v", err)
+	}
+	_, err = store.AddSchedule(repository.Schedule{ID: "s2", Target: "ghost-group", TargetType: "group"})
+	if !errors.Is(err, ErrDanglingReference) {
+		t.Fatalf("expected a dangling reference to ghost-group, got %v", err)
+	}
+	if store.IsDirty() {
+		t.Error("expected rejected upserts to leave the store clean")
+	}
+
+	if _, err := store.AddGroupUnchecked(repository.Group{Name: "group2", Container: []string{"ghost"}, Active: boolPtr(true)}); err != nil {
+		t.Fatalf("unexpected error from the unchecked upsert: %v", err)
+	}
+	if _, err := store.AddScheduleUnchecked(repository.Schedule{ID: "s2", Target: "ghost-group", TargetType: "group"}); err != nil {
+		t.Fatalf("unexpected error from the unchecked upsert: %v", err)
+	}
+
+	doc, _ := store.Snapshot()
+	report := CheckIntegrity(doc)
+	if report.IsEmpty() || report.Schedules["s2"] != "ghost-group" || len(report.GroupMembers["group2"]) != 1 {
+		t.Errorf("expected the forced references reported, got %+v", report)
+	}
+	if len(report.GroupMembers) != 1 || len(report.Schedules) != 1 || len(report.Order) != 0 {
+		t.Errorf("expected only the forced references reported, got %+v", report)
+	}
+}
+
 func TestStore_RemoveGroup_Success(t *testing.T) {
 	doc := createTestDocument()
 	store := NewStore(doc)
@@ -547,6 +601,73 @@ func TestStartPersistenceScheduler_FinalFlushOnShutdown(t *testing.T) {
 	}
 }
 
+func TestStartPersistenceScheduler_WriteThroughSavesOnMutation(t *testing.T) {
+	store := NewStore(createTestDocument())
+	saver := &mockSaver{}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	StartPersistenceScheduler(ctx, store, saver, time.Hour, WithWriteThrough(true)) // Tick never fires
+
+	if _, err := store.AddContainer(repository.Container{Name: "write-through", FriendlyName: "wt", URL: "http://wt"}); err != nil {
+		t.Fatalf("AddContainer failed: %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for saver.Count() == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if saver.Count() != 1 {
+		t.Fatalf("expected exactly one save after the mutation, got %d", saver.Count())
+	}
+	if store.IsDirty() {
+		t.Error("expected store to be clean after write-through flush")
+	}
+}
+
+func TestStartPersistenceScheduler_OnPersistCalledAfterSave(t *testing.T) {
+	store := NewStore(createTestDocument())
+	store.MarkDirty()
+	saver := &mockSaver{}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	persisted := make(chan int64, 1)
+	StartPersistenceScheduler(ctx, store, saver, 20*time.Millisecond, WithOnPersist(func(lastUpdate int64) {
+		persisted <- lastUpdate
+	}))
+
+	select {
+	case lastUpdate := <-persisted:
+		if lastUpdate != store.GetLastUpdate() {
+			t.Errorf("expected callback with the saved lastUpdate %d, got %d", store.GetLastUpdate(), lastUpdate)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the persist callback after the flush")
+	}
+}
+
+func TestStartPersistenceScheduler_BufferedDoesNotSaveOnMutation(t *testing.T) {
+	store := NewStore(createTestDocument())
+	saver := &mockSaver{}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	StartPersistenceScheduler(ctx, store, saver, time.Hour) // Tick never fires
+
+	if _, err := store.AddContainer(repository.Container{Name: "buffered", FriendlyName: "b", URL: "http://b"}); err != nil {
+		t.Fatalf("AddContainer failed: %v", err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if saver.Count() != 0 {
+		t.Errorf("expected no save before the next tick, got %d", saver.Count())
+	}
+	if !store.IsDirty() {
+		t.Error("expected store to stay dirty until the next tick")
+	}
+}
+
 // ==================== Concurrency Tests ====================
 
 // TestStore_ConcurrentAddContainer verifies that concurrent AddContainer operations
@@ -781,3 +902,158 @@ func TestStore_ConcurrentReplaceAndSnapshot(t *testing.T) {
 		t.Errorf("expected lastUpdate >= 2000, got %d", snapshot.Metadata.LastUpdate)
 	}
 }
+
+func TestStore_Subscribe_ReceivesChanges(t *testing.T) {
+	store := NewStore(createTestDocument())
+	events, unsubscribe := store.Subscribe()
+	defer unsubscribe()
+
+	if _, err := store.AddContainer(repository.Container{Name: "container2", FriendlyName: "Container 2", URL: "http://c2.local", Active: boolPtr(true)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case ev := <-events:
+		if ev.Kind != ChangeContainer || ev.Name != "container2" {
+			t.Errorf("unexpected event: %+v", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected a change event")
+	}
+
+	if err := store.Replace(createTestDocument()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	select {
+	case ev := <-events:
+		if ev.Kind != ChangeReplace {
+			t.Errorf("expected replace event, got %+v", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected a replace event")
+	}
+}
+
+func TestStore_Subscribe_Unsubscribe(t *testing.T) {
+	store := NewStore(createTestDocument())
+	events, unsubscribe := store.Subscribe()
+	unsubscribe()
+	// Calling it twice must be safe
+	unsubscribe()
+
+	if _, ok := <-events; ok {
+		t.Error("expected channel to be closed after unsubscribe")
+	}
+
+	// Publishing without subscribers must not block
+	if _, err := store.RemoveContainer("container1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestStore_MarkRunningAndStopped(t *testing.T) {
+	store := NewStore(createTestDocument())
+
+	if err := store.MarkRunning("container1", 1234); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// A second observation must keep the original timestamp
+	if err := store.MarkRunning("container1", 5678); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	doc, _ := store.Snapshot()
+	if rs := doc.Containers[0].RunningSince; rs == nil || *rs != 1234 {
+		t.Errorf("expected RunningSince 1234, got %v", rs)
+	}
+	if !store.IsDirty() {
+		t.Error("expected store to be dirty after MarkRunning")
+	}
+
+	if err := store.MarkStopped("container1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	doc, _ = store.Snapshot()
+	if doc.Containers[0].RunningSince != nil {
+		t.Errorf("expected RunningSince to be cleared, got %v", *doc.Containers[0].RunningSince)
+	}
+
+	if err := store.MarkRunning("missing", 1); !errors.Is(err, ErrContainerNotFound) {
+		t.Errorf("expected ErrContainerNotFound, got %v", err)
+	}
+}
+
+func TestStore_SetLastError(t *testing.T) {
+	store := NewStore(createTestDocument())
+
+	if err := store.SetLastError("container1", "startup timeout"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	doc, _ := store.Snapshot()
+	if doc.Containers[0].LastError != "startup timeout" {
+		t.Errorf("expected LastError to be set, got %q", doc.Containers[0].LastError)
+	}
+	if !store.IsDirty() {
+		t.Error("expected store to be dirty after SetLastError")
+	}
+
+	// Setting the same value again must not dirty the cache
+	store.ClearDirty()
+	if err := store.SetLastError("container1", "startup timeout"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if store.IsDirty() {
+		t.Error("expected store to stay clean when LastError is unchanged")
+	}
+
+	if err := store.SetLastError("missing", ""); !errors.Is(err, ErrContainerNotFound) {
+		t.Errorf("expected ErrContainerNotFound, got %v", err)
+	}
+}
+
+func TestStore_Apply_CommitsAndNotifies(t *testing.T) {
+	store := NewStore(createTestDocument())
+	events, unsubscribe := store.Subscribe()
+	defer unsubscribe()
+
+	doc, err := store.Apply(func(doc *repository.DataDocument) error {
+		doc.Containers[0].FriendlyName = "renamed"
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if doc.Containers[0].FriendlyName != "renamed" {
+		t.Errorf("expected returned snapshot to include the change, got %q", doc.Containers[0].FriendlyName)
+	}
+	if !store.IsDirty() {
+		t.Error("expected store to be dirty after Apply")
+	}
+	select {
+	case ev := <-events:
+		if ev.Kind != ChangeBulk {
+			t.Errorf("expected %s event, got %s", ChangeBulk, ev.Kind)
+		}
+	case <-time.After(time.Second):
+		t.Error("expected a change event")
+	}
+}
+
+func TestStore_Apply_RollsBackOnError(t *testing.T) {
+	store := NewStore(createTestDocument())
+	before, _ := store.Snapshot()
+
+	_, err := store.Apply(func(doc *repository.DataDocument) error {
+		doc.Containers[0].FriendlyName = "partial"
+		return errors.New("boom")
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	after, _ := store.Snapshot()
+	if after.Containers[0].FriendlyName != before.Containers[0].FriendlyName {
+		t.Errorf("expected no change after failed Apply, got %q", after.Containers[0].FriendlyName)
+	}
+	if store.IsDirty() {
+		t.Error("expected store to stay clean after failed Apply")
+	}
+}
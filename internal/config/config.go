@@ -2,12 +2,16 @@ package config
 
 import (
 	"fmt"
+	"net"
+	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/bassista/go_spin/internal/events"
 	"github.com/bassista/go_spin/internal/logger"
 	"github.com/joho/godotenv"
 	"github.com/spf13/viper"
@@ -17,20 +21,28 @@ const ENV_PREFIX = "GO_SPIN"
 
 // Config holds all application configuration (immutable after load)
 type Config struct {
-	Server ServerConfig
-	Data   DataConfig
-	Misc   MiscConfig
+	Server   ServerConfig
+	Data     DataConfig
+	Runtime  RuntimeConfig
+	Misc     MiscConfig
+	Webhooks WebhooksConfig
+	Auth     AuthConfig
+	Sync     SyncConfig
+	UI       UIConfig
 }
 
 type ServerConfig struct {
-	Port               int
-	WaitingServerPort  int
-	ReadTimeout        time.Duration
-	WriteTimeout       time.Duration
-	IdleTimeout        time.Duration
-	ShutDownTimeout    time.Duration
-	RequestTimeout     time.Duration
-	CORSAllowedOrigins string // CORS allowed origins, default "*"
+	Port                 int
+	WaitingServerPort    int
+	ReadTimeout          time.Duration
+	WriteTimeout         time.Duration
+	IdleTimeout          time.Duration
+	ShutDownTimeout      time.Duration
+	RequestTimeout       time.Duration
+	CORSAllowedOrigins   string        // CORS allowed origins, default "*"
+	CORSMaxAge           time.Duration // Access-Control-Max-Age for preflight caching
+	CORSAllowCredentials bool          // send Access-Control-Allow-Credentials for specific origins
+	BindAddress          string        // host/IP both servers listen on, empty means all interfaces
 }
 
 type DataConfig struct {
@@ -42,13 +54,232 @@ type DataConfig struct {
 	SpinUpUrl                string
 	RefreshIntervalSecs      int
 	StatsRefreshIntervalSecs int
+	MaxLoadForStart          float64       // scheduler defers starts above this host load, 0 disables the guard
+	PlanMaxDays              int           // maximum range accepted by the scheduler plan endpoints
+	NextEventsHorizonDays    int           // how far ahead GET /runtime/next-events looks for the next start/stop
+	StrictGroups             bool          // reject group start/waiting requests when a member container is missing
+	SchedulingChangeDebounce time.Duration // delay of the out-of-cycle tick after a cache change, 0 disables it
+	SchedulingMinTrigger     time.Duration // minimum spacing between change-triggered ticks
+	SchedulingExact          bool          // also evaluate exactly at the next planned start/stop, not only on poll
+	SchedulingErrorLogDedup  time.Duration // window collapsing the identical per-container scheduler error lines, 0 disables it
+	StartupProbeInterval     time.Duration // readiness polling interval while enforcing a container startup timeout
+	StopOnStartupTimeout     bool          // stop a container that does not become ready within its startup timeout
+	SaveMode                 string        // "serial" (default) or "coalesce" for concurrent data file saves
+	Compress                 bool          // gzip the data file on save (implied by a ".gz" file path)
+	ReadReplica              bool          // serve hot read endpoints from an eventually-consistent replica of the cache
+	ReadReplicaResync        time.Duration // periodic replica refresh, in addition to change events
+	MinSchedulingPoll        time.Duration // soft floor for SchedulingPoll, 0 disables it
+	MinSchedulingPollStrict  bool          // fail instead of clamping when SchedulingPoll is below the floor
+	WriteThrough             bool          // persist right after every cache mutation, the periodic flush stays as a safety net
+	LoadRetries              int           // extra attempts of the startup data load after a transient I/O error
+	LoadRetryDelay           time.Duration // delay before the first load retry, doubled on each further attempt
+	MaxNotesLength           int           // maximum container notes length in characters, 0 means unlimited
+	MaxMetaKeys              int           // maximum number of container meta entries, 0 means unlimited
+	SchedulerShards          int           // containers evaluated over this many ticks in round-robin, 1 evaluates all of them every tick
+	StateStreamInterval      time.Duration // sampling interval of the live /ws/state stream, 0 disables the stream
+	JanitorInterval          time.Duration // how often expired in-memory entries (day flags, extensions, readiness caches) are pruned, 0 disables it
+	OperationsRetention      time.Duration // how long a finished start/stop operation can still be looked up with GET /ops/:id
+	TenantsDir               string        // directory of additional tenant data documents (<tenant>.json) served under /api/v1/tenants/<tenant>, empty disables tenants
+	BackupRetention          int           // rotated copies of the data file written before each save (<file>.bak.N), 0 disables backups
+	AuditFilePath            string        // JSON Lines file of the audit log of the API changes and starts/stops, empty disables the audit log
+	AuditMaxEntries          int           // most recent audit entries kept (in memory and, after compaction, in the file), 0 keeps them all
+}
+
+type RuntimeConfig struct {
+	StatsEnabled      bool          // when false, stats endpoints return zeroed values without querying the runtime
+	MetricsRetention  time.Duration // per-container metrics history kept in memory, 0 disables the history
+	MetricsMaxSamples int           // cap on the samples held across all containers, 0 means no cap
+	AllowRecreate     bool          // let the docker runtime recreate containers to apply a CommandOverride
+	// WatchEvents keeps the running state of the docker containers in memory from the Docker events
+	// instead of inspecting a container on every check
+	WatchEvents bool
+	// DiscoveryInterval is how often containers are registered from their labels, 0 disables discovery
+	DiscoveryInterval time.Duration
+	// DiscoveryLabelPrefix prefixes the discovery labels, e.g. "go-spin" for go-spin.enable=true
+	DiscoveryLabelPrefix string
+	// WolSSHKeyFile and WolSSHKnownHosts are the private key and the known_hosts file used to shut
+	// down the machines of the "wol" entries over SSH
+	WolSSHKeyFile    string
+	WolSSHKnownHosts string
+	// ExecTimeout bounds a command run by POST /runtime/:name/exec
+	ExecTimeout time.Duration
+	// RemoteTimeout bounds a single call to the agent of a remote host
+	RemoteTimeout time.Duration
+	Hosts         []RemoteHostConfig // read from the runtime.hosts list of config.yaml
+}
+
+// RemoteHostConfig is a machine running the go_spin agent; containers declaring its name as
+// host are started and stopped through it.
+type RemoteHostConfig struct {
+	Name  string `mapstructure:"name"`
+	URL   string `mapstructure:"url"`   // base url of the agent, e.g. http://nas.lan:8086
+	Token string `mapstructure:"token"` // bearer token expected by the agent (AGENT_TOKEN)
 }
 
 type MiscConfig struct {
 	GinMode      string
 	SchedulingTZ string
-	RuntimeType  string // "docker" o "memory"
+	RuntimeType  string // "docker", "memory" o "systemd"
 	LogLevel     string // "debug", "info", "warn", "error", default "info"
+	// LogFormat is "text" (default) or "json", one object per line for log aggregators
+	LogFormat string
+	// LogFile also writes the logs to this file when set, rotated after LogMaxSizeMB
+	LogFile string
+	// LogMaxSizeMB is the size at which LogFile is rotated, 0 disables the rotation
+	LogMaxSizeMB int
+	// LogMaxAge removes the rotated copies of LogFile older than this, 0 keeps them
+	LogMaxAge time.Duration
+	// LogMaxBackups is the number of rotated copies of LogFile kept, 0 starts LogFile afresh at each rotation
+	LogMaxBackups int
+	// WaitingProbeBeforeRedirect makes the waiting page redirect server-side when the target is already ready
+	WaitingProbeBeforeRedirect bool
+	// WaitingDefaultMessage is shown on the waiting page when the container has no WaitingMessage
+	WaitingDefaultMessage string
+	// WaitingMaxWait is how long the waiting page polls before giving up, 0 means forever
+	WaitingMaxWait time.Duration
+	// ReadyCacheTTL keeps a positive /container/:name/ready probe result, 0 disables caching
+	ReadyCacheTTL time.Duration
+	// ReadyCacheNegativeTTL keeps a negative probe result (shorter, so recovery is noticed quickly)
+	ReadyCacheNegativeTTL time.Duration
+	// ExternalReadyURL is called with ?name=<container> for containers with readyCheckType "external"
+	// and must answer {"ready": bool}; empty leaves those containers never ready
+	ExternalReadyURL string
+	// ExternalReadyTimeout bounds a single call to the external ready checker
+	ExternalReadyTimeout time.Duration
+	// WaitingRuntimeUnavailable selects the waiting page behavior when the runtime is unreachable:
+	// "error" renders a 503 "temporarily unavailable" page, "wait" serves the polling page anyway
+	WaitingRuntimeUnavailable string
+	// WaitingStartConcurrency caps the concurrent container starts triggered by the waiting page, 0 means unlimited
+	WaitingStartConcurrency int
+	// InactiveStatus is the HTTP status of waiting page hits for inactive containers/groups (3xx or 4xx)
+	InactiveStatus int
+	// InactiveRedirectURL, when set, redirects waiting page hits for inactive entities there
+	// (with InactiveStatus if it is a 3xx, 302 otherwise)
+	InactiveRedirectURL string
+	// WaitingReportDependencies makes the JSON waiting page of a container with dependsOn list the
+	// dependencies not ready yet (waitingOn) and answer 425 until they are
+	WaitingReportDependencies bool
+	// SafeMode skips every container start/stop (manual, group, scheduler, waiting page) while the
+	// configuration stays editable; it can be toggled at runtime with POST /safe-mode
+	SafeMode bool
+	// DefaultURLScheme is prepended to schemeless container URLs ("host:port") in waiting redirects
+	DefaultURLScheme string
+	// WaitingProxy makes the waiting server reverse-proxy /:name/... to the container URL once it
+	// answers, instead of serving the waiting page
+	WaitingProxy bool
+	// WaitingProxyTimeout bounds how long a proxied request waits for the container to accept connections
+	WaitingProxyTimeout time.Duration
+	// WaitingProxyMaxBodyMB bounds the request body the proxy buffers to replay it on every attempt
+	WaitingProxyMaxBodyMB int
+	// WaitingRateLimitPerMinute caps the waiting page (or proxied) requests of each client IP, 0 disables the limit
+	WaitingRateLimitPerMinute int
+	// WaitingRateLimitBurst is the number of requests a client may send at once, 0 means WaitingRateLimitPerMinute
+	WaitingRateLimitBurst int
+	// WaitingCaptchaURL, when set, receives the browsers over the rate limit (with ?return=<url>)
+	// instead of a 429, e.g. a challenge page of the reverse proxy
+	WaitingCaptchaURL string
+	// WaitingTrustedProxies are the IPs/CIDRs whose X-Forwarded-For is trusted for the client IP of
+	// the waiting server; empty trusts none, so the client IP is the peer address
+	WaitingTrustedProxies []string
+}
+
+// WebhooksConfig lists the outbound webhooks notified of the events of the internal event bus.
+type WebhooksConfig struct {
+	Timeout time.Duration   // bounds a single webhook delivery
+	Hooks   []WebhookConfig // read from the webhooks.hooks list of config.yaml
+}
+
+// WebhookConfig is a single outbound webhook.
+type WebhookConfig struct {
+	URL string `mapstructure:"url"`
+	// Secret, when set, signs the body with HMAC-SHA256 in the X-GoSpin-Signature header
+	Secret string `mapstructure:"secret"`
+	// Events filters the event types delivered (e.g. "container.started"), empty means every type
+	Events []string `mapstructure:"events"`
+}
+
+// SyncConfig turns on the GitOps mode: the main data document is read-only through the API and
+// periodically replaced by a remote copy, fetched from URL or from a clone of GitRepo.
+type SyncConfig struct {
+	URL       string        // http(s) URL of the data document, e.g. the raw file of a git hosting service
+	GitRepo   string        // git repository cloned and pulled with the git command
+	GitBranch string        // branch of GitRepo
+	GitPath   string        // path of the data document inside GitRepo
+	GitDir    string        // local clone of GitRepo, a temporary directory when empty
+	Interval  time.Duration // how often the remote document is fetched
+	Timeout   time.Duration // bounds a single fetch
+}
+
+// Enabled reports whether the data document is synced from a remote source.
+func (s SyncConfig) Enabled() bool {
+	return s.URL != "" || s.GitRepo != ""
+}
+
+// UIConfig locates the files served to browsers.
+type UIConfig struct {
+	// WaitingTemplatePath is the waiting page template, reloaded when the file changes
+	WaitingTemplatePath string
+}
+
+// AuthConfig protects the management API; with no API keys and no users it stays open. The
+// waiting server is public unless WaitingUserHeader delegates its authentication to a proxy.
+type AuthConfig struct {
+	APIKeys []string   // admin keys, accepted in the X-API-Key header or as a bearer token
+	Keys    []AuthKey  // keys with a role, read from the auth.keys list of config.yaml
+	Users   []AuthUser // basic-auth users, read from the auth.users list of config.yaml
+	// WaitingUserHeader is the header carrying the user authenticated by a forward-auth proxy in
+	// front of the waiting server (e.g. "Remote-User"); when set, requests without it are rejected.
+	WaitingUserHeader string
+}
+
+// defaultAuthRole is the role of API keys and users that do not set one.
+const defaultAuthRole = "admin"
+
+// AuthKey is an API key of the management API with its role.
+type AuthKey struct {
+	Key  string `mapstructure:"key"`
+	Role string `mapstructure:"role"` // viewer, operator or admin (default)
+}
+
+// AuthUser is a basic-auth user of the management API.
+type AuthUser struct {
+	Username string `mapstructure:"username"`
+	// Password is plain text or a bcrypt hash ("$2a$..."), preferred in config files
+	Password string `mapstructure:"password"`
+	Role     string `mapstructure:"role"` // viewer, operator or admin (default)
+}
+
+// RoleOrDefault returns the role of the key, admin when unset.
+func (k AuthKey) RoleOrDefault() string {
+	if k.Role == "" {
+		return defaultAuthRole
+	}
+	return k.Role
+}
+
+// RoleOrDefault returns the role of the user, admin when unset.
+func (u AuthUser) RoleOrDefault() string {
+	if u.Role == "" {
+		return defaultAuthRole
+	}
+	return u.Role
+}
+
+// KeyRoles returns every API key with its role: api_keys are admin keys.
+func (a AuthConfig) KeyRoles() map[string]string {
+	keys := make(map[string]string, len(a.APIKeys)+len(a.Keys))
+	for _, k := range a.APIKeys {
+		keys[k] = defaultAuthRole
+	}
+	for _, k := range a.Keys {
+		keys[k.Key] = k.RoleOrDefault()
+	}
+	return keys
+}
+
+// Enabled reports whether the management API requires authentication.
+func (a AuthConfig) Enabled() bool {
+	return len(a.APIKeys) > 0 || len(a.Keys) > 0 || len(a.Users) > 0
 }
 
 // LoadConfig loads configuration from file, env vars and validates required fields.
@@ -74,6 +305,9 @@ func LoadConfig() (*Config, error) {
 	viper.SetDefault("server.shutdown_timeout_secs", 5)
 	viper.SetDefault("server.request_timeout_millis", 1000)
 	viper.SetDefault("server.cors_allowed_origins", "*")
+	viper.SetDefault("server.cors_max_age_secs", 86400)
+	viper.SetDefault("server.cors_allow_credentials", false)
+	viper.SetDefault("server.bind_address", "")
 
 	viper.SetDefault("data.file_path", confPath+"/data/config.json")
 	viper.SetDefault("data.persist_interval_secs", 5)
@@ -83,10 +317,91 @@ func LoadConfig() (*Config, error) {
 	viper.SetDefault("data.spin_up_url", "http://localhost/")
 	viper.SetDefault("data.refresh_interval_secs", 60)
 	viper.SetDefault("data.stats_refresh_interval_secs", 120)
+	viper.SetDefault("data.max_load_for_start", 0)
+	viper.SetDefault("data.plan_max_days", 366)
+	viper.SetDefault("data.next_events_horizon_days", 7)
+	viper.SetDefault("data.strict_groups", false)
+	viper.SetDefault("data.scheduling_change_debounce_millis", 500)
+	viper.SetDefault("data.scheduling_min_trigger_interval_millis", 2000)
+	viper.SetDefault("data.scheduling_exact_transitions", true)
+	viper.SetDefault("data.scheduling_error_log_dedup_secs", 300)
+	viper.SetDefault("data.startup_probe_interval_millis", 1000)
+	viper.SetDefault("data.stop_on_startup_timeout", false)
+	viper.SetDefault("data.save_mode", "serial")
+	viper.SetDefault("data.compress", false)
+	viper.SetDefault("data.read_replica", false)
+	viper.SetDefault("data.write_through", false)
+	viper.SetDefault("data.read_replica_resync_secs", 5)
+	viper.SetDefault("data.min_scheduling_poll_secs", 5)
+	viper.SetDefault("data.min_scheduling_poll_strict", false)
+	viper.SetDefault("data.load_retries", 3)
+	viper.SetDefault("data.load_retry_delay_millis", 500)
+	viper.SetDefault("data.max_notes_length", 2000)
+	viper.SetDefault("data.max_meta_keys", 32)
+	viper.SetDefault("data.scheduler_shards", 1)
+	viper.SetDefault("data.janitor_interval_secs", 300)
+	viper.SetDefault("data.operations_retention_secs", 600)
+	viper.SetDefault("data.tenants_dir", "")
+	viper.SetDefault("data.backup_retention", 5)
+	viper.SetDefault("data.audit_file_path", confPath+"/data/audit.jsonl")
+	viper.SetDefault("data.audit_max_entries", 10000)
+	viper.SetDefault("data.state_stream_interval_millis", 2000)
+	viper.SetDefault("runtime.stats_enabled", true)
+	viper.SetDefault("runtime.metrics_retention_minutes", 0)
+	viper.SetDefault("runtime.metrics_max_samples", 100000)
+	viper.SetDefault("runtime.allow_recreate", false)
+	viper.SetDefault("runtime.watch_events", true)
+	viper.SetDefault("runtime.discovery_interval_secs", 0)
+	viper.SetDefault("runtime.discovery_label_prefix", "go-spin")
+	viper.SetDefault("runtime.remote_timeout_secs", 10)
+	viper.SetDefault("runtime.exec_timeout_secs", 30)
+	viper.SetDefault("runtime.wol_ssh_key_file", "")
+	viper.SetDefault("runtime.wol_ssh_known_hosts", "")
 	viper.SetDefault("misc.gin_mode", "release")
 	viper.SetDefault("misc.scheduling_timezone", "Local")
 	viper.SetDefault("misc.runtime_type", "docker")
 	viper.SetDefault("misc.log_level", "info")
+	viper.SetDefault("misc.log_format", "text")
+	viper.SetDefault("misc.log_file", "")
+	viper.SetDefault("misc.log_max_size_mb", 100)
+	viper.SetDefault("misc.log_max_age_days", 7)
+	viper.SetDefault("misc.log_max_backups", 5)
+	viper.SetDefault("misc.waiting_probe_before_redirect", false)
+	viper.SetDefault("misc.waiting_default_message", "Starting up, please wait...")
+	viper.SetDefault("misc.waiting_max_wait_secs", 300)
+	viper.SetDefault("misc.default_url_scheme", "http://")
+	viper.SetDefault("misc.waiting_runtime_unavailable", "error")
+	viper.SetDefault("misc.waiting_start_concurrency", 4)
+	viper.SetDefault("misc.inactive_status", 403)
+	viper.SetDefault("misc.inactive_redirect_url", "")
+	viper.SetDefault("misc.ready_cache_ttl_millis", 2000)
+	viper.SetDefault("misc.ready_cache_negative_ttl_millis", 500)
+	viper.SetDefault("misc.waiting_report_dependencies", false)
+	viper.SetDefault("misc.safe_mode", false)
+	viper.SetDefault("misc.external_ready_url", "")
+	viper.SetDefault("misc.external_ready_timeout_millis", 2000)
+	viper.SetDefault("misc.waiting_proxy", false)
+	viper.SetDefault("misc.waiting_proxy_timeout_millis", 60000)
+	viper.SetDefault("misc.waiting_proxy_max_body_mb", 10)
+	viper.SetDefault("misc.waiting_rate_limit_per_minute", 0)
+	viper.SetDefault("misc.waiting_rate_limit_burst", 0)
+	viper.SetDefault("misc.waiting_captcha_url", "")
+	viper.SetDefault("misc.waiting_trusted_proxies", []string{})
+
+	viper.SetDefault("webhooks.timeout_millis", 5000)
+
+	viper.SetDefault("auth.api_keys", []string{})
+	viper.SetDefault("auth.waiting_user_header", "")
+
+	viper.SetDefault("ui.waiting_template_path", "./ui/templates/waiting.html")
+
+	viper.SetDefault("sync.url", "")
+	viper.SetDefault("sync.git_repo", "")
+	viper.SetDefault("sync.git_branch", "main")
+	viper.SetDefault("sync.git_path", "config.json")
+	viper.SetDefault("sync.git_dir", "")
+	viper.SetDefault("sync.interval_secs", 60)
+	viper.SetDefault("sync.timeout_secs", 30)
 
 	// Environment variables automatically override config file values
 	viper.AutomaticEnv()
@@ -101,6 +416,12 @@ func LoadConfig() (*Config, error) {
 		}
 	}
 
+	if profile := os.Getenv(ENV_PREFIX + "_PROFILE"); profile != "" {
+		if err := mergeProfileConfig(confPath, profile); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := dataFileExistenceCheck(); err != nil {
 		return nil, err
 	}
@@ -118,14 +439,17 @@ func LoadConfig() (*Config, error) {
 	// Build immutable config struct
 	cfg := &Config{
 		Server: ServerConfig{
-			Port:               port,
-			WaitingServerPort:  portWaitingServer,
-			ReadTimeout:        time.Duration(viper.GetInt("server.read_timeout_secs")) * time.Second,
-			WriteTimeout:       time.Duration(viper.GetInt("server.write_timeout_secs")) * time.Second,
-			IdleTimeout:        time.Duration(viper.GetInt("server.idle_timeout_secs")) * time.Second,
-			ShutDownTimeout:    time.Duration(viper.GetInt("server.shutdown_timeout_secs")) * time.Second,
-			RequestTimeout:     time.Duration(viper.GetInt("server.request_timeout_millis")) * time.Millisecond,
-			CORSAllowedOrigins: viper.GetString("server.cors_allowed_origins"),
+			Port:                 port,
+			WaitingServerPort:    portWaitingServer,
+			ReadTimeout:          time.Duration(viper.GetInt("server.read_timeout_secs")) * time.Second,
+			WriteTimeout:         time.Duration(viper.GetInt("server.write_timeout_secs")) * time.Second,
+			IdleTimeout:          time.Duration(viper.GetInt("server.idle_timeout_secs")) * time.Second,
+			ShutDownTimeout:      time.Duration(viper.GetInt("server.shutdown_timeout_secs")) * time.Second,
+			RequestTimeout:       time.Duration(viper.GetInt("server.request_timeout_millis")) * time.Millisecond,
+			CORSAllowedOrigins:   viper.GetString("server.cors_allowed_origins"),
+			CORSMaxAge:           time.Duration(viper.GetInt("server.cors_max_age_secs")) * time.Second,
+			CORSAllowCredentials: viper.GetBool("server.cors_allow_credentials"),
+			BindAddress:          strings.TrimSpace(viper.GetString("server.bind_address")),
 		},
 		Data: DataConfig{
 			FilePath:                 viper.GetString("data.file_path"),
@@ -136,13 +460,113 @@ func LoadConfig() (*Config, error) {
 			SpinUpUrl:                viper.GetString("data.spin_up_url"),
 			RefreshIntervalSecs:      viper.GetInt("data.refresh_interval_secs"),
 			StatsRefreshIntervalSecs: viper.GetInt("data.stats_refresh_interval_secs"),
+			MaxLoadForStart:          viper.GetFloat64("data.max_load_for_start"),
+			PlanMaxDays:              viper.GetInt("data.plan_max_days"),
+			NextEventsHorizonDays:    viper.GetInt("data.next_events_horizon_days"),
+			StrictGroups:             viper.GetBool("data.strict_groups"),
+			SchedulingChangeDebounce: time.Duration(viper.GetInt("data.scheduling_change_debounce_millis")) * time.Millisecond,
+			SchedulingMinTrigger:     time.Duration(viper.GetInt("data.scheduling_min_trigger_interval_millis")) * time.Millisecond,
+			SchedulingExact:          viper.GetBool("data.scheduling_exact_transitions"),
+			SchedulingErrorLogDedup:  time.Duration(viper.GetInt("data.scheduling_error_log_dedup_secs")) * time.Second,
+			StartupProbeInterval:     time.Duration(viper.GetInt("data.startup_probe_interval_millis")) * time.Millisecond,
+			StopOnStartupTimeout:     viper.GetBool("data.stop_on_startup_timeout"),
+			SaveMode:                 viper.GetString("data.save_mode"),
+			Compress:                 viper.GetBool("data.compress"),
+			ReadReplica:              viper.GetBool("data.read_replica"),
+			ReadReplicaResync:        time.Duration(viper.GetInt("data.read_replica_resync_secs")) * time.Second,
+			MinSchedulingPoll:        time.Duration(viper.GetInt("data.min_scheduling_poll_secs")) * time.Second,
+			MinSchedulingPollStrict:  viper.GetBool("data.min_scheduling_poll_strict"),
+			LoadRetries:              viper.GetInt("data.load_retries"),
+			LoadRetryDelay:           time.Duration(viper.GetInt("data.load_retry_delay_millis")) * time.Millisecond,
+			MaxNotesLength:           viper.GetInt("data.max_notes_length"),
+			MaxMetaKeys:              viper.GetInt("data.max_meta_keys"),
+			SchedulerShards:          viper.GetInt("data.scheduler_shards"),
+			JanitorInterval:          time.Duration(viper.GetInt("data.janitor_interval_secs")) * time.Second,
+			OperationsRetention:      time.Duration(viper.GetInt("data.operations_retention_secs")) * time.Second,
+			TenantsDir:               viper.GetString("data.tenants_dir"),
+			BackupRetention:          viper.GetInt("data.backup_retention"),
+			AuditFilePath:            viper.GetString("data.audit_file_path"),
+			AuditMaxEntries:          viper.GetInt("data.audit_max_entries"),
+			StateStreamInterval:      time.Duration(viper.GetInt("data.state_stream_interval_millis")) * time.Millisecond,
+			WriteThrough:             viper.GetBool("data.write_through"),
+		},
+		Runtime: RuntimeConfig{
+			StatsEnabled:         viper.GetBool("runtime.stats_enabled"),
+			MetricsRetention:     time.Duration(viper.GetInt("runtime.metrics_retention_minutes")) * time.Minute,
+			MetricsMaxSamples:    viper.GetInt("runtime.metrics_max_samples"),
+			AllowRecreate:        viper.GetBool("runtime.allow_recreate"),
+			WatchEvents:          viper.GetBool("runtime.watch_events"),
+			DiscoveryInterval:    time.Duration(viper.GetInt("runtime.discovery_interval_secs")) * time.Second,
+			DiscoveryLabelPrefix: strings.TrimSpace(viper.GetString("runtime.discovery_label_prefix")),
+			RemoteTimeout:        time.Duration(viper.GetInt("runtime.remote_timeout_secs")) * time.Second,
+			ExecTimeout:          time.Duration(viper.GetInt("runtime.exec_timeout_secs")) * time.Second,
+			WolSSHKeyFile:        strings.TrimSpace(viper.GetString("runtime.wol_ssh_key_file")),
+			WolSSHKnownHosts:     strings.TrimSpace(viper.GetString("runtime.wol_ssh_known_hosts")),
 		},
 		Misc: MiscConfig{
-			GinMode:      viper.GetString("misc.gin_mode"),
-			SchedulingTZ: viper.GetString("misc.scheduling_timezone"),
-			RuntimeType:  viper.GetString("misc.runtime_type"),
-			LogLevel:     viper.GetString("misc.log_level"),
+			GinMode:       viper.GetString("misc.gin_mode"),
+			SchedulingTZ:  viper.GetString("misc.scheduling_timezone"),
+			RuntimeType:   viper.GetString("misc.runtime_type"),
+			LogLevel:      viper.GetString("misc.log_level"),
+			LogFormat:     strings.ToLower(strings.TrimSpace(viper.GetString("misc.log_format"))),
+			LogFile:       strings.TrimSpace(viper.GetString("misc.log_file")),
+			LogMaxSizeMB:  viper.GetInt("misc.log_max_size_mb"),
+			LogMaxAge:     time.Duration(viper.GetInt("misc.log_max_age_days")) * 24 * time.Hour,
+			LogMaxBackups: viper.GetInt("misc.log_max_backups"),
+
+			WaitingProbeBeforeRedirect: viper.GetBool("misc.waiting_probe_before_redirect"),
+			WaitingDefaultMessage:      viper.GetString("misc.waiting_default_message"),
+			WaitingMaxWait:             time.Duration(viper.GetInt("misc.waiting_max_wait_secs")) * time.Second,
+			DefaultURLScheme:           viper.GetString("misc.default_url_scheme"),
+			WaitingRuntimeUnavailable:  viper.GetString("misc.waiting_runtime_unavailable"),
+			WaitingStartConcurrency:    viper.GetInt("misc.waiting_start_concurrency"),
+			InactiveStatus:             viper.GetInt("misc.inactive_status"),
+			InactiveRedirectURL:        strings.TrimSpace(viper.GetString("misc.inactive_redirect_url")),
+			ReadyCacheTTL:              time.Duration(viper.GetInt("misc.ready_cache_ttl_millis")) * time.Millisecond,
+			ReadyCacheNegativeTTL:      time.Duration(viper.GetInt("misc.ready_cache_negative_ttl_millis")) * time.Millisecond,
+			WaitingReportDependencies:  viper.GetBool("misc.waiting_report_dependencies"),
+			SafeMode:                   viper.GetBool("misc.safe_mode"),
+			ExternalReadyURL:           strings.TrimSpace(viper.GetString("misc.external_ready_url")),
+			ExternalReadyTimeout:       time.Duration(viper.GetInt("misc.external_ready_timeout_millis")) * time.Millisecond,
+			WaitingProxy:               viper.GetBool("misc.waiting_proxy"),
+			WaitingProxyTimeout:        time.Duration(viper.GetInt("misc.waiting_proxy_timeout_millis")) * time.Millisecond,
+			WaitingProxyMaxBodyMB:      viper.GetInt("misc.waiting_proxy_max_body_mb"),
+			WaitingRateLimitPerMinute:  viper.GetInt("misc.waiting_rate_limit_per_minute"),
+			WaitingRateLimitBurst:      viper.GetInt("misc.waiting_rate_limit_burst"),
+			WaitingCaptchaURL:          strings.TrimSpace(viper.GetString("misc.waiting_captcha_url")),
+			WaitingTrustedProxies:      viper.GetStringSlice("misc.waiting_trusted_proxies"),
 		},
+		Webhooks: WebhooksConfig{
+			Timeout: time.Duration(viper.GetInt("webhooks.timeout_millis")) * time.Millisecond,
+		},
+		Auth: AuthConfig{
+			APIKeys:           viper.GetStringSlice("auth.api_keys"),
+			WaitingUserHeader: strings.TrimSpace(viper.GetString("auth.waiting_user_header")),
+		},
+		UI: UIConfig{
+			WaitingTemplatePath: strings.TrimSpace(viper.GetString("ui.waiting_template_path")),
+		},
+		Sync: SyncConfig{
+			URL:       strings.TrimSpace(viper.GetString("sync.url")),
+			GitRepo:   strings.TrimSpace(viper.GetString("sync.git_repo")),
+			GitBranch: strings.TrimSpace(viper.GetString("sync.git_branch")),
+			GitPath:   strings.TrimSpace(viper.GetString("sync.git_path")),
+			GitDir:    viper.GetString("sync.git_dir"),
+			Interval:  time.Duration(viper.GetInt("sync.interval_secs")) * time.Second,
+			Timeout:   time.Duration(viper.GetInt("sync.timeout_secs")) * time.Second,
+		},
+	}
+	if err := viper.UnmarshalKey("webhooks.hooks", &cfg.Webhooks.Hooks); err != nil {
+		return nil, fmt.Errorf("invalid webhooks.hooks: %w", err)
+	}
+	if err := viper.UnmarshalKey("runtime.hosts", &cfg.Runtime.Hosts); err != nil {
+		return nil, fmt.Errorf("invalid runtime.hosts: %w", err)
+	}
+	if err := viper.UnmarshalKey("auth.users", &cfg.Auth.Users); err != nil {
+		return nil, fmt.Errorf("invalid auth.users: %w", err)
+	}
+	if err := viper.UnmarshalKey("auth.keys", &cfg.Auth.Keys); err != nil {
+		return nil, fmt.Errorf("invalid auth.keys: %w", err)
 	}
 
 	logger.WithComponent("config").Debugf("configuration loaded: port=%d, gin_mode=%s, runtime_type=%s, scheduling_enabled=%v, scheduling_tz=%s",
@@ -152,11 +576,41 @@ func LoadConfig() (*Config, error) {
 	if err := cfg.validate(); err != nil {
 		return nil, err
 	}
+	if err := cfg.applySchedulingPollFloor(); err != nil {
+		return nil, err
+	}
 	fmt.Println("All configuration loaded successfully")
 
 	return cfg, nil
 }
 
+// mergeProfileConfig merges config.<profile>.yaml, found next to the base config file (or in
+// confPath when there is none), over the values read so far. Environment variables still win.
+// A missing profile file is an error, since the profile was requested explicitly.
+func mergeProfileConfig(confPath, profile string) error {
+	if strings.ContainsAny(profile, `/\`) || strings.Contains(profile, "..") {
+		return fmt.Errorf("%s_PROFILE must be a plain name, got %q", ENV_PREFIX, profile)
+	}
+	dir := confPath
+	if used := viper.ConfigFileUsed(); used != "" {
+		dir = filepath.Dir(used)
+	}
+	profilePath := filepath.Join(dir, "config."+profile+".yaml")
+
+	f, err := os.Open(profilePath)
+	if err != nil {
+		return fmt.Errorf("profile %q config file error: %w", profile, err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	if err := viper.MergeConfig(f); err != nil {
+		return fmt.Errorf("profile %q config file error: %w", profile, err)
+	}
+	logger.WithComponent("config").Infof("Merged profile config: %s", profilePath)
+	return nil
+}
+
 func dataFileExistenceCheck() error {
 	fileStorePath := viper.GetString("data.file_path")
 	logger.WithComponent("config").Infof("Using data file: %s", fileStorePath)
@@ -203,9 +657,112 @@ func (c *Config) validate() error {
 	if c.Data.SchedulingPoll <= 0 {
 		return fmt.Errorf("data.scheduling_poll_interval_secs must be positive")
 	}
+	if c.Data.MaxLoadForStart < 0 {
+		return fmt.Errorf("data.max_load_for_start must not be negative")
+	}
+	if c.Data.PlanMaxDays < 0 {
+		return fmt.Errorf("data.plan_max_days must not be negative")
+	}
+	if c.Data.NextEventsHorizonDays < 0 {
+		return fmt.Errorf("data.next_events_horizon_days must not be negative")
+	}
+	if c.Data.SchedulingChangeDebounce < 0 {
+		return fmt.Errorf("data.scheduling_change_debounce_millis must not be negative")
+	}
+	if c.Data.SchedulingMinTrigger < 0 {
+		return fmt.Errorf("data.scheduling_min_trigger_interval_millis must not be negative")
+	}
+	if c.Data.SchedulingErrorLogDedup < 0 {
+		return fmt.Errorf("data.scheduling_error_log_dedup_secs must not be negative")
+	}
+	if c.Data.StartupProbeInterval < 0 {
+		return fmt.Errorf("data.startup_probe_interval_millis must not be negative")
+	}
+	if c.Data.SaveMode != "" && c.Data.SaveMode != "serial" && c.Data.SaveMode != "coalesce" {
+		return fmt.Errorf("data.save_mode must be 'serial' or 'coalesce'")
+	}
+	if c.Data.ReadReplicaResync < 0 {
+		return fmt.Errorf("data.read_replica_resync_secs must not be negative")
+	}
+	if c.Data.MinSchedulingPoll < 0 {
+		return fmt.Errorf("data.min_scheduling_poll_secs must not be negative")
+	}
+	if c.Data.LoadRetries < 0 {
+		return fmt.Errorf("data.load_retries must not be negative")
+	}
+	if c.Data.LoadRetryDelay < 0 {
+		return fmt.Errorf("data.load_retry_delay_millis must not be negative")
+	}
+	if c.Data.MaxNotesLength < 0 {
+		return fmt.Errorf("data.max_notes_length must not be negative")
+	}
+	if c.Data.MaxMetaKeys < 0 {
+		return fmt.Errorf("data.max_meta_keys must not be negative")
+	}
+	if c.Data.SchedulerShards < 0 {
+		return fmt.Errorf("data.scheduler_shards must not be negative")
+	}
+	if c.Data.JanitorInterval < 0 {
+		return fmt.Errorf("data.janitor_interval_secs must not be negative")
+	}
+	if c.Data.OperationsRetention < 0 {
+		return fmt.Errorf("data.operations_retention_secs must not be negative")
+	}
+	if c.Data.BackupRetention < 0 {
+		return fmt.Errorf("data.backup_retention must not be negative")
+	}
+	if c.Data.AuditMaxEntries < 0 {
+		return fmt.Errorf("data.audit_max_entries must not be negative")
+	}
+	if c.Data.StateStreamInterval < 0 {
+		return fmt.Errorf("data.state_stream_interval_millis must not be negative")
+	}
+	// Every .json file of the tenants directory is a tenant, so it must not hold the main document
+	if c.Data.TenantsDir != "" && filepath.Clean(c.Data.TenantsDir) == filepath.Dir(filepath.Clean(c.Data.FilePath)) {
+		return fmt.Errorf("data.tenants_dir must not be the directory of data.file_path")
+	}
+	if c.Runtime.MetricsRetention < 0 {
+		return fmt.Errorf("runtime.metrics_retention_minutes must not be negative")
+	}
+	if c.Runtime.MetricsMaxSamples < 0 {
+		return fmt.Errorf("runtime.metrics_max_samples must not be negative")
+	}
+	if c.Runtime.ExecTimeout < 0 {
+		return fmt.Errorf("runtime.exec_timeout_secs must not be negative")
+	}
+	if c.Runtime.DiscoveryInterval < 0 {
+		return fmt.Errorf("runtime.discovery_interval_secs must not be negative")
+	}
+	if c.Runtime.DiscoveryInterval > 0 {
+		if c.Runtime.DiscoveryLabelPrefix == "" {
+			return fmt.Errorf("runtime.discovery_label_prefix must not be empty when discovery is enabled")
+		}
+		if c.Misc.RuntimeType != "docker" {
+			return fmt.Errorf("runtime.discovery_interval_secs requires the docker runtime")
+		}
+		// A synced document cannot be changed locally
+		if c.Sync.Enabled() {
+			return fmt.Errorf("runtime.discovery_interval_secs cannot be used with sync")
+		}
+	}
+	if (c.Runtime.WolSSHKeyFile == "") != (c.Runtime.WolSSHKnownHosts == "") {
+		return fmt.Errorf("runtime.wol_ssh_key_file and runtime.wol_ssh_known_hosts must be set together")
+	}
+	if err := c.Runtime.validateHosts(); err != nil {
+		return err
+	}
 	if c.Server.Port < 1 || c.Server.Port > 65535 {
 		return fmt.Errorf("server.port must be a valid TCP port (1-65535)")
 	}
+	if !isValidBindAddress(c.Server.BindAddress) {
+		return fmt.Errorf("server.bind_address must be a valid IP address or host name, got %q", c.Server.BindAddress)
+	}
+	if c.Server.CORSMaxAge < 0 {
+		return fmt.Errorf("server.cors_max_age_secs must not be negative")
+	}
+	if c.Server.CORSAllowCredentials && strings.TrimSpace(c.Server.CORSAllowedOrigins) == "*" {
+		return fmt.Errorf("server.cors_allow_credentials cannot be used with wildcard server.cors_allowed_origins")
+	}
 	if c.Server.ShutDownTimeout <= 0 {
 		return fmt.Errorf("server.shutdown_timeout_secs must be positive")
 	}
@@ -221,15 +778,285 @@ func (c *Config) validate() error {
 	if c.Server.RequestTimeout <= 0 {
 		return fmt.Errorf("server.request_timeout_millis must be positive")
 	}
+	if c.Misc.ReadyCacheTTL < 0 {
+		return fmt.Errorf("misc.ready_cache_ttl_millis must not be negative")
+	}
+	if c.Misc.ReadyCacheNegativeTTL < 0 {
+		return fmt.Errorf("misc.ready_cache_negative_ttl_millis must not be negative")
+	}
+	if c.Misc.ExternalReadyURL != "" {
+		if c.Misc.ExternalReadyTimeout <= 0 {
+			return fmt.Errorf("misc.external_ready_timeout_millis must be positive")
+		}
+		if u, err := url.Parse(c.Misc.ExternalReadyURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("misc.external_ready_url must be an absolute http(s) url")
+		}
+	}
+	if c.Misc.DefaultURLScheme != "" && c.Misc.DefaultURLScheme != "http://" && c.Misc.DefaultURLScheme != "https://" {
+		return fmt.Errorf("misc.default_url_scheme must be 'http://' or 'https://'")
+	}
+	if err := c.Misc.validateInactive(); err != nil {
+		return err
+	}
+	if c.Misc.WaitingStartConcurrency < 0 {
+		return fmt.Errorf("misc.waiting_start_concurrency must not be negative")
+	}
+	if c.Misc.WaitingMaxWait < 0 {
+		return fmt.Errorf("misc.waiting_max_wait_secs must not be negative")
+	}
+	if c.Misc.WaitingProxyTimeout < 0 {
+		return fmt.Errorf("misc.waiting_proxy_timeout_millis must not be negative")
+	}
+	if c.Misc.WaitingProxy && c.Misc.WaitingProxyMaxBodyMB <= 0 {
+		return fmt.Errorf("misc.waiting_proxy_max_body_mb must be positive")
+	}
+	if c.Misc.WaitingRateLimitPerMinute < 0 {
+		return fmt.Errorf("misc.waiting_rate_limit_per_minute must not be negative")
+	}
+	if c.Misc.WaitingRateLimitBurst < 0 {
+		return fmt.Errorf("misc.waiting_rate_limit_burst must not be negative")
+	}
+	if c.Misc.WaitingCaptchaURL != "" {
+		if u, err := url.Parse(c.Misc.WaitingCaptchaURL); err != nil || (u.Scheme == "" && !strings.HasPrefix(u.Path, "/")) {
+			return fmt.Errorf("misc.waiting_captcha_url must be an absolute URL or path, got %q", c.Misc.WaitingCaptchaURL)
+		}
+	}
+	for _, proxy := range c.Misc.WaitingTrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			return fmt.Errorf("misc.waiting_trusted_proxies: %q is not an IP or a CIDR", proxy)
+		}
+	}
+	if c.Misc.WaitingRuntimeUnavailable != "" && c.Misc.WaitingRuntimeUnavailable != "error" && c.Misc.WaitingRuntimeUnavailable != "wait" {
+		return fmt.Errorf("misc.waiting_runtime_unavailable must be 'error' or 'wait'")
+	}
+	if c.Misc.LogFormat != "" && c.Misc.LogFormat != "text" && c.Misc.LogFormat != "json" {
+		return fmt.Errorf("misc.log_format must be 'text' or 'json'")
+	}
+	if c.Misc.LogMaxSizeMB < 0 {
+		return fmt.Errorf("misc.log_max_size_mb must not be negative")
+	}
+	if c.Misc.LogMaxAge < 0 {
+		return fmt.Errorf("misc.log_max_age_days must not be negative")
+	}
+	if c.Misc.LogMaxBackups < 0 {
+		return fmt.Errorf("misc.log_max_backups must not be negative")
+	}
 	if c.Misc.SchedulingTZ != "" && c.Misc.SchedulingTZ != "Local" {
 		if _, err := time.LoadLocation(c.Misc.SchedulingTZ); err != nil {
 			return fmt.Errorf("misc.scheduling_timezone is invalid: %w", err)
 		}
 	}
+	if err := c.Webhooks.validate(); err != nil {
+		return err
+	}
+	if err := c.Auth.validate(); err != nil {
+		return err
+	}
+	if err := c.Sync.validate(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validate checks the sync source, exactly one of url and git_repo, and its timings.
+func (s SyncConfig) validate() error {
+	if s.Interval < 0 {
+		return fmt.Errorf("sync.interval_secs must not be negative")
+	}
+	if s.Timeout < 0 {
+		return fmt.Errorf("sync.timeout_secs must not be negative")
+	}
+	if !s.Enabled() {
+		return nil
+	}
+	if s.URL != "" && s.GitRepo != "" {
+		return fmt.Errorf("sync.url and sync.git_repo are mutually exclusive")
+	}
+	if s.Interval == 0 || s.Timeout == 0 {
+		return fmt.Errorf("sync.interval_secs and sync.timeout_secs must be positive when sync is enabled")
+	}
+	if s.URL != "" {
+		if u, err := url.Parse(s.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("sync.url must be an absolute http(s) url")
+		}
+		return nil
+	}
+	if s.GitBranch == "" {
+		return fmt.Errorf("sync.git_branch must not be empty")
+	}
+	if s.GitPath == "" || filepath.IsAbs(s.GitPath) || !filepath.IsLocal(s.GitPath) {
+		return fmt.Errorf("sync.git_path must be a relative path inside the repository")
+	}
+	return nil
+}
+
+// validateHosts checks the remote hosts: unique non-empty names, absolute http(s) urls and a
+// positive timeout.
+func (r RuntimeConfig) validateHosts() error {
+	if r.RemoteTimeout < 0 {
+		return fmt.Errorf("runtime.remote_timeout_secs must not be negative")
+	}
+	if len(r.Hosts) > 0 && r.RemoteTimeout == 0 {
+		return fmt.Errorf("runtime.remote_timeout_secs must be positive when remote hosts are configured")
+	}
+	seen := make(map[string]bool, len(r.Hosts))
+	for i, host := range r.Hosts {
+		if strings.TrimSpace(host.Name) == "" {
+			return fmt.Errorf("runtime.hosts[%d].name must not be empty", i)
+		}
+		if seen[host.Name] {
+			return fmt.Errorf("runtime.hosts[%d]: duplicate host %q", i, host.Name)
+		}
+		seen[host.Name] = true
+		if u, err := url.Parse(host.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("runtime.hosts[%d].url must be an absolute http(s) url", i)
+		}
+	}
+	return nil
+}
+
+// validate checks every webhook: an absolute http(s) url and known event types.
+func (w WebhooksConfig) validate() error {
+	if w.Timeout < 0 {
+		return fmt.Errorf("webhooks.timeout_millis must not be negative")
+	}
+	if len(w.Hooks) > 0 && w.Timeout == 0 {
+		return fmt.Errorf("webhooks.timeout_millis must be positive when webhooks are configured")
+	}
+	for i, hook := range w.Hooks {
+		if u, err := url.Parse(hook.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("webhooks.hooks[%d].url must be an absolute http(s) url", i)
+		}
+		for _, t := range hook.Events {
+			if !events.IsKnown(events.Type(t)) {
+				return fmt.Errorf("webhooks.hooks[%d].events: unknown event type %q", i, t)
+			}
+		}
+	}
+	return nil
+}
+
+// validate rejects empty or duplicate API keys, users without name or password or defined twice,
+// unknown roles and a waiting user header that is not a header name.
+func (a AuthConfig) validate() error {
+	if strings.ContainsAny(a.WaitingUserHeader, " \t:") {
+		return fmt.Errorf("auth.waiting_user_header must be a header name, got %q", a.WaitingUserHeader)
+	}
+	for i, key := range a.APIKeys {
+		if strings.TrimSpace(key) == "" {
+			return fmt.Errorf("auth.api_keys[%d] must not be empty", i)
+		}
+	}
+	keys := make(map[string]bool, len(a.APIKeys)+len(a.Keys))
+	for _, key := range a.APIKeys {
+		keys[key] = true
+	}
+	for i, k := range a.Keys {
+		if strings.TrimSpace(k.Key) == "" {
+			return fmt.Errorf("auth.keys[%d] must not be empty", i)
+		}
+		if keys[k.Key] {
+			return fmt.Errorf("auth.keys[%d] is defined twice", i)
+		}
+		keys[k.Key] = true
+		if !validAuthRole(k.Role) {
+			return fmt.Errorf("auth.keys[%d].role must be 'viewer', 'operator' or 'admin'", i)
+		}
+	}
+	seen := make(map[string]bool, len(a.Users))
+	for i, u := range a.Users {
+		if u.Username == "" || u.Password == "" {
+			return fmt.Errorf("auth.users[%d] needs a username and a password", i)
+		}
+		if seen[u.Username] {
+			return fmt.Errorf("auth.users: duplicate username %q", u.Username)
+		}
+		seen[u.Username] = true
+		if !validAuthRole(u.Role) {
+			return fmt.Errorf("auth.users[%d].role must be 'viewer', 'operator' or 'admin'", i)
+		}
+	}
+	return nil
+}
+
+// validAuthRole reports whether role is a known role or empty (admin).
+func validAuthRole(role string) bool {
+	return role == "" || role == "viewer" || role == "operator" || role == "admin"
+}
+
+// applySchedulingPollFloor guards against a scheduling poll interval so short that the scheduler
+// keeps hammering the store and the runtime: below data.min_scheduling_poll_secs the interval is
+// clamped to the floor with a warning, or rejected when data.min_scheduling_poll_strict is set.
+func (c *Config) applySchedulingPollFloor() error {
+	floor := c.Data.MinSchedulingPoll
+	if floor <= 0 || c.Data.SchedulingPoll >= floor {
+		return nil
+	}
+	if c.Data.MinSchedulingPollStrict {
+		return fmt.Errorf("data.scheduling_poll_interval_secs (%v) is below data.min_scheduling_poll_secs (%v)", c.Data.SchedulingPoll, floor)
+	}
+	logger.WithComponent("config").Warnf("!!! data.scheduling_poll_interval_secs (%v) is below the minimum of %v, using %v instead (set data.min_scheduling_poll_secs to change the floor)",
+		c.Data.SchedulingPoll, floor, floor)
+	c.Data.SchedulingPoll = floor
+	return nil
+}
+
+// ListenAddr composes the listen address for port on the configured bind address ("host:port",
+// ":port" when listening on all interfaces).
+func (s ServerConfig) ListenAddr(port int) string {
+	return net.JoinHostPort(s.BindAddress, strconv.Itoa(port))
+}
+
+// isValidBindAddress accepts an empty address, an IPv4/IPv6 address or a DNS host name.
+func isValidBindAddress(addr string) bool {
+	if addr == "" || net.ParseIP(addr) != nil {
+		return true
+	}
+	if len(addr) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(addr, ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, r := range label {
+			if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
 
+// validateInactive checks misc.inactive_status (a redirect or client error) and misc.inactive_redirect_url.
+// A 3xx status needs a redirect URL; an unset (zero) status keeps the default.
+func (m MiscConfig) validateInactive() error {
+	isRedirect := m.InactiveStatus >= http.StatusMultipleChoices && m.InactiveStatus < http.StatusBadRequest
+	if m.InactiveStatus != 0 && (m.InactiveStatus < http.StatusMultipleChoices || m.InactiveStatus >= http.StatusInternalServerError || m.InactiveStatus == http.StatusNotModified) {
+		return fmt.Errorf("misc.inactive_status must be a 3xx redirect or 4xx client error status, got %d", m.InactiveStatus)
+	}
+	if m.InactiveRedirectURL == "" {
+		if isRedirect {
+			return fmt.Errorf("misc.inactive_status %d requires misc.inactive_redirect_url", m.InactiveStatus)
+		}
+		return nil
+	}
+	u, err := url.Parse(m.InactiveRedirectURL)
+	if err != nil || (u.Scheme == "" && !strings.HasPrefix(u.Path, "/")) {
+		return fmt.Errorf("misc.inactive_redirect_url must be an absolute URL or path, got %q", m.InactiveRedirectURL)
+	}
 	return nil
 }
 
+// SchedulingLocation resolves the scheduling timezone; empty or "Local" means time.Local.
+func (m MiscConfig) SchedulingLocation() (*time.Location, error) {
+	if m.SchedulingTZ == "" || m.SchedulingTZ == "Local" {
+		return time.Local, nil
+	}
+	return time.LoadLocation(m.SchedulingTZ)
+}
+
 // getEnvOrDefault returns env var value or default
 func getEnvOrDefault(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -1,9 +1,17 @@
 package config
 
 import (
+	"bytes"
+	"net"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/bassista/go_spin/internal/logger"
+	"github.com/spf13/viper"
 )
 
 func TestConfig_Validate_Valid(t *testing.T) {
@@ -615,3 +623,629 @@ func TestLoadConfig_UsesExistingDataFile(t *testing.T) {
 		t.Errorf("expected '%s', got '%s'", existingContent, string(content))
 	}
 }
+
+func TestConfig_Validate_CORSCredentialsWithWildcard(t *testing.T) {
+	cfg := &Config{
+		Server: ServerConfig{
+			Port:                 8080,
+			ReadTimeout:          10 * time.Second,
+			WriteTimeout:         10 * time.Second,
+			IdleTimeout:          120 * time.Second,
+			ShutDownTimeout:      5 * time.Second,
+			RequestTimeout:       1000 * time.Millisecond,
+			CORSAllowedOrigins:   "*",
+			CORSAllowCredentials: true,
+		},
+		Data: DataConfig{
+			FilePath:                 "/tmp/config.json",
+			PersistInterval:          5 * time.Second,
+			SchedulingPoll:           30 * time.Second,
+			RefreshIntervalSecs:      60,
+			StatsRefreshIntervalSecs: 120,
+		},
+		Misc: MiscConfig{
+			SchedulingTZ: "Local",
+		},
+	}
+
+	if err := cfg.validate(); err == nil {
+		t.Error("expected error for credentials with wildcard origin")
+	}
+
+	cfg.Server.CORSAllowedOrigins = "http://allowed.com"
+	if err := cfg.validate(); err != nil {
+		t.Errorf("expected credentials with specific origins to be valid, got: %v", err)
+	}
+}
+
+func TestConfig_Validate_DefaultURLScheme(t *testing.T) {
+	cfg := &Config{
+		Server: ServerConfig{
+			Port:            8080,
+			ReadTimeout:     10 * time.Second,
+			WriteTimeout:    10 * time.Second,
+			IdleTimeout:     120 * time.Second,
+			ShutDownTimeout: 5 * time.Second,
+			RequestTimeout:  1000 * time.Millisecond,
+		},
+		Data: DataConfig{
+			FilePath:                 "/tmp/config.json",
+			PersistInterval:          5 * time.Second,
+			SchedulingPoll:           30 * time.Second,
+			RefreshIntervalSecs:      60,
+			StatsRefreshIntervalSecs: 120,
+		},
+		Misc: MiscConfig{
+			SchedulingTZ:     "Local",
+			DefaultURLScheme: "ftp://",
+		},
+	}
+
+	if err := cfg.validate(); err == nil {
+		t.Error("expected error for unsupported default url scheme")
+	}
+
+	cfg.Misc.DefaultURLScheme = "https://"
+	if err := cfg.validate(); err != nil {
+		t.Errorf("expected https:// to be valid, got: %v", err)
+	}
+}
+
+func TestConfig_Validate_TenantsDir(t *testing.T) {
+	cfg := &Config{
+		Server: ServerConfig{
+			Port:            8080,
+			ReadTimeout:     10 * time.Second,
+			WriteTimeout:    10 * time.Second,
+			IdleTimeout:     120 * time.Second,
+			ShutDownTimeout: 5 * time.Second,
+			RequestTimeout:  1000 * time.Millisecond,
+		},
+		Data: DataConfig{
+			FilePath:                 "/data/config.json",
+			PersistInterval:          5 * time.Second,
+			SchedulingPoll:           30 * time.Second,
+			RefreshIntervalSecs:      60,
+			StatsRefreshIntervalSecs: 120,
+			TenantsDir:               "/data/",
+		},
+		Misc: MiscConfig{
+			SchedulingTZ: "Local",
+		},
+	}
+
+	if err := cfg.validate(); err == nil {
+		t.Error("expected error for a tenants dir holding the main data file")
+	}
+
+	cfg.Data.TenantsDir = "/data/tenants"
+	if err := cfg.validate(); err != nil {
+		t.Errorf("expected a separate tenants dir to be valid, got: %v", err)
+	}
+}
+
+func TestConfig_Validate_Discovery(t *testing.T) {
+	newConfig := func(runtime RuntimeConfig, runtimeType string, sync SyncConfig) *Config {
+		return &Config{
+			Server: ServerConfig{
+				Port:            8080,
+				ReadTimeout:     10 * time.Second,
+				WriteTimeout:    10 * time.Second,
+				IdleTimeout:     120 * time.Second,
+				ShutDownTimeout: 5 * time.Second,
+				RequestTimeout:  1000 * time.Millisecond,
+			},
+			Data: DataConfig{
+				FilePath:                 "/data/config.json",
+				PersistInterval:          5 * time.Second,
+				SchedulingPoll:           30 * time.Second,
+				RefreshIntervalSecs:      60,
+				StatsRefreshIntervalSecs: 120,
+			},
+			Runtime: runtime,
+			Misc:    MiscConfig{SchedulingTZ: "Local", RuntimeType: runtimeType},
+			Sync:    sync,
+		}
+	}
+	enabled := RuntimeConfig{DiscoveryInterval: time.Minute, DiscoveryLabelPrefix: "go-spin"}
+	synced := SyncConfig{URL: "https://git.local/raw/config.json", Interval: time.Minute, Timeout: time.Second}
+	tests := []struct {
+		name    string
+		cfg     *Config
+		wantErr bool
+	}{
+		{"disabled", newConfig(RuntimeConfig{}, "memory", SyncConfig{}), false},
+		{"enabled", newConfig(enabled, "docker", SyncConfig{}), false},
+		{"negative interval", newConfig(RuntimeConfig{DiscoveryInterval: -time.Second}, "docker", SyncConfig{}), true},
+		{"empty prefix", newConfig(RuntimeConfig{DiscoveryInterval: time.Minute}, "docker", SyncConfig{}), true},
+		{"memory runtime", newConfig(enabled, "memory", SyncConfig{}), true},
+		{"synced document", newConfig(enabled, "docker", synced), true},
+	}
+	for _, tt := range tests {
+		if err := tt.cfg.validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error %v, got %v", tt.name, tt.wantErr, err)
+		}
+	}
+}
+
+func TestRuntimeConfig_ValidateHosts(t *testing.T) {
+	nas := RemoteHostConfig{Name: "nas", URL: "http://nas.lan:8086", Token: "secret"}
+	tests := []struct {
+		name    string
+		cfg     RuntimeConfig
+		wantErr bool
+	}{
+		{"no hosts", RuntimeConfig{}, false},
+		{"valid", RuntimeConfig{RemoteTimeout: time.Second, Hosts: []RemoteHostConfig{nas, {Name: "pi", URL: "https://pi.lan"}}}, false},
+		{"zero timeout", RuntimeConfig{Hosts: []RemoteHostConfig{nas}}, true},
+		{"negative timeout", RuntimeConfig{RemoteTimeout: -time.Second}, true},
+		{"empty name", RuntimeConfig{RemoteTimeout: time.Second, Hosts: []RemoteHostConfig{{URL: "http://nas.lan"}}}, true},
+		{"duplicate name", RuntimeConfig{RemoteTimeout: time.Second, Hosts: []RemoteHostConfig{nas, nas}}, true},
+		{"relative url", RuntimeConfig{RemoteTimeout: time.Second, Hosts: []RemoteHostConfig{{Name: "nas", URL: "nas.lan:8086"}}}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.cfg.validateHosts(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error %v, got %v", tt.name, tt.wantErr, err)
+		}
+	}
+}
+
+func TestServerConfig_ListenAddr(t *testing.T) {
+	tests := []struct {
+		bind string
+		port int
+		want string
+	}{
+		{"", 8084, ":8084"},
+		{"127.0.0.1", 8085, "127.0.0.1:8085"},
+		{"localhost", 8084, "localhost:8084"},
+		{"::1", 8084, "[::1]:8084"},
+	}
+	for _, tt := range tests {
+		if got := (ServerConfig{BindAddress: tt.bind}).ListenAddr(tt.port); got != tt.want {
+			t.Errorf("ListenAddr(%q, %d) = %q, want %q", tt.bind, tt.port, got, tt.want)
+		}
+	}
+}
+
+func TestServerConfig_ListenAddr_BindsOnlyThere(t *testing.T) {
+	ln, err := net.Listen("tcp", ServerConfig{BindAddress: "127.0.0.1"}.ListenAddr(0))
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer func() { _ = ln.Close() }()
+
+	addr, ok := ln.Addr().(*net.TCPAddr)
+	if !ok || !addr.IP.IsLoopback() {
+		t.Errorf("expected a loopback listener, got %v", ln.Addr())
+	}
+}
+
+func TestIsValidBindAddress(t *testing.T) {
+	for addr, want := range map[string]bool{
+		"":               true,
+		"0.0.0.0":        true,
+		"192.168.1.10":   true,
+		"::1":            true,
+		"localhost":      true,
+		"my-host.lan":    true,
+		"not a host":     false,
+		"host:8080":      false,
+		"-bad.example":   false,
+		"trailing.dot.":  false,
+		"under_score.io": false,
+	} {
+		if got := isValidBindAddress(addr); got != want {
+			t.Errorf("isValidBindAddress(%q) = %v, want %v", addr, got, want)
+		}
+	}
+}
+
+func TestConfig_ApplySchedulingPollFloor_Clamps(t *testing.T) {
+	var buf bytes.Buffer
+	logger.Logger.SetOutput(&buf)
+	defer logger.Logger.SetOutput(os.Stdout)
+
+	cfg := &Config{Data: DataConfig{SchedulingPoll: time.Second, MinSchedulingPoll: 5 * time.Second}}
+	if err := cfg.applySchedulingPollFloor(); err != nil {
+		t.Fatalf("expected clamping without error, got: %v", err)
+	}
+	if cfg.Data.SchedulingPoll != 5*time.Second {
+		t.Errorf("expected poll interval clamped to 5s, got %v", cfg.Data.SchedulingPoll)
+	}
+	if !strings.Contains(buf.String(), "below the minimum") {
+		t.Errorf("expected a warning to be logged, got %q", buf.String())
+	}
+}
+
+func TestConfig_ApplySchedulingPollFloor_Strict(t *testing.T) {
+	cfg := &Config{Data: DataConfig{SchedulingPoll: time.Second, MinSchedulingPoll: 5 * time.Second, MinSchedulingPollStrict: true}}
+	if err := cfg.applySchedulingPollFloor(); err == nil {
+		t.Fatal("expected error in strict mode")
+	}
+	if cfg.Data.SchedulingPoll != time.Second {
+		t.Errorf("expected poll interval untouched in strict mode, got %v", cfg.Data.SchedulingPoll)
+	}
+}
+
+func TestConfig_ApplySchedulingPollFloor_AboveFloorOrDisabled(t *testing.T) {
+	cfg := &Config{Data: DataConfig{SchedulingPoll: 30 * time.Second, MinSchedulingPoll: 5 * time.Second, MinSchedulingPollStrict: true}}
+	if err := cfg.applySchedulingPollFloor(); err != nil || cfg.Data.SchedulingPoll != 30*time.Second {
+		t.Errorf("expected interval above the floor to be kept, got %v (err %v)", cfg.Data.SchedulingPoll, err)
+	}
+
+	cfg = &Config{Data: DataConfig{SchedulingPoll: time.Second}}
+	if err := cfg.applySchedulingPollFloor(); err != nil || cfg.Data.SchedulingPoll != time.Second {
+		t.Errorf("expected a zero floor to disable the guard, got %v (err %v)", cfg.Data.SchedulingPoll, err)
+	}
+}
+
+func TestLoadConfig_SchedulingPollBelowFloor(t *testing.T) {
+	tempDir := t.TempDir()
+
+	_ = os.Setenv("GO_SPIN_CONFIG_PATH", tempDir)
+	_ = os.Setenv("GO_SPIN_DATA_FILE_PATH", tempDir+"/data/config.json")
+	_ = os.Setenv("GO_SPIN_DATA_SCHEDULING_POLL_INTERVAL_SECS", "1")
+	defer func() {
+		_ = os.Unsetenv("GO_SPIN_CONFIG_PATH")
+		_ = os.Unsetenv("GO_SPIN_DATA_FILE_PATH")
+		_ = os.Unsetenv("GO_SPIN_DATA_SCHEDULING_POLL_INTERVAL_SECS")
+		_ = os.Unsetenv("GO_SPIN_DATA_MIN_SCHEDULING_POLL_STRICT")
+	}()
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("expected no error loading config, got: %v", err)
+	}
+	if cfg.Data.SchedulingPoll != 5*time.Second {
+		t.Errorf("expected poll interval clamped to the default 5s floor, got %v", cfg.Data.SchedulingPoll)
+	}
+
+	_ = os.Setenv("GO_SPIN_DATA_MIN_SCHEDULING_POLL_STRICT", "true")
+	if _, err := LoadConfig(); err == nil {
+		t.Error("expected error with data.min_scheduling_poll_strict enabled")
+	}
+}
+
+func TestMiscConfig_ValidateInactive(t *testing.T) {
+	tests := []struct {
+		status      int
+		redirectURL string
+		wantErr     bool
+	}{
+		{0, "", false},
+		{403, "", false},
+		{404, "", false},
+		{302, "https://example.com/landing", false},
+		{403, "/landing", false},
+		{302, "", true},
+		{304, "https://example.com", true},
+		{200, "", true},
+		{500, "", true},
+		{403, "landing", true},
+	}
+	for _, tt := range tests {
+		m := MiscConfig{InactiveStatus: tt.status, InactiveRedirectURL: tt.redirectURL}
+		if err := m.validateInactive(); (err != nil) != tt.wantErr {
+			t.Errorf("status %d, url %q: expected error %v, got %v", tt.status, tt.redirectURL, tt.wantErr, err)
+		}
+	}
+}
+
+func TestWebhooksConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     WebhooksConfig
+		wantErr bool
+	}{
+		{"no hooks", WebhooksConfig{}, false},
+		{"valid", WebhooksConfig{Timeout: time.Second, Hooks: []WebhookConfig{{URL: "https://ha.local/api/webhook/x", Events: []string{"container.started"}}}}, false},
+		{"every event", WebhooksConfig{Timeout: time.Second, Hooks: []WebhookConfig{{URL: "http://ha.local"}}}, false},
+		{"relative url", WebhooksConfig{Timeout: time.Second, Hooks: []WebhookConfig{{URL: "/hook"}}}, true},
+		{"unknown event", WebhooksConfig{Timeout: time.Second, Hooks: []WebhookConfig{{URL: "http://ha.local", Events: []string{"container.exploded"}}}}, true},
+		{"zero timeout", WebhooksConfig{Hooks: []WebhookConfig{{URL: "http://ha.local"}}}, true},
+		{"negative timeout", WebhooksConfig{Timeout: -time.Second}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.cfg.validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error %v, got %v", tt.name, tt.wantErr, err)
+		}
+	}
+}
+
+func TestAuthConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     AuthConfig
+		wantErr bool
+	}{
+		{"disabled", AuthConfig{}, false},
+		{"api key", AuthConfig{APIKeys: []string{"k3y"}}, false},
+		{"users", AuthConfig{Users: []AuthUser{{Username: "admin", Password: "secret"}, {Username: "viewer", Password: "$2a$10$abc"}}}, false},
+		{"empty api key", AuthConfig{APIKeys: []string{" "}}, true},
+		{"missing username", AuthConfig{Users: []AuthUser{{Password: "secret"}}}, true},
+		{"missing password", AuthConfig{Users: []AuthUser{{Username: "admin"}}}, true},
+		{"duplicate username", AuthConfig{Users: []AuthUser{{Username: "admin", Password: "a"}, {Username: "admin", Password: "b"}}}, true},
+		{"keys with roles", AuthConfig{APIKeys: []string{"k1"}, Keys: []AuthKey{{Key: "k2", Role: "viewer"}, {Key: "k3"}}}, false},
+		{"user role", AuthConfig{Users: []AuthUser{{Username: "dash", Password: "x", Role: "viewer"}}}, false},
+		{"unknown user role", AuthConfig{Users: []AuthUser{{Username: "dash", Password: "x", Role: "root"}}}, true},
+		{"unknown key role", AuthConfig{Keys: []AuthKey{{Key: "k", Role: "root"}}}, true},
+		{"empty key", AuthConfig{Keys: []AuthKey{{Role: "viewer"}}}, true},
+		{"duplicate key", AuthConfig{APIKeys: []string{"k"}, Keys: []AuthKey{{Key: "k", Role: "viewer"}}}, true},
+		{"waiting user header", AuthConfig{WaitingUserHeader: "Remote-User"}, false},
+		{"invalid waiting user header", AuthConfig{WaitingUserHeader: "Remote-User: x"}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.cfg.validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error %v, got %v", tt.name, tt.wantErr, err)
+		}
+	}
+}
+
+func TestSyncConfig_Validate(t *testing.T) {
+	git := SyncConfig{GitRepo: "https://git.local/ops.git", GitBranch: "main", GitPath: "go_spin/config.json", Interval: time.Minute, Timeout: time.Second}
+	tests := []struct {
+		name    string
+		cfg     SyncConfig
+		wantErr bool
+	}{
+		{"disabled", SyncConfig{}, false},
+		{"url", SyncConfig{URL: "https://git.local/raw/config.json", Interval: time.Minute, Timeout: time.Second}, false},
+		{"git", git, false},
+		{"relative url", SyncConfig{URL: "/config.json", Interval: time.Minute, Timeout: time.Second}, true},
+		{"url and git", SyncConfig{URL: "https://git.local/raw/config.json", GitRepo: git.GitRepo, GitBranch: "main", GitPath: "config.json", Interval: time.Minute, Timeout: time.Second}, true},
+		{"zero interval", SyncConfig{URL: "https://git.local/raw/config.json", Timeout: time.Second}, true},
+		{"negative timeout", SyncConfig{Timeout: -time.Second}, true},
+		{"git path outside the repository", SyncConfig{GitRepo: git.GitRepo, GitBranch: "main", GitPath: "../config.json", Interval: time.Minute, Timeout: time.Second}, true},
+		{"absolute git path", SyncConfig{GitRepo: git.GitRepo, GitBranch: "main", GitPath: "/config.json", Interval: time.Minute, Timeout: time.Second}, true},
+		{"empty git branch", SyncConfig{GitRepo: git.GitRepo, GitPath: "config.json", Interval: time.Minute, Timeout: time.Second}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.cfg.validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error %v, got %v", tt.name, tt.wantErr, err)
+		}
+	}
+}
+
+func TestAuthConfig_KeyRoles(t *testing.T) {
+	auth := AuthConfig{APIKeys: []string{"k1"}, Keys: []AuthKey{{Key: "k2", Role: "viewer"}, {Key: "k3"}}}
+	roles := auth.KeyRoles()
+	want := map[string]string{"k1": "admin", "k2": "viewer", "k3": "admin"}
+	if !reflect.DeepEqual(roles, want) {
+		t.Errorf("expected %v, got %v", want, roles)
+	}
+	if role := (AuthUser{Username: "u", Password: "p"}).RoleOrDefault(); role != "admin" {
+		t.Errorf("expected default user role admin, got %s", role)
+	}
+}
+
+func TestLoadConfig_ReadsWebhooks(t *testing.T) {
+	tempDir := t.TempDir()
+	yaml := "webhooks:\n  hooks:\n    - url: http://ha.local/api/webhook/go_spin\n      secret: s3cret\n      events: [container.started]\n"
+	if err := os.WriteFile(filepath.Join(tempDir, "config.yaml"), []byte(yaml), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	_ = os.Setenv("GO_SPIN_CONFIG_PATH", tempDir)
+	_ = os.Setenv("GO_SPIN_DATA_FILE_PATH", tempDir+"/data/config.json")
+	defer func() {
+		_ = os.Unsetenv("GO_SPIN_CONFIG_PATH")
+		_ = os.Unsetenv("GO_SPIN_DATA_FILE_PATH")
+		viper.Reset()
+	}()
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("expected no error loading config, got: %v", err)
+	}
+	if len(cfg.Webhooks.Hooks) != 1 {
+		t.Fatalf("expected one webhook, got %+v", cfg.Webhooks.Hooks)
+	}
+	hook := cfg.Webhooks.Hooks[0]
+	if hook.URL != "http://ha.local/api/webhook/go_spin" || hook.Secret != "s3cret" || len(hook.Events) != 1 || hook.Events[0] != "container.started" {
+		t.Errorf("unexpected webhook %+v", hook)
+	}
+	if cfg.Webhooks.Timeout != 5*time.Second {
+		t.Errorf("expected default timeout 5s, got %v", cfg.Webhooks.Timeout)
+	}
+}
+
+func TestLoadConfig_ProfileOverridesBase(t *testing.T) {
+	tempDir := t.TempDir()
+	base := "data:\n  plan_max_days: 10\n  max_load_for_start: 2.5\n"
+	profile := "data:\n  plan_max_days: 20\n"
+	if err := os.WriteFile(filepath.Join(tempDir, "config.yaml"), []byte(base), 0644); err != nil {
+		t.Fatalf("failed to write base config: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "config.prod.yaml"), []byte(profile), 0644); err != nil {
+		t.Fatalf("failed to write profile config: %v", err)
+	}
+
+	_ = os.Setenv("GO_SPIN_CONFIG_PATH", tempDir)
+	_ = os.Setenv("GO_SPIN_DATA_FILE_PATH", tempDir+"/data/config.json")
+	_ = os.Setenv("GO_SPIN_PROFILE", "prod")
+	defer func() {
+		_ = os.Unsetenv("GO_SPIN_CONFIG_PATH")
+		_ = os.Unsetenv("GO_SPIN_DATA_FILE_PATH")
+		_ = os.Unsetenv("GO_SPIN_PROFILE")
+		// The files read here must not leak into later LoadConfig calls through the global viper.
+		viper.Reset()
+	}()
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("expected no error loading config, got: %v", err)
+	}
+	if cfg.Data.PlanMaxDays != 20 {
+		t.Errorf("expected profile value 20 for plan_max_days, got %d", cfg.Data.PlanMaxDays)
+	}
+	if cfg.Data.MaxLoadForStart != 2.5 {
+		t.Errorf("expected base value 2.5 for max_load_for_start, got %v", cfg.Data.MaxLoadForStart)
+	}
+}
+
+func TestLoadConfig_MissingProfileFile(t *testing.T) {
+	tempDir := t.TempDir()
+
+	_ = os.Setenv("GO_SPIN_CONFIG_PATH", tempDir)
+	_ = os.Setenv("GO_SPIN_DATA_FILE_PATH", tempDir+"/data/config.json")
+	_ = os.Setenv("GO_SPIN_PROFILE", "staging")
+	defer func() {
+		_ = os.Unsetenv("GO_SPIN_CONFIG_PATH")
+		_ = os.Unsetenv("GO_SPIN_DATA_FILE_PATH")
+		_ = os.Unsetenv("GO_SPIN_PROFILE")
+		viper.Reset()
+	}()
+
+	if _, err := LoadConfig(); err == nil {
+		t.Error("expected error for a missing profile config file")
+	}
+}
+
+func TestLoadConfig_LogOutput(t *testing.T) {
+	tempDir := t.TempDir()
+	yaml := "misc:\n  log_format: JSON\n  log_file: /var/log/go_spin.log\n  log_max_age_days: 2\n"
+	if err := os.WriteFile(filepath.Join(tempDir, "config.yaml"), []byte(yaml), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	_ = os.Setenv("GO_SPIN_CONFIG_PATH", tempDir)
+	_ = os.Setenv("GO_SPIN_DATA_FILE_PATH", tempDir+"/data/config.json")
+	defer func() {
+		_ = os.Unsetenv("GO_SPIN_CONFIG_PATH")
+		_ = os.Unsetenv("GO_SPIN_DATA_FILE_PATH")
+		viper.Reset()
+	}()
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("expected no error loading config, got: %v", err)
+	}
+	if cfg.Misc.LogFormat != "json" || cfg.Misc.LogFile != "/var/log/go_spin.log" {
+		t.Errorf("unexpected log output %q %q", cfg.Misc.LogFormat, cfg.Misc.LogFile)
+	}
+	if cfg.Misc.LogMaxAge != 48*time.Hour {
+		t.Errorf("expected max age 48h, got %v", cfg.Misc.LogMaxAge)
+	}
+	if cfg.Misc.LogMaxSizeMB != 100 || cfg.Misc.LogMaxBackups != 5 {
+		t.Errorf("expected default rotation 100MB/5 backups, got %d/%d", cfg.Misc.LogMaxSizeMB, cfg.Misc.LogMaxBackups)
+	}
+}
+
+func TestConfig_Validate_LogOutput(t *testing.T) {
+	tests := []struct {
+		name    string
+		misc    MiscConfig
+		wantErr bool
+	}{
+		{"defaults", MiscConfig{}, false},
+		{"json", MiscConfig{LogFormat: "json", LogMaxSizeMB: 10, LogMaxBackups: 3}, false},
+		{"unknown format", MiscConfig{LogFormat: "xml"}, true},
+		{"negative size", MiscConfig{LogMaxSizeMB: -1}, true},
+		{"negative age", MiscConfig{LogMaxAge: -time.Hour}, true},
+		{"negative backups", MiscConfig{LogMaxBackups: -1}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				Server: ServerConfig{
+					Port:            8080,
+					ReadTimeout:     10 * time.Second,
+					WriteTimeout:    10 * time.Second,
+					IdleTimeout:     120 * time.Second,
+					ShutDownTimeout: 5 * time.Second,
+					RequestTimeout:  1000 * time.Millisecond,
+				},
+				Data: DataConfig{
+					FilePath:                 "/tmp/config.json",
+					PersistInterval:          5 * time.Second,
+					SchedulingPoll:           30 * time.Second,
+					RefreshIntervalSecs:      60,
+					StatsRefreshIntervalSecs: 120,
+				},
+				Misc: tt.misc,
+			}
+			if err := cfg.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_Validate_WaitingRateLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		misc    MiscConfig
+		wantErr bool
+	}{
+		{"disabled", MiscConfig{}, false},
+		{"limit", MiscConfig{WaitingRateLimitPerMinute: 30, WaitingRateLimitBurst: 5, WaitingCaptchaURL: "https://auth.lan/challenge", WaitingTrustedProxies: []string{"10.0.0.1", "172.16.0.0/12"}}, false},
+		{"negative limit", MiscConfig{WaitingRateLimitPerMinute: -1}, true},
+		{"negative burst", MiscConfig{WaitingRateLimitBurst: -1}, true},
+		{"relative captcha url", MiscConfig{WaitingCaptchaURL: "challenge"}, true},
+		{"invalid proxy", MiscConfig{WaitingTrustedProxies: []string{"proxy.lan"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				Server: ServerConfig{
+					Port:            8080,
+					ReadTimeout:     10 * time.Second,
+					WriteTimeout:    10 * time.Second,
+					IdleTimeout:     120 * time.Second,
+					ShutDownTimeout: 5 * time.Second,
+					RequestTimeout:  1000 * time.Millisecond,
+				},
+				Data: DataConfig{
+					FilePath:                 "/tmp/config.json",
+					PersistInterval:          5 * time.Second,
+					SchedulingPoll:           30 * time.Second,
+					RefreshIntervalSecs:      60,
+					StatsRefreshIntervalSecs: 120,
+				},
+				Misc: tt.misc,
+			}
+			if err := cfg.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_Validate_WaitingProxy(t *testing.T) {
+	tests := []struct {
+		name    string
+		misc    MiscConfig
+		wantErr bool
+	}{
+		{"disabled", MiscConfig{}, false},
+		{"enabled", MiscConfig{WaitingProxy: true, WaitingProxyTimeout: time.Minute, WaitingProxyMaxBodyMB: 10}, false},
+		{"negative timeout", MiscConfig{WaitingProxyTimeout: -time.Second}, true},
+		{"enabled without body limit", MiscConfig{WaitingProxy: true}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				Server: ServerConfig{
+					Port:            8080,
+					ReadTimeout:     10 * time.Second,
+					WriteTimeout:    10 * time.Second,
+					IdleTimeout:     120 * time.Second,
+					ShutDownTimeout: 5 * time.Second,
+					RequestTimeout:  1000 * time.Millisecond,
+				},
+				Data: DataConfig{
+					FilePath:                 "/tmp/config.json",
+					PersistInterval:          5 * time.Second,
+					SchedulingPoll:           30 * time.Second,
+					RefreshIntervalSecs:      60,
+					StatsRefreshIntervalSecs: 120,
+				},
+				Misc: tt.misc,
+			}
+			if err := cfg.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
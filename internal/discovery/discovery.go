@@ -0,0 +1,226 @@
+// Package discovery registers containers from their runtime labels (runtime.discovery_interval_secs),
+// the way reverse proxies like traefik pick up their routes: a container labeled
+// <prefix>.enable=true is added to the cache, kept in sync with its labels and removed when it
+// goes away.
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bassista/go_spin/internal/cache"
+	"github.com/bassista/go_spin/internal/logger"
+	"github.com/bassista/go_spin/internal/repository"
+	"github.com/bassista/go_spin/internal/runtime"
+	"github.com/go-playground/validator/v10"
+)
+
+// Label names, after the configured prefix and a dot.
+const (
+	labelEnable       = "enable"        // "true" registers the container
+	labelURL          = "url"           // container URL, data.base_url with $1 replaced by the name when missing
+	labelFriendlyName = "friendly_name" // friendly name, the lowercased name when missing
+	labelGroup        = "group"         // comma-separated groups the container is added to
+)
+
+// errNothingChanged aborts the discovery transaction so an unchanged document is not marked dirty.
+var errNothingChanged = errors.New("nothing changed")
+
+// Report lists the containers changed by a discovery pass.
+type Report struct {
+	Added   []string `json:"added"`
+	Updated []string `json:"updated"`
+	Removed []string `json:"removed"`
+}
+
+// IsEmpty reports whether the pass changed nothing.
+func (r Report) IsEmpty() bool {
+	return len(r.Added) == 0 && len(r.Updated) == 0 && len(r.Removed) == 0
+}
+
+// Worker reconciles the discovered containers of the cache with the labels of the runtime ones.
+type Worker struct {
+	labels    runtime.LabelLister
+	store     cache.TransactionalStore
+	prefix    string
+	baseURL   string
+	validator *validator.Validate
+}
+
+// NewWorker creates a Worker reading the labels starting with prefix from rt. baseURL is
+// data.base_url, used for containers without a url label.
+func NewWorker(rt runtime.ContainerRuntime, store cache.TransactionalStore, prefix, baseURL string) (*Worker, error) {
+	if !runtime.Supports[runtime.LabelLister](rt) {
+		return nil, errors.New("runtime does not report container labels")
+	}
+	return &Worker{labels: rt.(runtime.LabelLister), store: store, prefix: prefix, baseURL: baseURL, validator: validator.New()}, nil
+}
+
+// Start runs a discovery pass right away and then every interval until ctx is cancelled. Failed
+// passes are logged and leave the cache unchanged.
+func (w *Worker) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if _, err := w.Sync(ctx); err != nil && ctx.Err() == nil {
+				logger.WithComponent("discovery").Errorf("discovery failed: %v", err)
+			}
+			select {
+			case <-ctx.Done():
+				logger.WithComponent("discovery").Infof("discovery shutting down")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Sync runs one discovery pass in a single transaction: enabled runtime containers missing from
+// the cache are added active, discovered ones get their url, friendly name and groups from the
+// labels, and discovered ones without an enabled runtime container are removed with their
+// schedules. Containers defined by hand are never touched, even when they carry the labels.
+func (w *Worker) Sync(ctx context.Context) (Report, error) {
+	labels, err := w.labels.ContainerLabels(ctx)
+	if err != nil {
+		return Report{}, fmt.Errorf("read labels: %w", err)
+	}
+	wanted := w.wantedContainers(labels)
+
+	var report Report
+	_, err = w.store.Apply(func(doc *repository.DataDocument) error {
+		report = w.reconcile(doc, wanted)
+		if report.IsEmpty() {
+			return errNothingChanged
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, errNothingChanged) {
+			return Report{}, nil
+		}
+		return Report{}, fmt.Errorf("update cache: %w", err)
+	}
+	logger.WithComponent("discovery").Infof("discovery: %d added, %d updated, %d removed",
+		len(report.Added), len(report.Updated), len(report.Removed))
+	return report, nil
+}
+
+// wanted is a container to register and the groups it joins.
+type wanted struct {
+	container repository.Container
+	groups    []string
+}
+
+// wantedContainers returns, by name, the valid containers the labels enable.
+func (w *Worker) wantedContainers(labels map[string]map[string]string) map[string]wanted {
+	result := map[string]wanted{}
+	for name, l := range labels {
+		if enabled, _ := strconv.ParseBool(l[w.label(labelEnable)]); !enabled {
+			continue
+		}
+		active := true
+		container := repository.Container{
+			Name:         name,
+			FriendlyName: strings.ToLower(name),
+			URL:          strings.ReplaceAll(w.baseURL, "$1", name),
+			Active:       &active,
+			Discovered:   true,
+		}
+		if v := strings.TrimSpace(l[w.label(labelURL)]); v != "" {
+			container.URL = v
+		}
+		if v := strings.TrimSpace(l[w.label(labelFriendlyName)]); v != "" {
+			container.FriendlyName = strings.ToLower(v)
+		}
+		if err := w.validator.Struct(container); err != nil {
+			logger.WithComponent("discovery").Warnf("container %s not registered, invalid labels: %v", name, err)
+			continue
+		}
+		var groups []string
+		for _, g := range strings.Split(l[w.label(labelGroup)], ",") {
+			if g = strings.TrimSpace(g); g != "" {
+				groups = append(groups, g)
+			}
+		}
+		result[name] = wanted{container: container, groups: groups}
+	}
+	return result
+}
+
+// reconcile applies the wanted containers to doc and reports the changes.
+func (w *Worker) reconcile(doc *repository.DataDocument, wantedByName map[string]wanted) Report {
+	report := Report{Added: []string{}, Updated: []string{}, Removed: []string{}}
+
+	names := make([]string, 0, len(wantedByName))
+	for name := range wantedByName {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		want := wantedByName[name]
+		idx := slices.IndexFunc(doc.Containers, func(c repository.Container) bool { return c.Name == name })
+		switch {
+		case idx < 0:
+			doc.Containers = append(doc.Containers, want.container)
+			doc.Order = append(doc.Order, name)
+			report.Added = append(report.Added, name)
+		case !doc.Containers[idx].Discovered:
+			logger.WithComponent("discovery").Debugf("container %s is defined by hand, labels ignored", name)
+			continue
+		default:
+			current := &doc.Containers[idx]
+			if current.URL != want.container.URL || current.FriendlyName != want.container.FriendlyName {
+				// The rest of the definition (active, schedules, readiness...) stays editable
+				current.URL = want.container.URL
+				current.FriendlyName = want.container.FriendlyName
+				report.Updated = append(report.Updated, name)
+			}
+		}
+		if joinGroups(doc, name, want.groups) && idx >= 0 && !slices.Contains(report.Updated, name) {
+			report.Updated = append(report.Updated, name)
+		}
+	}
+
+	var gone []string
+	for _, c := range doc.Containers {
+		if _, ok := wantedByName[c.Name]; c.Discovered && !ok {
+			gone = append(gone, c.Name)
+		}
+	}
+	for _, name := range gone {
+		cache.RemoveContainerFrom(doc, name)
+		report.Removed = append(report.Removed, name)
+	}
+	return report
+}
+
+// joinGroups adds name to the groups, creating the missing ones active. Memberships are only
+// added: removing a group label leaves the container in the group. It reports whether doc changed.
+func joinGroups(doc *repository.DataDocument, name string, groups []string) bool {
+	changed := false
+	for _, group := range groups {
+		idx := slices.IndexFunc(doc.Groups, func(g repository.Group) bool { return g.Name == group })
+		if idx < 0 {
+			active := true
+			doc.Groups = append(doc.Groups, repository.Group{Name: group, Active: &active})
+			doc.GroupOrder = append(doc.GroupOrder, group)
+			idx = len(doc.Groups) - 1
+		}
+		if !slices.Contains(doc.Groups[idx].Container, name) {
+			doc.Groups[idx].Container = append(doc.Groups[idx].Container, name)
+			changed = true
+		}
+	}
+	return changed
+}
+
+func (w *Worker) label(name string) string {
+	return w.prefix + "." + name
+}
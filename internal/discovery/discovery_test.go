@@ -0,0 +1,99 @@
+package discovery
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/bassista/go_spin/internal/cache"
+	"github.com/bassista/go_spin/internal/repository"
+	"github.com/bassista/go_spin/internal/runtime"
+)
+
+// labeledRuntime is a memory runtime reporting fixed labels.
+type labeledRuntime struct {
+	*runtime.MemoryRuntime
+	labels map[string]map[string]string
+}
+
+func (r *labeledRuntime) ContainerLabels(_ context.Context) (map[string]map[string]string, error) {
+	return r.labels, nil
+}
+
+func TestWorker_Sync(t *testing.T) {
+	active := true
+	store := cache.NewStore(repository.DataDocument{
+		Containers: []repository.Container{
+			{Name: "manual", FriendlyName: "manual", URL: "http://manual", Active: &active},
+		},
+		Order: []string{"manual"},
+	})
+	rt := &labeledRuntime{MemoryRuntime: runtime.NewMemoryRuntime(), labels: map[string]map[string]string{
+		"web":    {"go-spin.enable": "true", "go-spin.url": "http://web:8080", "go-spin.group": "media, tools"},
+		"db":     {"go-spin.enable": "true"},
+		"manual": {"go-spin.enable": "true", "go-spin.url": "http://elsewhere"},
+		"plain":  {"com.docker.compose.project": "media"},
+		"broken": {"go-spin.enable": "true", "go-spin.url": "not a url"},
+	}}
+	w, err := NewWorker(rt, store, "go-spin", "http://$1.local")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := w.Sync(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(report.Added, []string{"db", "web"}) || len(report.Updated) != 0 || len(report.Removed) != 0 {
+		t.Fatalf("expected db and web added, got %+v", report)
+	}
+	doc, _ := store.Snapshot()
+	urls := map[string]string{}
+	for _, c := range doc.Containers {
+		urls[c.Name] = c.URL
+	}
+	want := map[string]string{"manual": "http://manual", "web": "http://web:8080", "db": "http://db.local"}
+	if !reflect.DeepEqual(urls, want) {
+		t.Errorf("expected urls %v, got %v", want, urls)
+	}
+	if len(doc.Groups) != 2 || !reflect.DeepEqual(doc.Groups[0].Container, []string{"web"}) || !reflect.DeepEqual(doc.GroupOrder, []string{"media", "tools"}) {
+		t.Errorf("expected web in the media and tools groups, got %+v", doc.Groups)
+	}
+
+	// Unchanged labels change nothing
+	store.ClearDirty()
+	if report, err = w.Sync(context.Background()); err != nil || !report.IsEmpty() || store.IsDirty() {
+		t.Errorf("expected an empty pass leaving the store clean, got %+v err=%v", report, err)
+	}
+
+	// A changed url updates the entry, a disappeared container is removed with its memberships
+	rt.labels = map[string]map[string]string{
+		"web": {"go-spin.enable": "true", "go-spin.url": "http://web:9090"},
+	}
+	report, err = w.Sync(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(report.Updated, []string{"web"}) || !reflect.DeepEqual(report.Removed, []string{"db"}) {
+		t.Errorf("expected web updated and db removed, got %+v", report)
+	}
+	doc, _ = store.Snapshot()
+	if len(doc.Containers) != 2 || doc.Containers[1].URL != "http://web:9090" || !reflect.DeepEqual(doc.Order, []string{"manual", "web"}) {
+		t.Errorf("unexpected containers %+v, order %v", doc.Containers, doc.Order)
+	}
+}
+
+func TestNewWorker_RequiresLabels(t *testing.T) {
+	if _, err := NewWorker(runtime.NewMemoryRuntime(), cache.NewStore(repository.DataDocument{}), "go-spin", ""); err == nil {
+		t.Error("expected an error for a runtime without labels")
+	}
+	// A wrapper implements LabelLister whatever it wraps
+	wrapped := runtime.Forwarder{Inner: runtime.NewMemoryRuntime()}
+	if _, err := NewWorker(wrapped, cache.NewStore(repository.DataDocument{}), "go-spin", ""); err == nil {
+		t.Error("expected an error for a wrapped runtime without labels")
+	}
+	labeled := runtime.Forwarder{Inner: &labeledRuntime{MemoryRuntime: runtime.NewMemoryRuntime()}}
+	if _, err := NewWorker(labeled, cache.NewStore(repository.DataDocument{}), "go-spin", ""); err != nil {
+		t.Errorf("expected a wrapped runtime with labels to be accepted, got %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package events
+
+import (
+	"sync"
+	"time"
+
+	"github.com/bassista/go_spin/internal/logger"
+)
+
+// Type identifies what happened.
+type Type string
+
+// Event types published on the bus.
+const (
+	ContainerStarted Type = "container.started"
+	ContainerStopped Type = "container.stopped"
+	ScheduleFired    Type = "schedule.fired"
+	CacheReplaced    Type = "cache.replaced"
+	ConfigPersisted  Type = "config.persisted"
+)
+
+// Types lists every event type published on the bus.
+var Types = []Type{ContainerStarted, ContainerStopped, ScheduleFired, CacheReplaced, ConfigPersisted}
+
+// IsKnown reports whether t is one of the published event types.
+func IsKnown(t Type) bool {
+	for _, known := range Types {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// subscriberBuffer is the number of pending events kept per subscriber; a subscriber that falls
+// further behind misses events rather than slowing the publisher down.
+const subscriberBuffer = 64
+
+// Event is a state change notification.
+type Event struct {
+	Type    Type              `json:"type"`
+	Time    time.Time         `json:"time"`
+	Subject string            `json:"subject,omitempty"` // container name, empty for document-wide events
+	Data    map[string]string `json:"data,omitempty"`
+}
+
+type subscription struct {
+	ch    chan Event
+	types map[Type]struct{} // empty means every type
+}
+
+// Bus is an in-memory publish/subscribe fan-out of events. Publishers never block: the
+// controllers, the scheduler and the persistence loop emit events and the consumers (state
+// stream, webhooks, ...) receive them on their own goroutine.
+type Bus struct {
+	mu     sync.Mutex
+	nextID int
+	subs   map[int]subscription
+	clock  func() time.Time
+}
+
+// NewBus creates a Bus with no subscribers.
+func NewBus() *Bus {
+	return &Bus{subs: map[int]subscription{}, clock: time.Now}
+}
+
+// Subscribe returns a channel receiving the events of the given types (every type when none is
+// given) and a function ending the subscription, which closes the channel.
+func (b *Bus) Subscribe(types ...Type) (<-chan Event, func()) {
+	sub := subscription{ch: make(chan Event, subscriberBuffer), types: map[Type]struct{}{}}
+	for _, t := range types {
+		sub.types[t] = struct{}{}
+	}
+
+	b.mu.Lock()
+	id := b.nextID
+	b.nextID++
+	b.subs[id] = sub
+	b.mu.Unlock()
+
+	var once sync.Once
+	return sub.ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			delete(b.subs, id)
+			close(sub.ch)
+		})
+	}
+}
+
+// Publish delivers ev to every interested subscriber without blocking, setting ev.Time when it
+// is zero. Publishing on a nil Bus is a no-op, so optional publishers need no nil checks.
+func (b *Bus) Publish(ev Event) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if ev.Time.IsZero() {
+		ev.Time = b.clock()
+	}
+	for _, sub := range b.subs {
+		if len(sub.types) > 0 {
+			if _, ok := sub.types[ev.Type]; !ok {
+				continue
+			}
+		}
+		select {
+		case sub.ch <- ev:
+		default:
+			logger.WithComponent("events").Debugf("subscriber behind, dropping %s event of %q", ev.Type, ev.Subject)
+		}
+	}
+}
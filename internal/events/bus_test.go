@@ -0,0 +1,112 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bassista/go_spin/internal/runtime"
+)
+
+func receive(t *testing.T, ch <-chan Event) Event {
+	t.Helper()
+	select {
+	case ev := <-ch:
+		return ev
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for an event")
+		return Event{}
+	}
+}
+
+func TestBus_FiltersByType(t *testing.T) {
+	bus := NewBus()
+	all, unsubscribeAll := bus.Subscribe()
+	defer unsubscribeAll()
+	started, unsubscribeStarted := bus.Subscribe(ContainerStarted)
+	defer unsubscribeStarted()
+
+	bus.Publish(Event{Type: ConfigPersisted})
+	bus.Publish(Event{Type: ContainerStarted, Subject: "c1"})
+
+	if ev := receive(t, all); ev.Type != ConfigPersisted || ev.Time.IsZero() {
+		t.Errorf("expected a timestamped config persisted event first, got %+v", ev)
+	}
+	if ev := receive(t, all); ev.Type != ContainerStarted {
+		t.Errorf("expected container started event, got %+v", ev)
+	}
+	if ev := receive(t, started); ev.Type != ContainerStarted || ev.Subject != "c1" {
+		t.Errorf("expected only the container started event, got %+v", ev)
+	}
+	select {
+	case ev := <-started:
+		t.Errorf("expected no further event for the filtered subscriber, got %+v", ev)
+	default:
+	}
+}
+
+func TestBus_UnsubscribeClosesChannelAndNilBusIsNoop(t *testing.T) {
+	bus := NewBus()
+	ch, unsubscribe := bus.Subscribe()
+	unsubscribe()
+	unsubscribe() // idempotent
+	if _, ok := <-ch; ok {
+		t.Error("expected channel closed after unsubscribe")
+	}
+	bus.Publish(Event{Type: CacheReplaced}) // no subscriber left, must not panic
+
+	var nilBus *Bus
+	nilBus.Publish(Event{Type: CacheReplaced})
+}
+
+func TestBus_PublishDoesNotBlockOnSlowSubscriber(t *testing.T) {
+	bus := NewBus()
+	_, unsubscribe := bus.Subscribe()
+	defer unsubscribe()
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < subscriberBuffer*2; i++ {
+			bus.Publish(Event{Type: ScheduleFired})
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("publish blocked on a subscriber that does not read")
+	}
+}
+
+func TestRuntime_PublishesSuccessfulActionsOnly(t *testing.T) {
+	ctx := context.Background()
+	bus := NewBus()
+	ch, unsubscribe := bus.Subscribe()
+	defer unsubscribe()
+
+	inner := runtime.NewMemoryRuntime()
+	rt := NewRuntime(inner, bus)
+	if err := rt.Start(ctx, "c1"); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if ev := receive(t, ch); ev.Type != ContainerStarted || ev.Subject != "c1" {
+		t.Errorf("expected container started event for c1, got %+v", ev)
+	}
+	if err := rt.Stop(ctx, "c1"); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	if ev := receive(t, ch); ev.Type != ContainerStopped || ev.Subject != "c1" {
+		t.Errorf("expected container stopped event for c1, got %+v", ev)
+	}
+
+	// Safe mode above the events wrapper keeps skipped actions off the bus.
+	safe := runtime.NewSafeModeRuntime(rt, true)
+	if err := safe.Start(ctx, "c2"); !runtime.IsSafeMode(err) {
+		t.Fatalf("expected safe mode error, got %v", err)
+	}
+	select {
+	case ev := <-ch:
+		t.Errorf("expected no event for a skipped start, got %+v", ev)
+	default:
+	}
+}
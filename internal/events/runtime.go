@@ -0,0 +1,36 @@
+package events
+
+import (
+	"context"
+
+	"github.com/bassista/go_spin/internal/runtime"
+)
+
+// Runtime wraps a ContainerRuntime and publishes ContainerStarted/ContainerStopped after every
+// successful Start/Stop, whichever component (API, scheduler, waiting server) triggered it.
+// Read operations and optional interfaces are forwarded.
+type Runtime struct {
+	runtime.Forwarder
+	bus *Bus
+}
+
+// NewRuntime wraps inner so its start/stop actions are published on bus.
+func NewRuntime(inner runtime.ContainerRuntime, bus *Bus) *Runtime {
+	return &Runtime{Forwarder: runtime.Forwarder{Inner: inner}, bus: bus}
+}
+
+func (r *Runtime) Start(ctx context.Context, containerName string) error {
+	if err := r.Inner.Start(ctx, containerName); err != nil {
+		return err
+	}
+	r.bus.Publish(Event{Type: ContainerStarted, Subject: containerName})
+	return nil
+}
+
+func (r *Runtime) Stop(ctx context.Context, containerName string) error {
+	if err := r.Inner.Stop(ctx, containerName); err != nil {
+		return err
+	}
+	r.bus.Publish(Event{Type: ContainerStopped, Subject: containerName})
+	return nil
+}
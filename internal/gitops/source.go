@@ -0,0 +1,148 @@
+package gitops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/bassista/go_spin/internal/config"
+)
+
+// maxDocumentBytes bounds the size of a fetched data document.
+const maxDocumentBytes = 16 << 20
+
+// gitCloneDirPattern names the temporary clone directory used when sync.git_dir is empty.
+const gitCloneDirPattern = "go_spin-sync-"
+
+// Source fetches the desired data document, plain or gzip-compressed JSON.
+type Source interface {
+	Fetch(ctx context.Context) ([]byte, error)
+	// String describes the source for logs, without credentials.
+	String() string
+}
+
+// NewSource returns the source configured by cfg: an HTTP(S) URL or a git repository.
+func NewSource(cfg config.SyncConfig) (Source, error) {
+	switch {
+	case cfg.URL != "":
+		return &URLSource{URL: cfg.URL, Client: &http.Client{}}, nil
+	case cfg.GitRepo != "":
+		dir := cfg.GitDir
+		if dir == "" {
+			tmp, err := os.MkdirTemp("", gitCloneDirPattern)
+			if err != nil {
+				return nil, fmt.Errorf("create git clone dir: %w", err)
+			}
+			dir = tmp
+		}
+		return &GitSource{Repo: cfg.GitRepo, Branch: cfg.GitBranch, Path: cfg.GitPath, Dir: dir}, nil
+	default:
+		return nil, errors.New("no sync source configured")
+	}
+}
+
+// URLSource downloads the data document with a GET request.
+type URLSource struct {
+	URL    string
+	Client *http.Client
+}
+
+// Fetch downloads the document; any status but 200 is an error.
+func (s *URLSource) Fetch(ctx context.Context) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return readDocument(resp.Body)
+}
+
+func (s *URLSource) String() string {
+	return redact(s.URL)
+}
+
+// GitSource reads the data document from a shallow clone of a branch, kept in Dir and updated
+// on every fetch with the git command.
+type GitSource struct {
+	Repo   string
+	Branch string
+	Path   string // of the document inside the repository
+	Dir    string
+}
+
+// Fetch clones the branch into Dir on the first call, then fetches and checks out its head, and
+// reads the document.
+func (s *GitSource) Fetch(ctx context.Context) ([]byte, error) {
+	if _, err := os.Stat(filepath.Join(s.Dir, ".git")); err != nil {
+		if err := git(ctx, "", "clone", "--quiet", "--depth", "1", "--branch", s.Branch, "--", s.Repo, s.Dir); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := git(ctx, s.Dir, "fetch", "--quiet", "--depth", "1", "origin", s.Branch); err != nil {
+			return nil, err
+		}
+		if err := git(ctx, s.Dir, "reset", "--quiet", "--hard", "FETCH_HEAD"); err != nil {
+			return nil, err
+		}
+	}
+
+	file, err := os.Open(filepath.Join(s.Dir, s.Path))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+	return readDocument(file)
+}
+
+func (s *GitSource) String() string {
+	return fmt.Sprintf("%s@%s:%s", redact(s.Repo), s.Branch, s.Path)
+}
+
+// git runs the git command in dir, returning its output in the error when it fails.
+func git(ctx context.Context, dir string, args ...string) error {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	// Never wait for credentials on a terminal: a sync without access must fail
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// readDocument reads at most maxDocumentBytes from r.
+func readDocument(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxDocumentBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxDocumentBytes {
+		return nil, fmt.Errorf("document larger than %d bytes", maxDocumentBytes)
+	}
+	return data, nil
+}
+
+// redact removes the user info (e.g. a token) from a URL for logging.
+func redact(raw string) string {
+	scheme, rest, found := strings.Cut(raw, "://")
+	if !found {
+		return raw
+	}
+	if at := strings.LastIndex(rest, "@"); at >= 0 && at < strings.IndexAny(rest+"/", "/") {
+		rest = rest[at+1:]
+	}
+	return scheme + "://" + rest
+}
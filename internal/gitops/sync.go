@@ -0,0 +1,101 @@
+// Package gitops keeps the data document in sync with a remote copy (sync.url or sync.git_repo):
+// the remote document is the source of truth and the management API cannot change it.
+package gitops
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bassista/go_spin/internal/logger"
+	"github.com/bassista/go_spin/internal/repository"
+)
+
+// Syncer replaces the cached data document with the remote one whenever its definitions change.
+type Syncer struct {
+	source  Source
+	store   repository.CacheStore
+	saver   repository.Saver
+	timeout time.Duration
+	now     func() time.Time
+}
+
+// NewSyncer creates a Syncer replacing the document of store and saving it with saver, so the
+// local data file holds the last synced document when the source is unreachable at startup.
+// timeout bounds every fetch.
+func NewSyncer(source Source, store repository.CacheStore, saver repository.Saver, timeout time.Duration) *Syncer {
+	return &Syncer{source: source, store: store, saver: saver, timeout: timeout, now: time.Now}
+}
+
+// Start syncs right away and then every interval until ctx is cancelled. Failed syncs are logged
+// and leave the current document in place.
+func (s *Syncer) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if _, err := s.Sync(ctx); err != nil && ctx.Err() == nil {
+				logger.WithComponent("gitops").Errorf("sync from %s failed: %v", s.source, err)
+			}
+			select {
+			case <-ctx.Done():
+				logger.WithComponent("gitops").Infof("sync shutting down")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Sync fetches and validates the remote document and, when its definitions differ from the
+// cached ones, replaces the cache with it and saves it. The runtime observations of the cached
+// containers (running, runningSince, lastError) are kept. It reports whether the cache changed.
+func (s *Syncer) Sync(ctx context.Context) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	data, err := s.source.Fetch(ctx)
+	if err != nil {
+		return false, fmt.Errorf("fetch: %w", err)
+	}
+	doc, err := repository.DecodeDocument(bytes.NewReader(data))
+	if err != nil {
+		return false, err
+	}
+	current, err := s.store.Snapshot()
+	if err != nil {
+		return false, fmt.Errorf("read cache: %w", err)
+	}
+
+	keepRuntimeState(doc, current)
+	if repository.AreDataDocumentsEqual(&current, doc) {
+		logger.WithComponent("gitops").Debugf("document of %s unchanged", s.source)
+		return false, nil
+	}
+	doc.Metadata.LastUpdate = s.now().UnixMilli()
+	if err := s.store.Replace(*doc); err != nil {
+		return false, fmt.Errorf("replace cache: %w", err)
+	}
+	if err := s.saver.Save(ctx, doc); err != nil {
+		logger.WithComponent("gitops").Warnf("synced document not saved to the data file: %v", err)
+	}
+	logger.WithComponent("gitops").Infof("document synced from %s: %d containers, %d groups, %d schedules",
+		s.source, len(doc.Containers), len(doc.Groups), len(doc.Schedules))
+	return true, nil
+}
+
+// keepRuntimeState copies the runtime observations of the containers of current to doc.
+func keepRuntimeState(doc *repository.DataDocument, current repository.DataDocument) {
+	byName := make(map[string]repository.Container, len(current.Containers))
+	for _, c := range current.Containers {
+		byName[c.Name] = c
+	}
+	for i := range doc.Containers {
+		if c, ok := byName[doc.Containers[i].Name]; ok {
+			doc.Containers[i].Running = c.Running
+			doc.Containers[i].RunningSince = c.RunningSince
+			doc.Containers[i].LastError = c.LastError
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package janitor
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bassista/go_spin/internal/logger"
+)
+
+// Prunable is an in-memory structure whose expired entries the janitor removes.
+type Prunable interface {
+	// Prune removes the entries expired at now and returns how many it removed.
+	Prune(now time.Time) int
+	// Len returns the current number of entries.
+	Len() int
+}
+
+// Janitor periodically prunes every registered structure, so the in-memory maps kept by the
+// different features (day flags, schedule extensions, readiness caches) do not grow unbounded
+// and each feature does not need its own cleanup goroutine.
+type Janitor struct {
+	mu      sync.Mutex
+	targets map[string]Prunable
+	clock   func() time.Time
+}
+
+// New creates a Janitor with no registered structures. clock returns the instant passed to
+// Prune (e.g. time.Now in the scheduling timezone); nil means time.Now.
+func New(clock func() time.Time) *Janitor {
+	if clock == nil {
+		clock = time.Now
+	}
+	return &Janitor{targets: map[string]Prunable{}, clock: clock}
+}
+
+// Register adds p under name, replacing any structure already registered with that name.
+// It is safe to call while the janitor is running. A nil p is ignored.
+func (j *Janitor) Register(name string, p Prunable) {
+	if p == nil {
+		return
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.targets[name] = p
+}
+
+// RunOnce prunes every registered structure and returns the number of entries removed per name.
+func (j *Janitor) RunOnce() map[string]int {
+	now := j.clock()
+	removed := map[string]int{}
+	for name, p := range j.snapshot() {
+		if n := p.Prune(now); n > 0 {
+			removed[name] = n
+		}
+	}
+	return removed
+}
+
+// Sizes returns the current number of entries of every registered structure.
+func (j *Janitor) Sizes() map[string]int {
+	sizes := map[string]int{}
+	for name, p := range j.snapshot() {
+		sizes[name] = p.Len()
+	}
+	return sizes
+}
+
+// snapshot copies the registrations, so pruning does not hold the janitor lock.
+func (j *Janitor) snapshot() map[string]Prunable {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	targets := make(map[string]Prunable, len(j.targets))
+	for name, p := range j.targets {
+		targets[name] = p
+	}
+	return targets
+}
+
+// Start runs RunOnce every interval until ctx is cancelled. The returned channel is closed once
+// the goroutine has exited. An interval <= 0 disables the janitor (the channel is closed at once).
+func (j *Janitor) Start(ctx context.Context, interval time.Duration) <-chan struct{} {
+	done := make(chan struct{})
+	if interval <= 0 {
+		logger.WithComponent("janitor").Debugf("janitor disabled")
+		close(done)
+		return done
+	}
+
+	logger.WithComponent("janitor").Debugf("starting janitor with interval: %v", interval)
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer close(done)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				logger.WithComponent("janitor").Debugf("janitor stopped")
+				return
+			case <-ticker.C:
+				removed := j.RunOnce()
+				if len(removed) == 0 {
+					continue
+				}
+				names := make([]string, 0, len(removed))
+				for name := range removed {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+				for _, name := range names {
+					logger.WithComponent("janitor").Debugf("pruned %d expired entries from %s", removed[name], name)
+				}
+			}
+		}
+	}()
+	return done
+}
@@ -0,0 +1,102 @@
+package janitor
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bassista/go_spin/internal/scheduler"
+)
+
+// expiringMap is a Prunable whose entries expire at a given instant. The janitor prunes it from
+// its own goroutine, so it is locked like the real structures.
+type expiringMap struct {
+	mu      sync.Mutex
+	entries map[string]time.Time
+}
+
+func (m *expiringMap) Prune(now time.Time) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	removed := 0
+	for key, expires := range m.entries {
+		if !now.Before(expires) {
+			delete(m.entries, key)
+			removed++
+		}
+	}
+	return removed
+}
+
+func (m *expiringMap) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.entries)
+}
+
+func TestJanitor_RunOnce_RemovesExpiredEntries(t *testing.T) {
+	now := time.Date(2024, 3, 18, 12, 0, 0, 0, time.UTC)
+	cache := &expiringMap{entries: map[string]time.Time{
+		"expired": now.Add(-time.Minute),
+		"fresh":   now.Add(time.Minute),
+	}}
+	ext := scheduler.NewExtensions()
+	ext.Set("yesterday", 30, now.AddDate(0, 0, -1))
+	ext.Set("today", 30, now)
+
+	j := New(func() time.Time { return now })
+	j.Register("cache", cache)
+	j.Register("extensions", ext)
+	j.Register("disabled", nil)
+
+	removed := j.RunOnce()
+	if removed["cache"] != 1 || removed["extensions"] != 1 {
+		t.Errorf("expected one entry pruned from each structure, got %v", removed)
+	}
+	if _, ok := cache.entries["fresh"]; !ok {
+		t.Error("expected the fresh entry to be kept")
+	}
+	if _, ok := ext.Get("today", now); !ok {
+		t.Error("expected today's extension to be kept")
+	}
+
+	sizes := j.Sizes()
+	if len(sizes) != 2 || sizes["cache"] != 1 || sizes["extensions"] != 1 {
+		t.Errorf("unexpected sizes after pruning: %v", sizes)
+	}
+}
+
+func TestJanitor_Start_PrunesPeriodicallyAndStopsOnCancel(t *testing.T) {
+	cache := &expiringMap{entries: map[string]time.Time{"expired": time.Now().Add(-time.Minute)}}
+	j := New(nil)
+	j.Register("cache", cache)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := j.Start(ctx, 10*time.Millisecond)
+
+	deadline := time.After(time.Second)
+	for j.Sizes()["cache"] != 0 {
+		select {
+		case <-deadline:
+			t.Fatal("expected the janitor to prune the expired entry")
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected the janitor to stop after context cancel")
+	}
+}
+
+func TestJanitor_Start_DisabledWithZeroInterval(t *testing.T) {
+	done := New(nil).Start(context.Background(), 0)
+	select {
+	case <-done:
+	default:
+		t.Fatal("expected a disabled janitor to be done immediately")
+	}
+}
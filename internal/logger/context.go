@@ -0,0 +1,41 @@
+package logger
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+// FieldRequestID is the log field carrying the ID of the API request that caused a log line.
+const FieldRequestID = "request_id"
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the request ID id.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID carried by ctx, "" when there is none.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// Detach returns base carrying the request ID of request, for the goroutines started by a request
+// that outlive it: they keep the cancellation of base and log with the ID of the request.
+func Detach(base, request context.Context) context.Context {
+	if id := RequestID(request); id != "" {
+		return WithRequestID(base, id)
+	}
+	return base
+}
+
+// WithContext is WithComponent with the request ID carried by ctx, if any.
+func WithContext(ctx context.Context, component string) *logrus.Entry {
+	entry := WithComponent(component)
+	if id := RequestID(ctx); id != "" {
+		return entry.WithField(FieldRequestID, id)
+	}
+	return entry
+}
@@ -0,0 +1,94 @@
+package logger
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Deduper collapses identical log lines emitted within a window. The first occurrence is logged
+// right away; repeats inside the window are only counted and reported as a single
+// "(repeated N times)" line once the window is over, either by the next occurrence or by Flush.
+type Deduper struct {
+	mu      sync.Mutex
+	window  time.Duration
+	now     func() time.Time
+	entries map[string]*dedupEntry
+}
+
+type dedupEntry struct {
+	entry      *logrus.Entry
+	level      logrus.Level
+	message    string
+	since      time.Time
+	suppressed int
+}
+
+// NewDeduper creates a Deduper collapsing identical messages within window.
+// A window <= 0 disables deduplication.
+func NewDeduper(window time.Duration) *Deduper {
+	return &Deduper{
+		window:  window,
+		now:     time.Now,
+		entries: map[string]*dedupEntry{},
+	}
+}
+
+// Errorf logs an error through entry unless the same message was logged within the window.
+func (d *Deduper) Errorf(entry *logrus.Entry, format string, args ...interface{}) {
+	d.logf(entry, logrus.ErrorLevel, format, args...)
+}
+
+// Warnf logs a warning through entry unless the same message was logged within the window.
+func (d *Deduper) Warnf(entry *logrus.Entry, format string, args ...interface{}) {
+	d.logf(entry, logrus.WarnLevel, format, args...)
+}
+
+func (d *Deduper) logf(entry *logrus.Entry, level logrus.Level, format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	if d.window <= 0 {
+		entry.Log(level, message)
+		return
+	}
+	key := fmt.Sprintf("%v|%s|%s", entry.Data["component"], level, message)
+	now := d.now()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	line := message
+	if e, ok := d.entries[key]; ok {
+		if now.Sub(e.since) < d.window {
+			e.suppressed++
+			return
+		}
+		if e.suppressed > 0 {
+			// The window is over: this occurrence reports the count and opens a new window.
+			line = fmt.Sprintf("%s (repeated %d times)", message, e.suppressed+1)
+		}
+	}
+	entry.Log(level, line)
+	d.entries[key] = &dedupEntry{entry: entry, level: level, message: message, since: now}
+}
+
+// Flush reports the repeats of every message whose window is over and forgets it, so a message
+// that stopped recurring still gets its count logged and the Deduper does not grow unbounded.
+func (d *Deduper) Flush() {
+	if d.window <= 0 {
+		return
+	}
+	now := d.now()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for key, e := range d.entries {
+		if now.Sub(e.since) < d.window {
+			continue
+		}
+		if e.suppressed > 0 {
+			e.entry.Log(e.level, fmt.Sprintf("%s (repeated %d times)", e.message, e.suppressed))
+		}
+		delete(d.entries, key)
+	}
+}
@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func newDedupTestLogger() (*logrus.Entry, *bytes.Buffer) {
+	var buf bytes.Buffer
+	l := logrus.New()
+	l.SetOutput(&buf)
+	l.SetFormatter(&logrus.TextFormatter{DisableTimestamp: true})
+	return l.WithField("component", "test"), &buf
+}
+
+func logLines(buf *bytes.Buffer) []string {
+	return strings.Split(strings.TrimSpace(buf.String()), "\n")
+}
+
+func TestDeduper_CollapsesRepeatsWithinWindow(t *testing.T) {
+	entry, buf := newDedupTestLogger()
+	now := time.Date(2024, 3, 18, 8, 0, 0, 0, time.UTC)
+	d := NewDeduper(time.Minute)
+	d.now = func() time.Time { return now }
+
+	for i := 0; i < 42; i++ {
+		d.Errorf(entry, "IsRunning(%s) error: %s", "c1", "docker down")
+		now = now.Add(time.Second)
+	}
+	if lines := logLines(buf); len(lines) != 1 {
+		t.Fatalf("expected a single line within the window, got %d: %v", len(lines), lines)
+	}
+
+	// Window over: the repeats are reported once.
+	now = now.Add(time.Minute)
+	d.Flush()
+	lines := logLines(buf)
+	if len(lines) != 2 {
+		t.Fatalf("expected an aggregated line after the window, got %d: %v", len(lines), lines)
+	}
+	if !strings.Contains(lines[1], "IsRunning(c1) error: docker down (repeated 41 times)") {
+		t.Errorf("expected aggregated count in %q", lines[1])
+	}
+
+	// Flushed entries are forgotten, so the next occurrence is logged as new.
+	d.Errorf(entry, "IsRunning(%s) error: %s", "c1", "docker down")
+	if lines := logLines(buf); len(lines) != 3 || strings.Contains(lines[2], "repeated") {
+		t.Errorf("expected a plain line after flush, got %v", lines)
+	}
+}
+
+func TestDeduper_NextOccurrenceReportsCount(t *testing.T) {
+	entry, buf := newDedupTestLogger()
+	now := time.Date(2024, 3, 18, 8, 0, 0, 0, time.UTC)
+	d := NewDeduper(time.Minute)
+	d.now = func() time.Time { return now }
+
+	d.Errorf(entry, "boom")
+	d.Errorf(entry, "boom")
+	d.Errorf(entry, "other")
+	now = now.Add(time.Minute)
+	d.Errorf(entry, "boom")
+
+	lines := logLines(buf)
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %d: %v", len(lines), lines)
+	}
+	if !strings.Contains(lines[2], "boom (repeated 2 times)") {
+		t.Errorf("expected the new window to report the count, got %q", lines[2])
+	}
+}
+
+func TestDeduper_ZeroWindowDisables(t *testing.T) {
+	entry, buf := newDedupTestLogger()
+	d := NewDeduper(0)
+
+	d.Warnf(entry, "same")
+	d.Warnf(entry, "same")
+	if lines := logLines(buf); len(lines) != 2 {
+		t.Errorf("expected every line to be logged, got %v", lines)
+	}
+}
@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"context"
 	"os"
 	"testing"
 
@@ -90,3 +91,26 @@ func TestWithComponentMultiple(t *testing.T) {
 		t.Error("expected different component values for different entries")
 	}
 }
+
+func TestWithContext_RequestID(t *testing.T) {
+	base, cancel := context.WithCancel(context.Background())
+	request := WithRequestID(context.Background(), "req-1")
+
+	if _, ok := WithContext(base, "test").Data[FieldRequestID]; ok {
+		t.Error("expected no request_id field without a request ID")
+	}
+
+	detached := Detach(base, request)
+	entry := WithContext(detached, "test")
+	if entry.Data[FieldRequestID] != "req-1" || entry.Data["component"] != "test" {
+		t.Errorf("unexpected fields %v", entry.Data)
+	}
+
+	cancel()
+	if detached.Err() == nil {
+		t.Error("expected the detached context to follow the cancellation of base")
+	}
+	if Detach(base, context.Background()) != base {
+		t.Error("expected base back for a request without ID")
+	}
+}
@@ -0,0 +1,57 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Formats of the log lines.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// bytesPerMB converts Options.MaxSizeMB to bytes.
+const bytesPerMB = 1024 * 1024
+
+// Options selects the format and the destinations of the logs.
+type Options struct {
+	// Format is FormatText (default) or FormatJSON.
+	Format string
+	// File, when set, receives the logs in addition to stdout.
+	File string
+	// MaxSizeMB rotates File once it reaches this size; 0 disables the rotation.
+	MaxSizeMB int
+	// MaxAge removes the rotated copies of File last written longer ago; 0 keeps them.
+	MaxAge time.Duration
+	// MaxBackups is how many rotated copies of File are kept.
+	MaxBackups int
+}
+
+// Configure applies opts to Logger. The returned closer releases the log file, nil when there is
+// none.
+func Configure(opts Options) (io.Closer, error) {
+	switch opts.Format {
+	case "", FormatText:
+		Logger.SetFormatter(&logrus.TextFormatter{FullTimestamp: true})
+	case FormatJSON:
+		Logger.SetFormatter(&logrus.JSONFormatter{TimestampFormat: time.RFC3339Nano})
+	default:
+		return nil, fmt.Errorf("unknown log format %q", opts.Format)
+	}
+
+	if opts.File == "" {
+		Logger.SetOutput(os.Stdout)
+		return nil, nil
+	}
+	file, err := NewRotatingFile(opts.File, int64(opts.MaxSizeMB)*bytesPerMB, opts.MaxAge, opts.MaxBackups)
+	if err != nil {
+		return nil, err
+	}
+	Logger.SetOutput(io.MultiWriter(os.Stdout, file))
+	return file, nil
+}
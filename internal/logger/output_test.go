@@ -0,0 +1,45 @@
+package logger
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigure_JSONFile(t *testing.T) {
+	formatter, out := Logger.Formatter, Logger.Out
+	defer func() {
+		Logger.SetFormatter(formatter)
+		Logger.SetOutput(out)
+	}()
+
+	path := filepath.Join(t.TempDir(), "go_spin.log")
+	closer, err := Configure(Options{Format: FormatJSON, File: path, MaxSizeMB: 1, MaxBackups: 1})
+	if err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+	WithComponent("test").Info("hello")
+	if err := closer.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read log file: %v", err)
+	}
+	var line map[string]any
+	if err := json.Unmarshal([]byte(strings.TrimSpace(string(data))), &line); err != nil {
+		t.Fatalf("expected a JSON line, got %q: %v", data, err)
+	}
+	if line["msg"] != "hello" || line["component"] != "test" {
+		t.Errorf("unexpected log line %v", line)
+	}
+}
+
+func TestConfigure_UnknownFormat(t *testing.T) {
+	if _, err := Configure(Options{Format: "xml"}); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
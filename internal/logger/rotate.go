@@ -0,0 +1,161 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// logFileMode is the permission of the log files created by RotatingFile.
+const logFileMode = 0644
+
+// RotatingFile is an io.Writer appending to a file that is rotated once it reaches maxSize bytes:
+// the file becomes <path>.1, the older copies shift to <path>.2 and so on. Copies beyond
+// maxBackups or last written more than maxAge ago are removed at each rotation.
+type RotatingFile struct {
+	path       string
+	maxSize    int64
+	maxAge     time.Duration
+	maxBackups int
+
+	mu     sync.Mutex
+	file   *os.File // nil when closed, or when it could not be opened again after a rotation
+	closed bool
+	size   int64
+	now    func() time.Time
+}
+
+// NewRotatingFile opens path for appending, creating it if needed. maxSize <= 0 disables the
+// rotation; maxAge <= 0 keeps the copies regardless of their age. maxBackups <= 0 keeps no copy:
+// the file is deleted and started afresh at each rotation, bounding the disk usage to maxSize.
+func NewRotatingFile(path string, maxSize int64, maxAge time.Duration, maxBackups int) (*RotatingFile, error) {
+	f := &RotatingFile{path: path, maxSize: maxSize, maxAge: maxAge, maxBackups: maxBackups, now: time.Now}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Write appends p, rotating the file first when p would push it past maxSize. A line is never
+// split across two files. When the rotation fails p is still appended to the current file, and
+// the rotation error is returned; the next write tries again.
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return 0, os.ErrClosed
+	}
+	if f.file == nil {
+		if err := f.open(); err != nil {
+			return 0, err
+		}
+	}
+	var rotateErr error
+	if f.maxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		rotateErr = f.rotate()
+		if f.file == nil {
+			return 0, rotateErr
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	if err != nil {
+		return n, err
+	}
+	return n, rotateErr
+}
+
+// Close closes the current file.
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed = true
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, logFileMode)
+	if err != nil {
+		return fmt.Errorf("open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("stat log file: %w", err)
+	}
+	f.file, f.size = file, info.Size()
+	return nil
+}
+
+func (f *RotatingFile) backupPath(id int) string {
+	return f.path + "." + strconv.Itoa(id)
+}
+
+// rotate moves the current file to backup 1, drops the backups beyond the retention and opens a
+// new file. The file is opened again even when the rotation fails, so later lines are still
+// written (to the current file); when it cannot be opened the next write tries again. The caller
+// must hold f.mu.
+func (f *RotatingFile) rotate() error {
+	rotateErr := f.file.Close()
+	if rotateErr != nil {
+		rotateErr = fmt.Errorf("close log file: %w", rotateErr)
+	} else {
+		rotateErr = f.shift()
+	}
+	f.file = nil
+	if err := f.open(); err != nil {
+		return errors.Join(rotateErr, err)
+	}
+	return rotateErr
+}
+
+// shift moves the closed current file to backup 1 and the older backups one ID up, or removes it
+// without backups.
+func (f *RotatingFile) shift() error {
+	if f.maxBackups <= 0 {
+		if err := os.Remove(f.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("remove log file: %w", err)
+		}
+		return nil
+	}
+	if err := os.Remove(f.backupPath(f.maxBackups)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove log backup %d: %w", f.maxBackups, err)
+	}
+	for id := f.maxBackups; id > 1; id-- {
+		if err := os.Rename(f.backupPath(id-1), f.backupPath(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("rotate log backup %d: %w", id-1, err)
+		}
+	}
+	if err := os.Rename(f.path, f.backupPath(1)); err != nil {
+		return fmt.Errorf("rotate log file: %w", err)
+	}
+	f.removeExpired()
+	return nil
+}
+
+// removeExpired removes the backups last written more than maxAge ago. Since older backups have
+// higher IDs, the first expired one and all the following are removed.
+func (f *RotatingFile) removeExpired() {
+	if f.maxAge <= 0 {
+		return
+	}
+	cutoff := f.now().Add(-f.maxAge)
+	for id := 1; id <= f.maxBackups; id++ {
+		info, err := os.Stat(f.backupPath(id))
+		if err != nil || info.ModTime().After(cutoff) {
+			continue
+		}
+		for ; id <= f.maxBackups; id++ {
+			_ = os.Remove(f.backupPath(id))
+		}
+	}
+}
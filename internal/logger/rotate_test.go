@@ -0,0 +1,143 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func readLogFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	return string(data)
+}
+
+func TestRotatingFile_RotatesAtMaxSize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "go_spin.log")
+	f, err := NewRotatingFile(path, 10, 0, 2)
+	if err != nil {
+		t.Fatalf("NewRotatingFile: %v", err)
+	}
+	defer f.Close()
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err := f.Write([]byte(line)); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+
+	if got := readLogFile(t, path); got != "fourth\n" {
+		t.Errorf("current file = %q", got)
+	}
+	if got := readLogFile(t, path+".1"); got != "third\n" {
+		t.Errorf("backup 1 = %q", got)
+	}
+	if got := readLogFile(t, path+".2"); got != "second\n" {
+		t.Errorf("backup 2 = %q", got)
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("expected no backup beyond maxBackups, stat error %v", err)
+	}
+}
+
+func TestRotatingFile_AppendsToExistingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "go_spin.log")
+	if err := os.WriteFile(path, []byte("old\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	f, err := NewRotatingFile(path, 0, 0, 1)
+	if err != nil {
+		t.Fatalf("NewRotatingFile: %v", err)
+	}
+	_, _ = f.Write([]byte(strings.Repeat("x", 100) + "\n"))
+	_ = f.Close()
+
+	if got := readLogFile(t, path); !strings.HasPrefix(got, "old\n") || len(got) != 105 {
+		t.Errorf("expected appended content without rotation, got %q", got)
+	}
+	if _, err := f.Write([]byte("late\n")); err == nil {
+		t.Error("expected an error writing to a closed file")
+	}
+}
+
+func TestRotatingFile_NoBackupsTruncates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "go_spin.log")
+	f, err := NewRotatingFile(path, 8, 0, 0)
+	if err != nil {
+		t.Fatalf("NewRotatingFile: %v", err)
+	}
+	defer f.Close()
+
+	_, _ = f.Write([]byte("first\n"))
+	_, _ = f.Write([]byte("second\n"))
+
+	if got := readLogFile(t, path); got != "second\n" {
+		t.Errorf("current file = %q", got)
+	}
+	if _, err := os.Stat(path + ".1"); !os.IsNotExist(err) {
+		t.Errorf("expected no backup, stat error %v", err)
+	}
+}
+
+func TestRotatingFile_RemovesExpiredBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "go_spin.log")
+	f, err := NewRotatingFile(path, 8, time.Hour, 3)
+	if err != nil {
+		t.Fatalf("NewRotatingFile: %v", err)
+	}
+	defer f.Close()
+
+	_, _ = f.Write([]byte("first\n"))
+	_, _ = f.Write([]byte("second\n"))
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(path+".1", old, old); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+	_, _ = f.Write([]byte("third\n"))
+
+	if got := readLogFile(t, path+".1"); got != "second\n" {
+		t.Errorf("backup 1 = %q", got)
+	}
+	if _, err := os.Stat(path + ".2"); !os.IsNotExist(err) {
+		t.Errorf("expected the expired backup to be removed, stat error %v", err)
+	}
+}
+
+func TestRotatingFile_KeepsWritingWhenRotationFails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "go_spin.log")
+	// A non-empty directory in place of backup 1 cannot be removed
+	if err := os.MkdirAll(filepath.Join(path+".1", "blocker"), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	f, err := NewRotatingFile(path, 8, 0, 1)
+	if err != nil {
+		t.Fatalf("NewRotatingFile: %v", err)
+	}
+	defer f.Close()
+
+	_, _ = f.Write([]byte("first\n"))
+	if _, err := f.Write([]byte("second\n")); err == nil {
+		t.Error("expected the rotation error")
+	}
+	if got := readLogFile(t, path); got != "first\nsecond\n" {
+		t.Errorf("expected the line in the current file, got %q", got)
+	}
+
+	if err := os.RemoveAll(path + ".1"); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if _, err := f.Write([]byte("third\n")); err != nil {
+		t.Fatalf("expected the next rotation to succeed: %v", err)
+	}
+	if got := readLogFile(t, path); got != "third\n" {
+		t.Errorf("current file = %q", got)
+	}
+	if got := readLogFile(t, path+".1"); got != "first\nsecond\n" {
+		t.Errorf("backup 1 = %q", got)
+	}
+}
@@ -0,0 +1,171 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+)
+
+// Sample is a single CPU/memory observation of a container.
+type Sample struct {
+	T   time.Time `json:"t"`
+	CPU float64   `json:"cpu"` // CPU usage percent
+	Mem float64   `json:"mem"` // memory usage in MB
+}
+
+// ring is a fixed-capacity circular buffer of samples in insertion (chronological) order.
+type ring struct {
+	buf   []Sample
+	head  int // index of the oldest sample
+	count int
+}
+
+func newRing(capacity int) *ring {
+	return &ring{buf: make([]Sample, capacity)}
+}
+
+// push appends s, overwriting the oldest sample when full; it reports whether one was overwritten.
+func (r *ring) push(s Sample) bool {
+	if r.count == len(r.buf) {
+		r.buf[r.head] = s
+		r.head = (r.head + 1) % len(r.buf)
+		return true
+	}
+	r.buf[(r.head+r.count)%len(r.buf)] = s
+	r.count++
+	return false
+}
+
+func (r *ring) oldest() Sample {
+	return r.buf[r.head]
+}
+
+func (r *ring) dropOldest() {
+	r.buf[r.head] = Sample{}
+	r.head = (r.head + 1) % len(r.buf)
+	r.count--
+}
+
+func (r *ring) at(i int) Sample {
+	return r.buf[(r.head+i)%len(r.buf)]
+}
+
+// History keeps the recent samples of every container in per-container ring buffers.
+// Samples older than the retention are evicted, and the number of samples held across all
+// containers never exceeds maxSamples (the globally oldest samples are dropped first).
+type History struct {
+	mu         sync.Mutex
+	retention  time.Duration
+	perRing    int
+	maxSamples int
+	total      int
+	rings      map[string]*ring
+}
+
+// NewHistory creates a History keeping retention worth of samples taken every interval,
+// holding at most maxSamples samples overall (0 means no global cap).
+func NewHistory(retention, interval time.Duration, maxSamples int) *History {
+	perRing := 1
+	if interval > 0 {
+		perRing = int(retention/interval) + 1
+	}
+	if maxSamples > 0 && perRing > maxSamples {
+		perRing = maxSamples
+	}
+	return &History{
+		retention:  retention,
+		perRing:    perRing,
+		maxSamples: maxSamples,
+		rings:      map[string]*ring{},
+	}
+}
+
+// Retention returns how long samples are kept.
+func (h *History) Retention() time.Duration {
+	return h.retention
+}
+
+// Add records a sample for the named container.
+func (h *History) Add(name string, s Sample) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.evictExpired(s.T)
+	r, ok := h.rings[name]
+	if !ok {
+		r = newRing(h.perRing)
+		h.rings[name] = r
+	}
+	if !r.push(s) {
+		h.total++
+	}
+	for h.maxSamples > 0 && h.total > h.maxSamples {
+		h.dropGloballyOldest()
+	}
+}
+
+// Window returns the samples of the named container taken within window before now, oldest first.
+func (h *History) Window(name string, window time.Duration, now time.Time) []Sample {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.evictExpired(now)
+	out := []Sample{}
+	r, ok := h.rings[name]
+	if !ok {
+		return out
+	}
+	from := now.Add(-window)
+	for i := 0; i < r.count; i++ {
+		if s := r.at(i); !s.T.Before(from) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// Forget drops the samples of the named container.
+func (h *History) Forget(name string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if r, ok := h.rings[name]; ok {
+		h.total -= r.count
+		delete(h.rings, name)
+	}
+}
+
+// evictExpired drops samples older than the retention; callers hold h.mu.
+func (h *History) evictExpired(now time.Time) {
+	cutoff := now.Add(-h.retention)
+	for name, r := range h.rings {
+		for r.count > 0 && r.oldest().T.Before(cutoff) {
+			r.dropOldest()
+			h.total--
+		}
+		if r.count == 0 {
+			delete(h.rings, name)
+		}
+	}
+}
+
+// dropGloballyOldest removes the oldest sample across all containers; callers hold h.mu.
+func (h *History) dropGloballyOldest() {
+	var oldestName string
+	var oldest time.Time
+	for name, r := range h.rings {
+		if r.count == 0 {
+			continue
+		}
+		if t := r.oldest().T; oldestName == "" || t.Before(oldest) {
+			oldestName, oldest = name, t
+		}
+	}
+	if oldestName == "" {
+		return
+	}
+	r := h.rings[oldestName]
+	r.dropOldest()
+	h.total--
+	if r.count == 0 {
+		delete(h.rings, oldestName)
+	}
+}
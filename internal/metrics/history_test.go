@@ -0,0 +1,118 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bassista/go_spin/internal/repository"
+	"github.com/bassista/go_spin/internal/runtime"
+)
+
+func TestHistory_WindowReturnsSamplesInOrder(t *testing.T) {
+	h := NewHistory(time.Hour, time.Minute, 0)
+	base := time.Date(2024, 3, 18, 10, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		h.Add("web", Sample{T: base.Add(time.Duration(i) * time.Minute), CPU: float64(i), Mem: float64(10 * i)})
+	}
+	h.Add("db", Sample{T: base, CPU: 99})
+
+	now := base.Add(4 * time.Minute)
+	got := h.Window("web", 2*time.Minute, now)
+	if len(got) != 3 {
+		t.Fatalf("expected 3 samples in a 2m window, got %d: %v", len(got), got)
+	}
+	for i, s := range got {
+		if want := float64(i + 2); s.CPU != want {
+			t.Errorf("sample %d: expected cpu %v, got %v", i, want, s.CPU)
+		}
+	}
+
+	if got := h.Window("missing", time.Hour, now); got == nil || len(got) != 0 {
+		t.Errorf("expected an empty non-nil slice for an unknown container, got %v", got)
+	}
+}
+
+func TestHistory_EvictsSamplesOlderThanRetention(t *testing.T) {
+	h := NewHistory(10*time.Minute, time.Minute, 0)
+	base := time.Date(2024, 3, 18, 10, 0, 0, 0, time.UTC)
+	h.Add("web", Sample{T: base, CPU: 1})
+	h.Add("web", Sample{T: base.Add(5 * time.Minute), CPU: 2})
+	h.Add("web", Sample{T: base.Add(12 * time.Minute), CPU: 3})
+
+	got := h.Window("web", time.Hour, base.Add(12*time.Minute))
+	if len(got) != 2 || got[0].CPU != 2 || got[1].CPU != 3 {
+		t.Fatalf("expected the sample older than the retention to be evicted, got %v", got)
+	}
+}
+
+func TestHistory_RingOverwritesOldest(t *testing.T) {
+	// 2m retention sampled every minute: at most 3 samples per container.
+	h := NewHistory(2*time.Minute, time.Minute, 0)
+	base := time.Date(2024, 3, 18, 10, 0, 0, 0, time.UTC)
+	for i := 0; i < 6; i++ {
+		h.Add("web", Sample{T: base.Add(time.Duration(i) * 30 * time.Second), CPU: float64(i)})
+	}
+
+	got := h.Window("web", time.Hour, base.Add(150*time.Second))
+	if len(got) != 3 || got[0].CPU != 3 || got[2].CPU != 5 {
+		t.Fatalf("expected the 3 most recent samples, got %v", got)
+	}
+}
+
+func TestHistory_CapsTotalSamples(t *testing.T) {
+	h := NewHistory(time.Hour, time.Minute, 4)
+	base := time.Date(2024, 3, 18, 10, 0, 0, 0, time.UTC)
+	h.Add("a", Sample{T: base, CPU: 1})
+	h.Add("b", Sample{T: base.Add(time.Minute), CPU: 2})
+	h.Add("a", Sample{T: base.Add(2 * time.Minute), CPU: 3})
+	h.Add("b", Sample{T: base.Add(3 * time.Minute), CPU: 4})
+	h.Add("c", Sample{T: base.Add(4 * time.Minute), CPU: 5})
+
+	now := base.Add(4 * time.Minute)
+	a := h.Window("a", time.Hour, now)
+	if len(a) != 1 || a[0].CPU != 3 {
+		t.Errorf("expected the globally oldest sample to be dropped, got %v", a)
+	}
+	total := len(a) + len(h.Window("b", time.Hour, now)) + len(h.Window("c", time.Hour, now))
+	if total != 4 {
+		t.Errorf("expected 4 samples overall, got %d", total)
+	}
+}
+
+type sampleStore struct {
+	doc repository.DataDocument
+}
+
+func (s *sampleStore) Snapshot() (repository.DataDocument, error) { return s.doc, nil }
+
+type statsRuntime struct {
+	runtime.ContainerRuntime
+	stats map[string]runtime.ContainerStats
+}
+
+func (r *statsRuntime) Stats(_ context.Context, name string) (runtime.ContainerStats, error) {
+	s, ok := r.stats[name]
+	if !ok {
+		return runtime.ContainerStats{}, errors.New("container " + name + " not running")
+	}
+	return s, nil
+}
+
+func TestSample_RecordsStatsAndSkipsErrors(t *testing.T) {
+	store := &sampleStore{doc: repository.DataDocument{Containers: []repository.Container{{Name: "web"}, {Name: "stopped"}}}}
+	rt := &statsRuntime{stats: map[string]runtime.ContainerStats{"web": {CPUPercent: 12.5, MemoryMB: 64}}}
+	h := NewHistory(time.Hour, time.Minute, 0)
+	now := time.Date(2024, 3, 18, 10, 0, 0, 0, time.UTC)
+
+	sample(context.Background(), store, rt, h, now)
+
+	got := h.Window("web", time.Hour, now)
+	if len(got) != 1 || got[0].CPU != 12.5 || got[0].Mem != 64 || !got[0].T.Equal(now) {
+		t.Errorf("expected one sample for web, got %v", got)
+	}
+	if got := h.Window("stopped", time.Hour, now); len(got) != 0 {
+		t.Errorf("expected no samples for a container whose stats fail, got %v", got)
+	}
+}
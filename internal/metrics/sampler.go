@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/bassista/go_spin/internal/cache"
+	"github.com/bassista/go_spin/internal/logger"
+	"github.com/bassista/go_spin/internal/runtime"
+)
+
+// StartSampler runs a goroutine that, every interval, queries the runtime stats of every
+// container in store and records them in h. Containers whose stats fail (e.g. not running)
+// are skipped. The goroutine stops when ctx is cancelled.
+func StartSampler(ctx context.Context, store cache.ReadOnlyStore, rt runtime.ContainerRuntime, h *History, interval time.Duration) {
+	logger.WithComponent("metrics").Debugf("starting metrics sampler with interval: %v, retention: %v", interval, h.Retention())
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				logger.WithComponent("metrics").Debugf("metrics sampler stopped")
+				return
+			case <-ticker.C:
+				// Bound a sweep by the interval so a slow runtime cannot pile sweeps up.
+				sweepCtx, cancel := context.WithTimeout(ctx, interval)
+				sample(sweepCtx, store, rt, h, time.Now())
+				cancel()
+			}
+		}
+	}()
+}
+
+// sample records one observation per container taken at now.
+func sample(ctx context.Context, store cache.ReadOnlyStore, rt runtime.ContainerRuntime, h *History, now time.Time) {
+	doc, err := store.Snapshot()
+	if err != nil {
+		logger.WithComponent("metrics").Errorf("snapshot error: %v", err)
+		return
+	}
+	for _, c := range doc.Containers {
+		if ctx.Err() != nil {
+			logger.WithComponent("metrics").Warnf("metrics sweep interrupted: %v", ctx.Err())
+			return
+		}
+		stats, err := rt.Stats(ctx, c.Name)
+		if err != nil {
+			logger.WithComponent("metrics").Tracef("skipping metrics sample for %s: %v", c.Name, err)
+			continue
+		}
+		h.Add(c.Name, Sample{T: now, CPU: stats.CPUPercent, Mem: stats.MemoryMB})
+	}
+}
@@ -0,0 +1,228 @@
+// Package ops serializes the start/stop operations of containers, coalescing the concurrent
+// requests for the same action into a single runtime call.
+package ops
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bassista/go_spin/internal/logger"
+	"github.com/bassista/go_spin/internal/runtime"
+)
+
+// ErrNotFound is returned by Wait for an unknown or pruned operation ID.
+var ErrNotFound = errors.New("operation not found")
+
+// Action is the runtime call an operation performs.
+type Action string
+
+const (
+	ActionStart Action = "start"
+	ActionStop  Action = "stop"
+)
+
+// Status is the progress of an operation.
+type Status string
+
+const (
+	StatusQueued    Status = "queued"
+	StatusRunning   Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+)
+
+// Operation is a snapshot of a queued start or stop.
+type Operation struct {
+	ID        string `json:"id"`
+	Container string `json:"container"`
+	Action    Action `json:"action"`
+	Status    Status `json:"status"`
+	Error     string `json:"error,omitempty"`
+	// Requests counts the submissions coalesced into the operation, 1 when it was not shared.
+	Requests   int        `json:"requests"`
+	QueuedAt   time.Time  `json:"queuedAt"`
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+// Done reports whether the operation has finished.
+func (o Operation) Done() bool {
+	return o.Status == StatusSucceeded || o.Status == StatusFailed
+}
+
+type entry struct {
+	op   Operation
+	err  error
+	done chan struct{}
+}
+
+// Queue runs the start/stop operations of each container one at a time, in submission order.
+// A submission for the same action as the last operation queued for the container, still queued
+// or running, joins that operation instead of adding a new one, so the waiting page, the
+// scheduler and the API hitting the same container at once produce one runtime call.
+// Operations of different containers run concurrently.
+type Queue struct {
+	ctx context.Context
+	rt  runtime.ContainerRuntime
+	// retention is how long a finished operation can still be looked up by its ID.
+	retention time.Duration
+
+	mu    sync.Mutex
+	seq   uint64
+	ops   map[string]*entry   // by ID, finished ones until pruned
+	lines map[string][]*entry // per container, the running operation first
+	last  map[string]*entry   // per container, the operation submitted last
+}
+
+// NewQueue creates a Queue calling rt. Operations run with ctx, not with the context of the
+// request that submitted them, so a client going away does not abort a start. Finished operations
+// are kept for retention before Prune removes them.
+func NewQueue(ctx context.Context, rt runtime.ContainerRuntime, retention time.Duration) *Queue {
+	return &Queue{ctx: ctx, rt: rt, retention: retention, ops: map[string]*entry{}, lines: map[string][]*entry{}, last: map[string]*entry{}}
+}
+
+// Submit queues action on container, or joins the matching operation already in flight, and
+// returns it.
+func (q *Queue) Submit(action Action, container string) Operation {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	line := q.lines[container]
+	if n := len(line); n > 0 && line[n-1].op.Action == action {
+		tail := line[n-1]
+		tail.op.Requests++
+		logger.WithComponent("ops").Debugf("%s of %s joined operation %s (%d requests)", action, container, tail.op.ID, tail.op.Requests)
+		return tail.op
+	}
+
+	q.seq++
+	e := &entry{
+		op: Operation{
+			ID:        strconv.FormatUint(q.seq, 10),
+			Container: container,
+			Action:    action,
+			Status:    StatusQueued,
+			Requests:  1,
+			QueuedAt:  time.Now(),
+		},
+		done: make(chan struct{}),
+	}
+	q.ops[e.op.ID] = e
+	q.last[container] = e
+	q.lines[container] = append(line, e)
+	if len(line) == 0 {
+		go q.run(container)
+	}
+	return e.op
+}
+
+// run performs the operations of container until its line is empty.
+func (q *Queue) run(container string) {
+	for {
+		q.mu.Lock()
+		e := q.lines[container][0]
+		now := time.Now()
+		e.op.Status, e.op.StartedAt = StatusRunning, &now
+		q.mu.Unlock()
+
+		var err error
+		if e.op.Action == ActionStart {
+			err = q.rt.Start(q.ctx, container)
+		} else {
+			err = q.rt.Stop(q.ctx, container)
+		}
+
+		q.mu.Lock()
+		finished := time.Now()
+		e.op.FinishedAt, e.err = &finished, err
+		e.op.Status = StatusSucceeded
+		if err != nil {
+			e.op.Status, e.op.Error = StatusFailed, err.Error()
+		}
+		close(e.done)
+		rest := q.lines[container][1:]
+		if len(rest) == 0 {
+			delete(q.lines, container)
+			q.mu.Unlock()
+			return
+		}
+		q.lines[container] = rest
+		q.mu.Unlock()
+	}
+}
+
+// Get returns the operation with the given ID.
+func (q *Queue) Get(id string) (Operation, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	e, ok := q.ops[id]
+	if !ok {
+		return Operation{}, false
+	}
+	return e.op, true
+}
+
+// Last returns the operation submitted last for container, until it is pruned.
+func (q *Queue) Last(container string) (Operation, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	e, ok := q.last[container]
+	if !ok {
+		return Operation{}, false
+	}
+	return e.op, true
+}
+
+// Wait blocks until the operation finishes or ctx is done, and returns the error of its runtime
+// call (or of ctx).
+func (q *Queue) Wait(ctx context.Context, id string) (Operation, error) {
+	q.mu.Lock()
+	e, ok := q.ops[id]
+	q.mu.Unlock()
+	if !ok {
+		return Operation{}, ErrNotFound
+	}
+	select {
+	case <-e.done:
+	case <-ctx.Done():
+		op, _ := q.Get(id)
+		return op, ctx.Err()
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return e.op, e.err
+}
+
+// Do submits action on container and waits for it.
+func (q *Queue) Do(ctx context.Context, action Action, container string) error {
+	op := q.Submit(action, container)
+	_, err := q.Wait(ctx, op.ID)
+	return err
+}
+
+// Prune removes the operations finished for longer than the retention of the queue.
+func (q *Queue) Prune(now time.Time) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	removed := 0
+	for id, e := range q.ops {
+		if e.op.FinishedAt != nil && now.Sub(*e.op.FinishedAt) > q.retention {
+			delete(q.ops, id)
+			if q.last[e.op.Container] == e {
+				delete(q.last, e.op.Container)
+			}
+			removed++
+		}
+	}
+	return removed
+}
+
+// Len returns the number of operations that can be looked up.
+func (q *Queue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.ops)
+}
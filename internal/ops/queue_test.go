@@ -0,0 +1,113 @@
+package ops
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bassista/go_spin/internal/runtime"
+)
+
+// blockingRuntime is a memory runtime whose starts wait on release and are counted.
+type blockingRuntime struct {
+	*runtime.MemoryRuntime
+	release  chan struct{}
+	mu       sync.Mutex
+	starts   int
+	startErr error
+}
+
+func (b *blockingRuntime) Start(ctx context.Context, name string) error {
+	<-b.release
+	b.mu.Lock()
+	b.starts++
+	b.mu.Unlock()
+	if b.startErr != nil {
+		return b.startErr
+	}
+	return b.MemoryRuntime.Start(ctx, name)
+}
+
+func TestQueue_CoalescesConcurrentStarts(t *testing.T) {
+	rt := &blockingRuntime{MemoryRuntime: runtime.NewMemoryRuntime(), release: make(chan struct{})}
+	q := NewQueue(context.Background(), rt, time.Minute)
+
+	first := q.Submit(ActionStart, "web")
+	second := q.Submit(ActionStart, "web")
+	other := q.Submit(ActionStart, "db")
+	if second.ID != first.ID || second.Requests != 2 {
+		t.Fatalf("expected the second start to join %s, got %+v", first.ID, second)
+	}
+	if other.ID == first.ID {
+		t.Fatal("expected a separate operation for another container")
+	}
+	close(rt.release)
+
+	op, err := q.Wait(context.Background(), first.ID)
+	if err != nil || op.Status != StatusSucceeded || op.FinishedAt == nil {
+		t.Fatalf("expected the start to succeed, got %+v err=%v", op, err)
+	}
+	if _, err := q.Wait(context.Background(), other.ID); err != nil {
+		t.Fatal(err)
+	}
+	if rt.starts != 2 {
+		t.Errorf("expected one runtime start per container, got %d", rt.starts)
+	}
+
+	// A finished operation is not joined
+	if again := q.Submit(ActionStart, "web"); again.ID == first.ID {
+		t.Error("expected a new operation once the previous one finished")
+	}
+}
+
+func TestQueue_KeepsOrderAcrossActions(t *testing.T) {
+	rt := &blockingRuntime{MemoryRuntime: runtime.NewMemoryRuntime(), release: make(chan struct{})}
+	q := NewQueue(context.Background(), rt, time.Minute)
+
+	start := q.Submit(ActionStart, "web")
+	stop := q.Submit(ActionStop, "web")
+	restart := q.Submit(ActionStart, "web")
+	if stop.ID == start.ID || restart.ID == start.ID || restart.ID == stop.ID {
+		t.Fatalf("expected three operations, got %s %s %s", start.ID, stop.ID, restart.ID)
+	}
+	if op, _ := q.Get(stop.ID); op.Status != StatusQueued {
+		t.Errorf("expected the stop queued behind the start, got %s", op.Status)
+	}
+	close(rt.release)
+
+	if _, err := q.Wait(context.Background(), restart.ID); err != nil {
+		t.Fatal(err)
+	}
+	if running, _ := rt.IsRunning(context.Background(), "web"); !running {
+		t.Error("expected web running after start, stop, start")
+	}
+}
+
+func TestQueue_FailureAndPrune(t *testing.T) {
+	rt := &blockingRuntime{MemoryRuntime: runtime.NewMemoryRuntime(), release: make(chan struct{}), startErr: runtime.ErrUnavailable}
+	close(rt.release)
+	q := NewQueue(context.Background(), rt, time.Minute)
+
+	err := q.Do(context.Background(), ActionStart, "web")
+	if !errors.Is(err, runtime.ErrUnavailable) {
+		t.Fatalf("expected the runtime error, got %v", err)
+	}
+	if _, err := q.Wait(context.Background(), "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	if last, ok := q.Last("web"); !ok || last.Status != StatusFailed || last.Error == "" {
+		t.Errorf("expected the failed start as last operation, got %+v", last)
+	}
+	if q.Len() != 1 || q.Prune(time.Now()) != 0 {
+		t.Fatal("expected the finished operation kept until its retention elapses")
+	}
+	if q.Prune(time.Now().Add(time.Minute+time.Second)) != 1 || q.Len() != 0 {
+		t.Error("expected the finished operation pruned")
+	}
+	if _, ok := q.Last("web"); ok {
+		t.Error("expected no last operation once pruned")
+	}
+}
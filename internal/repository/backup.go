@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// ErrBackupNotFound is returned when a backup id does not match an existing backup.
+var ErrBackupNotFound = errors.New("backup not found")
+
+// Backup describes a rotated copy of the data file; ID 1 is the most recent one.
+type Backup struct {
+	ID      int       `json:"id"`
+	ModTime time.Time `json:"modTime"` // when the copy was written, i.e. the save that replaced it
+	Size    int64     `json:"size"`
+}
+
+// BackupStore lists and reads the rotated backups of the data file.
+// It is optional: JSONRepository implements it, consumers type-assert it.
+type BackupStore interface {
+	ListBackups() ([]Backup, error)
+	// LoadBackup reads and validates backup id, without touching the data file.
+	LoadBackup(ctx context.Context, id int) (*DataDocument, error)
+}
+
+// backupPath returns the path of backup id.
+func (r *JSONRepository) backupPath(id int) string {
+	return r.path + ".bak." + strconv.Itoa(id)
+}
+
+// rotateBackups copies the current data file to backup 1, shifting the older backups and dropping
+// the one beyond the retention. Missing or empty data files (first start) are not backed up.
+// The caller must hold r.mu.
+func (r *JSONRepository) rotateBackups() error {
+	if r.backups <= 0 {
+		return nil
+	}
+	current, err := os.ReadFile(r.path)
+	if errors.Is(err, os.ErrNotExist) || (err == nil && len(current) == 0) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read data file: %w", err)
+	}
+
+	for id := r.backups; id > 1; id-- {
+		if err := os.Rename(r.backupPath(id-1), r.backupPath(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("rotate backup %d: %w", id-1, err)
+		}
+	}
+	if err := os.WriteFile(r.backupPath(1), current, 0644); err != nil {
+		return fmt.Errorf("write backup: %w", err)
+	}
+	return nil
+}
+
+// ListBackups returns the existing backups, most recent first.
+func (r *JSONRepository) ListBackups() ([]Backup, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	backups := make([]Backup, 0, r.backups)
+	for id := 1; id <= r.backups; id++ {
+		info, err := os.Stat(r.backupPath(id))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("stat backup %d: %w", id, err)
+		}
+		backups = append(backups, Backup{ID: id, ModTime: info.ModTime(), Size: info.Size()})
+	}
+	return backups, nil
+}
+
+// LoadBackup reads and validates backup id. It returns ErrBackupNotFound for an unknown id and
+// wraps ErrInvalidData when the backup cannot be used.
+func (r *JSONRepository) LoadBackup(ctx context.Context, id int) (*DataDocument, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("load backup cancelled: %w", err)
+	}
+	if id < 1 || id > r.backups {
+		return nil, ErrBackupNotFound
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	path := r.backupPath(id)
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return nil, ErrBackupNotFound
+	}
+	return r.loadFile(path)
+}
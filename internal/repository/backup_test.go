@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestJSONRepository_Backups_RotateOnSave(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.json")
+	if err := os.WriteFile(configPath, []byte("corrupted {"), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	r, err := NewJSONRepository(configPath, WithBackups(2))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	repo := r.(*JSONRepository)
+
+	doc := createTestDataDocument()
+	for i := 0; i < 3; i++ {
+		doc.Metadata.LastUpdate = int64(1000 + i)
+		if err := repo.Save(context.Background(), &doc); err != nil {
+			t.Fatalf("save %d failed: %v", i, err)
+		}
+	}
+
+	backups, err := repo.ListBackups()
+	if err != nil {
+		t.Fatalf("failed to list backups: %v", err)
+	}
+	if len(backups) != 2 || backups[0].ID != 1 || backups[1].ID != 2 {
+		t.Fatalf("expected backups 1 and 2, got %+v", backups)
+	}
+	if _, err := os.Stat(configPath + ".bak.3"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected no backup beyond the retention, got %v", err)
+	}
+
+	// Backup 1 holds the document replaced by the last save, backup 2 the one before
+	latest, err := repo.LoadBackup(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("failed to load backup 1: %v", err)
+	}
+	if latest.Metadata.LastUpdate != 1001 {
+		t.Errorf("expected backup 1 to hold lastUpdate 1001, got %d", latest.Metadata.LastUpdate)
+	}
+	previous, err := repo.LoadBackup(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("failed to load backup 2: %v", err)
+	}
+	if previous.Metadata.LastUpdate != 1000 {
+		t.Errorf("expected backup 2 to hold lastUpdate 1000, got %d", previous.Metadata.LastUpdate)
+	}
+}
+
+func TestJSONRepository_Backups_KeepsCorruptedFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.json")
+	if err := os.WriteFile(configPath, []byte("corrupted {"), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	r, err := NewJSONRepository(configPath, WithBackups(3))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	repo := r.(*JSONRepository)
+
+	doc := createTestDataDocument()
+	if err := repo.Save(context.Background(), &doc); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+
+	content, err := os.ReadFile(configPath + ".bak.1")
+	if err != nil || string(content) != "corrupted {" {
+		t.Fatalf("expected the overwritten file in backup 1, got %q (%v)", content, err)
+	}
+	if _, err := repo.LoadBackup(context.Background(), 1); !errors.Is(err, ErrInvalidData) {
+		t.Errorf("expected ErrInvalidData for a corrupted backup, got %v", err)
+	}
+	if _, err := repo.LoadBackup(context.Background(), 2); !errors.Is(err, ErrBackupNotFound) {
+		t.Errorf("expected ErrBackupNotFound for a missing backup, got %v", err)
+	}
+	if _, err := repo.LoadBackup(context.Background(), 4); !errors.Is(err, ErrBackupNotFound) {
+		t.Errorf("expected ErrBackupNotFound beyond the retention, got %v", err)
+	}
+}
+
+func TestJSONRepository_Backups_Disabled(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.json")
+	if err := os.WriteFile(configPath, []byte("{}"), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	repo, err := NewJSONRepository(configPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	doc := createTestDataDocument()
+	if err := repo.Save(context.Background(), &doc); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	if _, err := os.Stat(configPath + ".bak.1"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected no backup without retention, got %v", err)
+	}
+}
@@ -0,0 +1,42 @@
+package repository
+
+import "strings"
+
+// ContainerFilter selects containers. An empty filter matches every container.
+type ContainerFilter struct {
+	// Label is "key=value" (exact match) or "key" (label present with any value).
+	Label string `json:"label"`
+}
+
+// IsEmpty reports whether the filter has no criteria.
+func (f ContainerFilter) IsEmpty() bool {
+	return strings.TrimSpace(f.Label) == ""
+}
+
+// Matches reports whether c satisfies every criterion of the filter.
+func (f ContainerFilter) Matches(c Container) bool {
+	label := strings.TrimSpace(f.Label)
+	if label == "" {
+		return true
+	}
+	key, value, hasValue := strings.Cut(label, "=")
+	got, ok := c.Labels[strings.TrimSpace(key)]
+	if !ok {
+		return false
+	}
+	return !hasValue || got == strings.TrimSpace(value)
+}
+
+// FilterContainers returns the containers matching f, preserving their order.
+func FilterContainers(containers []Container, f ContainerFilter) []Container {
+	if f.IsEmpty() {
+		return containers
+	}
+	out := make([]Container, 0, len(containers))
+	for _, c := range containers {
+		if f.Matches(c) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
@@ -1,13 +1,19 @@
 package repository
 
 import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/bassista/go_spin/internal/logger"
@@ -23,6 +29,15 @@ type CacheStore interface {
 	Replace(doc DataDocument) error
 }
 
+// Save modes of JSONRepository.
+const (
+	SaveModeSerial   = "serial"   // every Save writes the file in turn
+	SaveModeCoalesce = "coalesce" // overlapping saves collapse into a single write of the latest document
+)
+
+// gzipMagic is the header of gzip streams, used to detect compressed data files.
+var gzipMagic = []byte{0x1f, 0x8b}
+
 // JSONRepository handles disk persistence and watching of the data file.
 type JSONRepository struct {
 	path      string
@@ -30,11 +45,56 @@ type JSONRepository struct {
 	base      string
 	validator *validator.Validate
 	mu        sync.Mutex
+	saveMode  string
+	compress  bool // write gzip-compressed JSON
+	backups   int  // rotated copies of the data file kept before each save, 0 disables them
+
+	// coalesce mode state, guarded by queueMu
+	queueMu    sync.Mutex
+	pendingDoc *DataDocument
+	waiters    []chan error
+	flushing   bool
+
+	writes atomic.Int64 // number of file writes, used by tests
+}
+
+// Option customizes a JSONRepository.
+type Option func(*JSONRepository)
+
+// WithSaveMode selects how concurrent saves are handled (SaveModeSerial or SaveModeCoalesce).
+// Empty or unknown values keep the serial mode.
+func WithSaveMode(mode string) Option {
+	return func(r *JSONRepository) {
+		if mode == SaveModeCoalesce {
+			r.saveMode = SaveModeCoalesce
+		}
+	}
+}
+
+// WithCompression makes Save write gzip-compressed JSON. It is enabled automatically
+// for paths ending in ".gz"; Load always detects compressed files transparently.
+func WithCompression(enabled bool) Option {
+	return func(r *JSONRepository) {
+		if enabled {
+			r.compress = true
+		}
+	}
+}
+
+// WithBackups keeps up to retention rotated copies of the data file: before each save the current
+// file is copied to "<file>.bak.1", shifting older copies up to "<file>.bak.<retention>".
+// A retention of 0 or less disables backups.
+func WithBackups(retention int) Option {
+	return func(r *JSONRepository) {
+		if retention > 0 {
+			r.backups = retention
+		}
+	}
 }
 
 // NewJSONRepository creates a repository for the given JSON file path.
 // It returns the repository interface to avoid leaking implementation details.
-func NewJSONRepository(path string) (Repository, error) {
+func NewJSONRepository(path string, opts ...Option) (Repository, error) {
 	if path == "" {
 		return nil, errors.New("data file path is required")
 	}
@@ -46,7 +106,11 @@ func NewJSONRepository(path string) (Repository, error) {
 	}
 
 	v := validator.New()
-	return &JSONRepository{path: path, dir: dir, base: base, validator: v}, nil
+	r := &JSONRepository{path: path, dir: dir, base: base, validator: v, saveMode: SaveModeSerial, compress: strings.HasSuffix(path, ".gz")}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r, nil
 }
 
 // Load reads the JSON file, parses and validates it.
@@ -80,24 +144,50 @@ func (r *JSONRepository) Load(ctx context.Context) (*DataDocument, error) {
 
 // loadUnlocked reads the JSON file without acquiring the lock (caller must hold it).
 func (r *JSONRepository) loadUnlocked() (*DataDocument, error) {
-	file, err := os.Open(r.path)
+	return r.loadFile(r.path)
+}
+
+// loadFile reads, parses and validates the data document stored at path.
+func (r *JSONRepository) loadFile(path string) (*DataDocument, error) {
+	file, err := os.Open(path)
 	if err != nil {
 		return nil, fmt.Errorf("open data file: %w", err)
 	}
 	defer func() { _ = file.Close() }()
+	return decodeDocument(file, r.validator)
+}
+
+// DecodeDocument parses and validates a data document read from input, plain or gzip-compressed
+// JSON, as the data file is loaded: defaults applied, schedules of missing targets removed.
+func DecodeDocument(input io.Reader) (*DataDocument, error) {
+	return decodeDocument(input, validator.New())
+}
+
+func decodeDocument(src io.Reader, v *validator.Validate) (*DataDocument, error) {
+	// Detect gzip by its magic bytes so plain and compressed files are both accepted.
+	reader := bufio.NewReader(src)
+	var input io.Reader = reader
+	if magic, err := reader.Peek(len(gzipMagic)); err == nil && bytes.Equal(magic, gzipMagic) {
+		gz, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, fmt.Errorf("open compressed data file: %w", invalidIfMalformed(err))
+		}
+		defer func() { _ = gz.Close() }()
+		input = gz
+	}
 
 	var doc DataDocument
-	if err := json.NewDecoder(file).Decode(&doc); err != nil {
-		return nil, fmt.Errorf("decode data file: %w", err)
+	if err := json.NewDecoder(input).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decode data file: %w", invalidIfMalformed(err))
 	}
 
 	doc.ApplyDefaults()
 
 	finalDoc := removeSchedulesWithMissingContainers(&doc)
 
-	if r.validator != nil {
-		if err := r.validator.Struct(finalDoc); err != nil {
-			return nil, fmt.Errorf("validate data file: %w", err)
+	if v != nil {
+		if err := v.Struct(finalDoc); err != nil {
+			return nil, fmt.Errorf("validate data file: %w: %w", ErrInvalidData, err)
 		}
 	}
 
@@ -168,6 +258,10 @@ func (r *JSONRepository) Save(ctx context.Context, doc *DataDocument) error {
 		return fmt.Errorf("save cancelled: %w", err)
 	}
 
+	if r.saveMode == SaveModeCoalesce {
+		return r.saveCoalesced(ctx, doc)
+	}
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -185,6 +279,63 @@ func (r *JSONRepository) Save(ctx context.Context, doc *DataDocument) error {
 	return nil
 }
 
+// saveCoalesced queues doc as the latest pending document. The first caller becomes the flusher and
+// writes pending documents until the queue is empty; callers arriving during a write simply replace
+// the pending document and wait, so a burst of saves results in few writes of the latest state.
+func (r *JSONRepository) saveCoalesced(ctx context.Context, doc *DataDocument) error {
+	done := make(chan error, 1)
+
+	r.queueMu.Lock()
+	r.pendingDoc = doc
+	r.waiters = append(r.waiters, done)
+	leader := !r.flushing
+	r.flushing = true
+	r.queueMu.Unlock()
+
+	if leader {
+		r.flushPending()
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			logger.WithComponent("json-repo").Debugf("save failed: %v", err)
+			return err
+		}
+		logger.WithComponent("json-repo").Debugf("data saved successfully (coalesced)")
+		return nil
+	case <-ctx.Done():
+		logger.WithComponent("json-repo").Debugf("save cancelled while queued: %v", ctx.Err())
+		return fmt.Errorf("save cancelled: %w", ctx.Err())
+	}
+}
+
+// flushPending writes the pending document until no more saves are queued.
+func (r *JSONRepository) flushPending() {
+	for {
+		r.queueMu.Lock()
+		doc := r.pendingDoc
+		waiters := r.waiters
+		r.pendingDoc = nil
+		r.waiters = nil
+		if doc == nil {
+			r.flushing = false
+			r.queueMu.Unlock()
+			return
+		}
+		r.queueMu.Unlock()
+
+		r.mu.Lock()
+		err := r.saveUnlocked(doc)
+		r.mu.Unlock()
+
+		logger.WithComponent("json-repo").Tracef("coalesced write served %d save(s)", len(waiters))
+		for _, w := range waiters {
+			w <- err
+		}
+	}
+}
+
 // saveUnlocked writes the document without acquiring the lock (caller must hold it).
 func (r *JSONRepository) saveUnlocked(doc *DataDocument) error {
 	payload, err := json.MarshalIndent(doc, "", "  ")
@@ -201,7 +352,15 @@ func (r *JSONRepository) saveUnlocked(doc *DataDocument) error {
 		_ = os.Remove(tmpFile.Name())
 	}()
 
-	if _, err := tmpFile.Write(payload); err != nil {
+	if r.compress {
+		gz := gzip.NewWriter(tmpFile)
+		if _, err := gz.Write(payload); err != nil {
+			return fmt.Errorf("write compressed temp file: %w", err)
+		}
+		if err := gz.Close(); err != nil {
+			return fmt.Errorf("finish compressed temp file: %w", err)
+		}
+	} else if _, err := tmpFile.Write(payload); err != nil {
 		return fmt.Errorf("write temp file: %w", err)
 	}
 
@@ -213,9 +372,15 @@ func (r *JSONRepository) saveUnlocked(doc *DataDocument) error {
 		return fmt.Errorf("close temp file: %w", err)
 	}
 
+	// A failed backup must not prevent saving the current state
+	if err := r.rotateBackups(); err != nil {
+		logger.WithComponent("json-repo").Warnf("cannot back up %s before saving: %v", r.path, err)
+	}
+
 	if err := os.Rename(tmpFile.Name(), r.path); err != nil {
 		return fmt.Errorf("replace data file: %w", err)
 	}
+	r.writes.Add(1)
 
 	return nil
 }
@@ -1111,3 +1111,306 @@ func (m *MockCacheStoreCountingReplaces) GetReplaceCount() int {
 	defer m.mu.RUnlock()
 	return *m.replaceCount
 }
+
+// rapidSaves runs n concurrent saves released at the same instant and returns the number of file writes.
+func rapidSaves(t testing.TB, repo *JSONRepository, n int) int64 {
+	start := make(chan struct{})
+	var ready, wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		ready.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			doc := createTestDataDocument()
+			doc.Metadata.LastUpdate = int64(i)
+			ready.Done()
+			<-start
+			if err := repo.Save(context.Background(), &doc); err != nil {
+				t.Errorf("save %d failed: %v", i, err)
+			}
+		}(i)
+	}
+	ready.Wait()
+	close(start)
+	wg.Wait()
+	return repo.writes.Load()
+}
+
+func TestJSONRepository_SaveMode_Serial_WritesEverySave(t *testing.T) {
+	repo, err := NewJSONRepository(filepath.Join(t.TempDir(), "config.json"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	jsonRepo := repo.(*JSONRepository)
+
+	const saves = 20
+	if writes := rapidSaves(t, jsonRepo, saves); writes != saves {
+		t.Errorf("expected %d writes in serial mode, got %d", saves, writes)
+	}
+}
+
+func TestJSONRepository_SaveMode_Coalesce_CollapsesWrites(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	repo, err := NewJSONRepository(configPath, WithSaveMode(SaveModeCoalesce))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	jsonRepo := repo.(*JSONRepository)
+
+	// Holding the file lock blocks the first write, so every other save queues behind it
+	const saves = 100
+	var wg sync.WaitGroup
+	save := func(i int) {
+		defer wg.Done()
+		doc := createTestDataDocument()
+		doc.Metadata.LastUpdate = int64(i)
+		if err := repo.Save(context.Background(), &doc); err != nil {
+			t.Errorf("save %d failed: %v", i, err)
+		}
+	}
+	jsonRepo.mu.Lock()
+	wg.Add(1)
+	go save(0)
+	waitForQueue(t, jsonRepo, func(pending *DataDocument, waiters int) bool { return pending == nil && waiters == 0 })
+	for i := 1; i < saves; i++ {
+		wg.Add(1)
+		go save(i)
+	}
+	waitForQueue(t, jsonRepo, func(_ *DataDocument, waiters int) bool { return waiters == saves-1 })
+	jsonRepo.mu.Unlock()
+	wg.Wait()
+
+	// The blocked write plus a single write of the latest queued document
+	if writes := jsonRepo.writes.Load(); writes != 2 {
+		t.Errorf("expected %d saves to collapse into 2 writes, got %d", saves, writes)
+	}
+
+	// The file must hold a complete, valid document after the burst
+	loaded, err := repo.Load(context.Background())
+	if err != nil {
+		t.Fatalf("failed to load after coalesced saves: %v", err)
+	}
+	if len(loaded.Containers) != 1 {
+		t.Errorf("expected 1 container, got %d", len(loaded.Containers))
+	}
+}
+
+// waitForQueue waits until cond holds for the coalesce queue of repo, once a flush is running.
+func waitForQueue(t *testing.T, repo *JSONRepository, cond func(pending *DataDocument, waiters int) bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		repo.queueMu.Lock()
+		ok := repo.flushing && cond(repo.pendingDoc, len(repo.waiters))
+		repo.queueMu.Unlock()
+		if ok {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("saves did not queue")
+}
+
+func TestJSONRepository_SaveMode_Coalesce_ValidationError(t *testing.T) {
+	repo, err := NewJSONRepository(filepath.Join(t.TempDir(), "config.json"), WithSaveMode(SaveModeCoalesce))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	doc := createTestDataDocument()
+	doc.Containers[0].Name = ""
+	if err := repo.Save(context.Background(), &doc); err == nil {
+		t.Error("expected validation error in coalesce mode")
+	}
+}
+
+func BenchmarkJSONRepository_RapidSaves(b *testing.B) {
+	for _, mode := range []string{SaveModeSerial, SaveModeCoalesce} {
+		b.Run(mode, func(b *testing.B) {
+			repo, err := NewJSONRepository(filepath.Join(b.TempDir(), "config.json"), WithSaveMode(mode))
+			if err != nil {
+				b.Fatalf("unexpected error: %v", err)
+			}
+			jsonRepo := repo.(*JSONRepository)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				rapidSaves(b, jsonRepo, 50)
+			}
+			b.ReportMetric(float64(jsonRepo.writes.Load())/float64(b.N), "writes/op")
+		})
+	}
+}
+
+func TestJSONRepository_Compressed_RoundTrip(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json.gz")
+	repo, err := NewJSONRepository(configPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	doc := createTestDataDocument()
+	if err := repo.Save(context.Background(), &doc); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+
+	raw, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	if len(raw) < 2 || raw[0] != 0x1f || raw[1] != 0x8b {
+		t.Fatal("expected file to be gzip-compressed")
+	}
+
+	loaded, err := repo.Load(context.Background())
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if !AreDataDocumentsEqual(&doc, loaded) {
+		t.Error("expected loaded document to match saved document")
+	}
+}
+
+func TestJSONRepository_WithCompression_LoadsPlainFile(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	doc := createTestDataDocument()
+	data, _ := json.MarshalIndent(doc, "", "  ")
+	if err := os.WriteFile(configPath, data, 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	repo, err := NewJSONRepository(configPath, WithCompression(true))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// An existing plain file is still readable and gets compressed on the next save.
+	loaded, err := repo.Load(context.Background())
+	if err != nil {
+		t.Fatalf("load of plain file failed: %v", err)
+	}
+	if err := repo.Save(context.Background(), loaded); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	raw, _ := os.ReadFile(configPath)
+	if len(raw) < 2 || raw[0] != 0x1f || raw[1] != 0x8b {
+		t.Error("expected file to be compressed after save")
+	}
+}
+
+func TestJSONRepository_StartWatcher_CompressedFileChange(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.json.gz")
+
+	repo, err := NewJSONRepository(configPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	jsonRepo := repo.(*JSONRepository)
+
+	doc := createTestDataDocument()
+	doc.Metadata.LastUpdate = 1000
+	if err := repo.Save(context.Background(), &doc); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+
+	cache := &MockCacheStore{
+		lastUpdate: 500, // Older than disk
+		dirty:      false,
+		doc:        DataDocument{},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := jsonRepo.StartWatcher(ctx, cache); err != nil {
+		t.Fatalf("failed to start watcher: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	// An atomic compressed save (temp file + rename) must be detected by basename
+	doc.Metadata.LastUpdate = 2000
+	doc.Containers[0].FriendlyName = "Updated Container"
+	if err := repo.Save(context.Background(), &doc); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+
+	time.Sleep(400 * time.Millisecond)
+
+	if !cache.IsReplaced() {
+		t.Error("expected cache to be replaced after compressed file change")
+	}
+}
+
+// flakyLoader fails with err for the first failures calls, then returns doc.
+type flakyLoader struct {
+	failures int
+	err      error
+	calls    int
+	doc      *DataDocument
+}
+
+func (l *flakyLoader) Load(_ context.Context) (*DataDocument, error) {
+	l.calls++
+	if l.calls <= l.failures {
+		return nil, l.err
+	}
+	return l.doc, nil
+}
+
+func TestLoadWithRetry_RecoversFromTransientError(t *testing.T) {
+	doc := createTestDataDocument()
+	loader := &flakyLoader{failures: 1, err: errors.New("open data file: stale NFS file handle"), doc: &doc}
+
+	got, err := LoadWithRetry(context.Background(), loader, 3, time.Millisecond)
+	if err != nil {
+		t.Fatalf("expected load to succeed on retry, got %v", err)
+	}
+	if got != &doc {
+		t.Error("expected the document of the successful attempt")
+	}
+	if loader.calls != 2 {
+		t.Errorf("expected 2 attempts, got %d", loader.calls)
+	}
+}
+
+func TestLoadWithRetry_GivesUpAfterRetries(t *testing.T) {
+	loader := &flakyLoader{failures: 10, err: errors.New("i/o timeout")}
+
+	if _, err := LoadWithRetry(context.Background(), loader, 2, time.Millisecond); err == nil {
+		t.Fatal("expected error after exhausting retries")
+	}
+	if loader.calls != 3 {
+		t.Errorf("expected 3 attempts, got %d", loader.calls)
+	}
+}
+
+func TestLoadWithRetry_FailsFastOnInvalidOrMissingFile(t *testing.T) {
+	dir := t.TempDir()
+	invalidPath := filepath.Join(dir, "invalid.json")
+	if err := os.WriteFile(invalidPath, []byte("{not json"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	for name, path := range map[string]string{
+		"invalid": invalidPath,
+		"missing": filepath.Join(dir, "missing.json"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			repo, err := NewJSONRepository(path)
+			if err != nil {
+				t.Fatalf("failed to create repository: %v", err)
+			}
+			_, err = repo.Load(context.Background())
+			if err == nil || IsTransientLoadError(err) {
+				t.Fatalf("expected a non-transient load error, got %v", err)
+			}
+
+			start := time.Now()
+			if _, err := LoadWithRetry(context.Background(), repo, 3, time.Second); err == nil {
+				t.Fatal("expected load to fail")
+			}
+			if elapsed := time.Since(start); elapsed >= time.Second {
+				t.Errorf("expected no retry delay, took %v", elapsed)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"time"
+
+	"github.com/bassista/go_spin/internal/logger"
+)
+
+// ErrInvalidData marks a data file that was read but cannot be used (malformed JSON or gzip,
+// failed validation). Reading it again would give the same result, so it is never retried.
+var ErrInvalidData = errors.New("invalid data file")
+
+// Loader loads the data document. Repository implements it.
+type Loader interface {
+	Load(ctx context.Context) (*DataDocument, error)
+}
+
+// invalidIfMalformed wraps decoding errors caused by the file content with ErrInvalidData,
+// leaving read errors (the file could not be read completely) as they are.
+func invalidIfMalformed(err error) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, gzip.ErrHeader) || errors.Is(err, gzip.ErrChecksum) {
+		return fmt.Errorf("%w: %w", ErrInvalidData, err)
+	}
+	return err
+}
+
+// IsTransientLoadError reports whether a Load error may go away on retry. A missing or
+// unreadable-by-permission file, invalid content and cancellation are not transient.
+func IsTransientLoadError(err error) bool {
+	switch {
+	case err == nil,
+		errors.Is(err, ErrInvalidData),
+		errors.Is(err, fs.ErrNotExist),
+		errors.Is(err, fs.ErrPermission),
+		errors.Is(err, context.Canceled),
+		errors.Is(err, context.DeadlineExceeded):
+		return false
+	}
+	return true
+}
+
+// LoadWithRetry loads through loader, retrying up to retries more times after a transient error
+// with a delay that starts at delay and doubles on each attempt. Other errors fail fast.
+func LoadWithRetry(ctx context.Context, loader Loader, retries int, delay time.Duration) (*DataDocument, error) {
+	for attempt := 0; ; attempt++ {
+		doc, err := loader.Load(ctx)
+		if err == nil {
+			return doc, nil
+		}
+		if attempt >= retries || !IsTransientLoadError(err) {
+			return nil, err
+		}
+		logger.WithComponent("json-repo").Warnf("load attempt %d/%d failed, retrying in %v: %v", attempt+1, retries+1, delay, err)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("load cancelled: %w", ctx.Err())
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+}
@@ -2,7 +2,9 @@ package repository
 
 import (
 	"encoding/json"
+	"fmt"
 	"reflect"
+	"time"
 )
 
 // Metadata holds versioning info for optimistic locking.
@@ -18,6 +20,8 @@ type DataDocument struct {
 	Groups     []Group     `json:"groups" validate:"dive"`
 	GroupOrder []string    `json:"groupOrder"`
 	Schedules  []Schedule  `json:"schedules" validate:"dive"`
+	// DefaultSchedule applies to every active container no schedule targets, nil disables it.
+	DefaultSchedule *DefaultSchedule `json:"defaultSchedule,omitempty"`
 }
 
 // Container models a single container entry.
@@ -28,6 +32,137 @@ type Container struct {
 	Running      *bool  `json:"running"`
 	Active       *bool  `json:"active" validate:"required"`
 	ActivatedAt  *int64 `json:"activatedAt"`
+	// Labels are free-form key/value tags used to select containers (e.g. env=staging).
+	Labels map[string]string `json:"labels,omitempty"`
+	// DependsOn lists containers this one needs; dependents are stopped before their dependencies.
+	DependsOn []string `json:"dependsOn,omitempty"`
+	// RunningSince is the epoch millis since which the container is known to run, nil when stopped.
+	RunningSince *int64 `json:"runningSince,omitempty"`
+	// StartupTimeoutSecs bounds how long a background start may take to become ready, nil disables the check.
+	StartupTimeoutSecs *int `json:"startupTimeoutSecs,omitempty" validate:"omitempty,min=1"`
+	// Priority orders starts issued together (scheduler tick, group start): higher first, then by name.
+	// It is a soft preference; DependsOn is what start correctness relies on.
+	Priority int `json:"priority,omitempty"`
+	// RestartCooldownSecs keeps the scheduler from restarting the container until this long after it stopped it, nil disables the cooldown.
+	RestartCooldownSecs *int `json:"restartCooldownSecs,omitempty" validate:"omitempty,min=1"`
+	// ReadyURLs are extra readiness endpoints probed together with URL according to ReadyMode.
+	ReadyURLs []string `json:"readyUrls,omitempty" validate:"omitempty,dive,required"`
+	ReadyMode string   `json:"readyMode,omitempty" validate:"omitempty,oneof=all any"`
+	// ReadyCheckType selects how readiness is checked: "http" probes the URLs (default), "external"
+	// asks the checker configured with misc.external_ready_url.
+	ReadyCheckType string `json:"readyCheckType,omitempty" validate:"omitempty,oneof=http external"`
+	// HealthCheck customizes the readiness check of ReadyCheckType "http"; nil keeps the default
+	// GET of URL and ReadyURLs expecting 200, 307 or 308.
+	HealthCheck *HealthCheck `json:"healthCheck,omitempty"`
+	// WaitingMessage is shown on the waiting page while the container starts (falls back to the configured default).
+	WaitingMessage string `json:"waitingMessage,omitempty"`
+	// WaitingPage customizes the waiting page of the container; nil uses the global template.
+	WaitingPage *WaitingPage `json:"waitingPage,omitempty"`
+	// CommandOverride replaces the container command on start. Docker cannot change the command of an
+	// existing container, so it is applied by recreating the container and only with runtime.allow_recreate.
+	CommandOverride []string `json:"commandOverride,omitempty"`
+	// ExecCommands are the commands POST /runtime/:name/exec may run inside the container, by name
+	// (e.g. "flush": ["redis-cli", "save"]); no other command can be run through the API.
+	ExecCommands map[string][]string `json:"execCommands,omitempty" validate:"omitempty,dive,keys,required,endkeys,min=1,dive,required"`
+	// PreStop runs before every stop of the container (scheduler, API, groups) so it can shut down
+	// cleanly; nil stops it right away.
+	PreStop *PreStopHook `json:"preStop,omitempty"`
+	// Notes and Meta are free-form annotations (owner, ticket links) with no effect on scheduling.
+	Notes string            `json:"notes,omitempty"`
+	Meta  map[string]string `json:"meta,omitempty"`
+	// Hidden leaves the container out of GET /containers unless ?includeHidden=true; it can still be
+	// controlled and scheduled.
+	Hidden *bool `json:"hidden,omitempty"`
+	// AllowedUsers restricts who may wake the container from the waiting server to these forward-auth
+	// users (auth.waiting_user_header); empty allows every authenticated user.
+	AllowedUsers []string `json:"allowedUsers,omitempty" validate:"omitempty,dive,required"`
+	// Discovered marks a container registered from its runtime labels (runtime.discovery_interval_secs):
+	// discovery keeps its URL in sync and removes it when the runtime container goes away.
+	Discovered bool `json:"discovered,omitempty"`
+	// Host is the name of the remote host (runtime.hosts) whose agent runs the container, empty for
+	// the local runtime.
+	Host string `json:"host,omitempty"`
+	// RuntimeType selects the runtime managing the entry: "systemd" for a systemd unit named like the
+	// container, "wol" for a physical machine woken with Wake-on-LAN (WakeOnLAN), "docker" for a
+	// Docker container; empty uses misc.runtime_type.
+	RuntimeType string `json:"runtimeType,omitempty" validate:"omitempty,oneof=docker systemd wol"`
+	// WakeOnLAN configures the machine of a "wol" entry.
+	WakeOnLAN *WakeOnLAN `json:"wakeOnLan,omitempty" validate:"required_if=RuntimeType wol"`
+	// LastError records the last background start failure (e.g. "startup timeout"), cleared on the next start.
+	LastError string `json:"lastError,omitempty"`
+}
+
+// Readiness modes for containers with several readiness URLs.
+const (
+	ReadyModeAll = "all" // every URL must be ready (default)
+	ReadyModeAny = "any" // one ready URL is enough
+)
+
+// Readiness check types.
+const (
+	ReadyCheckHTTP     = "http"     // probe URL and ReadyURLs (default)
+	ReadyCheckExternal = "external" // ask the configured external checker
+)
+
+// KeepRuntimeState copies the runtime observations (Running, RunningSince, LastError) of stored
+// into c: configuration updates do not change them.
+func (c *Container) KeepRuntimeState(stored Container) {
+	c.Running, c.RunningSince, c.LastError = stored.Running, stored.RunningSince, stored.LastError
+}
+
+// HealthCheck configures how the readiness of a container is probed.
+type HealthCheck struct {
+	// Type is "http" (default), "tcp" (connect to the host and port of URL and ReadyURLs) or
+	// "exec" (run Command inside the container, ready on exit code 0).
+	Type string `json:"type,omitempty" validate:"omitempty,oneof=http tcp exec"`
+	// Path is appended to the probed URLs (http only), e.g. "/healthz".
+	Path string `json:"path,omitempty"`
+	// ExpectedStatus lists the status codes meaning ready (http only), default 200, 307 and 308.
+	ExpectedStatus []int `json:"expectedStatus,omitempty" validate:"omitempty,dive,min=100,max=599"`
+	// TimeoutMillis bounds a single probe, 0 keeps the default.
+	TimeoutMillis int      `json:"timeoutMillis,omitempty" validate:"min=0"`
+	Command       []string `json:"command,omitempty" validate:"required_if=Type exec"`
+}
+
+// Health check types.
+const (
+	HealthCheckHTTP = "http"
+	HealthCheckTCP  = "tcp"
+	HealthCheckExec = "exec"
+)
+
+// HealthCheckType returns the configured health check type, HealthCheckHTTP by default.
+func (c Container) HealthCheckType() string {
+	if c.HealthCheck == nil || c.HealthCheck.Type == "" {
+		return HealthCheckHTTP
+	}
+	return c.HealthCheck.Type
+}
+
+// PreStopHook is called, or run inside the container, before it is stopped. A failed or timed out
+// hook is logged and the container is stopped anyway.
+type PreStopHook struct {
+	// URL is requested with Method (default POST), e.g. "http://db.local:8080/api/shutdown".
+	URL    string `json:"url,omitempty" validate:"required_without=Command,omitempty,url"`
+	Method string `json:"method,omitempty" validate:"omitempty,oneof=GET POST PUT DELETE"`
+	// Command is run inside the container through the runtime exec, e.g. ["redis-cli", "save"].
+	Command []string `json:"command,omitempty" validate:"omitempty,dive,required"`
+	// TimeoutSecs bounds the hook, 0 keeps the default of 30 seconds.
+	TimeoutSecs int `json:"timeoutSecs,omitempty" validate:"min=0"`
+}
+
+// WakeOnLAN describes a physical machine managed like a container: Start sends a magic packet,
+// Stop runs a shutdown command over SSH and the machine runs while ProbeAddress accepts connections.
+type WakeOnLAN struct {
+	MAC string `json:"mac" validate:"required,mac"`
+	// Broadcast is the address the magic packet is sent to, host or host:port, default 255.255.255.255:9.
+	Broadcast string `json:"broadcast,omitempty"`
+	// ProbeAddress is a host:port of the machine (e.g. its SSH port) answering while it is up.
+	ProbeAddress string `json:"probeAddress" validate:"required,hostname_port"`
+	// ShutdownSSH is the user@host[:port] logged in to shut the machine down, empty disables Stop.
+	ShutdownSSH string `json:"shutdownSsh,omitempty"`
+	// ShutdownCommand is run over SSH by Stop, default "sudo poweroff".
+	ShutdownCommand string `json:"shutdownCommand,omitempty"`
 }
 
 // Group groups containers by name.
@@ -35,6 +170,30 @@ type Group struct {
 	Container []string `json:"container"`
 	Name      string   `json:"name" validate:"required"`
 	Active    *bool    `json:"active" validate:"required"`
+	// StartDelaySecs is waited between start stages, after the containers of a stage are ready
+	// and before the members depending on them are started.
+	StartDelaySecs int `json:"startDelaySecs,omitempty" validate:"min=0"`
+	// ReadyMode decides when the group waiting page redirects: once "all" members are ready
+	// (default) or as soon as "any" is, to the first ready one.
+	ReadyMode string `json:"readyMode,omitempty" validate:"omitempty,oneof=all any"`
+	// AllowedUsers restricts who may wake the group from the waiting server, as for containers.
+	AllowedUsers []string `json:"allowedUsers,omitempty" validate:"omitempty,dive,required"`
+	// WaitingPage customizes the waiting page of the group; nil uses the one of its first member.
+	WaitingPage *WaitingPage `json:"waitingPage,omitempty"`
+}
+
+// WaitingPage customizes the waiting page of a container or group: its own template, inline or
+// read from the templates directory, and the values of the branding placeholders.
+type WaitingPage struct {
+	// Template is inline HTML used instead of the global template; it takes precedence over TemplatePath.
+	Template string `json:"template,omitempty"`
+	// TemplatePath is a file in the directory of the global template, e.g. "nas.html".
+	TemplatePath string `json:"templatePath,omitempty"`
+	// LogoURL and AccentColor fill {{LOGO_URL}} and {{ACCENT_COLOR}} (e.g. "#ff6600").
+	LogoURL     string `json:"logoUrl,omitempty" validate:"omitempty,url"`
+	AccentColor string `json:"accentColor,omitempty" validate:"omitempty,hexcolor"`
+	// Message replaces the waitingMessage of the container, or the one of the first group member.
+	Message string `json:"message,omitempty"`
 }
 
 // Schedule defines timers for a container or group.
@@ -45,12 +204,56 @@ type Schedule struct {
 	ID         string  `json:"id" validate:"required"`
 }
 
-// Timer represents a scheduled start/stop window.
+// DefaultSchedule holds the timers followed by containers without a schedule of their own.
+type DefaultSchedule struct {
+	Timers []Timer `json:"timers" validate:"dive"`
+}
+
+// Timer represents a scheduled start/stop window: weekly (StartTime/StopTime on Days) or, when
+// StartDate is set, a one-shot window between StartDate and StopDate.
 type Timer struct {
-	StartTime string `json:"startTime" validate:"required"`
-	StopTime  string `json:"stopTime" validate:"required"`
+	StartTime string `json:"startTime" validate:"required_without=StartDate"`
+	StopTime  string `json:"stopTime" validate:"required_without=StartDate"`
 	Days      []int  `json:"days" validate:"dive,min=0,max=6"`
 	Active    *bool  `json:"active" validate:"required"`
+	// StartDate and StopDate ("2006-01-02 15:04" or "2006-01-02" for midnight, in the scheduling
+	// timezone) make the timer one-shot; StartTime, StopTime and Days are then ignored.
+	StartDate string `json:"startDate,omitempty" validate:"required_with=StopDate"`
+	StopDate  string `json:"stopDate,omitempty" validate:"required_with=StartDate"`
+}
+
+// TimerDateLayouts are the accepted formats of Timer.StartDate and Timer.StopDate.
+var TimerDateLayouts = []string{"2006-01-02 15:04", "2006-01-02"}
+
+// IsOneShot reports whether the timer is a one-shot date window rather than a weekly one.
+func (t Timer) IsOneShot() bool {
+	return t.StartDate != "" || t.StopDate != ""
+}
+
+// OneShotWindow returns the start and stop instants of a one-shot timer in loc. It fails when a
+// date is malformed or the stop is not after the start.
+func (t Timer) OneShotWindow(loc *time.Location) (time.Time, time.Time, error) {
+	start, err := parseTimerDate(t.StartDate, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid startDate: %w", err)
+	}
+	stop, err := parseTimerDate(t.StopDate, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid stopDate: %w", err)
+	}
+	if !stop.After(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("stopDate %q must be after startDate %q", t.StopDate, t.StartDate)
+	}
+	return start, stop, nil
+}
+
+func parseTimerDate(value string, loc *time.Location) (time.Time, error) {
+	for _, layout := range TimerDateLayouts {
+		if d, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return d, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q does not match %v", value, TimerDateLayouts)
 }
 
 // ApplyDefaults sets fallback values after decode.
@@ -67,6 +270,14 @@ func (d *DataDocument) ApplyDefaults() {
 			d.Schedules[si].Timers[ti].applyDefaults()
 		}
 	}
+	if d.DefaultSchedule != nil {
+		if d.DefaultSchedule.Timers == nil {
+			d.DefaultSchedule.Timers = []Timer{}
+		}
+		for ti := range d.DefaultSchedule.Timers {
+			d.DefaultSchedule.Timers[ti].applyDefaults()
+		}
+	}
 }
 
 func (t *Group) applyDefaults() {
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"testing"
+	"time"
 )
 
 func boolPtr(b bool) *bool {
@@ -242,3 +243,53 @@ func TestAreDataDocumentsEqual_SameTimers(t *testing.T) {
 		t.Error("expected documents with same timers (ignoring metadata) to be equal")
 	}
 }
+
+func TestContainerFilter_Matches(t *testing.T) {
+	c := Container{Name: "api", Labels: map[string]string{"env": "staging"}}
+	tests := []struct {
+		label string
+		want  bool
+	}{
+		{"", true},
+		{"env=staging", true},
+		{"env = staging", true},
+		{"env", true},
+		{"env=prod", false},
+		{"tier", false},
+	}
+	for _, tt := range tests {
+		if got := (ContainerFilter{Label: tt.label}).Matches(c); got != tt.want {
+			t.Errorf("label %q: expected %v, got %v", tt.label, tt.want, got)
+		}
+	}
+	if (ContainerFilter{Label: "env"}).Matches(Container{Name: "unlabeled"}) {
+		t.Error("expected unlabeled container not to match")
+	}
+}
+
+func TestTimer_OneShotWindow(t *testing.T) {
+	timer := Timer{StartDate: "2025-12-24 18:00", StopDate: "2025-12-26"}
+	if !timer.IsOneShot() {
+		t.Fatal("expected a timer with dates to be one-shot")
+	}
+	start, stop, err := timer.OneShotWindow(time.UTC)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !start.Equal(time.Date(2025, 12, 24, 18, 0, 0, 0, time.UTC)) || !stop.Equal(time.Date(2025, 12, 26, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected window %v - %v", start, stop)
+	}
+
+	for _, invalid := range []Timer{
+		{StartDate: "2025-12-26", StopDate: "2025-12-24 18:00"},
+		{StartDate: "24/12/2025", StopDate: "2025-12-26"},
+		{StartDate: "2025-12-24"},
+	} {
+		if _, _, err := invalid.OneShotWindow(time.UTC); err == nil {
+			t.Errorf("expected error for %+v", invalid)
+		}
+	}
+	if (Timer{StartTime: "08:00", StopTime: "18:00"}).IsOneShot() {
+		t.Error("expected a weekly timer not to be one-shot")
+	}
+}
@@ -0,0 +1,181 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bassista/go_spin/internal/logger"
+)
+
+// RouteLookup returns the key of the runtime a container is routed to (e.g. its host, or its
+// runtime type), "" for the local runtime.
+type RouteLookup func(containerName string) string
+
+// Dispatcher routes the operations on a container to the runtime selected by its route: the
+// local runtime, or another one (e.g. a RemoteRuntime talking to the agent of another machine,
+// or the SystemdRuntime). Operations not tied to a container (Ping, the optional interfaces) go
+// to the local runtime.
+type Dispatcher struct {
+	local  ContainerRuntime
+	routes map[string]ContainerRuntime
+	lookup RouteLookup
+}
+
+// NewDispatcher creates a Dispatcher sending the containers whose route, as returned by lookup,
+// is a key of routes to that runtime and every other container to local.
+func NewDispatcher(local ContainerRuntime, routes map[string]ContainerRuntime, lookup RouteLookup) *Dispatcher {
+	return &Dispatcher{local: local, routes: routes, lookup: lookup}
+}
+
+// runtimeFor returns the runtime of the route of containerName; an unknown route is an error.
+func (d *Dispatcher) runtimeFor(containerName string) (ContainerRuntime, error) {
+	route := d.lookup(containerName)
+	if route == "" {
+		return d.local, nil
+	}
+	rt, ok := d.routes[route]
+	if !ok {
+		return nil, fmt.Errorf("container %s: unknown runtime %q", containerName, route)
+	}
+	return rt, nil
+}
+
+func (d *Dispatcher) IsRunning(ctx context.Context, containerName string) (bool, error) {
+	rt, err := d.runtimeFor(containerName)
+	if err != nil {
+		return false, err
+	}
+	return rt.IsRunning(ctx, containerName)
+}
+
+func (d *Dispatcher) Start(ctx context.Context, containerName string) error {
+	rt, err := d.runtimeFor(containerName)
+	if err != nil {
+		return err
+	}
+	return rt.Start(ctx, containerName)
+}
+
+func (d *Dispatcher) Stop(ctx context.Context, containerName string) error {
+	rt, err := d.runtimeFor(containerName)
+	if err != nil {
+		return err
+	}
+	return rt.Stop(ctx, containerName)
+}
+
+func (d *Dispatcher) Stats(ctx context.Context, containerName string) (ContainerStats, error) {
+	rt, err := d.runtimeFor(containerName)
+	if err != nil {
+		return ContainerStats{}, err
+	}
+	return rt.Stats(ctx, containerName)
+}
+
+// ListContainers returns the containers of the local runtime and of every other reachable one,
+// sorted like the Docker runtime does. An unreachable runtime other than the local one (e.g. a
+// remote host) is logged and skipped; a local failure is an error.
+func (d *Dispatcher) ListContainers(ctx context.Context) ([]string, error) {
+	names, err := d.local.ListContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for route, rt := range d.routes {
+		routed, err := rt.ListContainers(ctx)
+		if err != nil {
+			logger.WithComponent("dispatcher").Warnf("cannot list the containers of runtime %s: %v", route, err)
+			continue
+		}
+		names = append(names, routed...)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return strings.ToLower(names[i]) < strings.ToLower(names[j])
+	})
+	return names, nil
+}
+
+func (d *Dispatcher) Ping(ctx context.Context) (PingResult, error) {
+	return d.local.Ping(ctx)
+}
+
+// Unwrap returns the local runtime.
+func (d *Dispatcher) Unwrap() ContainerRuntime {
+	return d.local
+}
+
+// PublishedPorts forwards to the local runtime when it is a PortLister.
+func (d *Dispatcher) PublishedPorts(ctx context.Context) (map[string][]uint16, error) {
+	lister, ok := d.local.(PortLister)
+	if !ok {
+		return nil, errPortsUnsupported
+	}
+	return lister.PublishedPorts(ctx)
+}
+
+// ContainerLabels forwards to the local runtime when it is a LabelLister.
+func (d *Dispatcher) ContainerLabels(ctx context.Context) (map[string]map[string]string, error) {
+	lister, ok := d.local.(LabelLister)
+	if !ok {
+		return nil, errLabelsUnsupported
+	}
+	return lister.ContainerLabels(ctx)
+}
+
+// Exec runs cmd through the runtime of the route of the container, when it is an Executor.
+func (d *Dispatcher) Exec(ctx context.Context, containerName string, cmd []string) (int, error) {
+	rt, err := d.runtimeFor(containerName)
+	if err != nil {
+		return 0, err
+	}
+	executor, ok := rt.(Executor)
+	if !ok {
+		return 0, ErrExecUnsupported
+	}
+	return executor.Exec(ctx, containerName, cmd)
+}
+
+// ExecOutput runs cmd through the runtime of the route of the container, when it is an OutputExecutor.
+func (d *Dispatcher) ExecOutput(ctx context.Context, containerName string, cmd []string) (ExecResult, error) {
+	rt, err := d.runtimeFor(containerName)
+	if err != nil {
+		return ExecResult{}, err
+	}
+	executor, ok := rt.(OutputExecutor)
+	if !ok {
+		return ExecResult{}, ErrExecUnsupported
+	}
+	return executor.ExecOutput(ctx, containerName, cmd)
+}
+
+// WatchState forwards to the local runtime when it is a StateWatcher.
+func (d *Dispatcher) WatchState(ctx context.Context) {
+	if watcher, ok := d.local.(StateWatcher); ok {
+		watcher.WatchState(ctx)
+	}
+}
+
+// RunningStates returns the cached states of the local runtime, without the containers routed
+// to another runtime even when a local container has the same name.
+func (d *Dispatcher) RunningStates() (map[string]bool, bool) {
+	watcher, ok := d.local.(StateWatcher)
+	if !ok {
+		return nil, false
+	}
+	states, synced := watcher.RunningStates()
+	for name := range states {
+		if d.lookup(name) != "" {
+			delete(states, name)
+		}
+	}
+	return states, synced
+}
+
+// Health forwards to the local runtime when it is a HealthReporter.
+func (d *Dispatcher) Health() Health {
+	if reporter, ok := d.local.(HealthReporter); ok {
+		return reporter.Health()
+	}
+	return Health{}
+}
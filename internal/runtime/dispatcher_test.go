@@ -0,0 +1,66 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// failingListRuntime is a memory runtime that cannot list its containers.
+type failingListRuntime struct {
+	*MemoryRuntime
+}
+
+func (failingListRuntime) ListContainers(context.Context) ([]string, error) {
+	return nil, ErrUnavailable
+}
+
+func TestDispatcher_RoutesByHost(t *testing.T) {
+	ctx := context.Background()
+	local, nas := NewMemoryRuntime(), NewMemoryRuntime()
+	hosts := map[string]string{"media": "nas", "ghost": "missing"}
+	d := NewDispatcher(local, map[string]ContainerRuntime{
+		"nas":  nas,
+		"down": failingListRuntime{NewMemoryRuntime()},
+	}, func(name string) string { return hosts[name] })
+
+	if err := d.Start(ctx, "media"); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Start(ctx, "web"); err != nil {
+		t.Fatal(err)
+	}
+	if running, _ := nas.IsRunning(ctx, "media"); !running {
+		t.Error("expected media started on the nas")
+	}
+	if running, _ := local.IsRunning(ctx, "media"); running {
+		t.Error("expected media not started locally")
+	}
+	if running, _ := local.IsRunning(ctx, "web"); !running {
+		t.Error("expected web started locally")
+	}
+	if err := d.Start(ctx, "ghost"); err == nil {
+		t.Error("expected an error for an unknown runtime")
+	}
+
+	// The unreachable host is skipped
+	names, err := d.ListContainers(ctx)
+	if err != nil || !reflect.DeepEqual(names, []string{"media", "web"}) {
+		t.Errorf("expected media and web, got %v err=%v", names, err)
+	}
+
+	if err := d.Stop(ctx, "media"); err != nil {
+		t.Fatal(err)
+	}
+	if running, err := d.IsRunning(ctx, "media"); err != nil || running {
+		t.Errorf("expected media stopped, got %v err=%v", running, err)
+	}
+}
+
+func TestDispatcher_LocalListFailure(t *testing.T) {
+	d := NewDispatcher(failingListRuntime{NewMemoryRuntime()}, nil, func(string) string { return "" })
+	if _, err := d.ListContainers(context.Background()); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("expected the local failure, got %v", err)
+	}
+}
@@ -0,0 +1,173 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bassista/go_spin/internal/logger"
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/events"
+	"github.com/moby/moby/client"
+)
+
+// eventsRetryDelay is how long WatchState waits before subscribing again after the event stream fails.
+const eventsRetryDelay = 5 * time.Second
+
+// errEventStreamClosed reports an event stream closed by the daemon without an error.
+var errEventStreamClosed = errors.New("event stream closed")
+
+// stateCache holds the running state of every container, kept current by the Docker events.
+// Its zero value is an unsynced, empty cache.
+type stateCache struct {
+	mu      sync.RWMutex
+	running map[string]bool
+	synced  bool
+}
+
+// lookup returns the cached state of name; ok is false when the cache is not synced or does not
+// know the container.
+func (c *stateCache) lookup(name string) (running, ok bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if !c.synced {
+		return false, false
+	}
+	running, ok = c.running[name]
+	return running, ok
+}
+
+// reset replaces the cached states and marks the cache synced.
+func (c *stateCache) reset(running map[string]bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.running = running
+	c.synced = true
+}
+
+// invalidate marks the cache unsynced, so lookups fall back to the runtime.
+func (c *stateCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.running = nil
+	c.synced = false
+}
+
+// set records the state of name when the cache is synced.
+func (c *stateCache) set(name string, running bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.synced {
+		c.running[name] = running
+	}
+}
+
+// snapshot returns a copy of the cached states, false when the cache is not synced.
+func (c *stateCache) snapshot() (map[string]bool, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if !c.synced {
+		return nil, false
+	}
+	return maps.Clone(c.running), true
+}
+
+// apply updates the cache with a container event.
+func (c *stateCache) apply(msg events.Message) {
+	name := strings.TrimPrefix(msg.Actor.Attributes["name"], "/")
+	if name == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.synced {
+		return
+	}
+	switch msg.Action {
+	case events.ActionStart, events.ActionRestart:
+		c.running[name] = true
+	case events.ActionCreate, events.ActionDie:
+		c.running[name] = false
+	case events.ActionDestroy:
+		delete(c.running, name)
+	case events.ActionRename:
+		oldName := strings.TrimPrefix(msg.Actor.Attributes["oldName"], "/")
+		if running, ok := c.running[oldName]; ok {
+			delete(c.running, oldName)
+			c.running[name] = running
+		}
+	}
+}
+
+// WatchState follows the Docker container events until ctx is cancelled, keeping the running
+// state of every container in memory for IsRunning. When the stream fails (e.g. the daemon
+// restarts) the cache is dropped, IsRunning inspects the containers again, and the stream is
+// subscribed again after eventsRetryDelay.
+func (d *DockerRuntime) WatchState(ctx context.Context) {
+	for {
+		err := d.watchEvents(ctx)
+		d.states.invalidate()
+		if ctx.Err() != nil {
+			logger.WithComponent("docker").Debugf("event watcher stopped")
+			return
+		}
+		logger.WithComponent("docker").Warnf("docker event stream interrupted, inspecting containers until it resumes: %v", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(eventsRetryDelay):
+		}
+	}
+}
+
+// RunningStates returns a copy of the running state of every container, false while WatchState
+// is not synced with the daemon.
+func (d *DockerRuntime) RunningStates() (map[string]bool, bool) {
+	return d.states.snapshot()
+}
+
+// watchEvents subscribes to the container events, seeds the cache from the container list and
+// applies the events until the stream fails.
+func (d *DockerRuntime) watchEvents(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Subscribe before listing so no change between the list and the stream is lost
+	stream := d.cli.Events(ctx, client.EventsListOptions{
+		Filters: make(client.Filters).Add("type", string(events.ContainerEventType)),
+	})
+	result, err := d.cli.ContainerList(ctx, client.ContainerListOptions{All: true})
+	if err != nil {
+		return classifyError(err)
+	}
+	running := make(map[string]bool, len(result.Items))
+	for _, c := range result.Items {
+		if len(c.Names) == 0 {
+			continue
+		}
+		// A paused container still counts as running, as for ContainerInspect
+		running[strings.TrimPrefix(c.Names[0], "/")] = c.State == container.StateRunning || c.State == container.StatePaused
+	}
+	d.states.reset(running)
+	logger.WithComponent("docker").Debugf("event watcher synced, %d containers", len(running))
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err, ok := <-stream.Err:
+			if !ok || err == nil {
+				return errEventStreamClosed
+			}
+			return classifyError(err)
+		case msg, ok := <-stream.Messages:
+			if !ok {
+				return errEventStreamClosed
+			}
+			d.states.apply(msg)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/events"
+	"github.com/moby/moby/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func containerEvent(action events.Action, attributes map[string]string) events.Message {
+	return events.Message{Type: events.ContainerEventType, Action: action, Actor: events.Actor{Attributes: attributes}}
+}
+
+func TestDockerRuntime_WatchState(t *testing.T) {
+	mockClient := &MockDockerClient{}
+	dr := NewDockerRuntimeWithClient(mockClient)
+
+	messages := make(chan events.Message)
+	errs := make(chan error, 1)
+	mockClient.On("Events", mock.Anything, mock.Anything).Return(client.EventsResult{Messages: messages, Err: errs})
+	mockClient.On("ContainerList", mock.Anything, client.ContainerListOptions{All: true}).Return(client.ContainerListResult{
+		Items: []container.Summary{
+			{Names: []string{"/web"}, State: container.StateRunning},
+			{Names: []string{"/db"}, State: container.StateExited},
+			{Names: []string{"/cache"}, State: container.StatePaused},
+		},
+	}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		dr.WatchState(ctx)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool {
+		_, synced := dr.RunningStates()
+		return synced
+	}, time.Second, 10*time.Millisecond)
+
+	// Answered from the cache: an inspect would fail the mock
+	running, err := dr.IsRunning(ctx, "web")
+	assert.NoError(t, err)
+	assert.True(t, running)
+	running, err = dr.IsRunning(ctx, "cache")
+	assert.NoError(t, err)
+	assert.True(t, running)
+
+	messages <- containerEvent(events.ActionStart, map[string]string{"name": "db"})
+	messages <- containerEvent(events.ActionDie, map[string]string{"name": "web"})
+	messages <- containerEvent(events.ActionRename, map[string]string{"name": "cache2", "oldName": "/cache"})
+	messages <- containerEvent(events.ActionCreate, map[string]string{"name": "new"})
+	// One more event so the previous ones are applied
+	messages <- containerEvent(events.ActionAttach, map[string]string{"name": "new"})
+	states, synced := dr.RunningStates()
+	assert.True(t, synced)
+	assert.Equal(t, map[string]bool{"web": false, "db": true, "cache2": true, "new": false}, states)
+
+	// A broken stream drops the cache, IsRunning inspects again
+	errs <- errors.New("daemon restarted")
+	assert.Eventually(t, func() bool {
+		_, synced := dr.RunningStates()
+		return !synced
+	}, time.Second, 10*time.Millisecond)
+
+	cancel()
+	<-done
+	mockClient.AssertExpectations(t)
+}
+
+func TestDockerRuntime_StartStop_UpdateCachedState(t *testing.T) {
+	mockClient := &MockDockerClient{}
+	dr := NewDockerRuntimeWithClient(mockClient)
+	dr.states.reset(map[string]bool{"web": false})
+
+	ctx := context.Background()
+	mockClient.On("ContainerStart", ctx, "web", client.ContainerStartOptions{}).Return(client.ContainerStartResult{}, nil)
+	mockClient.On("ContainerStop", ctx, "web", client.ContainerStopOptions{}).Return(client.ContainerStopResult{}, nil)
+
+	assert.NoError(t, dr.Start(ctx, "web"))
+	running, err := dr.IsRunning(ctx, "web")
+	assert.NoError(t, err)
+	assert.True(t, running)
+
+	assert.NoError(t, dr.Stop(ctx, "web"))
+	running, err = dr.IsRunning(ctx, "web")
+	assert.NoError(t, err)
+	assert.False(t, running)
+	mockClient.AssertExpectations(t)
+}
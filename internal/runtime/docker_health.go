@@ -0,0 +1,90 @@
+package runtime
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/bassista/go_spin/internal/logger"
+)
+
+// Retries of the idempotent calls failing because the daemon is unreachable, e.g. while it
+// restarts: the client dials the socket again on every attempt.
+const (
+	unavailableRetries    = 3
+	unavailableRetryDelay = 200 * time.Millisecond // doubled after every attempt
+)
+
+// connectionHealth records the outcome of the calls to the daemon.
+type connectionHealth struct {
+	mu          sync.Mutex
+	failures    int
+	lastError   string
+	lastSuccess time.Time
+	lastFailure time.Time
+}
+
+func (h *connectionHealth) succeeded() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.failures > 0 {
+		logger.WithComponent("docker").Infof("docker daemon reachable again after %d failed calls", h.failures)
+	}
+	h.failures = 0
+	h.lastSuccess = time.Now()
+}
+
+func (h *connectionHealth) failed(err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.failures++
+	h.lastError = err.Error()
+	h.lastFailure = time.Now()
+}
+
+func (h *connectionHealth) snapshot() Health {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return Health{
+		ConsecutiveFailures: h.failures,
+		LastError:           h.lastError,
+		LastSuccess:         h.lastSuccess,
+		LastFailure:         h.lastFailure,
+	}
+}
+
+// record tracks the connection outcome of err: nil and container errors prove the daemon
+// reachable, connection failures do not.
+func (h *connectionHealth) record(err error) {
+	if err != nil && IsUnavailable(classifyError(err)) {
+		h.failed(err)
+		return
+	}
+	h.succeeded()
+}
+
+// Health reports the connection to the daemon as observed by the recent calls.
+func (d *DockerRuntime) Health() Health {
+	return d.health.snapshot()
+}
+
+// withRetry runs op, retrying it up to unavailableRetries times while it fails because the daemon
+// is unreachable, with a delay doubling from d.retryDelay. It returns the last error of op,
+// unclassified, so callers can still check it with errdefs.
+func (d *DockerRuntime) withRetry(ctx context.Context, op func() error) error {
+	delay := d.retryDelay
+	for attempt := 0; ; attempt++ {
+		err := op()
+		d.health.record(err)
+		if err == nil || attempt == unavailableRetries || !IsUnavailable(classifyError(err)) {
+			return err
+		}
+		logger.WithComponent("docker").Debugf("docker daemon unreachable, retrying in %v: %v", delay, err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
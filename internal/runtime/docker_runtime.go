@@ -1,15 +1,21 @@
 package runtime
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
+	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/bassista/go_spin/internal/logger"
 	"github.com/containerd/errdefs"
+	"github.com/moby/moby/api/pkg/stdcopy"
 	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/network"
 	"github.com/moby/moby/client"
 )
 
@@ -19,12 +25,39 @@ type DockerClient interface {
 	ContainerInspect(ctx context.Context, containerID string, options client.ContainerInspectOptions) (client.ContainerInspectResult, error)
 	ContainerStart(ctx context.Context, containerID string, options client.ContainerStartOptions) (client.ContainerStartResult, error)
 	ContainerStop(ctx context.Context, containerID string, options client.ContainerStopOptions) (client.ContainerStopResult, error)
+	ContainerRemove(ctx context.Context, containerID string, options client.ContainerRemoveOptions) (client.ContainerRemoveResult, error)
+	ContainerCreate(ctx context.Context, options client.ContainerCreateOptions) (client.ContainerCreateResult, error)
 	ContainerList(ctx context.Context, options client.ContainerListOptions) (client.ContainerListResult, error)
 	ContainerStats(ctx context.Context, containerID string, options client.ContainerStatsOptions) (client.ContainerStatsResult, error)
+	Ping(ctx context.Context, options client.PingOptions) (client.PingResult, error)
+	ServerVersion(ctx context.Context, options client.ServerVersionOptions) (client.ServerVersionResult, error)
+	ExecCreate(ctx context.Context, containerID string, options client.ExecCreateOptions) (client.ExecCreateResult, error)
+	ExecStart(ctx context.Context, execID string, options client.ExecStartOptions) (client.ExecStartResult, error)
+	ExecInspect(ctx context.Context, execID string, options client.ExecInspectOptions) (client.ExecInspectResult, error)
+	ExecAttach(ctx context.Context, execID string, options client.ExecAttachOptions) (client.ExecAttachResult, error)
+	Events(ctx context.Context, options client.EventsListOptions) client.EventsResult
 }
 
+// execPollInterval is how often Exec checks whether the command has terminated.
+const execPollInterval = 100 * time.Millisecond
+
+// maxExecOutput bounds the output kept by ExecOutput; the rest is read and dropped.
+const maxExecOutput = 64 * 1024
+
+// CommandLookup returns the command override configured for a container, nil when there is none.
+type CommandLookup func(containerName string) []string
+
 type DockerRuntime struct {
 	cli DockerClient
+
+	// commandLookup is set only when recreating containers is allowed (runtime.allow_recreate).
+	commandLookup CommandLookup
+
+	// states is kept current by WatchState; IsRunning inspects the container while it is not synced.
+	states stateCache
+
+	health     connectionHealth
+	retryDelay time.Duration // first delay of withRetry
 }
 
 func NewDockerRuntime() (*DockerRuntime, error) {
@@ -32,25 +65,32 @@ func NewDockerRuntime() (*DockerRuntime, error) {
 	if err != nil {
 		return nil, fmt.Errorf("error creating Docker client: %w", err)
 	}
-	return &DockerRuntime{cli: cli}, nil
+	return &DockerRuntime{cli: cli, retryDelay: unavailableRetryDelay}, nil
 }
 
 // NewDockerRuntimeWithClient creates a DockerRuntime with a custom client.
 // This is primarily used for testing purposes.
 func NewDockerRuntimeWithClient(cli DockerClient) *DockerRuntime {
-	return &DockerRuntime{cli: cli}
+	return &DockerRuntime{cli: cli, retryDelay: unavailableRetryDelay}
 }
 
 func (d *DockerRuntime) IsRunning(ctx context.Context, containerName string) (bool, error) {
+	if running, ok := d.states.lookup(containerName); ok {
+		return running, nil
+	}
 	logger.WithComponent("docker").Debugf("checking if container is running: %s", containerName)
-	inspect, err := d.cli.ContainerInspect(ctx, containerName, client.ContainerInspectOptions{})
+	var inspect client.ContainerInspectResult
+	err := d.withRetry(ctx, func() (err error) {
+		inspect, err = d.cli.ContainerInspect(ctx, containerName, client.ContainerInspectOptions{})
+		return err
+	})
 	if err != nil {
 		if errdefs.IsNotFound(err) {
 			logger.WithComponent("docker").Debugf("container not found: %s", containerName)
-			return false, fmt.Errorf("container %s not found", containerName)
+			return false, &NotFoundError{Kind: "container", Name: containerName}
 		}
 		logger.WithComponent("docker").Errorf("failed to inspect container %s: %v", containerName, err)
-		return false, fmt.Errorf("error checking status of container %s: %w", containerName, err)
+		return false, fmt.Errorf("error checking status of container %s: %w", containerName, classifyError(err))
 	}
 
 	if inspect.Container.State == nil {
@@ -62,37 +102,160 @@ func (d *DockerRuntime) IsRunning(ctx context.Context, containerName string) (bo
 	return inspect.Container.State.Running, nil
 }
 
+// EnableRecreate lets Start apply command overrides returned by lookup by recreating the container
+// (stop, remove, create with the new command, start). A nil lookup disables recreation.
+func (d *DockerRuntime) EnableRecreate(lookup CommandLookup) {
+	d.commandLookup = lookup
+}
+
 func (d *DockerRuntime) Start(ctx context.Context, containerName string) error {
-	logger.WithComponent("docker").Debugf("starting container: %s", containerName)
-	_, err := d.cli.ContainerStart(ctx, containerName, client.ContainerStartOptions{})
-	if err != nil {
-		logger.WithComponent("docker").Errorf("failed to start container %s: %v", containerName, err)
-		return fmt.Errorf("error starting container %s: %w", containerName, err)
+	if d.commandLookup != nil {
+		if cmd := d.commandLookup(containerName); len(cmd) > 0 {
+			return d.recreateWithCommand(ctx, containerName, cmd)
+		}
 	}
-	logger.WithComponent("docker").Debugf("container started successfully: %s", containerName)
-	return nil
+
+	logger.WithComponent("docker").Debugf("starting container: %s", containerName)
+	return d.startExisting(ctx, containerName)
 }
 
 func (d *DockerRuntime) Stop(ctx context.Context, containerName string) error {
 	logger.WithComponent("docker").Debugf("stopping container: %s", containerName)
-	_, err := d.cli.ContainerStop(ctx, containerName, client.ContainerStopOptions{})
+	err := d.withRetry(ctx, func() error {
+		_, err := d.cli.ContainerStop(ctx, containerName, client.ContainerStopOptions{})
+		return err
+	})
 	if err != nil {
 		logger.WithComponent("docker").Errorf("failed to stop container %s: %v", containerName, err)
-		return fmt.Errorf("error stopping container %s: %w", containerName, err)
+		return fmt.Errorf("error stopping container %s: %w", containerName, classifyError(err))
 	}
+	// Record the stop now: callers checking right away must not wait for the die event
+	d.states.set(containerName, false)
 	logger.WithComponent("docker").Debugf("container stopped successfully: %s", containerName)
 	return nil
 }
 
+// recreateWithCommand starts containerName with cmd as its command. The container is recreated from
+// its current configuration (stop, remove, create, start) unless it already runs with cmd.
+func (d *DockerRuntime) recreateWithCommand(ctx context.Context, containerName string, cmd []string) error {
+	inspect, err := d.cli.ContainerInspect(ctx, containerName, client.ContainerInspectOptions{})
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return &NotFoundError{Kind: "container", Name: containerName}
+		}
+		return fmt.Errorf("error inspecting container %s before recreate: %w", containerName, classifyError(err))
+	}
+	current := inspect.Container
+	if current.Config == nil {
+		return fmt.Errorf("cannot recreate container %s: missing configuration", containerName)
+	}
+
+	if slices.Equal(current.Config.Cmd, cmd) {
+		logger.WithComponent("docker").Debugf("container %s already uses the command override, starting it", containerName)
+		if current.State != nil && current.State.Running {
+			return nil
+		}
+		return d.startExisting(ctx, containerName)
+	}
+
+	logger.WithComponent("docker").Warnf("recreating container %s with command override %q", containerName, cmd)
+	if current.State != nil && current.State.Running {
+		if _, err := d.cli.ContainerStop(ctx, containerName, client.ContainerStopOptions{}); err != nil {
+			return fmt.Errorf("error stopping container %s before recreate: %w", containerName, classifyError(err))
+		}
+	}
+	if _, err := d.cli.ContainerRemove(ctx, containerName, client.ContainerRemoveOptions{}); err != nil {
+		return fmt.Errorf("error removing container %s before recreate: %w", containerName, classifyError(err))
+	}
+
+	cfg := *current.Config
+	cfg.Cmd = cmd
+	created, err := d.cli.ContainerCreate(ctx, client.ContainerCreateOptions{
+		Name:             containerName,
+		Config:           &cfg,
+		HostConfig:       current.HostConfig,
+		NetworkingConfig: endpointsConfig(current.NetworkSettings),
+	})
+	if err != nil {
+		logger.WithComponent("docker").Errorf("failed to recreate container %s: %v", containerName, err)
+		return fmt.Errorf("error recreating container %s: %w", containerName, classifyError(err))
+	}
+	for _, w := range created.Warnings {
+		logger.WithComponent("docker").Warnf("recreate %s: %s", containerName, w)
+	}
+	return d.startExisting(ctx, containerName)
+}
+
+// startExisting starts an already created container.
+func (d *DockerRuntime) startExisting(ctx context.Context, containerName string) error {
+	err := d.withRetry(ctx, func() error {
+		_, err := d.cli.ContainerStart(ctx, containerName, client.ContainerStartOptions{})
+		return err
+	})
+	if err != nil {
+		logger.WithComponent("docker").Errorf("failed to start container %s: %v", containerName, err)
+		return fmt.Errorf("error starting container %s: %w", containerName, classifyError(err))
+	}
+	d.states.set(containerName, true)
+	logger.WithComponent("docker").Debugf("container started successfully: %s", containerName)
+	return nil
+}
+
+// endpointsConfig rebuilds the user-specified network attachments of a container, dropping the
+// operational data (addresses, endpoint ids) assigned by the daemon.
+func endpointsConfig(settings *container.NetworkSettings) *network.NetworkingConfig {
+	if settings == nil || len(settings.Networks) == 0 {
+		return nil
+	}
+	endpoints := make(map[string]*network.EndpointSettings, len(settings.Networks))
+	for name, ep := range settings.Networks {
+		if ep == nil {
+			continue
+		}
+		endpoints[name] = &network.EndpointSettings{
+			IPAMConfig: ep.IPAMConfig,
+			Links:      ep.Links,
+			Aliases:    ep.Aliases,
+			DriverOpts: ep.DriverOpts,
+			GwPriority: ep.GwPriority,
+		}
+	}
+	return &network.NetworkingConfig{EndpointsConfig: endpoints}
+}
+
+// classifyError marks connection failures to the Docker daemon with ErrDaemonUnavailable,
+// unknown containers with ErrContainerNotFound and expired deadlines with ErrTimeout.
+func classifyError(err error) error {
+	switch {
+	case client.IsErrConnectionFailed(err) || errdefs.IsUnavailable(err):
+		return fmt.Errorf("%w: %w", ErrDaemonUnavailable, err)
+	case errdefs.IsNotFound(err):
+		return fmt.Errorf("%w: %w", ErrContainerNotFound, err)
+	case errdefs.IsDeadlineExceeded(err):
+		return fmt.Errorf("%w: %w", ErrTimeout, err)
+	}
+	return err
+}
+
+// listContainers lists every container, running and stopped, retrying while the daemon is unreachable.
+func (d *DockerRuntime) listContainers(ctx context.Context) (client.ContainerListResult, error) {
+	var result client.ContainerListResult
+	err := d.withRetry(ctx, func() (err error) {
+		result, err = d.cli.ContainerList(ctx, client.ContainerListOptions{All: true})
+		return err
+	})
+	return result, err
+}
+
 // ListContainers returns a list of container names from the Docker daemon.
 // Names are returned exactly as stored (case-sensitive), sorted alphabetically (case-insensitive).
 // This includes all containers (running and stopped).
 func (d *DockerRuntime) ListContainers(ctx context.Context) ([]string, error) {
 	logger.WithComponent("docker").Debugf("listing containers")
-	result, err := d.cli.ContainerList(ctx, client.ContainerListOptions{All: true})
+	result, err := d.listContainers(ctx)
 	if err != nil {
 		logger.WithComponent("docker").Errorf("failed to list containers: %v", err)
-		return nil, fmt.Errorf("error listing containers: %w", err)
+		return nil, fmt.Errorf("error listing containers: %w", classifyError(err))
 	}
 	names := make([]string, 0, len(result.Items))
 	for _, c := range result.Items {
@@ -110,21 +273,68 @@ func (d *DockerRuntime) ListContainers(ctx context.Context) ([]string, error) {
 	return names, nil
 }
 
+// PublishedPorts returns the sorted, de-duplicated TCP host ports published by every container.
+// Containers publishing nothing are omitted.
+func (d *DockerRuntime) PublishedPorts(ctx context.Context) (map[string][]uint16, error) {
+	result, err := d.listContainers(ctx)
+	if err != nil {
+		logger.WithComponent("docker").Errorf("failed to list container ports: %v", err)
+		return nil, fmt.Errorf("error listing containers: %w", classifyError(err))
+	}
+	ports := make(map[string][]uint16, len(result.Items))
+	for _, c := range result.Items {
+		if len(c.Names) == 0 {
+			continue
+		}
+		name := strings.TrimPrefix(c.Names[0], "/")
+		for _, p := range c.Ports {
+			// The same port is listed once per host address family (0.0.0.0 and ::)
+			if p.PublicPort == 0 || p.Type != "tcp" || slices.Contains(ports[name], p.PublicPort) {
+				continue
+			}
+			ports[name] = append(ports[name], p.PublicPort)
+		}
+		slices.Sort(ports[name])
+	}
+	return ports, nil
+}
+
+// ContainerLabels returns the labels of every container. Containers without labels are omitted.
+func (d *DockerRuntime) ContainerLabels(ctx context.Context) (map[string]map[string]string, error) {
+	result, err := d.listContainers(ctx)
+	if err != nil {
+		logger.WithComponent("docker").Errorf("failed to list container labels: %v", err)
+		return nil, fmt.Errorf("error listing containers: %w", classifyError(err))
+	}
+	labels := make(map[string]map[string]string, len(result.Items))
+	for _, c := range result.Items {
+		if len(c.Names) == 0 || len(c.Labels) == 0 {
+			continue
+		}
+		labels[strings.TrimPrefix(c.Names[0], "/")] = c.Labels
+	}
+	return labels, nil
+}
+
 // Stats returns CPU and memory usage statistics for a container.
 func (d *DockerRuntime) Stats(ctx context.Context, containerName string) (ContainerStats, error) {
 	logger.WithComponent("docker").Debugf("getting stats for container: %s", containerName)
 
-	result, err := d.cli.ContainerStats(ctx, containerName, client.ContainerStatsOptions{
-		Stream:                false,
-		IncludePreviousSample: true,
+	var result client.ContainerStatsResult
+	err := d.withRetry(ctx, func() (err error) {
+		result, err = d.cli.ContainerStats(ctx, containerName, client.ContainerStatsOptions{
+			Stream:                false,
+			IncludePreviousSample: true,
+		})
+		return err
 	})
 	if err != nil {
 		if errdefs.IsNotFound(err) {
 			logger.WithComponent("docker").Debugf("container not found: %s", containerName)
-			return ContainerStats{}, fmt.Errorf("container %s not found", containerName)
+			return ContainerStats{}, &NotFoundError{Kind: "container", Name: containerName}
 		}
 		logger.WithComponent("docker").Errorf("failed to get stats for container %s: %v", containerName, err)
-		return ContainerStats{}, fmt.Errorf("error getting stats for container %s: %w", containerName, err)
+		return ContainerStats{}, fmt.Errorf("error getting stats for container %s: %w", containerName, classifyError(err))
 	}
 	defer func() {
 		if cerr := result.Body.Close(); cerr != nil {
@@ -148,13 +358,129 @@ func (d *DockerRuntime) Stats(ctx context.Context, containerName string) (Contai
 }
 
 // calculateCPUPercent calculates the CPU usage percentage from Docker stats.
+// Partial samples (e.g. a container that just started has no previous system usage) yield 0
+// instead of NaN/Inf, so the value always serializes as a plain JSON number.
 func calculateCPUPercent(stats *container.StatsResponse) float64 {
-	cpuDelta := float64(stats.CPUStats.CPUUsage.TotalUsage - stats.PreCPUStats.CPUUsage.TotalUsage)
-	systemDelta := float64(stats.CPUStats.SystemUsage - stats.PreCPUStats.SystemUsage)
+	total, preTotal := stats.CPUStats.CPUUsage.TotalUsage, stats.PreCPUStats.CPUUsage.TotalUsage
+	system, preSystem := stats.CPUStats.SystemUsage, stats.PreCPUStats.SystemUsage
+	// The counters are unsigned: compare before subtracting to avoid wrapping around.
+	if total <= preTotal || system <= preSystem || stats.CPUStats.OnlineCPUs == 0 {
+		return 0.0
+	}
+
+	cpuDelta := float64(total - preTotal)
+	systemDelta := float64(system - preSystem)
+	cpuPercent := (cpuDelta / systemDelta) * float64(stats.CPUStats.OnlineCPUs) * 100.0
+	if math.IsNaN(cpuPercent) || math.IsInf(cpuPercent, 0) {
+		return 0.0
+	}
+	return cpuPercent
+}
 
-	if systemDelta > 0 && cpuDelta > 0 {
-		cpuPercent := (cpuDelta / systemDelta) * float64(stats.CPUStats.OnlineCPUs) * 100.0
-		return cpuPercent
+// Ping checks the connection to the Docker daemon and returns its version.
+// The daemon version is best effort: a failing version call after a successful ping is only logged.
+func (d *DockerRuntime) Ping(ctx context.Context) (PingResult, error) {
+	logger.WithComponent("docker").Debugf("pinging docker daemon")
+	// No retry: the ping reports the connection as it is now
+	ping, err := d.cli.Ping(ctx, client.PingOptions{})
+	d.health.record(err)
+	if err != nil {
+		logger.WithComponent("docker").Warnf("docker ping failed: %v", err)
+		return PingResult{}, fmt.Errorf("error pinging docker daemon: %w", classifyError(err))
+	}
+
+	result := PingResult{APIVersion: ping.APIVersion}
+	version, err := d.cli.ServerVersion(ctx, client.ServerVersionOptions{})
+	if err != nil {
+		logger.WithComponent("docker").Warnf("failed to read docker daemon version: %v", err)
+		return result, nil
+	}
+	result.Version = version.Version
+	if result.APIVersion == "" {
+		result.APIVersion = version.APIVersion
+	}
+	return result, nil
+}
+
+// Exec runs cmd detached in the container and waits for it to terminate, returning its exit code.
+// The output is discarded.
+func (d *DockerRuntime) Exec(ctx context.Context, containerName string, cmd []string) (int, error) {
+	logger.WithComponent("docker").Debugf("exec in container %s: %v", containerName, cmd)
+	created, err := d.cli.ExecCreate(ctx, containerName, client.ExecCreateOptions{Cmd: cmd})
+	if err != nil {
+		return 0, fmt.Errorf("error creating exec in container %s: %w", containerName, classifyError(err))
+	}
+	if _, err := d.cli.ExecStart(ctx, created.ID, client.ExecStartOptions{Detach: true}); err != nil {
+		return 0, fmt.Errorf("error starting exec in container %s: %w", containerName, classifyError(err))
+	}
+	return d.waitExec(ctx, containerName, created.ID)
+}
+
+// ExecOutput runs cmd attached to the container, waits for it to terminate and returns its exit
+// code with its stdout and stderr interleaved, truncated to maxExecOutput bytes.
+func (d *DockerRuntime) ExecOutput(ctx context.Context, containerName string, cmd []string) (ExecResult, error) {
+	logger.WithComponent("docker").Debugf("exec with output in container %s: %v", containerName, cmd)
+	created, err := d.cli.ExecCreate(ctx, containerName, client.ExecCreateOptions{Cmd: cmd, AttachStdout: true, AttachStderr: true})
+	if err != nil {
+		return ExecResult{}, fmt.Errorf("error creating exec in container %s: %w", containerName, classifyError(err))
+	}
+	attached, err := d.cli.ExecAttach(ctx, created.ID, client.ExecAttachOptions{})
+	if err != nil {
+		return ExecResult{}, fmt.Errorf("error starting exec in container %s: %w", containerName, classifyError(err))
+	}
+	defer attached.Close()
+	// Closing the connection unblocks the copy when ctx ends first
+	stop := context.AfterFunc(ctx, attached.Close)
+	defer stop()
+
+	out := &cappedBuffer{limit: maxExecOutput}
+	// Without a TTY the stream multiplexes stdout and stderr
+	if _, err := stdcopy.StdCopy(out, out, attached.Reader); err != nil {
+		if ctx.Err() != nil {
+			return ExecResult{}, ctx.Err()
+		}
+		return ExecResult{}, fmt.Errorf("error reading exec output of container %s: %w", containerName, err)
+	}
+	code, err := d.waitExec(ctx, containerName, created.ID)
+	if err != nil {
+		return ExecResult{}, err
+	}
+	return ExecResult{ExitCode: code, Output: out.String(), Truncated: out.truncated}, nil
+}
+
+// cappedBuffer keeps the first limit bytes written to it and drops the rest.
+type cappedBuffer struct {
+	bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.Len(); len(p) > room {
+		b.truncated = true
+		b.Buffer.Write(p[:max(room, 0)])
+		return len(p), nil
+	}
+	return b.Buffer.Write(p)
+}
+
+// waitExec polls the exec until it terminates and returns its exit code.
+func (d *DockerRuntime) waitExec(ctx context.Context, containerName, execID string) (int, error) {
+	ticker := time.NewTicker(execPollInterval)
+	defer ticker.Stop()
+	for {
+		inspect, err := d.cli.ExecInspect(ctx, execID, client.ExecInspectOptions{})
+		if err != nil {
+			return 0, fmt.Errorf("error inspecting exec in container %s: %w", containerName, classifyError(err))
+		}
+		if !inspect.Running {
+			logger.WithComponent("docker").Debugf("exec in container %s exited with %d", containerName, inspect.ExitCode)
+			return inspect.ExitCode, nil
+		}
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-ticker.C:
+		}
 	}
-	return 0.0
 }
@@ -3,13 +3,16 @@ package runtime
 import (
 	"bytes"
 	"context"
+	"encoding/binary"
 	"encoding/json"
 	"errors"
 	"io"
+	"net"
 	"testing"
 
 	"github.com/containerd/errdefs"
 	"github.com/moby/moby/api/types/container"
+	"github.com/moby/moby/api/types/network"
 	"github.com/moby/moby/client"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -35,16 +38,61 @@ func (m *MockDockerClient) ContainerStop(ctx context.Context, containerID string
 	return args.Get(0).(client.ContainerStopResult), args.Error(1)
 }
 
+func (m *MockDockerClient) ContainerRemove(ctx context.Context, containerID string, options client.ContainerRemoveOptions) (client.ContainerRemoveResult, error) {
+	args := m.Called(ctx, containerID, options)
+	return args.Get(0).(client.ContainerRemoveResult), args.Error(1)
+}
+
+func (m *MockDockerClient) ContainerCreate(ctx context.Context, options client.ContainerCreateOptions) (client.ContainerCreateResult, error) {
+	args := m.Called(ctx, options)
+	return args.Get(0).(client.ContainerCreateResult), args.Error(1)
+}
+
 func (m *MockDockerClient) ContainerList(ctx context.Context, options client.ContainerListOptions) (client.ContainerListResult, error) {
 	args := m.Called(ctx, options)
 	return args.Get(0).(client.ContainerListResult), args.Error(1)
 }
 
+func (m *MockDockerClient) Events(ctx context.Context, options client.EventsListOptions) client.EventsResult {
+	args := m.Called(ctx, options)
+	return args.Get(0).(client.EventsResult)
+}
+
 func (m *MockDockerClient) ContainerStats(ctx context.Context, containerID string, options client.ContainerStatsOptions) (client.ContainerStatsResult, error) {
 	args := m.Called(ctx, containerID, options)
 	return args.Get(0).(client.ContainerStatsResult), args.Error(1)
 }
 
+func (m *MockDockerClient) Ping(ctx context.Context, options client.PingOptions) (client.PingResult, error) {
+	args := m.Called(ctx, options)
+	return args.Get(0).(client.PingResult), args.Error(1)
+}
+
+func (m *MockDockerClient) ServerVersion(ctx context.Context, options client.ServerVersionOptions) (client.ServerVersionResult, error) {
+	args := m.Called(ctx, options)
+	return args.Get(0).(client.ServerVersionResult), args.Error(1)
+}
+
+func (m *MockDockerClient) ExecCreate(ctx context.Context, containerID string, options client.ExecCreateOptions) (client.ExecCreateResult, error) {
+	args := m.Called(ctx, containerID, options)
+	return args.Get(0).(client.ExecCreateResult), args.Error(1)
+}
+
+func (m *MockDockerClient) ExecStart(ctx context.Context, execID string, options client.ExecStartOptions) (client.ExecStartResult, error) {
+	args := m.Called(ctx, execID, options)
+	return args.Get(0).(client.ExecStartResult), args.Error(1)
+}
+
+func (m *MockDockerClient) ExecInspect(ctx context.Context, execID string, options client.ExecInspectOptions) (client.ExecInspectResult, error) {
+	args := m.Called(ctx, execID, options)
+	return args.Get(0).(client.ExecInspectResult), args.Error(1)
+}
+
+func (m *MockDockerClient) ExecAttach(ctx context.Context, execID string, options client.ExecAttachOptions) (client.ExecAttachResult, error) {
+	args := m.Called(ctx, execID, options)
+	return args.Get(0).(client.ExecAttachResult), args.Error(1)
+}
+
 func TestNewDockerRuntimeWithClient(t *testing.T) {
 	mockClient := &MockDockerClient{}
 	dr := NewDockerRuntimeWithClient(mockClient)
@@ -134,7 +182,8 @@ func TestDockerRuntime_IsRunning_ContainerNotFound(t *testing.T) {
 
 	running, err := dr.IsRunning(ctx, containerName)
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "not found")
+	assert.ErrorIs(t, err, ErrContainerNotFound)
+	assert.Equal(t, "container non-existent-container not found", err.Error())
 	assert.False(t, running)
 	mockClient.AssertExpectations(t)
 }
@@ -258,6 +307,53 @@ func TestDockerRuntime_ListContainers_Empty(t *testing.T) {
 	mockClient.AssertExpectations(t)
 }
 
+func TestDockerRuntime_PublishedPorts(t *testing.T) {
+	mockClient := &MockDockerClient{}
+	dr := NewDockerRuntimeWithClient(mockClient)
+
+	ctx := context.Background()
+
+	listResult := client.ContainerListResult{
+		Items: []container.Summary{
+			{Names: []string{"/web"}, Ports: []container.PortSummary{
+				{PrivatePort: 443, PublicPort: 8443, Type: "tcp"},
+				{PrivatePort: 80, PublicPort: 8080, Type: "tcp"},
+				{PrivatePort: 80, PublicPort: 8080, Type: "tcp"},
+				{PrivatePort: 53, PublicPort: 5353, Type: "udp"},
+			}},
+			{Names: []string{"/internal"}, Ports: []container.PortSummary{{PrivatePort: 5432, Type: "tcp"}}},
+		},
+	}
+
+	mockClient.On("ContainerList", ctx, client.ContainerListOptions{All: true}).Return(listResult, nil)
+
+	ports, err := dr.PublishedPorts(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]uint16{"web": {8080, 8443}}, ports)
+	mockClient.AssertExpectations(t)
+}
+
+func TestDockerRuntime_ContainerLabels(t *testing.T) {
+	mockClient := &MockDockerClient{}
+	dr := NewDockerRuntimeWithClient(mockClient)
+
+	ctx := context.Background()
+
+	listResult := client.ContainerListResult{
+		Items: []container.Summary{
+			{Names: []string{"/web"}, Labels: map[string]string{"com.docker.compose.project": "media"}},
+			{Names: []string{"/bare"}},
+		},
+	}
+
+	mockClient.On("ContainerList", ctx, client.ContainerListOptions{All: true}).Return(listResult, nil)
+
+	labels, err := dr.ContainerLabels(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]map[string]string{"web": {"com.docker.compose.project": "media"}}, labels)
+	mockClient.AssertExpectations(t)
+}
+
 func TestDockerRuntime_ListContainers_Error(t *testing.T) {
 	mockClient := &MockDockerClient{}
 	dr := NewDockerRuntimeWithClient(mockClient)
@@ -315,6 +411,79 @@ func TestDockerRuntime_Stats_Success(t *testing.T) {
 	mockClient.AssertExpectations(t)
 }
 
+func TestDockerRuntime_Stats_ZeroDelta(t *testing.T) {
+	mockClient := &MockDockerClient{}
+	dr := NewDockerRuntimeWithClient(mockClient)
+
+	ctx := context.Background()
+	containerName := "just-started"
+
+	// A container that just started reports no previous sample and no online CPUs yet.
+	statsResponse := container.StatsResponse{
+		CPUStats: container.CPUStats{
+			CPUUsage: container.CPUUsage{
+				TotalUsage: 1000000,
+			},
+			SystemUsage: 0,
+		},
+		MemoryStats: container.MemoryStats{
+			Usage: 1048576, // 1 MB in bytes
+		},
+	}
+
+	statsJSON, _ := json.Marshal(statsResponse)
+	mockBody := io.NopCloser(bytes.NewReader(statsJSON))
+
+	mockClient.On("ContainerStats", ctx, containerName, client.ContainerStatsOptions{
+		Stream:                false,
+		IncludePreviousSample: true,
+	}).Return(client.ContainerStatsResult{Body: mockBody}, nil)
+
+	stats, err := dr.Stats(ctx, containerName)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.0, stats.CPUPercent)
+	assert.InDelta(t, 1.0, stats.MemoryMB, 0.01)
+
+	encoded, err := json.Marshal(stats)
+	assert.NoError(t, err)
+	assert.Contains(t, string(encoded), `"CPUPercent":0`)
+	mockClient.AssertExpectations(t)
+}
+
+func TestCalculateCPUPercent_PartialSamples(t *testing.T) {
+	tests := []struct {
+		name  string
+		stats container.StatsResponse
+	}{
+		{
+			name: "zero system delta",
+			stats: container.StatsResponse{
+				CPUStats:    container.CPUStats{CPUUsage: container.CPUUsage{TotalUsage: 200}, SystemUsage: 1000, OnlineCPUs: 2},
+				PreCPUStats: container.CPUStats{CPUUsage: container.CPUUsage{TotalUsage: 100}, SystemUsage: 1000},
+			},
+		},
+		{
+			name: "counters going backwards",
+			stats: container.StatsResponse{
+				CPUStats:    container.CPUStats{CPUUsage: container.CPUUsage{TotalUsage: 100}, SystemUsage: 500, OnlineCPUs: 2},
+				PreCPUStats: container.CPUStats{CPUUsage: container.CPUUsage{TotalUsage: 200}, SystemUsage: 1000},
+			},
+		},
+		{
+			name: "no online cpus",
+			stats: container.StatsResponse{
+				CPUStats:    container.CPUStats{CPUUsage: container.CPUUsage{TotalUsage: 200}, SystemUsage: 2000},
+				PreCPUStats: container.CPUStats{CPUUsage: container.CPUUsage{TotalUsage: 100}, SystemUsage: 1000},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, 0.0, calculateCPUPercent(&tt.stats))
+		})
+	}
+}
+
 func TestDockerRuntime_Stats_NotFound(t *testing.T) {
 	mockClient := &MockDockerClient{}
 	dr := NewDockerRuntimeWithClient(mockClient)
@@ -352,3 +521,255 @@ func TestDockerRuntime_Stats_Error(t *testing.T) {
 	assert.Equal(t, ContainerStats{}, stats)
 	mockClient.AssertExpectations(t)
 }
+
+func TestDockerRuntime_Ping_Success(t *testing.T) {
+	mockClient := &MockDockerClient{}
+	dr := NewDockerRuntimeWithClient(mockClient)
+	ctx := context.Background()
+
+	mockClient.On("Ping", ctx, client.PingOptions{}).Return(client.PingResult{APIVersion: "1.47"}, nil)
+	mockClient.On("ServerVersion", ctx, client.ServerVersionOptions{}).Return(client.ServerVersionResult{Version: "27.3.1", APIVersion: "1.47"}, nil)
+
+	result, err := dr.Ping(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "27.3.1", result.Version)
+	assert.Equal(t, "1.47", result.APIVersion)
+	mockClient.AssertExpectations(t)
+}
+
+func TestDockerRuntime_Ping_Error(t *testing.T) {
+	mockClient := &MockDockerClient{}
+	dr := NewDockerRuntimeWithClient(mockClient)
+	ctx := context.Background()
+
+	mockClient.On("Ping", ctx, client.PingOptions{}).Return(client.PingResult{}, errors.New("connection refused"))
+
+	_, err := dr.Ping(ctx)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "connection refused")
+	mockClient.AssertNotCalled(t, "ServerVersion", ctx, client.ServerVersionOptions{})
+}
+
+func TestDockerRuntime_Start_OverrideWithoutRecreateStartsNormally(t *testing.T) {
+	mockClient := &MockDockerClient{}
+	dr := NewDockerRuntimeWithClient(mockClient)
+	ctx := context.Background()
+
+	// Recreation not enabled: an override in the data file must not matter.
+	mockClient.On("ContainerStart", ctx, "web", client.ContainerStartOptions{}).Return(client.ContainerStartResult{}, nil)
+
+	assert.NoError(t, dr.Start(ctx, "web"))
+	mockClient.AssertExpectations(t)
+	mockClient.AssertNotCalled(t, "ContainerInspect", mock.Anything, mock.Anything, mock.Anything)
+	mockClient.AssertNotCalled(t, "ContainerRemove", mock.Anything, mock.Anything, mock.Anything)
+	mockClient.AssertNotCalled(t, "ContainerCreate", mock.Anything, mock.Anything)
+}
+
+func TestDockerRuntime_Start_RecreateEnabledWithoutOverride(t *testing.T) {
+	mockClient := &MockDockerClient{}
+	dr := NewDockerRuntimeWithClient(mockClient)
+	dr.EnableRecreate(func(string) []string { return nil })
+	ctx := context.Background()
+
+	mockClient.On("ContainerStart", ctx, "web", client.ContainerStartOptions{}).Return(client.ContainerStartResult{}, nil)
+
+	assert.NoError(t, dr.Start(ctx, "web"))
+	mockClient.AssertExpectations(t)
+	mockClient.AssertNotCalled(t, "ContainerRemove", mock.Anything, mock.Anything, mock.Anything)
+	mockClient.AssertNotCalled(t, "ContainerCreate", mock.Anything, mock.Anything)
+}
+
+func TestDockerRuntime_Start_RecreatesWithCommandOverride(t *testing.T) {
+	mockClient := &MockDockerClient{}
+	dr := NewDockerRuntimeWithClient(mockClient)
+	override := []string{"sh", "-c", "sleep infinity"}
+	dr.EnableRecreate(func(name string) []string {
+		if name == "web" {
+			return override
+		}
+		return nil
+	})
+	ctx := context.Background()
+
+	hostConfig := &container.HostConfig{NetworkMode: "bridge"}
+	mockClient.On("ContainerInspect", ctx, "web", client.ContainerInspectOptions{}).Return(client.ContainerInspectResult{
+		Container: container.InspectResponse{
+			State:      &container.State{Running: true},
+			Config:     &container.Config{Image: "nginx", Cmd: []string{"nginx"}},
+			HostConfig: hostConfig,
+			NetworkSettings: &container.NetworkSettings{Networks: map[string]*network.EndpointSettings{
+				"proxy": {Aliases: []string{"web"}, EndpointID: "abc"},
+			}},
+		},
+	}, nil)
+	mockClient.On("ContainerStop", ctx, "web", client.ContainerStopOptions{}).Return(client.ContainerStopResult{}, nil)
+	mockClient.On("ContainerRemove", ctx, "web", client.ContainerRemoveOptions{}).Return(client.ContainerRemoveResult{}, nil)
+	mockClient.On("ContainerCreate", ctx, mock.MatchedBy(func(opts client.ContainerCreateOptions) bool {
+		ep := opts.NetworkingConfig.EndpointsConfig["proxy"]
+		return opts.Name == "web" && opts.Config.Image == "nginx" && assert.ObjectsAreEqual(override, opts.Config.Cmd) &&
+			opts.HostConfig == hostConfig && ep != nil && ep.EndpointID == "" && len(ep.Aliases) == 1
+	})).Return(client.ContainerCreateResult{ID: "new"}, nil)
+	mockClient.On("ContainerStart", ctx, "web", client.ContainerStartOptions{}).Return(client.ContainerStartResult{}, nil)
+
+	assert.NoError(t, dr.Start(ctx, "web"))
+	mockClient.AssertExpectations(t)
+}
+
+func TestDockerRuntime_Start_OverrideAlreadyApplied(t *testing.T) {
+	mockClient := &MockDockerClient{}
+	dr := NewDockerRuntimeWithClient(mockClient)
+	dr.EnableRecreate(func(string) []string { return []string{"debug"} })
+	ctx := context.Background()
+
+	mockClient.On("ContainerInspect", ctx, "web", client.ContainerInspectOptions{}).Return(client.ContainerInspectResult{
+		Container: container.InspectResponse{
+			State:  &container.State{Running: false},
+			Config: &container.Config{Image: "nginx", Cmd: []string{"debug"}},
+		},
+	}, nil)
+	mockClient.On("ContainerStart", ctx, "web", client.ContainerStartOptions{}).Return(client.ContainerStartResult{}, nil)
+
+	assert.NoError(t, dr.Start(ctx, "web"))
+	mockClient.AssertExpectations(t)
+	mockClient.AssertNotCalled(t, "ContainerRemove", mock.Anything, mock.Anything, mock.Anything)
+	mockClient.AssertNotCalled(t, "ContainerCreate", mock.Anything, mock.Anything)
+}
+
+func TestDockerRuntime_IsRunning_DaemonUnavailable(t *testing.T) {
+	mockClient := &MockDockerClient{}
+	dr := NewDockerRuntimeWithClient(mockClient)
+	ctx := context.Background()
+
+	dr.retryDelay = 0
+
+	mockClient.On("ContainerInspect", ctx, "web", client.ContainerInspectOptions{}).
+		Return(client.ContainerInspectResult{}, errdefs.ErrUnavailable)
+
+	_, err := dr.IsRunning(ctx, "web")
+	assert.Error(t, err)
+	assert.True(t, IsUnavailable(err))
+	assert.ErrorIs(t, err, ErrDaemonUnavailable)
+	mockClient.AssertNumberOfCalls(t, "ContainerInspect", unavailableRetries+1)
+
+	health := dr.Health()
+	assert.Equal(t, unavailableRetries+1, health.ConsecutiveFailures)
+	assert.NotEmpty(t, health.LastError)
+	assert.False(t, health.LastFailure.IsZero())
+}
+
+func TestDockerRuntime_IsRunning_RetriesUntilDaemonIsBack(t *testing.T) {
+	mockClient := &MockDockerClient{}
+	dr := NewDockerRuntimeWithClient(mockClient)
+	dr.retryDelay = 0
+	ctx := context.Background()
+
+	mockClient.On("ContainerInspect", ctx, "web", client.ContainerInspectOptions{}).
+		Return(client.ContainerInspectResult{}, errdefs.ErrUnavailable).Once()
+	mockClient.On("ContainerInspect", ctx, "web", client.ContainerInspectOptions{}).
+		Return(client.ContainerInspectResult{Container: container.InspectResponse{State: &container.State{Running: true}}}, nil).Once()
+
+	running, err := dr.IsRunning(ctx, "web")
+	assert.NoError(t, err)
+	assert.True(t, running)
+	assert.Equal(t, 0, dr.Health().ConsecutiveFailures)
+	mockClient.AssertExpectations(t)
+}
+
+func TestDockerRuntime_IsRunning_OtherErrorIsNotUnavailable(t *testing.T) {
+	mockClient := &MockDockerClient{}
+	dr := NewDockerRuntimeWithClient(mockClient)
+	ctx := context.Background()
+
+	mockClient.On("ContainerInspect", ctx, "web", client.ContainerInspectOptions{}).
+		Return(client.ContainerInspectResult{}, errors.New("boom"))
+
+	_, err := dr.IsRunning(ctx, "web")
+	assert.Error(t, err)
+	assert.False(t, IsUnavailable(err))
+	mockClient.AssertNumberOfCalls(t, "ContainerInspect", 1)
+}
+
+func TestDockerRuntime_Stop_TypedErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"not found", errdefs.ErrNotFound, ErrContainerNotFound},
+		{"deadline", context.DeadlineExceeded, ErrTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := &MockDockerClient{}
+			dr := NewDockerRuntimeWithClient(mockClient)
+			ctx := context.Background()
+
+			mockClient.On("ContainerStop", ctx, "web", client.ContainerStopOptions{}).
+				Return(client.ContainerStopResult{}, tt.err)
+
+			err := dr.Stop(ctx, "web")
+			assert.ErrorIs(t, err, tt.want)
+			assert.ErrorIs(t, err, tt.err)
+			assert.False(t, IsUnavailable(err))
+		})
+	}
+}
+
+func TestDockerRuntime_Exec(t *testing.T) {
+	mockClient := &MockDockerClient{}
+	ctx := context.Background()
+	cmd := []string{"pg_isready"}
+	mockClient.On("ExecCreate", ctx, "db", client.ExecCreateOptions{Cmd: cmd}).Return(client.ExecCreateResult{ID: "exec1"}, nil)
+	mockClient.On("ExecStart", ctx, "exec1", client.ExecStartOptions{Detach: true}).Return(client.ExecStartResult{}, nil)
+	mockClient.On("ExecInspect", ctx, "exec1", client.ExecInspectOptions{}).Return(client.ExecInspectResult{Running: true}, nil).Once()
+	mockClient.On("ExecInspect", ctx, "exec1", client.ExecInspectOptions{}).Return(client.ExecInspectResult{ExitCode: 2}, nil).Once()
+
+	dr := NewDockerRuntimeWithClient(mockClient)
+	code, err := dr.Exec(ctx, "db", cmd)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, code)
+	mockClient.AssertExpectations(t)
+}
+
+// execFrame encodes payload as a frame of the multiplexed exec stream (1 = stdout, 2 = stderr).
+func execFrame(stream byte, payload string) []byte {
+	header := []byte{stream, 0, 0, 0, 0, 0, 0, 0}
+	binary.BigEndian.PutUint32(header[4:], uint32(len(payload)))
+	return append(header, payload...)
+}
+
+func TestDockerRuntime_ExecOutput(t *testing.T) {
+	mockClient := &MockDockerClient{}
+	ctx := context.Background()
+	cmd := []string{"redis-cli", "save"}
+	server, conn := net.Pipe()
+	go func() {
+		_, _ = server.Write(execFrame(1, "OK\n"))
+		_, _ = server.Write(execFrame(2, "warning\n"))
+		_ = server.Close()
+	}()
+	mockClient.On("ExecCreate", ctx, "cache", client.ExecCreateOptions{Cmd: cmd, AttachStdout: true, AttachStderr: true}).Return(client.ExecCreateResult{ID: "exec1"}, nil)
+	mockClient.On("ExecAttach", ctx, "exec1", client.ExecAttachOptions{}).
+		Return(client.ExecAttachResult{HijackedResponse: client.NewHijackedResponse(conn, "application/vnd.docker.multiplexed-stream")}, nil)
+	mockClient.On("ExecInspect", ctx, "exec1", client.ExecInspectOptions{}).Return(client.ExecInspectResult{ExitCode: 0}, nil)
+
+	dr := NewDockerRuntimeWithClient(mockClient)
+	result, err := dr.ExecOutput(ctx, "cache", cmd)
+
+	assert.NoError(t, err)
+	assert.Equal(t, ExecResult{ExitCode: 0, Output: "OK\nwarning\n"}, result)
+	mockClient.AssertExpectations(t)
+}
+
+func TestCappedBuffer(t *testing.T) {
+	b := &cappedBuffer{limit: 4}
+	n, err := b.Write([]byte("abc"))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+	n, _ = b.Write([]byte("defg"))
+	assert.Equal(t, 4, n)
+	_, _ = b.Write([]byte("h"))
+	assert.Equal(t, "abcd", b.String())
+	assert.True(t, b.truncated)
+}
@@ -0,0 +1,48 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnavailable marks errors caused by the runtime itself being unreachable (e.g. the Docker
+// daemon is down), as opposed to errors about a specific container.
+var ErrUnavailable = errors.New("runtime unavailable")
+
+// ErrDaemonUnavailable marks the errors of DockerRuntime caused by an unreachable Docker daemon.
+// It wraps ErrUnavailable, so IsUnavailable reports them too.
+var ErrDaemonUnavailable = fmt.Errorf("%w: docker daemon", ErrUnavailable)
+
+// ErrContainerNotFound matches the errors about a container unknown to the runtime, see
+// NotFoundError.
+var ErrContainerNotFound = errors.New("container not found")
+
+// ErrTimeout marks the runtime calls that did not answer before their deadline.
+var ErrTimeout = errors.New("runtime call timed out")
+
+// NotFoundError is returned for a container unknown to the runtime; errors.Is matches it with
+// ErrContainerNotFound.
+type NotFoundError struct {
+	// Kind is what the runtime manages, e.g. "container", "unit" or "machine".
+	Kind string
+	Name string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%s %s not found", e.Kind, e.Name)
+}
+
+// Is reports whether target is ErrContainerNotFound.
+func (e *NotFoundError) Is(target error) bool {
+	return target == ErrContainerNotFound
+}
+
+// IsUnavailable reports whether err was caused by an unreachable runtime.
+func IsUnavailable(err error) bool {
+	return errors.Is(err, ErrUnavailable)
+}
+
+// IsNotFound reports whether err is about a container unknown to the runtime.
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrContainerNotFound)
+}
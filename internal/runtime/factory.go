@@ -1,19 +1,30 @@
 package runtime
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/bassista/go_spin/internal/repository"
 )
 
 const (
-	RuntimeTypeDocker = "docker"
-	RuntimeTypeMemory = "memory"
+	RuntimeTypeDocker  = "docker"
+	RuntimeTypeMemory  = "memory"
+	RuntimeTypeSystemd = "systemd"
 )
 
+// SupportedRuntimeTypes lists the accepted values of misc.runtime_type.
+var SupportedRuntimeTypes = []string{RuntimeTypeDocker, RuntimeTypeMemory, RuntimeTypeSystemd}
+
+// ErrUnknownRuntimeType is returned by NewRuntimeFromConfig for an unsupported runtime type.
+var ErrUnknownRuntimeType = errors.New("unknown runtime type")
+
 // NewRuntimeFromConfig creates a ContainerRuntime based on the runtime type.
 // If runtimeType is "memory", it creates a MemoryRuntime initialized from the document.
+// If runtimeType is "systemd", it creates a SystemdRuntime managing systemd units.
 // If runtimeType is "docker" (default), it creates a DockerRuntime.
+// Any other value returns an error wrapping ErrUnknownRuntimeType that lists the supported types.
 func NewRuntimeFromConfig(runtimeType string, doc *repository.DataDocument) (ContainerRuntime, error) {
 	switch runtimeType {
 	case RuntimeTypeMemory:
@@ -21,9 +32,11 @@ func NewRuntimeFromConfig(runtimeType string, doc *repository.DataDocument) (Con
 			return NewMemoryRuntimeFromDocument(*doc), nil
 		}
 		return NewMemoryRuntime(), nil
+	case RuntimeTypeSystemd:
+		return NewSystemdRuntime(), nil
 	case RuntimeTypeDocker, "":
 		return NewDockerRuntime()
 	default:
-		return nil, fmt.Errorf("unknown runtime type: %s (supported: %s, %s)", runtimeType, RuntimeTypeDocker, RuntimeTypeMemory)
+		return nil, fmt.Errorf("%w %q (supported: %s)", ErrUnknownRuntimeType, runtimeType, strings.Join(SupportedRuntimeTypes, ", "))
 	}
 }
@@ -2,6 +2,8 @@ package runtime
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"sync"
 	"testing"
 
@@ -51,7 +53,7 @@ func TestNewRuntimeFromConfig_Docker(t *testing.T) {
 	_, err := NewRuntimeFromConfig(RuntimeTypeDocker, nil)
 	// If Docker is not available, we expect an error, but not "unknown runtime type"
 	if err != nil {
-		if err.Error() == "unknown runtime type: docker (supported: docker, memory)" {
+		if errors.Is(err, ErrUnknownRuntimeType) {
 			t.Error("docker should be a recognized runtime type")
 		}
 		// Other errors (like Docker not available) are acceptable in test environment
@@ -64,13 +66,23 @@ func TestNewRuntimeFromConfig_EmptyString(t *testing.T) {
 	_, err := NewRuntimeFromConfig("", nil)
 	if err != nil {
 		// If Docker is not available, we expect an error, but not "unknown runtime type"
-		if err.Error() == "unknown runtime type:  (supported: docker, memory)" {
+		if errors.Is(err, ErrUnknownRuntimeType) {
 			t.Error("empty string should default to docker")
 		}
 		t.Logf("Docker runtime error (may be expected if Docker not running): %v", err)
 	}
 }
 
+func TestNewRuntimeFromConfig_Systemd(t *testing.T) {
+	rt, err := NewRuntimeFromConfig(RuntimeTypeSystemd, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := rt.(*SystemdRuntime); !ok {
+		t.Error("expected SystemdRuntime type")
+	}
+}
+
 func TestNewRuntimeFromConfig_UnknownType(t *testing.T) {
 	_, err := NewRuntimeFromConfig("unknown-runtime", nil)
 	if err == nil {
@@ -78,6 +90,26 @@ func TestNewRuntimeFromConfig_UnknownType(t *testing.T) {
 	}
 }
 
+func TestNewRuntimeFromConfig_UnknownTypeListsSupported(t *testing.T) {
+	doc := &repository.DataDocument{}
+	rt, err := NewRuntimeFromConfig("bogus", doc)
+	if err == nil {
+		t.Fatal("expected error for unknown runtime type")
+	}
+	if rt != nil {
+		t.Error("expected no runtime for an unknown type")
+	}
+	if !errors.Is(err, ErrUnknownRuntimeType) {
+		t.Errorf("expected ErrUnknownRuntimeType, got %v", err)
+	}
+	msg := err.Error()
+	for _, want := range append([]string{`"bogus"`}, SupportedRuntimeTypes...) {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected error %q to mention %s", msg, want)
+		}
+	}
+}
+
 // ==================== Concurrency Tests ====================
 
 // TestNewRuntimeFromConfig_ConcurrentCreation verifies that creating multiple
@@ -0,0 +1,108 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+)
+
+// Errors of the optional interfaces forwarded to a runtime that does not implement them.
+var (
+	ErrExecUnsupported   = errors.New("runtime does not support exec")
+	errPortsUnsupported  = errors.New("runtime does not report published ports")
+	errLabelsUnsupported = errors.New("runtime does not report container labels")
+)
+
+// Forwarder forwards ContainerRuntime and the optional interfaces (PortLister, LabelLister,
+// HealthReporter, StateWatcher, Executor, OutputExecutor) to Inner. Wrappers embed it and only
+// override the methods they change, so a new optional interface is forwarded in one place.
+type Forwarder struct {
+	Inner ContainerRuntime
+}
+
+// Unwrap returns Inner.
+func (f Forwarder) Unwrap() ContainerRuntime {
+	return f.Inner
+}
+
+func (f Forwarder) IsRunning(ctx context.Context, containerName string) (bool, error) {
+	return f.Inner.IsRunning(ctx, containerName)
+}
+
+func (f Forwarder) Start(ctx context.Context, containerName string) error {
+	return f.Inner.Start(ctx, containerName)
+}
+
+func (f Forwarder) Stop(ctx context.Context, containerName string) error {
+	return f.Inner.Stop(ctx, containerName)
+}
+
+func (f Forwarder) ListContainers(ctx context.Context) ([]string, error) {
+	return f.Inner.ListContainers(ctx)
+}
+
+func (f Forwarder) Stats(ctx context.Context, containerName string) (ContainerStats, error) {
+	return f.Inner.Stats(ctx, containerName)
+}
+
+func (f Forwarder) Ping(ctx context.Context) (PingResult, error) {
+	return f.Inner.Ping(ctx)
+}
+
+// PublishedPorts forwards to Inner when it is a PortLister.
+func (f Forwarder) PublishedPorts(ctx context.Context) (map[string][]uint16, error) {
+	lister, ok := f.Inner.(PortLister)
+	if !ok {
+		return nil, errPortsUnsupported
+	}
+	return lister.PublishedPorts(ctx)
+}
+
+// ContainerLabels forwards to Inner when it is a LabelLister.
+func (f Forwarder) ContainerLabels(ctx context.Context) (map[string]map[string]string, error) {
+	lister, ok := f.Inner.(LabelLister)
+	if !ok {
+		return nil, errLabelsUnsupported
+	}
+	return lister.ContainerLabels(ctx)
+}
+
+// Health forwards to Inner when it is a HealthReporter, and reports no failure otherwise.
+func (f Forwarder) Health() Health {
+	if reporter, ok := f.Inner.(HealthReporter); ok {
+		return reporter.Health()
+	}
+	return Health{}
+}
+
+// WatchState forwards to Inner when it is a StateWatcher, and returns right away otherwise.
+func (f Forwarder) WatchState(ctx context.Context) {
+	if watcher, ok := f.Inner.(StateWatcher); ok {
+		watcher.WatchState(ctx)
+	}
+}
+
+// RunningStates forwards to Inner when it is a StateWatcher.
+func (f Forwarder) RunningStates() (map[string]bool, bool) {
+	if watcher, ok := f.Inner.(StateWatcher); ok {
+		return watcher.RunningStates()
+	}
+	return nil, false
+}
+
+// Exec forwards to Inner when it is an Executor.
+func (f Forwarder) Exec(ctx context.Context, containerName string, cmd []string) (int, error) {
+	executor, ok := f.Inner.(Executor)
+	if !ok {
+		return 0, ErrExecUnsupported
+	}
+	return executor.Exec(ctx, containerName, cmd)
+}
+
+// ExecOutput forwards to Inner when it is an OutputExecutor.
+func (f Forwarder) ExecOutput(ctx context.Context, containerName string, cmd []string) (ExecResult, error) {
+	executor, ok := f.Inner.(OutputExecutor)
+	if !ok {
+		return ExecResult{}, ErrExecUnsupported
+	}
+	return executor.ExecOutput(ctx, containerName, cmd)
+}
@@ -0,0 +1,52 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestForwarder_OptionalInterfaces(t *testing.T) {
+	ctx := context.Background()
+
+	// The memory runtime does not run commands
+	bare := Forwarder{Inner: NewMemoryRuntime()}
+	if _, err := bare.Exec(ctx, "app", []string{"true"}); !errors.Is(err, ErrExecUnsupported) {
+		t.Errorf("expected ErrExecUnsupported, got %v", err)
+	}
+	if _, err := bare.ExecOutput(ctx, "app", []string{"true"}); !errors.Is(err, ErrExecUnsupported) {
+		t.Errorf("expected ErrExecUnsupported, got %v", err)
+	}
+	if states, ok := bare.RunningStates(); ok || states != nil {
+		t.Errorf("expected no running states, got %v, %v", states, ok)
+	}
+
+	inner := &execRecordingRuntime{MemoryRuntime: NewMemoryRuntime(), exitCode: 3}
+	fwd := Forwarder{Inner: inner}
+	if code, err := fwd.Exec(ctx, "app", []string{"true"}); err != nil || code != 3 {
+		t.Errorf("expected Exec to be forwarded, got %d, %v", code, err)
+	}
+	if !reflect.DeepEqual(inner.ran, [][]string{{"true"}}) {
+		t.Errorf("expected the command to reach the wrapped runtime, got %v", inner.ran)
+	}
+}
+
+func TestSupports_LooksThroughWrappers(t *testing.T) {
+	// Wrappers implement every optional interface: only the wrapped runtime tells what is supported
+	memory := Forwarder{Inner: NewDispatcher(NewMemoryRuntime(), nil, nil)}
+	if _, ok := ContainerRuntime(memory).(PortLister); !ok {
+		t.Fatal("expected the Forwarder to implement PortLister")
+	}
+	if Supports[PortLister](memory) || Supports[LabelLister](memory) || Supports[Executor](memory) {
+		t.Error("expected a wrapped memory runtime to support no optional interface")
+	}
+
+	executor := Forwarder{Inner: NewDispatcher(&execRecordingRuntime{MemoryRuntime: NewMemoryRuntime()}, nil, nil)}
+	if !Supports[Executor](executor) {
+		t.Error("expected a wrapped executor to support Executor")
+	}
+	if !Supports[Executor](&execRecordingRuntime{MemoryRuntime: NewMemoryRuntime()}) {
+		t.Error("expected an unwrapped executor to support Executor")
+	}
+}
@@ -81,3 +81,9 @@ func (m *MemoryRuntime) Stats(_ context.Context, containerName string) (Containe
 		MemoryMB:   0.0,
 	}, nil
 }
+
+// Ping always succeeds for the in-memory runtime.
+func (m *MemoryRuntime) Ping(_ context.Context) (PingResult, error) {
+	logger.WithComponent("memory-runtime").Debugf("ping")
+	return PingResult{Version: "memory"}, nil
+}
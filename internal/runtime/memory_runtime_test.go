@@ -193,3 +193,14 @@ func TestMemoryRuntime_Stats(t *testing.T) {
 		t.Errorf("expected MemoryMB 0, got %v", stats.MemoryMB)
 	}
 }
+
+func TestMemoryRuntime_Ping(t *testing.T) {
+	mr := NewMemoryRuntime()
+	result, err := mr.Ping(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Version != "memory" {
+		t.Errorf("expected version 'memory', got %q", result.Version)
+	}
+}
@@ -0,0 +1,103 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/bassista/go_spin/internal/logger"
+	"github.com/bassista/go_spin/internal/repository"
+)
+
+// Pre-stop hook defaults.
+const (
+	preStopDefaultTimeout = 30 * time.Second
+	preStopDefaultMethod  = http.MethodPost
+)
+
+// PreStopLookup returns the pre-stop hook of a container, nil when it has none.
+type PreStopLookup func(containerName string) *repository.PreStopHook
+
+// PreStopRuntime wraps a ContainerRuntime so that Stop first runs the pre-stop hook of the
+// container, if it is running: an HTTP request to the hook URL or a command executed inside the
+// container. Stop waits for the hook up to its timeout; a failed hook is logged and the container
+// is stopped anyway. Other calls and the optional interfaces are forwarded to the wrapped runtime.
+type PreStopRuntime struct {
+	Forwarder
+	lookup PreStopLookup
+	client *http.Client
+}
+
+// NewPreStopRuntime wraps inner, reading the hook of a container with lookup.
+func NewPreStopRuntime(inner ContainerRuntime, lookup PreStopLookup) *PreStopRuntime {
+	return &PreStopRuntime{Forwarder: Forwarder{Inner: inner}, lookup: lookup, client: &http.Client{}}
+}
+
+// Stop runs the pre-stop hook of the container, then stops it.
+func (p *PreStopRuntime) Stop(ctx context.Context, containerName string) error {
+	if hook := p.lookup(containerName); hook != nil {
+		if running, err := p.Inner.IsRunning(ctx, containerName); err == nil && running {
+			if err := p.runHook(ctx, containerName, hook); err != nil {
+				logger.WithComponent("pre-stop").Warnf("pre-stop hook of %s failed, stopping anyway: %v", containerName, err)
+			}
+		}
+	}
+	return p.Inner.Stop(ctx, containerName)
+}
+
+// runHook runs hook within its timeout.
+func (p *PreStopRuntime) runHook(ctx context.Context, containerName string, hook *repository.PreStopHook) error {
+	timeout := preStopDefaultTimeout
+	if hook.TimeoutSecs > 0 {
+		timeout = time.Duration(hook.TimeoutSecs) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	var err error
+	if hook.URL != "" {
+		err = p.callURL(ctx, hook)
+	} else {
+		err = p.exec(ctx, containerName, hook.Command)
+	}
+	if err == nil {
+		logger.WithComponent("pre-stop").Debugf("pre-stop hook of %s completed in %s", containerName, time.Since(start).Round(time.Millisecond))
+	}
+	return err
+}
+
+func (p *PreStopRuntime) callURL(ctx context.Context, hook *repository.PreStopHook) error {
+	method := hook.Method
+	if method == "" {
+		method = preStopDefaultMethod
+	}
+	req, err := http.NewRequestWithContext(ctx, method, hook.URL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// Drain the body so the hook endpoint finishes its work before the container is stopped
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("%s %s returned %s", method, hook.URL, resp.Status)
+	}
+	return nil
+}
+
+func (p *PreStopRuntime) exec(ctx context.Context, containerName string, cmd []string) error {
+	exitCode, err := p.Exec(ctx, containerName, cmd)
+	if err != nil {
+		return err
+	}
+	if exitCode != 0 {
+		return fmt.Errorf("command %v exited with code %d", cmd, exitCode)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package runtime
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/bassista/go_spin/internal/repository"
+)
+
+// execRecordingRuntime is a memory runtime recording the commands run with Exec.
+type execRecordingRuntime struct {
+	*MemoryRuntime
+	ran      [][]string
+	exitCode int
+}
+
+func (r *execRecordingRuntime) Exec(_ context.Context, _ string, cmd []string) (int, error) {
+	r.ran = append(r.ran, cmd)
+	return r.exitCode, nil
+}
+
+func TestPreStopRuntime_URLHook(t *testing.T) {
+	ctx := context.Background()
+	var calls []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	hooks := map[string]*repository.PreStopHook{
+		"db":     {URL: srv.URL + "/api/shutdown"},
+		"cache":  {URL: srv.URL + "/broken", Method: http.MethodGet},
+		"cold":   {URL: srv.URL + "/cold"},
+		"silent": nil,
+	}
+	inner := NewMemoryRuntime()
+	rt := NewPreStopRuntime(inner, func(name string) *repository.PreStopHook { return hooks[name] })
+	for _, name := range []string{"db", "cache", "silent"} {
+		_ = inner.Start(ctx, name)
+	}
+
+	for _, name := range []string{"db", "cache", "cold", "silent"} {
+		if err := rt.Stop(ctx, name); err != nil {
+			t.Errorf("stop %s: %v", name, err)
+		}
+		if running, _ := inner.IsRunning(ctx, name); running {
+			t.Errorf("expected %s stopped", name)
+		}
+	}
+	// The hook of a stopped container is not called, a failed hook does not prevent the stop
+	if want := []string{"POST /api/shutdown", "GET /broken"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("expected hook calls %v, got %v", want, calls)
+	}
+}
+
+func TestPreStopRuntime_CommandHook(t *testing.T) {
+	ctx := context.Background()
+	inner := &execRecordingRuntime{MemoryRuntime: NewMemoryRuntime(), exitCode: 1}
+	hook := &repository.PreStopHook{Command: []string{"pg_ctl", "stop", "-m", "fast"}}
+	rt := NewPreStopRuntime(inner, func(string) *repository.PreStopHook { return hook })
+	_ = inner.Start(ctx, "db")
+
+	if err := rt.Stop(ctx, "db"); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(inner.ran, [][]string{hook.Command}) {
+		t.Errorf("expected the hook command run, got %v", inner.ran)
+	}
+	if running, _ := inner.IsRunning(ctx, "db"); running {
+		t.Error("expected db stopped despite the non-zero exit code")
+	}
+}
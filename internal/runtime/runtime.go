@@ -1,6 +1,9 @@
 package runtime
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // ContainerStats holds resource usage statistics for a container.
 type ContainerStats struct {
@@ -10,6 +13,23 @@ type ContainerStats struct {
 	MemoryMB float64
 }
 
+// PingResult describes a successful connectivity check against the runtime.
+type PingResult struct {
+	// Version is the runtime (daemon) version, when known.
+	Version string
+	// APIVersion is the API version negotiated with the runtime, when applicable.
+	APIVersion string
+}
+
+// Health describes the connection to a runtime as observed by its recent calls.
+type Health struct {
+	// ConsecutiveFailures counts the calls failed because the runtime was unreachable since the last success.
+	ConsecutiveFailures int
+	LastError           string
+	LastSuccess         time.Time
+	LastFailure         time.Time
+}
+
 // ContainerRuntime abstracts container lifecycle operations.
 // A Docker-socket implementation will be added later.
 type ContainerRuntime interface {
@@ -21,4 +41,80 @@ type ContainerRuntime interface {
 	ListContainers(ctx context.Context) ([]string, error)
 	// Stats returns CPU and memory usage statistics for a container.
 	Stats(ctx context.Context, containerName string) (ContainerStats, error)
+	// Ping checks connectivity with the runtime and reports its version.
+	Ping(ctx context.Context) (PingResult, error)
+}
+
+// PortLister is implemented by runtimes that can report the host ports published by containers.
+// It is optional: consumers check it with Supports and fall back to defaults when it is not
+// implemented.
+type PortLister interface {
+	// PublishedPorts returns, per container name, the sorted TCP host ports the container publishes.
+	PublishedPorts(ctx context.Context) (map[string][]uint16, error)
+}
+
+// LabelLister is implemented by runtimes that can report the labels of containers.
+// It is optional: consumers check it with Supports and go without labels when it is not implemented.
+type LabelLister interface {
+	// ContainerLabels returns, per container name, the labels of the containers that have any.
+	ContainerLabels(ctx context.Context) (map[string]map[string]string, error)
+}
+
+// Wrapper is implemented by runtimes forwarding to another one (Forwarder, Dispatcher). They
+// implement the optional interfaces whether the runtime they forward to does or not.
+type Wrapper interface {
+	// Unwrap returns the runtime the calls are forwarded to; for a Dispatcher the local one.
+	Unwrap() ContainerRuntime
+}
+
+// Supports reports whether the runtime rt forwards to, through any Wrapper, implements the optional
+// interface T. A plain type assertion on a wrapped runtime always succeeds.
+func Supports[T any](rt ContainerRuntime) bool {
+	for {
+		wrapper, ok := rt.(Wrapper)
+		if !ok {
+			break
+		}
+		rt = wrapper.Unwrap()
+	}
+	_, ok := rt.(T)
+	return ok
+}
+
+// HealthReporter is implemented by runtimes that track the health of their connection.
+// It is optional: consumers type-assert it and rely on Ping alone when it is not implemented.
+type HealthReporter interface {
+	Health() Health
+}
+
+// StateWatcher is implemented by runtimes that can keep the running state of every container in
+// memory from the runtime events, so IsRunning does not query the runtime on every call.
+// It is optional: the app type-asserts it and runs WatchState in the background.
+type StateWatcher interface {
+	// WatchState follows the runtime events until ctx is cancelled, subscribing again after
+	// failures. IsRunning queries the runtime while the state is not synced.
+	WatchState(ctx context.Context)
+	// RunningStates returns a copy of the cached running states, false when they are not synced.
+	RunningStates() (map[string]bool, bool)
+}
+
+// Executor is implemented by runtimes that can run a command inside a running container.
+// It is optional: consumers (exec health checks) type-assert it.
+type Executor interface {
+	// Exec runs cmd in the container and returns its exit code once it terminates.
+	Exec(ctx context.Context, containerName string, cmd []string) (int, error)
+}
+
+// ExecResult is the outcome of a command run by an OutputExecutor.
+type ExecResult struct {
+	ExitCode int
+	// Output holds stdout and stderr interleaved, cut when Truncated is set.
+	Output    string
+	Truncated bool
+}
+
+// OutputExecutor is implemented by runtimes that can run a command inside a running container and
+// capture its output. It is optional: the exec endpoint type-asserts it.
+type OutputExecutor interface {
+	ExecOutput(ctx context.Context, containerName string, cmd []string) (ExecResult, error)
 }
@@ -0,0 +1,67 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/bassista/go_spin/internal/logger"
+)
+
+// ErrSafeMode is returned by SafeModeRuntime for the start/stop actions it skipped.
+var ErrSafeMode = errors.New("safe mode: action skipped")
+
+// IsSafeMode reports whether err comes from an action skipped by safe mode.
+func IsSafeMode(err error) bool {
+	return errors.Is(err, ErrSafeMode)
+}
+
+// SafeModeRuntime wraps a ContainerRuntime so that, while safe mode is enabled, Start and Stop
+// leave containers untouched and return ErrSafeMode. Read operations always reach the wrapped
+// runtime, and optional interfaces are forwarded. Commands (Exec, ExecOutput) run regardless of
+// safe mode: they do not start or stop containers.
+type SafeModeRuntime struct {
+	Forwarder
+	enabled atomic.Bool
+}
+
+// NewSafeModeRuntime wraps inner with safe mode initially set to enabled.
+func NewSafeModeRuntime(inner ContainerRuntime, enabled bool) *SafeModeRuntime {
+	s := &SafeModeRuntime{Forwarder: Forwarder{Inner: inner}}
+	s.enabled.Store(enabled)
+	return s
+}
+
+// Enabled reports whether safe mode is on.
+func (s *SafeModeRuntime) Enabled() bool {
+	return s.enabled.Load()
+}
+
+// SetEnabled turns safe mode on or off.
+func (s *SafeModeRuntime) SetEnabled(enabled bool) {
+	if s.enabled.Swap(enabled) == enabled {
+		return
+	}
+	if enabled {
+		logger.WithComponent("safe-mode").Warnf("safe mode enabled: start/stop actions are skipped")
+	} else {
+		logger.WithComponent("safe-mode").Warnf("safe mode disabled")
+	}
+}
+
+func (s *SafeModeRuntime) Start(ctx context.Context, containerName string) error {
+	if s.Enabled() {
+		logger.WithComponent("safe-mode").Infof("safe mode: skipped start of %s", containerName)
+		return fmt.Errorf("start %s: %w", containerName, ErrSafeMode)
+	}
+	return s.Inner.Start(ctx, containerName)
+}
+
+func (s *SafeModeRuntime) Stop(ctx context.Context, containerName string) error {
+	if s.Enabled() {
+		logger.WithComponent("safe-mode").Infof("safe mode: skipped stop of %s", containerName)
+		return fmt.Errorf("stop %s: %w", containerName, ErrSafeMode)
+	}
+	return s.Inner.Stop(ctx, containerName)
+}
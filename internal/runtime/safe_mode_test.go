@@ -0,0 +1,36 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSafeModeRuntime_SkipsStartAndStopWhileEnabled(t *testing.T) {
+	ctx := context.Background()
+	inner := NewMemoryRuntime()
+	inner.running["up"] = true
+	rt := NewSafeModeRuntime(inner, true)
+
+	if err := rt.Start(ctx, "down"); !IsSafeMode(err) {
+		t.Errorf("expected start to be skipped with ErrSafeMode, got %v", err)
+	}
+	if err := rt.Stop(ctx, "up"); !IsSafeMode(err) {
+		t.Errorf("expected stop to be skipped with ErrSafeMode, got %v", err)
+	}
+	if inner.running["down"] || !inner.running["up"] {
+		t.Errorf("expected containers untouched in safe mode, got %v", inner.running)
+	}
+
+	// Reads still reach the wrapped runtime
+	if running, err := rt.IsRunning(ctx, "up"); err != nil || !running {
+		t.Errorf("expected IsRunning to be forwarded, got %v, %v", running, err)
+	}
+
+	rt.SetEnabled(false)
+	if err := rt.Start(ctx, "down"); err != nil {
+		t.Fatalf("expected start to be forwarded once safe mode is off, got %v", err)
+	}
+	if !inner.running["down"] {
+		t.Error("expected container started once safe mode is off")
+	}
+}
@@ -0,0 +1,190 @@
+package runtime
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bassista/go_spin/internal/logger"
+)
+
+// bytesPerMB converts the memory reported by systemd to megabytes.
+const bytesPerMB = 1024 * 1024
+
+// SystemctlRunner runs systemctl with args and returns its standard output.
+type SystemctlRunner func(ctx context.Context, args ...string) (string, error)
+
+// SystemdRuntime manages systemd units (e.g. a Jellyfin installed on the host) as containers: the
+// container name is the unit name, with ".service" implied when it has no unit suffix. It drives
+// systemd through systemctl, which talks to the service manager over D-Bus, so go_spin needs
+// access to the system bus and the permission (root or a polkit rule) to start and stop the units.
+type SystemdRuntime struct {
+	run SystemctlRunner
+
+	mu       sync.Mutex
+	cpuUsage map[string]cpuSample // last CPU usage read by Stats, per unit
+}
+
+type cpuSample struct {
+	nanos uint64
+	at    time.Time
+}
+
+// NewSystemdRuntime creates a SystemdRuntime for the system service manager.
+func NewSystemdRuntime() *SystemdRuntime {
+	return NewSystemdRuntimeWithRunner(systemctl)
+}
+
+// NewSystemdRuntimeWithRunner creates a SystemdRuntime running systemctl through run (for tests).
+func NewSystemdRuntimeWithRunner(run SystemctlRunner) *SystemdRuntime {
+	return &SystemdRuntime{run: run, cpuUsage: map[string]cpuSample{}}
+}
+
+// systemctl runs the systemctl command. A missing command or an unreachable bus wraps
+// ErrUnavailable; other failures carry the output of the command.
+func systemctl(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "systemctl", args...)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err == nil {
+		return string(out), nil
+	}
+	msg := strings.TrimSpace(stderr.String())
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || strings.Contains(msg, "Failed to connect to bus") {
+		return "", fmt.Errorf("%w: systemctl %s: %w: %s", ErrUnavailable, args[0], err, msg)
+	}
+	return "", fmt.Errorf("systemctl %s: %w: %s", args[0], err, msg)
+}
+
+// serviceSuffix is implied for container names without a unit suffix.
+const serviceSuffix = ".service"
+
+// unitName returns the unit of a container, adding the .service suffix when the name has none.
+func unitName(containerName string) string {
+	if strings.Contains(containerName, ".") {
+		return containerName
+	}
+	return containerName + serviceSuffix
+}
+
+// show returns the requested properties of the unit of containerName; a unit unknown to systemd
+// is a not found error.
+func (s *SystemdRuntime) show(ctx context.Context, containerName string, properties ...string) (map[string]string, error) {
+	args := []string{"show", unitName(containerName)}
+	for _, p := range append([]string{"LoadState"}, properties...) {
+		args = append(args, "--property="+p)
+	}
+	out, err := s.run(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	values := map[string]string{}
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		if key, value, ok := strings.Cut(scanner.Text(), "="); ok {
+			values[key] = value
+		}
+	}
+	if values["LoadState"] == "not-found" {
+		return nil, &NotFoundError{Kind: "unit", Name: unitName(containerName)}
+	}
+	return values, nil
+}
+
+func (s *SystemdRuntime) IsRunning(ctx context.Context, containerName string) (bool, error) {
+	values, err := s.show(ctx, containerName, "ActiveState")
+	if err != nil {
+		return false, err
+	}
+	state := values["ActiveState"]
+	logger.WithComponent("systemd").Debugf("unit %s is %s", unitName(containerName), state)
+	// A unit starting or reloading is treated like a running container
+	return state == "active" || state == "activating" || state == "reloading", nil
+}
+
+func (s *SystemdRuntime) Start(ctx context.Context, containerName string) error {
+	if _, err := s.show(ctx, containerName); err != nil {
+		return err
+	}
+	logger.WithComponent("systemd").Debugf("starting unit %s", unitName(containerName))
+	_, err := s.run(ctx, "start", unitName(containerName))
+	return err
+}
+
+func (s *SystemdRuntime) Stop(ctx context.Context, containerName string) error {
+	if _, err := s.show(ctx, containerName); err != nil {
+		return err
+	}
+	logger.WithComponent("systemd").Debugf("stopping unit %s", unitName(containerName))
+	_, err := s.run(ctx, "stop", unitName(containerName))
+	return err
+}
+
+// ListContainers returns the loaded service units without the .service suffix, so they match
+// the container names, sorted like the Docker runtime does.
+func (s *SystemdRuntime) ListContainers(ctx context.Context) ([]string, error) {
+	out, err := s.run(ctx, "list-units", "--type=service", "--all", "--no-legend", "--plain")
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, line := range strings.Split(out, "\n") {
+		if fields := strings.Fields(line); len(fields) > 0 {
+			names = append(names, strings.TrimSuffix(fields[0], serviceSuffix))
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return strings.ToLower(names[i]) < strings.ToLower(names[j])
+	})
+	return names, nil
+}
+
+// Stats returns the memory of the unit and its CPU usage since the previous call (0 on the first
+// call); the unit needs CPU and memory accounting, which is on by default on recent systemd.
+func (s *SystemdRuntime) Stats(ctx context.Context, containerName string) (ContainerStats, error) {
+	values, err := s.show(ctx, containerName, "CPUUsageNSec", "MemoryCurrent")
+	if err != nil {
+		return ContainerStats{}, err
+	}
+	var stats ContainerStats
+	if mem, err := strconv.ParseUint(values["MemoryCurrent"], 10, 64); err == nil {
+		stats.MemoryMB = float64(mem) / bytesPerMB
+	}
+	// Without accounting systemd reports "[not set]"
+	nanos, err := strconv.ParseUint(values["CPUUsageNSec"], 10, 64)
+	if err != nil {
+		return stats, nil
+	}
+	now := time.Now()
+	s.mu.Lock()
+	prev, ok := s.cpuUsage[containerName]
+	s.cpuUsage[containerName] = cpuSample{nanos: nanos, at: now}
+	s.mu.Unlock()
+	if elapsed := now.Sub(prev.at); ok && nanos >= prev.nanos && elapsed > 0 {
+		stats.CPUPercent = float64(nanos-prev.nanos) / float64(elapsed.Nanoseconds()) * 100.0
+	}
+	return stats, nil
+}
+
+// Ping checks that systemctl answers and reports the systemd version.
+func (s *SystemdRuntime) Ping(ctx context.Context) (PingResult, error) {
+	out, err := s.run(ctx, "--version")
+	if err != nil {
+		return PingResult{}, err
+	}
+	// The first line is like "systemd 255 (255.4-1ubuntu8)"
+	fields := strings.Fields(strings.SplitN(out, "\n", 2)[0])
+	if len(fields) < 2 {
+		return PingResult{}, nil
+	}
+	return PingResult{Version: fields[1]}, nil
+}
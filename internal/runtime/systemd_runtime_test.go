@@ -0,0 +1,79 @@
+package runtime
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// fakeSystemctl answers systemctl calls from fixed units and records the start/stop commands.
+type fakeSystemctl struct {
+	units    map[string]string // unit -> ActiveState
+	commands []string
+}
+
+func (f *fakeSystemctl) run(_ context.Context, args ...string) (string, error) {
+	switch args[0] {
+	case "show":
+		state, ok := f.units[args[1]]
+		if !ok {
+			return "LoadState=not-found\nActiveState=inactive\n", nil
+		}
+		return "LoadState=loaded\nActiveState=" + state + "\nCPUUsageNSec=[not set]\nMemoryCurrent=104857600\n", nil
+	case "start", "stop":
+		f.commands = append(f.commands, args[0]+" "+args[1])
+		if args[0] == "start" {
+			f.units[args[1]] = "active"
+		} else {
+			f.units[args[1]] = "inactive"
+		}
+		return "", nil
+	case "list-units":
+		return "jellyfin.service loaded active running Jellyfin\nsshd.service loaded active running OpenSSH\n", nil
+	case "--version":
+		return "systemd 255 (255.4-1ubuntu8)\n+PAM +AUDIT\n", nil
+	}
+	return "", nil
+}
+
+func TestSystemdRuntime(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeSystemctl{units: map[string]string{"jellyfin.service": "inactive", "backup.timer": "active"}}
+	rt := NewSystemdRuntimeWithRunner(fake.run)
+
+	if running, err := rt.IsRunning(ctx, "jellyfin"); err != nil || running {
+		t.Errorf("expected jellyfin stopped, got %v err=%v", running, err)
+	}
+	if running, err := rt.IsRunning(ctx, "backup.timer"); err != nil || !running {
+		t.Errorf("expected the timer unit running, got %v err=%v", running, err)
+	}
+	if err := rt.Start(ctx, "jellyfin"); err != nil {
+		t.Fatal(err)
+	}
+	if running, _ := rt.IsRunning(ctx, "jellyfin"); !running {
+		t.Error("expected jellyfin running after start")
+	}
+	if err := rt.Stop(ctx, "jellyfin"); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(fake.commands, []string{"start jellyfin.service", "stop jellyfin.service"}) {
+		t.Errorf("unexpected commands %v", fake.commands)
+	}
+
+	if err := rt.Start(ctx, "missing"); !IsNotFound(err) {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+	if len(fake.commands) != 2 {
+		t.Errorf("expected no command for a missing unit, got %v", fake.commands)
+	}
+
+	if names, err := rt.ListContainers(ctx); err != nil || !reflect.DeepEqual(names, []string{"jellyfin", "sshd"}) {
+		t.Errorf("expected jellyfin and sshd, got %v err=%v", names, err)
+	}
+	if stats, err := rt.Stats(ctx, "jellyfin"); err != nil || stats.MemoryMB != 100 || stats.CPUPercent != 0 {
+		t.Errorf("expected 100MB without cpu, got %+v err=%v", stats, err)
+	}
+	if res, err := rt.Ping(ctx); err != nil || res.Version != "255" {
+		t.Errorf("expected version 255, got %+v err=%v", res, err)
+	}
+}
@@ -0,0 +1,232 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bassista/go_spin/internal/logger"
+	"github.com/bassista/go_spin/internal/repository"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// RuntimeTypeWakeOnLAN is the runtimeType of the entries representing a physical machine.
+const RuntimeTypeWakeOnLAN = "wol"
+
+// Wake-on-LAN defaults.
+const (
+	wolDefaultBroadcast       = "255.255.255.255"
+	wolDefaultPort            = "9"
+	wolDefaultShutdownCommand = "sudo poweroff"
+	wolDefaultSSHPort         = "22"
+	wolProbeTimeout           = 2 * time.Second  // bounds the connection to ProbeAddress
+	wolSSHTimeout             = 10 * time.Second // bounds the SSH handshake of Stop
+	wolSyncBytes              = 6                // 0xFF bytes opening the magic packet
+	wolMagicRepetitions       = 16               // times the MAC is repeated in the magic packet
+	wolMACLength              = 6                // bytes of an EUI-48 address
+)
+
+// WakeOnLANLookup returns the Wake-on-LAN configuration of a container, nil when it has none.
+type WakeOnLANLookup func(containerName string) *repository.WakeOnLAN
+
+// SSHConfig holds the credentials used to shut machines down.
+type SSHConfig struct {
+	KeyFile        string // private key file
+	KnownHostsFile string // known_hosts file verifying the machines
+}
+
+// WakeOnLANRuntime manages physical machines as containers: Start sends a Wake-on-LAN magic
+// packet, Stop logs in over SSH and runs the shutdown command, and the machine is running while
+// its probe address accepts TCP connections. The machines are not listed and report no stats.
+type WakeOnLANRuntime struct {
+	lookup WakeOnLANLookup
+	ssh    SSHConfig
+}
+
+// NewWakeOnLANRuntime creates a WakeOnLANRuntime reading the machine of a container with lookup.
+func NewWakeOnLANRuntime(lookup WakeOnLANLookup, sshConfig SSHConfig) *WakeOnLANRuntime {
+	return &WakeOnLANRuntime{lookup: lookup, ssh: sshConfig}
+}
+
+func (w *WakeOnLANRuntime) machine(containerName string) (*repository.WakeOnLAN, error) {
+	m := w.lookup(containerName)
+	if m == nil {
+		return nil, &NotFoundError{Kind: "machine", Name: containerName}
+	}
+	return m, nil
+}
+
+// IsRunning reports whether the probe address of the machine accepts connections.
+func (w *WakeOnLANRuntime) IsRunning(ctx context.Context, containerName string) (bool, error) {
+	m, err := w.machine(containerName)
+	if err != nil {
+		return false, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, wolProbeTimeout)
+	defer cancel()
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", m.ProbeAddress)
+	if err != nil {
+		logger.WithComponent("wol").Debugf("machine %s not reachable at %s: %v", containerName, m.ProbeAddress, err)
+		return false, nil
+	}
+	_ = conn.Close()
+	return true, nil
+}
+
+// Start sends the magic packet waking the machine.
+func (w *WakeOnLANRuntime) Start(ctx context.Context, containerName string) error {
+	m, err := w.machine(containerName)
+	if err != nil {
+		return err
+	}
+	packet, err := magicPacket(m.MAC)
+	if err != nil {
+		return err
+	}
+	addr := broadcastAddress(m.Broadcast)
+	conn, err := listenBroadcast(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot open the wake-on-lan socket: %w", err)
+	}
+	defer conn.Close()
+	target, err := net.ResolveUDPAddr("udp4", addr)
+	if err != nil {
+		return fmt.Errorf("invalid broadcast address %s: %w", addr, err)
+	}
+	if _, err := conn.WriteTo(packet, target); err != nil {
+		return fmt.Errorf("cannot send the magic packet to %s: %w", addr, err)
+	}
+	logger.WithComponent("wol").Debugf("magic packet sent to %s for machine %s (%s)", addr, containerName, m.MAC)
+	return nil
+}
+
+// Stop runs the shutdown command of the machine over SSH.
+func (w *WakeOnLANRuntime) Stop(ctx context.Context, containerName string) error {
+	m, err := w.machine(containerName)
+	if err != nil {
+		return err
+	}
+	if m.ShutdownSSH == "" {
+		return fmt.Errorf("machine %s has no shutdownSsh to stop it", containerName)
+	}
+	if w.ssh.KeyFile == "" || w.ssh.KnownHostsFile == "" {
+		return errors.New("ssh shutdown requires runtime.wol_ssh_key_file and runtime.wol_ssh_known_hosts")
+	}
+	user, addr := sshTarget(m.ShutdownSSH)
+	config, err := w.clientConfig(user)
+	if err != nil {
+		return err
+	}
+
+	dialer := net.Dialer{Timeout: wolSSHTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("cannot reach %s: %w", addr, err)
+	}
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, addr, config)
+	if err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("ssh login to %s failed: %w", addr, err)
+	}
+	client := ssh.NewClient(sshConn, chans, reqs)
+	defer client.Close()
+	session, err := client.NewSession()
+	if err != nil {
+		return fmt.Errorf("ssh session on %s failed: %w", addr, err)
+	}
+	defer session.Close()
+
+	command := m.ShutdownCommand
+	if command == "" {
+		command = wolDefaultShutdownCommand
+	}
+	logger.WithComponent("wol").Debugf("shutting down machine %s with %q", containerName, command)
+	if out, err := session.CombinedOutput(command); err != nil {
+		// The connection may drop while the machine powers off
+		var exitMissing *ssh.ExitMissingError
+		if errors.As(err, &exitMissing) {
+			return nil
+		}
+		return fmt.Errorf("shutdown of %s failed: %w: %s", containerName, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func (w *WakeOnLANRuntime) clientConfig(user string) (*ssh.ClientConfig, error) {
+	key, err := os.ReadFile(w.ssh.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read runtime.wol_ssh_key_file: %w", err)
+	}
+	signer, err := ssh.ParsePrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid runtime.wol_ssh_key_file: %w", err)
+	}
+	hostKeys, err := knownhosts.New(w.ssh.KnownHostsFile)
+	if err != nil {
+		return nil, fmt.Errorf("invalid runtime.wol_ssh_known_hosts: %w", err)
+	}
+	return &ssh.ClientConfig{
+		User:            user,
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		HostKeyCallback: hostKeys,
+		Timeout:         wolSSHTimeout,
+	}, nil
+}
+
+// ListContainers returns no machines: they only exist as entries of the data document.
+func (w *WakeOnLANRuntime) ListContainers(_ context.Context) ([]string, error) {
+	return nil, nil
+}
+
+// Stats returns zero values: the usage of a machine is not measured.
+func (w *WakeOnLANRuntime) Stats(ctx context.Context, containerName string) (ContainerStats, error) {
+	if _, err := w.machine(containerName); err != nil {
+		return ContainerStats{}, err
+	}
+	return ContainerStats{}, nil
+}
+
+// Ping always succeeds: there is no daemon behind the machines.
+func (w *WakeOnLANRuntime) Ping(_ context.Context) (PingResult, error) {
+	return PingResult{Version: RuntimeTypeWakeOnLAN}, nil
+}
+
+// magicPacket builds the Wake-on-LAN packet of mac: 6 bytes 0xFF followed by the MAC 16 times.
+func magicPacket(mac string) ([]byte, error) {
+	hw, err := net.ParseMAC(mac)
+	if err != nil || len(hw) != wolMACLength {
+		return nil, fmt.Errorf("invalid mac address %q", mac)
+	}
+	packet := bytes.Repeat([]byte{0xFF}, wolSyncBytes)
+	return append(packet, bytes.Repeat(hw, wolMagicRepetitions)...), nil
+}
+
+// broadcastAddress returns the host:port the magic packet is sent to.
+func broadcastAddress(broadcast string) string {
+	if broadcast == "" {
+		broadcast = wolDefaultBroadcast
+	}
+	if _, _, err := net.SplitHostPort(broadcast); err == nil {
+		return broadcast
+	}
+	return net.JoinHostPort(broadcast, wolDefaultPort)
+}
+
+// sshTarget splits user@host[:port], defaulting the port to 22 and the user to root.
+func sshTarget(target string) (user, addr string) {
+	user, host, ok := strings.Cut(target, "@")
+	if !ok {
+		user, host = "root", target
+	}
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(host, wolDefaultSSHPort)
+	}
+	return user, host
+}
@@ -0,0 +1,92 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/bassista/go_spin/internal/repository"
+)
+
+func TestMagicPacket(t *testing.T) {
+	packet, err := magicPacket("aa:bb:cc:dd:ee:ff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(packet) != 102 || !bytes.Equal(packet[:6], bytes.Repeat([]byte{0xFF}, 6)) {
+		t.Fatalf("unexpected packet header %x", packet[:6])
+	}
+	mac := []byte{0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff}
+	for i := 6; i < len(packet); i += 6 {
+		if !bytes.Equal(packet[i:i+6], mac) {
+			t.Fatalf("unexpected mac at %d: %x", i, packet[i:i+6])
+		}
+	}
+	if _, err := magicPacket("not-a-mac"); err == nil {
+		t.Error("expected an error for an invalid mac")
+	}
+}
+
+func TestWakeOnLAN_Addresses(t *testing.T) {
+	if got := broadcastAddress(""); got != "255.255.255.255:9" {
+		t.Errorf("unexpected default broadcast %s", got)
+	}
+	if got := broadcastAddress("192.168.1.255"); got != "192.168.1.255:9" {
+		t.Errorf("unexpected broadcast %s", got)
+	}
+	if got := broadcastAddress("192.168.1.255:7"); got != "192.168.1.255:7" {
+		t.Errorf("unexpected broadcast with port %s", got)
+	}
+	if user, addr := sshTarget("admin@nas.lan"); user != "admin" || addr != "nas.lan:22" {
+		t.Errorf("unexpected ssh target %s %s", user, addr)
+	}
+	if user, addr := sshTarget("nas.lan:2222"); user != "root" || addr != "nas.lan:2222" {
+		t.Errorf("unexpected ssh target %s %s", user, addr)
+	}
+}
+
+func TestWakeOnLANRuntime(t *testing.T) {
+	ctx := context.Background()
+	probe, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer probe.Close()
+	receiver, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer receiver.Close()
+
+	machines := map[string]*repository.WakeOnLAN{
+		"nas": {MAC: "aa:bb:cc:dd:ee:ff", Broadcast: receiver.LocalAddr().String(), ProbeAddress: probe.Addr().String()},
+	}
+	rt := NewWakeOnLANRuntime(func(name string) *repository.WakeOnLAN { return machines[name] }, SSHConfig{})
+
+	if running, err := rt.IsRunning(ctx, "nas"); err != nil || !running {
+		t.Errorf("expected the machine running while its probe address answers, got %v err=%v", running, err)
+	}
+	if err := rt.Start(ctx, "nas"); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 256)
+	_ = receiver.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := receiver.ReadFrom(buf)
+	if err != nil || n != 102 {
+		t.Errorf("expected the magic packet, got %d bytes err=%v", n, err)
+	}
+
+	if err := rt.Stop(ctx, "nas"); err == nil {
+		t.Error("expected an error stopping a machine without shutdownSsh")
+	}
+	if _, err := rt.IsRunning(ctx, "missing"); err == nil {
+		t.Error("expected an error for a container without wake-on-lan configuration")
+	}
+
+	probe.Close()
+	if running, err := rt.IsRunning(ctx, "nas"); err != nil || running {
+		t.Errorf("expected the machine stopped once its probe address is closed, got %v err=%v", running, err)
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !unix
+
+package runtime
+
+import (
+	"context"
+	"net"
+)
+
+// listenBroadcast opens a UDP socket; outside unix the broadcast option is left to the system.
+func listenBroadcast(ctx context.Context) (net.PacketConn, error) {
+	var lc net.ListenConfig
+	return lc.ListenPacket(ctx, "udp4", ":0")
+}
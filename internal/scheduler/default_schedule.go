@@ -0,0 +1,51 @@
+package scheduler
+
+import "github.com/bassista/go_spin/internal/repository"
+
+// DefaultScheduleID identifies the default schedule in evaluations and plans.
+const DefaultScheduleID = "_default"
+
+// effectiveSchedules returns the schedules of doc followed by the default schedule, expanded into
+// one container schedule per active container that no schedule targets. A container targeted by a
+// specific schedule (directly or through a group, active or not) never follows the default.
+func effectiveSchedules(
+	doc repository.DataDocument,
+	containersByName map[string]repository.Container,
+	groupsByName map[string]repository.Group,
+) []repository.Schedule {
+	if doc.DefaultSchedule == nil || len(doc.DefaultSchedule.Timers) == 0 {
+		return doc.Schedules
+	}
+
+	targeted := map[string]bool{}
+	for _, sched := range doc.Schedules {
+		switch sched.TargetType {
+		case "container":
+			targeted[sched.Target] = true
+		case "group":
+			for _, name := range groupsByName[sched.Target].Container {
+				targeted[name] = true
+			}
+		}
+	}
+
+	schedules := append([]repository.Schedule{}, doc.Schedules...)
+	for _, c := range doc.Containers {
+		if c.Name == "" || targeted[c.Name] {
+			continue
+		}
+		if c.Active != nil && !*c.Active {
+			continue
+		}
+		if _, ok := containersByName[c.Name]; !ok {
+			continue
+		}
+		schedules = append(schedules, repository.Schedule{
+			ID:         DefaultScheduleID,
+			Target:     c.Name,
+			TargetType: "container",
+			Timers:     doc.DefaultSchedule.Timers,
+		})
+	}
+	return schedules
+}
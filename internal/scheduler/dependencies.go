@@ -0,0 +1,128 @@
+package scheduler
+
+import (
+	"sort"
+
+	"github.com/bassista/go_spin/internal/logger"
+	"github.com/bassista/go_spin/internal/repository"
+)
+
+// StartOrder returns names sorted for starting: higher Container.Priority first, then by name.
+// Names missing from containersByName have priority 0.
+func StartOrder(names []string, containersByName map[string]repository.Container) []string {
+	ordered := append([]string(nil), names...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		pi, pj := containersByName[ordered[i]].Priority, containersByName[ordered[j]].Priority
+		if pi != pj {
+			return pi > pj
+		}
+		return ordered[i] < ordered[j]
+	})
+	return ordered
+}
+
+// stopOrder sorts the containers to stop so that every container comes before the containers
+// it depends on (reverse DependsOn order). Only dependencies within names are considered.
+// Ties are broken by name for deterministic output; containers in a dependency cycle are
+// appended by name after the acyclic part.
+func stopOrder(names []string, containersByName map[string]repository.Container) []string {
+	if len(names) < 2 {
+		return names
+	}
+
+	inSet := make(map[string]bool, len(names))
+	for _, name := range names {
+		inSet[name] = true
+	}
+
+	// dependents[x] counts the containers in the set that still need x; x can stop once it reaches 0.
+	dependents := make(map[string]int, len(names))
+	for _, name := range names {
+		for _, dep := range containersByName[name].DependsOn {
+			if inSet[dep] && dep != name {
+				dependents[dep]++
+			}
+		}
+	}
+
+	sorted := append([]string(nil), names...)
+	sort.Strings(sorted)
+
+	ordered := make([]string, 0, len(names))
+	done := make(map[string]bool, len(names))
+	for len(ordered) < len(sorted) {
+		progressed := false
+		for _, name := range sorted {
+			if done[name] || dependents[name] > 0 {
+				continue
+			}
+			done[name] = true
+			ordered = append(ordered, name)
+			progressed = true
+			for _, dep := range containersByName[name].DependsOn {
+				if inSet[dep] && dep != name {
+					dependents[dep]--
+				}
+			}
+			// Restart from the first name so the order stays deterministic.
+			break
+		}
+		if !progressed {
+			break
+		}
+	}
+
+	if len(ordered) < len(sorted) {
+		var cyclic []string
+		for _, name := range sorted {
+			if !done[name] {
+				cyclic = append(cyclic, name)
+			}
+		}
+		logger.WithComponent("sched").Warnf("dependency cycle among %v, stopping them by name", cyclic)
+		ordered = append(ordered, cyclic...)
+	}
+	return ordered
+}
+
+// StartStages splits names into start stages following DependsOn: every container comes in a
+// later stage than the containers it depends on, so a database starts before the app needing it.
+// Only dependencies within names are considered. Each stage is in StartOrder; containers in a
+// dependency cycle form a last stage.
+func StartStages(names []string, containersByName map[string]repository.Container) [][]string {
+	inSet := make(map[string]bool, len(names))
+	for _, name := range names {
+		inSet[name] = true
+	}
+
+	placed := make(map[string]bool, len(names))
+	remaining := StartOrder(names, containersByName)
+	var stages [][]string
+	for len(remaining) > 0 {
+		var stage, next []string
+		for _, name := range remaining {
+			ready := true
+			for _, dep := range containersByName[name].DependsOn {
+				if inSet[dep] && dep != name && !placed[dep] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				stage = append(stage, name)
+			} else {
+				next = append(next, name)
+			}
+		}
+		if len(stage) == 0 {
+			logger.WithComponent("sched").Warnf("dependency cycle among %v, starting them together", next)
+			return append(stages, next)
+		}
+		for _, name := range stage {
+			placed[name] = true
+		}
+		stages = append(stages, stage)
+		remaining = next
+	}
+	return stages
+}
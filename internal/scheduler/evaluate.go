@@ -0,0 +1,83 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/bassista/go_spin/internal/logger"
+	"github.com/bassista/go_spin/internal/repository"
+)
+
+// ScheduleState is the evaluation of a single schedule at a given instant.
+type ScheduleState struct {
+	ScheduleID   string             `json:"scheduleId"`
+	ActiveTimers []repository.Timer `json:"activeTimers"`
+	Containers   []string           `json:"containers"` // active containers the schedule wants running now
+}
+
+// ActiveNow evaluates every schedule of doc at now (in its location), honoring the transient
+// extensions in ext (which may be nil). It returns one entry per schedule, in document order,
+// followed by one DefaultScheduleID entry per container following the default schedule.
+func ActiveNow(doc repository.DataDocument, now time.Time, ext *Extensions) []ScheduleState {
+	containersByName, groupsByName := indexDocument(doc)
+	_, states := evaluateDesiredState(effectiveSchedules(doc, containersByName, groupsByName), containersByName, groupsByName, now, ext)
+	return states
+}
+
+// evaluateDesiredState decides which containers should be running at now. The returned map has an
+// entry for every known container (false by default); states details each schedule's contribution.
+// This is the single source of truth shared by the scheduler tick and the read-only endpoints.
+func evaluateDesiredState(
+	schedules []repository.Schedule,
+	containersByName map[string]repository.Container,
+	groupsByName map[string]repository.Group,
+	now time.Time,
+	ext *Extensions,
+) (map[string]bool, []ScheduleState) {
+	// By default, no container should be running.
+	// This will be set to true if any active schedule/timer indicates it should be running now.
+	desiredRunning := make(map[string]bool, len(containersByName))
+	for name := range containersByName {
+		desiredRunning[name] = false
+	}
+
+	states := make([]ScheduleState, 0, len(schedules))
+	for _, sched := range schedules {
+		state := ScheduleState{ScheduleID: sched.ID, ActiveTimers: []repository.Timer{}, Containers: []string{}}
+
+		// Expand the schedule target into a list of container names (handles both "container" and "group" target types).
+		containerNames := expandScheduleTargets(sched, containersByName, groupsByName)
+		if len(containerNames) == 0 {
+			logger.WithComponent("sched").Debugf("schedule %s expanded to 0 containers", sched.ID)
+			states = append(states, state)
+			continue
+		}
+
+		logger.WithComponent("sched").Tracef("schedule %s (target: %s) expanded to %d containers", sched.ID, sched.Target, len(containerNames))
+		stopOffset := ext.Offset(sched.ID, now)
+		if stopOffset != 0 {
+			logger.WithComponent("sched").Debugf("schedule %s stop times offset by %v for today", sched.ID, stopOffset)
+		}
+		for _, timer := range sched.Timers {
+			if timer.Active != nil && !*timer.Active {
+				logger.WithComponent("sched").Debugf("timer inactive for schedule %s", sched.ID)
+				continue
+			}
+			// Check if this timer is currently active (within its start/stop window, considering days and cross-midnight).
+			if !isTimerActiveAt(timer, now, stopOffset) {
+				continue
+			}
+			logger.WithComponent("sched").Debugf("timer %s-%s is active for schedule %s, marking %d containers as running", timer.StartTime, timer.StopTime, sched.ID, len(containerNames))
+			state.ActiveTimers = append(state.ActiveTimers, timer)
+		}
+
+		if len(state.ActiveTimers) > 0 {
+			// Respect each container's own active flag.
+			state.Containers = activeContainers(containerNames, containersByName)
+			for _, name := range state.Containers {
+				desiredRunning[name] = true
+			}
+		}
+		states = append(states, state)
+	}
+	return desiredRunning, states
+}
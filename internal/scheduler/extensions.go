@@ -0,0 +1,104 @@
+package scheduler
+
+import (
+	"sync"
+	"time"
+)
+
+// MaxExtensionMinutes bounds a schedule extension: it only applies to the current day.
+const MaxExtensionMinutes = 24 * 60
+
+// Extension is a transient offset applied to the stop times of a schedule's timers.
+type Extension struct {
+	ScheduleID string `json:"scheduleId"`
+	Minutes    int    `json:"minutes"` // positive extends, negative shortens the active window
+	Day        string `json:"day"`     // day key (YYYY-MM-DD, scheduling timezone) the extension applies to
+}
+
+// Extensions keeps per-schedule stop time offsets in memory. An extension is only valid on the
+// day it was set: it is ignored and dropped once the day rolls over. The zero value is not usable;
+// use NewExtensions. A nil *Extensions behaves as an empty set.
+type Extensions struct {
+	mu      sync.Mutex
+	entries map[string]Extension
+}
+
+// NewExtensions creates an empty extension set.
+func NewExtensions() *Extensions {
+	return &Extensions{entries: map[string]Extension{}}
+}
+
+// Set registers (or replaces) the extension of scheduleID for the day of now.
+func (e *Extensions) Set(scheduleID string, minutes int, now time.Time) Extension {
+	ext := Extension{ScheduleID: scheduleID, Minutes: minutes, Day: dayKey(now)}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.entries[scheduleID] = ext
+	return ext
+}
+
+// Clear removes the extension of scheduleID, reporting whether one was set.
+func (e *Extensions) Clear(scheduleID string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	_, ok := e.entries[scheduleID]
+	delete(e.entries, scheduleID)
+	return ok
+}
+
+// Get returns the extension of scheduleID if it is valid for the day of now.
+func (e *Extensions) Get(scheduleID string, now time.Time) (Extension, bool) {
+	if e == nil {
+		return Extension{}, false
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	ext, ok := e.entries[scheduleID]
+	if !ok {
+		return Extension{}, false
+	}
+	if ext.Day != dayKey(now) {
+		// Day rollover: the extension has expired.
+		delete(e.entries, scheduleID)
+		return Extension{}, false
+	}
+	return ext, true
+}
+
+// Offset returns the stop time offset to apply to scheduleID at now (zero when none is active).
+func (e *Extensions) Offset(scheduleID string, now time.Time) time.Duration {
+	ext, ok := e.Get(scheduleID, now)
+	if !ok {
+		return 0
+	}
+	return time.Duration(ext.Minutes) * time.Minute
+}
+
+// Prune drops the extensions set on a day other than the day of now, returning how many it
+// dropped. Get already ignores them; pruning only bounds the memory of schedules never read again.
+func (e *Extensions) Prune(now time.Time) int {
+	if e == nil {
+		return 0
+	}
+	today := dayKey(now)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	removed := 0
+	for id, ext := range e.entries {
+		if ext.Day != today {
+			delete(e.entries, id)
+			removed++
+		}
+	}
+	return removed
+}
+
+// Len returns the number of extensions held, expired ones included until pruned.
+func (e *Extensions) Len() int {
+	if e == nil {
+		return 0
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return len(e.entries)
+}
@@ -0,0 +1,44 @@
+package scheduler
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// DefaultLoadAvgPath is the Linux pseudo-file exposing the host load averages.
+const DefaultLoadAvgPath = "/proc/loadavg"
+
+// LoadProvider reports the current host load used by the scheduler load guard.
+type LoadProvider interface {
+	// Load returns the 1-minute load average of the host.
+	Load() (float64, error)
+}
+
+// ProcLoadProvider reads the 1-minute load average from a /proc/loadavg formatted file.
+type ProcLoadProvider struct {
+	Path string
+}
+
+// NewProcLoadProvider creates a LoadProvider reading from /proc/loadavg.
+func NewProcLoadProvider() *ProcLoadProvider {
+	return &ProcLoadProvider{Path: DefaultLoadAvgPath}
+}
+
+// Load parses the first field of the loadavg file.
+func (p *ProcLoadProvider) Load() (float64, error) {
+	content, err := os.ReadFile(p.Path)
+	if err != nil {
+		return 0, fmt.Errorf("read load average: %w", err)
+	}
+	fields := strings.Fields(string(content))
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("read load average: empty file %s", p.Path)
+	}
+	load, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse load average: %w", err)
+	}
+	return load, nil
+}
@@ -0,0 +1,108 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bassista/go_spin/internal/repository"
+)
+
+// errNextEventsComplete stops the plan walk once every container has both its next events.
+var errNextEventsComplete = errors.New("next events complete")
+
+// NextEvent is the next scheduled start and stop of a container, nil when none falls within the horizon.
+type NextEvent struct {
+	NextStart *time.Time `json:"nextStart"`
+	NextStop  *time.Time `json:"nextStop"`
+}
+
+// NextEvents returns, for every named container of doc, the first start and the first stop planned
+// strictly after now and before now+horizon, from the same timeline as WalkPlan (nominal times,
+// schedule extensions are not applied).
+func NextEvents(ctx context.Context, doc repository.DataDocument, now time.Time, horizon time.Duration, loc *time.Location) (map[string]NextEvent, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	events := make(map[string]NextEvent, len(doc.Containers))
+	for _, c := range doc.Containers {
+		if c.Name != "" {
+			events[c.Name] = NextEvent{}
+		}
+	}
+	if horizon <= 0 || len(events) == 0 {
+		return events, nil
+	}
+
+	pending := len(events) * 2 // start and stop still unknown per container
+	err := WalkPlan(ctx, doc, now, now.Add(horizon), loc, func(a PlannedAction) error {
+		if !a.Time.After(now) {
+			return nil
+		}
+		event, ok := events[a.Container]
+		if !ok {
+			return nil
+		}
+		at := a.Time
+		switch {
+		case a.Action == ActionStart && event.NextStart == nil:
+			event.NextStart = &at
+		case a.Action == ActionStop && event.NextStop == nil:
+			event.NextStop = &at
+		default:
+			return nil
+		}
+		events[a.Container] = event
+		pending--
+		if pending == 0 {
+			return errNextEventsComplete
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errNextEventsComplete) {
+		return nil, err
+	}
+	return events, nil
+}
+
+// NextScheduleEvent returns the first start and the first stop planned by the schedule id (any of
+// its containers) strictly after now and before now+horizon. Like NextEvents it uses nominal times.
+func NextScheduleEvent(ctx context.Context, doc repository.DataDocument, id string, now time.Time, horizon time.Duration, loc *time.Location) (NextEvent, error) {
+	var event NextEvent
+	if horizon <= 0 {
+		return event, nil
+	}
+	err := WalkPlan(ctx, doc, now, now.Add(horizon), loc, func(a PlannedAction) error {
+		if a.ScheduleID != id || !a.Time.After(now) {
+			return nil
+		}
+		at := a.Time
+		switch {
+		case a.Action == ActionStart && event.NextStart == nil:
+			event.NextStart = &at
+		case a.Action == ActionStop && event.NextStop == nil:
+			event.NextStop = &at
+		}
+		if event.NextStart != nil && event.NextStop != nil {
+			return errNextEventsComplete
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errNextEventsComplete) {
+		return NextEvent{}, err
+	}
+	return event, nil
+}
+
+// HasSchedule reports whether id names a schedule of doc, or the default schedule when one is set.
+func HasSchedule(doc repository.DataDocument, id string) bool {
+	if id == DefaultScheduleID {
+		return doc.DefaultSchedule != nil && len(doc.DefaultSchedule.Timers) > 0
+	}
+	for _, sched := range doc.Schedules {
+		if sched.ID == id {
+			return true
+		}
+	}
+	return false
+}
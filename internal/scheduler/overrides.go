@@ -0,0 +1,177 @@
+package scheduler
+
+import (
+	"sync"
+	"time"
+
+	"github.com/bassista/go_spin/internal/repository"
+)
+
+// MaxOverrideDuration bounds a container override, so a forgotten one does not disable the
+// schedule stops forever.
+const MaxOverrideDuration = 7 * 24 * time.Hour
+
+// Overrides holds the manual overrides of the scheduler, in memory: paused schedules, which
+// neither start nor stop their containers, and per-container overrides suppressing the
+// schedule-driven stops until a given instant. The zero value is not usable; use NewOverrides.
+// A nil *Overrides behaves as an empty set.
+type Overrides struct {
+	mu         sync.Mutex
+	paused     map[string]time.Time // schedule id -> paused since
+	containers map[string]time.Time // container name -> stops suppressed until
+}
+
+// NewOverrides creates an empty override set.
+func NewOverrides() *Overrides {
+	return &Overrides{paused: map[string]time.Time{}, containers: map[string]time.Time{}}
+}
+
+// Pause pauses scheduleID from now on, until Resume.
+func (o *Overrides) Pause(scheduleID string, now time.Time) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if _, ok := o.paused[scheduleID]; !ok {
+		o.paused[scheduleID] = now
+	}
+}
+
+// Resume resumes scheduleID, reporting whether it was paused.
+func (o *Overrides) Resume(scheduleID string) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	_, ok := o.paused[scheduleID]
+	delete(o.paused, scheduleID)
+	return ok
+}
+
+// IsPaused reports whether scheduleID is paused.
+func (o *Overrides) IsPaused(scheduleID string) bool {
+	if o == nil {
+		return false
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	_, ok := o.paused[scheduleID]
+	return ok
+}
+
+// Paused returns the paused schedule ids with the instant each was paused.
+func (o *Overrides) Paused() map[string]time.Time {
+	paused := map[string]time.Time{}
+	if o == nil {
+		return paused
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for id, since := range o.paused {
+		paused[id] = since
+	}
+	return paused
+}
+
+// SetContainer suppresses the schedule-driven stops of name until until, replacing any previous override.
+func (o *Overrides) SetContainer(name string, until time.Time) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.containers[name] = until
+}
+
+// ClearContainer removes the override of name, reporting whether one was set (expired ones included).
+func (o *Overrides) ClearContainer(name string) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	_, ok := o.containers[name]
+	delete(o.containers, name)
+	return ok
+}
+
+// ContainerUntil returns the end of the override of name when it is still in force at now.
+func (o *Overrides) ContainerUntil(name string, now time.Time) (time.Time, bool) {
+	if o == nil {
+		return time.Time{}, false
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	until, ok := o.containers[name]
+	if !ok || !now.Before(until) {
+		return time.Time{}, false
+	}
+	return until, true
+}
+
+// Containers returns the container overrides still in force at now.
+func (o *Overrides) Containers(now time.Time) map[string]time.Time {
+	containers := map[string]time.Time{}
+	if o == nil {
+		return containers
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for name, until := range o.containers {
+		if now.Before(until) {
+			containers[name] = until
+		}
+	}
+	return containers
+}
+
+// Prune drops the container overrides expired at now, returning how many it dropped. Paused
+// schedules never expire.
+func (o *Overrides) Prune(now time.Time) int {
+	if o == nil {
+		return 0
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	removed := 0
+	for name, until := range o.containers {
+		if !now.Before(until) {
+			delete(o.containers, name)
+			removed++
+		}
+	}
+	return removed
+}
+
+// Len returns the number of paused schedules and container overrides held.
+func (o *Overrides) Len() int {
+	if o == nil {
+		return 0
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.paused) + len(o.containers)
+}
+
+// withoutPaused splits schedules into the ones not paused and the containers held by a pause:
+// targeted by a paused schedule and by no other one. The scheduler leaves held containers alone,
+// while containers also targeted by a running schedule follow that schedule only.
+func (o *Overrides) withoutPaused(
+	schedules []repository.Schedule,
+	containersByName map[string]repository.Container,
+	groupsByName map[string]repository.Group,
+) ([]repository.Schedule, map[string]bool) {
+	if o.Len() == 0 {
+		return schedules, nil
+	}
+	active := make([]repository.Schedule, 0, len(schedules))
+	held := map[string]bool{}
+	followed := map[string]bool{}
+	for _, sched := range schedules {
+		names := expandScheduleTargets(sched, containersByName, groupsByName)
+		if o.IsPaused(sched.ID) {
+			for _, name := range names {
+				held[name] = true
+			}
+			continue
+		}
+		active = append(active, sched)
+		for _, name := range names {
+			followed[name] = true
+		}
+	}
+	for name := range followed {
+		delete(held, name)
+	}
+	return active, held
+}
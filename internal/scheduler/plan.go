@@ -0,0 +1,217 @@
+package scheduler
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/bassista/go_spin/internal/repository"
+)
+
+const (
+	ActionStart = "start"
+	ActionStop  = "stop"
+)
+
+// PlannedAction is a single start or stop transition computed from a schedule timer.
+type PlannedAction struct {
+	Time       time.Time `json:"time"`
+	Container  string    `json:"container"`
+	Action     string    `json:"action"`
+	ScheduleID string    `json:"scheduleId"`
+}
+
+// WalkPlan computes the start/stop timeline of every active timer in doc within [from, to)
+// and calls fn for each action in chronological order. Actions are produced one day at a
+// time, so memory usage does not grow with the size of the range.
+// Inactive timers, containers and groups are skipped with the same semantics as the scheduler.
+func WalkPlan(ctx context.Context, doc repository.DataDocument, from, to time.Time, loc *time.Location, fn func(PlannedAction) error) error {
+	if loc == nil {
+		loc = time.Local
+	}
+	from = from.In(loc)
+	to = to.In(loc)
+
+	containersByName, groupsByName := indexDocument(doc)
+	schedules := effectiveSchedules(doc, containersByName, groupsByName)
+
+	// Start one day early so cross-midnight windows opened the day before still emit their stop.
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, -1)
+	// One-shot windows may span several days: those opened before the first anchor day still emit their stop.
+	pending := planOpenOneShots(schedules, containersByName, groupsByName, day)
+	for day.Before(to) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		nextDay := day.AddDate(0, 0, 1)
+		pending = append(pending, planDay(schedules, containersByName, groupsByName, day)...)
+		sort.SliceStable(pending, func(i, j int) bool {
+			return pending[i].Time.Before(pending[j].Time)
+		})
+
+		// Actions earlier than the next anchor day can no longer be preceded by anything else.
+		emitted := 0
+		for _, action := range pending {
+			if !action.Time.Before(nextDay) {
+				break
+			}
+			emitted++
+			if action.Time.Before(from) || !action.Time.Before(to) {
+				continue
+			}
+			if err := fn(action); err != nil {
+				return err
+			}
+		}
+		pending = pending[emitted:]
+		day = nextDay
+	}
+
+	for _, action := range pending {
+		if action.Time.Before(from) || !action.Time.Before(to) {
+			continue
+		}
+		if err := fn(action); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// planDay returns the actions of all timers anchored on the given day (start of day in loc).
+func planDay(
+	schedules []repository.Schedule,
+	containersByName map[string]repository.Container,
+	groupsByName map[string]repository.Group,
+	day time.Time,
+) []PlannedAction {
+	var actions []PlannedAction
+	for _, sched := range schedules {
+		containerNames := activeContainers(expandScheduleTargets(sched, containersByName, groupsByName), containersByName)
+		if len(containerNames) == 0 {
+			continue
+		}
+		for _, timer := range sched.Timers {
+			if timer.Active != nil && !*timer.Active {
+				continue
+			}
+			start, stop, ok := timerWindow(timer, day)
+			if !ok {
+				continue
+			}
+			for _, name := range containerNames {
+				actions = append(actions,
+					PlannedAction{Time: start, Container: name, Action: ActionStart, ScheduleID: sched.ID},
+					PlannedAction{Time: stop, Container: name, Action: ActionStop, ScheduleID: sched.ID},
+				)
+			}
+		}
+	}
+	return actions
+}
+
+// planOpenOneShots returns the actions of the one-shot timers that started before day and stop
+// on or after it, which planDay never anchors on a day of the walked range.
+func planOpenOneShots(
+	schedules []repository.Schedule,
+	containersByName map[string]repository.Container,
+	groupsByName map[string]repository.Group,
+	day time.Time,
+) []PlannedAction {
+	var actions []PlannedAction
+	for _, sched := range schedules {
+		containerNames := activeContainers(expandScheduleTargets(sched, containersByName, groupsByName), containersByName)
+		if len(containerNames) == 0 {
+			continue
+		}
+		for _, timer := range sched.Timers {
+			if !timer.IsOneShot() || (timer.Active != nil && !*timer.Active) {
+				continue
+			}
+			start, stop, err := timer.OneShotWindow(day.Location())
+			if err != nil || !start.Before(day) || stop.Before(day) {
+				continue
+			}
+			for _, name := range containerNames {
+				actions = append(actions,
+					PlannedAction{Time: start, Container: name, Action: ActionStart, ScheduleID: sched.ID},
+					PlannedAction{Time: stop, Container: name, Action: ActionStop, ScheduleID: sched.ID},
+				)
+			}
+		}
+	}
+	return actions
+}
+
+// sameDay reports whether a and b fall on the same calendar day in the location of b.
+func sameDay(a, b time.Time) bool {
+	a = a.In(b.Location())
+	return a.Year() == b.Year() && a.YearDay() == b.YearDay()
+}
+
+// timerWindow returns the start/stop instants of timer anchored on day, if the timer runs that weekday.
+// A stop time not after the start time is interpreted as the following day (cross-midnight window).
+// A one-shot timer is anchored on the day of its StartDate only.
+func timerWindow(timer repository.Timer, day time.Time) (time.Time, time.Time, bool) {
+	if timer.IsOneShot() {
+		start, stop, err := timer.OneShotWindow(day.Location())
+		if err != nil || !sameDay(start, day) {
+			return time.Time{}, time.Time{}, false
+		}
+		return start, stop, true
+	}
+	if !containsInt(timer.Days, int(day.Weekday())) {
+		return time.Time{}, time.Time{}, false
+	}
+	startClock, err := time.Parse("15:04", timer.StartTime)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	stopClock, err := time.Parse("15:04", timer.StopTime)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+
+	start := time.Date(day.Year(), day.Month(), day.Day(), startClock.Hour(), startClock.Minute(), 0, 0, day.Location())
+	stop := time.Date(day.Year(), day.Month(), day.Day(), stopClock.Hour(), stopClock.Minute(), 0, 0, day.Location())
+	if !stop.After(start) {
+		stop = stop.Add(24 * time.Hour)
+	}
+	return start, stop, true
+}
+
+// activeContainers filters out names that are unknown or whose container is not active.
+func activeContainers(names []string, containersByName map[string]repository.Container) []string {
+	out := make([]string, 0, len(names))
+	for _, name := range names {
+		c, ok := containersByName[name]
+		if !ok {
+			continue
+		}
+		if c.Active != nil && !*c.Active {
+			continue
+		}
+		out = append(out, name)
+	}
+	return out
+}
+
+// indexDocument builds name lookup maps for containers and groups, skipping unnamed entries.
+func indexDocument(doc repository.DataDocument) (map[string]repository.Container, map[string]repository.Group) {
+	containersByName := map[string]repository.Container{}
+	for _, c := range doc.Containers {
+		if c.Name == "" {
+			continue
+		}
+		containersByName[c.Name] = c
+	}
+
+	groupsByName := map[string]repository.Group{}
+	for _, g := range doc.Groups {
+		if g.Name == "" {
+			continue
+		}
+		groupsByName[g.Name] = g
+	}
+	return containersByName, groupsByName
+}
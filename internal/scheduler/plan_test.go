@@ -0,0 +1,195 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bassista/go_spin/internal/repository"
+)
+
+func collectPlan(t *testing.T, doc repository.DataDocument, from, to time.Time) []PlannedAction {
+	t.Helper()
+	var actions []PlannedAction
+	err := WalkPlan(context.Background(), doc, from, to, time.UTC, func(a PlannedAction) error {
+		actions = append(actions, a)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return actions
+}
+
+func TestWalkPlan_DailyWindow(t *testing.T) {
+	doc := repository.DataDocument{
+		Containers: []repository.Container{{Name: "c1", Active: boolPtr(true)}},
+		Schedules: []repository.Schedule{{
+			ID: "s1", Target: "c1", TargetType: "container",
+			Timers: []repository.Timer{{StartTime: "08:00", StopTime: "18:00", Days: []int{0, 1, 2, 3, 4, 5, 6}, Active: boolPtr(true)}},
+		}},
+	}
+	from := time.Date(2024, 3, 18, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 2)
+
+	actions := collectPlan(t, doc, from, to)
+
+	if len(actions) != 4 {
+		t.Fatalf("expected 4 actions, got %d: %v", len(actions), actions)
+	}
+	expected := []struct {
+		at     time.Time
+		action string
+	}{
+		{time.Date(2024, 3, 18, 8, 0, 0, 0, time.UTC), ActionStart},
+		{time.Date(2024, 3, 18, 18, 0, 0, 0, time.UTC), ActionStop},
+		{time.Date(2024, 3, 19, 8, 0, 0, 0, time.UTC), ActionStart},
+		{time.Date(2024, 3, 19, 18, 0, 0, 0, time.UTC), ActionStop},
+	}
+	for i, e := range expected {
+		if !actions[i].Time.Equal(e.at) || actions[i].Action != e.action || actions[i].Container != "c1" || actions[i].ScheduleID != "s1" {
+			t.Errorf("action %d: expected %s at %v, got %+v", i, e.action, e.at, actions[i])
+		}
+	}
+}
+
+func TestWalkPlan_CrossMidnightIsChronological(t *testing.T) {
+	doc := repository.DataDocument{
+		Containers: []repository.Container{{Name: "c1", Active: boolPtr(true)}, {Name: "c2", Active: boolPtr(true)}},
+		Schedules: []repository.Schedule{
+			{ID: "night", Target: "c1", TargetType: "container",
+				Timers: []repository.Timer{{StartTime: "22:00", StopTime: "02:00", Days: []int{1}, Active: boolPtr(true)}}},
+			{ID: "early", Target: "c2", TargetType: "container",
+				Timers: []repository.Timer{{StartTime: "01:00", StopTime: "03:00", Days: []int{2}, Active: boolPtr(true)}}},
+		},
+	}
+	// 2024-03-18 is a Monday
+	from := time.Date(2024, 3, 18, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 2)
+
+	actions := collectPlan(t, doc, from, to)
+
+	if len(actions) != 4 {
+		t.Fatalf("expected 4 actions, got %d: %v", len(actions), actions)
+	}
+	for i := 1; i < len(actions); i++ {
+		if actions[i].Time.Before(actions[i-1].Time) {
+			t.Errorf("actions not in chronological order: %v", actions)
+		}
+	}
+	if actions[2].ScheduleID != "night" || actions[2].Action != ActionStop {
+		t.Errorf("expected cross-midnight stop at 02:00, got %+v", actions[2])
+	}
+}
+
+func TestWalkPlan_SkipsInactiveEntries(t *testing.T) {
+	doc := repository.DataDocument{
+		Containers: []repository.Container{{Name: "c1", Active: boolPtr(false)}, {Name: "c2", Active: boolPtr(true)}},
+		Schedules: []repository.Schedule{
+			{ID: "s1", Target: "c1", TargetType: "container",
+				Timers: []repository.Timer{{StartTime: "08:00", StopTime: "09:00", Days: []int{0, 1, 2, 3, 4, 5, 6}, Active: boolPtr(true)}}},
+			{ID: "s2", Target: "c2", TargetType: "container",
+				Timers: []repository.Timer{{StartTime: "08:00", StopTime: "09:00", Days: []int{0, 1, 2, 3, 4, 5, 6}, Active: boolPtr(false)}}},
+		},
+	}
+	from := time.Date(2024, 3, 18, 0, 0, 0, 0, time.UTC)
+
+	actions := collectPlan(t, doc, from, from.AddDate(0, 0, 1))
+
+	if len(actions) != 0 {
+		t.Errorf("expected no actions, got %v", actions)
+	}
+}
+
+func TestWalkPlan_ContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	from := time.Date(2024, 3, 18, 0, 0, 0, 0, time.UTC)
+
+	err := WalkPlan(ctx, repository.DataDocument{}, from, from.AddDate(0, 0, 1), time.UTC, func(PlannedAction) error { return nil })
+	if err == nil {
+		t.Error("expected error for cancelled context")
+	}
+}
+
+func TestNextEvents_DailyWindow(t *testing.T) {
+	doc := repository.DataDocument{
+		Containers: []repository.Container{{Name: "c1", Active: boolPtr(true)}, {Name: "idle", Active: boolPtr(true)}},
+		Schedules: []repository.Schedule{{
+			ID: "s1", Target: "c1", TargetType: "container",
+			Timers: []repository.Timer{{StartTime: "08:00", StopTime: "18:00", Days: []int{0, 1, 2, 3, 4, 5, 6}, Active: boolPtr(true)}},
+		}},
+	}
+	horizon := 7 * 24 * time.Hour
+
+	// Inside today's window: the next stop is today, the next start is tomorrow.
+	now := time.Date(2024, 3, 18, 12, 0, 0, 0, time.UTC)
+	events, err := NextEvents(context.Background(), doc, now, horizon, time.UTC)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c1 := events["c1"]
+	if c1.NextStart == nil || !c1.NextStart.Equal(time.Date(2024, 3, 19, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected tomorrow's start, got %v", c1.NextStart)
+	}
+	if c1.NextStop == nil || !c1.NextStop.Equal(time.Date(2024, 3, 18, 18, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected today's stop, got %v", c1.NextStop)
+	}
+
+	// Before today's window: both events are today.
+	events, err = NextEvents(context.Background(), doc, time.Date(2024, 3, 18, 6, 0, 0, 0, time.UTC), horizon, time.UTC)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c1 = events["c1"]
+	if c1.NextStart == nil || !c1.NextStart.Equal(time.Date(2024, 3, 18, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected today's start, got %v", c1.NextStart)
+	}
+
+	idle, ok := events["idle"]
+	if !ok || idle.NextStart != nil || idle.NextStop != nil {
+		t.Errorf("expected null events for an unscheduled container, got %+v (present=%v)", idle, ok)
+	}
+}
+
+func TestNextEvents_BeyondHorizonIsNull(t *testing.T) {
+	doc := repository.DataDocument{
+		Containers: []repository.Container{{Name: "c1", Active: boolPtr(true)}},
+		Schedules: []repository.Schedule{{
+			ID: "s1", Target: "c1", TargetType: "container",
+			// Mondays only; 2024-03-19 is a Tuesday.
+			Timers: []repository.Timer{{StartTime: "08:00", StopTime: "18:00", Days: []int{1}, Active: boolPtr(true)}},
+		}},
+	}
+	now := time.Date(2024, 3, 19, 12, 0, 0, 0, time.UTC)
+
+	events, err := NextEvents(context.Background(), doc, now, 2*24*time.Hour, time.UTC)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if events["c1"].NextStart != nil || events["c1"].NextStop != nil {
+		t.Errorf("expected no event within the horizon, got %+v", events["c1"])
+	}
+}
+
+func TestWalkPlan_OneShotSpanningDays(t *testing.T) {
+	doc := repository.DataDocument{
+		Containers: []repository.Container{{Name: "plex", Active: boolPtr(true)}},
+		Schedules: []repository.Schedule{{
+			ID: "xmas", Target: "plex", TargetType: "container",
+			Timers: []repository.Timer{{StartDate: "2025-12-24 18:00", StopDate: "2025-12-26", Active: boolPtr(true)}},
+		}},
+	}
+
+	all := collectPlan(t, doc, time.Date(2025, 12, 20, 0, 0, 0, 0, time.UTC), time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC))
+	if len(all) != 2 || all[0].Action != ActionStart || !all[0].Time.Equal(time.Date(2025, 12, 24, 18, 0, 0, 0, time.UTC)) ||
+		all[1].Action != ActionStop || !all[1].Time.Equal(time.Date(2025, 12, 26, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected one start and one stop, got %+v", all)
+	}
+
+	// A range starting well inside the window still reports the stop.
+	tail := collectPlan(t, doc, time.Date(2025, 12, 25, 12, 0, 0, 0, time.UTC), time.Date(2025, 12, 27, 0, 0, 0, 0, time.UTC))
+	if len(tail) != 1 || tail[0].Action != ActionStop {
+		t.Errorf("expected only the stop, got %+v", tail)
+	}
+}
@@ -2,11 +2,15 @@ package scheduler
 
 import (
 	"context"
+	"errors"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/bassista/go_spin/internal/cache"
+	"github.com/bassista/go_spin/internal/events"
 	"github.com/bassista/go_spin/internal/logger"
+	"github.com/bassista/go_spin/internal/ops"
 	"github.com/bassista/go_spin/internal/repository"
 	"github.com/bassista/go_spin/internal/runtime"
 )
@@ -14,6 +18,8 @@ import (
 type DayFlags struct {
 	StartedDayKey string
 	StoppedDayKey string
+	// StoppedAt is when the scheduler last stopped the running container, used by the restart cooldown.
+	StoppedAt time.Time
 }
 
 // PollingScheduler evaluates schedules on a fixed interval and performs at most
@@ -23,6 +29,7 @@ type DayFlags struct {
 // - If StartedDayKey == today, start is never attempted again today, regardless of running state.
 // - If StoppedDayKey == today, stop is never attempted again today.
 // - Stop evaluation is only performed after a start evaluation has happened that day.
+// - A container with RestartCooldownSecs is not started again until the cooldown after its last stop elapsed.
 //
 // NOTE: Flags are in-memory only.
 type PollingScheduler struct {
@@ -31,115 +38,309 @@ type PollingScheduler struct {
 	poll    time.Duration
 	loc     *time.Location
 
+	// Optional load guard: starts are deferred while the host load exceeds maxLoad.
+	loadProvider LoadProvider
+	maxLoad      float64
+
+	// Optional change trigger: cache changes schedule an out-of-cycle tick after changeDebounce,
+	// never sooner than minTriggerInterval after the previous tick.
+	changeDebounce     time.Duration
+	minTriggerInterval time.Duration
+
+	// Optional transient stop time offsets per schedule (today only).
+	extensions *Extensions
+
+	// Optional manual overrides: paused schedules and containers whose stops are suppressed.
+	overrides *Overrides
+
+	// clock returns the current instant; time.Now unless overridden to drive or simulate time.
+	clock func() time.Time
+
+	// errLog deduplicates the per-container runtime error lines repeated on every tick.
+	errLog *logger.Deduper
+
+	// Optional staggering: each tick evaluates only one of shards slices of the containers,
+	// in round-robin, so a full sweep takes shards ticks. shards <= 1 evaluates all of them.
+	shards    int
+	nextShard int
+
+	// Optional bus receiving a ScheduleFired event for every start/stop performed by a schedule.
+	events *events.Bus
+
+	// Optional exact transitions: a timer armed at the next planned start/stop runs a full
+	// evaluation right then, instead of waiting up to one poll interval.
+	exactTransitions bool
+
+	// Optional operation queue the starts and stops go through, so they coalesce with the same
+	// actions requested by the API and the waiting page.
+	ops *ops.Queue
+
 	mu    sync.Mutex
 	flags map[string]DayFlags
 }
 
-func NewPollingScheduler(store cache.ReadOnlyStore, rt runtime.ContainerRuntime, poll time.Duration, loc *time.Location) *PollingScheduler {
+// Option customizes a PollingScheduler at construction time.
+type Option func(*PollingScheduler)
+
+// WithLoadGuard defers scheduled starts while provider reports a load above maxLoad.
+// A maxLoad <= 0 or a nil provider disables the guard.
+func WithLoadGuard(provider LoadProvider, maxLoad float64) Option {
+	return func(s *PollingScheduler) {
+		s.loadProvider = provider
+		s.maxLoad = maxLoad
+	}
+}
+
+// WithChangeTrigger makes the scheduler react to cache change events (when the store
+// implements cache.ChangeNotifier) with a debounced out-of-cycle tick. Bursts of changes
+// within debounce collapse into a single tick, and no tick runs sooner than minInterval
+// after the previous one. A debounce <= 0 disables the trigger.
+func WithChangeTrigger(debounce, minInterval time.Duration) Option {
+	return func(s *PollingScheduler) {
+		s.changeDebounce = debounce
+		s.minTriggerInterval = minInterval
+	}
+}
+
+// WithExtensions makes the scheduler honor the transient schedule extensions held by ext.
+func WithExtensions(ext *Extensions) Option {
+	return func(s *PollingScheduler) {
+		s.extensions = ext
+	}
+}
+
+// WithOverrides makes the scheduler honor the paused schedules and container overrides held by o.
+func WithOverrides(o *Overrides) Option {
+	return func(s *PollingScheduler) {
+		s.overrides = o
+	}
+}
+
+// WithClock replaces the scheduler's source of the current time (time.Now by default), so tests
+// and previews can evaluate schedules at arbitrary instants. A nil clock keeps the default.
+func WithClock(clock func() time.Time) Option {
+	return func(s *PollingScheduler) {
+		if clock != nil {
+			s.clock = clock
+		}
+	}
+}
+
+// WithShards spreads the evaluation over n ticks: containers, sorted by name, are split into n
+// slices of equal size and each tick evaluates the next slice, bounding the runtime calls of a
+// tick on large installations. n <= 1 evaluates every container on every tick.
+func WithShards(n int) Option {
+	return func(s *PollingScheduler) {
+		s.shards = n
+	}
+}
+
+// WithEvents publishes an events.ScheduleFired event on bus for every container the scheduler
+// starts or stops. A nil bus disables the events.
+func WithEvents(bus *events.Bus) Option {
+	return func(s *PollingScheduler) {
+		s.events = bus
+	}
+}
+
+// WithExactTransitions arms a timer at the next start/stop planned by the schedules (nominal
+// times, see WalkPlan) and evaluates every container when it fires, so windows open and close on
+// time instead of within a poll interval. The timer is re-armed after every evaluation; polling
+// keeps running to catch what the plan does not show (extensions, runtime drift, data changes).
+func WithExactTransitions(enabled bool) Option {
+	return func(s *PollingScheduler) {
+		s.exactTransitions = enabled
+	}
+}
+
+// WithErrorLogDedup collapses the identical per-container error lines (e.g. a runtime that is down)
+// logged on the ticks within window into one line with a repeat count. A window <= 0, the
+// default, logs every line.
+func WithErrorLogDedup(window time.Duration) Option {
+	return func(s *PollingScheduler) {
+		s.errLog = logger.NewDeduper(window)
+	}
+}
+
+// WithOperations sends the starts and stops through q instead of calling the runtime directly.
+// A nil q keeps the direct calls.
+func WithOperations(q *ops.Queue) Option {
+	return func(s *PollingScheduler) {
+		s.ops = q
+	}
+}
+
+func NewPollingScheduler(store cache.ReadOnlyStore, rt runtime.ContainerRuntime, poll time.Duration, loc *time.Location, opts ...Option) *PollingScheduler {
 	if loc == nil {
 		loc = time.Local
 	}
 
-	return &PollingScheduler{
+	s := &PollingScheduler{
 		store:   store,
 		runtime: rt,
 		poll:    poll,
 		loc:     loc,
 		flags:   map[string]DayFlags{},
+		clock:   time.Now,
+		errLog:  logger.NewDeduper(0),
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *PollingScheduler) Start(ctx context.Context) {
 	logger.WithComponent("sched").Debugf("starting polling scheduler with interval: %v, timezone: %s", s.poll, s.loc.String())
 	ticker := time.NewTicker(s.poll)
+
+	var changes <-chan cache.ChangeEvent
+	unsubscribe := func() {}
+	if notifier, ok := s.store.(cache.ChangeNotifier); ok && s.changeDebounce > 0 {
+		changes, unsubscribe = notifier.Subscribe()
+		logger.WithComponent("sched").Debugf("scheduler reacting to cache changes, debounce: %v, min interval: %v", s.changeDebounce, s.minTriggerInterval)
+	}
+
 	go func() {
 		defer ticker.Stop()
+		defer unsubscribe()
+
+		// trigger is nil while no out-of-cycle tick is pending.
+		var triggerTimer *time.Timer
+		var trigger <-chan time.Time
+		var lastTick time.Time
+
+		// transition is nil while no planned start/stop is armed.
+		var transitionTimer *time.Timer
+		var transition <-chan time.Time
+		armTransition := func() {
+			if !s.exactTransitions {
+				return
+			}
+			next, ok := s.nextTransition(ctx)
+			if !ok {
+				transition = nil
+				return
+			}
+			delay := max(next.Sub(s.clock()), 0)
+			if transitionTimer == nil {
+				transitionTimer = time.NewTimer(delay)
+			} else {
+				transitionTimer.Reset(delay)
+			}
+			transition = transitionTimer.C
+			logger.WithComponent("sched").Debugf("next planned transition at %s (in %v)", next.Format(time.RFC3339), delay)
+		}
+		runTick := func() {
+			s.tick(ctx)
+			lastTick = s.clock()
+			armTransition()
+		}
+		armTransition()
+
 		for {
 			select {
 			case <-ctx.Done():
+				if triggerTimer != nil {
+					triggerTimer.Stop()
+				}
+				if transitionTimer != nil {
+					transitionTimer.Stop()
+				}
 				logger.WithComponent("sched").Info("scheduler stopped")
 				return
 			case <-ticker.C:
-				s.tick(ctx)
+				runTick()
+			case <-transition:
+				transition = nil
+				logger.WithComponent("sched").Debugf("running tick at planned transition")
+				s.evaluate(ctx, true)
+				lastTick = s.clock()
+				armTransition()
+			case ev, ok := <-changes:
+				if !ok {
+					changes = nil
+					continue
+				}
+				delay := s.changeDebounce
+				if wait := s.minTriggerInterval - s.clock().Sub(lastTick); wait > delay {
+					delay = wait
+				}
+				logger.WithComponent("sched").Tracef("cache change (%s %s), evaluating schedules in %v", ev.Kind, ev.Name, delay)
+				if triggerTimer == nil {
+					triggerTimer = time.NewTimer(delay)
+				} else {
+					triggerTimer.Reset(delay)
+				}
+				trigger = triggerTimer.C
+			case <-trigger:
+				trigger = nil
+				logger.WithComponent("sched").Debugf("running out-of-cycle tick after cache change")
+				runTick()
 			}
 		}
 	}()
 }
 
 func (s *PollingScheduler) tick(ctx context.Context) {
+	s.evaluate(ctx, false)
+}
+
+// warnDanglingTargets logs the schedules whose target container or group does not exist: they
+// are skipped by the evaluation.
+func (s *PollingScheduler) warnDanglingTargets(schedules []repository.Schedule, containersByName map[string]repository.Container, groupsByName map[string]repository.Group) {
+	for _, schedule := range schedules {
+		missing := false
+		switch schedule.TargetType {
+		case "container":
+			_, found := containersByName[schedule.Target]
+			missing = !found
+		case "group":
+			_, found := groupsByName[schedule.Target]
+			missing = !found
+		}
+		if missing {
+			s.errLog.Warnf(logger.WithComponent("sched"), "schedule %s targets unknown %s %s, ignored", schedule.ID, schedule.TargetType, schedule.Target)
+		}
+	}
+}
+
+// evaluate runs one scheduler pass. allShards evaluates every container regardless of sharding
+// (used at planned transitions) and leaves the round-robin shard position untouched.
+func (s *PollingScheduler) evaluate(ctx context.Context, allShards bool) {
 	logger.WithComponent("sched").Debugf("polling scheduler tick started")
+	// Report the repeat counts of error lines that stopped recurring.
+	defer s.errLog.Flush()
 	doc, err := s.store.Snapshot()
 	if err != nil {
 		logger.WithComponent("sched").Errorf("snapshot error: %v", err)
 		return
 	}
 
-	now := time.Now().In(s.loc)
+	now := s.clock().In(s.loc)
 	todayKey := dayKey(now)
 	logger.WithComponent("sched").Debugf("evaluating schedules for today: %s, current time: %s", todayKey, now.Format("15:04:05"))
 
 	// Build lookup maps for efficient access during schedule evaluation.
-	containersByName := map[string]repository.Container{}
-	for _, c := range doc.Containers {
-		if c.Name == "" {
-			continue
-		}
-		containersByName[c.Name] = c
-	}
-
-	groupsByName := map[string]repository.Group{}
-	for _, g := range doc.Groups {
-		if g.Name == "" {
-			continue
-		}
-		groupsByName[g.Name] = g
-	}
-
-	// Initialize desiredRunning map: by default, no container should be running.
-	// This will be set to true if any active schedule/timer indicates it should be running now.
-	desiredRunning := map[string]bool{}
-	for name := range containersByName {
-		desiredRunning[name] = false
-	}
+	containersByName, groupsByName := indexDocument(doc)
+	s.warnDanglingTargets(doc.Schedules, containersByName, groupsByName)
 
 	// Evaluate all schedules to determine which containers should be running based on active timers.
-	for _, sched := range doc.Schedules {
-		// Expand the schedule target into a list of container names (handles both "container" and "group" target types).
-		containerNames := expandScheduleTargets(sched, containersByName, groupsByName)
-		if len(containerNames) == 0 {
-			logger.WithComponent("sched").Debugf("schedule %s expanded to 0 containers", sched.ID)
-			continue
-		}
+	// Containers without a schedule of their own follow the default schedule, if any.
+	// Paused schedules do not contribute; the containers only they target are left alone.
+	schedules, held := s.overrides.withoutPaused(effectiveSchedules(doc, containersByName, groupsByName), containersByName, groupsByName)
+	desiredRunning, _ := evaluateDesiredState(schedules, containersByName, groupsByName, now, s.extensions)
 
-		logger.WithComponent("sched").Tracef("schedule %s (target: %s) expanded to %d containers", sched.ID, sched.Target, len(containerNames))
-		for _, timer := range sched.Timers {
-			if timer.Active != nil && !*timer.Active {
-				logger.WithComponent("sched").Debugf("timer inactive for schedule %s", sched.ID)
-				continue
-			}
-			// Check if this timer is currently active (within its start/stop window, considering days and cross-midnight).
-			if !isTimerActiveNow(timer, now) {
-				continue
-			}
+	// The host load is sampled lazily, at most once per tick, only when a start is needed.
+	loadChecked := false
+	deferStarts := false
 
-			logger.WithComponent("sched").Debugf("timer %s-%s is active for schedule %s, marking %d containers as running", timer.StartTime, timer.StopTime, sched.ID, len(containerNames))
-			// For each container targeted by this schedule, mark it as desired running if the container itself is active.
-			for _, containerName := range containerNames {
-				c, ok := containersByName[containerName]
-				if !ok {
-					continue
-				}
-				// Respect the container's own active flag.
-				if c.Active != nil && !*c.Active {
-					continue
-				}
-				desiredRunning[containerName] = true
-			}
-		}
-	}
+	// Stops are collected and executed after the loop so they can follow reverse dependency order.
+	var toStop []string
 
-	// For each container, decide whether to start or stop based on desired state and day-key flags.
-	for containerName := range containersByName {
+	// For each container of this tick's shard, decide whether to start or stop based on desired state and day-key flags.
+	// Starts are issued inline, so the shard is walked in start priority order.
+	for _, containerName := range StartOrder(s.shardNames(containersByName, allShards), containersByName) {
 		// Check for context cancellation to allow early exit during long iterations
 		select {
 		case <-ctx.Done():
@@ -148,6 +349,10 @@ func (s *PollingScheduler) tick(ctx context.Context) {
 		default:
 		}
 
+		if held[containerName] {
+			logger.WithComponent("sched").Debugf("container %s only targeted by paused schedules, skipping", containerName)
+			continue
+		}
 		flags := s.getFlags(containerName)
 		shouldRun := desiredRunning[containerName]
 		logger.WithComponent("sched").Debugf("container %s: shouldRun=%v, startedToday=%v, stoppedToday=%v",
@@ -162,16 +367,33 @@ func (s *PollingScheduler) tick(ctx context.Context) {
 			// Check current runtime state.
 			running, err := s.runtime.IsRunning(ctx, containerName)
 			if err != nil {
-				logger.WithComponent("sched").Errorf("IsRunning(%s) error: %v", containerName, err)
+				s.logRuntimeError("IsRunning", containerName, err)
 				continue
 			}
 			if !running {
-				if err := s.runtime.Start(ctx, containerName); err != nil {
-					logger.WithComponent("sched").Errorf("Start(%s) error: %v", containerName, err)
+				if remaining := restartCooldownRemaining(containersByName[containerName], flags, now); remaining > 0 {
+					// Leave the day flag untouched so the start is retried once the cooldown elapsed.
+					logger.WithComponent("sched").Debugf("container %s restart cooldown active for another %v, skipping start", containerName, remaining)
+					continue
+				}
+				if !loadChecked {
+					deferStarts = s.isHostOverloaded()
+					loadChecked = true
+				}
+				if deferStarts {
+					// Leave the day flag untouched so the start is retried on the next tick.
+					logger.WithComponent("sched").Debugf("container %s start deferred due to high host load", containerName)
+					continue
+				}
+				if err := s.start(ctx, containerName); err != nil {
+					s.logRuntimeError("Start", containerName, err)
 					continue
 				}
 				logger.WithComponent("sched").Infof("started %s", containerName)
+				s.events.Publish(events.Event{Type: events.ScheduleFired, Subject: containerName, Data: map[string]string{"action": "start"}})
 			}
+			// Either started now or observed running: record since when it runs.
+			cache.RecordRuntimeState(s.store, containerName, true, now)
 			// Mark that a start attempt was made today (even if it was already running).
 			flags.StartedDayKey = todayKey
 			s.setFlags(containerName, flags)
@@ -179,9 +401,11 @@ func (s *PollingScheduler) tick(ctx context.Context) {
 		}
 
 		// Container should not be running now.
-		// Stop evaluation only happens if a start evaluation occurred today (to avoid premature stops).
-		if flags.StartedDayKey != todayKey {
-			// Stop action is only evaluated after a start evaluation has happened today.
+		// Stop evaluation only happens if a start evaluation occurred today (to avoid premature stops),
+		// or on an earlier day with no stop evaluated since: a window that ended after midnight
+		// (cross-midnight or multi-day one-shot timers).
+		if flags.StartedDayKey != todayKey && (flags.StartedDayKey == "" || flags.StoppedDayKey >= flags.StartedDayKey) {
+			// Stop action is only evaluated after a start evaluation that was not followed by a stop.
 			logger.WithComponent("sched").Tracef("container %s not started today, skipping stop evaluation", containerName)
 			continue
 		}
@@ -191,25 +415,176 @@ func (s *PollingScheduler) tick(ctx context.Context) {
 			continue
 		}
 
+		if until, ok := s.overrides.ContainerUntil(containerName, now); ok {
+			// Leave the day flag untouched so the stop happens once the override expires.
+			logger.WithComponent("sched").Debugf("container %s stop suppressed by a manual override until %s", containerName, until.Format(time.RFC3339))
+			continue
+		}
+		toStop = append(toStop, containerName)
+	}
+
+	// Stop dependents before their dependencies (e.g. an app before its database).
+	for _, containerName := range stopOrder(toStop, containersByName) {
+		select {
+		case <-ctx.Done():
+			logger.WithComponent("sched").Debugf("tick cancelled, exiting stop loop")
+			return
+		default:
+		}
+
 		running, err := s.runtime.IsRunning(ctx, containerName)
 		if err != nil {
-			logger.WithComponent("sched").Errorf("IsRunning(%s) error: %v", containerName, err)
+			s.logRuntimeError("IsRunning", containerName, err)
 			continue
 		}
 		if running {
-			if err := s.runtime.Stop(ctx, containerName); err != nil {
-				logger.WithComponent("sched").Errorf("Stop(%s) error: %v", containerName, err)
+			if err := s.stop(ctx, containerName); err != nil {
+				s.logRuntimeError("Stop", containerName, err)
 				continue
 			}
 			logger.WithComponent("sched").Infof("stopped %s", containerName)
+			s.events.Publish(events.Event{Type: events.ScheduleFired, Subject: containerName, Data: map[string]string{"action": "stop"}})
 		}
+		cache.RecordRuntimeState(s.store, containerName, false, now)
 		// Mark that a stop attempt was made today (even if it was already stopped).
+		flags := s.getFlags(containerName)
 		flags.StoppedDayKey = todayKey
+		if running {
+			flags.StoppedAt = now
+		}
 		s.setFlags(containerName, flags)
 	}
 	logger.WithComponent("sched").Debugf("polling scheduler tick completed")
 }
 
+// shardNames returns the containers evaluated by the current tick and advances to the next shard.
+// all returns every container without advancing.
+func (s *PollingScheduler) shardNames(containersByName map[string]repository.Container, all bool) []string {
+	names := make([]string, 0, len(containersByName))
+	for name := range containersByName {
+		names = append(names, name)
+	}
+	if all || s.shards <= 1 || len(names) == 0 {
+		return names
+	}
+	sort.Strings(names)
+
+	size := (len(names) + s.shards - 1) / s.shards
+	shard := s.nextShard % s.shards
+	s.nextShard = (shard + 1) % s.shards
+
+	from := shard * size
+	if from >= len(names) {
+		// More shards than needed for the current container count: this slice is empty.
+		return nil
+	}
+	to := min(from+size, len(names))
+	logger.WithComponent("sched").Debugf("evaluating shard %d/%d (%d containers)", shard+1, s.shards, to-from)
+	return names[from:to]
+}
+
+// transitionLookahead bounds how far ahead the next planned transition is searched; when none is
+// found the timer stays unarmed and is searched again after the next poll.
+const transitionLookahead = 24 * time.Hour
+
+// errTransitionFound stops the plan walk at the first transition.
+var errTransitionFound = errors.New("transition found")
+
+// nextTransition returns the first start/stop planned strictly after now within transitionLookahead.
+func (s *PollingScheduler) nextTransition(ctx context.Context) (time.Time, bool) {
+	doc, err := s.store.Snapshot()
+	if err != nil {
+		logger.WithComponent("sched").Errorf("snapshot error: %v", err)
+		return time.Time{}, false
+	}
+	now := s.clock().In(s.loc)
+	var next time.Time
+	err = WalkPlan(ctx, doc, now, now.Add(transitionLookahead), s.loc, func(a PlannedAction) error {
+		if !a.Time.After(now) {
+			return nil
+		}
+		next = a.Time
+		return errTransitionFound
+	})
+	if err != nil && !errors.Is(err, errTransitionFound) {
+		logger.WithComponent("sched").Debugf("cannot compute the next transition: %v", err)
+		return time.Time{}, false
+	}
+	return next, !next.IsZero()
+}
+
+// isHostOverloaded reports whether the load guard is enabled and the host load exceeds the threshold.
+// Load read errors never block starts.
+func (s *PollingScheduler) isHostOverloaded() bool {
+	if s.loadProvider == nil || s.maxLoad <= 0 {
+		return false
+	}
+	load, err := s.loadProvider.Load()
+	if err != nil {
+		logger.WithComponent("sched").Warnf("cannot read host load, load guard skipped: %v", err)
+		return false
+	}
+	if load > s.maxLoad {
+		logger.WithComponent("sched").Warnf("host load %.2f exceeds max_load_for_start %.2f, deferring starts to next tick", load, s.maxLoad)
+		return true
+	}
+	return false
+}
+
+// restartCooldownRemaining returns how long the container must still stay down after the scheduler
+// stopped it, 0 when it has no cooldown or the cooldown elapsed.
+func restartCooldownRemaining(container repository.Container, flags DayFlags, now time.Time) time.Duration {
+	if container.RestartCooldownSecs == nil || flags.StoppedAt.IsZero() {
+		return 0
+	}
+	cooldown := time.Duration(*container.RestartCooldownSecs) * time.Second
+	if remaining := flags.StoppedAt.Add(cooldown).Sub(now); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// Prune drops the day flags that no longer affect a decision at now: those of containers removed
+// from the cache, and those recording neither a start nor a stop today once the container's
+// restart cooldown is over. It returns how many entries it dropped.
+func (s *PollingScheduler) Prune(now time.Time) int {
+	doc, err := s.store.Snapshot()
+	if err != nil {
+		logger.WithComponent("sched").Warnf("prune: snapshot error, keeping day flags: %v", err)
+		return 0
+	}
+	containersByName := make(map[string]repository.Container, len(doc.Containers))
+	for _, c := range doc.Containers {
+		containersByName[c.Name] = c
+	}
+
+	todayKey := dayKey(now.In(s.loc))
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	removed := 0
+	for name, flags := range s.flags {
+		container, exists := containersByName[name]
+		if exists {
+			if flags.StartedDayKey == todayKey || flags.StoppedDayKey == todayKey {
+				continue
+			}
+			if restartCooldownRemaining(container, flags, now) > 0 {
+				continue
+			}
+		}
+		delete(s.flags, name)
+		removed++
+	}
+	return removed
+}
+
+// Len returns the number of containers with day flags.
+func (s *PollingScheduler) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.flags)
+}
+
 func (s *PollingScheduler) getFlags(containerName string) DayFlags {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -263,29 +638,30 @@ func expandScheduleTargets(
 }
 
 func isTimerActiveNow(timer repository.Timer, now time.Time) bool {
-	startClock, err := time.Parse("15:04", timer.StartTime)
-	if err != nil {
-		return false
-	}
-	stopClock, err := time.Parse("15:04", timer.StopTime)
-	if err != nil {
-		return false
-	}
+	return isTimerActiveAt(timer, now, 0)
+}
 
+// isTimerActiveAt is like isTimerActiveNow with stopOffset added to the window stop time.
+// A negative offset that closes the window makes the timer inactive.
+func isTimerActiveAt(timer repository.Timer, now time.Time, stopOffset time.Duration) bool {
+	if timer.IsOneShot() {
+		// A one-shot window may span any number of days.
+		start, stop, err := timer.OneShotWindow(now.Location())
+		if err != nil {
+			return false
+		}
+		stop = stop.Add(stopOffset)
+		return (now.Equal(start) || now.After(start)) && now.Before(stop)
+	}
 	// Check windows anchored to today and yesterday (handles cross-midnight).
 	for _, dayOffset := range []int{0, -1} {
 		base := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, dayOffset)
 
-		weekday := int(base.Weekday())
-		if !containsInt(timer.Days, weekday) {
+		start, stop, ok := timerWindow(timer, base)
+		if !ok {
 			continue
 		}
-
-		start := time.Date(base.Year(), base.Month(), base.Day(), startClock.Hour(), startClock.Minute(), 0, 0, now.Location())
-		stop := time.Date(base.Year(), base.Month(), base.Day(), stopClock.Hour(), stopClock.Minute(), 0, 0, now.Location())
-		if !stop.After(start) {
-			stop = stop.Add(24 * time.Hour)
-		}
+		stop = stop.Add(stopOffset)
 
 		if (now.Equal(start) || now.After(start)) && now.Before(stop) {
 			return true
@@ -303,3 +679,29 @@ func containsInt(list []int, v int) bool {
 	}
 	return false
 }
+
+// logRuntimeError logs the failure of a runtime call for containerName. A container missing from
+// the runtime is a configuration issue rather than a runtime failure, so it is only a warning.
+func (s *PollingScheduler) logRuntimeError(call, containerName string, err error) {
+	if runtime.IsNotFound(err) {
+		s.errLog.Warnf(logger.WithComponent("sched"), "%s(%s): container not found in the runtime: %v", call, containerName, err)
+		return
+	}
+	s.errLog.Errorf(logger.WithComponent("sched"), "%s(%s) error: %v", call, containerName, err)
+}
+
+// start starts containerName through the operation queue when there is one.
+func (s *PollingScheduler) start(ctx context.Context, containerName string) error {
+	if s.ops != nil {
+		return s.ops.Do(ctx, ops.ActionStart, containerName)
+	}
+	return s.runtime.Start(ctx, containerName)
+}
+
+// stop stops containerName through the operation queue when there is one.
+func (s *PollingScheduler) stop(ctx context.Context, containerName string) error {
+	if s.ops != nil {
+		return s.ops.Do(ctx, ops.ActionStop, containerName)
+	}
+	return s.runtime.Stop(ctx, containerName)
+}
@@ -1,11 +1,19 @@
 package scheduler
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/bassista/go_spin/internal/cache"
+	"github.com/bassista/go_spin/internal/events"
+	"github.com/bassista/go_spin/internal/logger"
 	"github.com/bassista/go_spin/internal/repository"
 	"github.com/bassista/go_spin/internal/runtime"
 )
@@ -298,6 +306,52 @@ func TestIsTimerActiveNow_CrossMidnight(t *testing.T) {
 	}
 }
 
+func TestIsTimerActiveNow_OneShotSpansDays(t *testing.T) {
+	timer := repository.Timer{StartDate: "2025-12-24 18:00", StopDate: "2025-12-26", Active: boolPtr(true)}
+
+	for _, tt := range []struct {
+		now    time.Time
+		active bool
+	}{
+		{time.Date(2025, 12, 24, 17, 59, 0, 0, time.UTC), false},
+		{time.Date(2025, 12, 24, 18, 0, 0, 0, time.UTC), true},
+		{time.Date(2025, 12, 25, 12, 0, 0, 0, time.UTC), true},
+		{time.Date(2025, 12, 26, 0, 0, 0, 0, time.UTC), false},
+		{time.Date(2026, 12, 25, 12, 0, 0, 0, time.UTC), false},
+	} {
+		if got := isTimerActiveNow(timer, tt.now); got != tt.active {
+			t.Errorf("at %v: expected active=%v, got %v", tt.now, tt.active, got)
+		}
+	}
+}
+
+func TestPollingScheduler_Tick_StopsOneShotEndingOnLaterDay(t *testing.T) {
+	store := &MockStore{
+		doc: repository.DataDocument{
+			Containers: []repository.Container{{Name: "plex", Active: boolPtr(true)}},
+			Schedules: []repository.Schedule{{
+				ID: "xmas", Target: "plex", TargetType: "container",
+				Timers: []repository.Timer{{StartDate: "2025-12-24 18:00", StopDate: "2025-12-26", Active: boolPtr(true)}},
+			}},
+		},
+	}
+	now := time.Date(2025, 12, 25, 23, 59, 0, 0, time.UTC)
+	rt := NewMockRuntime()
+	sched := NewPollingScheduler(store, rt, time.Minute, time.UTC, WithClock(func() time.Time { return now }))
+
+	sched.tick(context.Background())
+	if len(rt.started) != 1 {
+		t.Fatalf("expected plex started inside the window, started: %v", rt.started)
+	}
+
+	// The window closes on the next day: the stop must happen although no start was evaluated today.
+	now = time.Date(2025, 12, 26, 0, 1, 0, 0, time.UTC)
+	sched.tick(context.Background())
+	if len(rt.stopped) != 1 || rt.stopped[0] != "plex" {
+		t.Errorf("expected plex stopped once the window ended, stopped: %v", rt.stopped)
+	}
+}
+
 func TestIsTimerActiveNow_InvalidStartTime(t *testing.T) {
 	now := time.Date(2024, 3, 18, 10, 0, 0, 0, time.UTC)
 
@@ -348,6 +402,35 @@ func TestPollingScheduler_GetSetFlags(t *testing.T) {
 	}
 }
 
+func TestPollingScheduler_Prune_DropsExpiredFlags(t *testing.T) {
+	now := time.Date(2024, 3, 18, 12, 0, 0, 0, time.UTC)
+	cooldown := 2 * 24 * 60 * 60 // two days, in seconds
+	store := &MockStore{doc: repository.DataDocument{Containers: []repository.Container{
+		{Name: "today"},
+		{Name: "old"},
+		{Name: "cooling", RestartCooldownSecs: &cooldown},
+	}}}
+	scheduler := NewPollingScheduler(store, NewMockRuntime(), 30*time.Second, time.UTC)
+
+	scheduler.setFlags("today", DayFlags{StartedDayKey: "2024-03-18"})
+	scheduler.setFlags("old", DayFlags{StoppedDayKey: "2024-03-17", StoppedAt: now.Add(-24 * time.Hour)})
+	scheduler.setFlags("cooling", DayFlags{StoppedDayKey: "2024-03-17", StoppedAt: now.Add(-24 * time.Hour)})
+	scheduler.setFlags("removed", DayFlags{StartedDayKey: "2024-03-18"})
+
+	if removed := scheduler.Prune(now); removed != 2 {
+		t.Errorf("expected 2 flags pruned, got %d", removed)
+	}
+	if scheduler.Len() != 2 {
+		t.Errorf("expected 2 flags left, got %d", scheduler.Len())
+	}
+	if scheduler.getFlags("today").StartedDayKey == "" {
+		t.Error("expected today's flags to be kept")
+	}
+	if scheduler.getFlags("cooling").StoppedAt.IsZero() {
+		t.Error("expected flags within the restart cooldown to be kept")
+	}
+}
+
 func TestPollingScheduler_Start_ContextCancel(t *testing.T) {
 	store := &MockStore{
 		doc: repository.DataDocument{
@@ -426,6 +509,130 @@ func TestPollingScheduler_Tick_StartsContainerWhenTimerActive(t *testing.T) {
 	}
 }
 
+func TestPollingScheduler_Tick_StartsByPriority(t *testing.T) {
+	allDay := []repository.Timer{{StartTime: "00:00", StopTime: "23:59", Days: []int{0, 1, 2, 3, 4, 5, 6}, Active: boolPtr(true)}}
+	store := &MockStore{
+		doc: repository.DataDocument{
+			Containers: []repository.Container{
+				{Name: "a-low", Active: boolPtr(true)},
+				{Name: "b-critical", Active: boolPtr(true), Priority: 10},
+				{Name: "c-mid", Active: boolPtr(true), Priority: 5},
+				{Name: "d-low", Active: boolPtr(true)},
+			},
+			Groups: []repository.Group{{Name: "all", Container: []string{"a-low", "b-critical", "c-mid", "d-low"}, Active: boolPtr(true)}},
+			Schedules: []repository.Schedule{
+				{ID: "sched1", Target: "all", TargetType: "group", Timers: allDay},
+			},
+		},
+	}
+
+	rt := NewMockRuntime()
+	scheduler := NewPollingScheduler(store, rt, 30*time.Second, time.UTC)
+	scheduler.tick(context.Background())
+
+	expected := []string{"b-critical", "c-mid", "a-low", "d-low"}
+	if fmt.Sprint(rt.started) != fmt.Sprint(expected) {
+		t.Errorf("expected starts in priority order %v, got %v", expected, rt.started)
+	}
+}
+
+func TestPollingScheduler_Tick_PublishesScheduleFired(t *testing.T) {
+	store := &MockStore{
+		doc: repository.DataDocument{
+			Containers: []repository.Container{{Name: "c1", Active: boolPtr(true)}},
+			Schedules: []repository.Schedule{{
+				ID: "sched1", Target: "c1", TargetType: "container",
+				Timers: []repository.Timer{{StartTime: "00:00", StopTime: "23:59", Days: []int{0, 1, 2, 3, 4, 5, 6}, Active: boolPtr(true)}},
+			}},
+		},
+	}
+	bus := events.NewBus()
+	ch, unsubscribe := bus.Subscribe(events.ScheduleFired)
+	defer unsubscribe()
+
+	rt := NewMockRuntime()
+	scheduler := NewPollingScheduler(store, rt, 30*time.Second, time.UTC, WithEvents(bus))
+	scheduler.tick(context.Background())
+
+	select {
+	case ev := <-ch:
+		if ev.Subject != "c1" || ev.Data["action"] != "start" {
+			t.Errorf("expected a start of c1, got %+v", ev)
+		}
+	default:
+		t.Fatal("expected a schedule fired event for the start")
+	}
+
+	// Already started today: no action, no event.
+	scheduler.tick(context.Background())
+	select {
+	case ev := <-ch:
+		t.Errorf("expected no event without an action, got %+v", ev)
+	default:
+	}
+}
+
+func TestPollingScheduler_Tick_SafeModeSkipsActions(t *testing.T) {
+	store := &MockStore{
+		doc: repository.DataDocument{
+			Containers: []repository.Container{{Name: "c1", Active: boolPtr(true)}},
+			Schedules: []repository.Schedule{{
+				ID: "sched1", Target: "c1", TargetType: "container",
+				Timers: []repository.Timer{{StartTime: "00:00", StopTime: "23:59", Days: []int{0, 1, 2, 3, 4, 5, 6}, Active: boolPtr(true)}},
+			}},
+		},
+	}
+	rt := NewMockRuntime()
+	safeMode := runtime.NewSafeModeRuntime(rt, true)
+	scheduler := NewPollingScheduler(store, safeMode, 30*time.Second, time.UTC)
+
+	scheduler.tick(context.Background())
+	if len(rt.started) != 0 {
+		t.Fatalf("expected no start in safe mode, got %v", rt.started)
+	}
+
+	// The skipped start is not recorded, so it happens once safe mode is turned off
+	safeMode.SetEnabled(false)
+	scheduler.tick(context.Background())
+	if len(rt.started) != 1 || rt.started[0] != "c1" {
+		t.Errorf("expected c1 started after safe mode was disabled, got %v", rt.started)
+	}
+}
+
+func TestPollingScheduler_Tick_ShardsSpreadEvaluation(t *testing.T) {
+	const containers = 10
+	const shards = 3
+	shardSize := (containers + shards - 1) / shards
+
+	doc := repository.DataDocument{}
+	for i := 0; i < containers; i++ {
+		name := fmt.Sprintf("c%02d", i)
+		doc.Containers = append(doc.Containers, repository.Container{Name: name, Active: boolPtr(true)})
+		doc.Schedules = append(doc.Schedules, repository.Schedule{
+			ID: "s-" + name, Target: name, TargetType: "container",
+			Timers: []repository.Timer{{StartTime: "00:00", StopTime: "23:59", Days: []int{0, 1, 2, 3, 4, 5, 6}, Active: boolPtr(true)}},
+		})
+	}
+	rt := NewMockRuntime()
+	scheduler := NewPollingScheduler(&MockStore{doc: doc}, rt, 30*time.Second, time.UTC, WithShards(shards))
+
+	for i := 0; i < shards; i++ {
+		before := len(rt.started)
+		scheduler.tick(context.Background())
+		if got := len(rt.started) - before; got > shardSize {
+			t.Errorf("tick %d evaluated %d containers, expected at most %d", i, got, shardSize)
+		}
+	}
+
+	started := map[string]bool{}
+	for _, name := range rt.started {
+		started[name] = true
+	}
+	if len(started) != containers {
+		t.Errorf("expected every container started after %d ticks, got %v", shards, rt.started)
+	}
+}
+
 func TestPollingScheduler_Tick_StopsContainerWhenOutsideTimerWindow(t *testing.T) {
 	loc := time.UTC
 
@@ -454,23 +661,18 @@ func TestPollingScheduler_Tick_StopsContainerWhenOutsideTimerWindow(t *testing.T
 
 	rt := NewMockRuntime()
 	rt.running["c1"] = true // Container is currently running
-	scheduler := NewPollingScheduler(store, rt, 30*time.Second, loc)
+	// Drive the clock outside the 01:00-02:00 window.
+	now := time.Date(2024, 3, 18, 10, 0, 0, 0, loc)
+	scheduler := NewPollingScheduler(store, rt, 30*time.Second, loc, WithClock(func() time.Time { return now }))
 
-	now := time.Now().In(loc)
-	// Only run if we're outside 01:00-02:00
-	if now.Hour() >= 2 || now.Hour() < 1 {
-		// First, simulate that start was already evaluated today
-		todayKey := dayKey(now)
-		scheduler.setFlags("c1", DayFlags{StartedDayKey: todayKey})
+	// First, simulate that start was already evaluated today
+	scheduler.setFlags("c1", DayFlags{StartedDayKey: dayKey(now)})
 
-		scheduler.tick(context.Background())
+	scheduler.tick(context.Background())
 
-		// Container should have been stopped
-		if len(rt.stopped) != 1 || rt.stopped[0] != "c1" {
-			t.Errorf("expected c1 to be stopped, got stopped: %v", rt.stopped)
-		}
-	} else {
-		t.Skip("Skipping test - cannot run during 01:00-02:00 window")
+	// Container should have been stopped
+	if len(rt.stopped) != 1 || rt.stopped[0] != "c1" {
+		t.Errorf("expected c1 to be stopped, got stopped: %v", rt.stopped)
 	}
 }
 
@@ -789,3 +991,723 @@ func TestPollingScheduler_ConcurrentStartMultipleTimes(t *testing.T) {
 	cancel()
 	time.Sleep(50 * time.Millisecond)
 }
+
+// fixedLoadProvider implements LoadProvider returning a constant load.
+type fixedLoadProvider struct {
+	load float64
+	err  error
+}
+
+func (f *fixedLoadProvider) Load() (float64, error) {
+	return f.load, f.err
+}
+
+func newLoadGuardTestStore() *MockStore {
+	return &MockStore{
+		doc: repository.DataDocument{
+			Containers: []repository.Container{
+				{Name: "c1", Active: boolPtr(true)},
+				{Name: "c2", Active: boolPtr(true)},
+			},
+			Schedules: []repository.Schedule{
+				{
+					ID:         "sched1",
+					Target:     "c1",
+					TargetType: "container",
+					Timers: []repository.Timer{
+						{
+							StartTime: "00:00",
+							StopTime:  "23:59",
+							Days:      []int{0, 1, 2, 3, 4, 5, 6},
+							Active:    boolPtr(true),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestPollingScheduler_Tick_HighLoadDefersStartsButStops(t *testing.T) {
+	loc := time.UTC
+	store := newLoadGuardTestStore()
+	rt := NewMockRuntime()
+	rt.running["c2"] = true
+
+	provider := &fixedLoadProvider{load: 12.5}
+	scheduler := NewPollingScheduler(store, rt, 30*time.Second, loc, WithLoadGuard(provider, 4))
+	todayKey := dayKey(time.Now().In(loc))
+	scheduler.setFlags("c2", DayFlags{StartedDayKey: todayKey})
+
+	scheduler.tick(context.Background())
+
+	if len(rt.started) != 0 {
+		t.Errorf("expected starts to be deferred under high load, got started: %v", rt.started)
+	}
+	if len(rt.stopped) != 1 || rt.stopped[0] != "c2" {
+		t.Errorf("expected c2 to be stopped under high load, got stopped: %v", rt.stopped)
+	}
+	if flags := scheduler.getFlags("c1"); flags.StartedDayKey == todayKey {
+		t.Error("expected deferred start to leave the start flag unset")
+	}
+
+	// Once load drops, the deferred start is issued on the next tick.
+	provider.load = 0.5
+	scheduler.tick(context.Background())
+
+	if len(rt.started) != 1 || rt.started[0] != "c1" {
+		t.Errorf("expected c1 to be started after load dropped, got started: %v", rt.started)
+	}
+}
+
+func TestPollingScheduler_Tick_LoadGuardErrorDoesNotBlockStarts(t *testing.T) {
+	store := newLoadGuardTestStore()
+	rt := NewMockRuntime()
+
+	provider := &fixedLoadProvider{err: context.DeadlineExceeded}
+	scheduler := NewPollingScheduler(store, rt, 30*time.Second, time.UTC, WithLoadGuard(provider, 4))
+
+	scheduler.tick(context.Background())
+
+	if len(rt.started) != 1 || rt.started[0] != "c1" {
+		t.Errorf("expected c1 to be started when load cannot be read, got started: %v", rt.started)
+	}
+}
+
+func TestProcLoadProvider_Load(t *testing.T) {
+	path := t.TempDir() + "/loadavg"
+	if err := os.WriteFile(path, []byte("3.25 2.10 1.05 2/345 6789\n"), 0644); err != nil {
+		t.Fatalf("failed to write loadavg file: %v", err)
+	}
+
+	load, err := (&ProcLoadProvider{Path: path}).Load()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if load != 3.25 {
+		t.Errorf("expected load 3.25, got %v", load)
+	}
+
+	if _, err := (&ProcLoadProvider{Path: path + ".missing"}).Load(); err == nil {
+		t.Error("expected error for missing loadavg file")
+	}
+}
+
+func TestPollingScheduler_ChangeTrigger_ActivationEvaluatesImmediately(t *testing.T) {
+	allDays := []int{0, 1, 2, 3, 4, 5, 6}
+	store := cache.NewStore(repository.DataDocument{
+		Containers: []repository.Container{
+			{Name: "c1", FriendlyName: "C1", URL: "http://c1", Active: boolPtr(false)},
+		},
+		Schedules: []repository.Schedule{
+			{ID: "s1", Target: "c1", TargetType: "container", Timers: []repository.Timer{
+				{StartTime: "00:00", StopTime: "23:59", Days: allDays, Active: boolPtr(true)},
+			}},
+		},
+	})
+	rt := NewMockRuntime()
+
+	// The poll interval is far longer than the test, so only the change trigger can start c1.
+	sched := NewPollingScheduler(store, rt, time.Hour, time.UTC, WithChangeTrigger(20*time.Millisecond, 0))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sched.Start(ctx)
+
+	if _, err := store.AddContainer(repository.Container{Name: "c1", FriendlyName: "C1", URL: "http://c1", Active: boolPtr(true)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		rt.mu.Lock()
+		started := len(rt.started)
+		rt.mu.Unlock()
+		if started > 0 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("expected container activation to trigger a near-immediate start")
+}
+
+func TestPollingScheduler_ChangeTrigger_Debounced(t *testing.T) {
+	store := cache.NewStore(repository.DataDocument{})
+	rt := NewMockRuntime()
+	ticks := &countingStore{Store: store}
+
+	sched := NewPollingScheduler(ticks, rt, time.Hour, time.UTC, WithChangeTrigger(50*time.Millisecond, 0))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sched.Start(ctx)
+
+	// A burst of changes collapses into a single evaluation
+	for i := 0; i < 5; i++ {
+		if _, err := store.AddContainer(repository.Container{Name: "c1", FriendlyName: "C1", URL: "http://c1", Active: boolPtr(false)}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	time.Sleep(200 * time.Millisecond)
+
+	if got := ticks.count(); got != 1 {
+		t.Errorf("expected 1 debounced evaluation, got %d", got)
+	}
+}
+
+// countingStore counts snapshots taken by the scheduler while forwarding change subscriptions.
+type countingStore struct {
+	*cache.Store
+	mu        sync.Mutex
+	snapshots int
+}
+
+func (c *countingStore) Snapshot() (repository.DataDocument, error) {
+	c.mu.Lock()
+	c.snapshots++
+	c.mu.Unlock()
+	return c.Store.Snapshot()
+}
+
+func (c *countingStore) count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.snapshots
+}
+
+func TestPollingScheduler_Tick_StopsDependentsFirst(t *testing.T) {
+	store := &MockStore{doc: repository.DataDocument{
+		Containers: []repository.Container{
+			{Name: "db", Active: boolPtr(true)},
+			{Name: "web", Active: boolPtr(true), DependsOn: []string{"db"}},
+		},
+	}}
+	rt := NewMockRuntime()
+	rt.running["db"] = true
+	rt.running["web"] = true
+
+	sched := NewPollingScheduler(store, rt, time.Minute, time.UTC)
+	// Both were started today by the scheduler and no timer is active anymore.
+	today := dayKey(time.Now().In(time.UTC))
+	sched.setFlags("db", DayFlags{StartedDayKey: today})
+	sched.setFlags("web", DayFlags{StartedDayKey: today})
+
+	sched.tick(context.Background())
+
+	if len(rt.stopped) != 2 || rt.stopped[0] != "web" || rt.stopped[1] != "db" {
+		t.Errorf("expected web to be stopped before db, got %v", rt.stopped)
+	}
+}
+
+func TestStopOrder(t *testing.T) {
+	containers := map[string]repository.Container{
+		"app":   {Name: "app", DependsOn: []string{"cache", "db"}},
+		"cache": {Name: "cache", DependsOn: []string{"db"}},
+		"db":    {Name: "db"},
+		"solo":  {Name: "solo", DependsOn: []string{"not-stopping"}},
+	}
+
+	got := stopOrder([]string{"db", "solo", "cache", "app"}, containers)
+	want := []string{"app", "cache", "db", "solo"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+}
+
+func TestStopOrder_Cycle(t *testing.T) {
+	containers := map[string]repository.Container{
+		"a": {Name: "a", DependsOn: []string{"b"}},
+		"b": {Name: "b", DependsOn: []string{"a"}},
+		"c": {Name: "c"},
+	}
+
+	got := stopOrder([]string{"b", "a", "c"}, containers)
+	if len(got) != 3 || got[0] != "c" || got[1] != "a" || got[2] != "b" {
+		t.Errorf("expected [c a b] with cyclic containers last, got %v", got)
+	}
+}
+
+func TestStartStages(t *testing.T) {
+	containers := map[string]repository.Container{
+		"app":   {Name: "app", DependsOn: []string{"cache", "db"}},
+		"cache": {Name: "cache", DependsOn: []string{"db"}},
+		"db":    {Name: "db"},
+		"solo":  {Name: "solo", Priority: 5, DependsOn: []string{"not-starting"}},
+		"a":     {Name: "a", DependsOn: []string{"b"}},
+		"b":     {Name: "b", DependsOn: []string{"a"}},
+	}
+
+	got := StartStages([]string{"app", "cache", "db", "solo", "a", "b"}, containers)
+	want := [][]string{{"solo", "db"}, {"cache"}, {"app"}, {"a", "b"}}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if strings.Join(got[i], ",") != strings.Join(want[i], ",") {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+}
+
+func (m *MockRuntime) Ping(_ context.Context) (runtime.PingResult, error) {
+	return runtime.PingResult{}, nil
+}
+
+// noonLocation returns a fixed zone in which now is about 12:00, so window tests do not depend on the wall clock.
+func noonLocation(now time.Time) *time.Location {
+	utc := now.UTC()
+	secondsOfDay := utc.Hour()*3600 + utc.Minute()*60 + utc.Second()
+	return time.FixedZone("noon", 12*3600-secondsOfDay)
+}
+
+func newExtensionTestStore() *MockStore {
+	return &MockStore{
+		doc: repository.DataDocument{
+			Containers: []repository.Container{{Name: "c1", Active: boolPtr(true)}},
+			Schedules: []repository.Schedule{{
+				ID:         "sched1",
+				Target:     "c1",
+				TargetType: "container",
+				Timers: []repository.Timer{{
+					StartTime: "09:00",
+					StopTime:  "11:00", // nominal stop already passed at ~12:00
+					Days:      []int{0, 1, 2, 3, 4, 5, 6},
+					Active:    boolPtr(true),
+				}},
+			}},
+		},
+	}
+}
+
+func TestPollingScheduler_Tick_ExtensionKeepsContainerUpPastStop(t *testing.T) {
+	loc := noonLocation(time.Now())
+	ext := NewExtensions()
+	ext.Set("sched1", 120, time.Now().In(loc))
+
+	rt := NewMockRuntime()
+	rt.running["c1"] = true
+	sched := NewPollingScheduler(newExtensionTestStore(), rt, time.Minute, loc, WithExtensions(ext))
+	sched.setFlags("c1", DayFlags{StartedDayKey: dayKey(time.Now().In(loc))})
+
+	sched.tick(context.Background())
+	if len(rt.stopped) != 0 {
+		t.Fatalf("expected c1 to stay up while extended, stopped: %v", rt.stopped)
+	}
+
+	// Clearing the extension restores the nominal stop time.
+	ext.Clear("sched1")
+	sched.tick(context.Background())
+	if len(rt.stopped) != 1 || rt.stopped[0] != "c1" {
+		t.Errorf("expected c1 to be stopped after clearing the extension, stopped: %v", rt.stopped)
+	}
+}
+
+func TestPollingScheduler_Tick_NegativeExtensionStopsEarly(t *testing.T) {
+	loc := noonLocation(time.Now())
+	store := newExtensionTestStore()
+	store.doc.Schedules[0].Timers[0].StopTime = "13:00"
+	ext := NewExtensions()
+	ext.Set("sched1", -90, time.Now().In(loc))
+
+	rt := NewMockRuntime()
+	rt.running["c1"] = true
+	sched := NewPollingScheduler(store, rt, time.Minute, loc, WithExtensions(ext))
+	sched.setFlags("c1", DayFlags{StartedDayKey: dayKey(time.Now().In(loc))})
+
+	sched.tick(context.Background())
+	if len(rt.stopped) != 1 {
+		t.Errorf("expected c1 to be stopped by the shortened window, stopped: %v", rt.stopped)
+	}
+}
+
+func TestExtensions_ExpireAtDayRollover(t *testing.T) {
+	ext := NewExtensions()
+	today := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	ext.Set("s1", 60, today)
+
+	if got := ext.Offset("s1", today.Add(time.Hour)); got != time.Hour {
+		t.Errorf("expected 1h offset on the same day, got %v", got)
+	}
+	if got := ext.Offset("s1", today.AddDate(0, 0, 1)); got != 0 {
+		t.Errorf("expected no offset the next day, got %v", got)
+	}
+	if _, ok := ext.Get("s1", today); ok {
+		t.Error("expected expired extension to be dropped")
+	}
+
+	var none *Extensions
+	if got := none.Offset("s1", today); got != 0 {
+		t.Errorf("expected nil extensions to report no offset, got %v", got)
+	}
+}
+
+func TestPollingScheduler_WithClock_DrivesWindowBoundaries(t *testing.T) {
+	loc := time.UTC
+	store := &MockStore{
+		doc: repository.DataDocument{
+			Containers: []repository.Container{
+				{Name: "c1", Active: boolPtr(true)},
+			},
+			Schedules: []repository.Schedule{
+				{
+					ID:         "sched1",
+					Target:     "c1",
+					TargetType: "container",
+					Timers: []repository.Timer{
+						{StartTime: "08:00", StopTime: "09:00", Days: []int{0, 1, 2, 3, 4, 5, 6}, Active: boolPtr(true)},
+					},
+				},
+			},
+		},
+	}
+
+	rt := NewMockRuntime()
+	now := time.Date(2024, 3, 18, 7, 59, 0, 0, loc)
+	sched := NewPollingScheduler(store, rt, 30*time.Second, loc, WithClock(func() time.Time { return now }))
+
+	// Just before the window: nothing happens.
+	sched.tick(context.Background())
+	if len(rt.started) != 0 || len(rt.stopped) != 0 {
+		t.Fatalf("expected no action before the window, got started=%v stopped=%v", rt.started, rt.stopped)
+	}
+
+	// Window opens: the container is started.
+	now = time.Date(2024, 3, 18, 8, 0, 0, 0, loc)
+	sched.tick(context.Background())
+	if len(rt.started) != 1 || rt.started[0] != "c1" {
+		t.Fatalf("expected c1 to be started at 08:00, got %v", rt.started)
+	}
+	if len(rt.stopped) != 0 {
+		t.Fatalf("expected no stop inside the window, got %v", rt.stopped)
+	}
+
+	// Window closes (stop time is exclusive): the container is stopped.
+	now = time.Date(2024, 3, 18, 9, 0, 0, 0, loc)
+	sched.tick(context.Background())
+	if len(rt.stopped) != 1 || rt.stopped[0] != "c1" {
+		t.Fatalf("expected c1 to be stopped at 09:00, got %v", rt.stopped)
+	}
+	if len(rt.started) != 1 {
+		t.Errorf("expected no further start, got %v", rt.started)
+	}
+}
+
+func TestPollingScheduler_WithClock_RecordsRunningSince(t *testing.T) {
+	store := cache.NewStore(repository.DataDocument{
+		Containers: []repository.Container{
+			{Name: "c1", FriendlyName: "C1", URL: "http://c1", Active: boolPtr(true)},
+		},
+		Schedules: []repository.Schedule{
+			{ID: "s1", Target: "c1", TargetType: "container", Timers: []repository.Timer{
+				{StartTime: "08:00", StopTime: "09:00", Days: []int{0, 1, 2, 3, 4, 5, 6}, Active: boolPtr(true)},
+			}},
+		},
+	})
+	now := time.Date(2024, 3, 18, 8, 0, 0, 0, time.UTC)
+	sched := NewPollingScheduler(store, NewMockRuntime(), 30*time.Second, time.UTC, WithClock(func() time.Time { return now }))
+
+	sched.tick(context.Background())
+
+	doc, err := store.Snapshot()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rs := doc.Containers[0].RunningSince; rs == nil || *rs != now.UnixMilli() {
+		t.Errorf("expected RunningSince %d from the scheduler clock, got %v", now.UnixMilli(), rs)
+	}
+}
+
+func TestPollingScheduler_Tick_RestartCooldown(t *testing.T) {
+	loc := time.UTC
+	cooldownSecs := 1200
+	store := &MockStore{
+		doc: repository.DataDocument{
+			Containers: []repository.Container{
+				{Name: "c1", Active: boolPtr(true), RestartCooldownSecs: &cooldownSecs},
+			},
+			Schedules: []repository.Schedule{
+				{
+					ID:         "sched1",
+					Target:     "c1",
+					TargetType: "container",
+					Timers: []repository.Timer{
+						{StartTime: "23:00", StopTime: "23:50", Days: []int{0, 1, 2, 3, 4, 5, 6}, Active: boolPtr(true)},
+						{StartTime: "00:00", StopTime: "01:00", Days: []int{0, 1, 2, 3, 4, 5, 6}, Active: boolPtr(true)},
+					},
+				},
+			},
+		},
+	}
+
+	rt := NewMockRuntime()
+	now := time.Date(2024, 3, 18, 23, 0, 0, 0, loc)
+	sched := NewPollingScheduler(store, rt, 30*time.Second, loc, WithClock(func() time.Time { return now }))
+
+	sched.tick(context.Background())
+	stoppedAt := time.Date(2024, 3, 18, 23, 50, 0, 0, loc)
+	now = stoppedAt
+	sched.tick(context.Background())
+	if len(rt.started) != 1 || len(rt.stopped) != 1 {
+		t.Fatalf("expected one start and one stop, got started=%v stopped=%v", rt.started, rt.stopped)
+	}
+
+	// Next day's window opens halfway through the cooldown: no restart yet.
+	cooldown := time.Duration(cooldownSecs) * time.Second
+	now = stoppedAt.Add(cooldown / 2)
+	sched.tick(context.Background())
+	if len(rt.started) != 1 {
+		t.Fatalf("expected no restart during the cooldown, got started=%v", rt.started)
+	}
+
+	// Cooldown elapsed: the container is restarted.
+	now = stoppedAt.Add(cooldown)
+	sched.tick(context.Background())
+	if len(rt.started) != 2 {
+		t.Fatalf("expected restart once the cooldown elapsed, got started=%v", rt.started)
+	}
+}
+
+func TestPollingScheduler_Tick_DeduplicatesRepeatedErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		window time.Duration
+		lines  int
+	}{
+		{"window", 5 * time.Minute, 1},
+		{"disabled", 0, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testErrorLogDedup(t, tt.window, tt.lines)
+		})
+	}
+}
+
+// testErrorLogDedup fails a scheduled start on 5 ticks and expects lines error lines.
+func testErrorLogDedup(t *testing.T, window time.Duration, lines int) {
+	var buf bytes.Buffer
+	logger.Logger.SetOutput(&buf)
+	defer logger.Logger.SetOutput(os.Stdout)
+
+	store := &MockStore{
+		doc: repository.DataDocument{
+			Containers: []repository.Container{{Name: "c1", Active: boolPtr(true)}},
+			Schedules: []repository.Schedule{
+				{
+					ID:         "sched1",
+					Target:     "c1",
+					TargetType: "container",
+					Timers: []repository.Timer{
+						{StartTime: "08:00", StopTime: "09:00", Days: []int{0, 1, 2, 3, 4, 5, 6}, Active: boolPtr(true)},
+					},
+				},
+			},
+		},
+	}
+	rt := NewMockRuntime()
+	rt.startErr = errors.New("docker down")
+	now := time.Date(2024, 3, 18, 8, 0, 0, 0, time.UTC)
+	sched := NewPollingScheduler(store, rt, 30*time.Second, time.UTC, WithClock(func() time.Time { return now }), WithErrorLogDedup(window))
+
+	// The failed start is retried on every tick.
+	for i := 0; i < 5; i++ {
+		sched.tick(context.Background())
+	}
+
+	if got := strings.Count(buf.String(), "Start(c1) error: docker down"); got != lines {
+		t.Errorf("expected %d error lines for repeated failures, got %d:\n%s", lines, got, buf.String())
+	}
+}
+
+func TestPollingScheduler_Tick_DefaultSchedule(t *testing.T) {
+	store := &MockStore{
+		doc: repository.DataDocument{
+			Containers: []repository.Container{
+				{Name: "plain", Active: boolPtr(true)},
+				{Name: "own", Active: boolPtr(true)},
+				{Name: "off", Active: boolPtr(false)},
+			},
+			Schedules: []repository.Schedule{
+				{
+					ID:         "own-sched",
+					Target:     "own",
+					TargetType: "container",
+					Timers: []repository.Timer{
+						{StartTime: "10:00", StopTime: "11:00", Days: []int{0, 1, 2, 3, 4, 5, 6}, Active: boolPtr(true)},
+					},
+				},
+			},
+			DefaultSchedule: &repository.DefaultSchedule{
+				Timers: []repository.Timer{
+					{StartTime: "08:00", StopTime: "09:00", Days: []int{0, 1, 2, 3, 4, 5, 6}, Active: boolPtr(true)},
+				},
+			},
+		},
+	}
+
+	rt := NewMockRuntime()
+	now := time.Date(2024, 3, 18, 8, 30, 0, 0, time.UTC)
+	sched := NewPollingScheduler(store, rt, 30*time.Second, time.UTC, WithClock(func() time.Time { return now }))
+	sched.tick(context.Background())
+
+	if len(rt.started) != 1 || rt.started[0] != "plain" {
+		t.Fatalf("expected only the unscheduled container to follow the default, got %v", rt.started)
+	}
+
+	// The container with its own schedule follows it, not the default.
+	now = time.Date(2024, 3, 18, 10, 30, 0, 0, time.UTC)
+	sched.tick(context.Background())
+	if len(rt.started) != 2 || rt.started[1] != "own" {
+		t.Fatalf("expected own to start in its own window, got %v", rt.started)
+	}
+	if len(rt.stopped) != 1 || rt.stopped[0] != "plain" {
+		t.Errorf("expected plain to stop after the default window, got %v", rt.stopped)
+	}
+}
+
+func TestPollingScheduler_ExactTransitionStartsOnTime(t *testing.T) {
+	store := &MockStore{
+		doc: repository.DataDocument{
+			Containers: []repository.Container{{Name: "c1", Active: boolPtr(true)}, {Name: "c2", Active: boolPtr(true)}},
+			Schedules: []repository.Schedule{{
+				ID: "sched1", Target: "c2", TargetType: "container",
+				Timers: []repository.Timer{{StartTime: "08:00", StopTime: "18:00", Days: []int{0, 1, 2, 3, 4, 5, 6}, Active: boolPtr(true)}},
+			}},
+		},
+	}
+	// The simulated clock reaches 08:00 100ms after the scheduler starts; the poll never fires.
+	base := time.Date(2024, 3, 18, 7, 59, 59, 900_000_000, time.UTC)
+	realStart := time.Now()
+	clock := func() time.Time { return base.Add(time.Since(realStart)) }
+
+	rt := NewMockRuntime()
+	// Two shards: the transition evaluates every container, not only the next slice.
+	sched := NewPollingScheduler(store, rt, time.Hour, time.UTC, WithClock(clock), WithShards(2), WithExactTransitions(true))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sched.Start(ctx)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		rt.mu.Lock()
+		started := append([]string(nil), rt.started...)
+		rt.mu.Unlock()
+		if len(started) > 0 {
+			if len(started) != 1 || started[0] != "c2" {
+				t.Fatalf("expected only c2 started, got %v", started)
+			}
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("expected c2 started at the planned transition")
+}
+
+func TestPollingScheduler_NextTransition(t *testing.T) {
+	store := &MockStore{
+		doc: repository.DataDocument{
+			Containers: []repository.Container{{Name: "c1", Active: boolPtr(true)}},
+			Schedules: []repository.Schedule{{
+				ID: "sched1", Target: "c1", TargetType: "container",
+				Timers: []repository.Timer{{StartTime: "08:00", StopTime: "18:00", Days: []int{0, 1, 2, 3, 4, 5, 6}, Active: boolPtr(true)}},
+			}},
+		},
+	}
+	now := time.Date(2024, 3, 18, 8, 0, 0, 0, time.UTC)
+	sched := NewPollingScheduler(store, NewMockRuntime(), time.Minute, time.UTC, WithClock(func() time.Time { return now }))
+
+	next, ok := sched.nextTransition(context.Background())
+	if !ok || !next.Equal(time.Date(2024, 3, 18, 18, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the 18:00 stop (the 08:00 start is not strictly after now), got %v %v", next, ok)
+	}
+
+	store.doc.Schedules = nil
+	if _, ok := sched.nextTransition(context.Background()); ok {
+		t.Error("expected no transition without schedules")
+	}
+}
+
+func TestPollingScheduler_Tick_PausedScheduleLeavesContainersAlone(t *testing.T) {
+	allDay := []repository.Timer{{StartTime: "00:00", StopTime: "23:59", Days: []int{0, 1, 2, 3, 4, 5, 6}, Active: boolPtr(true)}}
+	store := &MockStore{
+		doc: repository.DataDocument{
+			Containers: []repository.Container{{Name: "c1", Active: boolPtr(true)}, {Name: "c2", Active: boolPtr(true)}},
+			Schedules: []repository.Schedule{
+				{ID: "paused", Target: "c1", TargetType: "container", Timers: allDay},
+				{ID: "running", Target: "c2", TargetType: "container", Timers: allDay},
+			},
+		},
+	}
+	overrides := NewOverrides()
+	overrides.Pause("paused", time.Now())
+
+	rt := NewMockRuntime()
+	sched := NewPollingScheduler(store, rt, 30*time.Second, time.UTC, WithOverrides(overrides))
+	sched.tick(context.Background())
+	if len(rt.started) != 1 || rt.started[0] != "c2" {
+		t.Fatalf("expected only c2 started while c1's schedule is paused, started: %v", rt.started)
+	}
+
+	overrides.Resume("paused")
+	sched.tick(context.Background())
+	if len(rt.started) != 2 || rt.started[1] != "c1" {
+		t.Errorf("expected c1 started once resumed, started: %v", rt.started)
+	}
+}
+
+func TestPollingScheduler_Tick_ContainerOverrideSuppressesStop(t *testing.T) {
+	store := &MockStore{
+		doc: repository.DataDocument{
+			Containers: []repository.Container{{Name: "c1", Active: boolPtr(true)}},
+			Schedules: []repository.Schedule{{
+				ID: "sched1", Target: "c1", TargetType: "container",
+				Timers: []repository.Timer{{StartTime: "08:00", StopTime: "09:00", Days: []int{0, 1, 2, 3, 4, 5, 6}, Active: boolPtr(true)}},
+			}},
+		},
+	}
+	now := time.Date(2024, 3, 18, 10, 0, 0, 0, time.UTC)
+	overrides := NewOverrides()
+	overrides.SetContainer("c1", now.Add(4*time.Hour))
+
+	rt := NewMockRuntime()
+	rt.running["c1"] = true
+	sched := NewPollingScheduler(store, rt, 30*time.Second, time.UTC, WithOverrides(overrides), WithClock(func() time.Time { return now }))
+	sched.setFlags("c1", DayFlags{StartedDayKey: dayKey(now)})
+
+	sched.tick(context.Background())
+	if len(rt.stopped) != 0 {
+		t.Fatalf("expected the stop suppressed by the override, stopped: %v", rt.stopped)
+	}
+
+	now = now.Add(5 * time.Hour)
+	sched.tick(context.Background())
+	if len(rt.stopped) != 1 {
+		t.Errorf("expected c1 stopped once the override expired, stopped: %v", rt.stopped)
+	}
+}
+
+func TestOverrides_PruneDropsExpiredContainerOverrides(t *testing.T) {
+	now := time.Now()
+	o := NewOverrides()
+	o.Pause("s1", now)
+	o.SetContainer("old", now.Add(-time.Minute))
+	o.SetContainer("new", now.Add(time.Hour))
+
+	if removed := o.Prune(now); removed != 1 {
+		t.Errorf("expected one expired override pruned, got %d", removed)
+	}
+	if o.Len() != 2 {
+		t.Errorf("expected the pause and the live override kept, got %d entries", o.Len())
+	}
+	if _, ok := o.ContainerUntil("new", now); !ok {
+		t.Error("expected the live override in force")
+	}
+
+	var none *Overrides
+	if none.IsPaused("s1") || none.Len() != 0 {
+		t.Error("expected a nil set to be empty")
+	}
+}
@@ -0,0 +1,58 @@
+package waiting
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// cachedFile is a template file as read at modTime.
+type cachedFile struct {
+	content string
+	modTime time.Time
+	size    int64
+}
+
+// FileCache reads the per-container waiting templates of a directory and keeps them in memory,
+// reading a file again only once it changes on disk.
+type FileCache struct {
+	dir   string
+	mu    sync.Mutex
+	files map[string]cachedFile
+}
+
+// NewFileCache creates a FileCache for the templates in dir.
+func NewFileCache(dir string) *FileCache {
+	return &FileCache{dir: dir, files: map[string]cachedFile{}}
+}
+
+// Load returns the validated template name, a path relative to the cache directory; names leaving
+// the directory are rejected.
+func (f *FileCache) Load(name string) (string, error) {
+	if !filepath.IsLocal(name) {
+		return "", fmt.Errorf("%w: template path %q is outside %s", ErrInvalidTemplate, name, f.dir)
+	}
+	path := filepath.Join(f.dir, name)
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if cached, ok := f.files[name]; ok && cached.modTime.Equal(info.ModTime()) && cached.size == info.Size() {
+		return cached.content, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	content := string(data)
+	if err := Validate(content); err != nil {
+		return "", fmt.Errorf("%s: %w", path, err)
+	}
+	f.files[name] = cachedFile{content: content, modTime: info.ModTime(), size: info.Size()}
+	return content, nil
+}
@@ -0,0 +1,146 @@
+package waiting
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/bassista/go_spin/internal/logger"
+)
+
+// DefaultTemplatePath is the default path for the waiting page template.
+const DefaultTemplatePath = "./ui/templates/waiting.html"
+
+// Placeholders replaced when the waiting page is rendered.
+const (
+	PlaceholderContainerName  = "{{CONTAINER_NAME}}"
+	PlaceholderRedirectURL    = "{{REDIRECT_URL}}"
+	PlaceholderWaitingMessage = "{{WAITING_MESSAGE}}"
+	PlaceholderMaxWaitSecs    = "{{MAX_WAIT_SECS}}"
+	PlaceholderLogoURL        = "{{LOGO_URL}}"
+	PlaceholderAccentColor    = "{{ACCENT_COLOR}}"
+)
+
+// DefaultAccentColor fills {{ACCENT_COLOR}} for entries without an accent color.
+const DefaultAccentColor = "#34bfa3"
+
+// missingTemplate is served when the template file cannot be read.
+const missingTemplate = "<!-- template not found -->"
+
+// ErrInvalidTemplate marks a template rejected by Validate.
+var ErrInvalidTemplate = errors.New("invalid waiting template")
+
+// placeholderPattern matches anything shaped like a placeholder, to catch typos.
+var placeholderPattern = regexp.MustCompile(`{{\s*[A-Za-z_]+\s*}}`)
+
+// requiredPlaceholders must appear in every template: without them the page cannot
+// tell which container it waits for nor where to go once it is ready.
+var requiredPlaceholders = []string{PlaceholderContainerName, PlaceholderRedirectURL}
+
+// Template holds the waiting page template shared by every server rendering it, so an update
+// through the API is picked up by the waiting server too.
+type Template struct {
+	mu      sync.RWMutex
+	path    string
+	content string
+}
+
+// NewTemplate creates a Template with the given content, saved to path on Update.
+func NewTemplate(path, content string) *Template {
+	return &Template{path: path, content: content}
+}
+
+// LoadTemplate reads the template at path. A missing or unreadable file is logged and replaced
+// by a placeholder page, so the server still starts.
+func LoadTemplate(path string) *Template {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		logger.WithComponent("waiting-template").Warnf("failed to load waiting template from %s: %v", path, err)
+		return NewTemplate(path, missingTemplate)
+	}
+	logger.WithComponent("waiting-template").Infof("loaded waiting template from %s", path)
+	return NewTemplate(path, string(content))
+}
+
+// Path returns the file the template is read from and saved to.
+func (t *Template) Path() string {
+	return t.path
+}
+
+// Content returns the current template text.
+func (t *Template) Content() string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.content
+}
+
+// Update validates content, writes it to the template path and makes it current. On any
+// error the previous template stays in place.
+func (t *Template) Update(content string) error {
+	if err := Validate(content); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err := writeFileAtomic(t.path, []byte(content)); err != nil {
+		return err
+	}
+	t.content = content
+	logger.WithComponent("waiting-template").Infof("waiting template updated at %s", t.path)
+	return nil
+}
+
+// Validate checks that content is a usable waiting template: valid UTF-8, with the required
+// placeholders and no unknown ones.
+func Validate(content string) error {
+	if strings.TrimSpace(content) == "" {
+		return fmt.Errorf("%w: empty template", ErrInvalidTemplate)
+	}
+	if !utf8.ValidString(content) {
+		return fmt.Errorf("%w: not valid UTF-8", ErrInvalidTemplate)
+	}
+	for _, placeholder := range requiredPlaceholders {
+		if !strings.Contains(content, placeholder) {
+			return fmt.Errorf("%w: missing placeholder %s", ErrInvalidTemplate, placeholder)
+		}
+	}
+	for _, found := range placeholderPattern.FindAllString(content, -1) {
+		switch found {
+		case PlaceholderContainerName, PlaceholderRedirectURL, PlaceholderWaitingMessage, PlaceholderMaxWaitSecs,
+			PlaceholderLogoURL, PlaceholderAccentColor:
+		default:
+			return fmt.Errorf("%w: unknown placeholder %s", ErrInvalidTemplate, found)
+		}
+	}
+	return nil
+}
+
+// writeFileAtomic replaces path with data through a temp file and a rename, so a concurrent
+// reader never sees a partially written template.
+func writeFileAtomic(path string, data []byte) error {
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	defer func() {
+		_ = tmpFile.Close()
+		_ = os.Remove(tmpFile.Name())
+	}()
+
+	if _, err := tmpFile.Write(data); err != nil {
+		return fmt.Errorf("write temp file: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("close temp file: %w", err)
+	}
+	if err := os.Rename(tmpFile.Name(), path); err != nil {
+		return fmt.Errorf("replace template file: %w", err)
+	}
+	return nil
+}
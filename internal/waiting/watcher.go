@@ -0,0 +1,89 @@
+package waiting
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/bassista/go_spin/internal/logger"
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadDebounce coalesces the bursts of events an editor or an atomic replace produces.
+const reloadDebounce = 200 * time.Millisecond
+
+// Watch reloads the template whenever its file changes on disk, until ctx is done. Like the data
+// file watcher it watches the parent directory, so atomic replaces (temp+rename) are observed.
+// A file failing Validate is logged and the previous template stays in place.
+func (t *Template) Watch(ctx context.Context) error {
+	dir, base := filepath.Dir(t.path), filepath.Base(t.path)
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("create watcher: %w", err)
+	}
+	if err := watcher.Add(dir); err != nil {
+		_ = watcher.Close()
+		return fmt.Errorf("watch dir: %w", err)
+	}
+	logger.WithComponent("waiting-template").Debugf("watching waiting template %s", t.path)
+
+	go func() {
+		defer func() { _ = watcher.Close() }()
+
+		var debounce *time.Timer
+		for {
+			select {
+			case <-ctx.Done():
+				if debounce != nil {
+					debounce.Stop()
+				}
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Base(event.Name) != base {
+					continue
+				}
+				if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Chmod|fsnotify.Rename) == 0 {
+					continue
+				}
+				if debounce != nil {
+					debounce.Stop()
+				}
+				debounce = time.AfterFunc(reloadDebounce, t.reload)
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				logger.WithComponent("waiting-template").Errorf("watcher error: %v", err)
+			}
+		}
+	}()
+	return nil
+}
+
+// reload reads the template file again and makes it current when it changed and is valid.
+func (t *Template) reload() {
+	data, err := os.ReadFile(t.path)
+	if err != nil {
+		logger.WithComponent("waiting-template").Warnf("failed to reload waiting template from %s: %v", t.path, err)
+		return
+	}
+	content := string(data)
+	if err := Validate(content); err != nil {
+		logger.WithComponent("waiting-template").Warnf("ignoring waiting template change at %s: %v", t.path, err)
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	// Update already made its own write current
+	if content == t.content {
+		return
+	}
+	t.content = content
+	logger.WithComponent("waiting-template").Infof("reloaded waiting template from %s", t.path)
+}
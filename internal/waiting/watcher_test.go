@@ -0,0 +1,61 @@
+package waiting
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testTemplate = "<p>{{CONTAINER_NAME}} {{REDIRECT_URL}}</p>"
+
+// waitForContent polls t until its content is want or the deadline expires.
+func waitForContent(t *testing.T, tmpl *Template, want string) bool {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if tmpl.Content() == want {
+			return true
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	return false
+}
+
+func TestTemplate_WatchReloadsValidChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "waiting.html")
+	if err := os.WriteFile(path, []byte(testTemplate), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tmpl := LoadTemplate(path)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := tmpl.Watch(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	updated := "<h1>{{CONTAINER_NAME}}</h1> {{REDIRECT_URL}}"
+	if err := os.WriteFile(path, []byte(updated), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if !waitForContent(t, tmpl, updated) {
+		t.Fatalf("expected the edited template to be reloaded, got %q", tmpl.Content())
+	}
+
+	// An invalid edit keeps the previous template
+	if err := os.WriteFile(path, []byte("<p>no placeholders</p>"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(reloadDebounce + 200*time.Millisecond)
+	if got := tmpl.Content(); got != updated {
+		t.Errorf("expected the invalid edit ignored, got %q", got)
+	}
+}
+
+func TestTemplate_WatchMissingDir(t *testing.T) {
+	tmpl := NewTemplate(filepath.Join(t.TempDir(), "missing", "waiting.html"), testTemplate)
+	if err := tmpl.Watch(context.Background()); err == nil {
+		t.Error("expected an error watching a missing directory")
+	}
+}
@@ -0,0 +1,96 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bassista/go_spin/internal/config"
+	"github.com/bassista/go_spin/internal/events"
+	"github.com/bassista/go_spin/internal/logger"
+)
+
+// Headers set on every delivery.
+const (
+	HeaderEvent     = "X-GoSpin-Event"
+	HeaderSignature = "X-GoSpin-Signature"
+)
+
+// signaturePrefix precedes the hex HMAC-SHA256 of the body in HeaderSignature, GitHub style.
+const signaturePrefix = "sha256="
+
+// Start subscribes every configured webhook to bus and delivers the matching events until ctx
+// is cancelled. Each webhook has its own subscription and goroutine, so a slow endpoint delays
+// (and, once its queue is full, drops) only its own deliveries, in publication order.
+func Start(ctx context.Context, bus *events.Bus, cfg config.WebhooksConfig) {
+	client := &http.Client{Timeout: cfg.Timeout}
+	for _, hook := range cfg.Hooks {
+		types := make([]events.Type, 0, len(hook.Events))
+		for _, t := range hook.Events {
+			types = append(types, events.Type(t))
+		}
+		ch, unsubscribe := bus.Subscribe(types...)
+		logger.WithComponent("webhook").Infof("webhook %s subscribed to %v", hook.URL, hook.Events)
+
+		go func(hook config.WebhookConfig) {
+			defer unsubscribe()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case ev, ok := <-ch:
+					if !ok {
+						return
+					}
+					if err := Deliver(ctx, client, hook, ev); err != nil {
+						logger.WithComponent("webhook").Warnf("webhook %s: %s event of %q not delivered: %v", hook.URL, ev.Type, ev.Subject, err)
+					}
+				}
+			}
+		}(hook)
+	}
+}
+
+// Deliver posts ev as JSON to the webhook URL, signed with its secret when one is set.
+// Any non-2xx answer is an error; failed deliveries are not retried.
+func Deliver(ctx context.Context, client *http.Client, hook config.WebhookConfig, ev events.Event) error {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return fmt.Errorf("encode event: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, string(ev.Type))
+	if hook.Secret != "" {
+		req.Header.Set(HeaderSignature, Sign(hook.Secret, body))
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	logger.WithComponent("webhook").Debugf("webhook %s: %s event of %q delivered in %v", hook.URL, ev.Type, ev.Subject, time.Since(start))
+	return nil
+}
+
+// Sign returns the HeaderSignature value of body: "sha256=" followed by the hex HMAC-SHA256
+// of body keyed with secret. Receivers recompute it to authenticate the delivery.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
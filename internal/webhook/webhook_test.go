@@ -0,0 +1,85 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bassista/go_spin/internal/config"
+	"github.com/bassista/go_spin/internal/events"
+)
+
+type delivery struct {
+	header http.Header
+	body   []byte
+}
+
+func newReceiver(t *testing.T, status int) (*httptest.Server, <-chan delivery) {
+	t.Helper()
+	received := make(chan delivery, 8)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- delivery{header: r.Header.Clone(), body: body}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, received
+}
+
+func TestStart_DeliversFilteredSignedEvents(t *testing.T) {
+	srv, received := newReceiver(t, http.StatusOK)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus := events.NewBus()
+	Start(ctx, bus, config.WebhooksConfig{
+		Timeout: time.Second,
+		Hooks:   []config.WebhookConfig{{URL: srv.URL, Secret: "s3cret", Events: []string{"container.started"}}},
+	})
+	bus.Publish(events.Event{Type: events.ContainerStopped, Subject: "c1"})
+	bus.Publish(events.Event{Type: events.ContainerStarted, Subject: "c1"})
+
+	select {
+	case d := <-received:
+		if got := d.header.Get(HeaderEvent); got != "container.started" {
+			t.Errorf("expected only the container.started event, got %q", got)
+		}
+		if got, want := d.header.Get(HeaderSignature), Sign("s3cret", d.body); got != want {
+			t.Errorf("expected signature %q, got %q", want, got)
+		}
+		var ev events.Event
+		if err := json.Unmarshal(d.body, &ev); err != nil || ev.Subject != "c1" {
+			t.Errorf("expected the event of c1 as body, got %s (%v)", d.body, err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected a webhook delivery")
+	}
+	select {
+	case d := <-received:
+		t.Errorf("expected no further delivery, got %s", d.header.Get(HeaderEvent))
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestDeliver_NonSuccessStatusIsAnError(t *testing.T) {
+	srv, _ := newReceiver(t, http.StatusInternalServerError)
+	hook := config.WebhookConfig{URL: srv.URL}
+	if err := Deliver(context.Background(), srv.Client(), hook, events.Event{Type: events.ConfigPersisted}); err == nil {
+		t.Error("expected an error for a 500 answer")
+	}
+}
+
+func TestDeliver_NoSecretNoSignature(t *testing.T) {
+	srv, received := newReceiver(t, http.StatusNoContent)
+	hook := config.WebhookConfig{URL: srv.URL}
+	if err := Deliver(context.Background(), srv.Client(), hook, events.Event{Type: events.CacheReplaced}); err != nil {
+		t.Fatalf("expected delivery to succeed, got %v", err)
+	}
+	if d := <-received; d.header.Get(HeaderSignature) != "" {
+		t.Errorf("expected no signature without a secret, got %q", d.header.Get(HeaderSignature))
+	}
+}
@@ -7,6 +7,8 @@ function app() {
             // Stats refresh interval (seconds)
             statsRefreshInterval: 120, // default 120 seconds
             statsRefreshTimer: null,
+            // Live state stream (/ws/state); while open the stats polling is paused
+            stateStream: null,
             // Container stats map (name -> {cpu, mem})
             containerStats: {},
             // Show CPU/MEM columns (responsive)
@@ -124,6 +126,7 @@ function app() {
             await this.loadContainerStats();
             this.startAutoRefresh();
             this.startStatsRefresh();
+            this.startStateStream();
         },
 
         // Setup swipe and mouse-drag to delete
@@ -241,6 +244,11 @@ function app() {
         startStatsRefresh() {
             if (this.statsRefreshTimer) {
                 clearInterval(this.statsRefreshTimer);
+                this.statsRefreshTimer = null;
+            }
+            // The live state stream already pushes the stats
+            if (this.stateStream && this.stateStream.readyState === EventSource.OPEN) {
+                return;
             }
             this.statsRefreshTimer = setInterval(() => {
                 this.loadContainerStats();
@@ -257,6 +265,32 @@ function app() {
             this.startStatsRefresh();
         },
 
+        // Subscribe to the live container state (Server-Sent Events on /ws/state). While the stream
+        // is open the stats polling is paused; it resumes whenever the stream is unavailable.
+        startStateStream() {
+            if (!window.EventSource) return;
+            const stream = new EventSource(`${this.apiBase}/ws/state`);
+            stream.addEventListener('state', (e) => {
+                const s = JSON.parse(e.data);
+                const c = this.containers.find(x => x.name === s.container);
+                if (c) c.running = s.running;
+                const stats = s.running ? { cpu: s.cpu_percent ?? 0, mem: s.memory_mb ?? 0 } : { cpu: 0, mem: 0 };
+                this.containerStats = { ...this.containerStats, [s.container]: stats };
+            });
+            stream.onopen = () => {
+                if (this.statsRefreshTimer) {
+                    clearInterval(this.statsRefreshTimer);
+                    this.statsRefreshTimer = null;
+                }
+            };
+            stream.onerror = () => {
+                if (!this.statsRefreshTimer) {
+                    this.startStatsRefresh();
+                }
+            };
+            this.stateStream = stream;
+        },
+
         // Load container stats from runtime/stats endpoint
         async loadContainerStats() {
             try {
@@ -524,18 +558,24 @@ function app() {
         
         async saveContainer() {
             try {
-                const payload = {
-                    name: this.containerForm.name,
+                // Edits patch only the fields of the form, so the others (allowedUsers, host,
+                // healthCheck, ...) and the runtime state are kept
+                const fields = {
                     friendly_name: this.containerForm.friendly_name,
                     url: this.containerForm.url,
-                    running: this.containerForm.running,
                     active: this.containerForm.active
                 };
-                const res = await fetch(`${this.apiBase}/container`, {
-                    method: 'POST',
-                    headers: { 'Content-Type': 'application/json' },
-                    body: JSON.stringify(payload)
-                });
+                const res = this.editingContainer
+                    ? await fetch(`${this.apiBase}/container/${encodeURIComponent(this.containerForm.name)}`, {
+                        method: 'PATCH',
+                        headers: { 'Content-Type': 'application/json' },
+                        body: JSON.stringify(fields)
+                    })
+                    : await fetch(`${this.apiBase}/container`, {
+                        method: 'POST',
+                        headers: { 'Content-Type': 'application/json' },
+                        body: JSON.stringify({ name: this.containerForm.name, ...fields })
+                    });
                 if (!res.ok) {
                     const err = await res.json();
                     throw new Error(err.error || 'Save failed');
@@ -631,8 +671,9 @@ function app() {
         openGroupModal(group = null) {
             if (group) {
                 this.editingGroup = true;
+                // Keep every stored field, so saving sends back those the form does not edit
                 this.groupForm = {
-                    name: group.name,
+                    ...group,
                     container: [...(group.container || [])],
                     active: group.active || false
                 };
@@ -658,11 +699,7 @@ function app() {
         
         async saveGroup() {
             try {
-                const payload = {
-                    name: this.groupForm.name,
-                    container: this.groupForm.container,
-                    active: this.groupForm.active
-                };
+                const payload = { ...this.groupForm };
                 
                 const res = await fetch(`${this.apiBase}/group`, {
                     method: 'POST',
@@ -754,7 +791,10 @@ function app() {
                         startTime: t.startTime,
                         stopTime: t.stopTime,
                         days: [...(t.days || [])],
-                        active: t.active || false
+                        active: t.active || false,
+                        // One-shot windows are kept as they are (no editor yet)
+                        startDate: t.startDate,
+                        stopDate: t.stopDate
                     }))
                 };
             } else {
@@ -829,7 +869,9 @@ function app() {
                     startTime: t.startTime,
                     stopTime: t.stopTime,
                     days: t.days,
-                    active: t.active
+                    active: t.active,
+                    startDate: t.startDate || undefined,
+                    stopDate: t.stopDate || undefined
                 }));
                 
                 const payload = {